@@ -7,11 +7,17 @@ type analyticsIndexProvider interface {
 	DropDataset(datasetName string, opts *DropAnalyticsDatasetOptions) error
 	GetAllDatasets(opts *GetAllAnalyticsDatasetsOptions) ([]AnalyticsDataset, error)
 	CreateIndex(datasetName, indexName string, fields map[string]string, opts *CreateAnalyticsIndexOptions) error
+	CreatePrimaryIndex(datasetName string, opts *CreateAnalyticsPrimaryIndexOptions) error
 	DropIndex(datasetName, indexName string, opts *DropAnalyticsIndexOptions) error
 	GetAllIndexes(opts *GetAllAnalyticsIndexesOptions) ([]AnalyticsIndex, error)
+	CreateView(viewName, query string, opts *CreateAnalyticsViewOptions) error
+	DropView(viewName string, opts *DropAnalyticsViewOptions) error
+	CreateSynonym(synonymName, targetName string, opts *CreateAnalyticsSynonymOptions) error
+	DropSynonym(synonymName string, opts *DropAnalyticsSynonymOptions) error
+	GetAllSynonyms(opts *GetAllAnalyticsSynonymsOptions) ([]AnalyticsSynonym, error)
 	ConnectLink(opts *ConnectAnalyticsLinkOptions) error
 	DisconnectLink(opts *DisconnectAnalyticsLinkOptions) error
-	GetPendingMutations(opts *GetPendingMutationsAnalyticsOptions) (map[string]map[string]int, error)
+	GetPendingMutations(opts *GetPendingMutationsAnalyticsOptions) (*AnalyticsPendingMutations, error)
 	CreateLink(link AnalyticsLink, opts *CreateAnalyticsLinkOptions) error
 	ReplaceLink(link AnalyticsLink, opts *ReplaceAnalyticsLinkOptions) error
 	DropLink(linkName, dataverseName string, opts *DropAnalyticsLinkOptions) error
@@ -1,3 +1,5 @@
+//go:build !gocb_noanalytics
+
 package gocb
 
 import (
@@ -8,12 +10,42 @@ import (
 	"github.com/google/uuid"
 	"io"
 	"net/url"
+	"sort"
 	"strings"
 )
 
+// quoteAnalyticsIdentifier delimits an identifier with backticks, escaping any backslashes or backticks within it.
+func quoteAnalyticsIdentifier(name string) string {
+	name = strings.ReplaceAll(name, "\\", "\\\\")
+	return "`" + strings.ReplaceAll(name, "`", "\\`") + "`"
+}
+
+// quoteAnalyticsFieldPath quotes each segment of a dot separated field path, such as "address.city".
+func quoteAnalyticsFieldPath(path string) string {
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		segments[i] = quoteAnalyticsIdentifier(segment)
+	}
+
+	return strings.Join(segments, ".")
+}
+
+// uncompoundName quotes a dataverse name, which may be a compound name of the form "database/scope".
 func (am *analyticsProviderCore) uncompoundName(dataverse string) string {
 	dvPieces := strings.Split(dataverse, "/")
-	return "`" + strings.Join(dvPieces, "`.`") + "`"
+	for i, piece := range dvPieces {
+		dvPieces[i] = quoteAnalyticsIdentifier(piece)
+	}
+	return strings.Join(dvPieces, ".")
+}
+
+// qualifiedName quotes a name, qualifying it with a dataverse if one is provided.
+func (am *analyticsProviderCore) qualifiedName(dataverse, name string) string {
+	if dataverse == "" {
+		return quoteAnalyticsIdentifier(name)
+	}
+
+	return am.uncompoundName(dataverse) + "." + quoteAnalyticsIdentifier(name)
 }
 
 func (am *analyticsProviderCore) CreateDataverse(dataverseName string, opts *CreateAnalyticsDataverseOptions) error {
@@ -104,14 +136,17 @@ func (am *analyticsProviderCore) CreateDataset(datasetName, bucketName string, o
 		where += opts.Condition
 	}
 
-	if opts.DataverseName == "" {
-		datasetName = fmt.Sprintf("`%s`", datasetName)
+	datasetName = am.qualifiedName(opts.DataverseName, datasetName)
+
+	var q string
+	if opts.ScopeName != "" {
+		q = fmt.Sprintf("CREATE ANALYTICS COLLECTION %s %s ON %s.%s.%s %s", ignoreStr, datasetName,
+			quoteAnalyticsIdentifier(bucketName), quoteAnalyticsIdentifier(opts.ScopeName),
+			quoteAnalyticsIdentifier(opts.CollectionName), where)
 	} else {
-		datasetName = fmt.Sprintf("%s.`%s`", am.uncompoundName(opts.DataverseName), datasetName)
+		q = fmt.Sprintf("CREATE DATASET %s %s ON %s %s", ignoreStr, datasetName, quoteAnalyticsIdentifier(bucketName), where)
 	}
 
-	q := fmt.Sprintf("CREATE DATASET %s %s ON `%s` %s", ignoreStr, datasetName, bucketName, where)
-
 	span := am.tracer.createSpan(opts.ParentSpan, "manager_analytics_create_dataset", "management")
 	defer span.End()
 
@@ -138,11 +173,7 @@ func (am *analyticsProviderCore) DropDataset(datasetName string, opts *DropAnaly
 		ignoreStr = "IF EXISTS"
 	}
 
-	if opts.DataverseName == "" {
-		datasetName = fmt.Sprintf("`%s`", datasetName)
-	} else {
-		datasetName = fmt.Sprintf("%s.`%s`", am.uncompoundName(opts.DataverseName), datasetName)
-	}
+	datasetName = am.qualifiedName(opts.DataverseName, datasetName)
 
 	q := fmt.Sprintf("DROP DATASET %s %s", datasetName, ignoreStr)
 
@@ -220,18 +251,22 @@ func (am *analyticsProviderCore) CreateIndex(datasetName, indexName string, fiel
 		ignoreStr = "IF NOT EXISTS"
 	}
 
-	var indexFields []string
-	for name, typ := range fields {
-		indexFields = append(indexFields, name+":"+typ)
+	// Sort the fields so that the statement is deterministic, the order of a map is not.
+	fieldNames := make([]string, 0, len(fields))
+	for name := range fields {
+		fieldNames = append(fieldNames, name)
 	}
+	sort.Strings(fieldNames)
 
-	if opts.DataverseName == "" {
-		datasetName = fmt.Sprintf("`%s`", datasetName)
-	} else {
-		datasetName = fmt.Sprintf("%s.`%s`", am.uncompoundName(opts.DataverseName), datasetName)
+	var indexFields []string
+	for _, name := range fieldNames {
+		indexFields = append(indexFields, quoteAnalyticsFieldPath(name)+":"+strings.ToLower(fields[name]))
 	}
 
-	q := fmt.Sprintf("CREATE INDEX `%s` %s ON %s (%s)", indexName, ignoreStr, datasetName, strings.Join(indexFields, ","))
+	datasetName = am.qualifiedName(opts.DataverseName, datasetName)
+
+	q := fmt.Sprintf("CREATE INDEX %s %s ON %s (%s)", quoteAnalyticsIdentifier(indexName), ignoreStr, datasetName,
+		strings.Join(indexFields, ","))
 
 	span := am.tracer.createSpan(opts.ParentSpan, "manager_analytics_create_index", "management")
 	defer span.End()
@@ -249,6 +284,42 @@ func (am *analyticsProviderCore) CreateIndex(datasetName, indexName string, fiel
 	return nil
 }
 
+func (am *analyticsProviderCore) CreatePrimaryIndex(datasetName string, opts *CreateAnalyticsPrimaryIndexOptions) error {
+	if opts == nil {
+		opts = &CreateAnalyticsPrimaryIndexOptions{}
+	}
+
+	var indexNameStr string
+	if opts.IndexName != "" {
+		indexNameStr = quoteAnalyticsIdentifier(opts.IndexName) + " "
+	}
+
+	var ignoreStr string
+	if opts.IgnoreIfExists {
+		ignoreStr = "IF NOT EXISTS "
+	}
+
+	datasetName = am.qualifiedName(opts.DataverseName, datasetName)
+
+	q := fmt.Sprintf("CREATE PRIMARY INDEX %s%sON %s", indexNameStr, ignoreStr, datasetName)
+
+	span := am.tracer.createSpan(opts.ParentSpan, "manager_analytics_create_primary_index", "management")
+	span.SetAttribute("db.statement", q)
+	defer span.End()
+
+	_, err := am.doAnalyticsQuery(q, &AnalyticsOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    span,
+		Context:       opts.Context,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (am *analyticsProviderCore) DropIndex(datasetName, indexName string, opts *DropAnalyticsIndexOptions) error {
 	if opts == nil {
 		opts = &DropAnalyticsIndexOptions{}
@@ -259,13 +330,9 @@ func (am *analyticsProviderCore) DropIndex(datasetName, indexName string, opts *
 		ignoreStr = "IF EXISTS"
 	}
 
-	if opts.DataverseName == "" {
-		datasetName = fmt.Sprintf("`%s`", datasetName)
-	} else {
-		datasetName = fmt.Sprintf("%s.`%s`", am.uncompoundName(opts.DataverseName), datasetName)
-	}
+	datasetName = am.qualifiedName(opts.DataverseName, datasetName)
 
-	q := fmt.Sprintf("DROP INDEX %s.%s %s", datasetName, indexName, ignoreStr)
+	q := fmt.Sprintf("DROP INDEX %s.%s %s", datasetName, quoteAnalyticsIdentifier(indexName), ignoreStr)
 
 	span := am.tracer.createSpan(opts.ParentSpan, "manager_analytics_drop_index", "management")
 	span.SetAttribute("db.statement", q)
@@ -320,6 +387,173 @@ func (am *analyticsProviderCore) GetAllIndexes(opts *GetAllAnalyticsIndexesOptio
 	return indexes, nil
 }
 
+func (am *analyticsProviderCore) CreateView(viewName, query string, opts *CreateAnalyticsViewOptions) error {
+	if opts == nil {
+		opts = &CreateAnalyticsViewOptions{}
+	}
+
+	var replaceStr string
+	if opts.ReplaceIfExists {
+		replaceStr = "OR REPLACE "
+	}
+
+	var ignoreStr string
+	if opts.IgnoreIfExists {
+		ignoreStr = "IF NOT EXISTS"
+	}
+
+	viewName = am.qualifiedName(opts.DataverseName, viewName)
+
+	q := fmt.Sprintf("CREATE %sANALYTICS VIEW %s %s AS %s", replaceStr, viewName, ignoreStr, query)
+
+	span := am.tracer.createSpan(opts.ParentSpan, "manager_analytics_create_view", "management")
+	span.SetAttribute("db.statement", q)
+	defer span.End()
+
+	_, err := am.doAnalyticsQuery(q, &AnalyticsOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    span,
+		Context:       opts.Context,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (am *analyticsProviderCore) DropView(viewName string, opts *DropAnalyticsViewOptions) error {
+	if opts == nil {
+		opts = &DropAnalyticsViewOptions{}
+	}
+
+	var ignoreStr string
+	if opts.IgnoreIfNotExists {
+		ignoreStr = "IF EXISTS"
+	}
+
+	viewName = am.qualifiedName(opts.DataverseName, viewName)
+
+	q := fmt.Sprintf("DROP ANALYTICS VIEW %s %s", viewName, ignoreStr)
+
+	span := am.tracer.createSpan(opts.ParentSpan, "manager_analytics_drop_view", "management")
+	span.SetAttribute("db.statement", q)
+	defer span.End()
+
+	_, err := am.doAnalyticsQuery(q, &AnalyticsOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    span,
+		Context:       opts.Context,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (am *analyticsProviderCore) CreateSynonym(synonymName, targetName string, opts *CreateAnalyticsSynonymOptions) error {
+	if opts == nil {
+		opts = &CreateAnalyticsSynonymOptions{}
+	}
+
+	var ignoreStr string
+	if opts.IgnoreIfExists {
+		ignoreStr = "IF NOT EXISTS"
+	}
+
+	synonymName = am.qualifiedName(opts.DataverseName, synonymName)
+	targetName = am.qualifiedName(opts.TargetDataverseName, targetName)
+
+	q := fmt.Sprintf("CREATE ANALYTICS SYNONYM %s %s FOR %s", synonymName, ignoreStr, targetName)
+
+	span := am.tracer.createSpan(opts.ParentSpan, "manager_analytics_create_synonym", "management")
+	span.SetAttribute("db.statement", q)
+	defer span.End()
+
+	_, err := am.doAnalyticsQuery(q, &AnalyticsOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    span,
+		Context:       opts.Context,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (am *analyticsProviderCore) DropSynonym(synonymName string, opts *DropAnalyticsSynonymOptions) error {
+	if opts == nil {
+		opts = &DropAnalyticsSynonymOptions{}
+	}
+
+	var ignoreStr string
+	if opts.IgnoreIfNotExists {
+		ignoreStr = "IF EXISTS"
+	}
+
+	synonymName = am.qualifiedName(opts.DataverseName, synonymName)
+
+	q := fmt.Sprintf("DROP ANALYTICS SYNONYM %s %s", synonymName, ignoreStr)
+
+	span := am.tracer.createSpan(opts.ParentSpan, "manager_analytics_drop_synonym", "management")
+	span.SetAttribute("db.statement", q)
+	defer span.End()
+
+	_, err := am.doAnalyticsQuery(q, &AnalyticsOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    span,
+		Context:       opts.Context,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (am *analyticsProviderCore) GetAllSynonyms(opts *GetAllAnalyticsSynonymsOptions) ([]AnalyticsSynonym, error) {
+	if opts == nil {
+		opts = &GetAllAnalyticsSynonymsOptions{}
+	}
+
+	q := "SELECT s.* FROM Metadata.`Synonym` s WHERE s.DataverseName <> \"Metadata\""
+	span := am.tracer.createSpan(opts.ParentSpan, "manager_analytics_get_all_synonyms", "management")
+	span.SetAttribute("db.statement", q)
+	defer span.End()
+
+	rows, err := am.doAnalyticsQuery(q, &AnalyticsOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    span,
+		Context:       opts.Context,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	synonyms := make([]AnalyticsSynonym, len(rows))
+	for rowIdx, row := range rows {
+		var synonymData jsonAnalyticsSynonym
+		err := json.Unmarshal(row, &synonymData)
+		if err != nil {
+			return nil, err
+		}
+
+		err = synonyms[rowIdx].fromData(synonymData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return synonyms, nil
+}
+
 func (am *analyticsProviderCore) ConnectLink(opts *ConnectAnalyticsLinkOptions) error {
 	if opts == nil {
 		opts = &ConnectAnalyticsLinkOptions{}
@@ -329,9 +563,7 @@ func (am *analyticsProviderCore) ConnectLink(opts *ConnectAnalyticsLinkOptions)
 	if linkName == "" {
 		linkName = "Local"
 	}
-	if opts.DataverseName != "" {
-		linkName = fmt.Sprintf("%s.`%s`", am.uncompoundName(opts.DataverseName), linkName)
-	}
+	linkName = am.qualifiedName(opts.DataverseName, linkName)
 
 	q := fmt.Sprintf("CONNECT LINK %s", linkName)
 	span := am.tracer.createSpan(opts.ParentSpan, "manager_analytics_connect_link", "management")
@@ -360,9 +592,7 @@ func (am *analyticsProviderCore) DisconnectLink(opts *DisconnectAnalyticsLinkOpt
 	if linkName == "" {
 		linkName = "Local"
 	}
-	if opts.DataverseName != "" {
-		linkName = fmt.Sprintf("%s.`%s`", am.uncompoundName(opts.DataverseName), linkName)
-	}
+	linkName = am.qualifiedName(opts.DataverseName, linkName)
 
 	q := fmt.Sprintf("DISCONNECT LINK %s", linkName)
 	span := am.tracer.createSpan(opts.ParentSpan, "manager_analytics_disconnect_link", "management")
@@ -381,7 +611,7 @@ func (am *analyticsProviderCore) DisconnectLink(opts *DisconnectAnalyticsLinkOpt
 	return nil
 }
 
-func (am *analyticsProviderCore) GetPendingMutations(opts *GetPendingMutationsAnalyticsOptions) (map[string]map[string]int, error) {
+func (am *analyticsProviderCore) GetPendingMutations(opts *GetPendingMutationsAnalyticsOptions) (*AnalyticsPendingMutations, error) {
 	if opts == nil {
 		opts = &GetPendingMutationsAnalyticsOptions{}
 	}
@@ -413,9 +643,9 @@ func (am *analyticsProviderCore) GetPendingMutations(opts *GetPendingMutationsAn
 		return nil, makeMgmtBadStatusError("failed to get pending mutations", &req, resp)
 	}
 
-	pending := make(map[string]map[string]int)
+	var pendingData map[string]json.RawMessage
 	jsonDec := json.NewDecoder(resp.Body)
-	err = jsonDec.Decode(&pending)
+	err = jsonDec.Decode(&pendingData)
 	if err != nil {
 		return nil, err
 	}
@@ -425,9 +655,96 @@ func (am *analyticsProviderCore) GetPendingMutations(opts *GetPendingMutationsAn
 		logDebugf("Failed to close socket (%s)", err)
 	}
 
+	pending := &AnalyticsPendingMutations{}
+	for key, value := range pendingData {
+		// Older servers respond with counts keyed by dataverse then dataset, newer servers may instead key counts by
+		// the qualified dataset name.
+		var count json.Number
+		if err := json.Unmarshal(value, &count); err == nil {
+			dataverseName, datasetName := splitAnalyticsQualifiedName(key)
+			mutations, err := pendingMutationsFromNumber(count)
+			if err != nil {
+				return nil, err
+			}
+
+			pending.Datasets = append(pending.Datasets, AnalyticsDatasetPendingMutations{
+				DataverseName: dataverseName,
+				DatasetName:   datasetName,
+				Mutations:     mutations,
+			})
+			continue
+		}
+
+		var datasets map[string]json.Number
+		if err := json.Unmarshal(value, &datasets); err != nil {
+			return nil, wrapError(err, "failed to parse pending mutations")
+		}
+
+		for datasetName, count := range datasets {
+			mutations, err := pendingMutationsFromNumber(count)
+			if err != nil {
+				return nil, err
+			}
+
+			pending.Datasets = append(pending.Datasets, AnalyticsDatasetPendingMutations{
+				DataverseName: key,
+				DatasetName:   datasetName,
+				Mutations:     mutations,
+			})
+		}
+	}
+
+	sort.Slice(pending.Datasets, func(i, j int) bool {
+		if pending.Datasets[i].DataverseName != pending.Datasets[j].DataverseName {
+			return pending.Datasets[i].DataverseName < pending.Datasets[j].DataverseName
+		}
+		return pending.Datasets[i].DatasetName < pending.Datasets[j].DatasetName
+	})
+
 	return pending, nil
 }
 
+// pendingMutationsFromNumber converts a pending mutation count, which some servers report as a float, into an int64.
+func pendingMutationsFromNumber(count json.Number) (int64, error) {
+	if mutations, err := count.Int64(); err == nil {
+		return mutations, nil
+	}
+
+	mutations, err := count.Float64()
+	if err != nil {
+		return 0, wrapError(err, "failed to parse pending mutations")
+	}
+	return int64(mutations), nil
+}
+
+// splitAnalyticsQualifiedName splits a qualified dataset name, such as `travel-sample`.`inventory`.`airline` or
+// Default.airline, into a dataverse name, using "/" to separate the parts of compound names, and a dataset name.
+func splitAnalyticsQualifiedName(name string) (string, string) {
+	var parts []string
+	var current strings.Builder
+	inQuotes := false
+	for i := 0; i < len(name); i++ {
+		switch c := name[i]; {
+		case c == '\\' && inQuotes && i+1 < len(name):
+			i++
+			current.WriteByte(name[i])
+		case c == '`':
+			inQuotes = !inQuotes
+		case c == '.' && !inQuotes:
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	parts = append(parts, current.String())
+
+	if len(parts) == 1 {
+		return "", parts[0]
+	}
+	return strings.Join(parts[:len(parts)-1], "/"), parts[len(parts)-1]
+}
+
 func (am *analyticsProviderCore) CreateLink(link AnalyticsLink, opts *CreateAnalyticsLinkOptions) error {
 	if opts == nil {
 		opts = &CreateAnalyticsLinkOptions{}
@@ -554,7 +871,7 @@ func (am *analyticsProviderCore) DropLink(linkName, dataverseName string, opts *
 	var payload []byte
 	var endpoint string
 	if strings.Contains(dataverseName, "/") {
-		endpoint = fmt.Sprintf("/analytics/link/%s/%s", url.PathEscape(dataverseName), linkName)
+		endpoint = fmt.Sprintf("/analytics/link/%s/%s", url.PathEscape(dataverseName), url.PathEscape(linkName))
 	} else {
 		endpoint = "/analytics/link"
 		values := url.Values{}
@@ -609,33 +926,30 @@ func (am *analyticsProviderCore) GetLinks(opts *GetAnalyticsLinksOptions) ([]Ana
 		timeout = am.analyticsTimeout
 	}
 
-	var querystring []string
+	querystring := url.Values{}
 	var endpoint string
 	if strings.Contains(opts.Dataverse, "/") {
 		endpoint = fmt.Sprintf("/analytics/link/%s", url.PathEscape(opts.Dataverse))
 
 		if opts.Name != "" {
-			endpoint = fmt.Sprintf("%s/%s", endpoint, opts.Name)
-		}
-		if opts.LinkType != "" {
-			querystring = append(querystring, fmt.Sprintf("type=%s", opts.LinkType))
+			endpoint = fmt.Sprintf("%s/%s", endpoint, url.PathEscape(opts.Name))
 		}
 	} else {
 		endpoint = "/analytics/link"
 
 		if opts.Dataverse != "" {
-			querystring = append(querystring, "dataverse="+opts.Dataverse)
+			querystring.Set("dataverse", opts.Dataverse)
 			if opts.Name != "" {
-				querystring = append(querystring, "name="+opts.Name)
+				querystring.Set("name", opts.Name)
 			}
 		}
-		if opts.LinkType != "" {
-			querystring = append(querystring, fmt.Sprintf("type=%s", opts.LinkType))
-		}
+	}
+	if opts.LinkType != "" {
+		querystring.Set("type", string(opts.LinkType))
 	}
 
 	if len(querystring) > 0 {
-		endpoint = endpoint + "?" + strings.Join(querystring, "&")
+		endpoint = endpoint + "?" + querystring.Encode()
 	}
 
 	span := am.tracer.createSpan(opts.ParentSpan, "manager_analytics_get_all_links", "management")
@@ -718,18 +1032,18 @@ func (am *analyticsProviderCore) endpointFromLink(link AnalyticsLink) string {
 	switch l := link.(type) {
 	case *CouchbaseRemoteAnalyticsLink:
 		if strings.Contains(l.Dataverse, "/") {
-			endpoint = fmt.Sprintf("/analytics/link/%s/%s", url.PathEscape(l.Dataverse), l.LinkName)
+			endpoint = fmt.Sprintf("/analytics/link/%s/%s", url.PathEscape(l.Dataverse), url.PathEscape(l.LinkName))
 		} else {
 			endpoint = "/analytics/link"
 		}
 	case *S3ExternalAnalyticsLink:
 		if strings.Contains(l.Dataverse, "/") {
-			endpoint = fmt.Sprintf("/analytics/link/%s/%s", url.PathEscape(l.Dataverse), l.LinkName)
+			endpoint = fmt.Sprintf("/analytics/link/%s/%s", url.PathEscape(l.Dataverse), url.PathEscape(l.LinkName))
 		} else {
 			endpoint = "/analytics/link"
 		}
 	case *AzureBlobExternalAnalyticsLink:
-		endpoint = fmt.Sprintf("/analytics/link/%s/%s", url.PathEscape(l.Dataverse), l.LinkName)
+		endpoint = fmt.Sprintf("/analytics/link/%s/%s", url.PathEscape(l.Dataverse), url.PathEscape(l.LinkName))
 	default:
 		endpoint = "/analytics/link"
 	}
@@ -829,17 +1143,3 @@ func (am *analyticsProviderCore) doMgmtRequest(ctx context.Context, req mgmtRequ
 
 	return resp, nil
 }
-
-type jsonAnalyticsDataset struct {
-	DatasetName   string `json:"DatasetName"`
-	DataverseName string `json:"DataverseName"`
-	LinkName      string `json:"LinkName"`
-	BucketName    string `json:"BucketName"`
-}
-
-type jsonAnalyticsIndex struct {
-	IndexName     string `json:"IndexName"`
-	DatasetName   string `json:"DatasetName"`
-	DataverseName string `json:"DataverseName"`
-	IsPrimary     bool   `json:"IsPrimary"`
-}
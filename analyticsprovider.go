@@ -10,3 +10,8 @@ type analyticsRowReader interface {
 	MetaData() ([]byte, error)
 	Close() error
 }
+
+type analyticsHandleProvider interface {
+	AnalyticsHandleStatus(handle string, opts *AnalyticsHandleStatusOptions) (*jsonAnalyticsHandleResponse, error)
+	AnalyticsHandleRows(handle string, opts *AnalyticsHandleRowsOptions) (analyticsRowReader, error)
+}
@@ -1,9 +1,12 @@
+//go:build !gocb_noanalytics
+
 package gocb
 
 import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/couchbase/gocbcore/v10"
@@ -23,32 +26,6 @@ type analyticsProviderCore struct {
 	tracer               *tracerWrapper
 }
 
-type jsonAnalyticsMetrics struct {
-	ElapsedTime      string `json:"elapsedTime"`
-	ExecutionTime    string `json:"executionTime"`
-	ResultCount      uint64 `json:"resultCount"`
-	ResultSize       uint64 `json:"resultSize"`
-	MutationCount    uint64 `json:"mutationCount,omitempty"`
-	SortCount        uint64 `json:"sortCount,omitempty"`
-	ErrorCount       uint64 `json:"errorCount,omitempty"`
-	WarningCount     uint64 `json:"warningCount,omitempty"`
-	ProcessedObjects uint64 `json:"processedObjects,omitempty"`
-}
-
-type jsonAnalyticsWarning struct {
-	Code    uint32 `json:"code"`
-	Message string `json:"msg"`
-}
-
-type jsonAnalyticsResponse struct {
-	RequestID       string                 `json:"requestID"`
-	ClientContextID string                 `json:"clientContextID"`
-	Status          string                 `json:"status"`
-	Warnings        []jsonAnalyticsWarning `json:"warnings"`
-	Metrics         jsonAnalyticsMetrics   `json:"metrics"`
-	Signature       interface{}            `json:"signature"`
-}
-
 func (ap *analyticsProviderCore) AnalyticsQuery(statement string, scope *Scope, opts *AnalyticsOptions) (*AnalyticsResult, error) {
 	if opts == nil {
 		opts = &AnalyticsOptions{}
@@ -112,8 +89,224 @@ func (ap *analyticsProviderCore) AnalyticsQuery(statement string, scope *Scope,
 		User:          opts.Internal.User,
 	})
 	if err != nil {
+		setSpanOutcome(span, opts.Context, err)
 		return nil, maybeEnhanceAnalyticsError(err)
 	}
 
-	return newAnalyticsResult(res), nil
+	clientContextID := maybeGetAnalyticsOption(queryOpts, "client_context_id")
+	result := newAnalyticsResult(newAnalyticsProviderCoreRowReader(opts.Context, res, func() {
+		ap.abandonAnalyticsQuery(clientContextID, opts.RetryStrategy)
+	}, opts.OnMetaData))
+	result.handleProvider = ap
+	result.serializer = opts.Serializer
+	return result, nil
+}
+
+// abandonAnalyticsQuery asks the server to stop executing a query whose results are no longer wanted.
+func (ap *analyticsProviderCore) abandonAnalyticsQuery(clientContextID string, retryStrategy RetryStrategy) {
+	if clientContextID == "" || ap.mgmtProvider == nil {
+		return
+	}
+
+	req := mgmtRequest{
+		Service:       ServiceTypeAnalytics,
+		Method:        "DELETE",
+		Path:          "/analytics/admin/active_requests",
+		Body:          []byte(url.Values{"client_context_id": []string{clientContextID}}.Encode()),
+		ContentType:   "application/x-www-form-urlencoded",
+		IsIdempotent:  true,
+		RetryStrategy: retryStrategy,
+		Timeout:       ap.analyticsTimeout,
+	}
+
+	resp, err := ap.mgmtProvider.executeMgmtRequest(context.Background(), req)
+	if err != nil {
+		logDebugf("Failed to cancel analytics query %s: %s", clientContextID, err)
+		return
+	}
+
+	// The server responds with 404 if the query completed before it could be cancelled.
+	if resp.StatusCode != 200 && resp.StatusCode != 404 {
+		logDebugf("Failed to cancel analytics query %s, server responded with status %d", clientContextID, resp.StatusCode)
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		logDebugf("Failed to close socket (%s)", err)
+	}
+}
+
+// analyticsProviderCoreRowReader ties the lifetime of the response stream to the context provided by the user,
+// closing the stream and abandoning the query on the server if the context is done whilst the stream is still open.
+type analyticsProviderCoreRowReader struct {
+	reader     analyticsRowReader
+	ctx        *resultsContext
+	onMetaData func(meta *AnalyticsMetaData)
+}
+
+func newAnalyticsProviderCoreRowReader(ctx context.Context, reader analyticsRowReader, abandon func(),
+	onMetaData func(meta *AnalyticsMetaData)) *analyticsProviderCoreRowReader {
+	return &analyticsProviderCoreRowReader{
+		reader: reader,
+		// Closing the reader shuts down the underlying HTTP stream but the server will continue to execute the query
+		// unless it is told to stop, this is only needed if the stream had not already completed.
+		ctx: newResultsContext(ctx, reader, func(err error) {
+			go abandon()
+		}),
+		onMetaData: onMetaData,
+	}
+}
+
+func (a *analyticsProviderCoreRowReader) NextRow() []byte {
+	row := a.ctx.NextRow()
+	if row == nil && a.onMetaData != nil {
+		a.deliverMetaData()
+	}
+
+	return row
+}
+
+// deliverMetaData passes the warnings and metrics to the user as soon as the stream has completed, these are sent
+// by the server after the results.
+func (a *analyticsProviderCoreRowReader) deliverMetaData() {
+	onMetaData := a.onMetaData
+	a.onMetaData = nil
+
+	if a.Err() != nil {
+		return
+	}
+
+	metaBytes, err := a.MetaData()
+	if err != nil {
+		logDebugf("Failed to read analytics metadata: %s", err)
+		return
+	}
+
+	meta, err := parseAnalyticsMetaData(metaBytes)
+	if err != nil {
+		logDebugf("Failed to parse analytics metadata: %s", err)
+		return
+	}
+
+	onMetaData(meta)
+}
+
+func (a *analyticsProviderCoreRowReader) Err() error {
+	if err := a.ctx.Err(); err != nil {
+		return err
+	}
+
+	var err error
+	a.ctx.Do(func() {
+		err = a.reader.Err()
+	})
+	return err
+}
+
+func (a *analyticsProviderCoreRowReader) MetaData() (meta []byte, err error) {
+	a.ctx.Do(func() {
+		meta, err = a.reader.MetaData()
+	})
+	return
+}
+
+func (a *analyticsProviderCoreRowReader) Close() error {
+	ctxErr, err := a.ctx.Close()
+	if ctxErr != nil {
+		return ctxErr
+	}
+
+	return err
+}
+
+// analyticsHandleRequest builds a request for an analytics handle. Handles are returned as absolute URLs, as the
+// status and results of a query are only available from the node which executed it.
+func (ap *analyticsProviderCore) analyticsHandleRequest(handle string, timeout time.Duration,
+	retryStrategy RetryStrategy, span RequestSpan) (mgmtRequest, error) {
+	handleURL, err := url.Parse(handle)
+	if err != nil {
+		return mgmtRequest{}, wrapError(err, "failed to parse analytics handle")
+	}
+
+	req := mgmtRequest{
+		Service:       ServiceTypeAnalytics,
+		Method:        "GET",
+		Path:          handleURL.RequestURI(),
+		IsIdempotent:  true,
+		RetryStrategy: retryStrategy,
+		Timeout:       timeout,
+		parentSpanCtx: span.Context(),
+	}
+	if handleURL.Host != "" {
+		req.Endpoint = handleURL.Scheme + "://" + handleURL.Host
+	}
+	if req.Timeout == 0 {
+		req.Timeout = ap.analyticsTimeout
+	}
+
+	return req, nil
+}
+
+func (ap *analyticsProviderCore) AnalyticsHandleStatus(handle string, opts *AnalyticsHandleStatusOptions) (*jsonAnalyticsHandleResponse, error) {
+	span := ap.tracer.createSpan(opts.ParentSpan, "analytics_handle_status", "analytics")
+	defer span.End()
+
+	req, err := ap.analyticsHandleRequest(handle, opts.Timeout, opts.RetryStrategy, span)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ap.mgmtProvider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, makeMgmtBadStatusError("failed to get analytics handle status", &req, resp)
+	}
+
+	var jsonResp jsonAnalyticsHandleResponse
+	err = json.NewDecoder(resp.Body).Decode(&jsonResp)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		logDebugf("Failed to close socket (%s)", err)
+	}
+
+	return &jsonResp, nil
+}
+
+func (ap *analyticsProviderCore) AnalyticsHandleRows(handle string,
+	opts *AnalyticsHandleRowsOptions) (rowsOut analyticsRowReader, errOut error) {
+	span := ap.tracer.createSpan(opts.ParentSpan, "analytics_handle_rows", "analytics")
+	// The span lives for the lifetime of the response stream, it is ended by the row reader.
+	defer func() {
+		if errOut != nil {
+			setSpanOutcome(span, opts.Context, errOut)
+			span.End()
+		}
+	}()
+
+	req, err := ap.analyticsHandleRequest(handle, opts.Timeout, opts.RetryStrategy, span)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ap.mgmtProvider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, makeMgmtBadStatusError("failed to get analytics handle results", &req, resp)
+	}
+
+	return &analyticsHandleRowReader{
+		body:    resp.Body,
+		decoder: json.NewDecoder(resp.Body),
+		span:    span,
+	}, nil
 }
@@ -2,6 +2,7 @@ package gocb
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 	"time"
 
@@ -18,6 +19,23 @@ const (
 	AnalyticsScanConsistencyRequestPlus
 )
 
+// AnalyticsMode indicates how the analytics service should execute a query and return its results.
+// UNCOMMITTED: This API may change in the future.
+type AnalyticsMode string
+
+const (
+	// AnalyticsModeImmediate indicates that results should be streamed back as the query executes, this is the default.
+	AnalyticsModeImmediate AnalyticsMode = "immediate"
+
+	// AnalyticsModeAsync indicates that the query should be executed in the background, the response returns as
+	// soon as the query is accepted and the results can be fetched later using AnalyticsResult.Handle.
+	AnalyticsModeAsync AnalyticsMode = "async"
+
+	// AnalyticsModeDeferred indicates that the response should be returned once the query has completed, without
+	// any results, which can then be fetched using AnalyticsResult.Handle.
+	AnalyticsModeDeferred AnalyticsMode = "deferred"
+)
+
 // AnalyticsOptions is the set of options available to an Analytics query.
 type AnalyticsOptions struct {
 	// ClientContextID provides a unique ID for this query which can be used matching up requests between connectionManager and
@@ -27,23 +45,41 @@ type AnalyticsOptions struct {
 	// Priority sets whether this query should be assigned as high priority by the analytics engine.
 	Priority             bool
 	PositionalParameters []interface{}
-	NamedParameters      map[string]interface{}
-	Readonly             bool
-	ScanConsistency      AnalyticsScanConsistency
+	// NamedParameters can be built from a struct using NamedParametersFromStruct.
+	NamedParameters map[string]interface{}
+	Readonly        bool
+	ScanConsistency AnalyticsScanConsistency
+
+	// Mode specifies how the query is executed, when async or deferred the results must be fetched using
+	// AnalyticsResult.Handle.
+	// UNCOMMITTED: This API may change in the future.
+	Mode AnalyticsMode
 
 	// Raw provides a way to provide extra parameters in the request body for the query.
 	Raw map[string]interface{}
 
+	// Serializer is used to serialize positional and named parameters, and to deserialize rows returned by
+	// AnalyticsResult.Row and AnalyticsResult.One. If not set then encoding/json is used.
+	// UNCOMMITTED: This API may change in the future.
+	Serializer JSONSerializer
+
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
 
 	ParentSpan RequestSpan
 
 	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
-	// also applies to global level timeouts.
+	// also applies to global level timeouts. Cancelling the Context whilst the results are being streamed closes the
+	// stream and asks the server to stop executing the query.
 	// UNCOMMITTED: This API may change in the future.
 	Context context.Context
 
+	// OnMetaData is called with the warnings and metrics of the query as soon as they have been streamed, which is
+	// once the last row has been read. The analytics service sends these after the results, so they cannot be
+	// delivered any sooner. It is not called if the stream fails.
+	// UNCOMMITTED: This API may change in the future.
+	OnMetaData func(meta *AnalyticsMetaData)
+
 	// Internal: This should never be used and is not supported.
 	Internal struct {
 		User string
@@ -74,7 +110,19 @@ func (opts *AnalyticsOptions) toMap() (map[string]interface{}, error) {
 	}
 
 	if opts.PositionalParameters != nil {
-		execOpts["args"] = opts.PositionalParameters
+		if opts.Serializer != nil {
+			args := make([]json.RawMessage, len(opts.PositionalParameters))
+			for i, value := range opts.PositionalParameters {
+				b, err := opts.Serializer.Serialize(value)
+				if err != nil {
+					return nil, err
+				}
+				args[i] = b
+			}
+			execOpts["args"] = args
+		} else {
+			execOpts["args"] = opts.PositionalParameters
+		}
 	}
 
 	if opts.NamedParameters != nil {
@@ -82,7 +130,15 @@ func (opts *AnalyticsOptions) toMap() (map[string]interface{}, error) {
 			if !strings.HasPrefix(key, "$") {
 				key = "$" + key
 			}
-			execOpts[key] = value
+			if opts.Serializer != nil {
+				b, err := opts.Serializer.Serialize(value)
+				if err != nil {
+					return nil, err
+				}
+				execOpts[key] = json.RawMessage(b)
+			} else {
+				execOpts[key] = value
+			}
 		}
 	}
 
@@ -90,6 +146,14 @@ func (opts *AnalyticsOptions) toMap() (map[string]interface{}, error) {
 		execOpts["readonly"] = true
 	}
 
+	switch opts.Mode {
+	case "", AnalyticsModeImmediate:
+	case AnalyticsModeAsync, AnalyticsModeDeferred:
+		execOpts["mode"] = string(opts.Mode)
+	default:
+		return nil, makeInvalidArgumentsError("unexpected mode option")
+	}
+
 	if opts.Raw != nil {
 		for k, v := range opts.Raw {
 			execOpts[k] = v
@@ -1,6 +1,7 @@
 package gocb
 
 import (
+	"sync"
 	"time"
 )
 
@@ -13,15 +14,27 @@ type Bucket struct {
 	transcoder           Transcoder
 	retryStrategyWrapper *coreRetryStrategyWrapper
 	compressor           *compressor
+	warningHandler       WarningHandler
 
-	useServerDurations bool
-	useMutationTokens  bool
+	useServerDurations        bool
+	useMutationTokens         bool
+	allowSystemScopeMutations bool
+	readOnlyMode              bool
+	timeSource                func() time.Time
 
 	keyspace keyspace
 
+	tracer   *tracerWrapper
+	openSpan RequestSpan
+
 	bootstrapError    error
 	connectionManager connectionManager
 	getTransactions   func() *Transactions
+
+	// scopes caches the Scope handles returned by Scope, keyed by name.
+	scopes sync.Map
+
+	replicaCheck bucketReplicaCheck
 }
 
 func newBucket(c *Cluster, bucketName string) *Bucket {
@@ -34,15 +47,22 @@ func newBucket(c *Cluster, bucketName string) *Bucket {
 
 		retryStrategyWrapper: c.retryStrategyWrapper,
 
-		compressor: c.compressor,
+		compressor:     c.compressor,
+		warningHandler: c.warningHandler,
 
 		useServerDurations: c.useServerDurations,
 		useMutationTokens:  c.useMutationTokens,
 
+		allowSystemScopeMutations: c.allowSystemScopeMutations,
+		readOnlyMode:              c.readOnlyMode,
+		timeSource:                c.internalConfig.TimeSource,
+
 		keyspace: keyspace{
 			bucketName: bucketName,
 		},
 
+		tracer: c.tracer,
+
 		connectionManager: c.connectionManager,
 		getTransactions:   c.Transactions,
 	}
@@ -253,13 +273,28 @@ func (b *Bucket) Name() string {
 }
 
 // Scope returns an instance of a Scope.
+//
+// The same Scope is returned for each call with the same name on this Bucket. Cluster.Bucket returns a new Bucket
+// on each call, so Scopes obtained through different Bucket instances should be compared using Scope.Equal.
 func (b *Bucket) Scope(scopeName string) *Scope {
-	return newScope(b, scopeName)
+	if scope, ok := b.scopes.Load(scopeName); ok {
+		return scope.(*Scope)
+	}
+
+	scope, _ := b.scopes.LoadOrStore(scopeName, newScope(b, scopeName))
+	return scope.(*Scope)
 }
 
 // DefaultScope returns an instance of the default scope.
 func (b *Bucket) DefaultScope() *Scope {
-	return b.Scope("_default")
+	return b.Scope(DefaultScopeName)
+}
+
+// SystemScope returns an instance of the system scope.
+// KV mutations to collections within this scope are refused unless ClusterOptions.AllowSystemScopeMutations is set.
+// UNCOMMITTED: This API may change in the future.
+func (b *Bucket) SystemScope() *Scope {
+	return b.Scope(SystemScopeName)
 }
 
 // Collection returns an instance of a collection from within the default scope.
@@ -269,7 +304,7 @@ func (b *Bucket) Collection(collectionName string) *Collection {
 
 // DefaultCollection returns an instance of the default collection.
 func (b *Bucket) DefaultCollection() *Collection {
-	return b.DefaultScope().Collection("_default")
+	return b.DefaultScope().Collection(DefaultCollectionName)
 }
 
 // ViewIndexes returns a ViewIndexManager instance for managing views.
@@ -283,6 +318,7 @@ func (b *Bucket) ViewIndexes() *ViewIndexManager {
 			keyspace: &b.keyspace,
 			service:  serviceValueManagement,
 		},
+		viewQuery: b.ViewQuery,
 	}
 }
 
@@ -297,12 +333,15 @@ func (b *Bucket) CollectionsV2() *CollectionManagerV2 {
 			keyspace: &b.keyspace,
 			service:  serviceValueManagement,
 		},
+		bucket: b,
 	}
 }
 
 // Collections provides functions for managing collections.
 // Deprecated: See CollectionsV2.
 func (b *Bucket) Collections() *CollectionManager {
+	warnDeprecated(b.warningHandler, "Bucket.Collections", "Collections is deprecated, use CollectionsV2 instead")
+
 	// TODO: return error for unsupported collections
 	return &CollectionManager{
 		managerV2: b.CollectionsV2(),
@@ -322,6 +361,10 @@ func (b *Bucket) WaitUntilReady(timeout time.Duration, opts *WaitUntilReadyOptio
 			opts = &WaitUntilReadyOptions{}
 		}
 
+		span := waitUntilReadySpan(b.tracer, b.openSpan, opts)
+		span.SetAttribute(spanAttribDBNameKey, b.Name())
+		defer span.End()
+
 		if b.bootstrapError != nil {
 			return b.bootstrapError
 		}
@@ -27,6 +27,10 @@ type CollectionSpec struct {
 type ScopeSpec struct {
 	Name        string
 	Collections []CollectionSpec
+
+	// Limits are the quotas applied to the scope, or nil if the scope is not limited.
+	// UNCOMMITTED: This API may change in the future.
+	Limits *ScopeLimits
 }
 
 // CollectionManager provides methods for performing collections management.
@@ -123,12 +127,29 @@ type CreateScopeOptions struct {
 	RetryStrategy RetryStrategy
 	ParentSpan    RequestSpan
 
+	// Limits are the quotas applied to the scope, these are only enforced once rate limiting has been enabled on the
+	// cluster.
+	// UNCOMMITTED: This API may change in the future.
+	Limits *ScopeLimits
+
 	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
 	// also applies to global level timeouts.
 	// UNCOMMITTED: This API may change in the future.
 	Context context.Context
 }
 
+// UpdateScopeLimitsOptions is the set of options available to the UpdateScopeLimits operation.
+// UNCOMMITTED: This API may change in the future.
+type UpdateScopeLimitsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
 // CreateScope creates a new scope on the bucket.
 // Will be deprecated in favor of CollectionManagerV2.CreateScope in the next minor release.
 func (cm *CollectionManager) CreateScope(scopeName string, opts *CreateScopeOptions) error {
@@ -152,3 +173,23 @@ type DropScopeOptions struct {
 func (cm *CollectionManager) DropScope(scopeName string, opts *DropScopeOptions) error {
 	return cm.managerV2.DropScope(scopeName, opts)
 }
+
+// AwaitCollectionExistsOptions is the set of options available to the AwaitCollectionExists operation.
+// UNCOMMITTED: This API may change in the future.
+type AwaitCollectionExistsOptions struct {
+	// PollInterval is the interval between checks for the collection, by default the interval backs off.
+	PollInterval  time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a timeout will cause the shorter of the two to cause cancellation.
+	Context context.Context
+}
+
+// AwaitCollectionExists waits until a collection is present in the collections manifest of the bucket, such as after
+// it has been created using a different client or node, and a KV operation against the collection succeeds.
+// UNCOMMITTED: This API may change in the future.
+func (cm *CollectionManager) AwaitCollectionExists(scopeName, collectionName string, timeout time.Duration,
+	opts *AwaitCollectionExistsOptions) error {
+	return cm.managerV2.AwaitCollectionExists(scopeName, collectionName, timeout, opts)
+}
@@ -0,0 +1,101 @@
+package gocb
+
+// ScopeLimits are the quotas applied to a scope, a nil service limit means that the scope is not limited on that
+// service.
+// UNCOMMITTED: This API may change in the future.
+type ScopeLimits struct {
+	KeyValue       *KeyValueScopeLimits
+	Index          *IndexScopeLimits
+	Search         *SearchScopeLimits
+	ClusterManager *ClusterManagerScopeLimits
+}
+
+// KeyValueScopeLimits are the quotas applied to a scope on the data service.
+// UNCOMMITTED: This API may change in the future.
+type KeyValueScopeLimits struct {
+	// DataSizeBytes is the maximum amount of data that can be stored in the scope.
+	DataSizeBytes uint64
+}
+
+// IndexScopeLimits are the quotas applied to a scope on the index service.
+// UNCOMMITTED: This API may change in the future.
+type IndexScopeLimits struct {
+	NumIndexes uint32
+}
+
+// SearchScopeLimits are the quotas applied to a scope on the search service.
+// UNCOMMITTED: This API may change in the future.
+type SearchScopeLimits struct {
+	NumIndexes uint32
+}
+
+// ClusterManagerScopeLimits are the quotas applied to a scope by the cluster manager.
+// UNCOMMITTED: This API may change in the future.
+type ClusterManagerScopeLimits struct {
+	NumCollections uint32
+}
+
+type jsonKeyValueScopeLimits struct {
+	DataSize uint64 `json:"data_size"`
+}
+
+type jsonIndexScopeLimits struct {
+	NumIndexes uint32 `json:"num_indexes"`
+}
+
+type jsonSearchScopeLimits struct {
+	NumIndexes uint32 `json:"num_fts_indexes"`
+}
+
+type jsonClusterManagerScopeLimits struct {
+	NumCollections uint32 `json:"num_collections"`
+}
+
+type jsonScopeLimits struct {
+	KeyValue       *jsonKeyValueScopeLimits       `json:"kv,omitempty"`
+	Index          *jsonIndexScopeLimits          `json:"index,omitempty"`
+	Search         *jsonSearchScopeLimits         `json:"fts,omitempty"`
+	ClusterManager *jsonClusterManagerScopeLimits `json:"clusterManager,omitempty"`
+}
+
+func (sl *ScopeLimits) toData() jsonScopeLimits {
+	var data jsonScopeLimits
+	if sl.KeyValue != nil {
+		data.KeyValue = &jsonKeyValueScopeLimits{DataSize: sl.KeyValue.DataSizeBytes}
+	}
+	if sl.Index != nil {
+		data.Index = &jsonIndexScopeLimits{NumIndexes: sl.Index.NumIndexes}
+	}
+	if sl.Search != nil {
+		data.Search = &jsonSearchScopeLimits{NumIndexes: sl.Search.NumIndexes}
+	}
+	if sl.ClusterManager != nil {
+		data.ClusterManager = &jsonClusterManagerScopeLimits{NumCollections: sl.ClusterManager.NumCollections}
+	}
+
+	return data
+}
+
+func (sl *ScopeLimits) fromData(data jsonScopeLimits) {
+	if data.KeyValue != nil {
+		sl.KeyValue = &KeyValueScopeLimits{DataSizeBytes: data.KeyValue.DataSize}
+	}
+	if data.Index != nil {
+		sl.Index = &IndexScopeLimits{NumIndexes: data.Index.NumIndexes}
+	}
+	if data.Search != nil {
+		sl.Search = &SearchScopeLimits{NumIndexes: data.Search.NumIndexes}
+	}
+	if data.ClusterManager != nil {
+		sl.ClusterManager = &ClusterManagerScopeLimits{NumCollections: data.ClusterManager.NumCollections}
+	}
+}
+
+// jsonScopesLimits holds the limits of each scope in the manifest returned by the cluster, which are not part of the
+// manifest parsed by gocbcore.
+type jsonScopesLimits struct {
+	Scopes []struct {
+		Name   string           `json:"name"`
+		Limits *jsonScopeLimits `json:"limits,omitempty"`
+	} `json:"scopes"`
+}
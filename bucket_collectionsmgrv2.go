@@ -1,10 +1,17 @@
 package gocb
 
-import "time"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
 
 // CollectionManagerV2 provides methods for performing collections management.
 type CollectionManagerV2 struct {
 	controller *providerController[collectionsManagementProvider]
+
+	bucket *Bucket
 }
 
 // GetAllScopes gets all scopes from the bucket.
@@ -31,17 +38,21 @@ type CreateCollectionSettings struct {
 func (cm *CollectionManagerV2) CreateCollection(scopeName string, collectionName string, settings *CreateCollectionSettings, opts *CreateCollectionOptions) error {
 	return autoOpControlErrorOnly(cm.controller, "manager_collections_create_collection", func(provider collectionsManagementProvider) error {
 		if scopeName == "" {
-			return makeInvalidArgumentsError("collection name cannot be empty")
+			return makeInvalidArgumentsError("scope name cannot be empty")
 		}
 
 		if collectionName == "" {
-			return makeInvalidArgumentsError("scope name cannot be empty")
+			return makeInvalidArgumentsError("collection name cannot be empty")
 		}
 
 		if settings == nil {
 			settings = &CreateCollectionSettings{}
 		}
 
+		if settings.MaxExpiry < -time.Second {
+			return makeInvalidArgumentsError("max expiry cannot be less than -1 second")
+		}
+
 		if opts == nil {
 			opts = &CreateCollectionOptions{}
 		}
@@ -63,11 +74,15 @@ type UpdateCollectionSettings struct {
 func (cm *CollectionManagerV2) UpdateCollection(scopeName string, collectionName string, settings UpdateCollectionSettings, opts *UpdateCollectionOptions) error {
 	return autoOpControlErrorOnly(cm.controller, "manager_collections_update_collection", func(provider collectionsManagementProvider) error {
 		if scopeName == "" {
-			return makeInvalidArgumentsError("collection name cannot be empty")
+			return makeInvalidArgumentsError("scope name cannot be empty")
 		}
 
 		if collectionName == "" {
-			return makeInvalidArgumentsError("scope name cannot be empty")
+			return makeInvalidArgumentsError("collection name cannot be empty")
+		}
+
+		if settings.MaxExpiry < -time.Second {
+			return makeInvalidArgumentsError("max expiry cannot be less than -1 second")
 		}
 
 		if opts == nil {
@@ -82,11 +97,11 @@ func (cm *CollectionManagerV2) UpdateCollection(scopeName string, collectionName
 func (cm *CollectionManagerV2) DropCollection(scopeName string, collectionName string, opts *DropCollectionOptions) error {
 	return autoOpControlErrorOnly(cm.controller, "manager_collections_drop_collection", func(provider collectionsManagementProvider) error {
 		if scopeName == "" {
-			return makeInvalidArgumentsError("collection name cannot be empty")
+			return makeInvalidArgumentsError("scope name cannot be empty")
 		}
 
 		if collectionName == "" {
-			return makeInvalidArgumentsError("scope name cannot be empty")
+			return makeInvalidArgumentsError("collection name cannot be empty")
 		}
 
 		if opts == nil {
@@ -112,6 +127,24 @@ func (cm *CollectionManagerV2) CreateScope(scopeName string, opts *CreateScopeOp
 	})
 }
 
+// UpdateScopeLimits replaces the quotas applied to an existing scope, a nil service limit removes the limit on that
+// service. As with CreateScopeOptions.Limits, these are only enforced once rate limiting has been enabled on the
+// cluster.
+// UNCOMMITTED: This API may change in the future.
+func (cm *CollectionManagerV2) UpdateScopeLimits(scopeName string, limits ScopeLimits, opts *UpdateScopeLimitsOptions) error {
+	return autoOpControlErrorOnly(cm.controller, "manager_collections_update_scope_limits", func(provider collectionsManagementProvider) error {
+		if scopeName == "" {
+			return makeInvalidArgumentsError("scope name cannot be empty")
+		}
+
+		if opts == nil {
+			opts = &UpdateScopeLimitsOptions{}
+		}
+
+		return provider.UpdateScopeLimits(scopeName, limits, opts)
+	})
+}
+
 // DropScope removes a scope.
 func (cm *CollectionManagerV2) DropScope(scopeName string, opts *DropScopeOptions) error {
 	return autoOpControlErrorOnly(cm.controller, "manager_collections_drop_scope", func(provider collectionsManagementProvider) error {
@@ -126,3 +159,121 @@ func (cm *CollectionManagerV2) DropScope(scopeName string, opts *DropScopeOption
 		return provider.DropScope(scopeName, opts)
 	})
 }
+
+// awaitCollectionExistsProbeTimeout bounds each of the KV operations used by AwaitCollectionExists to check whether
+// the data service knows of the collection, as operations against unknown collections are retried until they time out.
+const awaitCollectionExistsProbeTimeout = 500 * time.Millisecond
+
+// awaitCollectionExistsProbeCandidates is the number of keys that AwaitCollectionExists chooses its probes from, one
+// for each data node, which is enough for every node to own at least one of them.
+const awaitCollectionExistsProbeCandidates = 1024
+
+// AwaitCollectionExists waits until a collection is present in the collections manifest of the bucket, such as after
+// it has been created using a different client or node, and a KV operation against the collection succeeds on every
+// data node. A timeout of 0 uses the management timeout of the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (cm *CollectionManagerV2) AwaitCollectionExists(scopeName, collectionName string, timeout time.Duration,
+	opts *AwaitCollectionExistsOptions) error {
+	if scopeName == "" {
+		return makeInvalidArgumentsError("scope name cannot be empty")
+	}
+	if collectionName == "" {
+		return makeInvalidArgumentsError("collection name cannot be empty")
+	}
+	if opts == nil {
+		opts = &AwaitCollectionExistsOptions{}
+	}
+	if timeout == 0 {
+		timeout = cm.bucket.timeoutsConfig.ManagementTimeout
+	}
+
+	return PollUntil(opts.Context, opts.PollInterval, func(ctx context.Context) (bool, error) {
+		scopes, err := cm.GetAllScopes(&GetAllScopesOptions{
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       ctx,
+		})
+		if err != nil {
+			return false, err
+		}
+
+		for _, scope := range scopes {
+			if scope.Name != scopeName {
+				continue
+			}
+
+			for _, collection := range scope.Collections {
+				if collection.Name == collectionName {
+					// The manifest is updated before every data node knows of the collection.
+					return cm.collectionExistsOnKV(ctx, scopeName, collectionName, opts)
+				}
+			}
+		}
+
+		return false, nil
+	}, &PollUntilOptions{
+		Timeout:    timeout,
+		ParentSpan: opts.ParentSpan,
+	})
+}
+
+// collectionExistsOnKV returns whether every data node knows of a collection, by probing a key owned by each node.
+func (cm *CollectionManagerV2) collectionExistsOnKV(ctx context.Context, scopeName, collectionName string,
+	opts *AwaitCollectionExistsOptions) (bool, error) {
+	collection := cm.bucket.Scope(scopeName).Collection(collectionName)
+
+	keys, err := awaitCollectionExistsProbeKeys(ctx, collection)
+	if errors.Is(err, ErrTimeout) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, key := range keys {
+		_, err := collection.Exists(key, &ExistsOptions{
+			Timeout:       awaitCollectionExistsProbeTimeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       ctx,
+		})
+		if err == nil {
+			continue
+		}
+		// A probe against a node which does not yet know of the collection is retried until it times out.
+		if errors.Is(err, ErrCollectionNotFound) || errors.Is(err, ErrScopeNotFound) || errors.Is(err, ErrTimeout) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// awaitCollectionExistsProbeKeys returns a key owned by each of the data nodes, or a single key where keys cannot be
+// grouped by node.
+func awaitCollectionExistsProbeKeys(ctx context.Context, collection *Collection) ([]string, error) {
+	candidates := make([]string, awaitCollectionExistsProbeCandidates)
+	for i := range candidates {
+		candidates[i] = fmt.Sprintf("gocb-await-collection-exists-%d", i)
+	}
+
+	res, err := collection.GroupKeysByNode(candidates, &GroupKeysByNodeOptions{
+		Timeout: awaitCollectionExistsProbeTimeout,
+		Context: ctx,
+	})
+	if errors.Is(err, ErrFeatureNotAvailable) || errors.Is(err, ErrInvalidArgument) {
+		return candidates[:1], nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, group := range res.Groups() {
+		keys = append(keys, group.Keys[0])
+	}
+
+	return keys, nil
+}
@@ -1,5 +1,16 @@
 package gocb
 
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/stretchr/testify/mock"
+)
+
 func (suite *IntegrationTestSuite) TestCollectionManagerCrudV2() {
 	suite.runCollectionManagerCrudTest(true)
 }
@@ -55,3 +66,191 @@ func (suite *IntegrationTestSuite) TestUpdateCollectionWithMaxExpiryAsNoExpiryV2
 func (suite *UnitTestSuite) TestGetAllScopesMgmtRequestFailsV2() {
 	suite.runGetAllScopesMgmtRequestFailsTest(true)
 }
+
+func (suite *UnitTestSuite) TestUpdateCollectionV2() {
+	provider := new(mockMgmtProvider)
+	provider.On("executeMgmtRequest", nil, mock.AnythingOfType("gocb.mgmtRequest")).
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(mgmtRequest)
+
+			suite.Assert().Equal("PATCH", req.Method)
+			suite.Assert().Equal("/pools/default/buckets/test/scopes/my%20scope/collections/col", req.Path)
+
+			form, err := url.ParseQuery(string(req.Body))
+			suite.Require().Nil(err, err)
+			suite.Assert().Equal(url.Values{
+				"maxTTL":  []string{"-1"},
+				"history": []string{"true"},
+			}, form)
+		}).
+		Return(&mgmtResponse{StatusCode: 200, Body: io.NopCloser(bytes.NewReader(nil))}, nil)
+
+	verifier := new(mockKvCapabilityVerifier)
+	verifier.On("BucketCapabilityStatus", gocbcore.BucketCapabilityNonDedupedHistory).
+		Return(gocbcore.CapabilityStatusSupported)
+
+	mgr := CollectionManagerV2{
+		controller: &providerController[collectionsManagementProvider]{
+			get: func() (collectionsManagementProvider, error) {
+				return &collectionsManagementProviderCore{
+					mgmtProvider:    provider,
+					featureVerifier: verifier,
+					bucketName:      "test",
+					tracer:          newTracerWrapper(&NoopTracer{}),
+				}, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+
+	err := mgr.UpdateCollection("my scope", "col", UpdateCollectionSettings{
+		MaxExpiry: -1 * time.Second,
+		History:   &CollectionHistorySettings{Enabled: true},
+	}, nil)
+	suite.Require().Nil(err, err)
+
+	err = mgr.UpdateCollection("my scope", "col", UpdateCollectionSettings{MaxExpiry: -2 * time.Second}, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	err = mgr.UpdateCollection("", "col", UpdateCollectionSettings{}, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+	suite.Assert().Contains(err.Error(), "scope name cannot be empty")
+}
+
+func (suite *UnitTestSuite) TestScopeLimitsV2() {
+	manifest := `{"uid":"2","scopes":[` +
+		`{"name":"_default","uid":"0","collections":[{"name":"_default","uid":"0"}]},` +
+		`{"name":"limited","uid":"8","limits":{"kv":{"data_size":1024},"clusterManager":{"num_collections":2}},` +
+		`"collections":[{"name":"col","uid":"9"}]}]}`
+
+	provider := new(mockMgmtProvider)
+	provider.On("executeMgmtRequest", nil, mock.AnythingOfType("gocb.mgmtRequest")).
+		Return(func(ctx context.Context, req mgmtRequest) *mgmtResponse {
+			if req.Method == "GET" {
+				return &mgmtResponse{StatusCode: 200, Body: io.NopCloser(bytes.NewReader([]byte(manifest)))}
+			}
+
+			suite.Assert().Equal("PATCH", req.Method)
+			suite.Assert().Equal("/pools/default/buckets/test/scopes/limited", req.Path)
+
+			form, err := url.ParseQuery(string(req.Body))
+			suite.Require().Nil(err, err)
+			suite.Assert().JSONEq(`{"index":{"num_indexes":5}}`, form.Get("limits"))
+
+			return &mgmtResponse{StatusCode: 200, Body: io.NopCloser(bytes.NewReader(nil))}
+		}, nil)
+
+	mgr := CollectionManagerV2{
+		controller: &providerController[collectionsManagementProvider]{
+			get: func() (collectionsManagementProvider, error) {
+				return &collectionsManagementProviderCore{
+					mgmtProvider: provider,
+					bucketName:   "test",
+					tracer:       newTracerWrapper(&NoopTracer{}),
+				}, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+
+	scopes, err := mgr.GetAllScopes(nil)
+	suite.Require().Nil(err, err)
+	suite.Require().Len(scopes, 2)
+	suite.Assert().Nil(scopes[0].Limits)
+	suite.Assert().Equal(&ScopeLimits{
+		KeyValue:       &KeyValueScopeLimits{DataSizeBytes: 1024},
+		ClusterManager: &ClusterManagerScopeLimits{NumCollections: 2},
+	}, scopes[1].Limits)
+
+	err = mgr.UpdateScopeLimits("limited", ScopeLimits{Index: &IndexScopeLimits{NumIndexes: 5}}, nil)
+	suite.Require().Nil(err, err)
+
+	err = mgr.UpdateScopeLimits("", ScopeLimits{}, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestAwaitCollectionExistsV2() {
+	manifestResponse := func(manifest string) *mgmtResponse {
+		return &mgmtResponse{StatusCode: 200, Body: io.NopCloser(bytes.NewReader([]byte(manifest)))}
+	}
+
+	provider := new(mockMgmtProvider)
+	provider.On("executeMgmtRequest", mock.Anything, mock.AnythingOfType("gocb.mgmtRequest")).
+		Return(manifestResponse(`{"uid":"1","scopes":[{"name":"_default","uid":"0","collections":[]}]}`), nil).Once()
+	for i := 0; i < 3; i++ {
+		provider.On("executeMgmtRequest", mock.Anything, mock.AnythingOfType("gocb.mgmtRequest")).
+			Return(manifestResponse(`{"uid":"2","scopes":[{"name":"_default","uid":"0","collections":[]},
+			{"name":"inventory","uid":"8","collections":[{"name":"airline","uid":"9"}]}]}`), nil).Once()
+	}
+
+	// Each of the two data nodes is probed with a key that it owns.
+	snapshot := newMockConfigSnapshot(8, 2)
+	snapshot.keyVbuckets = map[string]uint16{"gocb-await-collection-exists-5": 6}
+
+	// The first node does not know of the collection the first time that it is in the manifest, and the second node
+	// does not know of it the next time.
+	probes := map[string][]error{
+		"gocb-await-collection-exists-0": {
+			&gocbcore.TimeoutError{
+				InnerError:   gocbcore.ErrUnambiguousTimeout,
+				RetryReasons: []gocbcore.RetryReason{gocbcore.KVCollectionOutdatedRetryReason},
+			},
+			gocbcore.ErrDocumentNotFound,
+			gocbcore.ErrDocumentNotFound,
+		},
+		"gocb-await-collection-exists-5": {
+			&gocbcore.TimeoutError{InnerError: gocbcore.ErrUnambiguousTimeout},
+			gocbcore.ErrDocumentNotFound,
+		},
+	}
+
+	pendingOp := new(mockPendingOp)
+	coreProvider := new(mockKvProviderCoreProvider)
+	coreProvider.
+		On("GetMeta", mock.AnythingOfType("gocbcore.GetMetaOptions"), mock.AnythingOfType("gocbcore.GetMetaCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.GetMetaOptions)
+			suite.Assert().Equal("inventory", opts.ScopeName)
+			suite.Assert().Equal("airline", opts.CollectionName)
+
+			results := probes[string(opts.Key)]
+			suite.Require().NotEmpty(results, string(opts.Key))
+			probes[string(opts.Key)] = results[1:]
+
+			cb := args.Get(1).(gocbcore.GetMetaCallback)
+			cb(nil, results[0])
+		}).
+		Return(pendingOp, nil)
+
+	cli := new(mockConnectionManager)
+	cli.On("getKvProvider", "test").
+		Return(suite.kvProviderCore(coreProvider, &mockConfigSnapshotProvider{snapshot: snapshot}), nil)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	mgr := CollectionManagerV2{
+		controller: &providerController[collectionsManagementProvider]{
+			get: func() (collectionsManagementProvider, error) {
+				return &collectionsManagementProviderCore{
+					mgmtProvider: provider,
+					bucketName:   "test",
+					tracer:       newTracerWrapper(&NoopTracer{}),
+				}, nil
+			},
+			opController: mockOpController{},
+		},
+		bucket: suite.bucket("test", suite.defaultTimeoutConfig(), cli),
+	}
+
+	err := mgr.AwaitCollectionExists("inventory", "airline", 5*time.Second, &AwaitCollectionExistsOptions{
+		PollInterval: time.Millisecond,
+	})
+	suite.Require().Nil(err, err)
+	provider.AssertExpectations(suite.T())
+	suite.Assert().Empty(probes["gocb-await-collection-exists-0"])
+	suite.Assert().Empty(probes["gocb-await-collection-exists-5"])
+
+	err = mgr.AwaitCollectionExists("inventory", "", time.Second, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
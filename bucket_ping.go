@@ -14,7 +14,15 @@ type EndpointPingReport struct {
 	State     PingState
 	Error     string
 	Namespace string
-	Latency   time.Duration
+	// Latency is the latency of the most recent ping sent to the endpoint.
+	Latency time.Duration
+
+	// MinLatency, MaxLatency and MeanLatency summarise the latencies of the successful pings sent to the endpoint, see
+	// PingOptions.Samples. These are zero if no ping to the endpoint succeeded.
+	// UNCOMMITTED: This API may change in the future.
+	MinLatency  time.Duration
+	MaxLatency  time.Duration
+	MeanLatency time.Duration
 }
 
 // PingResult encapsulates the details from a executed ping operation.
@@ -22,7 +30,8 @@ type PingResult struct {
 	ID       string
 	Services map[ServiceType][]EndpointPingReport
 
-	sdk string
+	sdk       string
+	configRev int64
 }
 
 type jsonEndpointPingReport struct {
@@ -33,22 +42,28 @@ type jsonEndpointPingReport struct {
 	Error     string `json:"error,omitempty"`
 	Namespace string `json:"namespace,omitempty"`
 	LatencyUs uint64 `json:"latency_us"`
+
+	MinLatencyUs  uint64 `json:"min_latency_us"`
+	MaxLatencyUs  uint64 `json:"max_latency_us"`
+	MeanLatencyUs uint64 `json:"mean_latency_us"`
 }
 
 type jsonPingReport struct {
-	Version  uint16                              `json:"version"`
-	SDK      string                              `json:"sdk,omitempty"`
-	ID       string                              `json:"id,omitempty"`
-	Services map[string][]jsonEndpointPingReport `json:"services,omitempty"`
+	Version   uint16                              `json:"version"`
+	SDK       string                              `json:"sdk,omitempty"`
+	ID        string                              `json:"id,omitempty"`
+	ConfigRev int64                               `json:"config_rev,omitempty"`
+	Services  map[string][]jsonEndpointPingReport `json:"services,omitempty"`
 }
 
 // MarshalJSON generates a JSON representation of this ping report.
 func (report *PingResult) MarshalJSON() ([]byte, error) {
 	jsonReport := jsonPingReport{
-		Version:  2,
-		SDK:      report.sdk,
-		ID:       report.ID,
-		Services: make(map[string][]jsonEndpointPingReport),
+		Version:   2,
+		SDK:       report.sdk,
+		ID:        report.ID,
+		ConfigRev: report.configRev,
+		Services:  make(map[string][]jsonEndpointPingReport),
 	}
 
 	for serviceType, serviceInfo := range report.Services {
@@ -65,7 +80,11 @@ func (report *PingResult) MarshalJSON() ([]byte, error) {
 				State:     pingStateToString(service.State),
 				Error:     service.Error,
 				Namespace: service.Namespace,
-				LatencyUs: uint64(service.Latency / time.Nanosecond),
+				LatencyUs: uint64(service.Latency / time.Microsecond),
+
+				MinLatencyUs:  uint64(service.MinLatency / time.Microsecond),
+				MaxLatencyUs:  uint64(service.MaxLatency / time.Microsecond),
+				MeanLatencyUs: uint64(service.MeanLatency / time.Microsecond),
 			})
 		}
 	}
@@ -73,6 +92,62 @@ func (report *PingResult) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&jsonReport)
 }
 
+// PingHealthThresholds are the thresholds which a ping report is evaluated against by CheckHealth.
+// UNCOMMITTED: This API may change in the future.
+type PingHealthThresholds struct {
+	// MaxMeanLatency is the maximum mean latency allowed for an endpoint of each service. Endpoints of services
+	// which do not have a threshold only need to respond successfully. A service which has a threshold but is
+	// missing from the report fails.
+	MaxMeanLatency map[ServiceType]time.Duration
+
+	// MaxFailedEndpoints is the number of endpoints of a service which can fail before the service fails.
+	MaxFailedEndpoints int
+}
+
+// PingServiceHealth is the outcome of evaluating the endpoints of a service against PingHealthThresholds.
+// UNCOMMITTED: This API may change in the future.
+type PingServiceHealth struct {
+	Passed bool
+	// FailedEndpoints are the endpoints which did not respond successfully or exceeded the latency threshold.
+	FailedEndpoints []EndpointPingReport
+}
+
+// CheckHealth evaluates the report against the thresholds, returning whether each service in the report, or with a
+// latency threshold, passed. A service with no endpoints fails.
+// UNCOMMITTED: This API may change in the future.
+func (report *PingResult) CheckHealth(thresholds PingHealthThresholds) map[ServiceType]PingServiceHealth {
+	health := make(map[ServiceType]PingServiceHealth, len(report.Services))
+	for serviceType := range thresholds.MaxMeanLatency {
+		if _, ok := report.Services[serviceType]; !ok {
+			health[serviceType] = PingServiceHealth{Passed: false}
+		}
+	}
+	for serviceType, endpoints := range report.Services {
+		var failed []EndpointPingReport
+		for _, endpoint := range endpoints {
+			if thresholds.endpointFailed(serviceType, endpoint) {
+				failed = append(failed, endpoint)
+			}
+		}
+
+		health[serviceType] = PingServiceHealth{
+			Passed:          len(endpoints) > 0 && len(failed) <= thresholds.MaxFailedEndpoints,
+			FailedEndpoints: failed,
+		}
+	}
+
+	return health
+}
+
+func (thresholds PingHealthThresholds) endpointFailed(serviceType ServiceType, endpoint EndpointPingReport) bool {
+	if endpoint.State != PingStateOk {
+		return true
+	}
+
+	maxLatency, ok := thresholds.MaxMeanLatency[serviceType]
+	return ok && endpoint.MeanLatency > maxLatency
+}
+
 // PingOptions are the options available to the Ping operation.
 type PingOptions struct {
 	ServiceTypes []ServiceType
@@ -80,6 +155,12 @@ type PingOptions struct {
 	Timeout      time.Duration
 	ParentSpan   RequestSpan
 
+	// Samples is the number of times that each endpoint is pinged, the latencies of which are summarised in the
+	// report. Defaults to 1. Each round of pings is only sent once the previous round has completed, and the
+	// Timeout applies to each round, so the worst case duration of Ping is Samples multiplied by the Timeout.
+	// UNCOMMITTED: This API may change in the future.
+	Samples int
+
 	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
 	// also applies to global level timeouts.
 	// UNCOMMITTED: This API may change in the future.
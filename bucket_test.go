@@ -1,8 +1,11 @@
 package gocb
 
 import (
+	"context"
 	"errors"
 	"time"
+
+	"github.com/stretchr/testify/mock"
 )
 
 func (suite *IntegrationTestSuite) TestBucketWaitUntilReady() {
@@ -121,3 +124,53 @@ func (suite *IntegrationTestSuite) TestBucketOpsAfterClusterClose() {
 		suite.Require().ErrorIs(err, ErrShutdown)
 	})
 }
+
+type testWaitUntilReadyProvider struct{}
+
+func (p *testWaitUntilReadyProvider) WaitUntilReady(ctx context.Context, deadline time.Time, opts *WaitUntilReadyOptions) error {
+	return nil
+}
+
+func (suite *UnitTestSuite) TestBootstrapSpans() {
+	cli := new(mockConnectionManager)
+	cli.On("openBucket", "mock").Return(nil)
+	cli.On("getWaitUntilReadyProvider", mock.AnythingOfType("string")).Return(&testWaitUntilReadyProvider{}, nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	tracer := newTestTracer()
+	cluster := suite.newCluster(cli)
+	cluster.tracer = newTracerWrapper(tracer)
+	cluster.connectSpan = createBootstrapSpan(cluster.tracer, nil, spanNameClusterConnect)
+	cluster.connectSpan.End()
+
+	err := cluster.WaitUntilReady(time.Second, &WaitUntilReadyOptions{
+		ServiceTypes: []ServiceType{ServiceTypeQuery},
+	})
+	suite.Require().Nil(err, err)
+
+	b := cluster.Bucket("mock")
+	err = b.WaitUntilReady(time.Second, nil)
+	suite.Require().Nil(err, err)
+
+	spans := tracer.GetSpans()
+	suite.Require().Contains(spans, nil)
+	suite.Require().Len(spans[nil], 1)
+
+	connectSpan := spans[nil][0]
+	suite.Assert().Equal(spanNameClusterConnect, connectSpan.Name)
+	suite.Require().Contains(connectSpan.Spans, spanNameWaitUntilReady)
+	suite.Require().Len(connectSpan.Spans[spanNameWaitUntilReady], 1)
+	suite.Assert().Equal("query", connectSpan.Spans[spanNameWaitUntilReady][0].Tags[spanAttribServiceKey])
+	suite.Assert().True(connectSpan.Spans[spanNameWaitUntilReady][0].Finished)
+
+	suite.Require().Contains(connectSpan.Spans, spanNameBucketOpen)
+	suite.Require().Len(connectSpan.Spans[spanNameBucketOpen], 1)
+	openSpan := connectSpan.Spans[spanNameBucketOpen][0]
+	suite.Assert().Equal("mock", openSpan.Tags[spanAttribDBNameKey])
+	suite.Assert().True(openSpan.Finished)
+
+	suite.Require().Contains(openSpan.Spans, spanNameWaitUntilReady)
+	suite.Require().Len(openSpan.Spans[spanNameWaitUntilReady], 1)
+	suite.Assert().Equal("mock", openSpan.Spans[spanNameWaitUntilReady][0].Tags[spanAttribDBNameKey])
+}
@@ -2,6 +2,7 @@ package gocb
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
@@ -82,6 +83,8 @@ func (dd *DesignDocument) toData() (jsonDesignDocument, string, error) {
 // ViewIndexManager provides methods for performing View management.
 type ViewIndexManager struct {
 	controller *providerController[viewIndexProvider]
+
+	viewQuery func(designDoc string, viewName string, opts *ViewOptions) (*ViewResult, error)
 }
 
 // GetDesignDocumentOptions is the set of options available to the ViewIndexManager GetDesignDocument operation.
@@ -179,6 +182,14 @@ func (vm *ViewIndexManager) DropDesignDocument(name string, namespace DesignDocu
 
 // PublishDesignDocumentOptions is the set of options available to the ViewIndexManager PublishDesignDocument operation.
 type PublishDesignDocumentOptions struct {
+	// WaitForIndexBuild waits until the index of each view in the published design document has been built before
+	// returning.
+	// UNCOMMITTED: This API may change in the future.
+	WaitForIndexBuild bool
+	// IndexBuildTimeout bounds how long to wait for each index to be built, defaulting to the view timeout.
+	// UNCOMMITTED: This API may change in the future.
+	IndexBuildTimeout time.Duration
+
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
 	ParentSpan    RequestSpan
@@ -196,6 +207,137 @@ func (vm *ViewIndexManager) PublishDesignDocument(name string, opts *PublishDesi
 			opts = &PublishDesignDocumentOptions{}
 		}
 
-		return provider.PublishDesignDocument(name, opts)
+		if err := provider.PublishDesignDocument(name, opts); err != nil {
+			return err
+		}
+
+		if !opts.WaitForIndexBuild {
+			return nil
+		}
+
+		ddoc, err := provider.GetDesignDocument(name, DesignDocumentNamespaceProduction, &GetDesignDocumentOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+		if err != nil {
+			return err
+		}
+
+		return vm.waitForIndexBuild(opts.Context, *ddoc, DesignDocumentNamespaceProduction, opts.IndexBuildTimeout,
+			opts.RetryStrategy, opts.ParentSpan)
 	})
 }
+
+// SyncDesignDocumentOptions is the set of options available to the ViewIndexManager SyncDesignDocument operation.
+// UNCOMMITTED: This API may change in the future.
+type SyncDesignDocumentOptions struct {
+	// WaitForIndexBuild waits until the index of each view in the design document has been built before returning,
+	// if the design document was changed.
+	WaitForIndexBuild bool
+	// IndexBuildTimeout bounds how long to wait for each index to be built, defaulting to the view timeout.
+	IndexBuildTimeout time.Duration
+
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// SyncDesignDocument compares the design document with the existing design document of the same name in the
+// namespace and only upserts it if any views have been added, removed, or changed, so that repeated deployments do
+// not cause the views to be rebuilt. Returns whether the design document was changed.
+// UNCOMMITTED: This API may change in the future.
+func (vm *ViewIndexManager) SyncDesignDocument(ddoc DesignDocument, namespace DesignDocumentNamespace, opts *SyncDesignDocumentOptions) (bool, error) {
+	return autoOpControl(vm.controller, "manager_views_sync_design_document", func(provider viewIndexProvider) (bool, error) {
+		if opts == nil {
+			opts = &SyncDesignDocumentOptions{}
+		}
+
+		existing, err := provider.GetDesignDocument(ddoc.Name, namespace, &GetDesignDocumentOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+		if err != nil && !errors.Is(err, ErrDesignDocumentNotFound) {
+			return false, err
+		}
+
+		if err == nil && designDocumentViewsEqual(existing.Views, ddoc.Views) {
+			return false, nil
+		}
+
+		err = provider.UpsertDesignDocument(ddoc, namespace, &UpsertDesignDocumentOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+		if err != nil {
+			return false, err
+		}
+
+		if opts.WaitForIndexBuild {
+			err = vm.waitForIndexBuild(opts.Context, ddoc, namespace, opts.IndexBuildTimeout, opts.RetryStrategy,
+				opts.ParentSpan)
+			if err != nil {
+				return true, err
+			}
+		}
+
+		return true, nil
+	})
+}
+
+func designDocumentViewsEqual(existing, desired map[string]View) bool {
+	if len(existing) != len(desired) {
+		return false
+	}
+
+	for name, view := range desired {
+		existingView, ok := existing[name]
+		if !ok || existingView != view {
+			return false
+		}
+	}
+
+	return true
+}
+
+// waitForIndexBuild queries each view in the design document with request plus consistency, which causes the view
+// engine to bring the index up to date before responding.
+func (vm *ViewIndexManager) waitForIndexBuild(ctx context.Context, ddoc DesignDocument, namespace DesignDocumentNamespace,
+	timeout time.Duration, retryStrategy RetryStrategy, parentSpan RequestSpan) error {
+	if vm.viewQuery == nil {
+		return makeInvalidArgumentsError("waiting for index build is not supported by this view index manager")
+	}
+
+	for viewName := range ddoc.Views {
+		result, err := vm.viewQuery(ddoc.Name, viewName, &ViewOptions{
+			ScanConsistency: ViewScanConsistencyRequestPlus,
+			Limit:           1,
+			Namespace:       namespace,
+			Timeout:         timeout,
+			RetryStrategy:   retryStrategy,
+			ParentSpan:      parentSpan,
+			Context:         ctx,
+		})
+		if err != nil {
+			return err
+		}
+
+		// The rows are irrelevant, the response is only sent once the index has been built.
+		for result.Next() {
+		}
+		if err := result.Close(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
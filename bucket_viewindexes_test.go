@@ -2,6 +2,7 @@ package gocb
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -339,3 +340,63 @@ func (suite *UnitTestSuite) TestViewIndexManagerGetAllDesignDocumentsFiltersCorr
 	suite.Assert().Equal("test", ddocs[1].Name)
 	suite.Assert().Equal("test12", ddocs[2].Name)
 }
+
+func (suite *UnitTestSuite) TestViewIndexManagerSyncDesignDocument() {
+	existing := `{"views":{"by_name":{"map":"function (doc, meta) { emit(doc.name, null); }"}}}`
+	var upserts []string
+
+	mockProvider := new(mockMgmtProvider)
+	mockProvider.
+		On("executeMgmtRequest", nil, mock.AnythingOfType("mgmtRequest")).
+		Return(func(ctx context.Context, req mgmtRequest) *mgmtResponse {
+			suite.Assert().Equal("/_design/ddoc", req.Path)
+
+			if req.Method == "PUT" {
+				upserts = append(upserts, req.Path)
+				return &mgmtResponse{
+					StatusCode: 201,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`{"ok":true}`))),
+				}
+			}
+
+			return &mgmtResponse{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewReader([]byte(existing))),
+			}
+		}, nil)
+
+	var queried []string
+	viewMgr := suite.viewIndexManager(mockProvider)
+	viewMgr.viewQuery = func(designDoc string, viewName string, opts *ViewOptions) (*ViewResult, error) {
+		suite.Assert().Equal(ViewScanConsistencyRequestPlus, opts.ScanConsistency)
+		suite.Assert().Equal(DesignDocumentNamespaceProduction, opts.Namespace)
+		queried = append(queried, designDoc+"/"+viewName)
+
+		return newViewResult(&mockViewRowReader{Suite: suite}), nil
+	}
+
+	ddoc := DesignDocument{
+		Name: "ddoc",
+		Views: map[string]View{
+			"by_name": {Map: "function (doc, meta) { emit(doc.name, null); }"},
+		},
+	}
+
+	changed, err := viewMgr.SyncDesignDocument(ddoc, DesignDocumentNamespaceProduction, &SyncDesignDocumentOptions{
+		WaitForIndexBuild: true,
+	})
+	suite.Require().Nil(err, err)
+	suite.Assert().False(changed)
+	suite.Assert().Empty(upserts)
+	suite.Assert().Empty(queried)
+
+	ddoc.Views["by_age"] = View{Map: "function (doc, meta) { emit(doc.age, null); }", Reduce: "_count"}
+
+	changed, err = viewMgr.SyncDesignDocument(ddoc, DesignDocumentNamespaceProduction, &SyncDesignDocumentOptions{
+		WaitForIndexBuild: true,
+	})
+	suite.Require().Nil(err, err)
+	suite.Assert().True(changed)
+	suite.Assert().Len(upserts, 1)
+	suite.Assert().ElementsMatch([]string{"ddoc/by_name", "ddoc/by_age"}, queried)
+}
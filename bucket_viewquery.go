@@ -20,9 +20,10 @@ func (meta *ViewMetaData) fromData(data jsonViewResponse) error {
 
 // ViewRow represents a single row returned from a view query.
 type ViewRow struct {
-	ID         string
-	keyBytes   []byte
-	valueBytes []byte
+	ID            string
+	keyBytes      []byte
+	valueBytes    []byte
+	geometryBytes []byte
 }
 
 // Key returns the key associated with this view row.
@@ -35,6 +36,47 @@ func (vr *ViewRow) Value(valuePtr interface{}) error {
 	return json.Unmarshal(vr.valueBytes, valuePtr)
 }
 
+// Geometry decodes the GeoJSON geometry associated with this row. This is only available for rows returned from a
+// spatial view query.
+// UNCOMMITTED: This API may change in the future.
+func (vr *ViewRow) Geometry(valuePtr interface{}) error {
+	if len(vr.geometryBytes) == 0 {
+		return makeInvalidArgumentsError("row has no geometry, geometry is only available for spatial views")
+	}
+
+	return json.Unmarshal(vr.geometryBytes, valuePtr)
+}
+
+// TypedViewRow is a view row with its key and value decoded into the types K and V.
+// UNCOMMITTED: This API may change in the future.
+type TypedViewRow[K any, V any] struct {
+	ID    string
+	Key   K
+	Value V
+}
+
+// DecodeViewRow decodes the key and value of a view row into the types K and V. Rows from reduce views have no value
+// for ID, and rows from views which emit a null value leave Value as the zero value of V.
+// UNCOMMITTED: This API may change in the future.
+func DecodeViewRow[K any, V any](row ViewRow) (TypedViewRow[K, V], error) {
+	typed := TypedViewRow[K, V]{
+		ID: row.ID,
+	}
+
+	if len(row.keyBytes) > 0 {
+		if err := json.Unmarshal(row.keyBytes, &typed.Key); err != nil {
+			return typed, wrapError(err, "failed to decode view row key")
+		}
+	}
+	if len(row.valueBytes) > 0 {
+		if err := json.Unmarshal(row.valueBytes, &typed.Value); err != nil {
+			return typed, wrapError(err, "failed to decode view row value")
+		}
+	}
+
+	return typed, nil
+}
+
 // ViewResultRaw provides raw access to views data.
 // VOLATILE: This API is subject to change at any time.
 type ViewResultRaw struct {
@@ -121,6 +163,7 @@ func (r *ViewResult) Next() bool {
 	r.currentRow.ID = rowData.ID
 	r.currentRow.keyBytes = rowData.Key
 	r.currentRow.valueBytes = rowData.Value
+	r.currentRow.geometryBytes = rowData.Geometry
 
 	return true
 }
@@ -200,3 +243,16 @@ func (b *Bucket) ViewQuery(designDoc string, viewName string, opts *ViewOptions)
 		return provider.ViewQuery(designDoc, viewName, opts)
 	})
 }
+
+// SpatialViewQuery performs a spatial view query and returns a list of rows or an error. Spatial views are only
+// supported by Couchbase Server versions prior to 6.0.
+// UNCOMMITTED: This API may change in the future.
+func (b *Bucket) SpatialViewQuery(designDoc string, viewName string, opts *SpatialViewOptions) (*ViewResult, error) {
+	return autoOpControl(b.viewController(), "views", func(provider viewProvider) (*ViewResult, error) {
+		if opts == nil {
+			opts = &SpatialViewOptions{}
+		}
+
+		return provider.SpatialViewQuery(designDoc, viewName, opts)
+	})
+}
@@ -336,3 +336,66 @@ func (suite *UnitTestSuite) TestViewQueryRaw() {
 
 	suite.Assert().Equal(reader.Meta, metadata)
 }
+
+func (suite *UnitTestSuite) TestSpatialViewQuery() {
+	reader := &mockViewRowReader{
+		Dataset: []jsonViewRow{
+			{
+				ID:       "landmark_1",
+				Key:      json.RawMessage(`[[-0.12,-0.12],[51.5,51.5]]`),
+				Value:    json.RawMessage(`{"name":"Big Ben"}`),
+				Geometry: json.RawMessage(`{"type":"Point","coordinates":[-0.12,51.5]}`),
+			},
+		},
+		Meta:  []byte(`{"total_rows": 1}`),
+		Suite: suite,
+	}
+
+	bucket := suite.viewsBucket(reader, func(args mock.Arguments) {
+		opts := args.Get(1).(gocbcore.ViewQueryOptions)
+		suite.Assert().Equal("landmarks", opts.DesignDocumentName)
+		suite.Assert().Equal("by_location", opts.ViewName)
+		suite.Assert().Equal("_spatial", opts.ViewType)
+		suite.Assert().Equal("-1.5,50,0.5,52.25", opts.Options.Get("bbox"))
+		suite.Assert().Equal("5", opts.Options.Get("limit"))
+		suite.Assert().False(opts.Options.Has("reduce"))
+	})
+
+	result, err := bucket.SpatialViewQuery("landmarks", "by_location", &SpatialViewOptions{
+		Namespace:   DesignDocumentNamespaceProduction,
+		BoundingBox: &SpatialViewBoundingBox{MinLon: -1.5, MinLat: 50, MaxLon: 0.5, MaxLat: 52.25},
+		Limit:       5,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Require().True(result.Next())
+	row := result.Row()
+
+	var geometry struct {
+		Type        string    `json:"type"`
+		Coordinates []float64 `json:"coordinates"`
+	}
+	suite.Require().Nil(row.Geometry(&geometry))
+	suite.Assert().Equal("Point", geometry.Type)
+	suite.Assert().Equal([]float64{-0.12, 51.5}, geometry.Coordinates)
+
+	typed, err := DecodeViewRow[[][]float64, map[string]string](row)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(TypedViewRow[[][]float64, map[string]string]{
+		ID:    "landmark_1",
+		Key:   [][]float64{{-0.12, -0.12}, {51.5, 51.5}},
+		Value: map[string]string{"name": "Big Ben"},
+	}, typed)
+
+	_, err = DecodeViewRow[string, string](row)
+	suite.Assert().Error(err)
+
+	suite.Assert().False(result.Next())
+	suite.Require().Nil(result.Err())
+
+	_, err = bucket.SpatialViewQuery("landmarks", "by_location", &SpatialViewOptions{
+		BoundingBox: &SpatialViewBoundingBox{},
+		StartRange:  []interface{}{0, nil},
+	})
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
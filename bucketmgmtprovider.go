@@ -7,4 +7,7 @@ type bucketManagementProvider interface {
 	UpdateBucket(settings BucketSettings, opts *UpdateBucketOptions) error
 	DropBucket(name string, opts *DropBucketOptions) error
 	FlushBucket(name string, opts *FlushBucketOptions) error
+	GetItemCount(name string, opts *GetBucketOptions) (uint64, error)
+	CompactBucket(name string, opts *CompactBucketOptions) error
+	GetCompactionStatus(name string, opts *GetCompactionStatusOptions) (*BucketCompactionStatus, error)
 }
@@ -1,3 +1,5 @@
+//go:build !gocb_nocouchbase2
+
 package gocb
 
 import (
@@ -198,6 +200,18 @@ func (bm bucketManagementProviderPs) FlushBucket(name string, opts *FlushBucketO
 	return nil
 }
 
+func (bm bucketManagementProviderPs) GetItemCount(string, *GetBucketOptions) (uint64, error) {
+	return 0, ErrFeatureNotAvailable
+}
+
+func (bm bucketManagementProviderPs) CompactBucket(string, *CompactBucketOptions) error {
+	return ErrFeatureNotAvailable
+}
+
+func (bm bucketManagementProviderPs) GetCompactionStatus(string, *GetCompactionStatusOptions) (*BucketCompactionStatus, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
 func (bm bucketManagementProviderPs) psBucketToBucket(source *admin_bucket_v1.ListBucketsResponse_Bucket) (*BucketSettings, error) {
 	bucket := &BucketSettings{
 		Name:                 source.BucketName,
@@ -286,6 +300,9 @@ func (bm *bucketManagementProviderPs) settingsToCreateReq(settings CreateBucketS
 	if err != nil {
 		return nil, err
 	}
+	if settings.NumVBuckets > 0 {
+		return nil, wrapError(ErrFeatureNotAvailable, "setting the number of vbuckets is not supported by the couchbase2 protocol")
+	}
 
 	request.BucketName = settings.Name
 	request.NumReplicas = &settings.NumReplicas
@@ -388,6 +405,10 @@ func (bm *bucketManagementProviderPs) settingsToUpdateReq(settings BucketSetting
 	if err != nil {
 		return nil, err
 	}
+	if settings.NumVBuckets > 0 {
+		// The couchbase2 protocol does not report the number of vbuckets, so it cannot be checked against the bucket.
+		return nil, makeInvalidArgumentsError("the number of vbuckets cannot be changed once the bucket has been created")
+	}
 
 	request.BucketName = settings.Name
 	request.NumReplicas = &settings.NumReplicas
@@ -465,6 +486,9 @@ func (bm *bucketManagementProviderPs) validateSettings(settings BucketSettings)
 	if settings.BucketType == MemcachedBucketType && settings.NumReplicas > 0 {
 		return makeInvalidArgumentsError("replicas cannot be used with memcached buckets")
 	}
+	if settings.Rank > 0 {
+		return wrapError(ErrFeatureNotAvailable, "bucket rank is not supported by the couchbase2 protocol")
+	}
 
 	return nil
 }
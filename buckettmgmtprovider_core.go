@@ -39,6 +39,8 @@ type jsonBucketSettings struct {
 	HistoryRetentionCollectionDefault *bool  `json:"historyRetentionCollectionDefault"`
 	HistoryRetentionBytes             uint64 `json:"historyRetentionBytes"`
 	HistoryRetentionSeconds           int    `json:"historyRetentionSeconds"`
+	Rank                              uint32 `json:"rank"`
+	NumVBuckets                       uint16 `json:"numVBuckets"`
 }
 
 func (bs *BucketSettings) fromData(data jsonBucketSettings) error {
@@ -55,6 +57,8 @@ func (bs *BucketSettings) fromData(data jsonBucketSettings) error {
 	bs.StorageBackend = StorageBackend(data.StorageBackend)
 	bs.HistoryRetentionBytes = data.HistoryRetentionBytes
 	bs.HistoryRetentionDuration = time.Duration(data.HistoryRetentionSeconds) * time.Second
+	bs.Rank = data.Rank
+	bs.NumVBuckets = data.NumVBuckets
 
 	if data.HistoryRetentionCollectionDefault != nil {
 		if *data.HistoryRetentionCollectionDefault {
@@ -288,6 +292,16 @@ func (bm *bucketManagementProviderCore) CreateBucket(settings CreateBucketSettin
 		posts.Add("conflictResolutionType", string(settings.ConflictResolutionType))
 	}
 
+	if settings.NumVBuckets > 0 {
+		posts.Add("numVBuckets", fmt.Sprintf("%d", settings.NumVBuckets))
+	}
+
+	err = bm.checkSettingsSupported(opts.Context, span.Context(), &settings.BucketSettings, nil, opts.RetryStrategy,
+		opts.Timeout)
+	if err != nil {
+		return err
+	}
+
 	eSpan := bm.tracer.createSpan(span, "request_encoding", "")
 	d := posts.Encode()
 	eSpan.End()
@@ -335,6 +349,11 @@ func (bm *bucketManagementProviderCore) UpdateBucket(settings BucketSettings, op
 		return err
 	}
 
+	err = bm.checkSettingsSupported(opts.Context, span.Context(), &settings, &path, opts.RetryStrategy, opts.Timeout)
+	if err != nil {
+		return err
+	}
+
 	eSpan := bm.tracer.createSpan(span, "request_encoding", "")
 	d := posts.Encode()
 	eSpan.End()
@@ -544,6 +563,263 @@ func (bm *bucketManagementProviderCore) settingsToPostData(settings *BucketSetti
 	if settings.HistoryRetentionBytes > 0 {
 		posts.Add("historyRetentionBytes", fmt.Sprintf("%d", settings.HistoryRetentionBytes))
 	}
+	if settings.Rank > 0 {
+		posts.Add("rank", fmt.Sprintf("%d", settings.Rank))
+	}
 
 	return posts, nil
 }
+
+// clusterCompatVersion encodes a server version in the form used by the clusterCompatibility of nodes.
+func clusterCompatVersion(major, minor int) int {
+	return major*0x10000 + minor
+}
+
+// checkSettingsSupported returns ErrFeatureNotAvailable if the settings require a newer server version than the
+// cluster is running. The cluster version is only fetched when settings which require a newer version are used.
+// When updating, ErrInvalidArgument is returned if NumVBuckets is set and differs from that of the existing bucket.
+// updatePath is the path of the bucket being updated, or nil when the bucket is being created.
+func (bm *bucketManagementProviderCore) checkSettingsSupported(ctx context.Context, tracectx RequestSpanContext,
+	settings *BucketSettings, updatePath *string, strategy RetryStrategy, timeout time.Duration) error {
+	type requirement struct {
+		version int
+		feature string
+	}
+
+	var requirements []requirement
+	if settings.HistoryRetentionCollectionDefault != HistoryRetentionCollectionDefaultUnset ||
+		settings.HistoryRetentionBytes > 0 || settings.HistoryRetentionDuration > 0 {
+		requirements = append(requirements, requirement{clusterCompatVersion(7, 2), "history retention"})
+	}
+	if settings.Rank > 0 {
+		requirements = append(requirements, requirement{clusterCompatVersion(7, 6), "bucket rank"})
+	}
+	if updatePath == nil && settings.NumVBuckets > 0 {
+		requirements = append(requirements, requirement{clusterCompatVersion(8, 0), "setting the number of vbuckets"})
+	}
+	checkMigration := updatePath != nil && settings.StorageBackend != ""
+	checkNumVBuckets := updatePath != nil && settings.NumVBuckets > 0
+
+	var compat int
+	if len(requirements) > 0 || checkMigration {
+		var err error
+		compat, err = bm.clusterCompatibility(ctx, tracectx, strategy, timeout)
+		if err != nil {
+			return err
+		}
+
+		for _, req := range requirements {
+			if compat < req.version {
+				return wrapError(ErrFeatureNotAvailable, req.feature+" is not supported by this server version")
+			}
+		}
+	}
+
+	// Older servers accept the storage backend on update as long as it is unchanged, so it is only a migration if the
+	// bucket currently uses a different storage backend.
+	checkMigration = checkMigration && compat < clusterCompatVersion(7, 6)
+	if !checkMigration && !checkNumVBuckets {
+		return nil
+	}
+
+	existing, err := bm.get(ctx, tracectx, *updatePath, strategy, timeout)
+	if err != nil {
+		return err
+	}
+
+	// The number of vbuckets is fixed when the bucket is created, so rather than silently ignoring a different value
+	// it is rejected.
+	if checkNumVBuckets && existing.NumVBuckets != settings.NumVBuckets {
+		return makeInvalidArgumentsError("the number of vbuckets cannot be changed once the bucket has been created")
+	}
+	if checkMigration && existing.StorageBackend != "" && existing.StorageBackend != settings.StorageBackend {
+		return wrapError(ErrFeatureNotAvailable, "storage backend migration is not supported by this server version")
+	}
+
+	return nil
+}
+
+// clusterCompatibility returns the lowest clusterCompatibility of the nodes in the cluster, which is the version of
+// the features that the cluster supports.
+func (bm *bucketManagementProviderCore) clusterCompatibility(ctx context.Context, tracectx RequestSpanContext,
+	strategy RetryStrategy, timeout time.Duration) (int, error) {
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Path:          "/pools/default",
+		Method:        "GET",
+		IsIdempotent:  true,
+		RetryStrategy: strategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       timeout,
+		parentSpanCtx: tracectx,
+	}
+
+	resp, err := bm.mgmtProvider.executeMgmtRequest(ctx, req)
+	if err != nil {
+		return 0, makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return 0, makeMgmtBadStatusError("failed to get cluster compatibility", &req, resp)
+	}
+
+	var clusterData jsonClusterCfg
+	jsonDec := json.NewDecoder(resp.Body)
+	err = jsonDec.Decode(&clusterData)
+	if err != nil {
+		return 0, err
+	}
+
+	compat := 0
+	for _, node := range clusterData.Nodes {
+		if compat == 0 || node.ClusterCompatibility < compat {
+			compat = node.ClusterCompatibility
+		}
+	}
+
+	return compat, nil
+}
+
+type jsonBucketBasicStats struct {
+	BasicStats struct {
+		ItemCount uint64 `json:"itemCount"`
+	} `json:"basicStats"`
+}
+
+// GetItemCount returns the number of items in a bucket.
+func (bm *bucketManagementProviderCore) GetItemCount(name string, opts *GetBucketOptions) (uint64, error) {
+	path := fmt.Sprintf("/pools/default/buckets/%s", url.PathEscape(name))
+	span := bm.tracer.createSpan(opts.ParentSpan, "manager_bucket_get_item_count", "management")
+	span.SetAttribute("db.name", name)
+	span.SetAttribute("db.operation", "GET "+path)
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Path:          path,
+		Method:        "GET",
+		IsIdempotent:  true,
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := bm.mgmtProvider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return 0, makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		bktErr := bm.tryParseErrorMessage(&req, resp)
+		if bktErr != nil {
+			return 0, bktErr
+		}
+
+		return 0, makeMgmtBadStatusError("failed to get bucket item count", &req, resp)
+	}
+
+	var statsData jsonBucketBasicStats
+	jsonDec := json.NewDecoder(resp.Body)
+	err = jsonDec.Decode(&statsData)
+	if err != nil {
+		return 0, err
+	}
+
+	return statsData.BasicStats.ItemCount, nil
+}
+
+// CompactBucket starts compacting a bucket.
+func (bm *bucketManagementProviderCore) CompactBucket(name string, opts *CompactBucketOptions) error {
+	path := fmt.Sprintf("/pools/default/buckets/%s/controller/compactBucket", url.PathEscape(name))
+	span := bm.tracer.createSpan(opts.ParentSpan, "manager_bucket_compact_bucket", "management")
+	span.SetAttribute("db.name", name)
+	span.SetAttribute("db.operation", "POST "+path)
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Path:          path,
+		Method:        "POST",
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := bm.mgmtProvider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		bktErr := bm.tryParseErrorMessage(&req, resp)
+		if bktErr != nil {
+			return bktErr
+		}
+
+		return makeMgmtBadStatusError("failed to compact bucket", &req, resp)
+	}
+
+	return nil
+}
+
+type jsonClusterTask struct {
+	Type     string  `json:"type"`
+	Bucket   string  `json:"bucket"`
+	Status   string  `json:"status"`
+	Progress float64 `json:"progress"`
+}
+
+// GetCompactionStatus returns the status of the compaction of a bucket, from the tasks running on the cluster.
+func (bm *bucketManagementProviderCore) GetCompactionStatus(name string, opts *GetCompactionStatusOptions) (*BucketCompactionStatus, error) {
+	path := "/pools/default/tasks"
+	span := bm.tracer.createSpan(opts.ParentSpan, "manager_bucket_get_compaction_status", "management")
+	span.SetAttribute("db.name", name)
+	span.SetAttribute("db.operation", "GET "+path)
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Path:          path,
+		Method:        "GET",
+		IsIdempotent:  true,
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := bm.mgmtProvider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return nil, makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, makeMgmtBadStatusError("failed to get compaction status", &req, resp)
+	}
+
+	var tasksData []jsonClusterTask
+	jsonDec := json.NewDecoder(resp.Body)
+	err = jsonDec.Decode(&tasksData)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &BucketCompactionStatus{}
+	for _, task := range tasksData {
+		if task.Type != "bucket_compaction" || task.Bucket != name {
+			continue
+		}
+
+		status.Running = task.Status == "running"
+		status.Progress = task.Progress
+		break
+	}
+
+	return status, nil
+}
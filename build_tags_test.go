@@ -0,0 +1,112 @@
+package gocb
+
+import (
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// TestNoCouchbase2BuildTag verifies that every couchbase2 provider is excluded by the gocb_nocouchbase2 build tag, and
+// that nothing left in such a build imports the gRPC or protostellar packages.
+func (suite *UnitTestSuite) TestNoCouchbase2BuildTag() {
+	fset := token.NewFileSet()
+	err := filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != "." && (strings.HasPrefix(info.Name(), ".") || info.Name() == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly|parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		included := true
+		for _, group := range file.Comments {
+			if group.Pos() > file.Package {
+				break
+			}
+			for _, comment := range group.List {
+				if !constraint.IsGoBuild(comment.Text) {
+					continue
+				}
+				expr, err := constraint.Parse(comment.Text)
+				if err != nil {
+					return err
+				}
+				included = expr.Eval(func(tag string) bool {
+					return tag == "gocb_nocouchbase2"
+				})
+			}
+		}
+
+		if strings.HasSuffix(path, "_ps.go") {
+			suite.Assert().False(included, "%s is not excluded by the gocb_nocouchbase2 tag", path)
+		}
+		if !included {
+			return nil
+		}
+
+		for _, imp := range file.Imports {
+			importPath, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				return err
+			}
+			suite.Assert().False(strings.Contains(importPath, "grpc") || strings.Contains(importPath, "protostellar"),
+				"%s imports %s in a build with the gocb_nocouchbase2 tag", path, importPath)
+		}
+
+		return nil
+	})
+	suite.Require().Nil(err, err)
+}
+
+// TestFeatureBuildTags verifies that the providers for each optional service are excluded by that service's build tag.
+func (suite *UnitTestSuite) TestFeatureBuildTags() {
+	features := map[string][]string{
+		"gocb_nosearch": {
+			"searchprovider_core.go", "searchindexprovider_core.go", "searchprovider_ps.go",
+			"searchindexprovider_ps.go", "client_core_search.go", "client_ps_search.go",
+		},
+		"gocb_noanalytics":    {"analyticsprovider_core.go", "analyticsindexprovider_core.go", "client_core_analytics.go"},
+		"gocb_noeventing":     {"eventingmgmtprovider_core.go", "client_core_eventing.go"},
+		"gocb_notransactions": {"transactionsprovider_core.go", "client_core_transactions.go"},
+	}
+
+	fset := token.NewFileSet()
+	for tag, paths := range features {
+		for _, path := range paths {
+			file, err := parser.ParseFile(fset, path, nil, parser.PackageClauseOnly|parser.ParseComments)
+			suite.Require().Nil(err, err)
+
+			var expr constraint.Expr
+			for _, group := range file.Comments {
+				if group.Pos() > file.Package {
+					break
+				}
+				for _, comment := range group.List {
+					if constraint.IsGoBuild(comment.Text) {
+						expr, err = constraint.Parse(comment.Text)
+						suite.Require().Nil(err, err)
+					}
+				}
+			}
+			if suite.Assert().NotNil(expr, "%s has no build constraint", path) {
+				suite.Assert().False(expr.Eval(func(t string) bool { return t == tag }),
+					"%s is not excluded by the %s tag", path, tag)
+			}
+		}
+	}
+}
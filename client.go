@@ -31,6 +31,11 @@ type connectionManager interface {
 	getSearchCapabilitiesProvider() (searchCapabilityVerifier, error)
 	getEventingManagementProvider() (eventingManagementProvider, error)
 	getUserManagerProvider() (userManagerProvider, error)
+	getSecurityManagementProvider() (securityManagementProvider, error)
+	getLogCollectionProvider() (logCollectionProvider, error)
+	getXDCRManagementProvider() (xdcrManagementProvider, error)
+	getClusterSettingsProvider() (clusterSettingsProvider, error)
+	getNodeManagementProvider() (nodeManagementProvider, error)
 	getInternalProvider() (internalProvider, error)
 
 	initTransactions(config TransactionsConfig, cluster *Cluster) error
@@ -78,6 +83,16 @@ func autoOpControl[T any, P any](controller *providerController[P], operation st
 		return emptyT, err
 	}
 
+	if operation != "" && controller.meter != nil {
+		if res, ok := any(retT).(streamingResult); ok {
+			res.setStreamOutcome(&streamOutcome{
+				record: func(err error) {
+					controller.meter.countUnsuccessful(controller.service, operation, controller.keyspace, err)
+				},
+			})
+		}
+	}
+
 	return retT, nil
 }
 
@@ -94,18 +109,16 @@ type newConnectionMgrOptions struct {
 	tracer *tracerWrapper
 	meter  *meterWrapper
 
-	preferredServerGroup string
+	preferredServerGroup    string
+	endpointSelectionPolicy EndpointSelectionPolicy
 }
 
-func (c *Cluster) newConnectionMgr(protocol string, opts *newConnectionMgrOptions) connectionManager {
+func (c *Cluster) newConnectionMgr(protocol string, opts *newConnectionMgrOptions) (connectionManager, error) {
 	switch protocol {
 	case "couchbase2":
-		return &psConnectionMgr{
-			timeouts:     c.timeoutsConfig,
-			tracer:       opts.tracer,
-			meter:        opts.meter,
-			defaultRetry: c.retryStrategyWrapper.wrapped,
-		}
+		return c.newPsConnectionMgr(opts)
+	case "https":
+		return c.newDataAPIConnectionMgr(opts), nil
 	default:
 		return &stdConnectionMgr{
 			retryStrategyWrapper: c.retryStrategyWrapper,
@@ -114,6 +127,10 @@ func (c *Cluster) newConnectionMgr(protocol string, opts *newConnectionMgrOption
 			tracer:               opts.tracer,
 			meter:                opts.meter,
 			preferredServerGroup: opts.preferredServerGroup,
-		}
+			timeSource:           c.internalConfig.TimeSource,
+
+			endpointSelectionPolicy: opts.endpointSelectionPolicy,
+			queryEndpointSelector:   newLatencyAwareEndpointSelector(),
+		}, nil
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/couchbase/gocbcore/v10"
 )
@@ -19,8 +20,12 @@ type stdConnectionMgr struct {
 	timeouts             TimeoutsConfig
 	tracer               *tracerWrapper
 	meter                *meterWrapper
-	txns                 *transactionsProviderCore
+	txns                 transactionsProvider
 	preferredServerGroup string
+	timeSource           func() time.Time
+
+	endpointSelectionPolicy EndpointSelectionPolicy
+	queryEndpointSelector   *latencyAwareEndpointSelector
 
 	closed      atomic.Bool
 	activeOpsWg sync.WaitGroup
@@ -82,8 +87,7 @@ func (c *stdConnectionMgr) buildConfig(cluster *Cluster) error {
 				Tracer:           &coreRequestTracerWrapper{tracer: c.tracer.tracer},
 			},
 			MeterConfig: gocbcore.MeterConfig{
-				// At the moment we only support our own operations metric so there's no point in setting a meter for gocbcore.
-				Meter: nil,
+				Meter: c.coreMeter(),
 			},
 			CompressionConfig: gocbcore.CompressionConfig{
 				Enabled:  !cluster.compressionConfig.Disabled,
@@ -182,6 +186,7 @@ func (c *stdConnectionMgr) getKvProvider(bucketName string) (kvProvider, error)
 
 		tracer:               c.tracer,
 		preferredServerGroup: c.preferredServerGroup,
+		timeSource:           c.timeSource,
 	}, nil
 }
 
@@ -198,10 +203,12 @@ func (c *stdConnectionMgr) getKvBulkProvider(bucketName string) (kvBulkProvider,
 		return nil, errors.New("bucket not yet connected")
 	}
 	return &kvBulkProviderCore{
-		agent: agent,
+		agent:            agent,
+		snapshotProvider: &stdCoreConfigSnapshotProvider{agent: agent},
 
-		tracer: c.tracer,
-		meter:  c.meter,
+		tracer:     c.tracer,
+		meter:      c.meter,
+		timeSource: c.timeSource,
 	}, nil
 }
 
@@ -285,58 +292,13 @@ func (c *stdConnectionMgr) getQueryProvider() (queryProvider, error) {
 		transcoder:           c.transcoder,
 		timeouts:             c.timeouts,
 		tracer:               c.tracer,
-	}, nil
-}
-
-func (c *stdConnectionMgr) getQueryIndexProvider() (queryIndexProvider, error) {
-	if err := c.canPerformOp(); err != nil {
-		return nil, err
-	}
-
-	if c.agentgroup == nil {
-		return nil, errors.New("cluster not yet connected")
-	}
-
-	return &queryProviderCore{
-		provider: &queryProviderWrapper{provider: c.agentgroup},
-
-		retryStrategyWrapper: c.retryStrategyWrapper,
-		transcoder:           c.transcoder,
-		timeouts:             c.timeouts,
-		tracer:               c.tracer,
-	}, nil
-}
-
-func (c *stdConnectionMgr) getAnalyticsProvider() (analyticsProvider, error) {
-	if err := c.canPerformOp(); err != nil {
-		return nil, err
-	}
-
-	if c.agentgroup == nil {
-		return nil, errors.New("cluster not yet connected")
-	}
-
-	mgmtProvider, err := c.getHTTPProvider("")
-	if err != nil {
-		return nil, err
-	}
-
-	return &analyticsProviderCore{
-		provider: &analyticsProviderWrapper{provider: c.agentgroup},
-		mgmtProvider: &mgmtProviderCore{
-			provider:             mgmtProvider,
-			mgmtTimeout:          c.timeouts.ManagementTimeout,
-			retryStrategyWrapper: c.retryStrategyWrapper,
-		},
 
-		retryStrategyWrapper: c.retryStrategyWrapper,
-		transcoder:           c.transcoder,
-		analyticsTimeout:     c.timeouts.AnalyticsTimeout,
-		tracer:               c.tracer,
+		endpointSelectionPolicy: c.endpointSelectionPolicy,
+		endpointSelector:        c.queryEndpointSelector,
 	}, nil
 }
 
-func (c *stdConnectionMgr) getAnalyticsIndexProvider() (analyticsIndexProvider, error) {
+func (c *stdConnectionMgr) getQueryIndexProvider() (queryIndexProvider, error) {
 	if err := c.canPerformOp(); err != nil {
 		return nil, err
 	}
@@ -345,37 +307,19 @@ func (c *stdConnectionMgr) getAnalyticsIndexProvider() (analyticsIndexProvider,
 		return nil, errors.New("cluster not yet connected")
 	}
 
-	mgmtProvider, err := c.getHTTPProvider("")
+	httpProvider, err := c.getHTTPProvider("")
 	if err != nil {
 		return nil, err
 	}
 
-	return &analyticsProviderCore{
-		provider: &analyticsProviderWrapper{provider: c.agentgroup},
+	return &queryProviderCore{
+		provider: &queryProviderWrapper{provider: c.agentgroup},
 		mgmtProvider: &mgmtProviderCore{
-			provider:             mgmtProvider,
+			provider:             httpProvider,
 			mgmtTimeout:          c.timeouts.ManagementTimeout,
 			retryStrategyWrapper: c.retryStrategyWrapper,
 		},
 
-		retryStrategyWrapper: c.retryStrategyWrapper,
-		transcoder:           c.transcoder,
-		analyticsTimeout:     c.timeouts.AnalyticsTimeout,
-		tracer:               c.tracer,
-	}, nil
-}
-
-func (c *stdConnectionMgr) getSearchProvider() (searchProvider, error) {
-	if err := c.canPerformOp(); err != nil {
-		return nil, err
-	}
-
-	if c.agentgroup == nil {
-		return nil, errors.New("cluster not yet connected")
-	}
-
-	return &searchProviderCore{
-		provider:             &searchProviderWrapper{agent: c.agentgroup},
 		retryStrategyWrapper: c.retryStrategyWrapper,
 		transcoder:           c.transcoder,
 		timeouts:             c.timeouts,
@@ -383,44 +327,6 @@ func (c *stdConnectionMgr) getSearchProvider() (searchProvider, error) {
 	}, nil
 }
 
-func (c *stdConnectionMgr) getSearchIndexProvider() (searchIndexProvider, error) {
-	if err := c.canPerformOp(); err != nil {
-		return nil, err
-	}
-
-	provider, err := c.getHTTPProvider("")
-	if err != nil {
-		return nil, err
-	}
-
-	capVerifier, err := c.getSearchCapabilitiesProvider()
-	if err != nil {
-		return nil, err
-	}
-
-	return &searchIndexProviderCore{
-		mgmtProvider: &mgmtProviderCore{
-			provider:             provider,
-			mgmtTimeout:          c.timeouts.ManagementTimeout,
-			retryStrategyWrapper: c.retryStrategyWrapper,
-		},
-		searchCapVerifier: capVerifier,
-		tracer:            c.tracer,
-	}, nil
-}
-
-func (c *stdConnectionMgr) getSearchCapabilitiesProvider() (searchCapabilityVerifier, error) {
-	if err := c.canPerformOp(); err != nil {
-		return nil, err
-	}
-
-	if c.agentgroup == nil {
-		return nil, errors.New("cluster not yet connected")
-	}
-
-	return c.agentgroup.Internal(), nil
-}
-
 func (c *stdConnectionMgr) getHTTPProvider(bucketName string) (httpProvider, error) {
 	if err := c.canPerformOp(); err != nil {
 		return nil, err
@@ -549,7 +455,7 @@ func (c *stdConnectionMgr) getBucketManagementProvider() (bucketManagementProvid
 	}, nil
 }
 
-func (c *stdConnectionMgr) getEventingManagementProvider() (eventingManagementProvider, error) {
+func (c *stdConnectionMgr) getUserManagerProvider() (userManagerProvider, error) {
 	if err := c.canPerformOp(); err != nil {
 		return nil, err
 	}
@@ -559,8 +465,8 @@ func (c *stdConnectionMgr) getEventingManagementProvider() (eventingManagementPr
 		return nil, err
 	}
 
-	return &eventingManagementProviderCore{
-		mgmtProvider: &mgmtProviderCore{
+	return &userManagerProviderCore{
+		provider: &mgmtProviderCore{
 			provider:             provider,
 			mgmtTimeout:          c.timeouts.ManagementTimeout,
 			retryStrategyWrapper: c.retryStrategyWrapper,
@@ -569,7 +475,7 @@ func (c *stdConnectionMgr) getEventingManagementProvider() (eventingManagementPr
 	}, nil
 }
 
-func (c *stdConnectionMgr) getUserManagerProvider() (userManagerProvider, error) {
+func (c *stdConnectionMgr) getSecurityManagementProvider() (securityManagementProvider, error) {
 	if err := c.canPerformOp(); err != nil {
 		return nil, err
 	}
@@ -579,7 +485,7 @@ func (c *stdConnectionMgr) getUserManagerProvider() (userManagerProvider, error)
 		return nil, err
 	}
 
-	return &userManagerProviderCore{
+	return &securityManagementProviderCore{
 		provider: &mgmtProviderCore{
 			provider:             provider,
 			mgmtTimeout:          c.timeouts.ManagementTimeout,
@@ -589,7 +495,7 @@ func (c *stdConnectionMgr) getUserManagerProvider() (userManagerProvider, error)
 	}, nil
 }
 
-func (c *stdConnectionMgr) getInternalProvider() (internalProvider, error) {
+func (c *stdConnectionMgr) getLogCollectionProvider() (logCollectionProvider, error) {
 	if err := c.canPerformOp(); err != nil {
 		return nil, err
 	}
@@ -599,38 +505,96 @@ func (c *stdConnectionMgr) getInternalProvider() (internalProvider, error) {
 		return nil, err
 	}
 
-	return &internalProviderCore{
+	return &logCollectionProviderCore{
 		provider: &mgmtProviderCore{
 			provider:             provider,
 			mgmtTimeout:          c.timeouts.ManagementTimeout,
 			retryStrategyWrapper: c.retryStrategyWrapper,
 		},
 		tracer: c.tracer,
-		meter:  c.meter,
 	}, nil
+}
+
+func (c *stdConnectionMgr) getXDCRManagementProvider() (xdcrManagementProvider, error) {
+	if err := c.canPerformOp(); err != nil {
+		return nil, err
+	}
+
+	provider, err := c.getHTTPProvider("")
+	if err != nil {
+		return nil, err
+	}
 
+	return &xdcrManagementProviderCore{
+		provider: &mgmtProviderCore{
+			provider:             provider,
+			mgmtTimeout:          c.timeouts.ManagementTimeout,
+			retryStrategyWrapper: c.retryStrategyWrapper,
+		},
+		tracer: c.tracer,
+	}, nil
 }
 
-// initTransactions must only be called during cluster setup to prevent races.
-func (c *stdConnectionMgr) initTransactions(config TransactionsConfig, cluster *Cluster) error {
-	txns := &transactionsProviderCore{
-		getAgentProvider: c.agentgroup,
+func (c *stdConnectionMgr) getClusterSettingsProvider() (clusterSettingsProvider, error) {
+	if err := c.canPerformOp(); err != nil {
+		return nil, err
 	}
-	err := txns.Init(config, cluster)
+
+	provider, err := c.getHTTPProvider("")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	c.txns = txns
-	return nil
+	return &clusterSettingsProviderCore{
+		provider: &mgmtProviderCore{
+			provider:             provider,
+			mgmtTimeout:          c.timeouts.ManagementTimeout,
+			retryStrategyWrapper: c.retryStrategyWrapper,
+		},
+		tracer: c.tracer,
+	}, nil
+}
+
+func (c *stdConnectionMgr) getNodeManagementProvider() (nodeManagementProvider, error) {
+	if err := c.canPerformOp(); err != nil {
+		return nil, err
+	}
+
+	provider, err := c.getHTTPProvider("")
+	if err != nil {
+		return nil, err
+	}
+
+	return &nodeManagementProviderCore{
+		provider: &mgmtProviderCore{
+			provider:             provider,
+			mgmtTimeout:          c.timeouts.ManagementTimeout,
+			retryStrategyWrapper: c.retryStrategyWrapper,
+		},
+		tracer: c.tracer,
+	}, nil
 }
 
-func (c *stdConnectionMgr) getTransactionsProvider() (transactionsProvider, error) {
+func (c *stdConnectionMgr) getInternalProvider() (internalProvider, error) {
 	if err := c.canPerformOp(); err != nil {
 		return nil, err
 	}
 
-	return c.txns, nil
+	provider, err := c.getHTTPProvider("")
+	if err != nil {
+		return nil, err
+	}
+
+	return &internalProviderCore{
+		provider: &mgmtProviderCore{
+			provider:             provider,
+			mgmtTimeout:          c.timeouts.ManagementTimeout,
+			retryStrategyWrapper: c.retryStrategyWrapper,
+		},
+		tracer: c.tracer,
+		meter:  c.meter,
+	}, nil
+
 }
 
 func (c *stdConnectionMgr) connection(bucketName string) (*gocbcore.Agent, error) {
@@ -654,13 +618,7 @@ func (c *stdConnectionMgr) close() error {
 		return ErrShutdown
 	}
 
-	if c.txns != nil {
-		err := c.txns.close()
-		if err != nil {
-			logWarnf("Failed to close transactions in cluster close: %s", err)
-		}
-		c.txns = nil
-	}
+	c.closeTransactions()
 
 	c.lock.Lock()
 	if c.agentgroup == nil {
@@ -688,6 +646,20 @@ func (c *stdConnectionMgr) close() error {
 	return err
 }
 
+// coreMeter returns the meter that gocbcore records its metrics to. The noop and logging meters aren't given to
+// gocbcore, neither of them records the metrics that it reports.
+func (c *stdConnectionMgr) coreMeter() gocbcore.Meter {
+	if c.meter == nil {
+		return nil
+	}
+	switch c.meter.meter.(type) {
+	case nil, *NoopMeter, *LoggingMeter:
+		return nil
+	}
+
+	return &coreMeterWrapper{meter: c.meter.meter}
+}
+
 func (c *stdConnectionMgr) getMeter() *meterWrapper {
 	return c.meter
 }
@@ -0,0 +1,63 @@
+//go:build !gocb_noanalytics
+
+package gocb
+
+import "errors"
+
+func (c *stdConnectionMgr) getAnalyticsProvider() (analyticsProvider, error) {
+	if err := c.canPerformOp(); err != nil {
+		return nil, err
+	}
+
+	if c.agentgroup == nil {
+		return nil, errors.New("cluster not yet connected")
+	}
+
+	mgmtProvider, err := c.getHTTPProvider("")
+	if err != nil {
+		return nil, err
+	}
+
+	return &analyticsProviderCore{
+		provider: &analyticsProviderWrapper{provider: c.agentgroup},
+		mgmtProvider: &mgmtProviderCore{
+			provider:             mgmtProvider,
+			mgmtTimeout:          c.timeouts.ManagementTimeout,
+			retryStrategyWrapper: c.retryStrategyWrapper,
+		},
+
+		retryStrategyWrapper: c.retryStrategyWrapper,
+		transcoder:           c.transcoder,
+		analyticsTimeout:     c.timeouts.AnalyticsTimeout,
+		tracer:               c.tracer,
+	}, nil
+}
+
+func (c *stdConnectionMgr) getAnalyticsIndexProvider() (analyticsIndexProvider, error) {
+	if err := c.canPerformOp(); err != nil {
+		return nil, err
+	}
+
+	if c.agentgroup == nil {
+		return nil, errors.New("cluster not yet connected")
+	}
+
+	mgmtProvider, err := c.getHTTPProvider("")
+	if err != nil {
+		return nil, err
+	}
+
+	return &analyticsProviderCore{
+		provider: &analyticsProviderWrapper{provider: c.agentgroup},
+		mgmtProvider: &mgmtProviderCore{
+			provider:             mgmtProvider,
+			mgmtTimeout:          c.timeouts.ManagementTimeout,
+			retryStrategyWrapper: c.retryStrategyWrapper,
+		},
+
+		retryStrategyWrapper: c.retryStrategyWrapper,
+		transcoder:           c.transcoder,
+		analyticsTimeout:     c.timeouts.AnalyticsTimeout,
+		tracer:               c.tracer,
+	}, nil
+}
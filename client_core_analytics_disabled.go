@@ -0,0 +1,14 @@
+//go:build gocb_noanalytics
+
+package gocb
+
+// The analytics providers are used in builds with the gocb_noanalytics build tag, which exclude support for the
+// analytics service from the binary.
+
+func (c *stdConnectionMgr) getAnalyticsProvider() (analyticsProvider, error) {
+	return nil, wrapError(ErrFeatureNotAvailable, "analytics is not supported by this build, as it was built with the gocb_noanalytics tag")
+}
+
+func (c *stdConnectionMgr) getAnalyticsIndexProvider() (analyticsIndexProvider, error) {
+	return nil, wrapError(ErrFeatureNotAvailable, "analytics is not supported by this build, as it was built with the gocb_noanalytics tag")
+}
@@ -0,0 +1,23 @@
+//go:build !gocb_noeventing
+
+package gocb
+
+func (c *stdConnectionMgr) getEventingManagementProvider() (eventingManagementProvider, error) {
+	if err := c.canPerformOp(); err != nil {
+		return nil, err
+	}
+
+	provider, err := c.getHTTPProvider("")
+	if err != nil {
+		return nil, err
+	}
+
+	return &eventingManagementProviderCore{
+		mgmtProvider: &mgmtProviderCore{
+			provider:             provider,
+			mgmtTimeout:          c.timeouts.ManagementTimeout,
+			retryStrategyWrapper: c.retryStrategyWrapper,
+		},
+		tracer: c.tracer,
+	}, nil
+}
@@ -0,0 +1,9 @@
+//go:build gocb_noeventing
+
+package gocb
+
+// getEventingManagementProvider is used in builds with the gocb_noeventing build tag, which exclude support for the
+// eventing service from the binary.
+func (c *stdConnectionMgr) getEventingManagementProvider() (eventingManagementProvider, error) {
+	return nil, wrapError(ErrFeatureNotAvailable, "eventing is not supported by this build, as it was built with the gocb_noeventing tag")
+}
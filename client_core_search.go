@@ -0,0 +1,62 @@
+//go:build !gocb_nosearch
+
+package gocb
+
+import "errors"
+
+func (c *stdConnectionMgr) getSearchProvider() (searchProvider, error) {
+	if err := c.canPerformOp(); err != nil {
+		return nil, err
+	}
+
+	if c.agentgroup == nil {
+		return nil, errors.New("cluster not yet connected")
+	}
+
+	return &searchProviderCore{
+		provider:             &searchProviderWrapper{agent: c.agentgroup},
+		retryStrategyWrapper: c.retryStrategyWrapper,
+		transcoder:           c.transcoder,
+		timeouts:             c.timeouts,
+		tracer:               c.tracer,
+	}, nil
+}
+
+func (c *stdConnectionMgr) getSearchIndexProvider() (searchIndexProvider, error) {
+	if err := c.canPerformOp(); err != nil {
+		return nil, err
+	}
+
+	provider, err := c.getHTTPProvider("")
+	if err != nil {
+		return nil, err
+	}
+
+	capVerifier, err := c.getSearchCapabilitiesProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &searchIndexProviderCore{
+		mgmtProvider: &mgmtProviderCore{
+			provider:             provider,
+			mgmtTimeout:          c.timeouts.ManagementTimeout,
+			retryStrategyWrapper: c.retryStrategyWrapper,
+		},
+		searchCapVerifier: capVerifier,
+		timeouts:          c.timeouts,
+		tracer:            c.tracer,
+	}, nil
+}
+
+func (c *stdConnectionMgr) getSearchCapabilitiesProvider() (searchCapabilityVerifier, error) {
+	if err := c.canPerformOp(); err != nil {
+		return nil, err
+	}
+
+	if c.agentgroup == nil {
+		return nil, errors.New("cluster not yet connected")
+	}
+
+	return c.agentgroup.Internal(), nil
+}
@@ -0,0 +1,18 @@
+//go:build gocb_nosearch
+
+package gocb
+
+// The search providers are used in builds with the gocb_nosearch build tag, which exclude support for the search
+// service from the binary.
+
+func (c *stdConnectionMgr) getSearchProvider() (searchProvider, error) {
+	return nil, wrapError(ErrFeatureNotAvailable, "search is not supported by this build, as it was built with the gocb_nosearch tag")
+}
+
+func (c *stdConnectionMgr) getSearchIndexProvider() (searchIndexProvider, error) {
+	return nil, wrapError(ErrFeatureNotAvailable, "search is not supported by this build, as it was built with the gocb_nosearch tag")
+}
+
+func (c *stdConnectionMgr) getSearchCapabilitiesProvider() (searchCapabilityVerifier, error) {
+	return nil, wrapError(ErrFeatureNotAvailable, "search is not supported by this build, as it was built with the gocb_nosearch tag")
+}
@@ -0,0 +1,38 @@
+//go:build !gocb_notransactions
+
+package gocb
+
+// initTransactions must only be called during cluster setup to prevent races.
+func (c *stdConnectionMgr) initTransactions(config TransactionsConfig, cluster *Cluster) error {
+	txns := &transactionsProviderCore{
+		getAgentProvider: c.agentgroup,
+	}
+	err := txns.Init(config, cluster)
+	if err != nil {
+		return err
+	}
+
+	c.txns = txns
+	return nil
+}
+
+func (c *stdConnectionMgr) getTransactionsProvider() (transactionsProvider, error) {
+	if err := c.canPerformOp(); err != nil {
+		return nil, err
+	}
+
+	return c.txns, nil
+}
+
+func (c *stdConnectionMgr) closeTransactions() {
+	txns, ok := c.txns.(*transactionsProviderCore)
+	if !ok || txns == nil {
+		return
+	}
+
+	err := txns.close()
+	if err != nil {
+		logWarnf("Failed to close transactions in cluster close: %s", err)
+	}
+	c.txns = nil
+}
@@ -0,0 +1,17 @@
+//go:build gocb_notransactions
+
+package gocb
+
+// The transactions providers are used in builds with the gocb_notransactions build tag, which exclude support for
+// transactions from the binary.
+
+func (c *stdConnectionMgr) initTransactions(config TransactionsConfig, cluster *Cluster) error {
+	// As with couchbase2, returning an error here would cause cluster setup to always fail.
+	return nil
+}
+
+func (c *stdConnectionMgr) getTransactionsProvider() (transactionsProvider, error) {
+	return nil, wrapError(ErrFeatureNotAvailable, "transactions are not supported by this build, as it was built with the gocb_notransactions tag")
+}
+
+func (c *stdConnectionMgr) closeTransactions() {}
@@ -0,0 +1,364 @@
+package gocb
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+)
+
+// dataAPIConnectionMgr routes operations through the Capella Data API, a REST interface which is served over HTTPS.
+// It allows applications to reach a cluster from networks where only port 443 egress is permitted, at the cost of
+// only supporting key value CRUD operations and queries.
+type dataAPIConnectionMgr struct {
+	baseURL  string
+	username string
+	password string
+	client   *http.Client
+
+	timeouts   TimeoutsConfig
+	tracer     *tracerWrapper
+	meter      *meterWrapper
+	timeSource func() time.Time
+
+	closed      atomic.Bool
+	activeOpsWg sync.WaitGroup
+}
+
+func (c *Cluster) newDataAPIConnectionMgr(opts *newConnectionMgrOptions) *dataAPIConnectionMgr {
+	return &dataAPIConnectionMgr{
+		timeouts:   c.timeoutsConfig,
+		tracer:     opts.tracer,
+		meter:      opts.meter,
+		timeSource: c.internalConfig.TimeSource,
+	}
+}
+
+func (c *dataAPIConnectionMgr) connect() error {
+	return nil
+}
+
+func (c *dataAPIConnectionMgr) openBucket(bucketName string) error {
+	return nil
+}
+
+func (c *dataAPIConnectionMgr) buildConfig(cluster *Cluster) error {
+	spec := cluster.connSpec()
+	if len(spec.Addresses) != 1 {
+		return makeInvalidArgumentsError("the Data API connection string must contain exactly one host")
+	}
+
+	address := spec.Addresses[0]
+	if address.Port > 0 {
+		c.baseURL = fmt.Sprintf("https://%s:%d", address.Host, address.Port)
+	} else {
+		c.baseURL = "https://" + address.Host
+	}
+
+	creds, err := cluster.authenticator().Credentials(AuthCredsRequest{})
+	if err != nil {
+		return err
+	}
+	if len(creds) == 0 {
+		return makeInvalidArgumentsError("the Data API requires a username and password")
+	}
+	c.username = creds[0].Username
+	c.password = creds[0].Password
+
+	c.client = &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				RootCAs:            cluster.securityConfig.TLSRootCAs,
+				InsecureSkipVerify: cluster.securityConfig.TLSSkipVerify, // nolint: gosec
+				MinVersion:         tls.VersionTLS12,
+			},
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     4500 * time.Millisecond,
+		},
+	}
+
+	return nil
+}
+
+func (c *dataAPIConnectionMgr) canPerformOp() error {
+	if c.closed.Load() {
+		return ErrShutdown
+	}
+
+	return nil
+}
+
+func (c *dataAPIConnectionMgr) MarkOpBeginning() {
+	c.activeOpsWg.Add(1)
+}
+
+func (c *dataAPIConnectionMgr) MarkOpCompleted() {
+	c.activeOpsWg.Done()
+}
+
+// dataAPIMaxResponseSize is the largest response body which is read from the Data API. As the proxy does not stream
+// query rows, this bounds the memory used by a query with an unexpectedly large result.
+const dataAPIMaxResponseSize = 128 * 1024 * 1024
+
+type dataAPIResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// execute sends a request to the Data API, reading the entire response body. A request which times out after being
+// sent may have been applied, so the timeout is only unambiguous for idempotent requests.
+func (c *dataAPIConnectionMgr) execute(ctx context.Context, timeout time.Duration, idempotent bool, method, path string,
+	header http.Header, body []byte) (*dataAPIResponse, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			if idempotent {
+				return nil, wrapError(ErrUnambiguousTimeout, err.Error())
+			}
+			return nil, wrapError(ErrAmbiguousTimeout, err.Error())
+		}
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil, makeGenericError(ErrRequestCanceled, nil)
+		}
+		return nil, err
+	}
+
+	// One byte more than the maximum is read so that a body of exactly the maximum size is not mistaken for one
+	// which was truncated.
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, dataAPIMaxResponseSize+1))
+	if closeErr := resp.Body.Close(); closeErr != nil {
+		logDebugf("Failed to close socket (%s)", closeErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(respBody) > dataAPIMaxResponseSize {
+		return nil, fmt.Errorf("data api response exceeded the maximum size of %d bytes", dataAPIMaxResponseSize)
+	}
+
+	return &dataAPIResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       respBody,
+	}, nil
+}
+
+// dataAPIStatusError maps an unsuccessful Data API response onto the corresponding SDK error.
+func dataAPIStatusError(resp *dataAPIResponse) error {
+	var baseErr error
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		baseErr = ErrAuthenticationFailure
+	case http.StatusNotFound:
+		baseErr = ErrDocumentNotFound
+	case http.StatusConflict:
+		baseErr = ErrDocumentExists
+	case http.StatusPreconditionFailed:
+		baseErr = ErrCasMismatch
+	case http.StatusLocked:
+		baseErr = ErrDocumentLocked
+	case http.StatusRequestEntityTooLarge:
+		baseErr = ErrValueTooLarge
+	case http.StatusTooManyRequests:
+		baseErr = ErrRateLimitedFailure
+	case http.StatusServiceUnavailable:
+		baseErr = ErrServiceNotAvailable
+	case http.StatusGatewayTimeout:
+		baseErr = ErrAmbiguousTimeout
+	default:
+		baseErr = errors.New("unexpected data api response status " + strconv.Itoa(resp.StatusCode))
+	}
+
+	return makeGenericError(baseErr, map[string]interface{}{
+		"status_code": resp.StatusCode,
+		"body":        string(resp.Body),
+	})
+}
+
+func (c *dataAPIConnectionMgr) getKvProvider(bucketName string) (kvProvider, error) {
+	if err := c.canPerformOp(); err != nil {
+		return nil, err
+	}
+
+	return &kvProviderDataAPI{
+		mgr:    c,
+		tracer: c.tracer,
+	}, nil
+}
+
+func (c *dataAPIConnectionMgr) getKvBulkProvider(bucketName string) (kvBulkProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getKvCapabilitiesProvider(bucketName string) (kvCapabilityVerifier, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getViewProvider(bucketName string) (viewProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getViewIndexProvider(bucketName string) (viewIndexProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getQueryProvider() (queryProvider, error) {
+	if err := c.canPerformOp(); err != nil {
+		return nil, err
+	}
+
+	return &queryProviderDataAPI{
+		mgr:    c,
+		tracer: c.tracer,
+	}, nil
+}
+
+func (c *dataAPIConnectionMgr) getQueryIndexProvider() (queryIndexProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getAnalyticsProvider() (analyticsProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getAnalyticsIndexProvider() (analyticsIndexProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getSearchProvider() (searchProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getHTTPProvider(bucketName string) (httpProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getDiagnosticsProvider(bucketName string) (diagnosticsProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getWaitUntilReadyProvider(bucketName string) (waitUntilReadyProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getCollectionsManagementProvider(bucketName string) (collectionsManagementProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getBucketManagementProvider() (bucketManagementProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getSearchIndexProvider() (searchIndexProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getSearchCapabilitiesProvider() (searchCapabilityVerifier, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getEventingManagementProvider() (eventingManagementProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getUserManagerProvider() (userManagerProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getSecurityManagementProvider() (securityManagementProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getLogCollectionProvider() (logCollectionProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getXDCRManagementProvider() (xdcrManagementProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getClusterSettingsProvider() (clusterSettingsProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getNodeManagementProvider() (nodeManagementProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) getInternalProvider() (internalProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) initTransactions(config TransactionsConfig, cluster *Cluster) error {
+	// As with couchbase2, returning an error here would cause cluster setup to always fail.
+	return nil
+}
+
+func (c *dataAPIConnectionMgr) getTransactionsProvider() (transactionsProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) connection(bucketName string) (*gocbcore.Agent, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *dataAPIConnectionMgr) close() error {
+	if !c.closed.CompareAndSwap(false, true) {
+		return ErrShutdown
+	}
+
+	logDebugf("Waiting for any active requests to complete")
+	c.activeOpsWg.Wait()
+
+	if c.client != nil {
+		c.client.CloseIdleConnections()
+	}
+
+	if c.tracer != nil {
+		tracerDecRef(c.tracer.tracer)
+		c.tracer = nil
+	}
+	if c.meter != nil {
+		if meter, ok := c.meter.meter.(*LoggingMeter); ok {
+			meter.close()
+		}
+		c.meter = nil
+	}
+
+	return nil
+}
+
+func (c *dataAPIConnectionMgr) getMeter() *meterWrapper {
+	return c.meter
+}
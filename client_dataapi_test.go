@@ -0,0 +1,195 @@
+package gocb
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+func (suite *UnitTestSuite) dataAPICluster(handler http.HandlerFunc) (*Cluster, func()) {
+	srv := httptest.NewTLSServer(handler)
+
+	cluster, err := Connect(srv.URL, suite.dataAPIClusterOptions())
+	suite.Require().Nil(err, err)
+
+	return cluster, func() {
+		suite.Assert().Nil(cluster.Close(nil))
+		srv.Close()
+	}
+}
+
+func (suite *UnitTestSuite) dataAPIClusterOptions() ClusterOptions {
+	return ClusterOptions{
+		Authenticator: PasswordAuthenticator{
+			Username: "Administrator",
+			Password: "password",
+		},
+		SecurityConfig: SecurityConfig{
+			TLSSkipVerify: true,
+		},
+		TimeoutsConfig: TimeoutsConfig{
+			KVTimeout:    time.Second,
+			QueryTimeout: time.Second,
+		},
+	}
+}
+
+func (suite *UnitTestSuite) TestDataAPIClusterOption() {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.Assert().Equal("/v1/buckets/default/scopes/_default/collections/_default/documents/key", r.URL.EscapedPath())
+		w.Header().Set("ETag", `"1234"`)
+		_, _ = w.Write([]byte(`{"name":"alice"}`))
+	}))
+	defer srv.Close()
+
+	opts := suite.dataAPIClusterOptions()
+	opts.UseDataAPI = true
+	cluster, err := Connect("couchbases://"+srv.Listener.Addr().String(), opts)
+	suite.Require().Nil(err, err)
+	defer func() {
+		suite.Assert().Nil(cluster.Close(nil))
+	}()
+
+	res, err := cluster.Bucket("default").DefaultCollection().Get("key", nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(Cas(1234), res.Cas())
+
+	_, err = Connect("couchbase2://"+srv.Listener.Addr().String(), opts)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestDataAPIUpsertGet() {
+	var stored []byte
+	var storedFlags string
+	cluster, closeFn := suite.dataAPICluster(func(w http.ResponseWriter, r *http.Request) {
+		suite.Assert().Equal("/v1/buckets/default/scopes/_default/collections/_default/documents/key%201", r.URL.EscapedPath())
+
+		username, password, ok := r.BasicAuth()
+		suite.Assert().True(ok)
+		suite.Assert().Equal("Administrator", username)
+		suite.Assert().Equal("password", password)
+
+		switch r.Method {
+		case http.MethodPut:
+			body, err := io.ReadAll(r.Body)
+			suite.Require().Nil(err)
+			stored = body
+			storedFlags = r.Header.Get(dataAPIFlagsHeader)
+			w.Header().Set("ETag", `"1234"`)
+			w.WriteHeader(http.StatusOK)
+		case http.MethodGet:
+			if stored == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("ETag", `"1234"`)
+			w.Header().Set(dataAPIFlagsHeader, storedFlags)
+			_, _ = w.Write(stored)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	defer closeFn()
+
+	collection := cluster.Bucket("default").DefaultCollection()
+
+	_, err := collection.Get("key 1", nil)
+	suite.Assert().ErrorIs(err, ErrDocumentNotFound)
+
+	res, err := collection.Upsert("key 1", map[string]string{"name": "alice"}, nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(Cas(1234), res.Cas())
+
+	getRes, err := collection.Get("key 1", nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(Cas(1234), getRes.Cas())
+
+	var doc map[string]string
+	suite.Require().Nil(getRes.Content(&doc))
+	suite.Assert().Equal(map[string]string{"name": "alice"}, doc)
+
+	_, err = collection.Touch("key 1", time.Minute, nil)
+	suite.Assert().ErrorIs(err, ErrFeatureNotAvailable)
+}
+
+func (suite *UnitTestSuite) TestDataAPIQuery() {
+	cluster, closeFn := suite.dataAPICluster(func(w http.ResponseWriter, r *http.Request) {
+		suite.Assert().Equal(http.MethodPost, r.Method)
+		suite.Assert().Equal("/_p/query/query/service", r.URL.Path)
+
+		var body map[string]interface{}
+		suite.Require().Nil(json.NewDecoder(r.Body).Decode(&body))
+
+		if body["statement"] == "SELECT bad" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"requestID":"2","errors":[{"code":3000,"msg":"syntax error"}],"status":"fatal"}`))
+			return
+		}
+
+		suite.Assert().Equal("1s", body["timeout"])
+
+		if body["statement"] == "SELECT 2" {
+			suite.Assert().Equal("default:`de``fault`.`_default`", body["query_context"])
+		} else {
+			suite.Assert().Equal("SELECT 1", body["statement"])
+			suite.Assert().Equal("default:`default`.`_default`", body["query_context"])
+		}
+		_, _ = w.Write([]byte(`{"requestID":"1","results":[{"$1":1},{"$1":2}],"status":"success","metrics":{"resultCount":2}}`))
+	})
+	defer closeFn()
+
+	res, err := cluster.Bucket("default").DefaultScope().Query("SELECT 1", nil)
+	suite.Require().Nil(err, err)
+
+	var rows []map[string]int
+	for res.Next() {
+		var row map[string]int
+		suite.Require().Nil(res.Row(&row))
+		rows = append(rows, row)
+	}
+	suite.Require().Nil(res.Err())
+	suite.Assert().Equal([]map[string]int{{"$1": 1}, {"$1": 2}}, rows)
+
+	meta, err := res.MetaData()
+	suite.Require().Nil(err)
+	suite.Assert().Equal("1", meta.RequestID)
+	suite.Assert().Equal(uint64(2), meta.Metrics.ResultCount)
+
+	_, err = cluster.Bucket("default").DefaultScope().Query("SELECT bad", nil)
+	var queryErr *QueryError
+	suite.Require().ErrorAs(err, &queryErr)
+	suite.Assert().Equal(400, queryErr.HTTPStatusCode)
+	suite.Require().Len(queryErr.Errors, 1)
+	suite.Assert().Equal(uint32(3000), queryErr.Errors[0].Code)
+	suite.Assert().Equal("syntax error", queryErr.Errors[0].Message)
+	suite.Assert().ErrorIs(err, ErrParsingFailure)
+
+	res, err = cluster.Bucket("de`fault").DefaultScope().Query("SELECT 2", nil)
+	suite.Require().Nil(err, err)
+	suite.Require().Nil(res.Close())
+}
+
+func (suite *UnitTestSuite) TestDataAPITimeouts() {
+	done := make(chan struct{})
+	cluster, closeFn := suite.dataAPICluster(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	})
+	defer closeFn()
+	defer close(done)
+
+	collection := cluster.Bucket("default").DefaultCollection()
+
+	_, err := collection.Get("key", &GetOptions{Timeout: 10 * time.Millisecond})
+	suite.Assert().ErrorIs(err, ErrUnambiguousTimeout)
+
+	_, err = collection.Upsert("key", "value", &UpsertOptions{Timeout: 10 * time.Millisecond})
+	suite.Assert().ErrorIs(err, ErrAmbiguousTimeout)
+
+	_, err = cluster.Query("SELECT 1", &QueryOptions{Timeout: 10 * time.Millisecond, Readonly: true})
+	suite.Assert().ErrorIs(err, ErrUnambiguousTimeout)
+
+	_, err = cluster.Query("UPSERT INTO default VALUES (\"key\", 1)", &QueryOptions{Timeout: 10 * time.Millisecond})
+	suite.Assert().ErrorIs(err, ErrAmbiguousTimeout)
+}
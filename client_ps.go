@@ -1,3 +1,5 @@
+//go:build !gocb_nocouchbase2
+
 package gocb
 
 import (
@@ -17,6 +19,15 @@ import (
 	"github.com/couchbase/gocbcoreps"
 )
 
+func (c *Cluster) newPsConnectionMgr(opts *newConnectionMgrOptions) (connectionManager, error) {
+	return &psConnectionMgr{
+		timeouts:     c.timeoutsConfig,
+		tracer:       opts.tracer,
+		meter:        opts.meter,
+		defaultRetry: c.retryStrategyWrapper.wrapped,
+	}, nil
+}
+
 type psConnectionMgr struct {
 	host   string
 	lock   sync.Mutex
@@ -172,19 +183,6 @@ func (c *psConnectionMgr) getQueryIndexProvider() (queryIndexProvider, error) {
 	}, nil
 }
 
-func (c *psConnectionMgr) getSearchIndexProvider() (searchIndexProvider, error) {
-	if err := c.canPerformOp(); err != nil {
-		return nil, err
-	}
-
-	provider := c.agent.SearchAdminV1()
-	return &searchIndexProviderPs{
-		provider: provider,
-
-		managerProvider: newPsOpManagerProvider(c.defaultRetry, c.tracer, c.timeouts.QueryTimeout, c.meter, serviceValueManagement),
-	}, nil
-}
-
 func (c *psConnectionMgr) getSearchCapabilitiesProvider() (searchCapabilityVerifier, error) {
 	return nil, ErrFeatureNotAvailable
 }
@@ -222,18 +220,6 @@ func (c *psConnectionMgr) getAnalyticsIndexProvider() (analyticsIndexProvider, e
 	return nil, ErrFeatureNotAvailable
 }
 
-func (c *psConnectionMgr) getSearchProvider() (searchProvider, error) {
-	if err := c.canPerformOp(); err != nil {
-		return nil, err
-	}
-
-	return &searchProviderPs{
-		provider: c.agent.SearchV1(),
-
-		managerProvider: newPsOpManagerProvider(c.defaultRetry, c.tracer, c.timeouts.QueryTimeout, c.meter, serviceValueSearch),
-	}, nil
-}
-
 func (c *psConnectionMgr) getHTTPProvider(bucketName string) (httpProvider, error) {
 	return nil, ErrFeatureNotAvailable
 }
@@ -261,6 +247,26 @@ func (c *psConnectionMgr) getUserManagerProvider() (userManagerProvider, error)
 	return nil, ErrFeatureNotAvailable
 }
 
+func (c *psConnectionMgr) getSecurityManagementProvider() (securityManagementProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *psConnectionMgr) getLogCollectionProvider() (logCollectionProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *psConnectionMgr) getXDCRManagementProvider() (xdcrManagementProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *psConnectionMgr) getClusterSettingsProvider() (clusterSettingsProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (c *psConnectionMgr) getNodeManagementProvider() (nodeManagementProvider, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
 func (c *psConnectionMgr) getInternalProvider() (internalProvider, error) {
 	return nil, ErrFeatureNotAvailable
 }
@@ -0,0 +1,11 @@
+//go:build gocb_nocouchbase2
+
+package gocb
+
+// newPsConnectionMgr is used in builds with the gocb_nocouchbase2 build tag, which exclude support for the
+// couchbase2 protocol, and its gRPC dependencies, from the binary. Every couchbase2 provider, including those for
+// search, query, transactions and management, lives in a _ps.go file and so is excluded along with the connection
+// manager.
+func (c *Cluster) newPsConnectionMgr(opts *newConnectionMgrOptions) (connectionManager, error) {
+	return nil, wrapError(ErrFeatureNotAvailable, "couchbase2 scheme is not supported by this build, as it was built with the gocb_nocouchbase2 tag")
+}
@@ -0,0 +1,28 @@
+//go:build !gocb_nocouchbase2 && !gocb_nosearch
+
+package gocb
+
+func (c *psConnectionMgr) getSearchIndexProvider() (searchIndexProvider, error) {
+	if err := c.canPerformOp(); err != nil {
+		return nil, err
+	}
+
+	provider := c.agent.SearchAdminV1()
+	return &searchIndexProviderPs{
+		provider: provider,
+
+		managerProvider: newPsOpManagerProvider(c.defaultRetry, c.tracer, c.timeouts.QueryTimeout, c.meter, serviceValueManagement),
+	}, nil
+}
+
+func (c *psConnectionMgr) getSearchProvider() (searchProvider, error) {
+	if err := c.canPerformOp(); err != nil {
+		return nil, err
+	}
+
+	return &searchProviderPs{
+		provider: c.agent.SearchV1(),
+
+		managerProvider: newPsOpManagerProvider(c.defaultRetry, c.tracer, c.timeouts.QueryTimeout, c.meter, serviceValueSearch),
+	}, nil
+}
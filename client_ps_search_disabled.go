@@ -0,0 +1,14 @@
+//go:build !gocb_nocouchbase2 && gocb_nosearch
+
+package gocb
+
+// The couchbase2 search providers are used in builds with the gocb_nosearch build tag, which exclude support for the
+// search service from the binary.
+
+func (c *psConnectionMgr) getSearchProvider() (searchProvider, error) {
+	return nil, wrapError(ErrFeatureNotAvailable, "search is not supported by this build, as it was built with the gocb_nosearch tag")
+}
+
+func (c *psConnectionMgr) getSearchIndexProvider() (searchIndexProvider, error) {
+	return nil, wrapError(ErrFeatureNotAvailable, "search is not supported by this build, as it was built with the gocb_nosearch tag")
+}
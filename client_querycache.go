@@ -0,0 +1,402 @@
+package gocb
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+const (
+	defaultQueryCacheMaxEntries = 1000
+	defaultQueryCacheMaxBytes   = 64 * 1024 * 1024
+)
+
+// QueryCacheConfig specifies options for the client side query result cache. The cache is only used for queries
+// which set QueryOptions.Readonly, and never for queries using request_plus scan consistency, pinned to an endpoint
+// or run as transactions. Results are keyed by statement, scope, user and every option which is sent to the query
+// service. A query using ConsistentWith is only served from the cache if the cached result was produced with mutation
+// tokens at least as new as those supplied.
+// UNCOMMITTED: This API may change in the future.
+type QueryCacheConfig struct {
+	// TTL is how long a result remains in the cache, the cache is disabled if zero.
+	TTL time.Duration
+
+	// MaxEntries is the maximum number of results held in the cache, defaulting to 1000. The least recently used
+	// result is evicted once this limit is reached.
+	MaxEntries int
+
+	// MaxBytes is the maximum total size of the rows and metadata held in the cache, defaulting to 64MiB. The least
+	// recently used results are evicted once this limit is reached, results larger than the limit are streamed to
+	// the caller without being cached.
+	MaxBytes int
+}
+
+type queryCacheTokenKey struct {
+	bucketName string
+	vbID       uint16
+}
+
+type queryCacheEntry struct {
+	key             string
+	rows            [][]byte
+	metaData        []byte
+	preparedName    string
+	preparedNameErr error
+	endpoint        string
+	tokens          map[queryCacheTokenKey]uint64
+	expiresAt       time.Time
+	size            int
+}
+
+// satisfies returns whether the entry was produced at least as recently as all of the tokens in state.
+func (e *queryCacheEntry) satisfies(state *MutationState) bool {
+	if state == nil {
+		return true
+	}
+
+	for _, token := range state.tokens {
+		seqNo, ok := e.tokens[queryCacheTokenKey{token.bucketName, uint16(token.token.VbID)}]
+		if !ok || seqNo < uint64(token.token.SeqNo) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type queryCache struct {
+	lock       sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	maxBytes   int
+	size       int
+	entries    map[string]*list.Element
+	lru        *list.List
+	now        func() time.Time
+}
+
+func newQueryCache(config QueryCacheConfig, timeSource func() time.Time) *queryCache {
+	maxEntries := config.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultQueryCacheMaxEntries
+	}
+	maxBytes := config.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultQueryCacheMaxBytes
+	}
+	if timeSource == nil {
+		timeSource = time.Now
+	}
+
+	return &queryCache{
+		ttl:        config.TTL,
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		now:        timeSource,
+	}
+}
+
+func (c *queryCache) get(key string, consistentWith *MutationState) *queryCacheEntry {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+
+	entry := elem.Value.(*queryCacheEntry)
+	if c.now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		return nil
+	}
+
+	if !entry.satisfies(consistentWith) {
+		return nil
+	}
+
+	c.lru.MoveToFront(elem)
+	return entry
+}
+
+func (c *queryCache) put(entry *queryCacheEntry) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry.expiresAt = c.now().Add(c.ttl)
+
+	if elem, ok := c.entries[entry.key]; ok {
+		c.removeElement(elem)
+	}
+
+	if entry.size > c.maxBytes {
+		return
+	}
+
+	c.entries[entry.key] = c.lru.PushFront(entry)
+	c.size += entry.size
+
+	for c.lru.Len() > c.maxEntries || c.size > c.maxBytes {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+func (c *queryCache) removeElement(elem *list.Element) {
+	c.lru.Remove(elem)
+	entry := elem.Value.(*queryCacheEntry)
+	c.size -= entry.size
+	delete(c.entries, entry.key)
+}
+
+type jsonQueryCacheKey struct {
+	Statement string                 `json:"statement"`
+	Bucket    string                 `json:"bucket,omitempty"`
+	Scope     string                 `json:"scope,omitempty"`
+	User      string                 `json:"user,omitempty"`
+	Adhoc     bool                   `json:"adhoc,omitempty"`
+	Options   map[string]interface{} `json:"options"`
+}
+
+// queryCacheKey returns the key to cache the query under, or false if the query cannot be cached.
+func queryCacheKey(statement string, s *Scope, opts *QueryOptions) (string, bool) {
+	if !opts.Readonly || opts.ScanConsistency == QueryScanConsistencyRequestPlus || opts.AsTransaction != nil ||
+		opts.Endpoint != "" || opts.Internal.Endpoint != "" {
+		return "", false
+	}
+
+	// The options sent to the server determine the result, other than the client context id which is unique to each
+	// request and the scan vectors which are instead checked against the tokens of the cached entry.
+	queryOpts, err := opts.toMap()
+	if err != nil {
+		return "", false
+	}
+	delete(queryOpts, "client_context_id")
+	if opts.ConsistentWith != nil {
+		delete(queryOpts, "scan_consistency")
+		delete(queryOpts, "scan_vectors")
+	}
+
+	key := jsonQueryCacheKey{
+		Statement: statement,
+		User:      opts.Internal.User,
+		Adhoc:     opts.Adhoc,
+		Options:   queryOpts,
+	}
+	if s != nil {
+		key.Bucket = s.BucketName()
+		key.Scope = s.Name()
+	}
+
+	keyBytes, err := json.Marshal(key)
+	if err != nil {
+		logDebugf("Failed to build query cache key, bypassing cache: %s", err)
+		return "", false
+	}
+
+	return string(keyBytes), true
+}
+
+// queryCacheConnectionMgr wraps a connectionManager, serving repeated read only queries from a shared cache.
+type queryCacheConnectionMgr struct {
+	connectionManager
+	cache *queryCache
+}
+
+func newQueryCacheConnectionMgr(mgr connectionManager, config QueryCacheConfig,
+	timeSource func() time.Time) *queryCacheConnectionMgr {
+	return &queryCacheConnectionMgr{
+		connectionManager: mgr,
+		cache:             newQueryCache(config, timeSource),
+	}
+}
+
+func (c *queryCacheConnectionMgr) getQueryProvider() (queryProvider, error) {
+	provider, err := c.connectionManager.getQueryProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &cachingQueryProvider{
+		provider: provider,
+		cache:    c.cache,
+	}, nil
+}
+
+type cachingQueryProvider struct {
+	provider queryProvider
+	cache    *queryCache
+}
+
+func (p *cachingQueryProvider) Query(statement string, s *Scope, opts *QueryOptions) (*QueryResult, error) {
+	key, ok := queryCacheKey(statement, s, opts)
+	if !ok {
+		return p.provider.Query(statement, s, opts)
+	}
+
+	if entry := p.cache.get(key, opts.ConsistentWith); entry != nil {
+		// A cached result is available immediately, but a request made with a context which has already ended must
+		// still fail as it would have done had it been sent to the server.
+		if opts.Context != nil {
+			if err := opts.Context.Err(); err != nil {
+				return nil, resultsContextError(err)
+			}
+		}
+
+		return p.newCachedResult(entry, opts), nil
+	}
+
+	res, err := p.provider.Query(statement, s, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, overflow, err := p.populateEntry(key, res, opts.ConsistentWith)
+	if err != nil {
+		return nil, err
+	}
+	if overflow != nil {
+		result := newQueryResult(overflow)
+		result.serializer = opts.Serializer
+		return result, nil
+	}
+
+	p.cache.put(entry)
+
+	return p.newCachedResult(entry, opts), nil
+}
+
+// populateEntry reads the entire result into a new cache entry, closing the result. If the result grows larger than
+// the cache can hold then buffering stops and a reader is instead returned which replays the rows read so far before
+// streaming the remainder of the result, which is not cached.
+func (p *cachingQueryProvider) populateEntry(key string, res *QueryResult,
+	consistentWith *MutationState) (*queryCacheEntry, queryRowReader, error) {
+	entry := &queryCacheEntry{
+		key:      key,
+		endpoint: res.endpoint,
+		tokens:   make(map[queryCacheTokenKey]uint64),
+	}
+
+	entry.size = len(key)
+	for row := res.NextBytes(); row != nil; row = res.NextBytes() {
+		entry.rows = append(entry.rows, append([]byte(nil), row...))
+		entry.size += len(row)
+		if entry.size > p.cache.maxBytes {
+			return nil, &queryCacheOverflowRowReader{
+				rows:   entry.rows,
+				result: res,
+			}, nil
+		}
+	}
+
+	if err := res.Close(); err != nil {
+		return nil, nil, err
+	}
+	if err := res.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	metaData, err := res.reader.MetaData()
+	if err != nil {
+		return nil, nil, err
+	}
+	entry.metaData = metaData
+	entry.size += len(metaData)
+
+	entry.preparedName, entry.preparedNameErr = res.reader.PreparedName()
+
+	if consistentWith != nil {
+		for _, token := range consistentWith.tokens {
+			tokenKey := queryCacheTokenKey{token.bucketName, uint16(token.token.VbID)}
+			if seqNo := uint64(token.token.SeqNo); seqNo > entry.tokens[tokenKey] {
+				entry.tokens[tokenKey] = seqNo
+			}
+		}
+	}
+
+	return entry, nil, nil
+}
+
+func (p *cachingQueryProvider) newCachedResult(entry *queryCacheEntry, opts *QueryOptions) *QueryResult {
+	result := newQueryResult(&queryCacheRowReader{entry: entry})
+	result.serializer = opts.Serializer
+	return result
+}
+
+// queryCacheRowReader replays the rows of a cache entry. The entry is shared by every caller, so rows and metadata
+// are copied as they are handed out.
+type queryCacheRowReader struct {
+	entry *queryCacheEntry
+	idx   int
+}
+
+func (r *queryCacheRowReader) NextRow() []byte {
+	if r.idx >= len(r.entry.rows) {
+		return nil
+	}
+
+	row := r.entry.rows[r.idx]
+	r.idx++
+	return append([]byte(nil), row...)
+}
+
+func (r *queryCacheRowReader) Err() error {
+	return nil
+}
+
+func (r *queryCacheRowReader) MetaData() ([]byte, error) {
+	return append([]byte(nil), r.entry.metaData...), nil
+}
+
+func (r *queryCacheRowReader) Close() error {
+	return nil
+}
+
+func (r *queryCacheRowReader) PreparedName() (string, error) {
+	return r.entry.preparedName, r.entry.preparedNameErr
+}
+
+func (r *queryCacheRowReader) Endpoint() string {
+	return r.entry.endpoint
+}
+
+// queryCacheOverflowRowReader replays the rows which were buffered from a result before it was found to be too large
+// to cache, and then streams the remainder of the result.
+type queryCacheOverflowRowReader struct {
+	rows   [][]byte
+	idx    int
+	result *QueryResult
+}
+
+func (r *queryCacheOverflowRowReader) NextRow() []byte {
+	if r.idx < len(r.rows) {
+		row := r.rows[r.idx]
+		r.rows[r.idx] = nil
+		r.idx++
+		return row
+	}
+
+	return r.result.NextBytes()
+}
+
+func (r *queryCacheOverflowRowReader) Err() error {
+	return r.result.Err()
+}
+
+func (r *queryCacheOverflowRowReader) MetaData() ([]byte, error) {
+	return r.result.reader.MetaData()
+}
+
+func (r *queryCacheOverflowRowReader) Close() error {
+	return r.result.Close()
+}
+
+func (r *queryCacheOverflowRowReader) PreparedName() (string, error) {
+	return r.result.reader.PreparedName()
+}
+
+func (r *queryCacheOverflowRowReader) Endpoint() string {
+	return r.result.endpoint
+}
@@ -0,0 +1,306 @@
+package gocb
+
+import (
+	"context"
+	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) queryCacheResult(name string) *QueryResult {
+	return newQueryResult(&mockQueryIndexRowReader{
+		Dataset: []map[string]interface{}{{"name": name}},
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  []byte(`{"requestID":"` + name + `","status":"success"}`),
+			Suite: suite,
+		},
+	})
+}
+
+func (suite *UnitTestSuite) queryCacheCluster(provider queryProvider, config QueryCacheConfig) *Cluster {
+	cli := new(mockConnectionManager)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+	cli.On("getQueryProvider").Return(provider, nil)
+
+	return suite.newCluster(newQueryCacheConnectionMgr(cli, config, nil))
+}
+
+func (suite *UnitTestSuite) queryCacheRow(res *QueryResult) string {
+	var row map[string]interface{}
+	suite.Require().Nil(res.One(&row))
+	return row["name"].(string)
+}
+
+func (suite *UnitTestSuite) TestQueryCacheServesRepeatedQueries() {
+	statement := "SELECT name FROM default WHERE type = $type"
+	provider := new(mockQueryProvider)
+	provider.
+		On("Query", statement, (*Scope)(nil), mock.AnythingOfType("*gocb.QueryOptions")).
+		Return(suite.queryCacheResult("first"), nil).
+		Once()
+	provider.
+		On("Query", statement, (*Scope)(nil), mock.AnythingOfType("*gocb.QueryOptions")).
+		Return(suite.queryCacheResult("second"), nil).
+		Once()
+
+	cluster := suite.queryCacheCluster(provider, QueryCacheConfig{TTL: time.Minute})
+
+	opts := &QueryOptions{Readonly: true, NamedParameters: map[string]interface{}{"type": "brewery"}}
+	for i := 0; i < 3; i++ {
+		res, err := cluster.Query(statement, opts)
+		suite.Require().Nil(err, err)
+		suite.Assert().Equal("first", suite.queryCacheRow(res))
+
+		meta, err := res.MetaData()
+		suite.Require().Nil(err, err)
+		suite.Assert().Equal("first", meta.RequestID)
+	}
+
+	// Different parameters must not share a cache entry.
+	res, err := cluster.Query(statement, &QueryOptions{Readonly: true, NamedParameters: map[string]interface{}{"type": "beer"}})
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("second", suite.queryCacheRow(res))
+
+	provider.AssertExpectations(suite.T())
+}
+
+func (suite *UnitTestSuite) TestQueryCacheBypassed() {
+	statement := "SELECT name FROM default"
+	provider := new(mockQueryProvider)
+	provider.
+		On("Query", statement, (*Scope)(nil), mock.AnythingOfType("*gocb.QueryOptions")).
+		Return(suite.queryCacheResult("first"), nil).
+		Once()
+	provider.
+		On("Query", statement, (*Scope)(nil), mock.AnythingOfType("*gocb.QueryOptions")).
+		Return(suite.queryCacheResult("second"), nil).
+		Once()
+
+	cluster := suite.queryCacheCluster(provider, QueryCacheConfig{TTL: time.Minute})
+
+	res, err := cluster.Query(statement, nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("first", suite.queryCacheRow(res))
+
+	res, err = cluster.Query(statement, &QueryOptions{Readonly: true, ScanConsistency: QueryScanConsistencyRequestPlus})
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("second", suite.queryCacheRow(res))
+
+	provider.AssertExpectations(suite.T())
+}
+
+func (suite *UnitTestSuite) TestQueryCacheInvalidatedByNewerMutationToken() {
+	statement := "SELECT name FROM default"
+	provider := new(mockQueryProvider)
+	provider.
+		On("Query", statement, (*Scope)(nil), mock.AnythingOfType("*gocb.QueryOptions")).
+		Return(suite.queryCacheResult("first"), nil).
+		Once()
+	provider.
+		On("Query", statement, (*Scope)(nil), mock.AnythingOfType("*gocb.QueryOptions")).
+		Return(suite.queryCacheResult("second"), nil).
+		Once()
+
+	cluster := suite.queryCacheCluster(provider, QueryCacheConfig{TTL: time.Minute})
+
+	token := func(seqNo uint64) MutationToken {
+		return MutationToken{
+			token:      gocbcore.MutationToken{VbID: 12, VbUUID: 1234, SeqNo: gocbcore.SeqNo(seqNo)},
+			bucketName: "default",
+		}
+	}
+
+	res, err := cluster.Query(statement, &QueryOptions{Readonly: true, ConsistentWith: NewMutationState(token(10))})
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("first", suite.queryCacheRow(res))
+
+	// Tokens no newer than those used for the cached result are satisfied by the cache, as are queries
+	// which do not supply tokens at all.
+	res, err = cluster.Query(statement, &QueryOptions{Readonly: true, ConsistentWith: NewMutationState(token(9))})
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("first", suite.queryCacheRow(res))
+
+	res, err = cluster.Query(statement, &QueryOptions{Readonly: true})
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("first", suite.queryCacheRow(res))
+
+	res, err = cluster.Query(statement, &QueryOptions{Readonly: true, ConsistentWith: NewMutationState(token(11))})
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("second", suite.queryCacheRow(res))
+
+	res, err = cluster.Query(statement, &QueryOptions{Readonly: true, ConsistentWith: NewMutationState(token(11))})
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("second", suite.queryCacheRow(res))
+
+	provider.AssertExpectations(suite.T())
+}
+
+func (suite *UnitTestSuite) TestQueryCacheEviction() {
+	cache := newQueryCache(QueryCacheConfig{TTL: time.Minute, MaxEntries: 2}, nil)
+
+	cache.put(&queryCacheEntry{key: "a"})
+	cache.put(&queryCacheEntry{key: "b"})
+	suite.Require().NotNil(cache.get("a", nil))
+
+	// b is now the least recently used entry.
+	cache.put(&queryCacheEntry{key: "c"})
+	suite.Assert().NotNil(cache.get("a", nil))
+	suite.Assert().Nil(cache.get("b", nil))
+	suite.Assert().NotNil(cache.get("c", nil))
+
+	cache.ttl = -time.Second
+	cache.put(&queryCacheEntry{key: "d"})
+	suite.Assert().Nil(cache.get("d", nil))
+}
+
+func (suite *UnitTestSuite) TestQueryCacheExpiryTimeSource() {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newQueryCache(QueryCacheConfig{TTL: time.Minute}, func() time.Time {
+		return now
+	})
+
+	cache.put(&queryCacheEntry{key: "a"})
+
+	now = now.Add(time.Minute)
+	suite.Assert().NotNil(cache.get("a", nil))
+
+	now = now.Add(time.Nanosecond)
+	suite.Assert().Nil(cache.get("a", nil))
+}
+
+func (suite *UnitTestSuite) TestQueryCacheKeyIncludesOptions() {
+	base := func() *QueryOptions {
+		return &QueryOptions{Readonly: true, ClientContextID: "a"}
+	}
+
+	baseKey, ok := queryCacheKey("SELECT 1", nil, base())
+	suite.Require().True(ok)
+
+	sameKey, ok := queryCacheKey("SELECT 1", nil, &QueryOptions{Readonly: true, ClientContextID: "b"})
+	suite.Require().True(ok)
+	suite.Assert().Equal(baseKey, sameKey)
+
+	user := base()
+	user.Internal.User = "someone"
+	profile := base()
+	profile.Profile = QueryProfileModeTimings
+	metrics := base()
+	metrics.Metrics = true
+	scanWait := base()
+	scanWait.ScanWait = time.Second
+	adhoc := base()
+	adhoc.Adhoc = true
+
+	for _, opts := range []*QueryOptions{user, profile, metrics, scanWait, adhoc} {
+		key, ok := queryCacheKey("SELECT 1", nil, opts)
+		suite.Require().True(ok)
+		suite.Assert().NotEqual(baseKey, key)
+	}
+}
+
+func (suite *UnitTestSuite) TestQueryCacheEvictionBySize() {
+	cache := newQueryCache(QueryCacheConfig{TTL: time.Minute, MaxBytes: 10}, nil)
+
+	cache.put(&queryCacheEntry{key: "a", size: 4})
+	cache.put(&queryCacheEntry{key: "b", size: 4})
+	suite.Require().NotNil(cache.get("a", nil))
+
+	// b is now the least recently used entry.
+	cache.put(&queryCacheEntry{key: "c", size: 4})
+	suite.Assert().NotNil(cache.get("a", nil))
+	suite.Assert().Nil(cache.get("b", nil))
+	suite.Assert().NotNil(cache.get("c", nil))
+	suite.Assert().Equal(8, cache.size)
+
+	// Entries larger than the cache are never held.
+	cache.put(&queryCacheEntry{key: "d", size: 11})
+	suite.Assert().Nil(cache.get("d", nil))
+	suite.Assert().Equal(8, cache.size)
+}
+
+func (suite *UnitTestSuite) TestQueryCacheHitsAreCopiedAndRespectContext() {
+	statement := "SELECT name FROM default"
+	provider := new(mockQueryProvider)
+	provider.
+		On("Query", statement, (*Scope)(nil), mock.AnythingOfType("*gocb.QueryOptions")).
+		Return(suite.queryCacheResult("first"), nil).
+		Once()
+
+	cluster := suite.queryCacheCluster(provider, QueryCacheConfig{TTL: time.Minute})
+
+	res, err := cluster.Query(statement, &QueryOptions{Readonly: true})
+	suite.Require().Nil(err, err)
+	row := res.NextBytes()
+	suite.Require().NotNil(row)
+	// Modifying the row handed to one caller must not affect the rows handed to others.
+	for i := range row {
+		row[i] = 'x'
+	}
+	suite.Require().Nil(res.Close())
+
+	res, err = cluster.Query(statement, &QueryOptions{Readonly: true})
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("first", suite.queryCacheRow(res))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = cluster.Query(statement, &QueryOptions{Readonly: true, Context: ctx})
+	suite.Assert().ErrorIs(err, ErrRequestCanceled)
+
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	_, err = cluster.Query(statement, &QueryOptions{Readonly: true, Context: ctx})
+	suite.Assert().ErrorIs(err, ErrTimeout)
+
+	provider.AssertExpectations(suite.T())
+}
+
+func (suite *UnitTestSuite) TestQueryCacheStreamsResultsLargerThanCache() {
+	statement := "SELECT name FROM default"
+	dataset := []map[string]interface{}{{"name": "first"}, {"name": "second"}, {"name": "third"}}
+	newResult := func() *QueryResult {
+		return newQueryResult(&mockQueryIndexRowReader{
+			Dataset: dataset,
+			mockQueryRowReaderBase: mockQueryRowReaderBase{
+				Meta:  []byte(`{"requestID":"large","status":"success"}`),
+				Suite: suite,
+			},
+		})
+	}
+
+	provider := new(mockQueryProvider)
+	provider.
+		On("Query", statement, (*Scope)(nil), mock.AnythingOfType("*gocb.QueryOptions")).
+		Return(newResult(), nil).
+		Once()
+	provider.
+		On("Query", statement, (*Scope)(nil), mock.AnythingOfType("*gocb.QueryOptions")).
+		Return(newResult(), nil).
+		Once()
+
+	// The key and first row fit within the cache, the second row does not.
+	cluster := suite.queryCacheCluster(provider, QueryCacheConfig{TTL: time.Minute, MaxBytes: 120})
+
+	for i := 0; i < 2; i++ {
+		res, err := cluster.Query(statement, &QueryOptions{Readonly: true})
+		suite.Require().Nil(err, err)
+
+		var names []string
+		for res.Next() {
+			var row map[string]interface{}
+			suite.Require().Nil(res.Row(&row))
+			names = append(names, row["name"].(string))
+		}
+		suite.Require().Nil(res.Err())
+		suite.Assert().Equal([]string{"first", "second", "third"}, names)
+
+		meta, err := res.MetaData()
+		suite.Require().Nil(err, err)
+		suite.Assert().Equal("large", meta.RequestID)
+	}
+
+	provider.AssertExpectations(suite.T())
+}
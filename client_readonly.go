@@ -0,0 +1,544 @@
+package gocb
+
+import "encoding/json"
+
+// readOnlyConnectionMgr wraps a connectionManager, refusing any management or DDL operation which would modify the
+// cluster. KV mutations are refused by the Collection itself, see Collection.checkMutationAllowed.
+type readOnlyConnectionMgr struct {
+	connectionManager
+}
+
+func newReadOnlyConnectionMgr(mgr connectionManager) *readOnlyConnectionMgr {
+	return &readOnlyConnectionMgr{
+		connectionManager: mgr,
+	}
+}
+
+func makeReadOnlyError() error {
+	return makeGenericError(ErrClusterReadOnly, nil)
+}
+
+func (c *readOnlyConnectionMgr) getQueryProvider() (queryProvider, error) {
+	provider, err := c.connectionManager.getQueryProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readOnlyQueryProvider{provider}, nil
+}
+
+func (c *readOnlyConnectionMgr) getQueryIndexProvider() (queryIndexProvider, error) {
+	provider, err := c.connectionManager.getQueryIndexProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readOnlyQueryIndexProvider{provider}, nil
+}
+
+func (c *readOnlyConnectionMgr) getAnalyticsProvider() (analyticsProvider, error) {
+	provider, err := c.connectionManager.getAnalyticsProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readOnlyAnalyticsProvider{provider}, nil
+}
+
+func (c *readOnlyConnectionMgr) getAnalyticsIndexProvider() (analyticsIndexProvider, error) {
+	provider, err := c.connectionManager.getAnalyticsIndexProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readOnlyAnalyticsIndexProvider{provider}, nil
+}
+
+func (c *readOnlyConnectionMgr) getViewIndexProvider(bucketName string) (viewIndexProvider, error) {
+	provider, err := c.connectionManager.getViewIndexProvider(bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &readOnlyViewIndexProvider{provider}, nil
+}
+
+func (c *readOnlyConnectionMgr) getCollectionsManagementProvider(bucketName string) (collectionsManagementProvider, error) {
+	provider, err := c.connectionManager.getCollectionsManagementProvider(bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &readOnlyCollectionsManagementProvider{provider}, nil
+}
+
+func (c *readOnlyConnectionMgr) getBucketManagementProvider() (bucketManagementProvider, error) {
+	provider, err := c.connectionManager.getBucketManagementProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readOnlyBucketManagementProvider{provider}, nil
+}
+
+func (c *readOnlyConnectionMgr) getSearchIndexProvider() (searchIndexProvider, error) {
+	provider, err := c.connectionManager.getSearchIndexProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readOnlySearchIndexProvider{provider}, nil
+}
+
+func (c *readOnlyConnectionMgr) getEventingManagementProvider() (eventingManagementProvider, error) {
+	provider, err := c.connectionManager.getEventingManagementProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readOnlyEventingManagementProvider{provider}, nil
+}
+
+func (c *readOnlyConnectionMgr) getUserManagerProvider() (userManagerProvider, error) {
+	provider, err := c.connectionManager.getUserManagerProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readOnlyUserManagerProvider{provider}, nil
+}
+
+func (c *readOnlyConnectionMgr) getSecurityManagementProvider() (securityManagementProvider, error) {
+	provider, err := c.connectionManager.getSecurityManagementProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readOnlySecurityManagementProvider{provider}, nil
+}
+
+func (c *readOnlyConnectionMgr) getLogCollectionProvider() (logCollectionProvider, error) {
+	provider, err := c.connectionManager.getLogCollectionProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readOnlyLogCollectionProvider{provider}, nil
+}
+
+func (c *readOnlyConnectionMgr) getXDCRManagementProvider() (xdcrManagementProvider, error) {
+	provider, err := c.connectionManager.getXDCRManagementProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readOnlyXDCRManagementProvider{provider}, nil
+}
+
+func (c *readOnlyConnectionMgr) getClusterSettingsProvider() (clusterSettingsProvider, error) {
+	provider, err := c.connectionManager.getClusterSettingsProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readOnlyClusterSettingsProvider{provider}, nil
+}
+
+func (c *readOnlyConnectionMgr) getNodeManagementProvider() (nodeManagementProvider, error) {
+	provider, err := c.connectionManager.getNodeManagementProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &readOnlyNodeManagementProvider{provider}, nil
+}
+
+// readOnlyQueryProvider sends all queries with the readonly option so that the query service refuses any statement
+// which would modify data.
+type readOnlyQueryProvider struct {
+	queryProvider
+}
+
+func (p *readOnlyQueryProvider) Query(statement string, s *Scope, opts *QueryOptions) (*QueryResult, error) {
+	readOnlyOpts := *opts
+	readOnlyOpts.Readonly = true
+	return p.queryProvider.Query(statement, s, &readOnlyOpts)
+}
+
+// readOnlyAnalyticsProvider sends all analytics queries with the readonly option so that the analytics service
+// refuses any statement which would modify data.
+type readOnlyAnalyticsProvider struct {
+	analyticsProvider
+}
+
+func (p *readOnlyAnalyticsProvider) AnalyticsQuery(statement string, scope *Scope, opts *AnalyticsOptions) (*AnalyticsResult, error) {
+	readOnlyOpts := *opts
+	readOnlyOpts.Readonly = true
+	return p.analyticsProvider.AnalyticsQuery(statement, scope, &readOnlyOpts)
+}
+
+type readOnlyQueryIndexProvider struct {
+	queryIndexProvider
+}
+
+func (p *readOnlyQueryIndexProvider) CreatePrimaryIndex(*Collection, string, *CreatePrimaryQueryIndexOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyQueryIndexProvider) CreateIndex(*Collection, string, string, []string, *CreateQueryIndexOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyQueryIndexProvider) DropPrimaryIndex(*Collection, string, *DropPrimaryQueryIndexOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyQueryIndexProvider) DropIndex(*Collection, string, string, *DropQueryIndexOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyQueryIndexProvider) BuildDeferredIndexes(*Collection, string, *BuildDeferredQueryIndexOptions) ([]string, error) {
+	return nil, makeReadOnlyError()
+}
+
+type readOnlyAnalyticsIndexProvider struct {
+	analyticsIndexProvider
+}
+
+func (p *readOnlyAnalyticsIndexProvider) CreateDataverse(string, *CreateAnalyticsDataverseOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyAnalyticsIndexProvider) DropDataverse(string, *DropAnalyticsDataverseOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyAnalyticsIndexProvider) CreateDataset(string, string, *CreateAnalyticsDatasetOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyAnalyticsIndexProvider) DropDataset(string, *DropAnalyticsDatasetOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyAnalyticsIndexProvider) CreateIndex(string, string, map[string]string, *CreateAnalyticsIndexOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyAnalyticsIndexProvider) CreatePrimaryIndex(string, *CreateAnalyticsPrimaryIndexOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyAnalyticsIndexProvider) DropIndex(string, string, *DropAnalyticsIndexOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyAnalyticsIndexProvider) CreateView(string, string, *CreateAnalyticsViewOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyAnalyticsIndexProvider) DropView(string, *DropAnalyticsViewOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyAnalyticsIndexProvider) CreateSynonym(string, string, *CreateAnalyticsSynonymOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyAnalyticsIndexProvider) DropSynonym(string, *DropAnalyticsSynonymOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyAnalyticsIndexProvider) ConnectLink(*ConnectAnalyticsLinkOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyAnalyticsIndexProvider) DisconnectLink(*DisconnectAnalyticsLinkOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyAnalyticsIndexProvider) CreateLink(AnalyticsLink, *CreateAnalyticsLinkOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyAnalyticsIndexProvider) ReplaceLink(AnalyticsLink, *ReplaceAnalyticsLinkOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyAnalyticsIndexProvider) DropLink(string, string, *DropAnalyticsLinkOptions) error {
+	return makeReadOnlyError()
+}
+
+type readOnlyViewIndexProvider struct {
+	viewIndexProvider
+}
+
+func (p *readOnlyViewIndexProvider) UpsertDesignDocument(DesignDocument, DesignDocumentNamespace, *UpsertDesignDocumentOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyViewIndexProvider) DropDesignDocument(string, DesignDocumentNamespace, *DropDesignDocumentOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyViewIndexProvider) PublishDesignDocument(string, *PublishDesignDocumentOptions) error {
+	return makeReadOnlyError()
+}
+
+type readOnlyCollectionsManagementProvider struct {
+	collectionsManagementProvider
+}
+
+func (p *readOnlyCollectionsManagementProvider) CreateCollection(string, string, *CreateCollectionSettings, *CreateCollectionOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyCollectionsManagementProvider) UpdateCollection(string, string, UpdateCollectionSettings, *UpdateCollectionOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyCollectionsManagementProvider) DropCollection(string, string, *DropCollectionOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyCollectionsManagementProvider) CreateScope(string, *CreateScopeOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyCollectionsManagementProvider) UpdateScopeLimits(string, ScopeLimits, *UpdateScopeLimitsOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyCollectionsManagementProvider) DropScope(string, *DropScopeOptions) error {
+	return makeReadOnlyError()
+}
+
+type readOnlyBucketManagementProvider struct {
+	bucketManagementProvider
+}
+
+func (p *readOnlyBucketManagementProvider) CreateBucket(CreateBucketSettings, *CreateBucketOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyBucketManagementProvider) UpdateBucket(BucketSettings, *UpdateBucketOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyBucketManagementProvider) DropBucket(string, *DropBucketOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyBucketManagementProvider) FlushBucket(string, *FlushBucketOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyBucketManagementProvider) CompactBucket(string, *CompactBucketOptions) error {
+	return makeReadOnlyError()
+}
+
+type readOnlySearchIndexProvider struct {
+	searchIndexProvider
+}
+
+func (p *readOnlySearchIndexProvider) UpsertIndex(*Scope, SearchIndex, *UpsertSearchIndexOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlySearchIndexProvider) DropIndex(*Scope, string, *DropSearchIndexOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlySearchIndexProvider) PauseIngest(*Scope, string, *PauseIngestSearchIndexOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlySearchIndexProvider) ResumeIngest(*Scope, string, *ResumeIngestSearchIndexOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlySearchIndexProvider) AllowQuerying(*Scope, string, *AllowQueryingSearchIndexOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlySearchIndexProvider) DisallowQuerying(*Scope, string, *DisallowQueryingSearchIndexOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlySearchIndexProvider) FreezePlan(*Scope, string, *FreezePlanSearchIndexOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlySearchIndexProvider) UnfreezePlan(*Scope, string, *UnfreezePlanSearchIndexOptions) error {
+	return makeReadOnlyError()
+}
+
+type readOnlyEventingManagementProvider struct {
+	eventingManagementProvider
+}
+
+func (p *readOnlyEventingManagementProvider) UpsertFunction(*Scope, EventingFunction, *UpsertEventingFunctionOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyEventingManagementProvider) DropFunction(*Scope, string, *DropEventingFunctionOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyEventingManagementProvider) DeployFunction(*Scope, string, *DeployEventingFunctionOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyEventingManagementProvider) UndeployFunction(*Scope, string, *UndeployEventingFunctionOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyEventingManagementProvider) PauseFunction(*Scope, string, *PauseEventingFunctionOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyEventingManagementProvider) ResumeFunction(*Scope, string, *ResumeEventingFunctionOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyEventingManagementProvider) ImportFunctions(*Scope, []json.RawMessage, *ImportEventingFunctionsOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyEventingManagementProvider) UpdateFunctionSettings(*Scope, string, EventingFunctionSettings, *UpdateEventingFunctionSettingsOptions) error {
+	return makeReadOnlyError()
+}
+
+type readOnlyUserManagerProvider struct {
+	userManagerProvider
+}
+
+func (p *readOnlyUserManagerProvider) UpsertUser(User, *UpsertUserOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyUserManagerProvider) DropUser(string, *DropUserOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyUserManagerProvider) UpsertGroup(Group, *UpsertGroupOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyUserManagerProvider) DropGroup(string, *DropGroupOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyUserManagerProvider) ChangePassword(string, *ChangePasswordOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyUserManagerProvider) SetUserLocked(string, bool, *SetUserLockedOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyUserManagerProvider) UpsertLDAPSettings(LDAPSettings, *UpsertLDAPSettingsOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyUserManagerProvider) InvalidateLDAPCache(*InvalidateLDAPCacheOptions) error {
+	return makeReadOnlyError()
+}
+
+type readOnlySecurityManagementProvider struct {
+	securityManagementProvider
+}
+
+func (p *readOnlySecurityManagementProvider) UpdateClientCertAuthSettings(ClientCertAuthSettings, *UpdateClientCertAuthSettingsOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlySecurityManagementProvider) UpdateTLSSettings(TLSSettings, *UpdateTLSSettingsOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlySecurityManagementProvider) ReloadNodeCertificates(*ReloadNodeCertificatesOptions) error {
+	return makeReadOnlyError()
+}
+
+// readOnlyLogCollectionProvider refuses to start log collection, which writes the collected logs to each node and
+// may upload them, but still allows the status of a collection to be read.
+type readOnlyLogCollectionProvider struct {
+	logCollectionProvider
+}
+
+func (p *readOnlyLogCollectionProvider) StartLogCollection(*StartLogCollectionOptions) error {
+	return makeReadOnlyError()
+}
+
+type readOnlyXDCRManagementProvider struct {
+	xdcrManagementProvider
+}
+
+func (p *readOnlyXDCRManagementProvider) CreateRemoteCluster(XDCRRemoteCluster, *CreateXDCRRemoteClusterOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyXDCRManagementProvider) DeleteRemoteCluster(string, *DeleteXDCRRemoteClusterOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyXDCRManagementProvider) CreateReplication(XDCRReplication, *CreateXDCRReplicationOptions) (string, error) {
+	return "", makeReadOnlyError()
+}
+
+func (p *readOnlyXDCRManagementProvider) PauseReplication(string, *PauseXDCRReplicationOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyXDCRManagementProvider) ResumeReplication(string, *ResumeXDCRReplicationOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyXDCRManagementProvider) DeleteReplication(string, *DeleteXDCRReplicationOptions) error {
+	return makeReadOnlyError()
+}
+
+type readOnlyClusterSettingsProvider struct {
+	clusterSettingsProvider
+}
+
+func (p *readOnlyClusterSettingsProvider) UpdateAutoFailoverSettings(AutoFailoverSettings, *UpdateAutoFailoverSettingsOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyClusterSettingsProvider) UpdateEmailAlertSettings(EmailAlertSettings, *UpdateEmailAlertSettingsOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyClusterSettingsProvider) UpdateCompactionSettings(CompactionSettings, *UpdateCompactionSettingsOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyClusterSettingsProvider) UpdateMemoryQuotas(MemoryQuotas, *UpdateMemoryQuotasOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyClusterSettingsProvider) UpdateAuditSettings(AuditSettings, *UpdateAuditSettingsOptions) error {
+	return makeReadOnlyError()
+}
+
+type readOnlyNodeManagementProvider struct {
+	nodeManagementProvider
+}
+
+func (p *readOnlyNodeManagementProvider) Failover(string, *FailoverNodeOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyNodeManagementProvider) GracefulFailover(string, *GracefulFailoverNodeOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyNodeManagementProvider) Recover(string, NodeRecoveryType, *RecoverNodeOptions) error {
+	return makeReadOnlyError()
+}
+
+func (p *readOnlyNodeManagementProvider) Rebalance(*RebalanceOptions) error {
+	return makeReadOnlyError()
+}
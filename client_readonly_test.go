@@ -0,0 +1,80 @@
+package gocb
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) readOnlyCluster(setup func(cli *mockConnectionManager)) *Cluster {
+	cli := new(mockConnectionManager)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+	setup(cli)
+
+	cluster := suite.newCluster(newReadOnlyConnectionMgr(cli))
+	cluster.readOnlyMode = true
+
+	return cluster
+}
+
+func (suite *UnitTestSuite) TestReadOnlyModeManagementRefused() {
+	mgmtProvider := new(mockMgmtProvider)
+	cluster := suite.readOnlyCluster(func(cli *mockConnectionManager) {
+		cli.On("getBucketManagementProvider").Return(&bucketManagementProviderCore{
+			mgmtProvider: mgmtProvider,
+			tracer:       newTracerWrapper(&NoopTracer{}),
+		}, nil)
+		cli.On("getQueryIndexProvider").Return(&queryProviderCore{}, nil)
+		cli.On("getAnalyticsIndexProvider").Return(&struct{ analyticsIndexProvider }{}, nil)
+		cli.On("getLogCollectionProvider").Return(&logCollectionProviderCore{
+			provider: mgmtProvider,
+			tracer:   newTracerWrapper(&NoopTracer{}),
+		}, nil)
+	})
+
+	err := cluster.Buckets().CreateBucket(CreateBucketSettings{BucketSettings: BucketSettings{Name: "test"}}, nil)
+	suite.Assert().ErrorIs(err, ErrClusterReadOnly)
+
+	err = cluster.Buckets().DropBucket("test", nil)
+	suite.Assert().ErrorIs(err, ErrClusterReadOnly)
+
+	err = cluster.QueryIndexes().CreatePrimaryIndex("test", nil)
+	suite.Assert().ErrorIs(err, ErrClusterReadOnly)
+
+	err = cluster.AnalyticsIndexes().CreateView("test", "SELECT 1", nil)
+	suite.Assert().ErrorIs(err, ErrClusterReadOnly)
+
+	err = cluster.AnalyticsIndexes().CreateSynonym("test", "dataset", nil)
+	suite.Assert().ErrorIs(err, ErrClusterReadOnly)
+
+	err = cluster.AnalyticsIndexes().CreatePrimaryIndex("dataset", nil)
+	suite.Assert().ErrorIs(err, ErrClusterReadOnly)
+
+	err = cluster.StartLogCollection(nil)
+	suite.Assert().ErrorIs(err, ErrClusterReadOnly)
+
+	mgmtProvider.AssertNotCalled(suite.T(), "executeMgmtRequest", mock.Anything, mock.Anything)
+}
+
+func (suite *UnitTestSuite) TestReadOnlyModeQueryReadonly() {
+	provider := new(mockQueryProvider)
+	provider.
+		On("Query", "INSERT INTO default VALUES ('k', {})", (*Scope)(nil), mock.AnythingOfType("*gocb.QueryOptions")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(2).(*QueryOptions)
+			suite.Assert().True(opts.Readonly)
+		}).
+		Return(&QueryResult{}, nil)
+
+	cluster := suite.readOnlyCluster(func(cli *mockConnectionManager) {
+		cli.On("getQueryProvider").Return(provider, nil)
+	})
+
+	opts := &QueryOptions{}
+	_, err := cluster.Query("INSERT INTO default VALUES ('k', {})", opts)
+	suite.Require().Nil(err, err)
+
+	// The options supplied by the user must not be modified.
+	suite.Assert().False(opts.Readonly)
+	provider.AssertExpectations(suite.T())
+}
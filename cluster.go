@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	gocbconnstr "github.com/couchbaselabs/gocbconnstr/v2"
@@ -18,8 +20,15 @@ type Cluster struct {
 
 	connectionManager connectionManager
 
-	useServerDurations bool
-	useMutationTokens  bool
+	tracer *tracerWrapper
+	// connectSpan is the span covering Connect, connection lifecycle spans such as bucket_open and
+	// wait_until_ready are created as children of this span so that bootstrap latency can be broken down.
+	connectSpan RequestSpan
+
+	useServerDurations        bool
+	useMutationTokens         bool
+	allowSystemScopeMutations bool
+	readOnlyMode              bool
 
 	timeoutsConfig TimeoutsConfig
 
@@ -36,12 +45,18 @@ type Cluster struct {
 	transactionsConfig   TransactionsConfig
 	compressionConfig    CompressionConfig
 	compressor           *compressor
+	queryCacheConfig     QueryCacheConfig
+	warningHandler       WarningHandler
 
 	transactions *Transactions
 
 	keyspace keyspace
 
 	preferredServerGroup string
+
+	healthMonitorsLock   sync.Mutex
+	healthMonitors       map[*HealthMonitor]struct{}
+	healthMonitorsClosed bool
 }
 
 // IoConfig specifies IO related configuration options.
@@ -103,6 +118,12 @@ type CompressionConfig struct {
 type InternalConfig struct {
 	TLSRootCAProvider    func() *x509.CertPool
 	ConnectionBufferSize uint
+
+	// TimeSource is used in place of time.Now when converting expiry durations into absolute expiry times, when
+	// expiring query cache entries and when timestamping soft delete tombstones, allowing tests to simulate the passing
+	// of time, such as crossing the 30 day expiry boundary.
+	// UNCOMMITTED: This API may change in the future.
+	TimeSource func() time.Time
 }
 
 // ClusterOptions is the set of options available for creating a Cluster.
@@ -126,6 +147,12 @@ type ClusterOptions struct {
 	RetryStrategy RetryStrategy
 
 	// Tracer specifies the tracer to use for requests.
+	//
+	// Connecting is traced by a cluster_connect span, with dns_resolution and bootstrap child spans, and opening a
+	// bucket and WaitUntilReady by bucket_open and wait_until_ready spans beneath it. The per node phases of
+	// bootstrap, such as the TLS handshake, SASL authentication, bucket selection and config fetch, happen
+	// asynchronously within the connection pool and are not traced individually; their combined latency is
+	// captured by the wait_until_ready span.
 	Tracer RequestTracer
 
 	Meter Meter
@@ -152,6 +179,41 @@ type ClusterOptions struct {
 	// UNCOMMITTED: This API may change in the future.
 	PreferredServerGroup string
 
+	// AllowSystemScopeMutations allows KV mutations to be performed against collections within the _system scope.
+	// The _system scope is reserved by the server and so by default these mutations are refused, with
+	// ErrInvalidArgument, to prevent accidental writes.
+	// UNCOMMITTED: This API may change in the future.
+	AllowSystemScopeMutations bool
+
+	// EndpointSelectionPolicy specifies how the endpoint used for each query is chosen, defaulting to round robin.
+	// This can be overridden on a per query basis using QueryOptions. The policy only applies to queries, analytics
+	// and search requests are always distributed using round robin.
+	// UNCOMMITTED: This API may change in the future.
+	EndpointSelectionPolicy EndpointSelectionPolicy
+
+	// UseDataAPI routes key value CRUD operations and queries through the Data API, as when connecting using the
+	// https scheme, for connection strings using the couchbase or couchbases schemes. The connection string must
+	// contain exactly one host, and any port that it gives is used as the port of the Data API. This cannot be used
+	// with the couchbase2 scheme.
+	// UNCOMMITTED: This API may change in the future.
+	UseDataAPI bool
+
+	// ReadOnlyMode causes all mutating operations, such as KV and transactional writes, index DDL and management
+	// changes, to fail with ErrClusterReadOnly without contacting the server. Queries and analytics queries are
+	// sent with the readonly option set so that the server refuses any mutating statements.
+	// UNCOMMITTED: This API may change in the future.
+	ReadOnlyMode bool
+
+	// QueryCacheConfig specifies options for caching the results of read only queries within the client.
+	// UNCOMMITTED: This API may change in the future.
+	QueryCacheConfig QueryCacheConfig
+
+	// WarningHandler receives structured warnings, such as the use of deprecated APIs or the SDK silently falling
+	// back to a less capable behaviour. These warnings are always logged, the handler allows applications to act on
+	// them, e.g. failing CI builds which use deprecated APIs.
+	// UNCOMMITTED: This API may change in the future.
+	WarningHandler WarningHandler
+
 	// Internal: This should never be used and is not supported.
 	InternalConfig InternalConfig
 }
@@ -234,29 +296,39 @@ func clusterFromOptions(opts ClusterOptions) *Cluster {
 			KVScanTimeout:     kvScanTimeout,
 			ManagementTimeout: managementTimeout,
 		},
-		transcoder:             opts.Transcoder,
-		useMutationTokens:      useMutationTokens,
-		retryStrategyWrapper:   newCoreRetryStrategyWrapper(opts.RetryStrategy),
-		orphanLoggerEnabled:    !opts.OrphanReporterConfig.Disabled,
-		orphanLoggerInterval:   opts.OrphanReporterConfig.ReportInterval,
-		orphanLoggerSampleSize: opts.OrphanReporterConfig.SampleSize,
-		useServerDurations:     useServerDurations,
-		circuitBreakerConfig:   opts.CircuitBreakerConfig,
-		securityConfig:         opts.SecurityConfig,
-		internalConfig:         opts.InternalConfig,
-		transactionsConfig:     opts.TransactionsConfig,
-		compressionConfig:      opts.CompressionConfig,
+		transcoder:                opts.Transcoder,
+		useMutationTokens:         useMutationTokens,
+		allowSystemScopeMutations: opts.AllowSystemScopeMutations,
+		readOnlyMode:              opts.ReadOnlyMode,
+		retryStrategyWrapper:      newCoreRetryStrategyWrapper(opts.RetryStrategy),
+		orphanLoggerEnabled:       !opts.OrphanReporterConfig.Disabled,
+		orphanLoggerInterval:      opts.OrphanReporterConfig.ReportInterval,
+		orphanLoggerSampleSize:    opts.OrphanReporterConfig.SampleSize,
+		useServerDurations:        useServerDurations,
+		circuitBreakerConfig:      opts.CircuitBreakerConfig,
+		securityConfig:            opts.SecurityConfig,
+		internalConfig:            opts.InternalConfig,
+		transactionsConfig:        opts.TransactionsConfig,
+		compressionConfig:         opts.CompressionConfig,
+		queryCacheConfig:          opts.QueryCacheConfig,
 		compressor: &compressor{
 			CompressionEnabled:  !opts.CompressionConfig.Disabled,
 			CompressionMinSize:  opts.CompressionConfig.MinSize,
 			CompressionMinRatio: opts.CompressionConfig.MinRatio,
 		},
 		preferredServerGroup: opts.PreferredServerGroup,
+		warningHandler:       opts.WarningHandler,
 	}
 }
 
 // Connect creates and returns a Cluster instance created using the
 // provided options and a connection string.
+//
+// Connection strings using the https scheme, such as https://example.cloud.couchbase.com, route key value CRUD
+// operations and queries through the Data API rather than the key value and query services. This allows access from
+// networks where only HTTPS egress is permitted, but all other services are unavailable. The Data API can also be
+// selected for the couchbase and couchbases schemes by setting ClusterOptions.UseDataAPI.
+// UNCOMMITTED: The https scheme may change in the future.
 func Connect(connStr string, opts ClusterOptions) (*Cluster, error) {
 	connSpec, err := gocbconnstr.Parse(connStr)
 	if err != nil {
@@ -266,6 +338,9 @@ func Connect(connStr string, opts ClusterOptions) (*Cluster, error) {
 	if connSpec.Scheme == "http" {
 		return nil, errors.New("http scheme is not supported")
 	}
+	if opts.UseDataAPI && connSpec.Scheme == "couchbase2" {
+		return nil, makeInvalidArgumentsError("UseDataAPI cannot be used with the couchbase2 scheme")
+	}
 
 	cluster := clusterFromOptions(opts)
 	cluster.cSpec = connSpec
@@ -289,20 +364,50 @@ func Connect(connStr string, opts ClusterOptions) (*Cluster, error) {
 		meter = agMeter
 	}
 
-	cli := cluster.newConnectionMgr(connSpec.Scheme, &newConnectionMgrOptions{
-		tracer:               newTracerWrapper(initialTracer),
-		meter:                newMeterWrapper(meter),
-		preferredServerGroup: opts.PreferredServerGroup,
+	tracer := newTracerWrapper(initialTracer)
+	cluster.tracer = tracer
+
+	connectSpan := createBootstrapSpan(tracer, nil, spanNameClusterConnect)
+	connectSpan.SetAttribute(spanAttribNetTransport, connSpec.Scheme)
+	defer connectSpan.End()
+	cluster.connectSpan = connectSpan
+
+	protocol := connSpec.Scheme
+	if opts.UseDataAPI {
+		protocol = "https"
+	}
+
+	cli, err := cluster.newConnectionMgr(protocol, &newConnectionMgrOptions{
+		tracer:                  tracer,
+		meter:                   newMeterWrapper(meter),
+		preferredServerGroup:    opts.PreferredServerGroup,
+		endpointSelectionPolicy: opts.EndpointSelectionPolicy,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Building the config resolves the connection string, including any DNS SRV lookup.
+	dnsSpan := createBootstrapSpan(tracer, connectSpan, spanNameDNSResolution)
 	err = cli.buildConfig(cluster)
+	dnsSpan.End()
 	if err != nil {
 		return nil, err
 	}
 
+	// Connecting creates the connection pools, the nodes are bootstrapped asynchronously once this returns.
+	bootstrapSpan := createBootstrapSpan(tracer, connectSpan, spanNameBootstrap)
 	err = cli.connect()
+	bootstrapSpan.End()
 	if err != nil {
 		return nil, err
 	}
+	if cluster.queryCacheConfig.TTL > 0 {
+		cli = newQueryCacheConnectionMgr(cli, cluster.queryCacheConfig, cluster.internalConfig.TimeSource)
+	}
+	if cluster.readOnlyMode {
+		cli = newReadOnlyConnectionMgr(cli)
+	}
 	cluster.connectionManager = cli
 
 	cluster.transactions, err = cluster.initTransactions(cluster.transactionsConfig)
@@ -387,12 +492,43 @@ func (c *Cluster) parseExtraConnStrOptions(spec gocbconnstr.ConnSpec) error {
 		c.timeoutsConfig.ManagementTimeout = time.Duration(val) * time.Millisecond
 	}
 
+	// gocbcore also reads the compression options from the connection string, the compressor must match so that the
+	// size of values is validated against what gocbcore will actually send.
+	if valStr, ok := fetchOption("compression"); ok {
+		val, err := strconv.ParseBool(valStr)
+		if err != nil {
+			return fmt.Errorf("compression option must be a boolean")
+		}
+		c.compressor.CompressionEnabled = val
+	}
+
+	if valStr, ok := fetchOption("compression_min_size"); ok {
+		val, err := strconv.ParseUint(valStr, 10, 32)
+		if err != nil {
+			return fmt.Errorf("compression_min_size option must be a number")
+		}
+		c.compressor.CompressionMinSize = uint32(val)
+	}
+
+	if valStr, ok := fetchOption("compression_min_ratio"); ok {
+		val, err := strconv.ParseFloat(valStr, 64)
+		if err != nil {
+			return fmt.Errorf("compression_min_ratio option must be a number")
+		}
+		c.compressor.CompressionMinRatio = val
+	}
+
 	return nil
 }
 
 // Bucket connects the cluster to server(s) and returns a new Bucket instance.
 func (c *Cluster) Bucket(bucketName string) *Bucket {
 	b := newBucket(c, bucketName)
+
+	b.openSpan = createBootstrapSpan(c.tracer, c.connectSpan, spanNameBucketOpen)
+	b.openSpan.SetAttribute(spanAttribDBNameKey, bucketName)
+	defer b.openSpan.End()
+
 	err := c.connectionManager.openBucket(bucketName)
 	if err != nil {
 		b.setBootstrapError(err)
@@ -401,6 +537,12 @@ func (c *Cluster) Bucket(bucketName string) *Bucket {
 	return b
 }
 
+// Collection connects to the bucket of the keyspace and returns the collection which it identifies.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) Collection(keyspace Keyspace) *Collection {
+	return c.Bucket(keyspace.Bucket).Scope(keyspace.scopeName()).Collection(keyspace.collectionName())
+}
+
 func (c *Cluster) authenticator() Authenticator {
 	return c.auth
 }
@@ -420,6 +562,29 @@ type WaitUntilReadyOptions struct {
 
 	// VOLATILE: This API is subject to change at any time.
 	RetryStrategy RetryStrategy
+
+	// ParentSpan is the parent of the wait_until_ready span. If not set then the span is created as part of the
+	// trace for connecting to the cluster, or opening the bucket, so that bootstrap latency can be broken down.
+	// UNCOMMITTED: This API may change in the future.
+	ParentSpan RequestSpan
+}
+
+func waitUntilReadySpan(tracer *tracerWrapper, defaultParent RequestSpan, opts *WaitUntilReadyOptions) RequestSpan {
+	parent := opts.ParentSpan
+	if parent == nil {
+		parent = defaultParent
+	}
+
+	span := createBootstrapSpan(tracer, parent, spanNameWaitUntilReady)
+	if len(opts.ServiceTypes) > 0 {
+		services := make([]string, len(opts.ServiceTypes))
+		for i, svc := range opts.ServiceTypes {
+			services[i] = serviceTypeToString(svc)
+		}
+		span.SetAttribute(spanAttribServiceKey, strings.Join(services, ","))
+	}
+
+	return span
 }
 
 // WaitUntilReady will wait for the cluster object to be ready for use.
@@ -435,6 +600,9 @@ func (c *Cluster) WaitUntilReady(timeout time.Duration, opts *WaitUntilReadyOpti
 			opts = &WaitUntilReadyOptions{}
 		}
 
+		span := waitUntilReadySpan(c.tracer, c.connectSpan, opts)
+		defer span.End()
+
 		err := provider.WaitUntilReady(
 			opts.Context,
 			time.Now().Add(timeout),
@@ -448,10 +616,13 @@ func (c *Cluster) WaitUntilReady(timeout time.Duration, opts *WaitUntilReadyOpti
 	})
 }
 
-// Close shuts down all buckets in this cluster and invalidates any references this cluster has.
+// Close shuts down all buckets in this cluster, stops any health monitors started on it and invalidates any references
+// this cluster has.
 func (c *Cluster) Close(opts *ClusterCloseOptions) error {
 	var overallErr error
 
+	c.stopHealthMonitors()
+
 	if c.connectionManager != nil {
 		err := c.connectionManager.close()
 		if err != nil {
@@ -521,6 +692,17 @@ func (c *Cluster) internalController() *providerController[internalProvider] {
 	}
 }
 
+func (c *Cluster) logCollectionController() *providerController[logCollectionProvider] {
+	return &providerController[logCollectionProvider]{
+		get:          c.connectionManager.getLogCollectionProvider,
+		opController: c.connectionManager,
+
+		meter:    c.connectionManager.getMeter(),
+		keyspace: &c.keyspace,
+		service:  serviceValueManagement,
+	}
+}
+
 func (c *Cluster) transactionsController() *providerController[transactionsProvider] {
 	return &providerController[transactionsProvider]{
 		get:          c.connectionManager.getTransactionsProvider,
@@ -542,6 +724,66 @@ func (c *Cluster) Users() *UserManager {
 	}
 }
 
+// Security returns a SecurityManager for managing the security configuration of the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) Security() *SecurityManager {
+	return &SecurityManager{
+		controller: &providerController[securityManagementProvider]{
+			get:          c.connectionManager.getSecurityManagementProvider,
+			opController: c.connectionManager,
+
+			meter:    c.connectionManager.getMeter(),
+			keyspace: &c.keyspace,
+			service:  serviceValueManagement,
+		},
+	}
+}
+
+// XDCR returns an XDCRManager for managing cross data center replication.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) XDCR() *XDCRManager {
+	return &XDCRManager{
+		controller: &providerController[xdcrManagementProvider]{
+			get:          c.connectionManager.getXDCRManagementProvider,
+			opController: c.connectionManager,
+
+			meter:    c.connectionManager.getMeter(),
+			keyspace: &c.keyspace,
+			service:  serviceValueManagement,
+		},
+	}
+}
+
+// Settings returns a ClusterSettingsManager for managing the cluster wide settings of the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) Settings() *ClusterSettingsManager {
+	return &ClusterSettingsManager{
+		controller: &providerController[clusterSettingsProvider]{
+			get:          c.connectionManager.getClusterSettingsProvider,
+			opController: c.connectionManager,
+
+			meter:    c.connectionManager.getMeter(),
+			keyspace: &c.keyspace,
+			service:  serviceValueManagement,
+		},
+	}
+}
+
+// Nodes returns a NodeManager for managing the nodes of the cluster and rebalancing it.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) Nodes() *NodeManager {
+	return &NodeManager{
+		controller: &providerController[nodeManagementProvider]{
+			get:          c.connectionManager.getNodeManagementProvider,
+			opController: c.connectionManager,
+
+			meter:    c.connectionManager.getMeter(),
+			keyspace: &c.keyspace,
+			service:  serviceValueManagement,
+		},
+	}
+}
+
 // Buckets returns a BucketManager for managing buckets.
 func (c *Cluster) Buckets() *BucketManager {
 	return &BucketManager{
@@ -553,6 +795,10 @@ func (c *Cluster) Buckets() *BucketManager {
 			keyspace: &c.keyspace,
 			service:  serviceValueManagement,
 		},
+		waitUntilReady: func(bucketName string, timeout time.Duration, opts *WaitUntilReadyOptions) error {
+			return c.Bucket(bucketName).WaitUntilReady(timeout, opts)
+		},
+		warningHandler: c.warningHandler,
 	}
 }
 
@@ -567,6 +813,7 @@ func (c *Cluster) AnalyticsIndexes() *AnalyticsIndexManager {
 			keyspace: &c.keyspace,
 			service:  serviceValueManagement,
 		},
+		warningHandler: c.warningHandler,
 	}
 }
 
@@ -581,6 +828,7 @@ func (c *Cluster) QueryIndexes() *QueryIndexManager {
 			keyspace: &c.keyspace,
 			service:  serviceValueManagement,
 		},
+		warningHandler: c.warningHandler,
 	}
 }
 
@@ -2,12 +2,15 @@ package gocb
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 )
 
 // AnalyticsIndexManager provides methods for performing Couchbase Analytics index management.
 type AnalyticsIndexManager struct {
-	controller *providerController[analyticsIndexProvider]
+	controller     *providerController[analyticsIndexProvider]
+	warningHandler WarningHandler
 }
 
 // AnalyticsDataset contains information about an analytics dataset.
@@ -16,6 +19,12 @@ type AnalyticsDataset struct {
 	DataverseName string
 	LinkName      string
 	BucketName    string
+
+	// ScopeName and CollectionName are the scope and collection within BucketName that the dataset is created on.
+	// For datasets created on a bucket these are the default scope and collection.
+	// UNCOMMITTED: This API may change in the future.
+	ScopeName      string
+	CollectionName string
 }
 
 func (ad *AnalyticsDataset) fromData(data jsonAnalyticsDataset) error {
@@ -23,6 +32,17 @@ func (ad *AnalyticsDataset) fromData(data jsonAnalyticsDataset) error {
 	ad.DataverseName = data.DataverseName
 	ad.LinkName = data.LinkName
 	ad.BucketName = data.BucketName
+	ad.ScopeName = data.ScopeName
+	ad.CollectionName = data.CollectionName
+
+	if ad.BucketName != "" {
+		if ad.ScopeName == "" {
+			ad.ScopeName = "_default"
+		}
+		if ad.CollectionName == "" {
+			ad.CollectionName = "_default"
+		}
+	}
 
 	return nil
 }
@@ -44,6 +64,26 @@ func (ai *AnalyticsIndex) fromData(data jsonAnalyticsIndex) error {
 	return nil
 }
 
+// AnalyticsSynonym contains information about an analytics synonym.
+// UNCOMMITTED: This API may change in the future.
+type AnalyticsSynonym struct {
+	Name          string
+	DataverseName string
+
+	// TargetName and TargetDataverseName identify the object, such as a dataset or view, that the synonym refers to.
+	TargetName          string
+	TargetDataverseName string
+}
+
+func (as *AnalyticsSynonym) fromData(data jsonAnalyticsSynonym) error {
+	as.Name = data.SynonymName
+	as.DataverseName = data.DataverseName
+	as.TargetName = data.ObjectName
+	as.TargetDataverseName = data.ObjectDataverseName
+
+	return nil
+}
+
 // CreateAnalyticsDataverseOptions is the set of options available to the AnalyticsManager CreateDataverse operation.
 type CreateAnalyticsDataverseOptions struct {
 	IgnoreIfExists bool
@@ -106,6 +146,12 @@ type CreateAnalyticsDatasetOptions struct {
 	Condition      string
 	DataverseName  string
 
+	// ScopeName and CollectionName specify a collection within the bucket to create the dataset on, creating an
+	// analytics collection rather than a dataset on the bucket's default collection. Both must be set together.
+	// UNCOMMITTED: This API may change in the future.
+	ScopeName      string
+	CollectionName string
+
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
 	ParentSpan    RequestSpan
@@ -116,7 +162,8 @@ type CreateAnalyticsDatasetOptions struct {
 	Context context.Context
 }
 
-// CreateDataset creates a new analytics dataset.
+// CreateDataset creates a new analytics dataset on a bucket, or on a collection within the bucket if
+// CreateAnalyticsDatasetOptions.ScopeName and CollectionName are set.
 func (am *AnalyticsIndexManager) CreateDataset(datasetName, bucketName string, opts *CreateAnalyticsDatasetOptions) error {
 	return autoOpControlErrorOnly(am.controller, "manager_analytics_create_dataset", func(provider analyticsIndexProvider) error {
 		if opts == nil {
@@ -128,11 +175,35 @@ func (am *AnalyticsIndexManager) CreateDataset(datasetName, bucketName string, o
 				message: "dataset name cannot be empty",
 			}
 		}
+		if (opts.ScopeName == "") != (opts.CollectionName == "") {
+			return invalidArgumentsError{
+				message: "scope name and collection name must be set together",
+			}
+		}
 
 		return provider.CreateDataset(datasetName, bucketName, opts)
 	})
 }
 
+// CreateDatasetOnKeyspace creates a new analytics dataset on the collection identified by keyspace. If the keyspace
+// does not have a scope and collection then the dataset is created on the bucket's default collection.
+// UNCOMMITTED: This API may change in the future.
+func (am *AnalyticsIndexManager) CreateDatasetOnKeyspace(datasetName string, keyspace Keyspace,
+	opts *CreateAnalyticsDatasetOptions) error {
+	if err := keyspace.validate(); err != nil {
+		return err
+	}
+
+	var keyspaceOpts CreateAnalyticsDatasetOptions
+	if opts != nil {
+		keyspaceOpts = *opts
+	}
+	keyspaceOpts.ScopeName = keyspace.Scope
+	keyspaceOpts.CollectionName = keyspace.Collection
+
+	return am.CreateDataset(datasetName, keyspace.Bucket, &keyspaceOpts)
+}
+
 // DropAnalyticsDatasetOptions is the set of options available to the AnalyticsManager DropDataset operation.
 type DropAnalyticsDatasetOptions struct {
 	IgnoreIfNotExists bool
@@ -182,6 +253,69 @@ func (am *AnalyticsIndexManager) GetAllDatasets(opts *GetAllAnalyticsDatasetsOpt
 	})
 }
 
+// AnalyticsFieldType is the type of a field within an analytics index.
+// UNCOMMITTED: This API may change in the future.
+type AnalyticsFieldType string
+
+const (
+	// AnalyticsFieldTypeString indexes the field as a string.
+	AnalyticsFieldTypeString AnalyticsFieldType = "string"
+
+	// AnalyticsFieldTypeBigInt indexes the field as a 64 bit integer.
+	AnalyticsFieldTypeBigInt AnalyticsFieldType = "bigint"
+
+	// AnalyticsFieldTypeInt32 indexes the field as a 32 bit integer.
+	AnalyticsFieldTypeInt32 AnalyticsFieldType = "int32"
+
+	// AnalyticsFieldTypeDouble indexes the field as a double precision floating point number.
+	AnalyticsFieldTypeDouble AnalyticsFieldType = "double"
+
+	// AnalyticsFieldTypeFloat indexes the field as a single precision floating point number.
+	AnalyticsFieldTypeFloat AnalyticsFieldType = "float"
+
+	// AnalyticsFieldTypeBoolean indexes the field as a boolean.
+	AnalyticsFieldTypeBoolean AnalyticsFieldType = "boolean"
+
+	// AnalyticsFieldTypeDate indexes the field as a date.
+	AnalyticsFieldTypeDate AnalyticsFieldType = "date"
+
+	// AnalyticsFieldTypeTime indexes the field as a time.
+	AnalyticsFieldTypeTime AnalyticsFieldType = "time"
+
+	// AnalyticsFieldTypeDateTime indexes the field as a datetime.
+	AnalyticsFieldTypeDateTime AnalyticsFieldType = "datetime"
+)
+
+// analyticsIndexFieldTypes are the index field types, including aliases, which are accepted by the analytics service.
+var analyticsIndexFieldTypes = map[string]struct{}{
+	"string": {}, "bigint": {}, "int64": {}, "int32": {}, "integer": {}, "int": {}, "int16": {}, "smallint": {},
+	"int8": {}, "tinyint": {}, "double": {}, "float": {}, "boolean": {}, "date": {}, "time": {}, "datetime": {},
+}
+
+func validateAnalyticsIndexFields(fields map[string]string) error {
+	if len(fields) <= 0 {
+		return invalidArgumentsError{
+			message: "you must specify at least one field to index",
+		}
+	}
+
+	for name, typ := range fields {
+		if name == "" {
+			return invalidArgumentsError{
+				message: "index field name cannot be empty",
+			}
+		}
+		if _, ok := analyticsIndexFieldTypes[strings.ToLower(typ)]; !ok {
+			return invalidArgumentsError{
+				message: fmt.Sprintf("field %s has unsupported index type %q, expected one of string, bigint, int32, "+
+					"double, float, boolean, date, time or datetime", name, typ),
+			}
+		}
+	}
+
+	return nil
+}
+
 // CreateAnalyticsIndexOptions is the set of options available to the AnalyticsManager CreateIndex operation.
 type CreateAnalyticsIndexOptions struct {
 	IgnoreIfExists bool
@@ -197,7 +331,8 @@ type CreateAnalyticsIndexOptions struct {
 	Context context.Context
 }
 
-// CreateIndex creates a new analytics dataset.
+// CreateIndex creates a new analytics index. Fields maps the path of each field to index to its type, which should
+// be one of the AnalyticsFieldType values.
 func (am *AnalyticsIndexManager) CreateIndex(datasetName, indexName string, fields map[string]string, opts *CreateAnalyticsIndexOptions) error {
 	return autoOpControlErrorOnly(am.controller, "manager_analytics_create_index", func(provider analyticsIndexProvider) error {
 		if opts == nil {
@@ -209,13 +344,47 @@ func (am *AnalyticsIndexManager) CreateIndex(datasetName, indexName string, fiel
 				message: "index name cannot be empty",
 			}
 		}
-		if len(fields) <= 0 {
+		if err := validateAnalyticsIndexFields(fields); err != nil {
+			return err
+		}
+
+		return provider.CreateIndex(datasetName, indexName, fields, opts)
+	})
+}
+
+// CreateAnalyticsPrimaryIndexOptions is the set of options available to the AnalyticsManager CreatePrimaryIndex
+// operation.
+// UNCOMMITTED: This API may change in the future.
+type CreateAnalyticsPrimaryIndexOptions struct {
+	// IndexName is the name of the primary index, the server will generate a name if empty.
+	IndexName      string
+	IgnoreIfExists bool
+	DataverseName  string
+
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// CreatePrimaryIndex creates a new primary index on an analytics dataset.
+// UNCOMMITTED: This API may change in the future.
+func (am *AnalyticsIndexManager) CreatePrimaryIndex(datasetName string, opts *CreateAnalyticsPrimaryIndexOptions) error {
+	return autoOpControlErrorOnly(am.controller, "manager_analytics_create_primary_index", func(provider analyticsIndexProvider) error {
+		if opts == nil {
+			opts = &CreateAnalyticsPrimaryIndexOptions{}
+		}
+
+		if datasetName == "" {
 			return invalidArgumentsError{
-				message: "you must specify at least one field to index",
+				message: "dataset name cannot be empty",
 			}
 		}
 
-		return provider.CreateIndex(datasetName, indexName, fields, opts)
+		return provider.CreatePrimaryIndex(datasetName, opts)
 	})
 }
 
@@ -268,6 +437,170 @@ func (am *AnalyticsIndexManager) GetAllIndexes(opts *GetAllAnalyticsIndexesOptio
 	})
 }
 
+// CreateAnalyticsViewOptions is the set of options available to the AnalyticsManager CreateView operation.
+// UNCOMMITTED: This API may change in the future.
+type CreateAnalyticsViewOptions struct {
+	// IgnoreIfExists and ReplaceIfExists are mutually exclusive.
+	IgnoreIfExists  bool
+	ReplaceIfExists bool
+	DataverseName   string
+
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// CreateView creates a new analytics view, defined by an analytics query.
+// UNCOMMITTED: This API may change in the future.
+func (am *AnalyticsIndexManager) CreateView(viewName, query string, opts *CreateAnalyticsViewOptions) error {
+	return autoOpControlErrorOnly(am.controller, "manager_analytics_create_view", func(provider analyticsIndexProvider) error {
+		if opts == nil {
+			opts = &CreateAnalyticsViewOptions{}
+		}
+
+		if viewName == "" {
+			return makeInvalidArgumentsError("view name cannot be empty")
+		}
+		if query == "" {
+			return makeInvalidArgumentsError("view query cannot be empty")
+		}
+		if opts.IgnoreIfExists && opts.ReplaceIfExists {
+			return makeInvalidArgumentsError("cannot use both IgnoreIfExists and ReplaceIfExists")
+		}
+
+		return provider.CreateView(viewName, query, opts)
+	})
+}
+
+// DropAnalyticsViewOptions is the set of options available to the AnalyticsManager DropView operation.
+// UNCOMMITTED: This API may change in the future.
+type DropAnalyticsViewOptions struct {
+	IgnoreIfNotExists bool
+	DataverseName     string
+
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// DropView drops an analytics view.
+// UNCOMMITTED: This API may change in the future.
+func (am *AnalyticsIndexManager) DropView(viewName string, opts *DropAnalyticsViewOptions) error {
+	return autoOpControlErrorOnly(am.controller, "manager_analytics_drop_view", func(provider analyticsIndexProvider) error {
+		if opts == nil {
+			opts = &DropAnalyticsViewOptions{}
+		}
+
+		if viewName == "" {
+			return makeInvalidArgumentsError("view name cannot be empty")
+		}
+
+		return provider.DropView(viewName, opts)
+	})
+}
+
+// CreateAnalyticsSynonymOptions is the set of options available to the AnalyticsManager CreateSynonym operation.
+// UNCOMMITTED: This API may change in the future.
+type CreateAnalyticsSynonymOptions struct {
+	IgnoreIfExists bool
+
+	// DataverseName is the dataverse to create the synonym in.
+	DataverseName string
+
+	// TargetDataverseName is the dataverse containing the object that the synonym refers to.
+	TargetDataverseName string
+
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// CreateSynonym creates a new analytics synonym, an alternative name for a dataset, view or other synonym.
+// UNCOMMITTED: This API may change in the future.
+func (am *AnalyticsIndexManager) CreateSynonym(synonymName, targetName string, opts *CreateAnalyticsSynonymOptions) error {
+	return autoOpControlErrorOnly(am.controller, "manager_analytics_create_synonym", func(provider analyticsIndexProvider) error {
+		if opts == nil {
+			opts = &CreateAnalyticsSynonymOptions{}
+		}
+
+		if synonymName == "" {
+			return makeInvalidArgumentsError("synonym name cannot be empty")
+		}
+		if targetName == "" {
+			return makeInvalidArgumentsError("synonym target name cannot be empty")
+		}
+
+		return provider.CreateSynonym(synonymName, targetName, opts)
+	})
+}
+
+// DropAnalyticsSynonymOptions is the set of options available to the AnalyticsManager DropSynonym operation.
+// UNCOMMITTED: This API may change in the future.
+type DropAnalyticsSynonymOptions struct {
+	IgnoreIfNotExists bool
+	DataverseName     string
+
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// DropSynonym drops an analytics synonym.
+// UNCOMMITTED: This API may change in the future.
+func (am *AnalyticsIndexManager) DropSynonym(synonymName string, opts *DropAnalyticsSynonymOptions) error {
+	return autoOpControlErrorOnly(am.controller, "manager_analytics_drop_synonym", func(provider analyticsIndexProvider) error {
+		if opts == nil {
+			opts = &DropAnalyticsSynonymOptions{}
+		}
+
+		if synonymName == "" {
+			return makeInvalidArgumentsError("synonym name cannot be empty")
+		}
+
+		return provider.DropSynonym(synonymName, opts)
+	})
+}
+
+// GetAllAnalyticsSynonymsOptions is the set of options available to the AnalyticsManager GetAllSynonyms operation.
+// UNCOMMITTED: This API may change in the future.
+type GetAllAnalyticsSynonymsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// GetAllSynonyms gets all analytics synonyms.
+// UNCOMMITTED: This API may change in the future.
+func (am *AnalyticsIndexManager) GetAllSynonyms(opts *GetAllAnalyticsSynonymsOptions) ([]AnalyticsSynonym, error) {
+	return autoOpControl(am.controller, "manager_analytics_get_all_synonyms", func(provider analyticsIndexProvider) ([]AnalyticsSynonym, error) {
+		if opts == nil {
+			opts = &GetAllAnalyticsSynonymsOptions{}
+		}
+
+		return provider.GetAllSynonyms(opts)
+	})
+}
+
 // ConnectAnalyticsLinkOptions is the set of options available to the AnalyticsManager ConnectLink operation.
 type ConnectAnalyticsLinkOptions struct {
 	LinkName      string
@@ -333,8 +666,65 @@ type GetPendingMutationsAnalyticsOptions struct {
 }
 
 // GetPendingMutations returns the number of pending mutations for all indexes in the form of dataverse.dataset:mutations.
+//
+// Deprecated: Use GetAllPendingMutations instead.
 func (am *AnalyticsIndexManager) GetPendingMutations(opts *GetPendingMutationsAnalyticsOptions) (map[string]map[string]int, error) {
-	return autoOpControl(am.controller, "manager_analytics_get_pending_mutations", func(provider analyticsIndexProvider) (map[string]map[string]int, error) {
+	warnDeprecated(am.warningHandler, "AnalyticsIndexManager.GetPendingMutations",
+		"GetPendingMutations is deprecated, use GetAllPendingMutations instead")
+
+	pending, err := am.GetAllPendingMutations(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]map[string]int)
+	for _, dataset := range pending.Datasets {
+		if out[dataset.DataverseName] == nil {
+			out[dataset.DataverseName] = make(map[string]int)
+		}
+		out[dataset.DataverseName][dataset.DatasetName] = int(dataset.Mutations)
+	}
+
+	return out, nil
+}
+
+// AnalyticsDatasetPendingMutations is the number of mutations which are yet to be ingested by an analytics dataset.
+// UNCOMMITTED: This API may change in the future.
+type AnalyticsDatasetPendingMutations struct {
+	DataverseName string
+	DatasetName   string
+	Mutations     int64
+}
+
+// AnalyticsPendingMutations contains the number of mutations which are yet to be ingested by each analytics dataset.
+// UNCOMMITTED: This API may change in the future.
+type AnalyticsPendingMutations struct {
+	Datasets []AnalyticsDatasetPendingMutations
+}
+
+// Total returns the number of pending mutations across all datasets.
+func (p *AnalyticsPendingMutations) Total() int64 {
+	var total int64
+	for _, dataset := range p.Datasets {
+		total += dataset.Mutations
+	}
+	return total
+}
+
+// Get returns the number of pending mutations for a dataset, and whether the dataset was present.
+func (p *AnalyticsPendingMutations) Get(dataverseName, datasetName string) (int64, bool) {
+	for _, dataset := range p.Datasets {
+		if dataset.DataverseName == dataverseName && dataset.DatasetName == datasetName {
+			return dataset.Mutations, true
+		}
+	}
+	return 0, false
+}
+
+// GetAllPendingMutations returns the number of mutations which are yet to be ingested by each analytics dataset.
+// UNCOMMITTED: This API may change in the future.
+func (am *AnalyticsIndexManager) GetAllPendingMutations(opts *GetPendingMutationsAnalyticsOptions) (*AnalyticsPendingMutations, error) {
+	return autoOpControl(am.controller, "manager_analytics_get_pending_mutations", func(provider analyticsIndexProvider) (*AnalyticsPendingMutations, error) {
 		if opts == nil {
 			opts = &GetPendingMutationsAnalyticsOptions{}
 		}
@@ -544,3 +934,26 @@ func (am *AnalyticsIndexManager) GetLinks(opts *GetAnalyticsLinksOptions) ([]Ana
 		return provider.GetLinks(opts)
 	})
 }
+
+type jsonAnalyticsDataset struct {
+	DatasetName    string `json:"DatasetName"`
+	DataverseName  string `json:"DataverseName"`
+	LinkName       string `json:"LinkName"`
+	BucketName     string `json:"BucketName"`
+	ScopeName      string `json:"ScopeName"`
+	CollectionName string `json:"CollectionName"`
+}
+
+type jsonAnalyticsIndex struct {
+	IndexName     string `json:"IndexName"`
+	DatasetName   string `json:"DatasetName"`
+	DataverseName string `json:"DataverseName"`
+	IsPrimary     bool   `json:"IsPrimary"`
+}
+
+type jsonAnalyticsSynonym struct {
+	SynonymName         string `json:"SynonymName"`
+	DataverseName       string `json:"DataverseName"`
+	ObjectName          string `json:"ObjectName"`
+	ObjectDataverseName string `json:"ObjectDataverseName"`
+}
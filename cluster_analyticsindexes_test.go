@@ -1,8 +1,21 @@
+//go:build !gocb_noanalytics
+
 package gocb
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"io"
 	"net/url"
+	"testing"
+	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func (suite *IntegrationTestSuite) TestAnalyticsIndexesCrud() {
@@ -880,3 +893,404 @@ func (suite *UnitTestSuite) TestAnalyticsIndexesCouchbaseLinksFormEncode() {
 	suite.Assert().Equal("clientcertificate", q.Get("clientCertificate"))
 	suite.Assert().Equal("clientkey", q.Get("clientKey"))
 }
+
+func (suite *UnitTestSuite) TestAnalyticsIndexesLinkNamesEscaped() {
+	var paths []string
+	provider := new(mockMgmtProvider)
+	provider.
+		On("executeMgmtRequest", nil, mock.AnythingOfType("gocb.mgmtRequest")).
+		Run(func(args mock.Arguments) {
+			paths = append(paths, args.Get(1).(mgmtRequest).Path)
+		}).
+		Return(func(context.Context, mgmtRequest) *mgmtResponse {
+			return &mgmtResponse{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewReader([]byte("[]"))),
+			}
+		}, nil)
+
+	mgr := &AnalyticsIndexManager{
+		controller: &providerController[analyticsIndexProvider]{
+			get: func() (analyticsIndexProvider, error) {
+				return &analyticsProviderCore{
+					mgmtProvider: provider,
+					tracer:       newTracerWrapper(&NoopTracer{}),
+				}, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+
+	_, err := mgr.GetLinks(&GetAnalyticsLinksOptions{
+		Dataverse: "travel/inventory",
+		Name:      "my link?",
+		LinkType:  AnalyticsLinkTypeS3External,
+	})
+	suite.Require().Nil(err, err)
+
+	_, err = mgr.GetLinks(&GetAnalyticsLinksOptions{
+		Dataverse: "travel&sample",
+		Name:      "a=b",
+	})
+	suite.Require().Nil(err, err)
+
+	err = mgr.DropLink("my link?", "travel/inventory", nil)
+	suite.Require().Nil(err, err)
+
+	err = mgr.ReplaceLink(NewS3ExternalAnalyticsLink("my link?", "travel/inventory", "id", "secret", "us-east-1", nil), nil)
+	suite.Require().Nil(err, err)
+
+	suite.Require().Len(paths, 4)
+	suite.Assert().Equal("/analytics/link/travel%2Finventory/my%20link%3F?type=s3", paths[0])
+	suite.Assert().Equal("/analytics/link?dataverse=travel%26sample&name=a%3Db", paths[1])
+	suite.Assert().Equal("/analytics/link/travel%2Finventory/my%20link%3F", paths[2])
+	suite.Assert().Equal("/analytics/link/travel%2Finventory/my%20link%3F", paths[3])
+}
+
+func (suite *UnitTestSuite) analyticsIndexManager(runFn func(args mock.Arguments), reader analyticsRowReader) *AnalyticsIndexManager {
+	provider := new(mockAnalyticsProviderCoreProvider)
+	provider.
+		On("AnalyticsQuery", nil, mock.AnythingOfType("gocbcore.AnalyticsQueryOptions")).
+		Run(runFn).
+		Return(reader, nil)
+
+	return &AnalyticsIndexManager{
+		controller: &providerController[analyticsIndexProvider]{
+			get: func() (analyticsIndexProvider, error) {
+				return &analyticsProviderCore{
+					provider:             provider,
+					tracer:               newTracerWrapper(&NoopTracer{}),
+					retryStrategyWrapper: newCoreRetryStrategyWrapper(NewBestEffortRetryStrategy(nil)),
+					analyticsTimeout:     75 * time.Second,
+				}, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+}
+
+func (suite *UnitTestSuite) TestAnalyticsIndexesCreateDatasetOnCollection() {
+	var statements []string
+	mgr := suite.analyticsIndexManager(func(args mock.Arguments) {
+		opts := args.Get(1).(gocbcore.AnalyticsQueryOptions)
+
+		var payload map[string]interface{}
+		suite.Require().Nil(json.Unmarshal(opts.Payload, &payload))
+		statements = append(statements, payload["statement"].(string))
+	}, &mockQueryIndexRowReader{mockQueryRowReaderBase: mockQueryRowReaderBase{Suite: suite}})
+
+	err := mgr.CreateDataset("airlines", "travel-sample", &CreateAnalyticsDatasetOptions{
+		IgnoreIfExists: true,
+		DataverseName:  "travel/inventory",
+		ScopeName:      "inventory",
+		CollectionName: "airline",
+		Condition:      "country = \"France\"",
+	})
+	suite.Require().Nil(err, err)
+
+	err = mgr.CreateDataset("beers", "beer-sample", nil)
+	suite.Require().Nil(err, err)
+
+	err = mgr.CreateDataset("airlines", "travel-sample", &CreateAnalyticsDatasetOptions{
+		ScopeName: "inventory",
+	})
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	keyspace, err := ParseKeyspace("travel-sample.inventory.route")
+	suite.Require().Nil(err, err)
+	err = mgr.CreateDatasetOnKeyspace("routes", keyspace, &CreateAnalyticsDatasetOptions{
+		ScopeName: "ignored",
+	})
+	suite.Require().Nil(err, err)
+
+	err = mgr.CreateDatasetOnKeyspace("routes", Keyspace{Scope: "inventory", Collection: "route"}, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	suite.Require().Len(statements, 3)
+	suite.Assert().Equal("CREATE ANALYTICS COLLECTION IF NOT EXISTS `travel`.`inventory`.`airlines` ON "+
+		"`travel-sample`.`inventory`.`airline` WHERE country = \"France\"", statements[0])
+	suite.Assert().Equal("CREATE DATASET  `beers` ON `beer-sample` ", statements[1])
+	suite.Assert().Equal("CREATE ANALYTICS COLLECTION  `routes` ON `travel-sample`.`inventory`.`route` ", statements[2])
+}
+
+func (suite *UnitTestSuite) TestAnalyticsIndexesGetAllDatasetsKeyspace() {
+	reader := &mockQueryIndexRowReader{
+		Dataset: []map[string]interface{}{
+			{
+				"DatasetName":    "airlines",
+				"DataverseName":  "travel/inventory",
+				"LinkName":       "Local",
+				"BucketName":     "travel-sample",
+				"ScopeName":      "inventory",
+				"CollectionName": "airline",
+			},
+			{
+				"DatasetName":   "beers",
+				"DataverseName": "Default",
+				"LinkName":      "Local",
+				"BucketName":    "beer-sample",
+			},
+		},
+		mockQueryRowReaderBase: mockQueryRowReaderBase{Suite: suite},
+	}
+	mgr := suite.analyticsIndexManager(nil, reader)
+
+	datasets, err := mgr.GetAllDatasets(nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal([]AnalyticsDataset{
+		{
+			Name:           "airlines",
+			DataverseName:  "travel/inventory",
+			LinkName:       "Local",
+			BucketName:     "travel-sample",
+			ScopeName:      "inventory",
+			CollectionName: "airline",
+		},
+		{
+			Name:           "beers",
+			DataverseName:  "Default",
+			LinkName:       "Local",
+			BucketName:     "beer-sample",
+			ScopeName:      "_default",
+			CollectionName: "_default",
+		},
+	}, datasets)
+}
+
+func (suite *UnitTestSuite) TestAnalyticsIndexesIdentifierEscaping() {
+	var statements []string
+	mgr := suite.analyticsIndexManager(func(args mock.Arguments) {
+		opts := args.Get(1).(gocbcore.AnalyticsQueryOptions)
+
+		var payload map[string]interface{}
+		suite.Require().Nil(json.Unmarshal(opts.Payload, &payload))
+		statements = append(statements, payload["statement"].(string))
+	}, &mockQueryIndexRowReader{mockQueryRowReaderBase: mockQueryRowReaderBase{Suite: suite}})
+
+	err := mgr.DropDataverse("travel/in`ventory", nil)
+	suite.Require().Nil(err, err)
+
+	err = mgr.DropDataset("air\\lines", &DropAnalyticsDatasetOptions{DataverseName: "travel/inventory"})
+	suite.Require().Nil(err, err)
+
+	err = mgr.CreateIndex("airlines", "by`name", map[string]string{
+		"name":                               "string",
+		"address.city":                       "string",
+		"id:string) DROP DATAVERSE Default;": "string",
+	}, &CreateAnalyticsIndexOptions{
+		DataverseName: "travel/inventory",
+	})
+	suite.Require().Nil(err, err)
+
+	err = mgr.DropIndex("airlines", "by`name; DROP DATAVERSE Default", &DropAnalyticsIndexOptions{IgnoreIfNotExists: true})
+	suite.Require().Nil(err, err)
+
+	err = mgr.ConnectLink(&ConnectAnalyticsLinkOptions{DataverseName: "travel/inventory", LinkName: "remote"})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal([]string{
+		"DROP DATAVERSE `travel`.`in\\`ventory` ",
+		"DROP DATASET `travel`.`inventory`.`air\\\\lines` ",
+		"CREATE INDEX `by\\`name`  ON `travel`.`inventory`.`airlines` (`address`.`city`:string,`id:string) DROP DATAVERSE Default;`:string,`name`:string)",
+		"DROP INDEX `airlines`.`by\\`name; DROP DATAVERSE Default` IF EXISTS",
+		"CONNECT LINK `travel`.`inventory`.`remote`",
+	}, statements)
+}
+
+func (suite *UnitTestSuite) TestAnalyticsIndexesViewsAndSynonyms() {
+	var statements []string
+	mgr := suite.analyticsIndexManager(func(args mock.Arguments) {
+		opts := args.Get(1).(gocbcore.AnalyticsQueryOptions)
+
+		var payload map[string]interface{}
+		suite.Require().Nil(json.Unmarshal(opts.Payload, &payload))
+		statements = append(statements, payload["statement"].(string))
+	}, &mockQueryIndexRowReader{mockQueryRowReaderBase: mockQueryRowReaderBase{Suite: suite}})
+
+	err := mgr.CreateView("french_airlines", "SELECT a.* FROM airlines a WHERE a.country = \"France\"",
+		&CreateAnalyticsViewOptions{
+			ReplaceIfExists: true,
+			DataverseName:   "travel/inventory",
+		})
+	suite.Require().Nil(err, err)
+
+	err = mgr.DropView("french_airlines", &DropAnalyticsViewOptions{IgnoreIfNotExists: true})
+	suite.Require().Nil(err, err)
+
+	err = mgr.CreateSynonym("carriers", "airlines", &CreateAnalyticsSynonymOptions{
+		IgnoreIfExists:      true,
+		DataverseName:       "Default",
+		TargetDataverseName: "travel/inventory",
+	})
+	suite.Require().Nil(err, err)
+
+	err = mgr.DropSynonym("carriers", &DropAnalyticsSynonymOptions{DataverseName: "Default"})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal([]string{
+		"CREATE OR REPLACE ANALYTICS VIEW `travel`.`inventory`.`french_airlines`  AS SELECT a.* FROM airlines a WHERE a.country = \"France\"",
+		"DROP ANALYTICS VIEW `french_airlines` IF EXISTS",
+		"CREATE ANALYTICS SYNONYM `Default`.`carriers` IF NOT EXISTS FOR `travel`.`inventory`.`airlines`",
+		"DROP ANALYTICS SYNONYM `Default`.`carriers` ",
+	}, statements)
+
+	err = mgr.CreateView("french_airlines", "SELECT 1", &CreateAnalyticsViewOptions{
+		IgnoreIfExists:  true,
+		ReplaceIfExists: true,
+	})
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	err = mgr.CreateSynonym("carriers", "", nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestAnalyticsIndexesGetAllSynonyms() {
+	mgr := suite.analyticsIndexManager(func(args mock.Arguments) {
+		opts := args.Get(1).(gocbcore.AnalyticsQueryOptions)
+
+		var payload map[string]interface{}
+		suite.Require().Nil(json.Unmarshal(opts.Payload, &payload))
+		suite.Assert().Equal("SELECT s.* FROM Metadata.`Synonym` s WHERE s.DataverseName <> \"Metadata\"", payload["statement"])
+	}, &mockQueryIndexRowReader{
+		Dataset: []map[string]interface{}{
+			{
+				"SynonymName":         "carriers",
+				"DataverseName":       "Default",
+				"ObjectName":          "airlines",
+				"ObjectDataverseName": "travel/inventory",
+			},
+		},
+		mockQueryRowReaderBase: mockQueryRowReaderBase{Suite: suite},
+	})
+
+	synonyms, err := mgr.GetAllSynonyms(nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal([]AnalyticsSynonym{
+		{
+			Name:                "carriers",
+			DataverseName:       "Default",
+			TargetName:          "airlines",
+			TargetDataverseName: "travel/inventory",
+		},
+	}, synonyms)
+}
+
+func (suite *UnitTestSuite) TestAnalyticsIndexesGetAllPendingMutations() {
+	type tCase struct {
+		name string
+		body string
+	}
+
+	testCases := []tCase{
+		{
+			name: "nested",
+			body: `{"Default":{"beers":3,"breweries":0},"travel/inventory":{"airline":1.0}}`,
+		},
+		{
+			name: "qualified",
+			body: "{\"Default.beers\":3,\"Default.breweries\":0,\"`travel`.`inventory`.`airline`\":1}",
+		},
+	}
+
+	for _, tCase := range testCases {
+		suite.T().Run(tCase.name, func(te *testing.T) {
+			provider := new(mockMgmtProvider)
+			provider.
+				On("executeMgmtRequest", nil, mock.AnythingOfType("gocb.mgmtRequest")).
+				Return(func(context.Context, mgmtRequest) *mgmtResponse {
+					return &mgmtResponse{
+						StatusCode: 200,
+						Body:       io.NopCloser(bytes.NewReader([]byte(tCase.body))),
+					}
+				}, nil)
+
+			var warnings []Warning
+			mgr := &AnalyticsIndexManager{
+				controller: &providerController[analyticsIndexProvider]{
+					get: func() (analyticsIndexProvider, error) {
+						return &analyticsProviderCore{
+							mgmtProvider: provider,
+							tracer:       newTracerWrapper(&NoopTracer{}),
+						}, nil
+					},
+					opController: mockOpController{},
+				},
+				warningHandler: func(warning Warning) {
+					warnings = append(warnings, warning)
+				},
+			}
+
+			pending, err := mgr.GetAllPendingMutations(nil)
+			require.Nil(te, err, err)
+
+			assert.Equal(te, []AnalyticsDatasetPendingMutations{
+				{DataverseName: "Default", DatasetName: "beers", Mutations: 3},
+				{DataverseName: "Default", DatasetName: "breweries", Mutations: 0},
+				{DataverseName: "travel/inventory", DatasetName: "airline", Mutations: 1},
+			}, pending.Datasets)
+			assert.Equal(te, int64(4), pending.Total())
+
+			mutations, ok := pending.Get("travel/inventory", "airline")
+			assert.True(te, ok)
+			assert.Equal(te, int64(1), mutations)
+
+			_, ok = pending.Get("Default", "airline")
+			assert.False(te, ok)
+
+			legacy, err := mgr.GetPendingMutations(nil)
+			require.Nil(te, err, err)
+			assert.Equal(te, map[string]map[string]int{
+				"Default":          {"beers": 3, "breweries": 0},
+				"travel/inventory": {"airline": 1},
+			}, legacy)
+
+			require.Len(te, warnings, 1)
+			assert.Equal(te, WarningTypeDeprecated, warnings[0].Type)
+			assert.Equal(te, "AnalyticsIndexManager.GetPendingMutations", warnings[0].Feature)
+		})
+	}
+}
+
+func (suite *UnitTestSuite) TestAnalyticsIndexesCreateIndexFieldTypes() {
+	var statements []string
+	mgr := suite.analyticsIndexManager(func(args mock.Arguments) {
+		opts := args.Get(1).(gocbcore.AnalyticsQueryOptions)
+
+		var payload map[string]interface{}
+		suite.Require().Nil(json.Unmarshal(opts.Payload, &payload))
+		statements = append(statements, payload["statement"].(string))
+	}, &mockQueryIndexRowReader{mockQueryRowReaderBase: mockQueryRowReaderBase{Suite: suite}})
+
+	err := mgr.CreateIndex("airlines", "by_name", map[string]string{
+		"name":    string(AnalyticsFieldTypeString),
+		"id":      "BIGINT",
+		"country": "string",
+	}, nil)
+	suite.Require().Nil(err, err)
+
+	err = mgr.CreateIndex("airlines", "by_name", map[string]string{"name": "varchar"}, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	err = mgr.CreateIndex("airlines", "by_name", map[string]string{"": "string"}, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	err = mgr.CreatePrimaryIndex("airlines", &CreateAnalyticsPrimaryIndexOptions{
+		IndexName:      "primary",
+		IgnoreIfExists: true,
+		DataverseName:  "travel/inventory",
+	})
+	suite.Require().Nil(err, err)
+
+	err = mgr.CreatePrimaryIndex("beers", nil)
+	suite.Require().Nil(err, err)
+
+	err = mgr.CreatePrimaryIndex("", nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	suite.Require().Len(statements, 3)
+	suite.Assert().Equal("CREATE INDEX `by_name`  ON `airlines` (`country`:string,`id`:bigint,`name`:string)", statements[0])
+	suite.Assert().Equal("CREATE PRIMARY INDEX `primary` IF NOT EXISTS ON `travel`.`inventory`.`airlines`", statements[1])
+	suite.Assert().Equal("CREATE PRIMARY INDEX ON `beers`", statements[2])
+}
@@ -3,6 +3,7 @@ package gocb
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"time"
 )
 
@@ -126,7 +127,10 @@ func (arr *AnalyticsResultRaw) MetaData() ([]byte, error) {
 
 // AnalyticsResult allows access to the results of a query.
 type AnalyticsResult struct {
-	reader analyticsRowReader
+	reader         analyticsRowReader
+	handleProvider analyticsHandleProvider
+	serializer     JSONSerializer
+	outcome        *streamOutcome
 
 	rowBytes []byte
 }
@@ -149,6 +153,66 @@ func (r *AnalyticsResult) Raw() *AnalyticsResultRaw {
 	return vr
 }
 
+// ReadCloser returns the remaining rows as a stream of newline delimited JSON, allowing them to be copied to a
+// writer without deserializing them. Errors which occur on the stream are returned by Read, closing the ReadCloser
+// closes the results. Calling this function invalidates the underlying AnalyticsResult which will no longer be able to
+// be used.
+// UNCOMMITTED: This API may change in the future.
+func (r *AnalyticsResult) ReadCloser() io.ReadCloser {
+	if r.reader == nil {
+		return &analyticsResultReadCloser{err: r.Err()}
+	}
+
+	rc := &analyticsResultReadCloser{
+		reader: r.reader,
+	}
+
+	r.reader = nil
+	return rc
+}
+
+type analyticsResultReadCloser struct {
+	reader analyticsRowReader
+	buf    []byte
+	err    error
+}
+
+func (rc *analyticsResultReadCloser) Read(p []byte) (int, error) {
+	for len(rc.buf) == 0 {
+		if rc.err != nil {
+			return 0, rc.err
+		}
+
+		row := rc.reader.NextRow()
+		if row == nil {
+			rc.err = io.EOF
+			if err := rc.reader.Err(); err != nil {
+				rc.err = maybeEnhanceAnalyticsError(err)
+			}
+			continue
+		}
+
+		rc.buf = append(append(rc.buf, row...), '\n')
+	}
+
+	n := copy(p, rc.buf)
+	rc.buf = rc.buf[n:]
+	return n, nil
+}
+
+func (rc *analyticsResultReadCloser) Close() error {
+	if rc.reader == nil {
+		return rc.err
+	}
+
+	err := rc.reader.Close()
+	if err != nil {
+		return maybeEnhanceAnalyticsError(err)
+	}
+
+	return nil
+}
+
 // Next assigns the next result from the results into the value pointer, returning whether the read was successful.
 func (r *AnalyticsResult) Next() bool {
 	if r.reader == nil {
@@ -179,7 +243,40 @@ func (r *AnalyticsResult) Row(valuePtr interface{}) error {
 		return nil
 	}
 
-	return json.Unmarshal(r.rowBytes, valuePtr)
+	return r.deserialize(r.rowBytes, valuePtr)
+}
+
+// NextBytes moves to the next row and returns its raw JSON bytes, or nil if there are no more rows. The returned
+// slice is not copied and must not be modified. Err should be checked once nil is returned.
+// UNCOMMITTED: This API may change in the future.
+func (r *AnalyticsResult) NextBytes() []byte {
+	if !r.Next() {
+		return nil
+	}
+
+	return r.rowBytes
+}
+
+func (r *AnalyticsResult) deserialize(bytes []byte, valuePtr interface{}) error {
+	if r.serializer != nil {
+		return r.serializer.Deserialize(bytes, valuePtr)
+	}
+
+	return json.Unmarshal(bytes, valuePtr)
+}
+
+// Spool reads all of the remaining rows from the stream, holding them in memory up to the configured budget and
+// buffering any beyond it to a temporary file. Once spooled, MetaData can be accessed before the rows are iterated.
+// The response stream is closed by spooling, Close must still be called to remove any temporary file.
+// UNCOMMITTED: This API may change in the future.
+func (r *AnalyticsResult) Spool(opts *ResultSpoolOptions) error {
+	if r.reader == nil {
+		return r.Err()
+	}
+
+	spool := newRowSpool(r.reader, opts)
+	r.reader = spool
+	return spool.spoolErr
 }
 
 // Err returns any errors that have occurred on the stream
@@ -190,6 +287,7 @@ func (r *AnalyticsResult) Err() error {
 
 	err := r.reader.Err()
 	if err != nil {
+		r.outcome.observe(err)
 		return maybeEnhanceAnalyticsError(err)
 	}
 
@@ -204,12 +302,17 @@ func (r *AnalyticsResult) Close() error {
 
 	err := r.reader.Close()
 	if err != nil {
+		r.outcome.observe(err)
 		return maybeEnhanceAnalyticsError(err)
 	}
 
 	return nil
 }
 
+func (r *AnalyticsResult) setStreamOutcome(outcome *streamOutcome) {
+	r.outcome = outcome
+}
+
 // One assigns the first value from the results into the value pointer.
 // It will close the results but not before iterating through all remaining
 // results, as such this should only be used for very small resultsets - ideally
@@ -230,7 +333,7 @@ func (r *AnalyticsResult) One(valuePtr interface{}) error {
 		// do nothing with the row
 	}
 
-	return json.Unmarshal(valueBytes, valuePtr)
+	return r.deserialize(valueBytes, valuePtr)
 }
 
 // MetaData returns any meta-data that was available from this query.  Note that
@@ -246,8 +349,12 @@ func (r *AnalyticsResult) MetaData() (*AnalyticsMetaData, error) {
 		return nil, err
 	}
 
+	return parseAnalyticsMetaData(metaDataBytes)
+}
+
+func parseAnalyticsMetaData(metaDataBytes []byte) (*AnalyticsMetaData, error) {
 	var jsonResp jsonAnalyticsResponse
-	err = json.Unmarshal(metaDataBytes, &jsonResp)
+	err := json.Unmarshal(metaDataBytes, &jsonResp)
 	if err != nil {
 		return nil, err
 	}
@@ -278,3 +385,29 @@ func maybeGetAnalyticsOption(options map[string]interface{}, name string) string
 	}
 	return ""
 }
+
+type jsonAnalyticsMetrics struct {
+	ElapsedTime      string `json:"elapsedTime"`
+	ExecutionTime    string `json:"executionTime"`
+	ResultCount      uint64 `json:"resultCount"`
+	ResultSize       uint64 `json:"resultSize"`
+	MutationCount    uint64 `json:"mutationCount,omitempty"`
+	SortCount        uint64 `json:"sortCount,omitempty"`
+	ErrorCount       uint64 `json:"errorCount,omitempty"`
+	WarningCount     uint64 `json:"warningCount,omitempty"`
+	ProcessedObjects uint64 `json:"processedObjects,omitempty"`
+}
+
+type jsonAnalyticsWarning struct {
+	Code    uint32 `json:"code"`
+	Message string `json:"msg"`
+}
+
+type jsonAnalyticsResponse struct {
+	RequestID       string                 `json:"requestID"`
+	ClientContextID string                 `json:"clientContextID"`
+	Status          string                 `json:"status"`
+	Warnings        []jsonAnalyticsWarning `json:"warnings"`
+	Metrics         jsonAnalyticsMetrics   `json:"metrics"`
+	Signature       interface{}            `json:"signature"`
+}
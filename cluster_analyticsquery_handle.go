@@ -0,0 +1,271 @@
+package gocb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// AnalyticsHandleStatus is the status of an analytics query executed using the async or deferred mode.
+// UNCOMMITTED: This API may change in the future.
+type AnalyticsHandleStatus string
+
+const (
+	// AnalyticsHandleStatusQueued indicates that the query is waiting to be executed.
+	AnalyticsHandleStatusQueued AnalyticsHandleStatus = "queued"
+
+	// AnalyticsHandleStatusRunning indicates that the query is executing.
+	AnalyticsHandleStatusRunning AnalyticsHandleStatus = "running"
+
+	// AnalyticsHandleStatusSuccess indicates that the query completed and its results can be fetched using Rows.
+	AnalyticsHandleStatusSuccess AnalyticsHandleStatus = "success"
+
+	// AnalyticsHandleStatusFailed indicates that the query failed.
+	AnalyticsHandleStatusFailed AnalyticsHandleStatus = "failed"
+
+	// AnalyticsHandleStatusTimeout indicates that the query timed out.
+	AnalyticsHandleStatusTimeout AnalyticsHandleStatus = "timeout"
+
+	// AnalyticsHandleStatusFatal indicates that the query failed with a fatal error.
+	AnalyticsHandleStatusFatal AnalyticsHandleStatus = "fatal"
+)
+
+// AnalyticsHandleStatusOptions is the set of options available to the AnalyticsHandle Status operation.
+// UNCOMMITTED: This API may change in the future.
+type AnalyticsHandleStatusOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// AnalyticsHandleRowsOptions is the set of options available to the AnalyticsHandle Rows operation.
+// UNCOMMITTED: This API may change in the future.
+type AnalyticsHandleRowsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+type jsonAnalyticsHandleResponse struct {
+	Status string                 `json:"status"`
+	Handle string                 `json:"handle"`
+	Errors []jsonAnalyticsErrDesc `json:"errors"`
+}
+
+type jsonAnalyticsErrDesc struct {
+	Code    uint32 `json:"code"`
+	Message string `json:"msg"`
+}
+
+// AnalyticsHandle allows the status and results of an analytics query executed using the async or deferred mode to be
+// fetched, without holding the original request open while the query executes.
+// UNCOMMITTED: This API may change in the future.
+type AnalyticsHandle struct {
+	provider analyticsHandleProvider
+
+	statusHandle string
+	resultHandle string
+	errors       []AnalyticsErrorDesc
+	status       AnalyticsHandleStatus
+}
+
+// Handle returns the handle of a query executed using the async or deferred mode. Any remaining rows are read and
+// the result is closed.
+// UNCOMMITTED: This API may change in the future.
+func (r *AnalyticsResult) Handle() (*AnalyticsHandle, error) {
+	if r.reader == nil {
+		return nil, r.Err()
+	}
+
+	for r.Next() {
+	}
+	if err := r.Close(); err != nil {
+		return nil, err
+	}
+
+	metaDataBytes, err := r.reader.MetaData()
+	if err != nil {
+		return nil, err
+	}
+
+	var jsonResp jsonAnalyticsHandleResponse
+	if err := json.Unmarshal(metaDataBytes, &jsonResp); err != nil {
+		return nil, err
+	}
+
+	if jsonResp.Handle == "" || r.handleProvider == nil {
+		return nil, makeInvalidArgumentsError("result has no handle, the query must use the async or deferred mode")
+	}
+
+	handle := &AnalyticsHandle{
+		provider: r.handleProvider,
+	}
+	handle.update(jsonResp)
+
+	return handle, nil
+}
+
+func (h *AnalyticsHandle) update(jsonResp jsonAnalyticsHandleResponse) {
+	h.status = AnalyticsHandleStatus(jsonResp.Status)
+
+	// A query which has completed returns a handle for fetching its results, otherwise the handle is for polling its
+	// status.
+	if h.status == AnalyticsHandleStatusSuccess {
+		if jsonResp.Handle != "" {
+			h.resultHandle = jsonResp.Handle
+		}
+	} else if h.statusHandle == "" {
+		h.statusHandle = jsonResp.Handle
+	}
+
+	h.errors = make([]AnalyticsErrorDesc, len(jsonResp.Errors))
+	for i, desc := range jsonResp.Errors {
+		h.errors[i] = AnalyticsErrorDesc{
+			Code:    desc.Code,
+			Message: desc.Message,
+		}
+	}
+}
+
+// Status fetches the current status of the query. Once the query has completed successfully its results can be
+// fetched using Rows.
+func (h *AnalyticsHandle) Status(opts *AnalyticsHandleStatusOptions) (AnalyticsHandleStatus, error) {
+	if opts == nil {
+		opts = &AnalyticsHandleStatusOptions{}
+	}
+
+	if h.resultHandle != "" {
+		return AnalyticsHandleStatusSuccess, nil
+	}
+
+	jsonResp, err := h.provider.AnalyticsHandleStatus(h.statusHandle, opts)
+	if err != nil {
+		return "", err
+	}
+
+	h.update(*jsonResp)
+
+	return h.status, nil
+}
+
+// Rows fetches the results of the query, returning ErrAnalyticsHandleNotReady if the query has not yet completed
+// successfully.
+func (h *AnalyticsHandle) Rows(opts *AnalyticsHandleRowsOptions) (*AnalyticsResult, error) {
+	if opts == nil {
+		opts = &AnalyticsHandleRowsOptions{}
+	}
+
+	if h.resultHandle == "" {
+		_, err := h.Status(&AnalyticsHandleStatusOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if h.resultHandle == "" {
+			return nil, &AnalyticsError{
+				InnerError: wrapError(ErrAnalyticsHandleNotReady, "query status is "+string(h.status)),
+				Errors:     h.errors,
+			}
+		}
+	}
+
+	reader, err := h.provider.AnalyticsHandleRows(h.resultHandle, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAnalyticsResult(reader), nil
+}
+
+// analyticsHandleRowReader streams the rows of a JSON array, as returned when fetching the results of a handle.
+type analyticsHandleRowReader struct {
+	body    io.ReadCloser
+	decoder *json.Decoder
+	started bool
+	done    bool
+	err     error
+
+	// span covers the streaming of the results, it is ended once the stream has been read or closed.
+	span       RequestSpan
+	finishOnce sync.Once
+}
+
+func (r *analyticsHandleRowReader) finish() {
+	r.finishOnce.Do(func() {
+		if r.span == nil {
+			return
+		}
+		setSpanOutcome(r.span, nil, r.err)
+		r.span.End()
+	})
+}
+
+func (r *analyticsHandleRowReader) NextRow() []byte {
+	row := r.nextRow()
+	if row == nil {
+		r.finish()
+	}
+
+	return row
+}
+
+func (r *analyticsHandleRowReader) nextRow() []byte {
+	if r.done || r.err != nil {
+		return nil
+	}
+
+	if !r.started {
+		r.started = true
+		if _, err := r.decoder.Token(); err != nil {
+			r.err = err
+			return nil
+		}
+	}
+
+	if !r.decoder.More() {
+		r.done = true
+		return nil
+	}
+
+	var row json.RawMessage
+	if err := r.decoder.Decode(&row); err != nil {
+		r.err = err
+		return nil
+	}
+
+	return row
+}
+
+func (r *analyticsHandleRowReader) Err() error {
+	if r.err != nil {
+		r.finish()
+	}
+
+	return r.err
+}
+
+func (r *analyticsHandleRowReader) MetaData() ([]byte, error) {
+	// The results of a handle do not include any meta-data.
+	return []byte("{}"), nil
+}
+
+func (r *analyticsHandleRowReader) Close() error {
+	defer r.finish()
+
+	return r.body.Close()
+}
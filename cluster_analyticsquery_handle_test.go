@@ -0,0 +1,197 @@
+//go:build !gocb_noanalytics
+
+package gocb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) analyticsHandleCluster(meta string, mgmtProvider *mockMgmtProvider,
+	runFn func(args mock.Arguments)) *Cluster {
+	analyticsProviderCoreProvider := new(mockAnalyticsProviderCoreProvider)
+	analyticsProviderCoreProvider.
+		On("AnalyticsQuery", nil, mock.AnythingOfType("gocbcore.AnalyticsQueryOptions")).
+		Run(runFn).
+		Return(&mockAnalyticsRowReader{Meta: []byte(meta), Suite: suite}, nil)
+
+	analyticsProvider := &analyticsProviderCore{
+		provider:     analyticsProviderCoreProvider,
+		mgmtProvider: mgmtProvider,
+	}
+
+	cli := new(mockConnectionManager)
+	cli.On("getAnalyticsProvider").Return(analyticsProvider, nil)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	analyticsProvider.tracer = newTracerWrapper(&NoopTracer{})
+	analyticsProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	analyticsProvider.analyticsTimeout = cluster.timeoutsConfig.AnalyticsTimeout
+
+	return cluster
+}
+
+func (suite *UnitTestSuite) analyticsHandleResponse(body string) *mgmtResponse {
+	return &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+	}
+}
+
+func (suite *UnitTestSuite) TestAnalyticsQueryAsyncHandle() {
+	mgmtProvider := new(mockMgmtProvider)
+	mgmtProvider.
+		On("executeMgmtRequest", nil, mock.MatchedBy(func(req mgmtRequest) bool {
+			return req.Path == "/analytics/service/status/12-0"
+		})).
+		Return(suite.analyticsHandleResponse(`{"status":"running","handle":"http://10.0.0.1:8095/analytics/service/status/12-0"}`), nil).
+		Once()
+	mgmtProvider.
+		On("executeMgmtRequest", nil, mock.MatchedBy(func(req mgmtRequest) bool {
+			return req.Path == "/analytics/service/status/12-0"
+		})).
+		Return(suite.analyticsHandleResponse(`{"status":"success","handle":"http://10.0.0.1:8095/analytics/service/result/12-0"}`), nil).
+		Once()
+	mgmtProvider.
+		On("executeMgmtRequest", nil, mock.MatchedBy(func(req mgmtRequest) bool {
+			return req.Path == "/analytics/service/result/12-0"
+		})).
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(mgmtRequest)
+			suite.Assert().Equal("http://10.0.0.1:8095", req.Endpoint)
+			suite.Assert().Equal("GET", req.Method)
+			suite.Assert().Equal(ServiceTypeAnalytics, req.Service)
+		}).
+		Return(suite.analyticsHandleResponse(`[{"name":"a"},{"name":"b"}]`), nil).
+		Once()
+
+	cluster := suite.analyticsHandleCluster(
+		`{"requestID":"1","status":"running","handle":"http://10.0.0.1:8095/analytics/service/status/12-0"}`,
+		mgmtProvider,
+		func(args mock.Arguments) {
+			opts := args.Get(1).(gocbcore.AnalyticsQueryOptions)
+
+			var payload map[string]interface{}
+			suite.Require().Nil(json.Unmarshal(opts.Payload, &payload))
+			suite.Assert().Equal("async", payload["mode"])
+		})
+
+	result, err := cluster.AnalyticsQuery("SELECT name FROM dataset", &AnalyticsOptions{Mode: AnalyticsModeAsync})
+	suite.Require().Nil(err, err)
+
+	handle, err := result.Handle()
+	suite.Require().Nil(err, err)
+
+	_, err = handle.Rows(nil)
+	suite.Require().ErrorIs(err, ErrAnalyticsHandleNotReady)
+
+	status, err := handle.Status(nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(AnalyticsHandleStatusSuccess, status)
+
+	rows, err := handle.Rows(nil)
+	suite.Require().Nil(err, err)
+
+	var names []string
+	for rows.Next() {
+		var row map[string]string
+		suite.Require().Nil(rows.Row(&row))
+		names = append(names, row["name"])
+	}
+	suite.Require().Nil(rows.Err())
+	suite.Require().Nil(rows.Close())
+	suite.Assert().Equal([]string{"a", "b"}, names)
+
+	mgmtProvider.AssertExpectations(suite.T())
+}
+
+func (suite *UnitTestSuite) TestAnalyticsQueryDeferredHandle() {
+	mgmtProvider := new(mockMgmtProvider)
+	mgmtProvider.
+		On("executeMgmtRequest", nil, mock.AnythingOfType("gocb.mgmtRequest")).
+		Return(suite.analyticsHandleResponse(`[1,2,3]`), nil).
+		Once()
+
+	cluster := suite.analyticsHandleCluster(
+		`{"requestID":"1","status":"success","handle":"/analytics/service/result/13-0"}`,
+		mgmtProvider,
+		nil)
+
+	result, err := cluster.AnalyticsQuery("SELECT VALUE 1", &AnalyticsOptions{Mode: AnalyticsModeDeferred})
+	suite.Require().Nil(err, err)
+
+	handle, err := result.Handle()
+	suite.Require().Nil(err, err)
+
+	// Deferred queries have already completed, so no status request is needed.
+	status, err := handle.Status(nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(AnalyticsHandleStatusSuccess, status)
+
+	rows, err := handle.Rows(nil)
+	suite.Require().Nil(err, err)
+
+	var values []int
+	for rows.Next() {
+		var value int
+		suite.Require().Nil(rows.Row(&value))
+		values = append(values, value)
+	}
+	suite.Require().Nil(rows.Err())
+	suite.Assert().Equal([]int{1, 2, 3}, values)
+
+	mgmtProvider.AssertExpectations(suite.T())
+}
+
+func (suite *UnitTestSuite) TestAnalyticsQueryHandleNotAvailable() {
+	cluster := suite.analyticsHandleCluster(`{"requestID":"1","status":"success"}`, new(mockMgmtProvider), nil)
+
+	result, err := cluster.AnalyticsQuery("SELECT VALUE 1", nil)
+	suite.Require().Nil(err, err)
+
+	_, err = result.Handle()
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	_, err = cluster.AnalyticsQuery("SELECT VALUE 1", &AnalyticsOptions{Mode: "later"})
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
+
+func (suite *UnitTestSuite) TestAnalyticsHandleRowsSpanCoversStream() {
+	mgmtProvider := new(mockMgmtProvider)
+	mgmtProvider.
+		On("executeMgmtRequest", nil, mock.AnythingOfType("gocb.mgmtRequest")).
+		Return(suite.analyticsHandleResponse(`[1,2]`), nil).
+		Once()
+
+	tracer := newTestTracer()
+	provider := &analyticsProviderCore{
+		mgmtProvider: mgmtProvider,
+		tracer:       newTracerWrapper(tracer),
+	}
+
+	reader, err := provider.AnalyticsHandleRows("/analytics/service/result/13-0", &AnalyticsHandleRowsOptions{})
+	suite.Require().Nil(err, err)
+
+	spans := tracer.GetSpans()
+	suite.Require().Len(spans[nil], 1)
+	span := spans[nil][0]
+	suite.Assert().Equal("analytics_handle_rows", span.Name)
+
+	// The span remains open whilst the rows are streamed.
+	suite.Assert().NotNil(reader.NextRow())
+	suite.Assert().False(span.Finished)
+
+	suite.Assert().NotNil(reader.NextRow())
+	suite.Assert().Nil(reader.NextRow())
+	suite.Assert().True(span.Finished)
+	suite.Require().Nil(reader.Err())
+	suite.Require().Nil(reader.Close())
+}
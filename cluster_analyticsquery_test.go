@@ -1,10 +1,14 @@
+//go:build !gocb_noanalytics
+
 package gocb
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/couchbase/gocbcore/v10"
@@ -698,3 +702,276 @@ func (suite *UnitTestSuite) TestAnalyticsQueryRaw() {
 
 	suite.Assert().Equal(reader.Meta, metadata)
 }
+
+func (suite *UnitTestSuite) TestAnalyticsQueryContextCancelledWhilstStreaming() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reader := &blockingQueryRowReader{
+		rows:    [][]byte{[]byte(`{"id":1}`), []byte(`{"id":2}`)},
+		closeCh: make(chan struct{}),
+	}
+
+	provider := new(mockAnalyticsProviderCoreProvider)
+	provider.
+		On("AnalyticsQuery", ctx, mock.AnythingOfType("gocbcore.AnalyticsQueryOptions")).
+		Return(reader, nil).
+		Once()
+
+	abandonedCh := make(chan mgmtRequest, 1)
+	mgmtProvider := new(mockMgmtProvider)
+	mgmtProvider.
+		On("executeMgmtRequest", context.Background(), mock.AnythingOfType("gocb.mgmtRequest")).
+		Run(func(args mock.Arguments) {
+			abandonedCh <- args.Get(1).(mgmtRequest)
+		}).
+		Return(&mgmtResponse{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewReader(nil)),
+		}, nil).
+		Once()
+
+	analyticsProvider := &analyticsProviderCore{
+		provider:     provider,
+		mgmtProvider: mgmtProvider,
+		tracer:       newTracerWrapper(&NoopTracer{}),
+	}
+
+	cli := new(mockConnectionManager)
+	cli.On("getAnalyticsProvider").Return(analyticsProvider, nil)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	analyticsProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	analyticsProvider.analyticsTimeout = cluster.timeoutsConfig.AnalyticsTimeout
+
+	result, err := cluster.AnalyticsQuery("SELECT 1", &AnalyticsOptions{
+		ClientContextID: "my-context",
+		Context:         ctx,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Require().True(result.Next())
+
+	cancel()
+
+	suite.Assert().False(result.Next())
+	suite.Assert().ErrorIs(result.Err(), ErrRequestCanceled)
+	suite.Assert().ErrorIs(result.Close(), ErrRequestCanceled)
+
+	select {
+	case req := <-abandonedCh:
+		suite.Assert().Equal("DELETE", req.Method)
+		suite.Assert().Equal("/analytics/admin/active_requests", req.Path)
+		suite.Assert().Equal(ServiceTypeAnalytics, req.Service)
+		suite.Assert().Equal("client_context_id=my-context", string(req.Body))
+	case <-time.After(5 * time.Second):
+		suite.T().Fatal("query was not cancelled on the server")
+	}
+}
+
+func (suite *UnitTestSuite) TestAnalyticsQueryContextDoneAfterStreamCompleted() {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(50*time.Millisecond))
+	defer cancel()
+
+	var dataset testAnalyticsDataset
+	err := loadJSONTestDataset("beer_sample_analytics_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockAnalyticsRowReader{
+		Dataset: dataset.Results,
+		Meta:    suite.mustConvertToBytes(dataset.jsonAnalyticsResponse),
+		Suite:   suite,
+	}
+
+	provider := new(mockAnalyticsProviderCoreProvider)
+	provider.
+		On("AnalyticsQuery", ctx, mock.AnythingOfType("gocbcore.AnalyticsQueryOptions")).
+		Return(reader, nil).
+		Once()
+
+	mgmtProvider := new(mockMgmtProvider)
+
+	analyticsProvider := &analyticsProviderCore{
+		provider:     provider,
+		mgmtProvider: mgmtProvider,
+		tracer:       newTracerWrapper(&NoopTracer{}),
+	}
+
+	cli := new(mockConnectionManager)
+	cli.On("getAnalyticsProvider").Return(analyticsProvider, nil)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	analyticsProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	analyticsProvider.analyticsTimeout = cluster.timeoutsConfig.AnalyticsTimeout
+
+	var delivered *AnalyticsMetaData
+	result, err := cluster.AnalyticsQuery("SELECT 1", &AnalyticsOptions{
+		ClientContextID: "my-context",
+		Context:         ctx,
+		OnMetaData: func(meta *AnalyticsMetaData) {
+			delivered = meta
+		},
+	})
+	suite.Require().Nil(err, err)
+
+	var rows int
+	for result.Next() {
+		rows++
+	}
+	suite.Assert().Equal(len(dataset.Results), rows)
+	suite.Require().NotNil(delivered)
+	suite.Assert().Equal(dataset.RequestID, delivered.RequestID)
+
+	<-ctx.Done()
+
+	suite.Assert().Nil(result.Err())
+	suite.Assert().Nil(result.Close())
+
+	// The stream completed before the deadline so the query must not be abandoned on the server.
+	mgmtProvider.AssertNotCalled(suite.T(), "executeMgmtRequest", mock.Anything, mock.Anything)
+}
+
+func (suite *UnitTestSuite) TestAnalyticsQueryContextDeadlineWhilstStreaming() {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(50*time.Millisecond))
+	defer cancel()
+
+	reader := &blockingQueryRowReader{
+		rows:    [][]byte{[]byte(`{"id":1}`), []byte(`{"id":2}`)},
+		closeCh: make(chan struct{}),
+	}
+
+	abandoned := make(chan struct{}, 1)
+	abandon := func() {
+		abandoned <- struct{}{}
+	}
+
+	result := newAnalyticsResult(newAnalyticsProviderCoreRowReader(ctx, reader, abandon, nil))
+	suite.Require().True(result.Next())
+
+	<-ctx.Done()
+
+	suite.Assert().False(result.Next())
+	suite.Assert().ErrorIs(result.Err(), ErrTimeout)
+	suite.Assert().NotErrorIs(result.Err(), ErrRequestCanceled)
+	suite.Assert().ErrorIs(result.Close(), ErrTimeout)
+
+	select {
+	case <-abandoned:
+	case <-time.After(5 * time.Second):
+		suite.T().Fatal("query was not cancelled on the server")
+	}
+}
+
+func (suite *UnitTestSuite) TestAnalyticsQueryResultsReadCloser() {
+	var dataset testAnalyticsDataset
+	err := loadJSONTestDataset("beer_sample_analytics_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockAnalyticsRowReader{
+		Dataset: dataset.Results,
+		Meta:    suite.mustConvertToBytes(dataset.jsonAnalyticsResponse),
+		Suite:   suite,
+	}
+	result := newAnalyticsResult(reader)
+
+	rc := result.ReadCloser()
+	suite.Assert().False(result.Next())
+
+	decoder := json.NewDecoder(rc)
+	var breweries []testBreweryDocument
+	for {
+		var doc testBreweryDocument
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		suite.Require().Nil(err, err)
+		breweries = append(breweries, doc)
+	}
+	suite.Require().Nil(rc.Close())
+
+	suite.Assert().Equal(dataset.Results, breweries)
+}
+
+func (suite *UnitTestSuite) TestAnalyticsQueryResultsReadCloserStreamError() {
+	reader := &mockAnalyticsRowReader{
+		RowsErr: errors.New("stream failed"),
+		Suite:   suite,
+	}
+	result := newAnalyticsResult(reader)
+
+	_, err := io.ReadAll(result.ReadCloser())
+	suite.Assert().EqualError(err, "stream failed")
+}
+
+func (suite *UnitTestSuite) TestAnalyticsQueryResultsNextBytes() {
+	var dataset testAnalyticsDataset
+	err := loadJSONTestDataset("beer_sample_analytics_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockAnalyticsRowReader{
+		Dataset: dataset.Results,
+		Meta:    suite.mustConvertToBytes(dataset.jsonAnalyticsResponse),
+		Suite:   suite,
+	}
+	result := newAnalyticsResult(reader)
+
+	var breweries []testBreweryDocument
+	for row := result.NextBytes(); row != nil; row = result.NextBytes() {
+		var doc testBreweryDocument
+		suite.Require().Nil(json.Unmarshal(row, &doc))
+		breweries = append(breweries, doc)
+	}
+	suite.Require().Nil(result.Err())
+
+	suite.Assert().Equal(dataset.Results, breweries)
+}
+
+func (suite *UnitTestSuite) TestAnalyticsQueryResultsSerializer() {
+	var dataset testAnalyticsDataset
+	err := loadJSONTestDataset("beer_sample_analytics_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockAnalyticsRowReader{
+		Dataset: dataset.Results,
+		Meta:    suite.mustConvertToBytes(dataset.jsonAnalyticsResponse),
+		Suite:   suite,
+	}
+	serializer := &testQuerySerializer{}
+	result := newAnalyticsResult(reader)
+	result.serializer = serializer
+
+	for result.Next() {
+		var doc testBreweryDocument
+		suite.Require().Nil(result.Row(&doc))
+	}
+	suite.Assert().Equal(len(dataset.Results), serializer.deserialized)
+}
+
+func (suite *UnitTestSuite) TestAnalyticsOptionsSerializer() {
+	serializer := &testQuerySerializer{}
+	opts := &AnalyticsOptions{
+		PositionalParameters: []interface{}{1},
+		Serializer:           serializer,
+	}
+
+	execOpts, err := opts.toMap()
+	suite.Require().Nil(err)
+	suite.Assert().Equal([]json.RawMessage{json.RawMessage(`"serialized"`)}, execOpts["args"])
+
+	opts = &AnalyticsOptions{
+		NamedParameters: map[string]interface{}{"name": 2},
+		Serializer:      serializer,
+	}
+
+	execOpts, err = opts.toMap()
+	suite.Require().Nil(err)
+	suite.Assert().Equal(json.RawMessage(`"serialized"`), execOpts["$name"])
+	suite.Assert().ElementsMatch([]interface{}{1, 2}, serializer.serialized)
+}
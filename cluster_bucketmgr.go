@@ -2,6 +2,7 @@ package gocb
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
@@ -117,12 +118,34 @@ type BucketSettings struct {
 	HistoryRetentionCollectionDefault HistoryRetentionCollectionDefault
 	HistoryRetentionBytes             uint64
 	HistoryRetentionDuration          time.Duration
+
+	// Rank determines the order in which buckets are handled during failover and rebalance, with higher ranked
+	// buckets handled first. Requires Couchbase Server 7.6 or later.
+	// UNCOMMITTED: This API may change in the future.
+	Rank uint32
+
+	// NumVBuckets is the number of vbuckets for the bucket, which can only be set when the bucket is created. If it is
+	// set when updating a bucket then it must match the current number of vbuckets.
+	// Requires Couchbase Server 8.0 or later.
+	// UNCOMMITTED: This API may change in the future.
+	NumVBuckets uint16
 }
 
 // BucketManager provides methods for performing bucket management operations.
 // See BucketManager for methods that allow creating and removing buckets themselves.
 type BucketManager struct {
 	controller *providerController[bucketManagementProvider]
+
+	waitUntilReady func(bucketName string, timeout time.Duration, opts *WaitUntilReadyOptions) error
+	warningHandler WarningHandler
+}
+
+// warnIfMaxTTL warns when the deprecated MaxTTL is used. Settings fetched by GetBucket have the same MaxTTL and
+// MaxExpiry, so these are not warned about when passed back to UpdateBucket.
+func (bm *BucketManager) warnIfMaxTTL(settings *BucketSettings) {
+	if settings.MaxTTL > 0 && settings.MaxTTL != settings.MaxExpiry {
+		warnDeprecated(bm.warningHandler, "BucketSettings.MaxTTL", "MaxTTL is deprecated, use MaxExpiry instead")
+	}
 }
 
 // GetBucketOptions is the set of options available to the bucket manager GetBucket operation.
@@ -195,6 +218,7 @@ func (bm *BucketManager) CreateBucket(settings CreateBucketSettings, opts *Creat
 		if opts == nil {
 			opts = &CreateBucketOptions{}
 		}
+		bm.warnIfMaxTTL(&settings.BucketSettings)
 
 		return provider.CreateBucket(settings, opts)
 	})
@@ -212,12 +236,15 @@ type UpdateBucketOptions struct {
 	Context context.Context
 }
 
-// UpdateBucket updates a bucket on the cluster.
+// UpdateBucket updates a bucket on the cluster. Changing the StorageBackend of a bucket starts migrating it to the new
+// storage backend, which requires Couchbase Server 7.6 or later. The migration of each node completes when the node is
+// next rebalanced. ErrInvalidArgument is returned if NumVBuckets is set and differs from the current number of vbuckets.
 func (bm *BucketManager) UpdateBucket(settings BucketSettings, opts *UpdateBucketOptions) error {
 	return autoOpControlErrorOnly(bm.controller, "manager_bucket_update_bucket", func(provider bucketManagementProvider) error {
 		if opts == nil {
 			opts = &UpdateBucketOptions{}
 		}
+		bm.warnIfMaxTTL(&settings)
 
 		return provider.UpdateBucket(settings, opts)
 	})
@@ -248,6 +275,8 @@ func (bm *BucketManager) DropBucket(name string, opts *DropBucketOptions) error
 
 // FlushBucketOptions is the set of options available to the bucket manager FlushBucket operation.
 type FlushBucketOptions struct {
+	// Timeout bounds the flush request, or the entire operation including waiting for the bucket to become empty
+	// when WaitUntilEmpty is set.
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
 	ParentSpan    RequestSpan
@@ -256,16 +285,210 @@ type FlushBucketOptions struct {
 	// also applies to global level timeouts.
 	// UNCOMMITTED: This API may change in the future.
 	Context context.Context
+
+	// WaitUntilEmpty waits until the item count of the bucket reaches zero after the flush has been started.
+	// UNCOMMITTED: This API may change in the future.
+	WaitUntilEmpty bool
+
+	// OnProgress is called with the item count of the bucket each time it is checked whilst waiting for the bucket to
+	// become empty.
+	// UNCOMMITTED: This API may change in the future.
+	OnProgress func(progress FlushBucketProgress)
+}
+
+// FlushBucketProgress describes the progress of a flush which is being waited on.
+// UNCOMMITTED: This API may change in the future.
+type FlushBucketProgress struct {
+	ItemCount uint64
 }
 
 // FlushBucket will delete all the of the data from a bucket.
 // Keep in mind that you must have flushing enabled in the buckets configuration.
 func (bm *BucketManager) FlushBucket(name string, opts *FlushBucketOptions) error {
-	return autoOpControlErrorOnly(bm.controller, "manager_bucket_flush_bucket", func(provider bucketManagementProvider) error {
-		if opts == nil {
-			opts = &FlushBucketOptions{}
+	if opts == nil {
+		opts = &FlushBucketOptions{}
+	}
+	if !opts.WaitUntilEmpty {
+		return autoOpControlErrorOnly(bm.controller, "manager_bucket_flush_bucket", func(provider bucketManagementProvider) error {
+			return provider.FlushBucket(name, opts)
+		})
+	}
+
+	ctx, cancel := ensureContext(opts.Context, opts.Timeout)
+	defer cancel()
+
+	err := autoOpControlErrorOnly(bm.controller, "manager_bucket_flush_bucket", func(provider bucketManagementProvider) error {
+		return provider.FlushBucket(name, &FlushBucketOptions{
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       ctx,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return PollUntil(ctx, 0, func(ctx context.Context) (bool, error) {
+		itemCount, err := autoOpControl(bm.controller, "manager_bucket_get_item_count", func(provider bucketManagementProvider) (uint64, error) {
+			return provider.GetItemCount(name, &GetBucketOptions{
+				RetryStrategy: opts.RetryStrategy,
+				ParentSpan:    opts.ParentSpan,
+				Context:       ctx,
+			})
+		})
+		if err != nil {
+			return false, err
+		}
+
+		if opts.OnProgress != nil {
+			opts.OnProgress(FlushBucketProgress{ItemCount: itemCount})
 		}
 
-		return provider.FlushBucket(name, opts)
+		return itemCount == 0, nil
+	}, &PollUntilOptions{
+		ParentSpan: opts.ParentSpan,
 	})
 }
+
+// EnsureBucketOptions is the set of options available to the bucket manager EnsureBucket operation.
+// UNCOMMITTED: This API may change in the future.
+type EnsureBucketOptions struct {
+	// UpdateIfDifferent updates the bucket if it already exists but any of the updatable settings differ. Only
+	// settings which are set are compared.
+	UpdateIfDifferent bool
+	// NumReplicas, FlushEnabled and ReplicaIndexDisabled take precedence over the values in the settings, whose zero
+	// values cannot be told apart from the values not being set. They are used both when the bucket is created and
+	// when it is updated because UpdateIfDifferent is set. Nil uses the value in the settings when creating the
+	// bucket, and leaves the existing value of the bucket unchanged when updating it.
+	NumReplicas          *uint32
+	FlushEnabled         *bool
+	ReplicaIndexDisabled *bool
+	// NoWait returns as soon as the bucket has been created or updated, rather than waiting until the bucket is
+	// ready for key value operations.
+	NoWait bool
+
+	// Timeout bounds the entire operation, including waiting for the bucket to become ready.
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation.
+	Context context.Context
+}
+
+// EnsureBucket creates the bucket if it does not already exist, and then waits until it is ready for key value
+// operations.
+// UNCOMMITTED: This API may change in the future.
+func (bm *BucketManager) EnsureBucket(settings CreateBucketSettings, opts *EnsureBucketOptions) error {
+	if opts == nil {
+		opts = &EnsureBucketOptions{}
+	}
+	if settings.Name == "" {
+		return makeInvalidArgumentsError("bucket name cannot be empty")
+	}
+
+	ctx, cancel := ensureContext(opts.Context, opts.Timeout)
+	defer cancel()
+
+	existing, err := bm.GetBucket(settings.Name, &GetBucketOptions{
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       ctx,
+	})
+	switch {
+	case errors.Is(err, ErrBucketNotFound):
+		err = bm.CreateBucket(withEnsureBucketOptions(settings, opts), &CreateBucketOptions{
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       ctx,
+		})
+		if err != nil && !errors.Is(err, ErrBucketExists) {
+			return err
+		}
+	case err != nil:
+		return err
+	case opts.UpdateIfDifferent:
+		if merged, changed := mergeBucketSettings(*existing, settings.BucketSettings, opts); changed {
+			err = bm.UpdateBucket(merged, &UpdateBucketOptions{
+				RetryStrategy: opts.RetryStrategy,
+				ParentSpan:    opts.ParentSpan,
+				Context:       ctx,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if opts.NoWait || bm.waitUntilReady == nil {
+		return nil
+	}
+
+	deadline, _ := ctx.Deadline()
+	return bm.waitUntilReady(settings.Name, time.Until(deadline), &WaitUntilReadyOptions{
+		ServiceTypes:  []ServiceType{ServiceTypeKeyValue},
+		Context:       ctx,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+	})
+}
+
+// withEnsureBucketOptions applies the settings which are set in opts to the settings used to create the bucket.
+func withEnsureBucketOptions(settings CreateBucketSettings, opts *EnsureBucketOptions) CreateBucketSettings {
+	if opts.FlushEnabled != nil {
+		settings.FlushEnabled = *opts.FlushEnabled
+	}
+	if opts.ReplicaIndexDisabled != nil {
+		settings.ReplicaIndexDisabled = *opts.ReplicaIndexDisabled
+	}
+	if opts.NumReplicas != nil {
+		settings.NumReplicas = *opts.NumReplicas
+	}
+
+	return settings
+}
+
+// mergeBucketSettings overlays the updatable settings of desired onto existing, so that settings which were not
+// specified are left unchanged by an update. Settings which cannot be changed once a bucket has been created, such as
+// the bucket type, are ignored. The settings whose zero values are valid are only taken from opts.
+func mergeBucketSettings(existing, desired BucketSettings, opts *EnsureBucketOptions) (BucketSettings, bool) {
+	merged := existing
+	if opts.FlushEnabled != nil {
+		merged.FlushEnabled = *opts.FlushEnabled
+	}
+	if opts.ReplicaIndexDisabled != nil {
+		merged.ReplicaIndexDisabled = *opts.ReplicaIndexDisabled
+	}
+	if opts.NumReplicas != nil {
+		merged.NumReplicas = *opts.NumReplicas
+	}
+	if desired.RAMQuotaMB > 0 {
+		merged.RAMQuotaMB = desired.RAMQuotaMB
+	}
+	if desired.EvictionPolicy != "" {
+		merged.EvictionPolicy = desired.EvictionPolicy
+	}
+	if desired.MaxExpiry > 0 {
+		merged.MaxExpiry = desired.MaxExpiry
+	}
+	if desired.CompressionMode != "" {
+		merged.CompressionMode = desired.CompressionMode
+	}
+	if desired.MinimumDurabilityLevel != DurabilityLevelUnknown {
+		merged.MinimumDurabilityLevel = desired.MinimumDurabilityLevel
+	}
+	if desired.HistoryRetentionCollectionDefault != HistoryRetentionCollectionDefaultUnset {
+		merged.HistoryRetentionCollectionDefault = desired.HistoryRetentionCollectionDefault
+	}
+	if desired.HistoryRetentionBytes > 0 {
+		merged.HistoryRetentionBytes = desired.HistoryRetentionBytes
+	}
+	if desired.HistoryRetentionDuration > 0 {
+		merged.HistoryRetentionDuration = desired.HistoryRetentionDuration
+	}
+	if desired.Rank > 0 {
+		merged.Rank = desired.Rank
+	}
+
+	return merged, merged != existing
+}
@@ -0,0 +1,75 @@
+package gocb
+
+import (
+	"context"
+	"time"
+)
+
+// BucketCompactionStatus describes the compaction of a bucket.
+// UNCOMMITTED: This API may change in the future.
+type BucketCompactionStatus struct {
+	// Running indicates whether the bucket is currently being compacted.
+	Running bool
+
+	// Progress is the percentage of the running compaction which has completed.
+	Progress float64
+}
+
+// CompactBucketOptions is the set of options available to the bucket manager CompactBucket operation.
+// UNCOMMITTED: This API may change in the future.
+type CompactBucketOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// CompactBucket starts compacting the data of a bucket. Compaction runs in the background, use GetCompactionStatus
+// to follow its progress.
+// UNCOMMITTED: This API may change in the future.
+func (bm *BucketManager) CompactBucket(name string, opts *CompactBucketOptions) error {
+	return autoOpControlErrorOnly(bm.controller, "manager_bucket_compact_bucket", func(provider bucketManagementProvider) error {
+		if name == "" {
+			return makeInvalidArgumentsError("bucket name cannot be empty")
+		}
+
+		if opts == nil {
+			opts = &CompactBucketOptions{}
+		}
+
+		return provider.CompactBucket(name, opts)
+	})
+}
+
+// GetCompactionStatusOptions is the set of options available to the bucket manager GetCompactionStatus operation.
+// UNCOMMITTED: This API may change in the future.
+type GetCompactionStatusOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// GetCompactionStatus returns whether a bucket is being compacted, and the progress of the compaction.
+// UNCOMMITTED: This API may change in the future.
+func (bm *BucketManager) GetCompactionStatus(name string, opts *GetCompactionStatusOptions) (*BucketCompactionStatus, error) {
+	return autoOpControl(bm.controller, "manager_bucket_get_compaction_status", func(provider bucketManagementProvider) (*BucketCompactionStatus, error) {
+		if name == "" {
+			return nil, makeInvalidArgumentsError("bucket name cannot be empty")
+		}
+
+		if opts == nil {
+			opts = &GetCompactionStatusOptions{}
+		}
+
+		return provider.GetCompactionStatus(name, opts)
+	})
+}
@@ -1,11 +1,17 @@
 package gocb
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 )
 
 func (suite *IntegrationTestSuite) TestBucketMgrOps() {
@@ -586,3 +592,247 @@ func (suite *IntegrationTestSuite) TestBucketMgrHistoryRetention() {
 
 	suite.Assert().Equal(HistoryRetentionCollectionDefaultDisabled, b.HistoryRetentionCollectionDefault)
 }
+
+func (suite *UnitTestSuite) TestMergeBucketSettings() {
+	existing := BucketSettings{
+		Name:           "test",
+		RAMQuotaMB:     100,
+		NumReplicas:    1,
+		BucketType:     CouchbaseBucketType,
+		EvictionPolicy: EvictionPolicyTypeValueOnly,
+		MaxExpiry:      time.Hour,
+	}
+
+	merged, changed := mergeBucketSettings(existing, BucketSettings{Name: "test", NumReplicas: 1}, &EnsureBucketOptions{})
+	suite.Assert().False(changed)
+	suite.Assert().Equal(existing, merged)
+
+	// Settings left at their zero values are not applied unless they are explicitly set.
+	merged, changed = mergeBucketSettings(existing, BucketSettings{Name: "test"}, &EnsureBucketOptions{})
+	suite.Assert().False(changed)
+	suite.Assert().Equal(existing, merged)
+
+	merged, changed = mergeBucketSettings(existing, BucketSettings{
+		Name:        "test",
+		RAMQuotaMB:  200,
+		NumReplicas: 1,
+		BucketType:  EphemeralBucketType,
+	}, &EnsureBucketOptions{})
+	suite.Assert().True(changed)
+	suite.Assert().Equal(uint64(200), merged.RAMQuotaMB)
+	suite.Assert().Equal(CouchbaseBucketType, merged.BucketType)
+	suite.Assert().Equal(time.Hour, merged.MaxExpiry)
+
+	numReplicas := uint32(0)
+	flushEnabled := true
+	merged, changed = mergeBucketSettings(existing, BucketSettings{Name: "test"}, &EnsureBucketOptions{
+		NumReplicas:  &numReplicas,
+		FlushEnabled: &flushEnabled,
+	})
+	suite.Assert().True(changed)
+	suite.Assert().Equal(uint32(0), merged.NumReplicas)
+	suite.Assert().True(merged.FlushEnabled)
+	suite.Assert().False(merged.ReplicaIndexDisabled)
+}
+
+func (suite *UnitTestSuite) TestWithEnsureBucketOptions() {
+	settings := CreateBucketSettings{
+		BucketSettings: BucketSettings{
+			Name:                 "test",
+			NumReplicas:          2,
+			ReplicaIndexDisabled: true,
+		},
+	}
+
+	suite.Assert().Equal(settings, withEnsureBucketOptions(settings, &EnsureBucketOptions{}))
+
+	numReplicas := uint32(0)
+	flushEnabled := true
+	created := withEnsureBucketOptions(settings, &EnsureBucketOptions{
+		NumReplicas:  &numReplicas,
+		FlushEnabled: &flushEnabled,
+	})
+	suite.Assert().Equal(uint32(0), created.NumReplicas)
+	suite.Assert().True(created.FlushEnabled)
+	suite.Assert().True(created.ReplicaIndexDisabled)
+}
+
+func (suite *UnitTestSuite) bucketManagerWithResponses(responses map[string]string, runFn func(req mgmtRequest)) *BucketManager {
+	provider := new(mockMgmtProvider)
+	for key, body := range responses {
+		method, path, _ := strings.Cut(key, " ")
+		statusCode := 200
+		if method == "POST" && path == "/pools/default/buckets" {
+			statusCode = 202
+		}
+
+		provider.
+			On("executeMgmtRequest", nil, mock.MatchedBy(func(req mgmtRequest) bool {
+				return req.Method == method && req.Path == path
+			})).
+			Run(func(args mock.Arguments) {
+				if runFn != nil {
+					runFn(args.Get(1).(mgmtRequest))
+				}
+			}).
+			Return(&mgmtResponse{
+				StatusCode: uint32(statusCode),
+				Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+			}, nil)
+	}
+
+	return &BucketManager{
+		controller: &providerController[bucketManagementProvider]{
+			get: func() (bucketManagementProvider, error) {
+				return &bucketManagementProviderCore{
+					mgmtProvider: provider,
+					tracer:       newTracerWrapper(&NoopTracer{}),
+				}, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+}
+
+func (suite *UnitTestSuite) TestBucketMgrSettingsFeatureDetection() {
+	clusterCfg := func(compat int) string {
+		return fmt.Sprintf(`{"nodes":[{"clusterCompatibility":%d},{"clusterCompatibility":%d}]}`, compat+1, compat)
+	}
+
+	settings := BucketSettings{
+		Name:        "test",
+		RAMQuotaMB:  100,
+		BucketType:  CouchbaseBucketType,
+		Rank:        10,
+		NumVBuckets: 128,
+	}
+
+	var posted bool
+	mgr := suite.bucketManagerWithResponses(map[string]string{
+		"GET /pools/default": clusterCfg(clusterCompatVersion(7, 6)),
+	}, func(req mgmtRequest) {
+		posted = posted || req.Method == "POST"
+	})
+	err := mgr.CreateBucket(CreateBucketSettings{BucketSettings: settings}, nil)
+	suite.Assert().ErrorIs(err, ErrFeatureNotAvailable)
+	suite.Assert().False(posted)
+
+	var form url.Values
+	mgr = suite.bucketManagerWithResponses(map[string]string{
+		"GET /pools/default":          clusterCfg(clusterCompatVersion(8, 0)),
+		"POST /pools/default/buckets": "",
+	}, func(req mgmtRequest) {
+		if req.Method == "POST" {
+			form, err = url.ParseQuery(string(req.Body))
+			suite.Require().Nil(err, err)
+		}
+	})
+	err = mgr.CreateBucket(CreateBucketSettings{BucketSettings: settings}, nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("10", form.Get("rank"))
+	suite.Assert().Equal("128", form.Get("numVBuckets"))
+
+	settings.StorageBackend = StorageBackendMagma
+	settings.Rank = 0
+	settings.NumVBuckets = 0
+	mgr = suite.bucketManagerWithResponses(map[string]string{
+		"GET /pools/default":              clusterCfg(clusterCompatVersion(7, 2)),
+		"GET /pools/default/buckets/test": `{"name":"test","bucketType":"membase","storageBackend":"couchstore"}`,
+	}, nil)
+	err = mgr.UpdateBucket(settings, nil)
+	suite.Assert().ErrorIs(err, ErrFeatureNotAvailable)
+}
+
+func (suite *UnitTestSuite) TestBucketMgrUpdateBucketNumVBuckets() {
+	settings := BucketSettings{
+		Name:        "test",
+		RAMQuotaMB:  100,
+		BucketType:  CouchbaseBucketType,
+		NumVBuckets: 128,
+	}
+
+	var posted bool
+	newManager := func() *BucketManager {
+		return suite.bucketManagerWithResponses(map[string]string{
+			"GET /pools/default/buckets/test":  `{"name":"test","bucketType":"membase","numVBuckets":1024}`,
+			"POST /pools/default/buckets/test": "",
+		}, func(req mgmtRequest) {
+			posted = posted || req.Method == "POST"
+		})
+	}
+	err := newManager().UpdateBucket(settings, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+	suite.Assert().False(posted)
+
+	settings.NumVBuckets = 1024
+	err = newManager().UpdateBucket(settings, nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().True(posted)
+}
+
+func (suite *UnitTestSuite) TestBucketMgrFlushBucketWaitUntilEmpty() {
+	provider := new(mockMgmtProvider)
+	matchRequest := func(method, path string) interface{} {
+		return mock.MatchedBy(func(req mgmtRequest) bool {
+			return req.Method == method && req.Path == path
+		})
+	}
+	itemCountResponse := func(count int) *mgmtResponse {
+		return &mgmtResponse{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewReader([]byte(fmt.Sprintf(`{"basicStats":{"itemCount":%d}}`, count)))),
+		}
+	}
+
+	provider.On("executeMgmtRequest", mock.Anything, matchRequest("POST", "/pools/default/buckets/test/controller/doFlush")).
+		Return(&mgmtResponse{StatusCode: 200, Body: io.NopCloser(bytes.NewReader(nil))}, nil).Once()
+	provider.On("executeMgmtRequest", mock.Anything, matchRequest("GET", "/pools/default/buckets/test")).
+		Return(itemCountResponse(5), nil).Once()
+	provider.On("executeMgmtRequest", mock.Anything, matchRequest("GET", "/pools/default/buckets/test")).
+		Return(itemCountResponse(0), nil).Once()
+
+	mgr := &BucketManager{
+		controller: &providerController[bucketManagementProvider]{
+			get: func() (bucketManagementProvider, error) {
+				return &bucketManagementProviderCore{
+					mgmtProvider: provider,
+					tracer:       newTracerWrapper(&NoopTracer{}),
+				}, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+
+	var progress []FlushBucketProgress
+	err := mgr.FlushBucket("test", &FlushBucketOptions{
+		WaitUntilEmpty: true,
+		Timeout:        5 * time.Second,
+		OnProgress: func(p FlushBucketProgress) {
+			progress = append(progress, p)
+		},
+	})
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal([]FlushBucketProgress{{ItemCount: 5}, {ItemCount: 0}}, progress)
+	provider.AssertExpectations(suite.T())
+}
+
+func (suite *UnitTestSuite) TestBucketMgrGetCompactionStatus() {
+	tasks := `[{"type":"rebalance","status":"notRunning"},
+		{"type":"bucket_compaction","bucket":"other","status":"running","progress":10},
+		{"type":"bucket_compaction","bucket":"test","status":"running","progress":42.5}]`
+	mgr := suite.bucketManagerWithResponses(map[string]string{
+		"GET /pools/default/tasks": tasks,
+	}, nil)
+
+	status, err := mgr.GetCompactionStatus("test", nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(&BucketCompactionStatus{Running: true, Progress: 42.5}, status)
+
+	mgr = suite.bucketManagerWithResponses(map[string]string{
+		"GET /pools/default/tasks": `[{"type":"rebalance","status":"notRunning"}]`,
+	}, nil)
+
+	status, err = mgr.GetCompactionStatus("test", nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(&BucketCompactionStatus{}, status)
+}
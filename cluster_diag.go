@@ -59,9 +59,14 @@ func (report *DiagnosticsResult) MarshalJSON() ([]byte, error) {
 			serviceStr := serviceTypeToString(service.Type)
 			stateStr := endpointStateToString(service.State)
 
+			var lastActivityUs uint64
+			if !service.LastActivity.IsZero() {
+				lastActivityUs = uint64(time.Since(service.LastActivity) / time.Microsecond)
+			}
+
 			jsonReport.Services[serviceStr] = append(jsonReport.Services[serviceStr], jsonDiagnosticEntry{
 				ID:             service.ID,
-				LastActivityUs: uint64(time.Since(service.LastActivity).Nanoseconds()),
+				LastActivityUs: lastActivityUs,
 				Remote:         service.Remote,
 				Local:          service.Local,
 				State:          stateStr,
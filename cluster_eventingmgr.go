@@ -111,6 +111,7 @@ var (
 )
 
 // EventingFunctionSettings are the settings for an EventingFunction.
+// QueryConsistency only supports QueryScanConsistencyNotBounded and QueryScanConsistencyRequestPlus.
 type EventingFunctionSettings struct {
 	CPPWorkerThreadCount   int
 	DCPStreamBoundary      EventingFunctionDCPBoundary
@@ -141,6 +142,9 @@ type EventingFunctionSettings struct {
 	AppLogMaxSize          int
 	AppLogMaxFiles         int
 	CheckpointInterval     time.Duration
+	// OnDeployTimeout is the maximum time that the OnDeploy handler of the function may run for.
+	// UNCOMMITTED: This API may change in the future.
+	OnDeployTimeout time.Duration
 }
 
 // EventingFunctionBucketAccess represents the level of access an eventing function has to a bucket.
@@ -259,11 +263,7 @@ type EventingFunctionConstantBinding struct {
 }
 
 // EventingFunctionKeyspace represents a triple of bucket, collection, and scope names.
-type EventingFunctionKeyspace struct {
-	Bucket     string
-	Scope      string
-	Collection string
-}
+type EventingFunctionKeyspace = Keyspace
 
 // EventingStatus represents the current state of all eventing functions.
 type EventingStatus struct {
@@ -350,8 +350,14 @@ type UpsertEventingFunctionOptions struct {
 }
 
 // UpsertFunction inserts or updates an eventing function.
+// If the Version or Settings.LanguageCompatibility of the function are not set then they are set to those supported
+// by the oldest eventing node in the cluster, where this can be determined.
 func (efm *EventingFunctionManager) UpsertFunction(function EventingFunction, opts *UpsertEventingFunctionOptions) error {
 	return autoOpControlErrorOnly(efm.controller, "manager_eventing_upsert_function", func(provider eventingManagementProvider) error {
+		if err := validateEventingFunctionSettings(function.Settings); err != nil {
+			return err
+		}
+
 		if opts == nil {
 			opts = &UpsertEventingFunctionOptions{}
 		}
@@ -543,3 +549,485 @@ func (efm *EventingFunctionManager) FunctionsStatus(opts *EventingFunctionsStatu
 		return provider.FunctionsStatus(nil, opts)
 	})
 }
+
+// ExportEventingFunctionsOptions are the options available when using the ExportFunctions operation.
+// UNCOMMITTED: This API may change in the future.
+type ExportEventingFunctionsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// ExportFunctions fetches the definitions of all eventing functions which are not in a scope, as exported by the
+// eventing service. Functions in a scope are exported by ScopeEventingFunctionManager.ExportFunctions.
+// Each function is returned exactly as exported, including any fields which are not known to this SDK, and
+// marshalling the returned functions to JSON produces the eventing service's export format. They can be passed
+// back to ImportFunctions.
+// UNCOMMITTED: This API may change in the future.
+func (efm *EventingFunctionManager) ExportFunctions(opts *ExportEventingFunctionsOptions) ([]json.RawMessage, error) {
+	return autoOpControl(efm.controller, "manager_eventing_export_functions", func(provider eventingManagementProvider) ([]json.RawMessage, error) {
+		if opts == nil {
+			opts = &ExportEventingFunctionsOptions{}
+		}
+
+		return provider.ExportFunctions(nil, opts)
+	})
+}
+
+// ImportEventingFunctionsOptions are the options available when using the ImportFunctions operation.
+// UNCOMMITTED: This API may change in the future.
+type ImportEventingFunctionsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// ImportFunctions creates or replaces eventing functions from their definitions, such as those returned by
+// ExportFunctions. Imported functions are always created in an undeployed state.
+// UNCOMMITTED: This API may change in the future.
+func (efm *EventingFunctionManager) ImportFunctions(functions []json.RawMessage, opts *ImportEventingFunctionsOptions) error {
+	return autoOpControlErrorOnly(efm.controller, "manager_eventing_import_functions", func(provider eventingManagementProvider) error {
+		if len(functions) == 0 {
+			return makeInvalidArgumentsError("at least one function must be provided")
+		}
+
+		if opts == nil {
+			opts = &ImportEventingFunctionsOptions{}
+		}
+
+		return provider.ImportFunctions(nil, functions, opts)
+	})
+}
+
+// ValidateEventingFunctionOptions are the options available when using the ValidateFunction operation.
+// UNCOMMITTED: This API may change in the future.
+type ValidateEventingFunctionOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// ValidateFunction asks the eventing service to compile an eventing function without storing it, returning
+// ErrEventingFunctionCompilationFailure if the code fails to compile, or ErrFeatureNotAvailable if the eventing
+// service does not support validation.
+// If the Version or Settings.LanguageCompatibility of the function are not set then they are set to those supported
+// by the oldest eventing node in the cluster, where this can be determined.
+// UNCOMMITTED: This API may change in the future.
+func (efm *EventingFunctionManager) ValidateFunction(function EventingFunction, opts *ValidateEventingFunctionOptions) error {
+	return autoOpControlErrorOnly(efm.controller, "manager_eventing_validate_function", func(provider eventingManagementProvider) error {
+		if function.Name == "" {
+			return makeInvalidArgumentsError("function name cannot be empty")
+		}
+
+		if opts == nil {
+			opts = &ValidateEventingFunctionOptions{}
+		}
+
+		return provider.ValidateFunction(nil, function, opts)
+	})
+}
+
+// UpdateEventingFunctionSettingsOptions are the options available when using the UpdateFunctionSettings operation.
+// UNCOMMITTED: This API may change in the future.
+type UpdateEventingFunctionSettingsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// UpdateFunctionSettings updates the settings of an existing eventing function, without changing its code or
+// bindings. The settings are typically those returned by GetFunction, modified as required. Zero valued numeric,
+// string and duration settings leave the existing setting unchanged, whilst boolean settings are always applied.
+// DeploymentStatus and ProcessingStatus are ignored, DeployFunction, UndeployFunction, PauseFunction and
+// ResumeFunction should be used to change them instead.
+// UNCOMMITTED: This API may change in the future.
+func (efm *EventingFunctionManager) UpdateFunctionSettings(name string, settings EventingFunctionSettings,
+	opts *UpdateEventingFunctionSettingsOptions) error {
+	return autoOpControlErrorOnly(efm.controller, "manager_eventing_update_function_settings", func(provider eventingManagementProvider) error {
+		if name == "" {
+			return makeInvalidArgumentsError("function name cannot be empty")
+		}
+		if err := validateEventingFunctionSettings(settings); err != nil {
+			return err
+		}
+
+		if opts == nil {
+			opts = &UpdateEventingFunctionSettingsOptions{}
+		}
+
+		return provider.UpdateFunctionSettings(nil, name, settings, opts)
+	})
+}
+
+func validateEventingFunctionSettings(settings EventingFunctionSettings) error {
+	switch settings.QueryConsistency {
+	case 0, QueryScanConsistencyNotBounded, QueryScanConsistencyRequestPlus:
+	default:
+		return makeInvalidArgumentsError("unsupported query consistency")
+	}
+	if settings.WorkerCount < 0 || settings.CPPWorkerThreadCount < 0 || settings.TimerContextSize < 0 ||
+		settings.BucketCacheSize < 0 || settings.BucketCacheAge < 0 {
+		return makeInvalidArgumentsError("settings cannot be negative")
+	}
+
+	return nil
+}
+
+type jsonEventingFunction struct {
+	Name               string                               `json:"appname"`
+	Code               string                               `json:"appcode"`
+	Version            string                               `json:"version"`
+	EnforceSchema      bool                                 `json:"enforce_schema,omitempty"`
+	HandlerUUID        int                                  `json:"handleruuid,omitempty"`
+	FunctionInstanceID string                               `json:"function_instance_id,omitempty"`
+	Settings           jsonEventingFunctionSettings         `json:"settings"`
+	DeploymentConfig   jsonEventingFunctionDeploymentConfig `json:"depcfg"`
+	FunctionScope      *jsonEventingFunctionScope           `json:"function_scope,omitempty"`
+}
+
+type jsonEventingFunctionScope struct {
+	BucketName string `json:"bucket"`
+	ScopeName  string `json:"scope"`
+}
+
+type jsonEventingFunctionSettings struct {
+	CPPWorkerThreadCount   int                                   `json:"cpp_worker_thread_count,omitempty"`
+	DCPStreamBoundary      EventingFunctionDCPBoundary           `json:"dcp_stream_boundary,omitempty"`
+	Description            string                                `json:"description,omitempty"`
+	DeploymentStatus       EventingFunctionDeploymentStatus      `json:"deployment_status"`
+	ProcessingStatus       EventingFunctionProcessingStatus      `json:"processing_status"`
+	LanguageCompatibility  EventingFunctionLanguageCompatibility `json:"language_compatibility,omitempty"`
+	LogLevel               EventingFunctionLogLevel              `json:"log_level,omitempty"`
+	ExecutionTimeout       int                                   `json:"execution_timeout,omitempty"`
+	LCBInstCapacity        int                                   `json:"lcb_inst_capacity,omitempty"`
+	LCBRetryCount          int                                   `json:"lcb_retry_count,omitempty"`
+	LCBTimeout             int                                   `json:"lcb_timeout,omitempty"`
+	QueryConsistency       string                                `json:"n1ql_consistency,omitempty"`
+	NumTimerPartitions     int                                   `json:"num_timer_partitions,omitempty"`
+	SockBatchSize          int                                   `json:"sock_batch_size,omitempty"`
+	TickDuration           int                                   `json:"tick_duration,omitempty"`
+	TimerContextSize       int                                   `json:"timer_context_size,omitempty"`
+	UserPrefix             string                                `json:"user_prefix,omitempty"`
+	BucketCacheSize        int                                   `json:"bucket_cache_size,omitempty"`
+	BucketCacheAge         int                                   `json:"bucket_cache_age,omitempty"`
+	CurlMaxAllowedRespSize int                                   `json:"curl_max_allowed_resp_size,omitempty"`
+	QueryPrepareAll        bool                                  `json:"n1ql_prepare_all,omitempty"`
+	WorkerCount            int                                   `json:"worker_count,omitempty"`
+	HandlerHeaders         []string                              `json:"handler_headers,omitempty"`
+	HandlerFooters         []string                              `json:"handler_footers,omitempty"`
+	EnableAppLogRotation   bool                                  `json:"enable_applog_rotation,omitempty"`
+	AppLogDir              string                                `json:"app_log_dir,omitempty"`
+	AppLogMaxSize          int                                   `json:"app_log_max_size,omitempty"`
+	AppLogMaxFiles         int                                   `json:"app_log_max_files,omitempty"`
+	CheckpointInterval     int                                   `json:"checkpoint_interval,omitempty"`
+	OnDeployTimeout        int                                   `json:"on_deploy_timeout,omitempty"`
+}
+
+// jsonEventingFunctionSettingsUpdate is the body used to update the settings of an existing function. The shadowing
+// fields omit the deployment and processing status, which are changed using the lifecycle operations, and always send
+// boolean settings so that they can be disabled.
+type jsonEventingFunctionSettingsUpdate struct {
+	jsonEventingFunctionSettings
+	DeploymentStatus     *bool `json:"deployment_status,omitempty"`
+	ProcessingStatus     *bool `json:"processing_status,omitempty"`
+	QueryPrepareAll      bool  `json:"n1ql_prepare_all"`
+	EnableAppLogRotation bool  `json:"enable_applog_rotation"`
+}
+
+type jsonEventingFunctionDeploymentConfig struct {
+	MetadataBucket     string                                `json:"metadata_bucket"`
+	MetadataScope      string                                `json:"metadata_scope,omitempty"`
+	MetadataCollection string                                `json:"metadata_collection,omitempty"`
+	SourceBucket       string                                `json:"source_bucket"`
+	SourceScope        string                                `json:"source_scope,omitempty"`
+	SourceCollection   string                                `json:"source_collection,omitempty"`
+	BucketBindings     []jsonEventingFunctionBucketBinding   `json:"buckets,omitempty"`
+	UrlBindings        []jsonEventingFunctionUrlBinding      `json:"curl,omitempty"`
+	ConstantBindings   []jsonEventingFunctionConstantBinding `json:"constants,omitempty"`
+}
+
+type jsonEventingFunctionBucketBinding struct {
+	Alias      string                       `json:"alias"`
+	Bucket     string                       `json:"bucket_name"`
+	Scope      string                       `json:"scope_name,omitempty"`
+	Collection string                       `json:"collection_name,omitempty"`
+	Access     EventingFunctionBucketAccess `json:"access"`
+}
+
+type jsonEventingFunctionUrlBinding struct {
+	Hostname               string `json:"hostname"`
+	Alias                  string `json:"value"`
+	AuthType               string `json:"auth_type"`
+	AllowCookies           bool   `json:"allow_cookies"`
+	ValidateSSLCertificate bool   `json:"validate_ssl_certificate"`
+	Username               string `json:"username,omitempty"`
+	Password               string `json:"password,omitempty"`
+	BearerKey              string `json:"bearer_key,omitempty"`
+}
+
+type jsonEventingFunctionConstantBinding struct {
+	Alias   string `json:"value"`
+	Literal string `json:"literal"`
+}
+
+type jsonEventingFunctionsStatusApp struct {
+	CompositeStatus       EventingFunctionStatus           `json:"composite_status"`
+	Name                  string                           `json:"name"`
+	NumBootstrappingNodes int                              `json:"num_bootstrapping_nodes"`
+	NumDeployedNodes      int                              `json:"num_deployed_nodes"`
+	DeploymentStatus      EventingFunctionDeploymentStatus `json:"deployment_status"`
+	ProcessingStatus      EventingFunctionProcessingStatus `json:"processing_status"`
+	FunctionScope         *jsonEventingFunctionScope       `json:"function_scope,omitempty"`
+}
+
+type jsonEventingFunctionsStatus struct {
+	Apps             []jsonEventingFunctionsStatusApp `json:"apps"`
+	NumEventingNodes int                              `json:"num_eventing_nodes"`
+}
+
+func (jf *jsonEventingFunction) MatchesScope(scope *Scope) bool {
+	if scope == nil {
+		return jf.FunctionScope == nil || (jf.FunctionScope.ScopeName == "*" && jf.FunctionScope.BucketName == "*")
+	}
+
+	return jf.FunctionScope != nil && jf.FunctionScope.ScopeName == scope.Name() && jf.FunctionScope.BucketName == scope.BucketName()
+}
+
+func (jfs *jsonEventingFunctionsStatusApp) MatchesScope(scope *Scope) bool {
+	if scope == nil {
+		return jfs.FunctionScope == nil || (jfs.FunctionScope.ScopeName == "*" && jfs.FunctionScope.BucketName == "*")
+	}
+
+	return jfs.FunctionScope != nil && jfs.FunctionScope.ScopeName == scope.Name() && jfs.FunctionScope.BucketName == scope.BucketName()
+}
+
+func (s EventingFunctionSettings) toJSONEventingFunctionSettings() jsonEventingFunctionSettings {
+	return jsonEventingFunctionSettings{
+		CPPWorkerThreadCount:   s.CPPWorkerThreadCount,
+		DCPStreamBoundary:      s.DCPStreamBoundary,
+		Description:            s.Description,
+		DeploymentStatus:       s.DeploymentStatus,
+		ProcessingStatus:       s.ProcessingStatus,
+		LanguageCompatibility:  s.LanguageCompatibility,
+		LogLevel:               s.LogLevel,
+		ExecutionTimeout:       int(s.ExecutionTimeout.Seconds()),
+		LCBInstCapacity:        s.LCBInstCapacity,
+		LCBRetryCount:          s.LCBRetryCount,
+		LCBTimeout:             int(s.LCBTimeout.Seconds()),
+		QueryConsistency:       eventingQueryConsistencyToJSON(s.QueryConsistency),
+		NumTimerPartitions:     s.NumTimerPartitions,
+		SockBatchSize:          s.SockBatchSize,
+		TickDuration:           int(s.TickDuration.Milliseconds()),
+		TimerContextSize:       s.TimerContextSize,
+		UserPrefix:             s.UserPrefix,
+		BucketCacheSize:        s.BucketCacheSize,
+		BucketCacheAge:         s.BucketCacheAge,
+		CurlMaxAllowedRespSize: s.CurlMaxAllowedRespSize,
+		QueryPrepareAll:        s.QueryPrepareAll,
+		WorkerCount:            s.WorkerCount,
+		HandlerHeaders:         s.HandlerHeaders,
+		HandlerFooters:         s.HandlerFooters,
+		EnableAppLogRotation:   s.EnableAppLogRotation,
+		AppLogDir:              s.AppLogDir,
+		AppLogMaxSize:          s.AppLogMaxSize,
+		AppLogMaxFiles:         s.AppLogMaxFiles,
+		CheckpointInterval:     int(s.CheckpointInterval.Seconds()),
+		OnDeployTimeout:        int(s.OnDeployTimeout.Seconds()),
+	}
+}
+
+func (js jsonEventingFunctionSettings) toEventingFunctionSettings() EventingFunctionSettings {
+	return EventingFunctionSettings{
+		CPPWorkerThreadCount:   js.CPPWorkerThreadCount,
+		DCPStreamBoundary:      js.DCPStreamBoundary,
+		Description:            js.Description,
+		DeploymentStatus:       js.DeploymentStatus,
+		ProcessingStatus:       js.ProcessingStatus,
+		LanguageCompatibility:  js.LanguageCompatibility,
+		LogLevel:               js.LogLevel,
+		ExecutionTimeout:       time.Duration(js.ExecutionTimeout) * time.Second,
+		LCBInstCapacity:        js.LCBInstCapacity,
+		LCBRetryCount:          js.LCBRetryCount,
+		LCBTimeout:             time.Duration(js.LCBTimeout) * time.Second,
+		QueryConsistency:       eventingQueryConsistencyFromJSON(js.QueryConsistency),
+		NumTimerPartitions:     js.NumTimerPartitions,
+		SockBatchSize:          js.SockBatchSize,
+		TickDuration:           time.Duration(js.TickDuration) * time.Millisecond,
+		TimerContextSize:       js.TimerContextSize,
+		UserPrefix:             js.UserPrefix,
+		BucketCacheSize:        js.BucketCacheSize,
+		BucketCacheAge:         js.BucketCacheAge,
+		CurlMaxAllowedRespSize: js.CurlMaxAllowedRespSize,
+		QueryPrepareAll:        js.QueryPrepareAll,
+		WorkerCount:            js.WorkerCount,
+		HandlerHeaders:         js.HandlerHeaders,
+		HandlerFooters:         js.HandlerFooters,
+		EnableAppLogRotation:   js.EnableAppLogRotation,
+		AppLogDir:              js.AppLogDir,
+		AppLogMaxSize:          js.AppLogMaxSize,
+		AppLogMaxFiles:         js.AppLogMaxFiles,
+		CheckpointInterval:     time.Duration(js.CheckpointInterval) * time.Second,
+		OnDeployTimeout:        time.Duration(js.OnDeployTimeout) * time.Second,
+	}
+}
+
+// eventingQueryConsistencyToJSON converts a scan consistency to the values used by the eventing service, which differ
+// from those used by the query service.
+func eventingQueryConsistencyToJSON(consistency QueryScanConsistency) string {
+	switch consistency {
+	case QueryScanConsistencyNotBounded:
+		return "none"
+	case QueryScanConsistencyRequestPlus:
+		return "request"
+	default:
+		return ""
+	}
+}
+
+func eventingQueryConsistencyFromJSON(consistency string) QueryScanConsistency {
+	switch consistency {
+	case "none":
+		return QueryScanConsistencyNotBounded
+	case "request":
+		return QueryScanConsistencyRequestPlus
+	default:
+		return 0
+	}
+}
+
+func (ef *EventingFunction) toJSONEventingFunction() jsonEventingFunction {
+	var bucketBindings []jsonEventingFunctionBucketBinding
+	for _, b := range ef.BucketBindings {
+		bucketBindings = append(bucketBindings, jsonEventingFunctionBucketBinding{
+			Alias:      b.Alias,
+			Bucket:     b.Name.Bucket,
+			Scope:      b.Name.Scope,
+			Collection: b.Name.Collection,
+			Access:     b.Access,
+		})
+	}
+	var urlBindings []jsonEventingFunctionUrlBinding
+	for _, b := range ef.UrlBindings {
+		urlBindings = append(urlBindings, jsonEventingFunctionUrlBinding{
+			Hostname:               b.Hostname,
+			Alias:                  b.Alias,
+			AuthType:               b.Auth.Method(),
+			AllowCookies:           b.AllowCookies,
+			ValidateSSLCertificate: b.ValidateSSLCertificate,
+			Username:               b.Auth.Username(),
+			Password:               b.Auth.Password(),
+			BearerKey:              b.Auth.Key(),
+		})
+	}
+	var constantBindings []jsonEventingFunctionConstantBinding
+	for _, b := range ef.ConstantBindings {
+		constantBindings = append(constantBindings, jsonEventingFunctionConstantBinding(b))
+	}
+
+	return jsonEventingFunction{
+		Name:               ef.Name,
+		Code:               ef.Code,
+		Version:            ef.Version,
+		EnforceSchema:      ef.EnforceSchema,
+		HandlerUUID:        ef.HandlerUUID,
+		FunctionInstanceID: ef.FunctionInstanceID,
+		Settings:           ef.Settings.toJSONEventingFunctionSettings(),
+		DeploymentConfig: jsonEventingFunctionDeploymentConfig{
+			MetadataBucket:     ef.MetadataKeyspace.Bucket,
+			MetadataScope:      ef.MetadataKeyspace.Scope,
+			MetadataCollection: ef.MetadataKeyspace.Collection,
+			SourceBucket:       ef.SourceKeyspace.Bucket,
+			SourceScope:        ef.SourceKeyspace.Scope,
+			SourceCollection:   ef.SourceKeyspace.Collection,
+			BucketBindings:     bucketBindings,
+			UrlBindings:        urlBindings,
+			ConstantBindings:   constantBindings,
+		},
+	}
+}
+
+func (ef *EventingFunction) fromJSONEventingFunction(jf jsonEventingFunction) {
+	var bucketBindings []EventingFunctionBucketBinding
+	for _, b := range jf.DeploymentConfig.BucketBindings {
+		bucketBindings = append(bucketBindings, EventingFunctionBucketBinding{
+			Alias: b.Alias,
+			Name: EventingFunctionKeyspace{
+				Bucket:     b.Bucket,
+				Scope:      b.Scope,
+				Collection: b.Collection,
+			},
+			Access: b.Access,
+		})
+	}
+	var urlBindings []EventingFunctionUrlBinding
+	for _, b := range jf.DeploymentConfig.UrlBindings {
+		var auth EventingFunctionUrlAuth
+		switch b.AuthType {
+		case "no-auth":
+			auth = EventingFunctionUrlNoAuth{}
+		case "basic":
+			auth = EventingFunctionUrlAuthBasic{
+				User: b.Username,
+			}
+		case "digest":
+			auth = EventingFunctionUrlAuthDigest{
+				User: b.Username,
+			}
+		case "bearer":
+			auth = EventingFunctionUrlAuthBearer{}
+		}
+
+		urlBindings = append(urlBindings, EventingFunctionUrlBinding{
+			Hostname:               b.Hostname,
+			Alias:                  b.Alias,
+			Auth:                   auth,
+			AllowCookies:           b.AllowCookies,
+			ValidateSSLCertificate: b.ValidateSSLCertificate,
+		})
+	}
+	var constantBindings []EventingFunctionConstantBinding
+	for _, b := range jf.DeploymentConfig.ConstantBindings {
+		constantBindings = append(constantBindings, EventingFunctionConstantBinding(b))
+	}
+
+	ef.Name = jf.Name
+	ef.Code = jf.Code
+	ef.Version = jf.Version
+	ef.EnforceSchema = jf.EnforceSchema
+	ef.HandlerUUID = jf.HandlerUUID
+	ef.FunctionInstanceID = jf.FunctionInstanceID
+	ef.Settings = jf.Settings.toEventingFunctionSettings()
+	ef.MetadataKeyspace = EventingFunctionKeyspace{
+		Bucket:     jf.DeploymentConfig.MetadataBucket,
+		Scope:      jf.DeploymentConfig.MetadataScope,
+		Collection: jf.DeploymentConfig.MetadataCollection,
+	}
+	ef.SourceKeyspace = EventingFunctionKeyspace{
+		Bucket:     jf.DeploymentConfig.SourceBucket,
+		Scope:      jf.DeploymentConfig.SourceScope,
+		Collection: jf.DeploymentConfig.SourceCollection,
+	}
+	ef.BucketBindings = bucketBindings
+	ef.UrlBindings = urlBindings
+	ef.ConstantBindings = constantBindings
+}
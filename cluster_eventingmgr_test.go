@@ -1,11 +1,18 @@
+//go:build !gocb_noeventing
+
 package gocb
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 )
 
 type eventingManager interface {
@@ -692,3 +699,276 @@ func (suite *IntegrationTestSuite) dropCollection(scope, collection string) {
 	err := cmgr.DropCollection(scope, collection, nil)
 	suite.Require().Nil(err, err)
 }
+
+func (suite *UnitTestSuite) eventingUpsertRequests(clusterBody string, function EventingFunction,
+	opts *UpsertEventingFunctionOptions) []mgmtRequest {
+	var reqs []mgmtRequest
+	mockProvider := new(mockMgmtProvider)
+	mockProvider.
+		On("executeMgmtRequest", nil, mock.AnythingOfType("mgmtRequest")).
+		Return(func(_ context.Context, req mgmtRequest) *mgmtResponse {
+			reqs = append(reqs, req)
+
+			body := "{}"
+			if req.Path == "/pools/default" {
+				body = clusterBody
+			}
+			return &mgmtResponse{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+			}
+		}, nil)
+
+	mgr := &EventingFunctionManager{
+		controller: &providerController[eventingManagementProvider]{
+			get: func() (eventingManagementProvider, error) {
+				return &eventingManagementProviderCore{
+					mgmtProvider: mockProvider,
+					tracer:       newTracerWrapper(&NoopTracer{}),
+				}, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+
+	err := mgr.UpsertFunction(function, opts)
+	suite.Require().Nil(err, err)
+
+	return reqs
+}
+
+func (suite *UnitTestSuite) TestEventingUpsertFunctionNegotiatesCompatibility() {
+	clusterBody := `{"nodes":[` +
+		`{"version":"7.6.0-2176-enterprise","services":["kv","eventing"]},` +
+		`{"version":"6.6.5-10080-enterprise","services":["eventing"]},` +
+		`{"version":"6.0.0-1693-enterprise","services":["kv"]}]}`
+
+	reqs := suite.eventingUpsertRequests(clusterBody, EventingFunction{Name: "test", Code: "function OnUpdate(doc, meta) {}"},
+		&UpsertEventingFunctionOptions{Timeout: time.Minute})
+	suite.Require().Len(reqs, 2)
+	suite.Assert().Equal("/pools/default", reqs[0].Path)
+	suite.Assert().Equal("/api/v1/functions/test", reqs[1].Path)
+
+	// The timeout is shared by both of the requests.
+	suite.Assert().False(reqs[0].Deadline.IsZero())
+	suite.Assert().Equal(reqs[0].Deadline, reqs[1].Deadline)
+
+	var function jsonEventingFunction
+	suite.Require().Nil(json.Unmarshal(reqs[1].Body, &function))
+	suite.Assert().Equal("evt-6.6.5-10080-ee", function.Version)
+	suite.Assert().Equal(EventingFunctionLanguageCompatibilityVersion662, function.Settings.LanguageCompatibility)
+}
+
+func (suite *UnitTestSuite) TestEventingUpsertFunctionExplicitCompatibility() {
+	reqs := suite.eventingUpsertRequests("", EventingFunction{
+		Name:    "test",
+		Version: "evt-7.2.0-5325-ee",
+		Settings: EventingFunctionSettings{
+			LanguageCompatibility: EventingFunctionLanguageCompatibilityVersion650,
+		},
+	}, nil)
+	suite.Require().Len(reqs, 1)
+
+	var function jsonEventingFunction
+	suite.Require().Nil(json.Unmarshal(reqs[0].Body, &function))
+	suite.Assert().Equal("evt-7.2.0-5325-ee", function.Version)
+	suite.Assert().Equal(EventingFunctionLanguageCompatibilityVersion650, function.Settings.LanguageCompatibility)
+}
+
+func (suite *UnitTestSuite) TestEventingUpsertFunctionCompatibilityUnavailable() {
+	reqs := suite.eventingUpsertRequests(`{"nodes":[{"version":"7.6.0-2176-enterprise","services":["kv"]}]}`,
+		EventingFunction{Name: "test"}, nil)
+	suite.Require().Len(reqs, 2)
+
+	var function jsonEventingFunction
+	suite.Require().Nil(json.Unmarshal(reqs[1].Body, &function))
+	suite.Assert().Empty(function.Version)
+	suite.Assert().Empty(function.Settings.LanguageCompatibility)
+}
+
+func (suite *UnitTestSuite) eventingProvider(statusCode int, respBody string, reqs *[]mgmtRequest) *eventingManagementProviderCore {
+	mockProvider := new(mockMgmtProvider)
+	mockProvider.
+		On("executeMgmtRequest", nil, mock.AnythingOfType("mgmtRequest")).
+		Return(func(_ context.Context, req mgmtRequest) *mgmtResponse {
+			*reqs = append(*reqs, req)
+
+			body := respBody
+			code := statusCode
+			if req.Path == "/pools/default" {
+				body = `{"nodes":[]}`
+				code = 200
+			}
+			return &mgmtResponse{
+				StatusCode: uint32(code),
+				Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+			}
+		}, nil)
+
+	return &eventingManagementProviderCore{
+		mgmtProvider: mockProvider,
+		tracer:       newTracerWrapper(&NoopTracer{}),
+	}
+}
+
+func (suite *UnitTestSuite) TestEventingExportFunctionsFiltersScope() {
+	body := `[` +
+		`{"appname":"global","appcode":"function OnUpdate(doc, meta) {}","function_scope":{"bucket":"*","scope":"*"}},` +
+		`{"appname":"scoped","appcode":"function OnUpdate(doc, meta) {}","function_scope":{"bucket":"travel-sample","scope":"inventory"},"unknown_field":1}]`
+
+	var reqs []mgmtRequest
+	provider := suite.eventingProvider(200, body, &reqs)
+	scope := suite.newScope(suite.bucket("travel-sample", suite.defaultTimeoutConfig(), nil), "inventory")
+
+	functions, err := provider.ExportFunctions(scope, nil)
+	suite.Require().Nil(err, err)
+	suite.Require().Len(functions, 1)
+	suite.Assert().JSONEq(`{"appname":"scoped","appcode":"function OnUpdate(doc, meta) {}",`+
+		`"function_scope":{"bucket":"travel-sample","scope":"inventory"},"unknown_field":1}`, string(functions[0]))
+
+	suite.Require().Len(reqs, 1)
+	suite.Assert().Equal("GET", reqs[0].Method)
+	suite.Assert().Equal("/api/v1/export", reqs[0].Path)
+
+	functions, err = provider.ExportFunctions(nil, nil)
+	suite.Require().Nil(err, err)
+	suite.Require().Len(functions, 1)
+	suite.Assert().Contains(string(functions[0]), `"appname":"global"`)
+}
+
+func (suite *UnitTestSuite) TestEventingImportFunctions() {
+	var reqs []mgmtRequest
+	provider := suite.eventingProvider(200, "[]", &reqs)
+	scope := suite.newScope(suite.bucket("travel-sample", suite.defaultTimeoutConfig(), nil), "inventory")
+
+	err := provider.ImportFunctions(scope, []json.RawMessage{
+		json.RawMessage(`{"appname":"one","appcode":"function OnUpdate(doc, meta) {}","unknown_field":1}`),
+		json.RawMessage(`{"appname":"two","appcode":"function OnDelete(meta) {}"}`),
+	}, nil)
+	suite.Require().Nil(err, err)
+
+	suite.Require().Len(reqs, 1)
+	suite.Assert().Equal("POST", reqs[0].Method)
+	suite.Assert().Equal("/api/v1/import", reqs[0].Path)
+
+	var functions []jsonEventingFunction
+	suite.Require().Nil(json.Unmarshal(reqs[0].Body, &functions))
+	suite.Require().Len(functions, 2)
+	suite.Assert().Equal("one", functions[0].Name)
+	suite.Assert().Equal("two", functions[1].Name)
+	suite.Assert().Contains(string(reqs[0].Body), `"unknown_field":1`)
+	for _, function := range functions {
+		suite.Require().NotNil(function.FunctionScope)
+		suite.Assert().Equal("travel-sample", function.FunctionScope.BucketName)
+		suite.Assert().Equal("inventory", function.FunctionScope.ScopeName)
+	}
+}
+
+func (suite *UnitTestSuite) TestEventingImportFunctionsNoFunctions() {
+	mgr := &EventingFunctionManager{
+		controller: &providerController[eventingManagementProvider]{
+			get: func() (eventingManagementProvider, error) {
+				return &eventingManagementProviderCore{}, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+
+	err := mgr.ImportFunctions(nil, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestEventingUpsertFunctionInvalidSettings() {
+	mgr := &EventingFunctionManager{
+		controller: &providerController[eventingManagementProvider]{
+			get: func() (eventingManagementProvider, error) {
+				return &eventingManagementProviderCore{}, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+
+	err := mgr.UpsertFunction(EventingFunction{
+		Name:     "test",
+		Settings: EventingFunctionSettings{WorkerCount: -1},
+	}, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	err = mgr.UpsertFunction(EventingFunction{
+		Name:     "test",
+		Settings: EventingFunctionSettings{QueryConsistency: QueryScanConsistency(10)},
+	}, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestEventingValidateFunctionCompilationFailure() {
+	var reqs []mgmtRequest
+	provider := suite.eventingProvider(422, `{"name":"ERR_HANDLER_COMPILATION","description":"handler compilation failed"}`, &reqs)
+
+	err := provider.ValidateFunction(nil, EventingFunction{
+		Name: "test",
+		Code: "function OnUpdate(doc, meta) {",
+	}, nil)
+	suite.Assert().ErrorIs(err, ErrEventingFunctionCompilationFailure)
+
+	suite.Require().Len(reqs, 2)
+	suite.Assert().Equal("/pools/default", reqs[0].Path)
+	suite.Assert().Equal("POST", reqs[1].Method)
+	suite.Assert().Equal("/api/v1/functions/test/validate", reqs[1].Path)
+}
+
+func (suite *UnitTestSuite) TestEventingValidateFunctionNotSupported() {
+	var reqs []mgmtRequest
+	provider := suite.eventingProvider(404, `404 page not found`, &reqs)
+
+	err := provider.ValidateFunction(nil, EventingFunction{
+		Name: "test",
+		Code: "function OnUpdate(doc, meta) {}",
+	}, nil)
+	suite.Assert().ErrorIs(err, ErrFeatureNotAvailable)
+}
+
+func (suite *UnitTestSuite) TestEventingUpdateFunctionSettings() {
+	var reqs []mgmtRequest
+	provider := suite.eventingProvider(200, "{}", &reqs)
+	scope := suite.newScope(suite.bucket("travel-sample", suite.defaultTimeoutConfig(), nil), "inventory")
+
+	err := provider.UpdateFunctionSettings(scope, "test", EventingFunctionSettings{
+		WorkerCount:      4,
+		LogLevel:         EventingFunctionLogLevelDebug,
+		QueryConsistency: QueryScanConsistencyRequestPlus,
+		DeploymentStatus: EventingFunctionDeploymentStatusDeployed,
+	}, nil)
+	suite.Require().Nil(err, err)
+
+	suite.Require().Len(reqs, 1)
+	suite.Assert().Equal("POST", reqs[0].Method)
+	suite.Assert().Equal("/api/v1/functions/test/settings?bucket=travel-sample&scope=inventory", reqs[0].Path)
+
+	var settings map[string]interface{}
+	suite.Require().Nil(json.Unmarshal(reqs[0].Body, &settings))
+	suite.Assert().Equal(float64(4), settings["worker_count"])
+	suite.Assert().Equal("DEBUG", settings["log_level"])
+	suite.Assert().Equal("request", settings["n1ql_consistency"])
+	suite.Assert().Equal(false, settings["n1ql_prepare_all"])
+	suite.Assert().NotContains(settings, "deployment_status")
+	suite.Assert().NotContains(settings, "processing_status")
+	suite.Assert().NotContains(settings, "timer_context_size")
+}
+
+func (suite *UnitTestSuite) TestEventingFunctionQueryConsistencyJSON() {
+	function := EventingFunction{
+		Name: "test",
+		Settings: EventingFunctionSettings{
+			QueryConsistency: QueryScanConsistencyNotBounded,
+		},
+	}
+
+	b, err := json.Marshal(&function)
+	suite.Require().Nil(err, err)
+	suite.Assert().Contains(string(b), `"n1ql_consistency":"none"`)
+
+	var decoded EventingFunction
+	suite.Require().Nil(json.Unmarshal(b, &decoded))
+	suite.Assert().Equal(QueryScanConsistencyNotBounded, decoded.Settings.QueryConsistency)
+}
@@ -0,0 +1,315 @@
+package gocb
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HealthEventType is the type of event emitted by a HealthMonitor.
+// UNCOMMITTED: This API may change in the future.
+type HealthEventType uint
+
+const (
+	// HealthEventEndpointDegraded indicates that an endpoint failed to respond successfully, or responded slower than
+	// the configured thresholds allow.
+	HealthEventEndpointDegraded HealthEventType = iota + 1
+
+	// HealthEventEndpointRecovered indicates that a previously degraded endpoint is healthy again.
+	HealthEventEndpointRecovered
+
+	// HealthEventNodeUnreachable indicates that none of the pinged endpoints on a node responded successfully.
+	HealthEventNodeUnreachable
+)
+
+// HealthEvent is emitted by a HealthMonitor when the health of an endpoint or node changes.
+// UNCOMMITTED: This API may change in the future.
+type HealthEvent struct {
+	Type HealthEventType
+	// Node is the hostname of the node that the event relates to.
+	Node string
+	// Service and Endpoint are the endpoint that the event relates to, they are not set for
+	// HealthEventNodeUnreachable.
+	Service  ServiceType
+	Endpoint EndpointPingReport
+	Time     time.Time
+}
+
+// HealthListener receives the events emitted by a HealthMonitor. Events are delivered in order on the monitor's
+// routine, so OnHealthEvent should not block. OnHealthEvent may call Stop on the monitor.
+// UNCOMMITTED: This API may change in the future.
+type HealthListener interface {
+	OnHealthEvent(event HealthEvent)
+}
+
+// HealthMonitorOptions is the set of options available when starting a HealthMonitor.
+// UNCOMMITTED: This API may change in the future.
+type HealthMonitorOptions struct {
+	// Interval is the time between each round of pings. Defaults to 30 seconds.
+	Interval time.Duration
+	// ServiceTypes are the services to ping, if empty then every service is pinged.
+	ServiceTypes []ServiceType
+	// Samples is the number of times each endpoint is pinged in each round, see PingOptions.Samples.
+	Samples int
+	// Timeout applies to each ping.
+	Timeout time.Duration
+	// Thresholds are used to determine whether an endpoint is degraded, MaxFailedEndpoints is not used.
+	Thresholds PingHealthThresholds
+}
+
+// HealthMonitor periodically pings the cluster, notifying a listener when endpoints become degraded or recover and
+// when nodes become unreachable.
+// UNCOMMITTED: This API may change in the future.
+type HealthMonitor struct {
+	interval   time.Duration
+	pingOpts   PingOptions
+	thresholds PingHealthThresholds
+	listener   HealthListener
+	ping       func(opts *PingOptions) (*PingResult, error)
+
+	degradedEndpoints map[pingEndpointKey]struct{}
+	unreachableNodes  map[string]struct{}
+
+	// ctx is cancelled by Stop, so that an in progress round of pings does not hold up stopping.
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	doneCh   chan struct{}
+	// onStop is called once when the monitor is stopped, the cluster uses it to stop tracking the monitor.
+	onStop func()
+}
+
+// StartHealthMonitor starts monitoring the health of the cluster, emitting events to the listener until Stop is
+// called or the cluster is closed. Returns ErrFeatureNotAvailable if the cluster cannot be pinged, such as when using
+// the couchbase2 protocol.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) StartHealthMonitor(listener HealthListener, opts *HealthMonitorOptions) (*HealthMonitor, error) {
+	if listener == nil {
+		return nil, makeInvalidArgumentsError("listener cannot be nil")
+	}
+	if opts == nil {
+		opts = &HealthMonitorOptions{}
+	}
+	if opts.Interval < 0 {
+		return nil, makeInvalidArgumentsError("interval cannot be negative")
+	}
+
+	// Ping is not supported by every protocol, in which case the monitor would only ever log failures.
+	if _, err := c.connectionManager.getDiagnosticsProvider(""); errors.Is(err, ErrFeatureNotAvailable) {
+		return nil, err
+	}
+
+	monitor := newHealthMonitor(c.Ping, listener, opts)
+	if !c.trackHealthMonitor(monitor) {
+		return nil, ErrShutdown
+	}
+	go monitor.monitorRoutine()
+
+	return monitor, nil
+}
+
+func (c *Cluster) trackHealthMonitor(monitor *HealthMonitor) bool {
+	c.healthMonitorsLock.Lock()
+	defer c.healthMonitorsLock.Unlock()
+
+	if c.healthMonitorsClosed {
+		return false
+	}
+	if c.healthMonitors == nil {
+		c.healthMonitors = make(map[*HealthMonitor]struct{})
+	}
+	c.healthMonitors[monitor] = struct{}{}
+	monitor.onStop = func() {
+		c.healthMonitorsLock.Lock()
+		delete(c.healthMonitors, monitor)
+		c.healthMonitorsLock.Unlock()
+	}
+
+	return true
+}
+
+// stopHealthMonitors stops every health monitor started on the cluster, and prevents any more from being started.
+func (c *Cluster) stopHealthMonitors() {
+	c.healthMonitorsLock.Lock()
+	c.healthMonitorsClosed = true
+	monitors := make([]*HealthMonitor, 0, len(c.healthMonitors))
+	for monitor := range c.healthMonitors {
+		monitors = append(monitors, monitor)
+	}
+	c.healthMonitorsLock.Unlock()
+
+	for _, monitor := range monitors {
+		monitor.Stop()
+	}
+}
+
+func newHealthMonitor(ping func(opts *PingOptions) (*PingResult, error), listener HealthListener,
+	opts *HealthMonitorOptions) *HealthMonitor {
+	interval := opts.Interval
+	if interval == 0 {
+		interval = 30 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &HealthMonitor{
+		interval: interval,
+		pingOpts: PingOptions{
+			ServiceTypes: opts.ServiceTypes,
+			Samples:      opts.Samples,
+			Timeout:      opts.Timeout,
+		},
+		thresholds:        opts.Thresholds,
+		listener:          listener,
+		ping:              ping,
+		degradedEndpoints: make(map[pingEndpointKey]struct{}),
+		unreachableNodes:  make(map[string]struct{}),
+		ctx:               ctx,
+		cancel:            cancel,
+		stopCh:            make(chan struct{}),
+		doneCh:            make(chan struct{}),
+	}
+}
+
+// Stop stops the monitor, cancelling any in progress round of pings. Stop does not wait for the monitor's routine to
+// exit, so that it can be called from OnHealthEvent, an event which is already being delivered when Stop is called
+// may still be received.
+// UNCOMMITTED: This API may change in the future.
+func (m *HealthMonitor) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stopCh)
+		m.cancel()
+		if m.onStop != nil {
+			m.onStop()
+		}
+	})
+}
+
+func (m *HealthMonitor) emit(event HealthEvent) {
+	if m.ctx.Err() != nil {
+		return
+	}
+
+	m.listener.OnHealthEvent(event)
+}
+
+func (m *HealthMonitor) monitorRoutine() {
+	defer close(m.doneCh)
+
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-time.After(m.interval):
+		}
+
+		pingOpts := m.pingOpts
+		pingOpts.Context = m.ctx
+		report, err := m.ping(&pingOpts)
+		if m.ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logWarnf("Health monitor failed to ping cluster: %v", err)
+			continue
+		}
+
+		m.evaluate(report, time.Now())
+	}
+}
+
+func (m *HealthMonitor) evaluate(report *PingResult, now time.Time) {
+	nodeHealthy := make(map[string]bool)
+	reported := make(map[pingEndpointKey]struct{})
+	for serviceType, endpoints := range report.Services {
+		for _, endpoint := range endpoints {
+			node := healthMonitorNode(endpoint.Remote)
+			key := pingEndpointKey{
+				service:   serviceType,
+				remote:    endpoint.Remote,
+				namespace: endpoint.Namespace,
+			}
+			reported[key] = struct{}{}
+
+			if endpoint.State == PingStateOk {
+				nodeHealthy[node] = true
+			} else if _, ok := nodeHealthy[node]; !ok {
+				nodeHealthy[node] = false
+			}
+
+			_, wasDegraded := m.degradedEndpoints[key]
+			degraded := m.thresholds.endpointFailed(serviceType, endpoint)
+			if degraded == wasDegraded {
+				continue
+			}
+
+			eventType := HealthEventEndpointRecovered
+			if degraded {
+				eventType = HealthEventEndpointDegraded
+				m.degradedEndpoints[key] = struct{}{}
+			} else {
+				delete(m.degradedEndpoints, key)
+			}
+
+			m.emit(HealthEvent{
+				Type:     eventType,
+				Node:     node,
+				Service:  serviceType,
+				Endpoint: endpoint,
+				Time:     now,
+			})
+		}
+	}
+
+	// Endpoints and nodes which are no longer part of the cluster are forgotten, without an event, so that the state
+	// does not grow as the cluster topology changes.
+	for key := range m.degradedEndpoints {
+		if _, ok := reported[key]; !ok {
+			delete(m.degradedEndpoints, key)
+		}
+	}
+	for node := range m.unreachableNodes {
+		if _, ok := nodeHealthy[node]; !ok {
+			delete(m.unreachableNodes, node)
+		}
+	}
+
+	for node, healthy := range nodeHealthy {
+		_, wasUnreachable := m.unreachableNodes[node]
+		if healthy {
+			delete(m.unreachableNodes, node)
+			continue
+		}
+		if wasUnreachable {
+			continue
+		}
+
+		m.unreachableNodes[node] = struct{}{}
+		m.emit(HealthEvent{
+			Type: HealthEventNodeUnreachable,
+			Node: node,
+			Time: now,
+		})
+	}
+}
+
+// healthMonitorNode returns the hostname of an endpoint, which is an address for the data service and a URL for the
+// HTTP services.
+func healthMonitorNode(remote string) string {
+	if strings.Contains(remote, "://") {
+		if parsed, err := url.Parse(remote); err == nil {
+			return parsed.Hostname()
+		}
+	}
+
+	host, _, err := net.SplitHostPort(remote)
+	if err != nil {
+		return remote
+	}
+
+	return host
+}
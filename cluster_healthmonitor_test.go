@@ -0,0 +1,243 @@
+package gocb
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+type testHealthListener struct {
+	lock   sync.Mutex
+	events []HealthEvent
+}
+
+func (l *testHealthListener) OnHealthEvent(event HealthEvent) {
+	l.lock.Lock()
+	l.events = append(l.events, event)
+	l.lock.Unlock()
+}
+
+func (l *testHealthListener) takeEvents() []HealthEvent {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	events := l.events
+	l.events = nil
+	return events
+}
+
+func (suite *UnitTestSuite) TestHealthMonitorEvaluate() {
+	listener := &testHealthListener{}
+	monitor := newHealthMonitor(nil, listener, &HealthMonitorOptions{
+		Thresholds: PingHealthThresholds{
+			MaxMeanLatency: map[ServiceType]time.Duration{
+				ServiceTypeQuery: 100 * time.Millisecond,
+			},
+		},
+	})
+
+	report := func(kvState PingState, queryLatency time.Duration, queryState PingState) *PingResult {
+		return &PingResult{
+			Services: map[ServiceType][]EndpointPingReport{
+				ServiceTypeKeyValue: {
+					{Remote: "10.0.0.1:11210", Namespace: "default", State: kvState},
+				},
+				ServiceTypeQuery: {
+					{Remote: "http://10.0.0.1:8093", State: queryState, MeanLatency: queryLatency},
+				},
+			},
+		}
+	}
+
+	now := time.Now()
+	monitor.evaluate(report(PingStateOk, 10*time.Millisecond, PingStateOk), now)
+	suite.Assert().Empty(listener.takeEvents())
+
+	monitor.evaluate(report(PingStateOk, 200*time.Millisecond, PingStateOk), now)
+	events := listener.takeEvents()
+	suite.Require().Len(events, 1)
+	suite.Assert().Equal(HealthEventEndpointDegraded, events[0].Type)
+	suite.Assert().Equal(ServiceTypeQuery, events[0].Service)
+	suite.Assert().Equal("10.0.0.1", events[0].Node)
+	suite.Assert().Equal(now, events[0].Time)
+
+	// The endpoint remains degraded so no further events are emitted.
+	monitor.evaluate(report(PingStateOk, 200*time.Millisecond, PingStateOk), now)
+	suite.Assert().Empty(listener.takeEvents())
+
+	monitor.evaluate(report(PingStateTimeout, 0, PingStateError), now)
+	events = listener.takeEvents()
+	suite.Require().Len(events, 2)
+	suite.Assert().Equal(HealthEventEndpointDegraded, events[0].Type)
+	suite.Assert().Equal(ServiceTypeKeyValue, events[0].Service)
+	suite.Assert().Equal(HealthEventNodeUnreachable, events[1].Type)
+	suite.Assert().Equal("10.0.0.1", events[1].Node)
+
+	monitor.evaluate(report(PingStateTimeout, 0, PingStateError), now)
+	suite.Assert().Empty(listener.takeEvents())
+
+	monitor.evaluate(report(PingStateOk, 10*time.Millisecond, PingStateOk), now)
+	events = listener.takeEvents()
+	suite.Require().Len(events, 2)
+	for _, event := range events {
+		suite.Assert().Equal(HealthEventEndpointRecovered, event.Type)
+	}
+
+	// Endpoints and nodes missing from the latest report are forgotten.
+	monitor.evaluate(report(PingStateTimeout, 200*time.Millisecond, PingStateOk), now)
+	suite.Require().Len(listener.takeEvents(), 2)
+	monitor.evaluate(&PingResult{}, now)
+	suite.Assert().Empty(listener.takeEvents())
+	suite.Assert().Empty(monitor.degradedEndpoints)
+	suite.Assert().Empty(monitor.unreachableNodes)
+}
+
+func (suite *UnitTestSuite) TestHealthMonitorRoutine() {
+	listener := &testHealthListener{}
+
+	var lock sync.Mutex
+	var pings int
+	pinged := make(chan struct{}, 10)
+	ping := func(opts *PingOptions) (*PingResult, error) {
+		suite.Assert().Equal(2, opts.Samples)
+		suite.Assert().Equal([]ServiceType{ServiceTypeKeyValue}, opts.ServiceTypes)
+
+		lock.Lock()
+		pings++
+		count := pings
+		lock.Unlock()
+		pinged <- struct{}{}
+
+		if count == 1 {
+			return nil, errors.New("cluster not ready")
+		}
+
+		return &PingResult{
+			Services: map[ServiceType][]EndpointPingReport{
+				ServiceTypeKeyValue: {
+					{Remote: "10.0.0.1:11210", State: PingStateError},
+				},
+			},
+		}, nil
+	}
+
+	monitor := newHealthMonitor(ping, listener, &HealthMonitorOptions{
+		Interval:     time.Millisecond,
+		ServiceTypes: []ServiceType{ServiceTypeKeyValue},
+		Samples:      2,
+	})
+	go monitor.monitorRoutine()
+
+	<-pinged
+	<-pinged
+	monitor.Stop()
+	monitor.Stop()
+	<-monitor.doneCh
+
+	events := listener.takeEvents()
+	suite.Require().Len(events, 2)
+	suite.Assert().Equal(HealthEventEndpointDegraded, events[0].Type)
+	suite.Assert().Equal(HealthEventNodeUnreachable, events[1].Type)
+}
+
+func (suite *UnitTestSuite) TestHealthMonitorStopCancelsPing() {
+	pinging := make(chan struct{})
+	ping := func(opts *PingOptions) (*PingResult, error) {
+		close(pinging)
+		<-opts.Context.Done()
+		return nil, opts.Context.Err()
+	}
+
+	monitor := newHealthMonitor(ping, &testHealthListener{}, &HealthMonitorOptions{
+		Interval: time.Millisecond,
+	})
+	go monitor.monitorRoutine()
+
+	<-pinging
+	monitor.Stop()
+	<-monitor.doneCh
+}
+
+func (suite *UnitTestSuite) TestHealthMonitorStopFromListener() {
+	ping := func(opts *PingOptions) (*PingResult, error) {
+		return &PingResult{
+			Services: map[ServiceType][]EndpointPingReport{
+				ServiceTypeKeyValue: {
+					{Remote: "10.0.0.1:11210", State: PingStateError},
+					{Remote: "10.0.0.2:11210", State: PingStateError},
+				},
+			},
+		}, nil
+	}
+
+	listener := &stoppingHealthListener{}
+	monitor := newHealthMonitor(ping, listener, &HealthMonitorOptions{
+		Interval: time.Millisecond,
+	})
+	listener.monitor = monitor
+	go monitor.monitorRoutine()
+
+	select {
+	case <-monitor.doneCh:
+	case <-time.After(5 * time.Second):
+		suite.T().Fatal("monitor did not stop")
+	}
+
+	// The first event stops the monitor, so no further events are emitted.
+	suite.Assert().Equal(1, listener.events)
+}
+
+type stoppingHealthListener struct {
+	monitor *HealthMonitor
+	events  int
+}
+
+func (l *stoppingHealthListener) OnHealthEvent(event HealthEvent) {
+	l.events++
+	l.monitor.Stop()
+}
+
+func (suite *UnitTestSuite) TestStartHealthMonitorInvalidArguments() {
+	c := &Cluster{}
+
+	_, err := c.StartHealthMonitor(nil, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	_, err = c.StartHealthMonitor(&testHealthListener{}, &HealthMonitorOptions{Interval: -time.Second})
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestStartHealthMonitorFeatureNotAvailable() {
+	cli := new(mockConnectionManager)
+	cli.On("getDiagnosticsProvider", "").Return(nil, ErrFeatureNotAvailable)
+
+	c := &Cluster{connectionManager: cli}
+
+	_, err := c.StartHealthMonitor(&testHealthListener{}, nil)
+	suite.Assert().ErrorIs(err, ErrFeatureNotAvailable)
+	suite.Assert().Empty(c.healthMonitors)
+}
+
+func (suite *UnitTestSuite) TestClusterCloseStopsHealthMonitors() {
+	cli := new(mockConnectionManager)
+	cli.On("getDiagnosticsProvider", "").Return(&mockDiagnosticsProvider{}, nil)
+	cli.On("close").Return(nil)
+
+	c := &Cluster{connectionManager: cli}
+
+	monitor, err := c.StartHealthMonitor(&testHealthListener{}, nil)
+	suite.Require().Nil(err, err)
+	stopped, err := c.StartHealthMonitor(&testHealthListener{}, nil)
+	suite.Require().Nil(err, err)
+
+	stopped.Stop()
+	suite.Assert().Len(c.healthMonitors, 1)
+
+	err = c.Close(nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Empty(c.healthMonitors)
+	<-monitor.doneCh
+
+	_, err = c.StartHealthMonitor(&testHealthListener{}, nil)
+	suite.Assert().ErrorIs(err, ErrShutdown)
+}
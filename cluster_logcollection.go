@@ -0,0 +1,155 @@
+package gocb
+
+import (
+	"context"
+	"time"
+)
+
+// LogCollectionState describes the state of a log collection, or of log collection on a single node.
+// UNCOMMITTED: This API may change in the future.
+type LogCollectionState string
+
+const (
+	// LogCollectionStateIdle indicates that no log collection has been run since the cluster started.
+	LogCollectionStateIdle LogCollectionState = "idle"
+
+	// LogCollectionStateRunning indicates that a log collection is in progress.
+	LogCollectionStateRunning LogCollectionState = "running"
+
+	// LogCollectionStateCompleted indicates that the most recent log collection has completed.
+	LogCollectionStateCompleted LogCollectionState = "completed"
+
+	// LogCollectionStateCancelled indicates that the most recent log collection was cancelled.
+	LogCollectionStateCancelled LogCollectionState = "cancelled"
+)
+
+// StartLogCollectionOptions is the set of options available to the StartLogCollection operation.
+// UNCOMMITTED: This API may change in the future.
+type StartLogCollectionOptions struct {
+	// Nodes are the OTP names of the nodes to collect logs from, e.g. "ns_1@10.0.0.1". Logs are collected from all
+	// nodes if this is empty.
+	Nodes []string
+
+	// RedactionLevel is the level of redaction applied to the collected logs. Only RedactNone and RedactPartial are
+	// supported by the server.
+	RedactionLevel LogRedactLevel
+
+	// UploadHost is the host to upload the collected logs to, logs are only stored locally on each node if this is
+	// empty. Customer must be set if UploadHost is set.
+	UploadHost  string
+	UploadProxy string
+	Customer    string
+	Ticket      string
+
+	// LogDir and TmpDir are the directories on each node that logs are written to and that temporary files are
+	// written to during collection.
+	LogDir string
+	TmpDir string
+
+	BypassReachabilityChecks bool
+
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// StartLogCollection starts collecting logs, as with cbcollect_info, on the nodes in the cluster. Use
+// GetLogCollectionStatus to monitor the progress of the collection.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) StartLogCollection(opts *StartLogCollectionOptions) error {
+	return autoOpControlErrorOnly(c.logCollectionController(), "manager_cluster_start_log_collection", func(provider logCollectionProvider) error {
+		if opts == nil {
+			opts = &StartLogCollectionOptions{}
+		}
+
+		if opts.RedactionLevel != RedactNone && opts.RedactionLevel != RedactPartial {
+			return makeInvalidArgumentsError("log collection only supports none or partial redaction")
+		}
+		if opts.UploadHost != "" && opts.Customer == "" {
+			return makeInvalidArgumentsError("customer must be set when uploading logs")
+		}
+		if opts.UploadHost == "" && (opts.Customer != "" || opts.Ticket != "" || opts.UploadProxy != "") {
+			return makeInvalidArgumentsError("upload host must be set when customer, ticket or upload proxy are set")
+		}
+
+		return provider.StartLogCollection(opts)
+	})
+}
+
+// LogCollectionNodeStatus is the status of log collection on a single node.
+// UNCOMMITTED: This API may change in the future.
+type LogCollectionNodeStatus struct {
+	// Status is the state of collection on the node, e.g. "collecting", "collected", "uploading", "uploaded" or
+	// "failed".
+	Status string
+
+	// Path is the path on the node that the logs were written to.
+	Path string
+
+	// URL is the location that the logs were uploaded to, if they were uploaded.
+	URL string
+}
+
+// LogCollectionStatus is the status of the most recent log collection.
+// UNCOMMITTED: This API may change in the future.
+type LogCollectionStatus struct {
+	State LogCollectionState
+
+	// Progress is the percentage of the collection which has completed.
+	Progress int
+
+	// Nodes is the status of collection on each node, keyed by the OTP name of the node.
+	Nodes map[string]LogCollectionNodeStatus
+}
+
+type jsonLogCollectionNodeStatus struct {
+	Status string `json:"status"`
+	Path   string `json:"path"`
+	URL    string `json:"url"`
+}
+
+type jsonLogCollectionTask struct {
+	Type     string                                 `json:"type"`
+	Status   string                                 `json:"status"`
+	Progress int                                    `json:"progress"`
+	PerNode  map[string]jsonLogCollectionNodeStatus `json:"perNode"`
+}
+
+func (s *LogCollectionStatus) fromData(data jsonLogCollectionTask) error {
+	s.State = LogCollectionState(data.Status)
+	s.Progress = data.Progress
+	s.Nodes = make(map[string]LogCollectionNodeStatus, len(data.PerNode))
+	for node, nodeData := range data.PerNode {
+		s.Nodes[node] = LogCollectionNodeStatus(nodeData)
+	}
+
+	return nil
+}
+
+// GetLogCollectionStatusOptions is the set of options available to the GetLogCollectionStatus operation.
+// UNCOMMITTED: This API may change in the future.
+type GetLogCollectionStatusOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// GetLogCollectionStatus returns the status of the most recent log collection.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) GetLogCollectionStatus(opts *GetLogCollectionStatusOptions) (*LogCollectionStatus, error) {
+	return autoOpControl(c.logCollectionController(), "manager_cluster_get_log_collection_status", func(provider logCollectionProvider) (*LogCollectionStatus, error) {
+		if opts == nil {
+			opts = &GetLogCollectionStatusOptions{}
+		}
+
+		return provider.GetLogCollectionStatus(opts)
+	})
+}
@@ -0,0 +1,132 @@
+package gocb
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) logCollectionCluster(runFn func(args mock.Arguments), args ...interface{}) *Cluster {
+	mockProvider := new(mockMgmtProvider)
+	call := mockProvider.
+		On("executeMgmtRequest", nil, mock.AnythingOfType("mgmtRequest")).
+		Return(args...)
+
+	if runFn != nil {
+		call.Run(runFn)
+	}
+
+	cli := new(mockConnectionManager)
+	cli.On("getLogCollectionProvider").Return(&logCollectionProviderCore{
+		provider: mockProvider,
+		tracer:   newTracerWrapper(&NoopTracer{}),
+	}, nil)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	return suite.newCluster(cli)
+}
+
+func (suite *UnitTestSuite) TestClusterStartLogCollection() {
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte("[]"))),
+	}
+
+	cluster := suite.logCollectionCluster(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/controller/startLogsCollection", req.Path)
+		suite.Assert().Equal("POST", req.Method)
+		suite.Assert().False(req.IsIdempotent)
+		suite.Assert().Equal(1*time.Second, req.Timeout)
+
+		form, err := url.ParseQuery(string(req.Body))
+		suite.Require().Nil(err, err)
+
+		suite.Assert().Equal(url.Values{
+			"nodes":             []string{"ns_1@10.0.0.1,ns_1@10.0.0.2"},
+			"logRedactionLevel": []string{"partial"},
+			"uploadHost":        []string{"uploads.couchbase.com"},
+			"customer":          []string{"acme"},
+			"ticket":            []string{"12345"},
+		}, form)
+	}, resp, nil)
+
+	err := cluster.StartLogCollection(&StartLogCollectionOptions{
+		Nodes:          []string{"ns_1@10.0.0.1", "ns_1@10.0.0.2"},
+		RedactionLevel: RedactPartial,
+		UploadHost:     "uploads.couchbase.com",
+		Customer:       "acme",
+		Ticket:         "12345",
+		Timeout:        1 * time.Second,
+	})
+	suite.Require().Nil(err, err)
+}
+
+func (suite *UnitTestSuite) TestClusterStartLogCollectionInvalidArguments() {
+	cluster := suite.logCollectionCluster(nil, nil, nil)
+
+	err := cluster.StartLogCollection(&StartLogCollectionOptions{RedactionLevel: RedactFull})
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	err = cluster.StartLogCollection(&StartLogCollectionOptions{UploadHost: "uploads.couchbase.com"})
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	err = cluster.StartLogCollection(&StartLogCollectionOptions{Ticket: "12345"})
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestClusterGetLogCollectionStatus() {
+	body := `[{"type":"rebalance","status":"notRunning"},{"type":"clusterLogsCollection","status":"running",` +
+		`"progress":50,"perNode":{"ns_1@10.0.0.1":{"status":"collected","path":"/tmp/collectinfo-1.zip"},` +
+		`"ns_1@10.0.0.2":{"status":"uploaded","path":"/tmp/collectinfo-2.zip",` +
+		`"url":"https://uploads.couchbase.com/acme/collectinfo-2.zip"}}}]`
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+	}
+
+	cluster := suite.logCollectionCluster(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/pools/default/tasks", req.Path)
+		suite.Assert().Equal("GET", req.Method)
+		suite.Assert().True(req.IsIdempotent)
+	}, resp, nil)
+
+	status, err := cluster.GetLogCollectionStatus(nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(&LogCollectionStatus{
+		State:    LogCollectionStateRunning,
+		Progress: 50,
+		Nodes: map[string]LogCollectionNodeStatus{
+			"ns_1@10.0.0.1": {Status: "collected", Path: "/tmp/collectinfo-1.zip"},
+			"ns_1@10.0.0.2": {
+				Status: "uploaded",
+				Path:   "/tmp/collectinfo-2.zip",
+				URL:    "https://uploads.couchbase.com/acme/collectinfo-2.zip",
+			},
+		},
+	}, status)
+}
+
+func (suite *UnitTestSuite) TestClusterGetLogCollectionStatusIdle() {
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte(`[{"type":"rebalance","status":"notRunning"}]`))),
+	}
+
+	cluster := suite.logCollectionCluster(nil, resp, nil)
+
+	status, err := cluster.GetLogCollectionStatus(nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(LogCollectionStateIdle, status.State)
+	suite.Assert().Empty(status.Nodes)
+}
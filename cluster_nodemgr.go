@@ -0,0 +1,402 @@
+package gocb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// NodeManager provides methods for listing the nodes of the cluster, failing them over, recovering them and
+// rebalancing the cluster.
+// UNCOMMITTED: This API may change in the future.
+type NodeManager struct {
+	controller *providerController[nodeManagementProvider]
+}
+
+// NodeStatus is the health of a node as seen by the cluster.
+// UNCOMMITTED: This API may change in the future.
+type NodeStatus string
+
+const (
+	// NodeStatusHealthy indicates that the node is running and responsive.
+	NodeStatusHealthy NodeStatus = "healthy"
+
+	// NodeStatusUnhealthy indicates that the node cannot be reached by the cluster.
+	NodeStatusUnhealthy NodeStatus = "unhealthy"
+
+	// NodeStatusWarmup indicates that the data service on the node is loading data from disk.
+	NodeStatusWarmup NodeStatus = "warmup"
+)
+
+// NodeMembership is the membership of a node within the cluster.
+// UNCOMMITTED: This API may change in the future.
+type NodeMembership string
+
+const (
+	// NodeMembershipActive indicates that the node is an active member of the cluster.
+	NodeMembershipActive NodeMembership = "active"
+
+	// NodeMembershipInactiveAdded indicates that the node has been added to, or is being recovered into, the cluster
+	// and becomes active on the next rebalance.
+	NodeMembershipInactiveAdded NodeMembership = "inactiveAdded"
+
+	// NodeMembershipInactiveFailed indicates that the node has been failed over.
+	NodeMembershipInactiveFailed NodeMembership = "inactiveFailed"
+)
+
+// NodeRecoveryType specifies how a failed over node is added back to the cluster.
+// UNCOMMITTED: This API may change in the future.
+type NodeRecoveryType string
+
+const (
+	// NodeRecoveryTypeFull discards the data held by the node, which is then rebuilt from the rest of the cluster.
+	NodeRecoveryTypeFull NodeRecoveryType = "full"
+
+	// NodeRecoveryTypeDelta reuses the data held by the node, only transferring the mutations made since it was
+	// failed over.
+	NodeRecoveryTypeDelta NodeRecoveryType = "delta"
+)
+
+// Node represents a node of the cluster.
+// UNCOMMITTED: This API may change in the future.
+type Node struct {
+	// Hostname is the host and management port of the node, which identifies the node to the other NodeManager
+	// operations.
+	Hostname   string
+	Services   []string
+	Status     NodeStatus
+	Membership NodeMembership
+	Version    string
+	// RecoveryType is the recovery type set for a failed over node, empty if none has been set.
+	RecoveryType NodeRecoveryType
+}
+
+// RebalanceProgress is the progress of a rebalance or graceful failover.
+// UNCOMMITTED: This API may change in the future.
+type RebalanceProgress struct {
+	Running bool
+	// Progress is the percentage of the rebalance which has completed.
+	Progress float64
+	// ErrorMessage is the reason that the most recent rebalance failed, if it did.
+	ErrorMessage string
+	// ID identifies the rebalance, it is empty where the cluster does not report it.
+	ID string
+}
+
+// RebalanceHandle allows the progress of a rebalance or graceful failover to be monitored.
+// UNCOMMITTED: This API may change in the future.
+type RebalanceHandle struct {
+	controller *providerController[nodeManagementProvider]
+
+	lock sync.Mutex
+	// previousID identifies the most recent rebalance before this one was started.
+	previousID string
+	// id identifies this rebalance, once the cluster has reported it.
+	id string
+}
+
+// ListNodesOptions is the set of options available to the node manager ListNodes operation.
+// UNCOMMITTED: This API may change in the future.
+type ListNodesOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// ListNodes returns all of the nodes of the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (nm *NodeManager) ListNodes(opts *ListNodesOptions) ([]Node, error) {
+	return autoOpControl(nm.controller, "manager_nodes_list_nodes", func(provider nodeManagementProvider) ([]Node, error) {
+		if opts == nil {
+			opts = &ListNodesOptions{}
+		}
+
+		return provider.ListNodes(opts)
+	})
+}
+
+// FailoverNodeOptions is the set of options available to the node manager Failover operation.
+// UNCOMMITTED: This API may change in the future.
+type FailoverNodeOptions struct {
+	// AllowUnsafe allows the node to be failed over even if doing so could lose data, such as when the cluster does
+	// not have a quorum of nodes.
+	AllowUnsafe bool
+
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// Failover immediately fails over a node, promoting replicas on the other nodes in place of the data that it held.
+// Mutations which had not yet been replicated from the node are lost.
+// UNCOMMITTED: This API may change in the future.
+func (nm *NodeManager) Failover(hostname string, opts *FailoverNodeOptions) error {
+	return autoOpControlErrorOnly(nm.controller, "manager_nodes_failover", func(provider nodeManagementProvider) error {
+		if hostname == "" {
+			return makeInvalidArgumentsError("hostname cannot be empty")
+		}
+
+		if opts == nil {
+			opts = &FailoverNodeOptions{}
+		}
+
+		return provider.Failover(hostname, opts)
+	})
+}
+
+// GracefulFailoverNodeOptions is the set of options available to the node manager GracefulFailover operation.
+// UNCOMMITTED: This API may change in the future.
+type GracefulFailoverNodeOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// GracefulFailover starts failing over a node once the replicas on the other nodes have caught up with it, so that
+// no mutations are lost. The returned handle can be used to wait for the failover to complete.
+// UNCOMMITTED: This API may change in the future.
+func (nm *NodeManager) GracefulFailover(hostname string, opts *GracefulFailoverNodeOptions) (*RebalanceHandle, error) {
+	return autoOpControl(nm.controller, "manager_nodes_graceful_failover", func(provider nodeManagementProvider) (*RebalanceHandle, error) {
+		if hostname == "" {
+			return nil, makeInvalidArgumentsError("hostname cannot be empty")
+		}
+
+		if opts == nil {
+			opts = &GracefulFailoverNodeOptions{}
+		}
+
+		previous, err := provider.GetRebalanceProgress(&RebalanceProgressOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = provider.GracefulFailover(hostname, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RebalanceHandle{controller: nm.controller, previousID: previous.ID}, nil
+	})
+}
+
+// RecoverNodeOptions is the set of options available to the node manager Recover operation.
+// UNCOMMITTED: This API may change in the future.
+type RecoverNodeOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// Recover marks a failed over node to be added back to the cluster using the given recovery type. The node becomes
+// active again once the cluster has been rebalanced.
+// UNCOMMITTED: This API may change in the future.
+func (nm *NodeManager) Recover(hostname string, recoveryType NodeRecoveryType, opts *RecoverNodeOptions) error {
+	return autoOpControlErrorOnly(nm.controller, "manager_nodes_recover", func(provider nodeManagementProvider) error {
+		if hostname == "" {
+			return makeInvalidArgumentsError("hostname cannot be empty")
+		}
+		if recoveryType != NodeRecoveryTypeFull && recoveryType != NodeRecoveryTypeDelta {
+			return makeInvalidArgumentsError("recovery type must be full or delta")
+		}
+
+		if opts == nil {
+			opts = &RecoverNodeOptions{}
+		}
+
+		return provider.Recover(hostname, recoveryType, opts)
+	})
+}
+
+// RebalanceOptions is the set of options available to the node manager Rebalance operation.
+// UNCOMMITTED: This API may change in the future.
+type RebalanceOptions struct {
+	// EjectedNodes are the hostnames of the nodes to remove from the cluster as part of the rebalance.
+	EjectedNodes []string
+
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// Rebalance starts rebalancing the cluster, which activates any added or recovered nodes and removes any ejected or
+// failed over nodes. The returned handle can be used to monitor the rebalance.
+// UNCOMMITTED: This API may change in the future.
+func (nm *NodeManager) Rebalance(opts *RebalanceOptions) (*RebalanceHandle, error) {
+	return autoOpControl(nm.controller, "manager_nodes_rebalance", func(provider nodeManagementProvider) (*RebalanceHandle, error) {
+		if opts == nil {
+			opts = &RebalanceOptions{}
+		}
+
+		previous, err := provider.GetRebalanceProgress(&RebalanceProgressOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = provider.Rebalance(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RebalanceHandle{controller: nm.controller, previousID: previous.ID}, nil
+	})
+}
+
+// RebalanceProgressOptions is the set of options available to the RebalanceHandle Progress operation.
+// UNCOMMITTED: This API may change in the future.
+type RebalanceProgressOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// Progress returns the current progress of the rebalance. Once a later rebalance has been started, this rebalance is
+// reported as complete.
+// UNCOMMITTED: This API may change in the future.
+func (h *RebalanceHandle) Progress(opts *RebalanceProgressOptions) (*RebalanceProgress, error) {
+	return autoOpControl(h.controller, "manager_nodes_rebalance_progress", func(provider nodeManagementProvider) (*RebalanceProgress, error) {
+		if opts == nil {
+			opts = &RebalanceProgressOptions{}
+		}
+
+		progress, err := provider.GetRebalanceProgress(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return h.track(progress), nil
+	})
+}
+
+// track ties the progress reported by the cluster, which is that of its most recent rebalance, to this rebalance.
+func (h *RebalanceHandle) track(progress *RebalanceProgress) *RebalanceProgress {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if progress.ID == "" {
+		return progress
+	}
+
+	if h.id == "" {
+		if progress.ID == h.previousID {
+			// The cluster has not yet reported this rebalance, only the one before it.
+			return &RebalanceProgress{Running: true}
+		}
+		h.id = progress.ID
+	}
+
+	if progress.ID != h.id {
+		return &RebalanceProgress{Progress: 100, ID: h.id}
+	}
+
+	return progress
+}
+
+// WaitForRebalanceOptions is the set of options available to the RebalanceHandle Wait operation.
+// UNCOMMITTED: This API may change in the future.
+type WaitForRebalanceOptions struct {
+	// PollInterval is how long to wait between checking the progress of the rebalance, defaults to 1 second.
+	PollInterval time.Duration
+
+	// Timeout bounds how long to wait for the rebalance to complete, rebalances of large clusters can take hours so
+	// this defaults to no timeout.
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation.
+	Context context.Context
+}
+
+// Wait blocks until the rebalance has completed, returning ErrRebalanceFailed if the rebalance failed. Errors getting
+// the progress of the rebalance which are likely to be transient, such as timeouts whilst the orchestrator node moves,
+// are retried until the rebalance completes or the timeout is reached.
+// UNCOMMITTED: This API may change in the future.
+func (h *RebalanceHandle) Wait(opts *WaitForRebalanceOptions) error {
+	if opts == nil {
+		opts = &WaitForRebalanceOptions{}
+	}
+
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = 1 * time.Second
+	}
+
+	return PollUntil(opts.Context, interval, func(ctx context.Context) (bool, error) {
+		progress, err := h.Progress(&RebalanceProgressOptions{
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       ctx,
+		})
+		if err != nil {
+			if ctx.Err() == nil && isTransientRebalanceProgressError(err) {
+				logDebugf("Retrying transient error getting rebalance progress: %v", err)
+				return false, nil
+			}
+
+			return false, err
+		}
+		if progress.Running {
+			return false, nil
+		}
+		if progress.ErrorMessage != "" {
+			return false, wrapError(ErrRebalanceFailed, progress.ErrorMessage)
+		}
+
+		return true, nil
+	}, &PollUntilOptions{
+		Timeout:    opts.Timeout,
+		ParentSpan: opts.ParentSpan,
+	})
+}
+
+// isTransientRebalanceProgressError returns whether an error getting the progress of a rebalance is likely to resolve
+// itself, as nodes can briefly fail to respond whilst they are rebalanced.
+func isTransientRebalanceProgressError(err error) bool {
+	if errors.Is(err, ErrTimeout) || errors.Is(err, ErrServiceNotAvailable) || errors.Is(err, ErrTemporaryFailure) {
+		return true
+	}
+
+	var httpErr *HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode >= 500
+}
@@ -0,0 +1,220 @@
+package gocb
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+const testNodesPoolBody = `{"nodes":[` +
+	`{"hostname":"10.0.0.1:8091","otpNode":"ns_1@10.0.0.1","services":["kv","n1ql"],"status":"healthy",` +
+	`"clusterMembership":"active","version":"7.6.0-2176-enterprise","recoveryType":"none"},` +
+	`{"hostname":"10.0.0.2:8091","otpNode":"ns_1@10.0.0.2","services":["kv","index"],"status":"unhealthy",` +
+	`"clusterMembership":"inactiveFailed","version":"7.6.0-2176-enterprise","recoveryType":"delta"}]}`
+
+func (suite *UnitTestSuite) nodeManager(mockProvider *mockMgmtProvider) *NodeManager {
+	provider := &nodeManagementProviderCore{
+		provider: mockProvider,
+		tracer:   newTracerWrapper(&NoopTracer{}),
+	}
+
+	return &NodeManager{
+		controller: &providerController[nodeManagementProvider]{
+			get: func() (nodeManagementProvider, error) {
+				return provider, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+}
+
+func (suite *UnitTestSuite) mockNodeRequest(mockProvider *mockMgmtProvider, method, path string, statusCode uint32,
+	body string) *mock.Call {
+	return mockProvider.
+		On("executeMgmtRequest", mock.Anything, mock.MatchedBy(func(req mgmtRequest) bool {
+			return req.Method == method && req.Path == path
+		})).
+		Return(&mgmtResponse{
+			StatusCode: statusCode,
+			Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+		}, nil)
+}
+
+func (suite *UnitTestSuite) TestNodeManagerListNodes() {
+	mockProvider := new(mockMgmtProvider)
+	suite.mockNodeRequest(mockProvider, "GET", "/pools/default", 200, testNodesPoolBody)
+
+	nodes, err := suite.nodeManager(mockProvider).ListNodes(nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal([]Node{
+		{
+			Hostname:   "10.0.0.1:8091",
+			Services:   []string{"kv", "n1ql"},
+			Status:     NodeStatusHealthy,
+			Membership: NodeMembershipActive,
+			Version:    "7.6.0-2176-enterprise",
+		},
+		{
+			Hostname:     "10.0.0.2:8091",
+			Services:     []string{"kv", "index"},
+			Status:       NodeStatusUnhealthy,
+			Membership:   NodeMembershipInactiveFailed,
+			Version:      "7.6.0-2176-enterprise",
+			RecoveryType: NodeRecoveryTypeDelta,
+		},
+	}, nodes)
+}
+
+func (suite *UnitTestSuite) TestNodeManagerRebalance() {
+	mockProvider := new(mockMgmtProvider)
+	suite.mockNodeRequest(mockProvider, "GET", "/pools/default/tasks", 200,
+		`[{"type":"rebalance","status":"notRunning","rebalanceId":"previous"}]`)
+	suite.mockNodeRequest(mockProvider, "GET", "/pools/default", 200, testNodesPoolBody)
+	suite.mockNodeRequest(mockProvider, "POST", "/controller/rebalance", 200, "").
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(mgmtRequest)
+
+			form, err := url.ParseQuery(string(req.Body))
+			suite.Require().Nil(err, err)
+			suite.Assert().Equal("ns_1@10.0.0.1,ns_1@10.0.0.2", form.Get("knownNodes"))
+			suite.Assert().Equal("ns_1@10.0.0.2", form.Get("ejectedNodes"))
+		})
+
+	handle, err := suite.nodeManager(mockProvider).Rebalance(&RebalanceOptions{
+		EjectedNodes: []string{"10.0.0.2"},
+	})
+	suite.Require().Nil(err, err)
+	suite.Require().NotNil(handle)
+	suite.Assert().Equal("previous", handle.previousID)
+	mockProvider.AssertNumberOfCalls(suite.T(), "executeMgmtRequest", 3)
+}
+
+func (suite *UnitTestSuite) TestNodeManagerFindNodeSharedHost() {
+	// Nodes which share a host, such as in a development cluster, can only be identified by their management port.
+	nodesData := []jsonNode{
+		{Hostname: "127.0.0.1:9000", OTPNode: "n_0@127.0.0.1"},
+		{Hostname: "127.0.0.1:9001", OTPNode: "n_1@127.0.0.1"},
+		{Hostname: "10.0.0.1:8091", OTPNode: "ns_1@10.0.0.1"},
+	}
+
+	node, err := findNode(nodesData, "127.0.0.1:9001")
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("n_1@127.0.0.1", node.OTPNode)
+
+	_, err = findNode(nodesData, "127.0.0.1")
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	node, err = findNode(nodesData, "10.0.0.1")
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("ns_1@10.0.0.1", node.OTPNode)
+
+	_, err = findNode(nodesData, "10.0.0.9")
+	suite.Assert().ErrorIs(err, ErrNodeNotFound)
+}
+
+func (suite *UnitTestSuite) TestNodeManagerFailoverNodeNotFound() {
+	mockProvider := new(mockMgmtProvider)
+	suite.mockNodeRequest(mockProvider, "GET", "/pools/default", 200, testNodesPoolBody)
+
+	err := suite.nodeManager(mockProvider).Failover("10.0.0.9:8091", nil)
+	suite.Assert().True(errors.Is(err, ErrNodeNotFound))
+	mockProvider.AssertNumberOfCalls(suite.T(), "executeMgmtRequest", 1)
+}
+
+func (suite *UnitTestSuite) TestNodeManagerRecover() {
+	mockProvider := new(mockMgmtProvider)
+	suite.mockNodeRequest(mockProvider, "GET", "/pools/default", 200, testNodesPoolBody)
+	suite.mockNodeRequest(mockProvider, "POST", "/controller/setRecoveryType", 200, "").
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(mgmtRequest)
+			suite.Assert().Equal("otpNode=ns_1%4010.0.0.2&recoveryType=delta", string(req.Body))
+		})
+
+	err := suite.nodeManager(mockProvider).Recover("10.0.0.2:8091", NodeRecoveryTypeDelta, nil)
+	suite.Require().Nil(err, err)
+
+	err = suite.nodeManager(mockProvider).Recover("10.0.0.2:8091", "partial", nil)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
+
+func (suite *UnitTestSuite) TestRebalanceHandleWait() {
+	mockProvider := new(mockMgmtProvider)
+	suite.mockNodeRequest(mockProvider, "GET", "/pools/default/tasks", 200,
+		`[{"type":"rebalance","status":"running","progress":42.5}]`).Once()
+	suite.mockNodeRequest(mockProvider, "GET", "/pools/default/tasks", 200,
+		`[{"type":"rebalance","status":"notRunning"}]`).Once()
+
+	handle := &RebalanceHandle{controller: suite.nodeManager(mockProvider).controller}
+	err := handle.Wait(&WaitForRebalanceOptions{
+		PollInterval: time.Millisecond,
+		Timeout:      5 * time.Second,
+	})
+	suite.Require().Nil(err, err)
+	mockProvider.AssertNumberOfCalls(suite.T(), "executeMgmtRequest", 2)
+
+	mockProvider = new(mockMgmtProvider)
+	suite.mockNodeRequest(mockProvider, "GET", "/pools/default/tasks", 200,
+		`[{"type":"rebalance","status":"notRunning","errorMessage":"Rebalance failed. See logs for detailed reason."}]`)
+
+	handle = &RebalanceHandle{controller: suite.nodeManager(mockProvider).controller}
+	err = handle.Wait(&WaitForRebalanceOptions{PollInterval: time.Millisecond})
+	suite.Assert().True(errors.Is(err, ErrRebalanceFailed))
+}
+
+func (suite *UnitTestSuite) TestRebalanceHandleWaitRetriesTransientErrors() {
+	mockProvider := new(mockMgmtProvider)
+	suite.mockNodeRequest(mockProvider, "GET", "/pools/default/tasks", 503, "").Once()
+	mockProvider.
+		On("executeMgmtRequest", mock.Anything, mock.AnythingOfType("gocb.mgmtRequest")).
+		Return(nil, ErrUnambiguousTimeout).Once()
+	suite.mockNodeRequest(mockProvider, "GET", "/pools/default/tasks", 200,
+		`[{"type":"rebalance","status":"notRunning"}]`).Once()
+
+	handle := &RebalanceHandle{controller: suite.nodeManager(mockProvider).controller}
+	err := handle.Wait(&WaitForRebalanceOptions{
+		PollInterval: time.Millisecond,
+		Timeout:      5 * time.Second,
+	})
+	suite.Require().Nil(err, err)
+	mockProvider.AssertNumberOfCalls(suite.T(), "executeMgmtRequest", 3)
+
+	mockProvider = new(mockMgmtProvider)
+	suite.mockNodeRequest(mockProvider, "GET", "/pools/default/tasks", 400, "bad request")
+
+	handle = &RebalanceHandle{controller: suite.nodeManager(mockProvider).controller}
+	err = handle.Wait(&WaitForRebalanceOptions{PollInterval: time.Millisecond})
+	suite.Assert().NotNil(err)
+	mockProvider.AssertNumberOfCalls(suite.T(), "executeMgmtRequest", 1)
+}
+
+func (suite *UnitTestSuite) TestRebalanceHandleTracksItsRebalance() {
+	mockProvider := new(mockMgmtProvider)
+	// The failure of the previous rebalance is still reported until this one is.
+	suite.mockNodeRequest(mockProvider, "GET", "/pools/default/tasks", 200,
+		`[{"type":"rebalance","status":"notRunning","rebalanceId":"previous","errorMessage":"Rebalance failed."}]`).Once()
+	suite.mockNodeRequest(mockProvider, "GET", "/pools/default/tasks", 200,
+		`[{"type":"rebalance","status":"running","rebalanceId":"current","progress":10}]`).Once()
+	// A later rebalance has been started, so this one has completed.
+	suite.mockNodeRequest(mockProvider, "GET", "/pools/default/tasks", 200,
+		`[{"type":"rebalance","status":"running","rebalanceId":"later","progress":5}]`).Once()
+
+	handle := &RebalanceHandle{controller: suite.nodeManager(mockProvider).controller, previousID: "previous"}
+
+	progress, err := handle.Progress(nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().True(progress.Running)
+	suite.Assert().Empty(progress.ErrorMessage)
+
+	progress, err = handle.Progress(nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(&RebalanceProgress{Running: true, Progress: 10, ID: "current"}, progress)
+
+	progress, err = handle.Progress(nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(&RebalanceProgress{Progress: 100, ID: "current"}, progress)
+}
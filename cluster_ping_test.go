@@ -1,6 +1,7 @@
 package gocb
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -231,3 +232,116 @@ func (suite *UnitTestSuite) TestClusterPingOne() {
 		suite.Assert().Equal(expectedService.ID, service.ID)
 	}
 }
+
+func (suite *UnitTestSuite) TestClusterPingSamples() {
+	sample := func(latency time.Duration, state gocbcore.PingState) *gocbcore.PingResult {
+		return &gocbcore.PingResult{
+			ConfigRev: 64,
+			Services: map[gocbcore.ServiceType][]gocbcore.EndpointPingResult{
+				gocbcore.N1qlService: {
+					{
+						Endpoint: "server1",
+						Latency:  latency,
+						State:    state,
+					},
+				},
+			},
+		}
+	}
+
+	coreProvider := new(mockDiagnosticsProviderCoreProvider)
+	coreProvider.On("Ping", nil, mock.AnythingOfType("gocbcore.PingOptions")).
+		Return(sample(40*time.Millisecond, gocbcore.PingStateTimeout), nil).Once()
+	coreProvider.On("Ping", nil, mock.AnythingOfType("gocbcore.PingOptions")).
+		Return(sample(10*time.Millisecond, gocbcore.PingStateOK), nil).Once()
+	coreProvider.On("Ping", nil, mock.AnythingOfType("gocbcore.PingOptions")).
+		Return(sample(25*time.Millisecond, gocbcore.PingStateOK), nil).Once()
+
+	provider := &diagnosticsProviderCore{
+		provider: coreProvider,
+		tracer:   newTracerWrapper(&NoopTracer{}),
+	}
+
+	report, err := provider.Ping(&PingOptions{Samples: 3})
+	suite.Require().Nil(err, err)
+	coreProvider.AssertNumberOfCalls(suite.T(), "Ping", 3)
+
+	suite.Require().Len(report.Services[ServiceTypeQuery], 1)
+	endpoint := report.Services[ServiceTypeQuery][0]
+	suite.Assert().Equal("server1", endpoint.Remote)
+	suite.Assert().Equal(PingStateTimeout, endpoint.State)
+	suite.Assert().Equal(25*time.Millisecond, endpoint.Latency)
+	// Only the latencies of the successful pings are summarised.
+	suite.Assert().Equal(10*time.Millisecond, endpoint.MinLatency)
+	suite.Assert().Equal(25*time.Millisecond, endpoint.MaxLatency)
+	suite.Assert().Equal(17500*time.Microsecond, endpoint.MeanLatency)
+
+	b, err := json.Marshal(report)
+	suite.Require().Nil(err, err)
+
+	var jsonReport jsonPingReport
+	suite.Require().Nil(json.Unmarshal(b, &jsonReport))
+	suite.Assert().Equal(uint16(2), jsonReport.Version)
+	suite.Assert().Equal(int64(64), jsonReport.ConfigRev)
+	suite.Assert().Equal([]jsonEndpointPingReport{
+		{
+			Remote:        "server1",
+			State:         "timeout",
+			LatencyUs:     25000,
+			MinLatencyUs:  10000,
+			MaxLatencyUs:  25000,
+			MeanLatencyUs: 17500,
+		},
+	}, jsonReport.Services["query"])
+}
+
+func (suite *UnitTestSuite) TestPingResultCheckHealth() {
+	report := &PingResult{
+		Services: map[ServiceType][]EndpointPingReport{
+			ServiceTypeQuery: {
+				{Remote: "server1", State: PingStateOk, MeanLatency: 10 * time.Millisecond},
+				{Remote: "server2", State: PingStateOk, MeanLatency: 300 * time.Millisecond},
+			},
+			ServiceTypeSearch: {
+				{Remote: "server1", State: PingStateOk, MeanLatency: 300 * time.Millisecond},
+			},
+			ServiceTypeAnalytics: {
+				{Remote: "server1", State: PingStateError, Error: "connection refused"},
+			},
+			ServiceTypeManagement: {},
+		},
+	}
+
+	health := report.CheckHealth(PingHealthThresholds{
+		MaxMeanLatency: map[ServiceType]time.Duration{
+			ServiceTypeQuery: 100 * time.Millisecond,
+		},
+	})
+
+	suite.Require().Len(health, 4)
+	suite.Assert().False(health[ServiceTypeQuery].Passed)
+	suite.Require().Len(health[ServiceTypeQuery].FailedEndpoints, 1)
+	suite.Assert().Equal("server2", health[ServiceTypeQuery].FailedEndpoints[0].Remote)
+	suite.Assert().True(health[ServiceTypeSearch].Passed)
+	suite.Assert().False(health[ServiceTypeAnalytics].Passed)
+	suite.Assert().False(health[ServiceTypeManagement].Passed)
+
+	health = report.CheckHealth(PingHealthThresholds{
+		MaxMeanLatency: map[ServiceType]time.Duration{
+			ServiceTypeQuery: 100 * time.Millisecond,
+		},
+		MaxFailedEndpoints: 1,
+	})
+	suite.Assert().True(health[ServiceTypeQuery].Passed)
+	suite.Assert().True(health[ServiceTypeAnalytics].Passed)
+
+	// A service with a threshold which is missing from the report fails.
+	health = report.CheckHealth(PingHealthThresholds{
+		MaxMeanLatency: map[ServiceType]time.Duration{
+			ServiceTypeKeyValue: 100 * time.Millisecond,
+		},
+	})
+	suite.Require().Len(health, 5)
+	suite.Assert().False(health[ServiceTypeKeyValue].Passed)
+	suite.Assert().Empty(health[ServiceTypeKeyValue].FailedEndpoints)
+}
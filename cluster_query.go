@@ -179,6 +179,8 @@ type QueryResult struct {
 	nextRowBytes  []byte
 	rowBytes      []byte
 	endpoint      string
+	serializer    JSONSerializer
+	outcome       *streamOutcome
 }
 
 func newQueryResult(reader queryRowReader) *QueryResult {
@@ -240,7 +242,40 @@ func (r *QueryResult) Row(valuePtr interface{}) error {
 		return nil
 	}
 
-	return json.Unmarshal(r.rowBytes, valuePtr)
+	return r.deserialize(r.rowBytes, valuePtr)
+}
+
+// NextBytes moves to the next row and returns its raw JSON bytes, or nil if there are no more rows. The returned
+// slice is not copied and must not be modified. Err should be checked once nil is returned.
+// UNCOMMITTED: This API may change in the future.
+func (r *QueryResult) NextBytes() []byte {
+	if !r.Next() {
+		return nil
+	}
+
+	return r.rowBytes
+}
+
+func (r *QueryResult) deserialize(bytes []byte, valuePtr interface{}) error {
+	if r.serializer != nil {
+		return r.serializer.Deserialize(bytes, valuePtr)
+	}
+
+	return json.Unmarshal(bytes, valuePtr)
+}
+
+// Spool reads all of the remaining rows from the stream, holding them in memory up to the configured budget and
+// buffering any beyond it to a temporary file. Once spooled, MetaData can be accessed before the rows are iterated.
+// The response stream is closed by spooling, Close must still be called to remove any temporary file.
+// UNCOMMITTED: This API may change in the future.
+func (r *QueryResult) Spool(opts *ResultSpoolOptions) error {
+	if r.reader == nil {
+		return r.Err()
+	}
+
+	spool := newSpooledQueryRowReader(r.reader, opts)
+	r.reader = spool
+	return spool.spoolErr
 }
 
 // Err returns any errors that have occurred on the stream
@@ -251,6 +286,7 @@ func (r *QueryResult) Err() error {
 
 	err := r.reader.Err()
 	if err != nil {
+		r.outcome.observe(err)
 		if r.transactionID != "" {
 			return singleQueryErrToTransactionError(err, r.transactionID)
 		}
@@ -269,6 +305,7 @@ func (r *QueryResult) Close() error {
 
 	err := r.reader.Close()
 	if err != nil {
+		r.outcome.observe(err)
 		if r.transactionID != "" {
 			return singleQueryErrToTransactionError(err, r.transactionID)
 		}
@@ -279,6 +316,10 @@ func (r *QueryResult) Close() error {
 	return nil
 }
 
+func (r *QueryResult) setStreamOutcome(outcome *streamOutcome) {
+	r.outcome = outcome
+}
+
 // One assigns the first value from the results into the value pointer.
 // It will Close the results but not before iterating through all remaining
 // results, as such this should only be used for very small resultsets - ideally
@@ -300,7 +341,7 @@ func (r *QueryResult) One(valuePtr interface{}) error {
 	}
 	r.nextRowBytes = nil
 
-	return json.Unmarshal(valueBytes, valuePtr)
+	return r.deserialize(valueBytes, valuePtr)
 }
 
 // MetaData returns any meta-data that was available from this query.  Note that
@@ -331,6 +372,39 @@ func (r *QueryResult) MetaData() (*QueryMetaData, error) {
 	return &metaData, nil
 }
 
+// forEachRow invokes rowFn for each remaining row as it is read from the stream, closing the result once all rows
+// have been read or rowFn returns an error.
+func (r *QueryResult) forEachRow(rowFn func(row []byte) error) (*QueryMetaData, error) {
+	for row := r.NextBytes(); row != nil; row = r.NextBytes() {
+		if err := rowFn(row); err != nil {
+			if closeErr := r.Close(); closeErr != nil {
+				logDebugf("failed to close query result after callback error: %s", closeErr)
+			}
+			return nil, err
+		}
+	}
+
+	if err := r.Err(); err != nil {
+		if closeErr := r.Close(); closeErr != nil {
+			logDebugf("failed to close query result after stream error: %s", closeErr)
+		}
+		return nil, err
+	}
+
+	if err := r.Close(); err != nil {
+		return nil, err
+	}
+
+	return r.MetaData()
+}
+
+// TransactionID returns the ID of the transaction that the query was executed within, if the query was executed as
+// a single query transaction.
+// UNCOMMITTED: This API may change in the future.
+func (r *QueryResult) TransactionID() string {
+	return r.transactionID
+}
+
 // QueryResultInternal provides access to internal only functionality.
 // Internal: This should never be used and is not supported.
 type QueryResultInternal struct {
@@ -364,3 +438,38 @@ func (c *Cluster) Query(statement string, opts *QueryOptions) (*QueryResult, err
 		return provider.Query(statement, nil, opts)
 	})
 }
+
+// QuerySingleTransaction executes the query statement on the server as a single query transaction, so that any
+// documents modified by the statement are updated atomically. This is equivalent to calling Query with
+// QueryOptions.AsTransaction set, using the default SingleQueryTransactionOptions if they are not already set. The ID
+// of the transaction is available from QueryResult.TransactionID.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) QuerySingleTransaction(statement string, opts *QueryOptions) (*QueryResult, error) {
+	return c.Query(statement, singleTransactionQueryOptions(opts))
+}
+
+func singleTransactionQueryOptions(opts *QueryOptions) *QueryOptions {
+	var txOpts QueryOptions
+	if opts != nil {
+		txOpts = *opts
+	}
+	if txOpts.AsTransaction == nil {
+		txOpts.AsTransaction = &SingleQueryTransactionOptions{}
+	}
+
+	return &txOpts
+}
+
+// QueryCallback executes the query statement on the server, invoking rowFn with the raw JSON bytes of each row as it
+// is read from the response stream. Rows are not buffered, the stream is only read from once rowFn returns, so a slow
+// callback applies backpressure to the server. Returning an error from rowFn stops the query and the error is
+// returned. The returned slice must not be modified by rowFn. Once all rows are read the query meta-data is returned.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) QueryCallback(statement string, opts *QueryOptions, rowFn func(row []byte) error) (*QueryMetaData, error) {
+	result, err := c.Query(statement, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.forEachRow(rowFn)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -731,6 +732,87 @@ func (suite *UnitTestSuite) TestQueryResultsOne() {
 	suite.Assert().Equal(&aMeta, metadata)
 }
 
+func (suite *UnitTestSuite) TestQueryResultsNextBytes() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  suite.mustConvertToBytes(dataset.jsonQueryResponse),
+			Suite: suite,
+		},
+	}
+	result := newQueryResult(reader)
+
+	var count int
+	for row := result.NextBytes(); row != nil; row = result.NextBytes() {
+		var doc testBreweryDocument
+		suite.Require().Nil(json.Unmarshal(row, &doc))
+		suite.Assert().Equal(dataset.Results[count], doc)
+		count++
+	}
+	suite.Assert().Equal(len(dataset.Results), count)
+
+	err = result.Err()
+	suite.Require().Nil(err, err)
+}
+
+type testQuerySerializer struct {
+	serialized   []interface{}
+	deserialized int
+}
+
+func (s *testQuerySerializer) Serialize(value interface{}) ([]byte, error) {
+	s.serialized = append(s.serialized, value)
+	return []byte(`"serialized"`), nil
+}
+
+func (s *testQuerySerializer) Deserialize(bytes []byte, out interface{}) error {
+	s.deserialized++
+	return json.Unmarshal(bytes, out)
+}
+
+func (suite *UnitTestSuite) TestQueryResultsSerializer() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  suite.mustConvertToBytes(dataset.jsonQueryResponse),
+			Suite: suite,
+		},
+	}
+	serializer := &testQuerySerializer{}
+	result := newQueryResult(reader)
+	result.serializer = serializer
+
+	for result.Next() {
+		var doc testBreweryDocument
+		suite.Require().Nil(result.Row(&doc))
+	}
+	suite.Assert().Equal(len(dataset.Results), serializer.deserialized)
+}
+
+func (suite *UnitTestSuite) TestQueryOptionsSerializer() {
+	serializer := &testQuerySerializer{}
+	opts := &QueryOptions{
+		PositionalParameters: []interface{}{1},
+		NamedParameters:      map[string]interface{}{"name": 2},
+		Serializer:           serializer,
+	}
+
+	execOpts, err := opts.toMap()
+	suite.Require().Nil(err)
+
+	suite.Assert().Equal([]json.RawMessage{json.RawMessage(`"serialized"`)}, execOpts["args"])
+	suite.Assert().Equal(json.RawMessage(`"serialized"`), execOpts["$name"])
+	suite.Assert().ElementsMatch([]interface{}{1, 2}, serializer.serialized)
+}
+
 func (suite *UnitTestSuite) TestQueryResultsErr() {
 	reader := &mockQueryRowReader{
 		mockQueryRowReaderBase: mockQueryRowReaderBase{
@@ -1133,3 +1215,576 @@ func (suite *UnitTestSuite) TestQueryPreserveExpiry() {
 	suite.Require().Nil(err)
 	suite.Require().NotNil(result)
 }
+
+func (suite *UnitTestSuite) TestQueryPreserveExpiryNotSupported() {
+	retErr := &gocbcore.N1QLError{
+		InnerError:      errors.New("unknown query error"),
+		Endpoint:        "http://localhost:8093",
+		Statement:       "UPDATE dataset SET x = 1",
+		ClientContextID: "context",
+		Errors:          []gocbcore.N1QLErrorDesc{{Code: 1065, Message: "Unrecognized parameter in request: preserve_expiry"}},
+	}
+
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("N1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(1).(gocbcore.N1QLQueryOptions)
+
+			var payload map[string]interface{}
+			suite.Require().Nil(json.Unmarshal(opts.Payload, &payload))
+			suite.Assert().Equal(true, payload["preserve_expiry"])
+		}).
+		Return(nil, retErr)
+
+	queryProvider := &queryProviderCore{
+		provider: provider,
+	}
+
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	queryProvider.tracer = newTracerWrapper(&NoopTracer{})
+	queryProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	queryProvider.timeouts = cluster.timeoutsConfig
+
+	_, err := cluster.Query("UPDATE dataset SET x = 1", &QueryOptions{
+		Adhoc:          true,
+		PreserveExpiry: true,
+	})
+	suite.Require().ErrorIs(err, ErrFeatureNotAvailable)
+
+	var paramErr *QueryParameterError
+	suite.Require().ErrorAs(err, &paramErr)
+	suite.Assert().Equal("preserve_expiry", paramErr.Parameter)
+
+	var queryErr *QueryError
+	suite.Require().ErrorAs(err, &queryErr)
+	suite.Assert().Equal("UPDATE dataset SET x = 1", queryErr.Statement)
+}
+
+func (suite *UnitTestSuite) TestQueryPreserveExpiryReadonly() {
+	opts := &QueryOptions{
+		PreserveExpiry: true,
+		Readonly:       true,
+	}
+	execOpts, err := opts.toMap()
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(true, execOpts["preserve_expiry"])
+	suite.Assert().Equal(true, execOpts["readonly"])
+}
+
+func (suite *UnitTestSuite) TestQueryUseReplicaNotSupported() {
+	// This mirrors the error returned by gocbcore when the cluster does not advertise the readFromReplica capability.
+	retErr := &gocbcore.N1QLError{
+		InnerError: wrapError(ErrFeatureNotAvailable, "use replica is not supported by this cluster version"),
+	}
+
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("N1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(1).(gocbcore.N1QLQueryOptions)
+
+			var payload map[string]interface{}
+			suite.Require().Nil(json.Unmarshal(opts.Payload, &payload))
+			suite.Assert().Equal("on", payload["use_replica"])
+		}).
+		Return(nil, retErr)
+
+	queryProvider := &queryProviderCore{
+		provider: provider,
+	}
+
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	queryProvider.tracer = newTracerWrapper(&NoopTracer{})
+	queryProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	queryProvider.timeouts = cluster.timeoutsConfig
+
+	_, err := cluster.Query("SELECT 1=1", &QueryOptions{
+		Adhoc:      true,
+		UseReplica: QueryUseReplicaLevelOn,
+	})
+	suite.Require().ErrorIs(err, ErrFeatureNotAvailable)
+
+	var paramErr *QueryParameterError
+	suite.Require().ErrorAs(err, &paramErr)
+	suite.Assert().Equal("use_replica", paramErr.Parameter)
+}
+
+func (suite *UnitTestSuite) TestQueryUseReplicaOptions() {
+	type tCase struct {
+		level    QueryUseReplicaLevel
+		expected interface{}
+	}
+
+	for _, tc := range []tCase{
+		{level: QueryUseReplicaLevelNotSet, expected: nil},
+		{level: QueryUseReplicaLevelOff, expected: "off"},
+		{level: QueryUseReplicaLevelOn, expected: "on"},
+	} {
+		opts := &QueryOptions{UseReplica: tc.level}
+		execOpts, err := opts.toMap()
+		suite.Require().Nil(err)
+
+		if tc.expected == nil {
+			suite.Assert().NotContains(execOpts, "use_replica")
+		} else {
+			suite.Assert().Equal(tc.expected, execOpts["use_replica"])
+		}
+	}
+}
+
+// blockingQueryRowReader returns its rows and then blocks, as a slow stream would, until it is closed.
+type blockingQueryRowReader struct {
+	rows    [][]byte
+	closeCh chan struct{}
+	closed  sync.Once
+
+	mockQueryRowReaderBase
+}
+
+func (arr *blockingQueryRowReader) NextRow() []byte {
+	if arr.idx < len(arr.rows) {
+		row := arr.rows[arr.idx]
+		arr.idx++
+		return row
+	}
+
+	<-arr.closeCh
+	return nil
+}
+
+func (arr *blockingQueryRowReader) Close() error {
+	arr.closed.Do(func() {
+		close(arr.closeCh)
+	})
+	return nil
+}
+
+func (suite *UnitTestSuite) TestQueryContextCancelledWhilstStreaming() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reader := &blockingQueryRowReader{
+		rows:    [][]byte{[]byte(`{"id":1}`), []byte(`{"id":2}`)},
+		closeCh: make(chan struct{}),
+	}
+
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("N1QLQuery", ctx, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Return(reader, nil).
+		Once()
+
+	tracer := newTestTracer()
+	queryProvider := &queryProviderCore{
+		provider: provider,
+		tracer:   newTracerWrapper(tracer),
+	}
+
+	meter := newTestMeter()
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+	cli.On("getMeter").Return(newMeterWrapper(meter))
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	queryProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	queryProvider.timeouts = cluster.timeoutsConfig
+
+	result, err := cluster.Query("SELECT 1", &QueryOptions{
+		Adhoc:   true,
+		Context: ctx,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Require().True(result.Next())
+
+	spans := tracer.GetSpans()
+	suite.Require().Contains(spans, nil)
+	suite.Require().Len(spans[nil], 1)
+	span := spans[nil][0]
+	suite.Assert().False(span.Finished)
+
+	cancel()
+
+	suite.Assert().True(result.Next())
+	suite.Assert().False(result.Next())
+	suite.Assert().ErrorIs(result.Err(), ErrRequestCanceled)
+	suite.Assert().ErrorIs(result.Close(), ErrRequestCanceled)
+
+	suite.Assert().True(span.Finished)
+	suite.Assert().Equal(true, span.Tags[spanAttribCancelledKey])
+
+	// The query itself returned successfully, the cancellation of its stream is counted once.
+	suite.Require().Contains(meter.counters, meterNameCBOperationsCancelled+":query")
+	suite.Assert().Equal(uint64(1), meter.counters[meterNameCBOperationsCancelled+":query"].count)
+	suite.Assert().NotContains(meter.counters, meterNameCBOperationsFailed+":query")
+}
+
+func (suite *UnitTestSuite) TestQueryContextDeadlineWhilstStreaming() {
+	reader := &blockingQueryRowReader{
+		rows:    [][]byte{[]byte(`{"id":1}`), []byte(`{"id":2}`)},
+		closeCh: make(chan struct{}),
+	}
+
+	deadlineCtx, deadlineCancel := context.WithDeadline(context.Background(), time.Now().Add(50*time.Millisecond))
+	defer deadlineCancel()
+
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("N1QLQuery", deadlineCtx, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Return(reader, nil).
+		Once()
+
+	queryProvider := &queryProviderCore{
+		provider: provider,
+		tracer:   newTracerWrapper(&NoopTracer{}),
+	}
+
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	queryProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	queryProvider.timeouts = cluster.timeoutsConfig
+
+	result, err := cluster.Query("SELECT 1", &QueryOptions{
+		Adhoc:   true,
+		Context: deadlineCtx,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Require().True(result.Next())
+	<-deadlineCtx.Done()
+
+	suite.Assert().True(result.Next())
+	suite.Assert().False(result.Next())
+	suite.Assert().ErrorIs(result.Err(), ErrTimeout)
+	suite.Assert().NotErrorIs(result.Err(), ErrRequestCanceled)
+	suite.Assert().ErrorIs(result.Close(), ErrTimeout)
+
+	select {
+	case <-reader.closeCh:
+	default:
+		suite.T().Fatalf("Expected stream to be closed")
+	}
+}
+
+func (suite *UnitTestSuite) TestQueryContextCancelledWhilstWaitingForRow() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reader := &blockingQueryRowReader{
+		rows:    [][]byte{[]byte(`{"id":1}`)},
+		closeCh: make(chan struct{}),
+	}
+
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("N1QLQuery", ctx, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Return(reader, nil).
+		Once()
+
+	queryProvider := &queryProviderCore{
+		provider: provider,
+		tracer:   newTracerWrapper(&NoopTracer{}),
+	}
+
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	queryProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	queryProvider.timeouts = cluster.timeoutsConfig
+
+	result, err := cluster.Query("SELECT 1", &QueryOptions{
+		Adhoc:   true,
+		Context: ctx,
+	})
+	suite.Require().Nil(err, err)
+
+	// Moving to the first row waits for the next row, which never arrives, until the context is cancelled.
+	time.AfterFunc(50*time.Millisecond, cancel)
+	suite.Assert().True(result.Next())
+	suite.Assert().False(result.Next())
+	suite.Assert().ErrorIs(result.Err(), ErrRequestCanceled)
+	suite.Assert().ErrorIs(result.Close(), ErrRequestCanceled)
+
+	select {
+	case <-reader.closeCh:
+	default:
+		suite.T().Fatalf("Expected stream to be closed")
+	}
+}
+
+func (suite *UnitTestSuite) TestQueryContextCancelledBeforeResponse() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("N1QLQuery", ctx, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Return(nil, gocbcore.ErrRequestCanceled).
+		Once()
+
+	tracer := newTestTracer()
+	queryProvider := &queryProviderCore{
+		provider: provider,
+		tracer:   newTracerWrapper(tracer),
+	}
+
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	queryProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	queryProvider.timeouts = cluster.timeoutsConfig
+
+	_, err := cluster.Query("SELECT 1", &QueryOptions{
+		Adhoc:   true,
+		Context: ctx,
+	})
+	suite.Require().ErrorIs(err, ErrRequestCanceled)
+
+	spans := tracer.GetSpans()
+	suite.Require().Contains(spans, nil)
+	suite.Require().Len(spans[nil], 1)
+	suite.Assert().True(spans[nil][0].Finished)
+	suite.Assert().Equal(true, spans[nil][0].Tags[spanAttribCancelledKey])
+}
+
+func (suite *UnitTestSuite) TestQueryCancellationAccounting() {
+	type tCase struct {
+		name            string
+		cancelContext   bool
+		err             error
+		expectedCounter string
+		expectedOutcome string
+	}
+
+	testCases := []tCase{
+		{
+			name:            "cancelled",
+			cancelContext:   true,
+			err:             gocbcore.ErrRequestCanceled,
+			expectedCounter: meterNameCBOperationsCancelled,
+			expectedOutcome: spanOutcomeCancelled,
+		},
+		{
+			name:            "failed",
+			err:             gocbcore.ErrServiceNotAvailable,
+			expectedCounter: meterNameCBOperationsFailed,
+			expectedOutcome: spanOutcomeError,
+		},
+	}
+
+	for _, tCase := range testCases {
+		suite.Run(tCase.name, func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tCase.cancelContext {
+				cancel()
+			}
+
+			provider := new(mockQueryProviderCoreProvider)
+			provider.
+				On("N1QLQuery", ctx, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+				Return(nil, tCase.err).
+				Once()
+
+			tracer := newTestTracer()
+			queryProvider := &queryProviderCore{
+				provider: provider,
+				tracer:   newTracerWrapper(tracer),
+			}
+
+			meter := newTestMeter()
+			cli := new(mockConnectionManager)
+			cli.On("getQueryProvider").Return(queryProvider, nil)
+			cli.On("getMeter").Return(newMeterWrapper(meter))
+			cli.On("MarkOpBeginning").Return()
+			cli.On("MarkOpCompleted").Return()
+
+			cluster := suite.newCluster(cli)
+			queryProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+			queryProvider.timeouts = cluster.timeoutsConfig
+
+			_, err := cluster.Query("SELECT 1", &QueryOptions{
+				Adhoc:   true,
+				Context: ctx,
+			})
+			suite.Require().ErrorIs(err, tCase.err)
+
+			suite.Require().Contains(meter.counters, tCase.expectedCounter+":query")
+			suite.Assert().Equal(uint64(1), meter.counters[tCase.expectedCounter+":query"].count)
+			suite.Assert().Len(meter.counters, 1)
+
+			spans := tracer.GetSpans()
+			suite.Require().Len(spans[nil], 1)
+			suite.Assert().Equal(tCase.expectedOutcome, spans[nil][0].Tags[spanAttribOutcomeKey])
+		})
+	}
+}
+
+func (suite *UnitTestSuite) queryCallbackCluster(reader queryRowReader) *Cluster {
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("N1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Return(reader, nil)
+
+	queryProvider := &queryProviderCore{
+		provider: provider,
+		tracer:   newTracerWrapper(&NoopTracer{}),
+	}
+
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	queryProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	queryProvider.timeouts = cluster.timeoutsConfig
+
+	return cluster
+}
+
+func (suite *UnitTestSuite) TestQueryCallback() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  suite.mustConvertToBytes(dataset.jsonQueryResponse),
+			Suite: suite,
+		},
+	}
+	cluster := suite.queryCallbackCluster(reader)
+
+	var docs []testBreweryDocument
+	metadata, err := cluster.QueryCallback("SELECT * FROM `beer-sample`", &QueryOptions{Adhoc: true},
+		func(row []byte) error {
+			var doc testBreweryDocument
+			if err := json.Unmarshal(row, &doc); err != nil {
+				return err
+			}
+			docs = append(docs, doc)
+			return nil
+		})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(dataset.Results, docs)
+	suite.Assert().Equal(dataset.jsonQueryResponse.RequestID, metadata.RequestID)
+}
+
+func (suite *UnitTestSuite) TestQueryCallbackError() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  suite.mustConvertToBytes(dataset.jsonQueryResponse),
+			Suite: suite,
+		},
+	}
+	cluster := suite.queryCallbackCluster(reader)
+
+	cbErr := errors.New("callback error")
+	var calls int
+	_, err = cluster.QueryCallback("SELECT * FROM `beer-sample`", &QueryOptions{Adhoc: true},
+		func(row []byte) error {
+			calls++
+			return cbErr
+		})
+	suite.Assert().Equal(cbErr, err)
+	suite.Assert().Equal(1, calls)
+}
+
+func (suite *UnitTestSuite) TestQueryCallbackStreamError() {
+	reader := &mockQueryRowReader{
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			RowsErr: errors.New("some error"),
+			Suite:   suite,
+		},
+	}
+	cluster := suite.queryCallbackCluster(reader)
+
+	_, err := cluster.QueryCallback("SELECT 1", &QueryOptions{Adhoc: true}, func(row []byte) error {
+		suite.Fail("callback should not be invoked")
+		return nil
+	})
+	suite.Assert().NotNil(err)
+}
+
+type testSingleQueryTransactionsProvider struct {
+	transactionsProvider
+	singleQueryMode bool
+	perConfig       *TransactionOptions
+}
+
+func (p *testSingleQueryTransactionsProvider) Run(ctx context.Context, logicFn AttemptFunc, perConfig *TransactionOptions,
+	singleQueryMode bool) (*TransactionResult, error) {
+	p.singleQueryMode = singleQueryMode
+	p.perConfig = perConfig
+	return nil, ErrTransactionAbortedExternally
+}
+
+func (suite *UnitTestSuite) TestQuerySingleTransaction() {
+	txProvider := &testSingleQueryTransactionsProvider{}
+
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(new(mockQueryProvider), nil)
+	cli.On("getTransactionsProvider").Return(txProvider, nil)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	cluster.transactions = &Transactions{controller: cluster.transactionsController()}
+
+	opts := &QueryOptions{Timeout: 5 * time.Second}
+	_, err := cluster.QuerySingleTransaction("UPDATE default SET a = 1", opts)
+	suite.Require().ErrorIs(err, ErrTransactionAbortedExternally)
+
+	suite.Assert().True(txProvider.singleQueryMode)
+	suite.Assert().Equal(5*time.Second, txProvider.perConfig.Timeout)
+	// The options supplied by the user must not be modified.
+	suite.Assert().Nil(opts.AsTransaction)
+}
+
+func (suite *UnitTestSuite) TestQueryResultTransactionID() {
+	result := newQueryResult(&mockQueryRowReader{
+		mockQueryRowReaderBase: mockQueryRowReaderBase{Suite: suite},
+	})
+	suite.Assert().Empty(result.TransactionID())
+
+	result.transactionID = "txid"
+	suite.Assert().Equal("txid", result.TransactionID())
+}
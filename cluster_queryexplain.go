@@ -0,0 +1,232 @@
+package gocb
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// explainChildKeys are the plan properties which contain child operators.
+var explainChildKeys = []string{"~children", "~child", "scans", "scan", "first", "second"}
+
+// ExplainOperator is a single operator within a query plan.
+// UNCOMMITTED: This API may change in the future.
+type ExplainOperator struct {
+	// Operator is the name of the operator, such as IndexScan3, Fetch or Filter.
+	Operator string
+
+	// Index is the name of the index used by a scan operator.
+	Index string
+
+	// Keyspace is the keyspace that the operator applies to.
+	Keyspace string
+
+	// Covers lists the expressions covered by an index scan, if the scan is covering.
+	Covers []string
+
+	// Children are the operators which feed into this operator.
+	Children []ExplainOperator
+
+	// Properties contains all of the properties of the operator, excluding child operators.
+	Properties map[string]interface{}
+}
+
+// explainIndexScanPrefixes are the prefixes of the operators which scan an index, the operators are versioned by a
+// numeric suffix.
+var explainIndexScanPrefixes = []string{"IndexScan", "PrimaryScan", "IndexCountScan", "IndexCountDistinctScan",
+	"DistinctScan"}
+
+// IsIndexScan returns whether this operator is a scan of an index.
+func (op *ExplainOperator) IsIndexScan() bool {
+	for _, prefix := range explainIndexScanPrefixes {
+		if strings.HasPrefix(op.Operator, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (op *ExplainOperator) fromData(data map[string]interface{}) {
+	op.Properties = make(map[string]interface{}, len(data))
+	for k, v := range data {
+		op.Properties[k] = v
+	}
+
+	op.Operator, _ = data["#operator"].(string)
+	op.Index, _ = data["index"].(string)
+	op.Keyspace, _ = data["keyspace"].(string)
+
+	if covers, ok := data["covers"].([]interface{}); ok {
+		for _, cover := range covers {
+			if coverStr, ok := cover.(string); ok {
+				op.Covers = append(op.Covers, coverStr)
+			}
+		}
+	}
+
+	for _, key := range explainChildKeys {
+		switch child := data[key].(type) {
+		case map[string]interface{}:
+			op.Children = append(op.Children, newExplainOperator(child))
+		case []interface{}:
+			for _, c := range child {
+				if childData, ok := c.(map[string]interface{}); ok {
+					op.Children = append(op.Children, newExplainOperator(childData))
+				}
+			}
+		default:
+			continue
+		}
+
+		delete(op.Properties, key)
+	}
+}
+
+func newExplainOperator(data map[string]interface{}) ExplainOperator {
+	var op ExplainOperator
+	op.fromData(data)
+	return op
+}
+
+// walk invokes fn for this operator and all of its descendants, depth first.
+func (op *ExplainOperator) walk(fn func(op *ExplainOperator)) {
+	fn(op)
+	for i := range op.Children {
+		op.Children[i].walk(fn)
+	}
+}
+
+// ExplainIndexSelection describes an index chosen by the query planner.
+// UNCOMMITTED: This API may change in the future.
+type ExplainIndexSelection struct {
+	Name      string
+	Keyspace  string
+	Using     string
+	IsPrimary bool
+	// IsCovering indicates that the index contains all of the fields required by the query from this keyspace.
+	IsCovering bool
+}
+
+// ExplainResult is the result of an ExplainQuery operation.
+// UNCOMMITTED: This API may change in the future.
+type ExplainResult struct {
+	// Plan is the root operator of the query plan.
+	Plan ExplainOperator
+
+	// Text is the text of the statement which was explained.
+	Text string
+
+	// Indexes are the indexes selected by the query planner, in the order that they appear in the plan.
+	Indexes []ExplainIndexSelection
+
+	// Raw is the unparsed plan returned by the query service.
+	Raw json.RawMessage
+}
+
+// IsCovered returns whether the query is satisfied entirely by covering indexes, without fetching any documents.
+func (r *ExplainResult) IsCovered() bool {
+	if len(r.Indexes) == 0 {
+		return false
+	}
+
+	for _, index := range r.Indexes {
+		if !index.IsCovering {
+			return false
+		}
+	}
+
+	covered := true
+	r.Plan.walk(func(op *ExplainOperator) {
+		if op.Operator == "Fetch" {
+			covered = false
+		}
+	})
+
+	return covered
+}
+
+type jsonExplainRow struct {
+	Plan json.RawMessage `json:"plan"`
+	Text string          `json:"text"`
+}
+
+func (r *ExplainResult) fromData(data jsonExplainRow) error {
+	var plan map[string]interface{}
+	if err := json.Unmarshal(data.Plan, &plan); err != nil {
+		return err
+	}
+
+	r.Plan.fromData(plan)
+	r.Text = data.Text
+	r.Raw = data.Plan
+
+	r.Plan.walk(func(op *ExplainOperator) {
+		// A DistinctScan wraps the scan of the index that it deduplicates, which is recorded itself.
+		if !op.IsIndexScan() || op.Index == "" {
+			return
+		}
+
+		using, _ := op.Properties["using"].(string)
+		r.Indexes = append(r.Indexes, ExplainIndexSelection{
+			Name:       op.Index,
+			Keyspace:   op.Keyspace,
+			Using:      using,
+			IsPrimary:  strings.HasPrefix(op.Operator, "PrimaryScan"),
+			IsCovering: len(op.Covers) > 0,
+		})
+	})
+
+	return nil
+}
+
+func explainQueryResult(result *QueryResult) (*ExplainResult, error) {
+	var row jsonExplainRow
+	hasRow := result.Next()
+	if hasRow {
+		if err := result.Row(&row); err != nil {
+			if closeErr := result.Close(); closeErr != nil {
+				logDebugf("failed to close explain query result: %s", closeErr)
+			}
+			return nil, err
+		}
+	}
+
+	if err := result.Close(); err != nil {
+		return nil, err
+	}
+
+	if !hasRow {
+		return nil, ErrNoResult
+	}
+
+	var explain ExplainResult
+	if err := explain.fromData(row); err != nil {
+		return nil, err
+	}
+
+	return &explain, nil
+}
+
+// ExplainQuery asks the query service to explain how it would execute the statement, returning the query plan
+// along with the indexes that would be used. The statement must not already begin with EXPLAIN.
+// UNCOMMITTED: This API may change in the future.
+func (c *Cluster) ExplainQuery(statement string, opts *QueryOptions) (*ExplainResult, error) {
+	result, err := c.Query("EXPLAIN "+statement, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return explainQueryResult(result)
+}
+
+// ExplainQuery asks the query service to explain how it would execute the statement, constraining the query to the
+// bucket and scope. See Cluster.ExplainQuery for details.
+// UNCOMMITTED: This API may change in the future.
+func (s *Scope) ExplainQuery(statement string, opts *QueryOptions) (*ExplainResult, error) {
+	result, err := s.Query("EXPLAIN "+statement, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return explainQueryResult(result)
+}
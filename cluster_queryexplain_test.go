@@ -0,0 +1,89 @@
+package gocb
+
+import "encoding/json"
+
+func (suite *UnitTestSuite) explainQueryReader(planJSON string) *mockQueryIndexRowReader {
+	var plan map[string]interface{}
+	suite.Require().Nil(json.Unmarshal([]byte(planJSON), &plan))
+
+	return &mockQueryIndexRowReader{
+		Dataset: []map[string]interface{}{{
+			"plan": plan,
+			"text": "SELECT name FROM `travel-sample` WHERE type = \"airline\"",
+		}},
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  []byte(`{"requestID":"1","status":"success"}`),
+			Suite: suite,
+		},
+	}
+}
+
+func (suite *UnitTestSuite) TestExplainQueryCovered() {
+	reader := suite.explainQueryReader(`{"#operator":"Sequence","~children":[{"#operator":"IndexScan3",` +
+		`"covers":["cover ((` + "`travel-sample`" + `.` + "`type`" + `))","cover ((meta(` + "`travel-sample`" + `).` + "`id`" + `))"],` +
+		`"index":"def_type","keyspace":"travel-sample","namespace":"default","using":"gsi"},` +
+		`{"#operator":"Parallel","~child":{"#operator":"Sequence","~children":[{"#operator":"Filter"},` +
+		`{"#operator":"InitialProject"}]}}]}`)
+	cluster := suite.queryCallbackCluster(reader)
+
+	res, err := cluster.ExplainQuery("SELECT type FROM `travel-sample` WHERE type = \"airline\"", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal("Sequence", res.Plan.Operator)
+	suite.Require().Len(res.Plan.Children, 2)
+	suite.Assert().Equal("IndexScan3", res.Plan.Children[0].Operator)
+	suite.Assert().Equal("def_type", res.Plan.Children[0].Index)
+	suite.Assert().Len(res.Plan.Children[0].Covers, 2)
+	suite.Assert().Equal("gsi", res.Plan.Children[0].Properties["using"])
+	suite.Assert().NotContains(res.Plan.Properties, "~children")
+	suite.Require().Len(res.Plan.Children[1].Children, 1)
+	suite.Assert().Len(res.Plan.Children[1].Children[0].Children, 2)
+
+	suite.Assert().Equal([]ExplainIndexSelection{{
+		Name:       "def_type",
+		Keyspace:   "travel-sample",
+		Using:      "gsi",
+		IsCovering: true,
+	}}, res.Indexes)
+	suite.Assert().True(res.IsCovered())
+	suite.Assert().NotEmpty(res.Text)
+	suite.Assert().NotEmpty(res.Raw)
+}
+
+func (suite *UnitTestSuite) TestExplainQueryFetch() {
+	reader := suite.explainQueryReader(`{"#operator":"Sequence","~children":[{"#operator":"PrimaryScan3",` +
+		`"index":"#primary","keyspace":"travel-sample","using":"gsi"},{"#operator":"Fetch","keyspace":"travel-sample"}]}`)
+	cluster := suite.queryCallbackCluster(reader)
+
+	res, err := cluster.ExplainQuery("SELECT * FROM `travel-sample`", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+
+	suite.Require().Len(res.Indexes, 1)
+	suite.Assert().True(res.Indexes[0].IsPrimary)
+	suite.Assert().False(res.Indexes[0].IsCovering)
+	suite.Assert().False(res.IsCovered())
+}
+
+func (suite *UnitTestSuite) TestExplainQueryCountAndDistinctScans() {
+	reader := suite.explainQueryReader(`{"#operator":"Sequence","~children":[{"#operator":"IndexCountScan2",` +
+		`"covers":["cover ((` + "`travel-sample`" + `.` + "`type`" + `))"],"index":"def_type",` +
+		`"keyspace":"travel-sample","using":"gsi"},{"#operator":"DistinctScan","scan":{"#operator":"IndexScan3",` +
+		`"covers":["cover ((` + "`travel-sample`" + `.` + "`name`" + `))"],"index":"def_name",` +
+		`"keyspace":"travel-sample","using":"gsi"}}]}`)
+	cluster := suite.queryCallbackCluster(reader)
+
+	res, err := cluster.ExplainQuery("SELECT COUNT(type) FROM `travel-sample` WHERE type = \"airline\"",
+		&QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+
+	suite.Require().Len(res.Plan.Children, 2)
+	suite.Assert().True(res.Plan.Children[0].IsIndexScan())
+	suite.Assert().True(res.Plan.Children[1].IsIndexScan())
+	suite.Assert().False(res.Plan.IsIndexScan())
+
+	suite.Assert().Equal([]ExplainIndexSelection{
+		{Name: "def_type", Keyspace: "travel-sample", Using: "gsi", IsCovering: true},
+		{Name: "def_name", Keyspace: "travel-sample", Using: "gsi", IsCovering: true},
+	}, res.Indexes)
+	suite.Assert().True(res.IsCovered())
+}
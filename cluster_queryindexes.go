@@ -7,7 +7,16 @@ import (
 
 // QueryIndexManager provides methods for performing Couchbase query index management.
 type QueryIndexManager struct {
-	controller *providerController[queryIndexProvider]
+	controller     *providerController[queryIndexProvider]
+	warningHandler WarningHandler
+}
+
+// warnIfScopeCollection warns when the deprecated ScopeName and CollectionName options are used.
+func (qm *QueryIndexManager) warnIfScopeCollection(options, scope, collection string) {
+	if scope != "" || collection != "" {
+		warnDeprecated(qm.warningHandler, options+".ScopeName",
+			"ScopeName and CollectionName are deprecated, use CollectionQueryIndexManager instead")
+	}
 }
 
 func (qm *QueryIndexManager) validateScopeCollection(scope, collection string) error {
@@ -26,6 +35,16 @@ type CreateQueryIndexOptions struct {
 	Deferred       bool
 	NumReplicas    int
 
+	// IncludeMissing indexes documents which are missing the leading index key, allowing the index to be used by
+	// queries which do not filter on that key. This requires Couchbase Server 7.1 or later.
+	// UNCOMMITTED: This API may change in the future.
+	IncludeMissing bool
+
+	// RawKeys specifies that the index keys are N1QL expressions, such as those using FLATTEN_KEYS within an array
+	// index, and so are used as provided rather than being escaped as field names.
+	// UNCOMMITTED: This API may change in the future.
+	RawKeys bool
+
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
 	ParentSpan    RequestSpan
@@ -42,8 +61,7 @@ type CreateQueryIndexOptions struct {
 }
 
 // CreateIndex creates an index over the specified fields.
-// The SDK will automatically escape the provided index keys. For more advanced use cases like index keys using keywords
-// cluster.Query or scope.Query should be used with the query directly.
+// The SDK will automatically escape the provided index keys, unless RawKeys is set in the options.
 func (qm *QueryIndexManager) CreateIndex(bucketName, indexName string, keys []string, opts *CreateQueryIndexOptions) error {
 	return autoOpControlErrorOnly(qm.controller, "manager_query_create_index", func(provider queryIndexProvider) error {
 		if opts == nil {
@@ -60,6 +78,7 @@ func (qm *QueryIndexManager) CreateIndex(bucketName, indexName string, keys []st
 				message: "you must specify at least one index-key to index",
 			}
 		}
+		qm.warnIfScopeCollection("CreateQueryIndexOptions", opts.ScopeName, opts.CollectionName)
 		if err := qm.validateScopeCollection(opts.ScopeName, opts.CollectionName); err != nil {
 			return err
 		}
@@ -92,6 +111,14 @@ type CreatePrimaryQueryIndexOptions struct {
 
 // CreatePrimaryIndex creates a primary index.  An empty customName uses the default naming.
 func (qm *QueryIndexManager) CreatePrimaryIndex(bucketName string, opts *CreatePrimaryQueryIndexOptions) error {
+	if opts != nil {
+		qm.warnIfScopeCollection("CreatePrimaryQueryIndexOptions", opts.ScopeName, opts.CollectionName)
+	}
+
+	return qm.createPrimaryIndex(bucketName, opts)
+}
+
+func (qm *QueryIndexManager) createPrimaryIndex(bucketName string, opts *CreatePrimaryQueryIndexOptions) error {
 	return autoOpControlErrorOnly(qm.controller, "manager_query_create_primary_index", func(provider queryIndexProvider) error {
 		if opts == nil {
 			opts = &CreatePrimaryQueryIndexOptions{}
@@ -135,6 +162,7 @@ func (qm *QueryIndexManager) DropIndex(bucketName, indexName string, opts *DropQ
 				message: "an invalid index name was specified",
 			}
 		}
+		qm.warnIfScopeCollection("DropQueryIndexOptions", opts.ScopeName, opts.CollectionName)
 		if err := qm.validateScopeCollection(opts.ScopeName, opts.CollectionName); err != nil {
 			return err
 		}
@@ -169,6 +197,7 @@ func (qm *QueryIndexManager) DropPrimaryIndex(bucketName string, opts *DropPrima
 		if opts == nil {
 			opts = &DropPrimaryQueryIndexOptions{}
 		}
+		qm.warnIfScopeCollection("DropPrimaryQueryIndexOptions", opts.ScopeName, opts.CollectionName)
 		if err := qm.validateScopeCollection(opts.ScopeName, opts.CollectionName); err != nil {
 			return err
 		}
@@ -188,6 +217,11 @@ type GetAllQueryIndexesOptions struct {
 	// Deprecated: See CollectionQueryIndexManager.
 	CollectionName string
 
+	// IncludeStatus fetches the status of the indexes from the index service, populating the NumReplicas,
+	// ReplicaIDs, NumPartitions and LastScanTime of each index. This requires an additional request to the cluster.
+	// UNCOMMITTED: This API may change in the future.
+	IncludeStatus bool
+
 	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
 	// also applies to global level timeouts.
 	// UNCOMMITTED: This API may change in the future.
@@ -200,6 +234,7 @@ func (qm *QueryIndexManager) GetAllIndexes(bucketName string, opts *GetAllQueryI
 		if opts == nil {
 			opts = &GetAllQueryIndexesOptions{}
 		}
+		qm.warnIfScopeCollection("GetAllQueryIndexesOptions", opts.ScopeName, opts.CollectionName)
 
 		return provider.GetAllIndexes(nil, bucketName, opts)
 	})
@@ -220,6 +255,18 @@ type BuildDeferredQueryIndexOptions struct {
 	// also applies to global level timeouts.
 	// UNCOMMITTED: This API may change in the future.
 	Context context.Context
+
+	// WaitUntilOnline causes BuildDeferredIndexes to block until all of the indexes which were built are online.
+	// Timeout then applies to both building the indexes and waiting for them, and ErrUnambiguousTimeout is
+	// returned if the indexes are not online in time.
+	// UNCOMMITTED: This API may change in the future.
+	WaitUntilOnline bool
+
+	// PollInterval is the maximum interval between checks of the state of the indexes when WaitUntilOnline is set,
+	// the interval backs off up to this value which defaults to 1 second. This is not used by the couchbase2
+	// protocol, where the server waits for the indexes.
+	// UNCOMMITTED: This API may change in the future.
+	PollInterval time.Duration
 }
 
 // BuildDeferredIndexes builds all indexes which are currently in deferred state.
@@ -230,6 +277,7 @@ func (qm *QueryIndexManager) BuildDeferredIndexes(bucketName string, opts *Build
 		if opts == nil {
 			opts = &BuildDeferredQueryIndexOptions{}
 		}
+		qm.warnIfScopeCollection("BuildDeferredQueryIndexOptions", opts.ScopeName, opts.CollectionName)
 		if err := qm.validateScopeCollection(opts.ScopeName, opts.CollectionName); err != nil {
 			return nil, err
 		}
@@ -258,6 +306,14 @@ type WatchQueryIndexOptions struct {
 
 // WatchIndexes waits for a set of indexes to come online.
 func (qm *QueryIndexManager) WatchIndexes(bucketName string, watchList []string, timeout time.Duration, opts *WatchQueryIndexOptions) error {
+	if opts != nil {
+		qm.warnIfScopeCollection("WatchQueryIndexOptions", opts.ScopeName, opts.CollectionName)
+	}
+
+	return qm.watchIndexes(bucketName, watchList, timeout, opts)
+}
+
+func (qm *QueryIndexManager) watchIndexes(bucketName string, watchList []string, timeout time.Duration, opts *WatchQueryIndexOptions) error {
 	return autoOpControlErrorOnly(qm.controller, "manager_query_watch_indexes", func(provider queryIndexProvider) error {
 		if opts == nil {
 			opts = &WatchQueryIndexOptions{}
@@ -268,3 +324,104 @@ func (qm *QueryIndexManager) WatchIndexes(bucketName string, watchList []string,
 		return provider.WatchIndexes(nil, bucketName, watchList, timeout, opts)
 	})
 }
+
+// EnsurePrimaryQueryIndexOptions is the set of options available to the query index manager EnsurePrimaryIndex
+// operation.
+// UNCOMMITTED: This API may change in the future.
+type EnsurePrimaryQueryIndexOptions struct {
+	CustomName  string
+	NumReplicas int
+	// NoWait returns as soon as the index has been created, rather than waiting until it is online.
+	NoWait bool
+
+	// Timeout bounds the entire operation, including waiting for the index to come online.
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation.
+	Context context.Context
+}
+
+// EnsurePrimaryIndex creates a primary index on the keyspace if one does not already exist, and then waits until the
+// index is online.
+// UNCOMMITTED: This API may change in the future.
+func (qm *QueryIndexManager) EnsurePrimaryIndex(keyspace Keyspace, opts *EnsurePrimaryQueryIndexOptions) error {
+	if opts == nil {
+		opts = &EnsurePrimaryQueryIndexOptions{}
+	}
+	if err := keyspace.validate(); err != nil {
+		return err
+	}
+
+	ctx, cancel := ensureContext(opts.Context, opts.Timeout)
+	defer cancel()
+
+	// The scope and collection options are deprecated for applications, but are how the keyspace is passed on here.
+	err := qm.createPrimaryIndex(keyspace.Bucket, &CreatePrimaryQueryIndexOptions{
+		IgnoreIfExists: true,
+		CustomName:     opts.CustomName,
+		NumReplicas:    opts.NumReplicas,
+		RetryStrategy:  opts.RetryStrategy,
+		ParentSpan:     opts.ParentSpan,
+		ScopeName:      keyspace.Scope,
+		CollectionName: keyspace.Collection,
+		Context:        ctx,
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.NoWait {
+		return nil
+	}
+
+	var watchList []string
+	if opts.CustomName != "" {
+		watchList = []string{opts.CustomName}
+	}
+
+	deadline, _ := ctx.Deadline()
+	return qm.watchIndexes(keyspace.Bucket, watchList, time.Until(deadline), &WatchQueryIndexOptions{
+		WatchPrimary:   opts.CustomName == "",
+		RetryStrategy:  opts.RetryStrategy,
+		ParentSpan:     opts.ParentSpan,
+		ScopeName:      keyspace.Scope,
+		CollectionName: keyspace.Collection,
+		Context:        ctx,
+	})
+}
+
+// GetQueryIndexStatsOptions is the set of options available to the query indexes GetIndexStats operation.
+// UNCOMMITTED: This API may change in the future.
+type GetQueryIndexStatsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// GetIndexStats returns the statistics reported by the index service for an index on the keyspace. The statistics
+// are gathered from every index node and keyed by the index instance they refer to, as each replica and partition
+// of an index is reported separately.
+// UNCOMMITTED: This API may change in the future.
+func (qm *QueryIndexManager) GetIndexStats(keyspace Keyspace, indexName string, opts *GetQueryIndexStatsOptions) (map[string]interface{}, error) {
+	return autoOpControl(qm.controller, "manager_query_get_index_stats", func(provider queryIndexProvider) (map[string]interface{}, error) {
+		if err := keyspace.validate(); err != nil {
+			return nil, err
+		}
+		if indexName == "" {
+			return nil, makeInvalidArgumentsError("index name cannot be empty")
+		}
+
+		if opts == nil {
+			opts = &GetQueryIndexStatsOptions{}
+		}
+
+		return provider.GetIndexStats(keyspace, indexName, opts)
+	})
+}
@@ -1,10 +1,17 @@
 package gocb
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"strings"
 	"time"
 
+	"github.com/couchbase/gocbcore/v10"
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 )
 
 func (suite *IntegrationTestSuite) TestQueryIndexesCrud() {
@@ -617,3 +624,313 @@ func (suite *UnitTestSuite) TestQueryIndexesParsing() {
 	suite.Assert().Empty(index.Condition)
 	suite.Assert().Equal("HASH(`_type`)", index.Partition)
 }
+
+type mockQueryValueRowReader struct {
+	Dataset []interface{}
+	mockQueryRowReaderBase
+}
+
+func (arr *mockQueryValueRowReader) NextRow() []byte {
+	if arr.idx == len(arr.Dataset) {
+		return nil
+	}
+
+	idx := arr.idx
+	arr.idx++
+
+	return arr.Suite.mustConvertToBytes(arr.Dataset[idx])
+}
+
+func (suite *UnitTestSuite) TestQueryIndexesBuildDeferredWaitUntilOnline() {
+	indexRow := func(state string) []interface{} {
+		return []interface{}{map[string]interface{}{
+			"name": "idx_type", "keyspace_id": "test", "bucket_id": "mybucket", "state": state, "using": "gsi",
+		}}
+	}
+	newReader := func(rows []interface{}) *mockQueryValueRowReader {
+		return &mockQueryValueRowReader{
+			Dataset:                rows,
+			mockQueryRowReaderBase: mockQueryRowReaderBase{Suite: suite},
+		}
+	}
+
+	var statements []string
+	provider := new(mockQueryProviderCoreProvider)
+	for _, rows := range [][]interface{}{{"idx_type"}, nil, indexRow("building"), indexRow("online")} {
+		provider.
+			On("N1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+			Run(func(args mock.Arguments) {
+				var payload map[string]interface{}
+				suite.Require().Nil(json.Unmarshal(args.Get(1).(gocbcore.N1QLQueryOptions).Payload, &payload))
+				statements = append(statements, payload["statement"].(string))
+			}).
+			Return(newReader(rows), nil).
+			Once()
+	}
+
+	mgr := QueryIndexManager{
+		controller: &providerController[queryIndexProvider]{
+			get: func() (queryIndexProvider, error) {
+				return &queryProviderCore{
+					provider: provider,
+					tracer:   newTracerWrapper(&NoopTracer{}),
+					timeouts: TimeoutsConfig{ManagementTimeout: 10 * time.Second},
+				}, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+
+	names, err := mgr.BuildDeferredIndexes("mybucket", &BuildDeferredQueryIndexOptions{
+		WaitUntilOnline: true,
+		PollInterval:    time.Millisecond,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal([]string{"idx_type"}, names)
+	suite.Require().Len(statements, 4)
+	suite.Assert().True(strings.HasPrefix(statements[1], "BUILD INDEX ON `mybucket`"), statements[1])
+	suite.Assert().True(strings.HasPrefix(statements[2], "SELECT `idx`.* FROM system:indexes"), statements[2])
+	provider.AssertExpectations(suite.T())
+}
+
+func (suite *UnitTestSuite) TestQueryIndexesGetAllIndexesStatus() {
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("N1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Return(func(context.Context, gocbcore.N1QLQueryOptions) queryRowReader {
+			return &mockQueryIndexRowReader{
+				Dataset: []map[string]interface{}{
+					{"name": "def_type", "keyspace_id": "travel-sample", "state": "online", "using": "gsi"},
+					{"name": "idx_name", "keyspace_id": "airline", "bucket_id": "travel-sample", "scope_id": "inventory", "state": "online", "using": "gsi"},
+				},
+				mockQueryRowReaderBase: mockQueryRowReaderBase{Suite: suite},
+			}
+		}, nil)
+
+	statusBody := `{"indexes":[` +
+		`{"index":"def_type","bucket":"travel-sample","scope":"_default","collection":"_default","replicaId":1,"numReplica":1,"lastScanTime":"NA"},` +
+		`{"index":"def_type","bucket":"travel-sample","scope":"_default","collection":"_default","replicaId":0,"numReplica":1,"lastScanTime":"Tue Mar 12 10:20:30 UTC 2024"},` +
+		`{"index":"idx_name","bucket":"travel-sample","scope":"inventory","collection":"airline","replicaId":0,"numReplica":0,"partitioned":true,"numPartition":8,"lastScanTime":"NA"},` +
+		`{"index":"idx_name","bucket":"travel-sample","scope":"inventory","collection":"hotel","replicaId":0,"numReplica":2,"lastScanTime":"NA"}]}`
+	mgmtProvider := new(mockMgmtProvider)
+	mgmtProvider.
+		On("executeMgmtRequest", nil, mock.MatchedBy(func(req mgmtRequest) bool {
+			return req.Service == ServiceTypeManagement && req.Method == "GET" && req.Path == "/indexStatus"
+		})).
+		Return(&mgmtResponse{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewReader([]byte(statusBody))),
+		}, nil)
+
+	mgr := QueryIndexManager{
+		controller: &providerController[queryIndexProvider]{
+			get: func() (queryIndexProvider, error) {
+				return &queryProviderCore{
+					provider:     provider,
+					mgmtProvider: mgmtProvider,
+					tracer:       newTracerWrapper(&NoopTracer{}),
+				}, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+
+	// The status is only fetched when requested.
+	indexes, err := mgr.GetAllIndexes("travel-sample", nil)
+	suite.Require().Nil(err, err)
+	suite.Require().Len(indexes, 2)
+	suite.Assert().Nil(indexes[0].ReplicaIDs)
+	mgmtProvider.AssertNotCalled(suite.T(), "executeMgmtRequest", nil, mock.Anything)
+
+	indexes, err = mgr.GetAllIndexes("travel-sample", &GetAllQueryIndexesOptions{IncludeStatus: true})
+	suite.Require().Nil(err, err)
+	suite.Require().Len(indexes, 2)
+
+	suite.Assert().Equal(1, indexes[0].NumReplicas)
+	suite.Assert().Equal([]int{0, 1}, indexes[0].ReplicaIDs)
+	suite.Assert().Zero(indexes[0].NumPartitions)
+	suite.Assert().Equal(time.Date(2024, 3, 12, 10, 20, 30, 0, time.UTC), indexes[0].LastScanTime.UTC())
+
+	suite.Assert().Equal(0, indexes[1].NumReplicas)
+	suite.Assert().Equal([]int{0}, indexes[1].ReplicaIDs)
+	suite.Assert().Equal(8, indexes[1].NumPartitions)
+	suite.Assert().True(indexes[1].LastScanTime.IsZero())
+}
+
+func (suite *UnitTestSuite) TestQueryIndexesGetIndexStats() {
+	// The replica of the index is hosted by the second index node, and the third node hosts neither.
+	nodeStats := map[string]string{
+		"http://10.0.0.1:9102": `{"travel-sample:def_type":{"items_count":917}}`,
+		"http://10.0.0.2:9102": `{"travel-sample:def_type (replica 1)":{"items_count":917}}`,
+	}
+
+	mgmtProvider := new(mockMgmtProvider)
+	mgmtProvider.
+		On("executeMgmtRequest", nil, mock.AnythingOfType("gocb.mgmtRequest")).
+		Return(func(ctx context.Context, req mgmtRequest) *mgmtResponse {
+			if req.Service == ServiceTypeManagement {
+				suite.Assert().Equal("/pools/default/nodeServices", req.Path)
+				return &mgmtResponse{
+					Endpoint:   "http://10.0.0.1:8091",
+					StatusCode: 200,
+					Body: io.NopCloser(bytes.NewReader([]byte(`{"nodesExt":[` +
+						`{"services":{"mgmt":8091,"indexHttp":9102,"indexHttps":19102},"hostname":"10.0.0.1"},` +
+						`{"services":{"mgmt":8091,"indexHttp":9102,"indexHttps":19102},"hostname":"10.0.0.2"},` +
+						`{"services":{"mgmt":8091,"indexHttp":9102,"indexHttps":19102},"hostname":"10.0.0.3"}]}`))),
+				}
+			}
+
+			suite.Assert().Equal(serviceTypeIndex, req.Service)
+			body, ok := nodeStats[req.Endpoint]
+			if !ok || req.Path != "/api/v1/stats/travel-sample._default._default/def_type" {
+				return &mgmtResponse{
+					Endpoint:   req.Endpoint,
+					StatusCode: 404,
+					Body:       io.NopCloser(bytes.NewReader(nil)),
+				}
+			}
+
+			return &mgmtResponse{
+				Endpoint:   req.Endpoint,
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+			}
+		}, nil)
+
+	mgr := QueryIndexManager{
+		controller: &providerController[queryIndexProvider]{
+			get: func() (queryIndexProvider, error) {
+				return &queryProviderCore{
+					mgmtProvider: mgmtProvider,
+					tracer:       newTracerWrapper(&NoopTracer{}),
+				}, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+
+	stats, err := mgr.GetIndexStats(Keyspace{Bucket: "travel-sample"}, "def_type", nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Contains(stats, "travel-sample:def_type")
+	suite.Assert().Contains(stats, "travel-sample:def_type (replica 1)")
+
+	_, err = mgr.GetIndexStats(Keyspace{Bucket: "travel-sample"}, "missing", nil)
+	suite.Assert().ErrorIs(err, ErrIndexNotFound)
+
+	_, err = mgr.GetIndexStats(Keyspace{}, "def_type", nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestQueryIndexesGetIndexStatsAlternateAddresses() {
+	// The SDK is connected using the external network, each node hosts a partition of the index.
+	nodeStats := map[string]string{
+		"https://node1.example.com:29102": `{"travel-sample:def_type":{"items_count":500,"avg_scan_latency":100}}`,
+		"https://node2.example.com:19102": `{"travel-sample:def_type":{"items_count":417,"avg_scan_latency":300}}`,
+	}
+
+	var deadline time.Time
+	mgmtProvider := new(mockMgmtProvider)
+	mgmtProvider.
+		On("executeMgmtRequest", nil, mock.AnythingOfType("gocb.mgmtRequest")).
+		Return(func(ctx context.Context, req mgmtRequest) *mgmtResponse {
+			// Every request shares the deadline of the operation.
+			suite.Assert().False(req.Deadline.IsZero())
+			if deadline.IsZero() {
+				deadline = req.Deadline
+			}
+			suite.Assert().Equal(deadline, req.Deadline)
+
+			if req.Service == ServiceTypeManagement {
+				return &mgmtResponse{
+					Endpoint:   "https://node1.example.com:28091",
+					StatusCode: 200,
+					Body: io.NopCloser(bytes.NewReader([]byte(`{"nodesExt":[` +
+						`{"services":{"mgmtSSL":18091,"indexHttps":19102},"hostname":"10.0.0.1",` +
+						`"alternateAddresses":{"external":{"hostname":"node1.example.com","ports":{"mgmtSSL":28091,"indexHttps":29102}}}},` +
+						`{"services":{"mgmtSSL":18091,"indexHttps":19102},"hostname":"10.0.0.2",` +
+						`"alternateAddresses":{"external":{"hostname":"node2.example.com"}}}]}`))),
+				}
+			}
+
+			body, ok := nodeStats[req.Endpoint]
+			suite.Require().True(ok, req.Endpoint)
+			return &mgmtResponse{
+				Endpoint:   req.Endpoint,
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+			}
+		}, nil)
+
+	mgr := QueryIndexManager{
+		controller: &providerController[queryIndexProvider]{
+			get: func() (queryIndexProvider, error) {
+				return &queryProviderCore{
+					mgmtProvider: mgmtProvider,
+					timeouts:     TimeoutsConfig{ManagementTimeout: 10 * time.Second},
+					tracer:       newTracerWrapper(&NoopTracer{}),
+				}, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+
+	stats, err := mgr.GetIndexStats(Keyspace{Bucket: "travel-sample"}, "def_type", nil)
+	suite.Require().Nil(err, err)
+	suite.Require().Contains(stats, "travel-sample:def_type")
+
+	indexStats := stats["travel-sample:def_type"].(map[string]interface{})
+	suite.Assert().Equal(float64(917), indexStats["items_count"])
+	// Averages are not summed across nodes.
+	suite.Assert().Contains([]interface{}{float64(100), float64(300)}, indexStats["avg_scan_latency"])
+	mgmtProvider.AssertNumberOfCalls(suite.T(), "executeMgmtRequest", 3)
+}
+
+func (suite *UnitTestSuite) TestQueryIndexesCreateIndexStatement() {
+	var statements []string
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("N1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(1).(gocbcore.N1QLQueryOptions)
+
+			var payload map[string]interface{}
+			suite.Require().Nil(json.Unmarshal(opts.Payload, &payload))
+			statements = append(statements, payload["statement"].(string))
+		}).
+		Return(&mockQueryIndexRowReader{mockQueryRowReaderBase: mockQueryRowReaderBase{Suite: suite}}, nil)
+
+	mgr := QueryIndexManager{
+		controller: &providerController[queryIndexProvider]{
+			get: func() (queryIndexProvider, error) {
+				return &queryProviderCore{
+					provider: provider,
+					tracer:   newTracerWrapper(&NoopTracer{}),
+				}, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+
+	err := mgr.CreateIndex("travel-sample", "idx_country", []string{"country", "name"}, &CreateQueryIndexOptions{
+		IncludeMissing: true,
+		Deferred:       true,
+		ScopeName:      "inventory",
+		CollectionName: "airline",
+	})
+	suite.Require().Nil(err, err)
+
+	err = mgr.CreateIndex("travel-sample", "idx_schedule", []string{"DISTINCT ARRAY FLATTEN_KEYS(s.day, s.flight) FOR s IN schedule END"},
+		&CreateQueryIndexOptions{
+			RawKeys:        true,
+			ScopeName:      "inventory",
+			CollectionName: "route",
+		})
+	suite.Require().Nil(err, err)
+
+	suite.Require().Len(statements, 2)
+	suite.Assert().Equal("CREATE INDEX `idx_country` ON `travel-sample`.`inventory`.`airline` (`country` INCLUDE MISSING, `name`) "+
+		"WITH {\"defer_build\":true}", statements[0])
+	suite.Assert().Equal("CREATE INDEX `idx_schedule` ON `travel-sample`.`inventory`.`route` "+
+		"(DISTINCT ARRAY FLATTEN_KEYS(s.day, s.flight) FOR s IN schedule END)", statements[1])
+}
@@ -17,7 +17,8 @@ type SearchIndex struct {
 	SourceName string
 	// Type is the type of index, e.g. fulltext-index or fulltext-alias.
 	Type string
-	// IndexParams are index properties such as store type and mappings.
+	// IndexParams are index properties such as store type and mappings. SetParams and DecodeParams can be used to
+	// work with these through SearchIndexParams.
 	Params map[string]interface{}
 	// SourceUUID is the UUID of the data source, this can be used to more tightly tie the index to a source.
 	SourceUUID string
@@ -218,6 +219,34 @@ func (sm *SearchIndexManager) AnalyzeDocument(indexName string, doc interface{},
 	})
 }
 
+// EnsureSearchIndexOptions is the set of options available to the search index EnsureIndex operation.
+// UNCOMMITTED: This API may change in the future.
+type EnsureSearchIndexOptions struct {
+	// UpdateIfDifferent updates the index if it already exists but any of the properties set in the definition
+	// differ from those of the existing index. Note that updating an index causes it to be rebuilt.
+	UpdateIfDifferent bool
+	// NoWait returns as soon as the index has been created or updated, rather than waiting until it can be queried.
+	NoWait bool
+
+	// Timeout bounds the entire operation, including waiting for the index to become usable.
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation.
+	Context context.Context
+}
+
+// EnsureIndex creates the index if it does not already exist, and then waits until the index can be queried.
+// UNCOMMITTED: This API may change in the future.
+func (sm *SearchIndexManager) EnsureIndex(indexDefinition SearchIndex, opts *EnsureSearchIndexOptions) error {
+	if opts == nil {
+		opts = &EnsureSearchIndexOptions{}
+	}
+
+	return ensureSearchIndex(sm, indexDefinition, opts)
+}
+
 // GetIndexedDocumentsCountOptions is the set of options available to the search index GetIndexedDocumentsCount operation.
 type GetIndexedDocumentsCountOptions struct {
 	Timeout       time.Duration
@@ -245,6 +274,76 @@ func (sm *SearchIndexManager) GetIndexedDocumentsCount(indexName string, opts *G
 	})
 }
 
+// SearchIndexedDocumentsCount is the number of documents indexed by a search index, broken down by index partition.
+// UNCOMMITTED: This API may change in the future.
+type SearchIndexedDocumentsCount struct {
+	Total uint64
+	// Partitions maps the name of each index partition hosted by the cluster to the number of documents it has
+	// indexed.
+	Partitions map[string]uint64
+}
+
+// GetIndexedDocumentsCountByPartitionOptions is the set of options available to the search index
+// GetIndexedDocumentsCountByPartition operation.
+// UNCOMMITTED: This API may change in the future.
+type GetIndexedDocumentsCountByPartitionOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// GetIndexedDocumentsCountByPartition retrieves the document count for each partition of a search index, which can
+// be used to monitor the progress of an index build. The partitions are gathered from every search node.
+// UNCOMMITTED: This API may change in the future.
+func (sm *SearchIndexManager) GetIndexedDocumentsCountByPartition(indexName string,
+	opts *GetIndexedDocumentsCountByPartitionOptions) (*SearchIndexedDocumentsCount, error) {
+	return autoOpControl(sm.controller, "manager_search_get_indexed_documents_count_by_partition", func(provider searchIndexProvider) (*SearchIndexedDocumentsCount, error) {
+		if opts == nil {
+			opts = &GetIndexedDocumentsCountByPartitionOptions{}
+		}
+
+		if indexName == "" {
+			return nil, invalidArgumentsError{"indexName cannot be empty"}
+		}
+
+		return provider.GetIndexedDocumentsCountByPartition(nil, indexName, opts)
+	})
+}
+
+// GetSearchIndexStatsOptions is the set of options available to the search index GetIndexStats operation.
+// UNCOMMITTED: This API may change in the future.
+type GetSearchIndexStatsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// GetIndexStats retrieves the statistics of a search index, such as doc_count and num_mutations_to_index, keyed by
+// name. The statistics are gathered from every search node, with numeric statistics summed across the nodes. The set
+// of statistics returned depends upon the server version.
+// UNCOMMITTED: This API may change in the future.
+func (sm *SearchIndexManager) GetIndexStats(indexName string, opts *GetSearchIndexStatsOptions) (map[string]interface{}, error) {
+	return autoOpControl(sm.controller, "manager_search_get_stats", func(provider searchIndexProvider) (map[string]interface{}, error) {
+		if opts == nil {
+			opts = &GetSearchIndexStatsOptions{}
+		}
+
+		if indexName == "" {
+			return nil, invalidArgumentsError{"indexName cannot be empty"}
+		}
+
+		return provider.GetIndexStats(nil, indexName, opts)
+	})
+}
+
 // PauseIngestSearchIndexOptions is the set of options available to the search index PauseIngest operation.
 type PauseIngestSearchIndexOptions struct {
 	Timeout       time.Duration
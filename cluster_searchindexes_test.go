@@ -1,7 +1,10 @@
+//go:build !gocb_nosearch
+
 package gocb
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -344,6 +347,80 @@ func (suite *UnitTestSuite) TestSearchIndexesAnalyzeDocumentCore() {
 	suite.Require().NotNil(res)
 }
 
+func (suite *UnitTestSuite) TestSearchIndexesStatsCore() {
+	// Each node reports only on the partitions that it hosts, the second node hosts a replica of the first partition.
+	responses := map[string]map[string]string{
+		"https://10.0.0.1:18094": {
+			"/api/stats/index/travel-sample.inventory.hotels": `{"feeds":{},"pindexes":{` +
+				`"hotels_13aa53f3_4c1a7b2a":{"basic":{"DocCount":700},"partitions":{}}}}`,
+			"/api/nsstats/index/travel-sample.inventory.hotels": `{"doc_count":700,"num_mutations_to_index":0}`,
+		},
+		"https://10.0.0.2:18094": {
+			"/api/stats/index/travel-sample.inventory.hotels": `{"feeds":{},"pindexes":{` +
+				`"hotels_13aa53f3_4c1a7b2a":{"basic":{"DocCount":698},"partitions":{}},` +
+				`"hotels_13aa53f3_54820232":{"basic":{"DocCount":217},"partitions":{}}}}`,
+			"/api/nsstats/index/travel-sample.inventory.hotels": `{"doc_count":217,"num_mutations_to_index":2}`,
+		},
+	}
+
+	mockProvider := new(mockMgmtProvider)
+	mockProvider.
+		On("executeMgmtRequest", nil, mock.AnythingOfType("gocb.mgmtRequest")).
+		Return(func(ctx context.Context, req mgmtRequest) *mgmtResponse {
+			suite.Assert().Equal("GET", req.Method)
+			suite.Assert().True(req.IsIdempotent)
+
+			if req.Service == ServiceTypeManagement {
+				suite.Assert().Equal("/pools/default/nodeServices", req.Path)
+				return &mgmtResponse{
+					Endpoint:   "https://10.0.0.1:18091",
+					StatusCode: 200,
+					Body: io.NopCloser(bytes.NewReader([]byte(`{"nodesExt":[` +
+						`{"services":{"mgmt":8091,"fts":8094,"ftsSSL":18094},"hostname":"10.0.0.1"},` +
+						`{"services":{"mgmt":8091,"kv":11210},"hostname":"10.0.0.3"},` +
+						`{"services":{"mgmt":8091,"fts":8094,"ftsSSL":18094},"hostname":"10.0.0.2"}]}`))),
+				}
+			}
+
+			suite.Assert().Equal(ServiceTypeSearch, req.Service)
+			body, ok := responses[req.Endpoint][req.Path]
+			suite.Require().True(ok, req.Endpoint+req.Path)
+			return &mgmtResponse{
+				Endpoint:   req.Endpoint,
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+			}
+		}, nil)
+
+	capVerifier := new(mockSearchCapabilityVerifier)
+	capVerifier.
+		On("SearchCapabilityStatus", mock.AnythingOfType("gocbcore.SearchCapability")).
+		Return(gocbcore.CapabilityStatusSupported)
+
+	mgr := &searchIndexProviderCore{
+		mgmtProvider:      mockProvider,
+		searchCapVerifier: capVerifier,
+		tracer:            newTracerWrapper(&NoopTracer{}),
+	}
+
+	scope := suite.newScope(suite.bucket("travel-sample", suite.defaultTimeoutConfig(), nil), "inventory")
+
+	count, err := mgr.GetIndexedDocumentsCountByPartition(scope, "hotels", &GetIndexedDocumentsCountByPartitionOptions{})
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(&SearchIndexedDocumentsCount{
+		Total: 917,
+		Partitions: map[string]uint64{
+			"hotels_13aa53f3_4c1a7b2a": 700,
+			"hotels_13aa53f3_54820232": 217,
+		},
+	}, count)
+
+	stats, err := mgr.GetIndexStats(scope, "hotels", &GetSearchIndexStatsOptions{})
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(float64(917), stats["doc_count"])
+	suite.Assert().Equal(float64(2), stats["num_mutations_to_index"])
+}
+
 func (suite *UnitTestSuite) TestSearchIndexSerialization() {
 	source := []byte(`{"name":"test","type":"fulltext-index","sourceType":"couchbase","sourceName":"bucket"}`)
 	var index SearchIndex
@@ -371,3 +448,280 @@ func (suite *UnitTestSuite) TestSearchIndexCanDeserializeFromUI() {
 
 	suite.Assert().Equal("test", index.Name)
 }
+
+func (suite *UnitTestSuite) TestSearchIndexSetParams() {
+	index := SearchIndex{
+		Name:       "vectors",
+		Type:       "fulltext-index",
+		SourceName: "default",
+	}
+
+	field := NewSearchVectorFieldMapping("embedding", 3, SearchVectorSimilarityDotProduct)
+	field.VectorIndexOptimizedFor = SearchVectorOptimizationLatency
+	err := index.SetParams(SearchIndexParams{
+		DocConfig: &SearchIndexDocConfig{
+			Mode:      "scope.collection.type_field",
+			TypeField: "type",
+		},
+		Mapping: &SearchIndexMapping{
+			Types: map[string]SearchDocumentMapping{
+				"_default._default": {
+					Enabled: true,
+					Properties: map[string]SearchDocumentMapping{
+						"embedding": {
+							Enabled: true,
+							Fields:  []SearchFieldMapping{field},
+						},
+					},
+				},
+			},
+			DefaultAnalyzer: "lower",
+			Analysis: &SearchIndexAnalysis{
+				Analyzers: map[string]SearchCustomAnalyzer{
+					"lower": {
+						Tokenizer:    "unicode",
+						TokenFilters: []string{"to_lower"},
+					},
+				},
+			},
+		},
+	})
+	suite.Require().NoError(err)
+
+	expected := map[string]interface{}{
+		"doc_config": map[string]interface{}{
+			"mode":       "scope.collection.type_field",
+			"type_field": "type",
+		},
+		"mapping": map[string]interface{}{
+			"types": map[string]interface{}{
+				"_default._default": map[string]interface{}{
+					"enabled": true,
+					"dynamic": false,
+					"properties": map[string]interface{}{
+						"embedding": map[string]interface{}{
+							"enabled": true,
+							"dynamic": false,
+							"fields": []interface{}{
+								map[string]interface{}{
+									"name":                       "embedding",
+									"type":                       "vector",
+									"index":                      true,
+									"dims":                       float64(3),
+									"similarity":                 "dot_product",
+									"vector_index_optimized_for": "latency",
+								},
+							},
+						},
+					},
+				},
+			},
+			"default_analyzer":  "lower",
+			"store_dynamic":     false,
+			"index_dynamic":     false,
+			"docvalues_dynamic": false,
+			"analysis": map[string]interface{}{
+				"analyzers": map[string]interface{}{
+					"lower": map[string]interface{}{
+						"type":          "custom",
+						"tokenizer":     "unicode",
+						"token_filters": []interface{}{"to_lower"},
+					},
+				},
+			},
+		},
+	}
+	suite.Assert().Equal(expected, index.Params)
+
+	params, err := index.DecodeParams()
+	suite.Require().NoError(err)
+	suite.Assert().Equal(field, params.Mapping.Types["_default._default"].Properties["embedding"].Fields[0])
+	suite.Assert().Equal("custom", params.Mapping.Analysis.Analyzers["lower"].Type)
+
+	err = index.SetParams(SearchIndexParams{
+		Mapping: &SearchIndexMapping{
+			DefaultMapping: &SearchDocumentMapping{
+				Enabled: true,
+				Fields:  []SearchFieldMapping{{Name: "embedding", Type: SearchFieldTypeVector, Index: true}},
+			},
+		},
+	})
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestSearchIndexDecodeParamsFromUI() {
+	data, err := loadRawTestDataset("uisearchindex")
+	suite.Require().NoError(err)
+
+	var index SearchIndex
+	err = json.Unmarshal(data, &index)
+	suite.Require().NoError(err)
+
+	params, err := index.DecodeParams()
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal("type_field", params.DocConfig.Mode)
+	suite.Assert().Equal("standard", params.Mapping.DefaultAnalyzer)
+	suite.Assert().True(params.Mapping.DefaultMapping.Enabled)
+	suite.Assert().True(params.Mapping.DefaultMapping.Dynamic)
+	suite.Assert().True(params.Mapping.IndexDynamic)
+	suite.Assert().False(params.Mapping.StoreDynamic)
+	suite.Assert().Equal("scorch", params.Store["indexType"])
+	suite.Assert().Equal("regexp", params.Mapping.Analysis.CharFilters["a"]["type"])
+
+	params, err = (&SearchIndex{Params: map[string]interface{}{
+		"mapping": map[string]interface{}{"default_mapping": map[string]interface{}{}},
+	}}).DecodeParams()
+	suite.Require().NoError(err)
+	suite.Assert().True(params.Mapping.DefaultMapping.Enabled)
+	suite.Assert().True(params.Mapping.DocValuesDynamic)
+}
+
+type fakeSearchIndexEnsurer struct {
+	index      *SearchIndex
+	upserts    []SearchIndex
+	countCalls int
+}
+
+func (f *fakeSearchIndexEnsurer) GetIndex(string, *GetSearchIndexOptions) (*SearchIndex, error) {
+	if f.index == nil {
+		return nil, ErrIndexNotFound
+	}
+
+	return f.index, nil
+}
+
+func (f *fakeSearchIndexEnsurer) UpsertIndex(index SearchIndex, _ *UpsertSearchIndexOptions) error {
+	f.upserts = append(f.upserts, index)
+	return nil
+}
+
+func (f *fakeSearchIndexEnsurer) GetIndexedDocumentsCount(string, *GetIndexedDocumentsCountOptions) (uint64, error) {
+	f.countCalls++
+	if f.countCalls < 3 {
+		return 0, ErrIndexNotFound
+	}
+
+	return 0, nil
+}
+
+func (suite *UnitTestSuite) TestEnsureSearchIndex() {
+	desired := SearchIndex{
+		Name:       "hotels",
+		Type:       "fulltext-index",
+		SourceName: "travel-sample",
+		Params:     map[string]interface{}{"doc_config": map[string]interface{}{"mode": "type_field"}},
+	}
+
+	fake := &fakeSearchIndexEnsurer{}
+	err := ensureSearchIndex(fake, desired, &EnsureSearchIndexOptions{Timeout: 5 * time.Second})
+	suite.Require().Nil(err, err)
+	suite.Assert().Len(fake.upserts, 1)
+	suite.Assert().Equal(3, fake.countCalls)
+
+	existing := desired
+	existing.UUID = "abc"
+	existing.SourceUUID = "def"
+	existing.PlanParams = map[string]interface{}{"numReplicas": float64(0)}
+	existing.Params = map[string]interface{}{
+		"doc_config": map[string]interface{}{"mode": "type_field", "type_field": "type"},
+		"store":      map[string]interface{}{"indexType": "scorch"},
+	}
+
+	fake = &fakeSearchIndexEnsurer{index: &existing}
+	err = ensureSearchIndex(fake, desired, &EnsureSearchIndexOptions{UpdateIfDifferent: true, NoWait: true})
+	suite.Require().Nil(err, err)
+	suite.Assert().Empty(fake.upserts)
+
+	desired.PlanParams = map[string]interface{}{"numReplicas": 1}
+	err = ensureSearchIndex(fake, desired, &EnsureSearchIndexOptions{NoWait: true})
+	suite.Require().Nil(err, err)
+	suite.Assert().Empty(fake.upserts)
+
+	err = ensureSearchIndex(fake, desired, &EnsureSearchIndexOptions{UpdateIfDifferent: true, NoWait: true})
+	suite.Require().Nil(err, err)
+	suite.Require().Len(fake.upserts, 1)
+	suite.Assert().Equal("abc", fake.upserts[0].UUID)
+}
+
+func (suite *UnitTestSuite) TestExportImportSearchIndex() {
+	existing := SearchIndex{
+		UUID:       "abc",
+		Name:       "travel-sample.inventory.hotels",
+		Type:       "fulltext-index",
+		SourceName: "travel-sample",
+		SourceUUID: "def",
+		Params:     map[string]interface{}{"doc_config": map[string]interface{}{"mode": "scope.collection.type_field"}},
+	}
+
+	fake := &fakeSearchIndexEnsurer{index: &existing}
+	definition, err := exportSearchIndex(fake, "hotels", &ExportSearchIndexOptions{})
+	suite.Require().Nil(err, err)
+
+	var exported map[string]interface{}
+	suite.Require().Nil(json.Unmarshal(definition, &exported))
+	suite.Assert().Equal("hotels", exported["name"])
+	suite.Assert().Empty(exported["uuid"])
+	suite.Assert().Empty(exported["sourceUUID"])
+
+	fake = &fakeSearchIndexEnsurer{}
+	err = importSearchIndex(fake, "", definition, &ImportSearchIndexOptions{SourceName: "travel-sample-prod"})
+	suite.Require().Nil(err, err)
+	suite.Require().Len(fake.upserts, 1)
+	suite.Assert().Equal("hotels", fake.upserts[0].Name)
+	suite.Assert().Equal("travel-sample-prod", fake.upserts[0].SourceName)
+	suite.Assert().Empty(fake.upserts[0].UUID)
+	suite.Assert().Empty(fake.upserts[0].SourceUUID)
+
+	live := existing
+	live.UUID = "xyz"
+	fake = &fakeSearchIndexEnsurer{index: &live}
+	err = importSearchIndex(fake, "", definition, &ImportSearchIndexOptions{})
+	suite.Require().Nil(err, err)
+	suite.Require().Len(fake.upserts, 1)
+	suite.Assert().Equal("xyz", fake.upserts[0].UUID)
+	suite.Assert().Equal("travel-sample", fake.upserts[0].SourceName)
+
+	err = importSearchIndex(fake, "", []byte("{"), &ImportSearchIndexOptions{})
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestImportSearchIndexIntoScope() {
+	definition := []byte(`{
+		"name": "hotels",
+		"type": "fulltext-index",
+		"sourceName": "travel-sample",
+		"params": {
+			"doc_config": {"mode": "scope.collection.type_field", "type_field": "type"},
+			"mapping": {
+				"default_analyzer": "standard",
+				"types": {
+					"inventory.hotel": {"enabled": true},
+					"inventory.landmark.museum": {"enabled": false}
+				}
+			}
+		}
+	}`)
+
+	fake := &fakeSearchIndexEnsurer{}
+	err := importSearchIndex(fake, "staging", definition, &ImportSearchIndexOptions{})
+	suite.Require().Nil(err, err)
+	suite.Require().Len(fake.upserts, 1)
+
+	mapping := fake.upserts[0].Params["mapping"].(map[string]interface{})
+	suite.Assert().Equal("standard", mapping["default_analyzer"])
+	suite.Assert().Equal(map[string]interface{}{
+		"staging.hotel":           map[string]interface{}{"enabled": true},
+		"staging.landmark.museum": map[string]interface{}{"enabled": false},
+	}, mapping["types"])
+
+	// Indexes which do not map documents by collection are imported as is.
+	fake = &fakeSearchIndexEnsurer{}
+	err = importSearchIndex(fake, "staging", []byte(`{
+		"name": "hotels",
+		"params": {"doc_config": {"mode": "type_field"}, "mapping": {"types": {"hotel.v1": {"enabled": true}}}}
+	}`), &ImportSearchIndexOptions{})
+	suite.Require().Nil(err, err)
+	suite.Require().Len(fake.upserts, 1)
+	suite.Assert().Contains(fake.upserts[0].Params["mapping"].(map[string]interface{})["types"], "hotel.v1")
+}
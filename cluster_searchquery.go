@@ -65,6 +65,11 @@ func (metrics *SearchMetrics) fromData(data jsonSearchResponse) error {
 type SearchMetaData struct {
 	Metrics SearchMetrics
 	Errors  map[string]string
+
+	// CollapsedHits is the total number of hits which were discarded due to SearchOptions.CollapseField. Metrics
+	// reports the hits as returned by the server, before collapsing.
+	// UNCOMMITTED: This API may change in the future.
+	CollapsedHits uint64
 }
 
 func (meta *SearchMetaData) fromData(data jsonSearchResponse) error {
@@ -101,6 +106,64 @@ type SearchDateRangeFacetResult struct {
 	Count int
 }
 
+// StartTime parses the start of the date range, returning the zero time if the range has no start.
+// UNCOMMITTED: This API may change in the future.
+func (dr SearchDateRangeFacetResult) StartTime() (time.Time, error) {
+	return parseSearchFacetDate(dr.Start)
+}
+
+// EndTime parses the end of the date range, returning the zero time if the range has no end.
+// UNCOMMITTED: This API may change in the future.
+func (dr SearchDateRangeFacetResult) EndTime() (time.Time, error) {
+	return parseSearchFacetDate(dr.End)
+}
+
+func parseSearchFacetDate(date string) (time.Time, error) {
+	if date == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return time.Time{}, wrapError(err, "failed to parse search facet date")
+	}
+
+	return t, nil
+}
+
+// SearchTermFacet holds the result of a term facet.
+// UNCOMMITTED: This API may change in the future.
+type SearchTermFacet struct {
+	Name    string
+	Field   string
+	Total   uint64
+	Missing uint64
+	Other   uint64
+	Terms   []SearchTermFacetResult
+}
+
+// SearchNumericRangeFacet holds the result of a numeric range facet.
+// UNCOMMITTED: This API may change in the future.
+type SearchNumericRangeFacet struct {
+	Name          string
+	Field         string
+	Total         uint64
+	Missing       uint64
+	Other         uint64
+	NumericRanges []SearchNumericRangeFacetResult
+}
+
+// SearchDateRangeFacet holds the result of a date range facet.
+// UNCOMMITTED: This API may change in the future.
+type SearchDateRangeFacet struct {
+	Name       string
+	Field      string
+	Total      uint64
+	Missing    uint64
+	Other      uint64
+	DateRanges []SearchDateRangeFacetResult
+}
+
 // SearchFacetResult provides access to the result of a faceted query.
 type SearchFacetResult struct {
 	Name          string
@@ -157,10 +220,65 @@ type SearchRow struct {
 	Explanation interface{}
 	Locations   map[string]map[string][]SearchRowLocation
 	Fragments   map[string][]string
-	fieldsBytes []byte
+
+	// GeoDistance is the distance of the hit from the location of the first SearchSortGeoDistance in the request,
+	// in the unit requested by that sort. This is nil if the request was not sorted by geo distance or the distance
+	// was not returned by the server.
+	// UNCOMMITTED: This API may change in the future.
+	GeoDistance *float64
+
+	// CollapsedHits is the number of other hits with the same value for SearchOptions.CollapseField which were
+	// discarded in favour of this one.
+	// UNCOMMITTED: This API may change in the future.
+	CollapsedHits uint64
+
+	// Sort is the sort key of the hit, which can be passed to SearchOptions.SearchAfter or SearchBefore.
+	// UNCOMMITTED: This API may change in the future.
+	Sort []string
+
+	rowBytes         []byte
+	fieldsBytes      []byte
+	explanationBytes []byte
 }
 
 // Fields decodes the fields included in a search hit.
 func (sr *SearchRow) Fields(valuePtr interface{}) error {
 	return json.Unmarshal(sr.fieldsBytes, valuePtr)
 }
+
+// RawFields returns the raw JSON of the fields included in a search hit, this is nil if no fields were requested.
+// UNCOMMITTED: This API may change in the future.
+func (sr *SearchRow) RawFields() json.RawMessage {
+	return sr.fieldsBytes
+}
+
+// RawHit returns the raw JSON of the search hit, as it was returned by the server.
+// UNCOMMITTED: This API may change in the future.
+func (sr *SearchRow) RawHit() json.RawMessage {
+	return sr.rowBytes
+}
+
+// SearchExplanation is a node in the tree explaining how the score of a search hit was computed. The Value of each
+// node is derived from the values of its Children.
+// UNCOMMITTED: This API may change in the future.
+type SearchExplanation struct {
+	Value    float64             `json:"value"`
+	Message  string              `json:"message"`
+	Children []SearchExplanation `json:"children,omitempty"`
+}
+
+// ExplanationTree decodes the explanation of the score of a search hit, this is nil if SearchOptions.Explain was
+// not set.
+// UNCOMMITTED: This API may change in the future.
+func (sr *SearchRow) ExplanationTree() (*SearchExplanation, error) {
+	if len(sr.explanationBytes) == 0 || string(sr.explanationBytes) == "null" {
+		return nil, nil
+	}
+
+	var explanation SearchExplanation
+	if err := json.Unmarshal(sr.explanationBytes, &explanation); err != nil {
+		return nil, err
+	}
+
+	return &explanation, nil
+}
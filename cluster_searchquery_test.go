@@ -1,9 +1,12 @@
+//go:build !gocb_nosearch
+
 package gocb
 
 import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math"
 	"time"
 
 	"github.com/stretchr/testify/mock"
@@ -562,3 +565,430 @@ func (suite *UnitTestSuite) TestClusterSearchEmptyBucketAndScopeNames() {
 	_, err := cluster.Search("testindex", request, nil)
 	suite.Require().Nil(err, err)
 }
+
+func testPrefixCodedSearchSortDistance(distance float64) string {
+	bits := math.Float64bits(distance)
+	if int64(bits) < 0 {
+		bits ^= 0x7fffffffffffffff
+	}
+	sortableBits := bits ^ 0x8000000000000000
+
+	buf := make([]byte, 11)
+	buf[0] = 0x20
+	for i := 10; i > 0; i-- {
+		buf[i] = byte(sortableBits & 0x7f)
+		sortableBits >>= 7
+	}
+
+	return string(buf)
+}
+
+func (suite *UnitTestSuite) TestSearchQueryGeoDistanceSort() {
+	reader := &mockSearchRowReader{
+		Dataset: []jsonSearchRow{
+			{ID: "prefixcoded", Sort: []string{"_score", testPrefixCodedSearchSortDistance(12.5)}},
+			{ID: "decoded", Sort: []string{"_score", "3.25"}},
+			{ID: "nosort"},
+		},
+		Meta:  []byte("{}"),
+		Suite: suite,
+	}
+
+	cluster := suite.searchCluster(reader, func(args mock.Arguments) {
+		opts := args.Get(1).(gocbcore.SearchQueryOptions)
+
+		var actualOptions map[string]interface{}
+		err := json.Unmarshal(opts.Payload, &actualOptions)
+		suite.Require().Nil(err)
+
+		s := actualOptions["sort"].([]interface{})
+		suite.Require().Len(s, 2)
+		srt := s[1].(map[string]interface{})
+		suite.Assert().Equal("geo_distance", srt["by"])
+		suite.Assert().Equal("kilometers", srt["unit"])
+	})
+
+	result, err := cluster.SearchQuery("testindex", search.NewMatchAllQuery(), &SearchOptions{
+		Sort: []search.Sort{
+			search.NewSearchSortScore(),
+			search.NewSearchSortGeoDistance("geo", -2.23, 53.48).Unit("KM"),
+		},
+	})
+	suite.Require().Nil(err, err)
+
+	var hits []SearchRow
+	for result.Next() {
+		hits = append(hits, result.Row())
+	}
+	suite.Require().Nil(result.Err())
+	suite.Require().Len(hits, 3)
+
+	suite.Require().NotNil(hits[0].GeoDistance)
+	suite.Assert().Equal(12.5, *hits[0].GeoDistance)
+	suite.Require().NotNil(hits[1].GeoDistance)
+	suite.Assert().Equal(3.25, *hits[1].GeoDistance)
+	suite.Assert().Nil(hits[2].GeoDistance)
+}
+
+func (suite *UnitTestSuite) TestSearchQueryGeoDistanceSortInvalidUnit() {
+	reader := &mockSearchRowReader{
+		Dataset: []jsonSearchRow{},
+		Meta:    []byte("{}"),
+		Suite:   suite,
+	}
+
+	cluster := suite.searchCluster(reader, func(args mock.Arguments) {
+		suite.Fail("request should not have been sent")
+	})
+
+	_, err := cluster.SearchQuery("testindex", search.NewMatchAllQuery(), &SearchOptions{
+		Sort: []search.Sort{
+			search.NewSearchSortGeoDistance("geo", -2.23, 53.48).Unit("furlongs"),
+		},
+	})
+	suite.Require().NotNil(err)
+}
+
+func (suite *UnitTestSuite) TestSearchQueryDSLAdditions() {
+	reader := &mockSearchRowReader{
+		Dataset: []jsonSearchRow{},
+		Meta:    []byte("{}"),
+		Suite:   suite,
+	}
+
+	cluster := suite.searchCluster(reader, func(args mock.Arguments) {
+		opts := args.Get(1).(gocbcore.SearchQueryOptions)
+
+		var actualOptions map[string]interface{}
+		err := json.Unmarshal(opts.Payload, &actualOptions)
+		suite.Require().Nil(err)
+
+		query := actualOptions["query"].(map[string]interface{})
+		conjuncts := query["conjuncts"].([]interface{})
+		suite.Require().Len(conjuncts, 2)
+
+		suite.Assert().Equal(map[string]interface{}{
+			"polygon_points": []interface{}{
+				[]interface{}{-2.1, 53.5},
+				[]interface{}{-2.3, 53.4},
+				[]interface{}{-2.2, 53.3},
+			},
+			"field": "geo",
+		}, conjuncts[0])
+
+		suite.Assert().Equal(map[string]interface{}{
+			"disjuncts": []interface{}{
+				map[string]interface{}{"ids": []interface{}{"beer1", "beer2"}},
+				map[string]interface{}{"ids": []interface{}{"beer3"}, "boost": 2.5},
+			},
+			"boost": 1.5,
+		}, conjuncts[1])
+
+		s := actualOptions["sort"].([]interface{})
+		suite.Require().Len(s, 1)
+		srt := s[0].(map[string]interface{})
+		suite.Assert().Equal("geo_distance", srt["by"])
+		suite.Assert().Equal([]interface{}{-2.23, 53.48}, srt["location"])
+		suite.Assert().Equal("miles", srt["unit"])
+	})
+
+	polygon := search.NewGeoPolygonQueryFromPoints(search.LatLon(53.5, -2.1), search.LatLon(53.4, -2.3)).
+		AddPoints(search.LatLon(53.3, -2.2)).
+		Field("geo")
+	suite.Assert().Equal(search.Coordinate{Lon: -2.2, Lat: 53.3}, polygon.Points()[2])
+
+	docIDs := search.NewDocIDQuery("beer1", "beer2").AddBoostedDocID("beer3", 2.5).Boost(1.5)
+
+	_, err := cluster.SearchQuery("testindex", search.NewConjunctionQuery(polygon, docIDs), &SearchOptions{
+		Sort: []search.Sort{
+			search.NewSearchSortGeoDistanceFrom("geo", search.LatLon(53.48, -2.23)).InMiles().Ascending(),
+		},
+	})
+	suite.Require().Nil(err, err)
+
+	b, err := json.Marshal(search.NewGeoPolygonQueryFromPoints(search.LatLon(53.5, -2.1), search.LatLon(53.4, -2.3)))
+	suite.Require().Nil(err, err)
+	suite.Assert().JSONEq(`{"polygon_points":[[-2.1,53.5],[-2.3,53.4]]}`, string(b))
+}
+
+func (suite *UnitTestSuite) TestSearchQueryCollapseField() {
+	reader := &mockSearchRowReader{
+		Dataset: []jsonSearchRow{
+			{ID: "beer1", Score: 0.9, Fields: json.RawMessage(`{"brewery_id":"brewery1","name":"a"}`)},
+			{ID: "beer2", Score: 0.8, Fields: json.RawMessage(`{"brewery_id":"brewery2","name":"b"}`)},
+			{ID: "beer3", Score: 0.7, Fields: json.RawMessage(`{"brewery_id":"brewery1","name":"c"}`)},
+			{ID: "beer4", Score: 0.95, Fields: json.RawMessage(`{"brewery_id":"brewery2","name":"d"}`)},
+			{ID: "beer5", Score: 0.6, Fields: json.RawMessage(`{"name":"e"}`)},
+			{ID: "beer6", Score: 0.5, Fields: json.RawMessage(`{"brewery_id":"brewery1","name":"f"}`)},
+		},
+		Meta:  []byte(`{"total_hits":6}`),
+		Suite: suite,
+	}
+
+	cluster := suite.searchCluster(reader, func(args mock.Arguments) {
+		opts := args.Get(1).(gocbcore.SearchQueryOptions)
+
+		var actualOptions map[string]interface{}
+		err := json.Unmarshal(opts.Payload, &actualOptions)
+		suite.Require().Nil(err)
+
+		suite.Assert().Equal([]interface{}{"name", "brewery_id"}, actualOptions["fields"])
+	})
+
+	result, err := cluster.SearchQuery("testindex", search.NewMatchAllQuery(), &SearchOptions{
+		Fields:        []string{"name"},
+		CollapseField: "brewery_id",
+	})
+	suite.Require().Nil(err, err)
+
+	var ids []string
+	var collapsed []uint64
+	for result.Next() {
+		row := result.Row()
+		ids = append(ids, row.ID)
+		collapsed = append(collapsed, row.CollapsedHits)
+	}
+	suite.Require().Nil(result.Err())
+
+	// The best scoring hit for each brewery is kept, in the position of the first hit for that brewery.
+	suite.Assert().Equal([]string{"beer1", "beer4", "beer5"}, ids)
+	suite.Assert().Equal([]uint64{2, 1, 0}, collapsed)
+
+	meta, err := result.MetaData()
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(uint64(3), meta.CollapsedHits)
+	suite.Assert().Equal(uint64(6), meta.Metrics.TotalRows)
+}
+
+// streamingSearchRowReader only makes the metadata available once all of the rows have been read, as with the
+// gocbcore streamer.
+type streamingSearchRowReader struct {
+	*mockSearchRowReader
+}
+
+func (arr *streamingSearchRowReader) MetaData() ([]byte, error) {
+	if arr.idx < len(arr.Dataset) {
+		return nil, errors.New("the result must be fully read before accessing the meta-data")
+	}
+
+	return arr.mockSearchRowReader.MetaData()
+}
+
+func (suite *UnitTestSuite) TestSearchQueryTypedFacets() {
+	reader := &streamingSearchRowReader{&mockSearchRowReader{
+		Dataset: []jsonSearchRow{{ID: "a"}, {ID: "b"}},
+		Meta: []byte(`{"total_hits": 2, "facets": {
+			"countries": {"field": "country", "total": 10, "missing": 1, "other": 2,
+				"terms": [{"term": "uk", "count": 4}, {"term": "us", "count": 3}]},
+			"altitudes": {"field": "alt", "total": 5, "missing": 3,
+				"numeric_ranges": [{"name": "low", "min": 0, "max": 100, "count": 5}]},
+			"opened": {"field": "opened", "total": 1,
+				"date_ranges": [{"name": "old", "end": "2000-01-01T00:00:00Z", "count": 1}]},
+			"empty": {"field": "other", "total": 0}
+		}}`),
+		Suite: suite,
+	}}
+
+	cluster := suite.searchCluster(reader, func(args mock.Arguments) {})
+
+	result, err := cluster.SearchQuery("testindex", search.NewMatchAllQuery(), &SearchOptions{
+		Facets: map[string]search.Facet{
+			"countries": search.NewTermFacet("country", 2),
+			"altitudes": search.NewNumericFacet("alt", 1).AddRange("low", 0, 100),
+			"opened":    search.NewDateFacet("opened", 1).AddRange("old", "", "2000-01-01T00:00:00Z"),
+			"empty":     search.NewTermFacet("other", 1),
+		},
+	})
+	suite.Require().Nil(err, err)
+
+	// The facets are accessed before the rows have been iterated.
+	terms, err := result.TermFacet("countries")
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(&SearchTermFacet{
+		Field:   "country",
+		Total:   10,
+		Missing: 1,
+		Other:   2,
+		Terms:   []SearchTermFacetResult{{Term: "uk", Count: 4}, {Term: "us", Count: 3}},
+	}, terms)
+
+	numeric, err := result.NumericRangeFacet("altitudes")
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(uint64(3), numeric.Missing)
+	suite.Assert().Equal([]SearchNumericRangeFacetResult{{Name: "low", Min: 0, Max: 100, Count: 5}}, numeric.NumericRanges)
+
+	dates, err := result.DateRangeFacet("opened")
+	suite.Require().Nil(err, err)
+	suite.Require().Len(dates.DateRanges, 1)
+	start, err := dates.DateRanges[0].StartTime()
+	suite.Require().Nil(err, err)
+	suite.Assert().True(start.IsZero())
+	end, err := dates.DateRanges[0].EndTime()
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC), end)
+
+	empty, err := result.TermFacet("empty")
+	suite.Require().Nil(err, err)
+	suite.Assert().Empty(empty.Terms)
+
+	_, err = result.DateRangeFacet("countries")
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+	_, err = result.NumericRangeFacet("empty")
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+	_, err = result.TermFacet("missing")
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	var ids []string
+	for result.Next() {
+		ids = append(ids, result.Row().ID)
+	}
+	suite.Require().Nil(result.Err())
+	suite.Assert().Equal([]string{"a", "b"}, ids)
+	suite.Require().Nil(result.Close())
+}
+
+func (suite *UnitTestSuite) TestSearchQuerySearchAfter() {
+	reader := &mockSearchRowReader{
+		Dataset: []jsonSearchRow{
+			{ID: "airline_10", Sort: []string{"UK", "airline_10"}},
+			{ID: "airline_20", Sort: []string{"UK", "airline_20"}},
+		},
+		Meta:  []byte(`{"total_hits": 40}`),
+		Suite: suite,
+	}
+
+	var payloads []map[string]interface{}
+	cluster := suite.searchCluster(reader, func(args mock.Arguments) {
+		opts := args.Get(1).(gocbcore.SearchQueryOptions)
+
+		var actualOptions map[string]interface{}
+		suite.Require().Nil(json.Unmarshal(opts.Payload, &actualOptions))
+		payloads = append(payloads, actualOptions)
+	})
+
+	sort := []search.Sort{search.NewSearchSortField("country"), search.NewSearchSortID()}
+	result, err := cluster.SearchQuery("testindex", search.NewMatchAllQuery(), &SearchOptions{
+		Limit:       2,
+		Sort:        sort,
+		SearchAfter: []string{"UK", "airline_5"},
+	})
+	suite.Require().Nil(err, err)
+	suite.Assert().Nil(result.LastSortKey())
+
+	var keys [][]string
+	for result.Next() {
+		keys = append(keys, result.Row().Sort)
+	}
+	suite.Require().Nil(result.Err())
+	suite.Assert().Equal([][]string{{"UK", "airline_10"}, {"UK", "airline_20"}}, keys)
+	suite.Assert().Equal([]string{"UK", "airline_20"}, result.LastSortKey())
+
+	_, err = cluster.SearchQuery("testindex", search.NewMatchAllQuery(), &SearchOptions{
+		SearchBefore: []string{"1.5"},
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Require().Len(payloads, 2)
+	suite.Assert().Equal([]interface{}{"UK", "airline_5"}, payloads[0]["search_after"])
+	suite.Assert().NotContains(payloads[0], "search_before")
+	suite.Assert().Equal([]interface{}{"1.5"}, payloads[1]["search_before"])
+
+	invalid := []*SearchOptions{
+		{Sort: sort, SearchAfter: []string{"UK", "a"}, SearchBefore: []string{"UK", "b"}},
+		{Sort: sort, SearchAfter: []string{"UK", "a"}, Skip: 10},
+		{Sort: sort, SearchAfter: []string{"UK"}},
+		{SearchBefore: []string{"1", "2"}},
+	}
+	for _, opts := range invalid {
+		_, err := opts.toMap("testindex")
+		suite.Assert().ErrorIs(err, ErrInvalidArgument)
+	}
+}
+
+func (suite *UnitTestSuite) TestSearchQueryRawHitsAndCancellation() {
+	reader := &mockSearchRowReader{
+		Dataset: []jsonSearchRow{
+			{
+				ID:     "a",
+				Fields: json.RawMessage(`{"name":"hotel"}`),
+				Explanation: json.RawMessage(`{"value":1.5,"message":"sum of:","children":[` +
+					`{"value":1,"message":"weight(name:hotel)"},{"value":0.5,"message":"weight(type:hotel)"}]}`),
+			},
+			{ID: "b"},
+			{ID: "c"},
+		},
+		Meta:  []byte(`{"total_hits": 3}`),
+		Suite: suite,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	provider := new(mockSearchProviderCoreProvider)
+	provider.
+		On("SearchQuery", ctx, mock.AnythingOfType("gocbcore.SearchQueryOptions")).
+		Return(reader, nil)
+
+	searchProvider := &searchProviderCore{
+		provider: provider,
+		tracer:   newTracerWrapper(&NoopTracer{}),
+		timeouts: TimeoutsConfig{SearchTimeout: time.Second},
+	}
+
+	result, err := searchProvider.SearchQuery("testindex", search.NewMatchAllQuery(), &SearchOptions{
+		Explain: true,
+		Context: ctx,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Require().True(result.Next())
+	row := result.Row()
+	suite.Assert().JSONEq(`{"name":"hotel"}`, string(row.RawFields()))
+
+	var hit map[string]interface{}
+	suite.Require().Nil(json.Unmarshal(row.RawHit(), &hit))
+	suite.Assert().Equal("a", hit["id"])
+
+	explanation, err := row.ExplanationTree()
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(&SearchExplanation{
+		Value:   1.5,
+		Message: "sum of:",
+		Children: []SearchExplanation{
+			{Value: 1, Message: "weight(name:hotel)"},
+			{Value: 0.5, Message: "weight(type:hotel)"},
+		},
+	}, explanation)
+	suite.Assert().NotNil(row.Explanation)
+
+	suite.Require().True(result.Next())
+	row = result.Row()
+	explanation, err = row.ExplanationTree()
+	suite.Require().Nil(err, err)
+	suite.Assert().Nil(explanation)
+
+	cancel()
+	suite.Assert().False(result.Next())
+	suite.Assert().ErrorIs(result.Err(), ErrRequestCanceled)
+	suite.Assert().ErrorIs(result.Close(), ErrRequestCanceled)
+}
+
+func (suite *UnitTestSuite) TestSearchQueryContextDeadlineWhilstStreaming() {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(50*time.Millisecond))
+	defer cancel()
+
+	reader := &blockingQueryRowReader{
+		rows:    [][]byte{[]byte(`{"id":"a"}`)},
+		closeCh: make(chan struct{}),
+	}
+
+	result := newSearchResult(newSearchProviderCoreRowReader(ctx, reader))
+	suite.Require().True(result.Next())
+
+	<-ctx.Done()
+
+	suite.Assert().False(result.Next())
+	suite.Assert().ErrorIs(result.Err(), ErrTimeout)
+	suite.Assert().NotErrorIs(result.Err(), ErrRequestCanceled)
+	suite.Assert().ErrorIs(result.Close(), ErrTimeout)
+}
@@ -0,0 +1,175 @@
+package gocb
+
+import (
+	"context"
+	"time"
+)
+
+// SecurityManager provides methods for managing the security configuration of the cluster, such as the certificates
+// which it trusts and presents, client certificate authentication and TLS settings.
+// UNCOMMITTED: This API may change in the future.
+type SecurityManager struct {
+	controller *providerController[securityManagementProvider]
+}
+
+// TrustedCA represents a certificate authority which is trusted by the cluster.
+// UNCOMMITTED: This API may change in the future.
+type TrustedCA struct {
+	ID      int
+	Subject string
+	// Type is the origin of the certificate, e.g. "generated" or "uploaded".
+	Type      string
+	NotBefore time.Time
+	NotAfter  time.Time
+	// PEM is the PEM encoded certificate.
+	PEM string
+	// Nodes are the nodes whose certificates have been signed by this certificate authority.
+	Nodes []string
+}
+
+type jsonTrustedCA struct {
+	ID        int      `json:"id"`
+	Subject   string   `json:"subject"`
+	Type      string   `json:"type"`
+	NotBefore string   `json:"notBefore"`
+	NotAfter  string   `json:"notAfter"`
+	PEM       string   `json:"pem"`
+	Nodes     []string `json:"nodes"`
+}
+
+func (ca *TrustedCA) fromData(data jsonTrustedCA) error {
+	notBefore, err := parseCertificateTime(data.NotBefore)
+	if err != nil {
+		return err
+	}
+	notAfter, err := parseCertificateTime(data.NotAfter)
+	if err != nil {
+		return err
+	}
+
+	ca.ID = data.ID
+	ca.Subject = data.Subject
+	ca.Type = data.Type
+	ca.NotBefore = notBefore
+	ca.NotAfter = notAfter
+	ca.PEM = data.PEM
+	ca.Nodes = data.Nodes
+
+	return nil
+}
+
+// NodeCertificateWarning represents a problem that the cluster has detected with a node certificate.
+// UNCOMMITTED: This API may change in the future.
+type NodeCertificateWarning struct {
+	Severity string
+	Message  string
+}
+
+// NodeCertificate represents the certificate presented by a node in the cluster.
+// UNCOMMITTED: This API may change in the future.
+type NodeCertificate struct {
+	Node    string
+	Subject string
+	// Type is the origin of the certificate, e.g. "generated" or "uploaded".
+	Type    string
+	Expires time.Time
+	// PEM is the PEM encoded certificate.
+	PEM      string
+	Warnings []NodeCertificateWarning
+}
+
+type jsonNodeCertificateWarning struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+type jsonNodeCertificate struct {
+	Node     string                       `json:"node"`
+	Subject  string                       `json:"subject"`
+	Type     string                       `json:"type"`
+	Expires  string                       `json:"expires"`
+	PEM      string                       `json:"pem"`
+	Warnings []jsonNodeCertificateWarning `json:"warnings"`
+}
+
+func (cert *NodeCertificate) fromData(data jsonNodeCertificate) error {
+	expires, err := parseCertificateTime(data.Expires)
+	if err != nil {
+		return err
+	}
+
+	cert.Node = data.Node
+	cert.Subject = data.Subject
+	cert.Type = data.Type
+	cert.Expires = expires
+	cert.PEM = data.PEM
+	for _, warning := range data.Warnings {
+		cert.Warnings = append(cert.Warnings, NodeCertificateWarning{
+			Severity: warning.Severity,
+			Message:  warning.Message,
+		})
+	}
+
+	return nil
+}
+
+func parseCertificateTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, wrapError(err, "failed to parse certificate time")
+	}
+
+	return t, nil
+}
+
+// GetTrustedCAsOptions is the set of options available to the SecurityManager GetTrustedCAs operation.
+// UNCOMMITTED: This API may change in the future.
+type GetTrustedCAsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// GetTrustedCAs returns the certificate authorities trusted by the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (sm *SecurityManager) GetTrustedCAs(opts *GetTrustedCAsOptions) ([]TrustedCA, error) {
+	return autoOpControl(sm.controller, "manager_security_get_trusted_cas", func(provider securityManagementProvider) ([]TrustedCA, error) {
+		if opts == nil {
+			opts = &GetTrustedCAsOptions{}
+		}
+
+		return provider.GetTrustedCAs(opts)
+	})
+}
+
+// GetNodeCertificatesOptions is the set of options available to the SecurityManager GetNodeCertificates operation.
+// UNCOMMITTED: This API may change in the future.
+type GetNodeCertificatesOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// GetNodeCertificates returns the certificates presented by each node in the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (sm *SecurityManager) GetNodeCertificates(opts *GetNodeCertificatesOptions) ([]NodeCertificate, error) {
+	return autoOpControl(sm.controller, "manager_security_get_node_certificates", func(provider securityManagementProvider) ([]NodeCertificate, error) {
+		if opts == nil {
+			opts = &GetNodeCertificatesOptions{}
+		}
+
+		return provider.GetNodeCertificates(opts)
+	})
+}
@@ -0,0 +1,214 @@
+package gocb
+
+import (
+	"context"
+	"time"
+)
+
+// ClientCertAuthState specifies whether clients can authenticate using certificates.
+// UNCOMMITTED: This API may change in the future.
+type ClientCertAuthState string
+
+const (
+	// ClientCertAuthStateDisable specifies that client certificates are not used for authentication.
+	ClientCertAuthStateDisable ClientCertAuthState = "disable"
+
+	// ClientCertAuthStateEnable specifies that clients can authenticate using either certificates or credentials.
+	ClientCertAuthStateEnable ClientCertAuthState = "enable"
+
+	// ClientCertAuthStateMandatory specifies that clients must authenticate using certificates.
+	ClientCertAuthStateMandatory ClientCertAuthState = "mandatory"
+)
+
+// ClientCertAuthPrefix specifies how a username is extracted from a client certificate.
+// UNCOMMITTED: This API may change in the future.
+type ClientCertAuthPrefix struct {
+	// Path is the certificate field containing the username, such as subject.cn or san.email.
+	Path string
+	// Prefix is removed from the start of the field.
+	Prefix string
+	// Delimiter ends the username within the field, such as @ for an email address.
+	Delimiter string
+}
+
+// ClientCertAuthSettings represents the client certificate authentication configuration of the cluster.
+// UNCOMMITTED: This API may change in the future.
+type ClientCertAuthSettings struct {
+	State    ClientCertAuthState
+	Prefixes []ClientCertAuthPrefix
+}
+
+// TLSMinVersion is the minimum TLS version accepted by the cluster.
+// UNCOMMITTED: This API may change in the future.
+type TLSMinVersion string
+
+const (
+	// TLSMinVersion12 specifies that TLS 1.2 or later is required.
+	TLSMinVersion12 TLSMinVersion = "tlsv1.2"
+
+	// TLSMinVersion13 specifies that TLS 1.3 is required.
+	TLSMinVersion13 TLSMinVersion = "tlsv1.3"
+)
+
+// TLSSettings represents the TLS configuration of the cluster.
+// UNCOMMITTED: This API may change in the future.
+type TLSSettings struct {
+	// MinVersion is the minimum TLS version accepted, when updating a zero value leaves the existing server setting
+	// unchanged.
+	MinVersion TLSMinVersion
+	// CipherSuites are the names of the cipher suites accepted. When updating a nil value leaves the existing server
+	// setting unchanged, whilst an empty, non-nil, value resets the server to its default cipher suites.
+	CipherSuites []string
+	// HonorCipherOrder is whether the server's order of preference of cipher suites is used, when updating a nil
+	// value leaves the existing server setting unchanged.
+	HonorCipherOrder *bool
+}
+
+// GetClientCertAuthSettingsOptions is the set of options available to the SecurityManager GetClientCertAuthSettings
+// operation.
+// UNCOMMITTED: This API may change in the future.
+type GetClientCertAuthSettingsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// GetClientCertAuthSettings returns the client certificate authentication configuration of the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (sm *SecurityManager) GetClientCertAuthSettings(opts *GetClientCertAuthSettingsOptions) (*ClientCertAuthSettings, error) {
+	return autoOpControl(sm.controller, "manager_security_get_client_cert_auth", func(provider securityManagementProvider) (*ClientCertAuthSettings, error) {
+		if opts == nil {
+			opts = &GetClientCertAuthSettingsOptions{}
+		}
+
+		return provider.GetClientCertAuthSettings(opts)
+	})
+}
+
+// UpdateClientCertAuthSettingsOptions is the set of options available to the SecurityManager
+// UpdateClientCertAuthSettings operation.
+// UNCOMMITTED: This API may change in the future.
+type UpdateClientCertAuthSettingsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// UpdateClientCertAuthSettings updates the client certificate authentication configuration of the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (sm *SecurityManager) UpdateClientCertAuthSettings(settings ClientCertAuthSettings, opts *UpdateClientCertAuthSettingsOptions) error {
+	return autoOpControlErrorOnly(sm.controller, "manager_security_update_client_cert_auth", func(provider securityManagementProvider) error {
+		switch settings.State {
+		case ClientCertAuthStateDisable, ClientCertAuthStateEnable, ClientCertAuthStateMandatory:
+		default:
+			return makeInvalidArgumentsError("state must be disable, enable or mandatory")
+		}
+		if settings.State != ClientCertAuthStateDisable && len(settings.Prefixes) == 0 {
+			return makeInvalidArgumentsError("prefixes cannot be empty when client certificate authentication is enabled")
+		}
+		for _, prefix := range settings.Prefixes {
+			if prefix.Path == "" {
+				return makeInvalidArgumentsError("prefix path cannot be empty")
+			}
+		}
+
+		if opts == nil {
+			opts = &UpdateClientCertAuthSettingsOptions{}
+		}
+
+		return provider.UpdateClientCertAuthSettings(settings, opts)
+	})
+}
+
+// GetTLSSettingsOptions is the set of options available to the SecurityManager GetTLSSettings operation.
+// UNCOMMITTED: This API may change in the future.
+type GetTLSSettingsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// GetTLSSettings returns the TLS configuration of the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (sm *SecurityManager) GetTLSSettings(opts *GetTLSSettingsOptions) (*TLSSettings, error) {
+	return autoOpControl(sm.controller, "manager_security_get_tls_settings", func(provider securityManagementProvider) (*TLSSettings, error) {
+		if opts == nil {
+			opts = &GetTLSSettingsOptions{}
+		}
+
+		return provider.GetTLSSettings(opts)
+	})
+}
+
+// UpdateTLSSettingsOptions is the set of options available to the SecurityManager UpdateTLSSettings operation.
+// UNCOMMITTED: This API may change in the future.
+type UpdateTLSSettingsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// UpdateTLSSettings updates the TLS configuration of the cluster, only the settings which are set are changed.
+// UNCOMMITTED: This API may change in the future.
+func (sm *SecurityManager) UpdateTLSSettings(settings TLSSettings, opts *UpdateTLSSettingsOptions) error {
+	return autoOpControlErrorOnly(sm.controller, "manager_security_update_tls_settings", func(provider securityManagementProvider) error {
+		switch settings.MinVersion {
+		case "", TLSMinVersion12, TLSMinVersion13:
+		default:
+			return makeInvalidArgumentsError("minimum tls version must be tlsv1.2 or tlsv1.3")
+		}
+
+		if opts == nil {
+			opts = &UpdateTLSSettingsOptions{}
+		}
+
+		return provider.UpdateTLSSettings(settings, opts)
+	})
+}
+
+// ReloadNodeCertificatesOptions is the set of options available to the SecurityManager ReloadNodeCertificates
+// operation.
+// UNCOMMITTED: This API may change in the future.
+type ReloadNodeCertificatesOptions struct {
+	// Nodes are the hostnames of the nodes to reload certificates on, as returned by NodeManager.ListNodes. If empty
+	// then certificates are reloaded on every node.
+	Nodes []string
+
+	// Timeout applies to each of the requests made to the nodes.
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// ReloadNodeCertificates causes nodes to load the certificate and private key which have been placed in their inbox
+// directory, such as after the certificates have been rotated.
+// UNCOMMITTED: This API may change in the future.
+func (sm *SecurityManager) ReloadNodeCertificates(opts *ReloadNodeCertificatesOptions) error {
+	return autoOpControlErrorOnly(sm.controller, "manager_security_reload_node_certificates", func(provider securityManagementProvider) error {
+		if opts == nil {
+			opts = &ReloadNodeCertificatesOptions{}
+		}
+
+		return provider.ReloadNodeCertificates(opts)
+	})
+}
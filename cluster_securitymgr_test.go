@@ -0,0 +1,265 @@
+package gocb
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) securityManager(runFn func(args mock.Arguments), args ...interface{}) *SecurityManager {
+	mockProvider := new(mockMgmtProvider)
+	call := mockProvider.
+		On("executeMgmtRequest", nil, mock.AnythingOfType("mgmtRequest")).
+		Return(args...)
+
+	if runFn != nil {
+		call.Run(runFn)
+	}
+
+	provider := &securityManagementProviderCore{
+		provider: mockProvider,
+		tracer:   newTracerWrapper(&NoopTracer{}),
+	}
+
+	return &SecurityManager{
+		controller: &providerController[securityManagementProvider]{
+			get: func() (securityManagementProvider, error) {
+				return provider, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+}
+
+func (suite *UnitTestSuite) TestSecurityManagerGetTrustedCAs() {
+	body := `[{"id":0,"subject":"CN=Couchbase Server 7b7e4d6f","notBefore":"2013-01-01T00:00:00.000Z",` +
+		`"notAfter":"2049-12-31T23:59:59.000Z","type":"generated","pem":"-----BEGIN CERTIFICATE-----",` +
+		`"nodes":["10.0.0.1","10.0.0.2"]}]`
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+	}
+
+	mgr := suite.securityManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/pools/default/trustedCAs", req.Path)
+		suite.Assert().True(req.IsIdempotent)
+		suite.Assert().Equal(1*time.Second, req.Timeout)
+		suite.Assert().Equal("GET", req.Method)
+	}, resp, nil)
+
+	cas, err := mgr.GetTrustedCAs(&GetTrustedCAsOptions{
+		Timeout: 1 * time.Second,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal([]TrustedCA{{
+		ID:        0,
+		Subject:   "CN=Couchbase Server 7b7e4d6f",
+		Type:      "generated",
+		NotBefore: time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:  time.Date(2049, 12, 31, 23, 59, 59, 0, time.UTC),
+		PEM:       "-----BEGIN CERTIFICATE-----",
+		Nodes:     []string{"10.0.0.1", "10.0.0.2"},
+	}}, cas)
+}
+
+func (suite *UnitTestSuite) TestSecurityManagerGetNodeCertificates() {
+	body := `[{"node":"10.0.0.1:8091","subject":"CN=Couchbase Server Node (10.0.0.1)",` +
+		`"expires":"2025-06-01T12:00:00.000Z","type":"generated","pem":"-----BEGIN CERTIFICATE-----",` +
+		`"warnings":[{"severity":"warning","message":"This certificate will expire soon"}]}]`
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+	}
+
+	mgr := suite.securityManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/pools/default/certificates", req.Path)
+		suite.Assert().True(req.IsIdempotent)
+		suite.Assert().Equal("GET", req.Method)
+	}, resp, nil)
+
+	certs, err := mgr.GetNodeCertificates(nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal([]NodeCertificate{{
+		Node:     "10.0.0.1:8091",
+		Subject:  "CN=Couchbase Server Node (10.0.0.1)",
+		Type:     "generated",
+		Expires:  time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		PEM:      "-----BEGIN CERTIFICATE-----",
+		Warnings: []NodeCertificateWarning{{Severity: "warning", Message: "This certificate will expire soon"}},
+	}}, certs)
+}
+
+func (suite *UnitTestSuite) TestSecurityManagerGetTrustedCAsBadStatus() {
+	resp := &mgmtResponse{
+		StatusCode: 403,
+		Body:       io.NopCloser(bytes.NewReader([]byte(`{"message":"Forbidden"}`))),
+	}
+
+	mgr := suite.securityManager(nil, resp, nil)
+
+	_, err := mgr.GetTrustedCAs(nil)
+	suite.Require().NotNil(err)
+
+	var httpErr *HTTPError
+	suite.Assert().True(errors.As(err, &httpErr))
+}
+
+func (suite *UnitTestSuite) TestSecurityManagerUpdateClientCertAuthSettings() {
+	resp := &mgmtResponse{
+		StatusCode: 202,
+		Body:       io.NopCloser(bytes.NewReader([]byte{})),
+	}
+
+	mgr := suite.securityManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/settings/clientCertAuth", req.Path)
+		suite.Assert().Equal("POST", req.Method)
+		suite.Assert().Equal("application/json", req.ContentType)
+		suite.Assert().JSONEq(`{"state":"mandatory","prefixes":[{"path":"san.email","prefix":"","delimiter":"@"}]}`,
+			string(req.Body))
+	}, resp, nil)
+
+	err := mgr.UpdateClientCertAuthSettings(ClientCertAuthSettings{
+		State:    ClientCertAuthStateMandatory,
+		Prefixes: []ClientCertAuthPrefix{{Path: "san.email", Delimiter: "@"}},
+	}, nil)
+	suite.Require().Nil(err, err)
+
+	err = mgr.UpdateClientCertAuthSettings(ClientCertAuthSettings{State: ClientCertAuthStateEnable}, nil)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
+
+func (suite *UnitTestSuite) TestSecurityManagerTLSSettings() {
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body: io.NopCloser(bytes.NewReader([]byte(`{"tlsMinVersion":"tlsv1.2","honorCipherOrder":true,` +
+			`"cipherSuites":["TLS_AES_128_GCM_SHA256"],"disableUIOverHttp":false}`))),
+	}
+
+	mgr := suite.securityManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/settings/security", req.Path)
+		suite.Assert().Equal("GET", req.Method)
+	}, resp, nil)
+
+	settings, err := mgr.GetTLSSettings(nil)
+	suite.Require().Nil(err, err)
+	honorCipherOrder := true
+	suite.Assert().Equal(&TLSSettings{
+		MinVersion:       TLSMinVersion12,
+		CipherSuites:     []string{"TLS_AES_128_GCM_SHA256"},
+		HonorCipherOrder: &honorCipherOrder,
+	}, settings)
+
+	resp = &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte{})),
+	}
+
+	var body string
+	mgr = suite.securityManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/settings/security", req.Path)
+		suite.Assert().Equal("POST", req.Method)
+		body = string(req.Body)
+	}, resp, nil)
+
+	// Only the settings which are set are sent, so that the others are left unchanged.
+	err = mgr.UpdateTLSSettings(TLSSettings{MinVersion: TLSMinVersion13}, nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("tlsMinVersion=tlsv1.3", body)
+
+	honorCipherOrder = false
+	err = mgr.UpdateTLSSettings(TLSSettings{CipherSuites: []string{}, HonorCipherOrder: &honorCipherOrder}, nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("cipherSuites=%5B%5D&honorCipherOrder=false", body)
+
+	err = mgr.UpdateTLSSettings(TLSSettings{MinVersion: "tlsv1.1"}, nil)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
+
+func (suite *UnitTestSuite) securityManagerReloadingCertificates(mgmtEndpoint, nodes string, endpoints *[]string) *SecurityManager {
+	mockProvider := new(mockMgmtProvider)
+	mockProvider.
+		On("executeMgmtRequest", nil, mock.MatchedBy(func(req mgmtRequest) bool {
+			return req.Path == "/pools/default"
+		})).
+		Return(&mgmtResponse{
+			Endpoint:   mgmtEndpoint,
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"nodes":[` + nodes + `]}`))),
+		}, nil)
+
+	mockProvider.
+		On("executeMgmtRequest", nil, mock.MatchedBy(func(req mgmtRequest) bool {
+			return req.Path == "/node/controller/reloadCertificate"
+		})).
+		Run(func(args mock.Arguments) {
+			req := args.Get(1).(mgmtRequest)
+			suite.Assert().Equal("POST", req.Method)
+			*endpoints = append(*endpoints, req.Endpoint)
+		}).
+		Return(&mgmtResponse{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		}, nil)
+
+	provider := &securityManagementProviderCore{
+		provider: mockProvider,
+		tracer:   newTracerWrapper(&NoopTracer{}),
+	}
+	return &SecurityManager{
+		controller: &providerController[securityManagementProvider]{
+			get: func() (securityManagementProvider, error) {
+				return provider, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+}
+
+func (suite *UnitTestSuite) TestSecurityManagerReloadNodeCertificates() {
+	nodes := `{"hostname":"10.0.0.1:8091","otpNode":"ns_1@10.0.0.1","ports":{"httpsMgmt":18091},` +
+		`"alternateAddresses":{"external":{"hostname":"node1.example.com","ports":{"mgmt":9091,"mgmtSSL":19091}}}},` +
+		`{"hostname":"10.0.0.2:8091","otpNode":"ns_1@10.0.0.2","ports":{"httpsMgmt":18091},` +
+		`"alternateAddresses":{"external":{"hostname":"node2.example.com","ports":{"mgmt":9091,"mgmtSSL":19091}}}}`
+
+	var endpoints []string
+	mgr := suite.securityManagerReloadingCertificates("https://10.0.0.1:18091", nodes, &endpoints)
+	err := mgr.ReloadNodeCertificates(nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal([]string{"https://10.0.0.1:18091", "https://10.0.0.2:18091"}, endpoints)
+
+	endpoints = nil
+	mgr = suite.securityManagerReloadingCertificates("http://10.0.0.1:8091", nodes, &endpoints)
+	err = mgr.ReloadNodeCertificates(&ReloadNodeCertificatesOptions{Nodes: []string{"10.0.0.2"}})
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal([]string{"http://10.0.0.2:8091"}, endpoints)
+
+	// When connected over alternate addresses the requests are sent to the alternate addresses of the nodes.
+	endpoints = nil
+	mgr = suite.securityManagerReloadingCertificates("https://node1.example.com:19091", nodes, &endpoints)
+	err = mgr.ReloadNodeCertificates(nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal([]string{"https://node1.example.com:19091", "https://node2.example.com:19091"}, endpoints)
+
+	// A node without a TLS management port is an error, rather than a request to port 0.
+	endpoints = nil
+	mgr = suite.securityManagerReloadingCertificates("https://10.0.0.1:18091",
+		`{"hostname":"10.0.0.1:8091","otpNode":"ns_1@10.0.0.1","ports":{}}`, &endpoints)
+	err = mgr.ReloadNodeCertificates(nil)
+	suite.Assert().True(errors.Is(err, ErrServiceNotAvailable), err)
+	suite.Assert().Empty(endpoints)
+}
@@ -0,0 +1,321 @@
+package gocb
+
+import (
+	"context"
+	"time"
+)
+
+// ClusterSettingsManager provides methods for reading and updating the cluster wide settings of the cluster.
+// UNCOMMITTED: This API may change in the future.
+type ClusterSettingsManager struct {
+	controller *providerController[clusterSettingsProvider]
+}
+
+// AutoFailoverSettings represents the auto-failover configuration of the cluster.
+// When updating, the current settings are read from the cluster and only the fields which are set are changed, nil
+// and zero valued fields leave the existing server setting unchanged. The booleans are pointers so that false can be
+// told apart from not being set, they are always set by GetAutoFailoverSettings.
+// UNCOMMITTED: This API may change in the future.
+type AutoFailoverSettings struct {
+	Enabled *bool
+	// Timeout is how long a node must be unresponsive before it is failed over.
+	Timeout time.Duration
+	// MaxCount is the number of auto-failover events which may occur before the count must be reset.
+	MaxCount int
+	// Count is the number of auto-failover events which have occurred. It is ignored when updating the settings.
+	Count int
+	// FailoverOnDataDiskIssues enables failover of nodes which have been unable to read or write to the data disk
+	// for DataDiskIssuesTimePeriod.
+	FailoverOnDataDiskIssues *bool
+	DataDiskIssuesTimePeriod time.Duration
+	// CanAbortRebalance allows auto-failover to abort an ongoing rebalance in order to fail over a node.
+	CanAbortRebalance *bool
+}
+
+// EmailAlertSettings represents the email alert configuration of the cluster.
+// When updating, the current settings are read from the cluster and only the fields which are set are changed, nil
+// and zero valued fields leave the existing server setting unchanged. A non-nil but empty Recipients or Alerts
+// clears them. The booleans are pointers so that false can be told apart from not being set, they are always set by
+// GetEmailAlertSettings.
+// UNCOMMITTED: This API may change in the future.
+type EmailAlertSettings struct {
+	Enabled    *bool
+	Recipients []string
+	Sender     string
+	EmailHost  string
+	EmailPort  int
+	// EmailEncrypt specifies whether the connection to the email server uses TLS.
+	EmailEncrypt  *bool
+	EmailUsername string
+	// EmailPassword is the password for EmailUsername. It is never returned by the server, so is empty when fetched,
+	// and the existing password is kept when it is not set.
+	EmailPassword string
+	// Alerts are the names of the alerts which send emails, such as auto_failover_node.
+	Alerts []string
+}
+
+// CompactionSettings represents the auto-compaction configuration used by buckets which do not override it.
+// Zero valued fragmentation thresholds are disabled, whilst a zero valued PurgeInterval leaves the existing server
+// setting unchanged.
+// UNCOMMITTED: This API may change in the future.
+type CompactionSettings struct {
+	// ParallelDBAndViewCompaction specifies whether database and view compaction can run at the same time.
+	ParallelDBAndViewCompaction bool
+	// DatabaseFragmentationPercentage is the fragmentation percentage at which database compaction is triggered.
+	DatabaseFragmentationPercentage int
+	// DatabaseFragmentationSizeMB is the fragmentation size at which database compaction is triggered.
+	DatabaseFragmentationSizeMB uint64
+	// ViewFragmentationPercentage is the fragmentation percentage at which view compaction is triggered.
+	ViewFragmentationPercentage int
+	// ViewFragmentationSizeMB is the fragmentation size at which view compaction is triggered.
+	ViewFragmentationSizeMB uint64
+	// PurgeInterval is how long tombstones are kept before being purged by compaction, the server rounds this to a
+	// fraction of a day.
+	PurgeInterval time.Duration
+}
+
+// MemoryQuotas represents the memory quotas, in megabytes, given to each service on each node of the cluster.
+// When updating, zero valued quotas leave the existing server setting unchanged.
+// UNCOMMITTED: This API may change in the future.
+type MemoryQuotas struct {
+	DataMB      uint64
+	IndexMB     uint64
+	SearchMB    uint64
+	AnalyticsMB uint64
+	EventingMB  uint64
+}
+
+// GetAutoFailoverSettingsOptions is the set of options available to the cluster settings manager
+// GetAutoFailoverSettings operation.
+// UNCOMMITTED: This API may change in the future.
+type GetAutoFailoverSettingsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// GetAutoFailoverSettings returns the auto-failover configuration of the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (sm *ClusterSettingsManager) GetAutoFailoverSettings(opts *GetAutoFailoverSettingsOptions) (*AutoFailoverSettings, error) {
+	return autoOpControl(sm.controller, "manager_settings_get_auto_failover", func(provider clusterSettingsProvider) (*AutoFailoverSettings, error) {
+		if opts == nil {
+			opts = &GetAutoFailoverSettingsOptions{}
+		}
+
+		return provider.GetAutoFailoverSettings(opts)
+	})
+}
+
+// UpdateAutoFailoverSettingsOptions is the set of options available to the cluster settings manager
+// UpdateAutoFailoverSettings operation.
+// UNCOMMITTED: This API may change in the future.
+type UpdateAutoFailoverSettingsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// UpdateAutoFailoverSettings updates the auto-failover configuration of the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (sm *ClusterSettingsManager) UpdateAutoFailoverSettings(settings AutoFailoverSettings, opts *UpdateAutoFailoverSettingsOptions) error {
+	return autoOpControlErrorOnly(sm.controller, "manager_settings_update_auto_failover", func(provider clusterSettingsProvider) error {
+		if settings.Timeout < 0 {
+			return makeInvalidArgumentsError("timeout cannot be negative")
+		}
+		if settings.MaxCount < 0 {
+			return makeInvalidArgumentsError("max count cannot be negative")
+		}
+		if settings.DataDiskIssuesTimePeriod < 0 {
+			return makeInvalidArgumentsError("data disk issues time period cannot be negative")
+		}
+
+		if opts == nil {
+			opts = &UpdateAutoFailoverSettingsOptions{}
+		}
+
+		return provider.UpdateAutoFailoverSettings(settings, opts)
+	})
+}
+
+// GetEmailAlertSettingsOptions is the set of options available to the cluster settings manager
+// GetEmailAlertSettings operation.
+// UNCOMMITTED: This API may change in the future.
+type GetEmailAlertSettingsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// GetEmailAlertSettings returns the email alert configuration of the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (sm *ClusterSettingsManager) GetEmailAlertSettings(opts *GetEmailAlertSettingsOptions) (*EmailAlertSettings, error) {
+	return autoOpControl(sm.controller, "manager_settings_get_email_alerts", func(provider clusterSettingsProvider) (*EmailAlertSettings, error) {
+		if opts == nil {
+			opts = &GetEmailAlertSettingsOptions{}
+		}
+
+		return provider.GetEmailAlertSettings(opts)
+	})
+}
+
+// UpdateEmailAlertSettingsOptions is the set of options available to the cluster settings manager
+// UpdateEmailAlertSettings operation.
+// UNCOMMITTED: This API may change in the future.
+type UpdateEmailAlertSettingsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// UpdateEmailAlertSettings updates the email alert configuration of the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (sm *ClusterSettingsManager) UpdateEmailAlertSettings(settings EmailAlertSettings, opts *UpdateEmailAlertSettingsOptions) error {
+	return autoOpControlErrorOnly(sm.controller, "manager_settings_update_email_alerts", func(provider clusterSettingsProvider) error {
+		if settings.EmailPort < 0 {
+			return makeInvalidArgumentsError("email port cannot be negative")
+		}
+
+		if opts == nil {
+			opts = &UpdateEmailAlertSettingsOptions{}
+		}
+
+		return provider.UpdateEmailAlertSettings(settings, opts)
+	})
+}
+
+// GetCompactionSettingsOptions is the set of options available to the cluster settings manager
+// GetCompactionSettings operation.
+// UNCOMMITTED: This API may change in the future.
+type GetCompactionSettingsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// GetCompactionSettings returns the global auto-compaction configuration of the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (sm *ClusterSettingsManager) GetCompactionSettings(opts *GetCompactionSettingsOptions) (*CompactionSettings, error) {
+	return autoOpControl(sm.controller, "manager_settings_get_compaction", func(provider clusterSettingsProvider) (*CompactionSettings, error) {
+		if opts == nil {
+			opts = &GetCompactionSettingsOptions{}
+		}
+
+		return provider.GetCompactionSettings(opts)
+	})
+}
+
+// UpdateCompactionSettingsOptions is the set of options available to the cluster settings manager
+// UpdateCompactionSettings operation.
+// UNCOMMITTED: This API may change in the future.
+type UpdateCompactionSettingsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// UpdateCompactionSettings updates the global auto-compaction configuration of the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (sm *ClusterSettingsManager) UpdateCompactionSettings(settings CompactionSettings, opts *UpdateCompactionSettingsOptions) error {
+	return autoOpControlErrorOnly(sm.controller, "manager_settings_update_compaction", func(provider clusterSettingsProvider) error {
+		if settings.DatabaseFragmentationPercentage < 0 || settings.DatabaseFragmentationPercentage > 100 {
+			return makeInvalidArgumentsError("database fragmentation percentage must be between 0 and 100")
+		}
+		if settings.ViewFragmentationPercentage < 0 || settings.ViewFragmentationPercentage > 100 {
+			return makeInvalidArgumentsError("view fragmentation percentage must be between 0 and 100")
+		}
+		if settings.PurgeInterval < 0 {
+			return makeInvalidArgumentsError("purge interval cannot be negative")
+		}
+
+		if opts == nil {
+			opts = &UpdateCompactionSettingsOptions{}
+		}
+
+		return provider.UpdateCompactionSettings(settings, opts)
+	})
+}
+
+// GetMemoryQuotasOptions is the set of options available to the cluster settings manager GetMemoryQuotas operation.
+// UNCOMMITTED: This API may change in the future.
+type GetMemoryQuotasOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// GetMemoryQuotas returns the memory quotas of the services in the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (sm *ClusterSettingsManager) GetMemoryQuotas(opts *GetMemoryQuotasOptions) (*MemoryQuotas, error) {
+	return autoOpControl(sm.controller, "manager_settings_get_memory_quotas", func(provider clusterSettingsProvider) (*MemoryQuotas, error) {
+		if opts == nil {
+			opts = &GetMemoryQuotasOptions{}
+		}
+
+		return provider.GetMemoryQuotas(opts)
+	})
+}
+
+// UpdateMemoryQuotasOptions is the set of options available to the cluster settings manager UpdateMemoryQuotas
+// operation.
+// UNCOMMITTED: This API may change in the future.
+type UpdateMemoryQuotasOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// UpdateMemoryQuotas updates the memory quotas of the services in the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (sm *ClusterSettingsManager) UpdateMemoryQuotas(quotas MemoryQuotas, opts *UpdateMemoryQuotasOptions) error {
+	return autoOpControlErrorOnly(sm.controller, "manager_settings_update_memory_quotas", func(provider clusterSettingsProvider) error {
+		if quotas == (MemoryQuotas{}) {
+			return makeInvalidArgumentsError("at least one memory quota must be specified")
+		}
+
+		if opts == nil {
+			opts = &UpdateMemoryQuotasOptions{}
+		}
+
+		return provider.UpdateMemoryQuotas(quotas, opts)
+	})
+}
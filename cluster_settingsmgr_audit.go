@@ -0,0 +1,131 @@
+package gocb
+
+import (
+	"context"
+	"time"
+)
+
+// AuditIgnoredUser is a user whose actions are not audited.
+// UNCOMMITTED: This API may change in the future.
+type AuditIgnoredUser struct {
+	Username string
+	Domain   AuthDomain
+}
+
+// AuditSettings represents the audit configuration of the cluster.
+// When updating, the event and user lists are always applied, whilst zero valued strings, numbers and durations leave
+// the existing server setting unchanged.
+// UNCOMMITTED: This API may change in the future.
+type AuditSettings struct {
+	Enabled bool
+	// LogPath is the directory on each node that audit logs are written to.
+	LogPath string
+	// RotateInterval is how long the audit log is written to before it is rotated.
+	RotateInterval time.Duration
+	// RotateSizeBytes is the size that the audit log can grow to before it is rotated.
+	RotateSizeBytes uint64
+	// DisabledEvents are the IDs of the events which are not audited, see GetAuditEventDescriptors.
+	DisabledEvents []uint32
+	IgnoredUsers   []AuditIgnoredUser
+}
+
+// AuditEventDescriptor describes an event which can be audited.
+// UNCOMMITTED: This API may change in the future.
+type AuditEventDescriptor struct {
+	ID          uint32
+	Name        string
+	Module      string
+	Description string
+}
+
+// GetAuditSettingsOptions is the set of options available to the cluster settings manager GetAuditSettings operation.
+// UNCOMMITTED: This API may change in the future.
+type GetAuditSettingsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// GetAuditSettings returns the audit configuration of the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (sm *ClusterSettingsManager) GetAuditSettings(opts *GetAuditSettingsOptions) (*AuditSettings, error) {
+	return autoOpControl(sm.controller, "manager_settings_get_audit", func(provider clusterSettingsProvider) (*AuditSettings, error) {
+		if opts == nil {
+			opts = &GetAuditSettingsOptions{}
+		}
+
+		return provider.GetAuditSettings(opts)
+	})
+}
+
+// UpdateAuditSettingsOptions is the set of options available to the cluster settings manager UpdateAuditSettings
+// operation.
+// UNCOMMITTED: This API may change in the future.
+type UpdateAuditSettingsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// UpdateAuditSettings updates the audit configuration of the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (sm *ClusterSettingsManager) UpdateAuditSettings(settings AuditSettings, opts *UpdateAuditSettingsOptions) error {
+	return autoOpControlErrorOnly(sm.controller, "manager_settings_update_audit", func(provider clusterSettingsProvider) error {
+		if settings.RotateInterval < 0 {
+			return makeInvalidArgumentsError("rotate interval cannot be negative")
+		}
+		if settings.RotateInterval%time.Second != 0 {
+			return makeInvalidArgumentsError("rotate interval must be a whole number of seconds")
+		}
+		for _, user := range settings.IgnoredUsers {
+			if user.Username == "" {
+				return makeInvalidArgumentsError("ignored user username cannot be empty")
+			}
+			if user.Domain != LocalDomain && user.Domain != ExternalDomain {
+				return makeInvalidArgumentsError("ignored user domain must be local or external")
+			}
+		}
+
+		if opts == nil {
+			opts = &UpdateAuditSettingsOptions{}
+		}
+
+		return provider.UpdateAuditSettings(settings, opts)
+	})
+}
+
+// GetAuditEventDescriptorsOptions is the set of options available to the cluster settings manager
+// GetAuditEventDescriptors operation.
+// UNCOMMITTED: This API may change in the future.
+type GetAuditEventDescriptorsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// GetAuditEventDescriptors returns the events which can be disabled using AuditSettings.DisabledEvents.
+// UNCOMMITTED: This API may change in the future.
+func (sm *ClusterSettingsManager) GetAuditEventDescriptors(opts *GetAuditEventDescriptorsOptions) ([]AuditEventDescriptor, error) {
+	return autoOpControl(sm.controller, "manager_settings_get_audit_descriptors", func(provider clusterSettingsProvider) ([]AuditEventDescriptor, error) {
+		if opts == nil {
+			opts = &GetAuditEventDescriptorsOptions{}
+		}
+
+		return provider.GetAuditEventDescriptors(opts)
+	})
+}
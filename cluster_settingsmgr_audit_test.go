@@ -0,0 +1,80 @@
+package gocb
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) TestClusterSettingsManagerGetAuditSettings() {
+	body := `{"auditdEnabled":true,"disabled":[8243,8255],` +
+		`"disabledUsers":[{"name":"backup","domain":"local"},{"name":"svc-ldap","domain":"external"}],` +
+		`"logPath":"/opt/couchbase/var/lib/couchbase/logs","rotateInterval":86400,"rotateSize":20971520}`
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+	}
+
+	mgr := suite.clusterSettingsManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/settings/audit", req.Path)
+		suite.Assert().Equal("GET", req.Method)
+		suite.Assert().True(req.IsIdempotent)
+	}, resp, nil)
+
+	settings, err := mgr.GetAuditSettings(nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(&AuditSettings{
+		Enabled:         true,
+		LogPath:         "/opt/couchbase/var/lib/couchbase/logs",
+		RotateInterval:  24 * time.Hour,
+		RotateSizeBytes: 20971520,
+		DisabledEvents:  []uint32{8243, 8255},
+		IgnoredUsers: []AuditIgnoredUser{
+			{Username: "backup", Domain: LocalDomain},
+			{Username: "svc-ldap", Domain: ExternalDomain},
+		},
+	}, settings)
+}
+
+func (suite *UnitTestSuite) TestClusterSettingsManagerUpdateAuditSettings() {
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte{})),
+	}
+
+	mgr := suite.clusterSettingsManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/settings/audit", req.Path)
+		suite.Assert().Equal("POST", req.Method)
+
+		form, err := url.ParseQuery(string(req.Body))
+		suite.Require().Nil(err, err)
+		suite.Assert().Equal(url.Values{
+			"auditdEnabled":  []string{"true"},
+			"rotateInterval": []string{"3600"},
+			"disabled":       []string{"8243,8255"},
+			"disabledUsers":  []string{"backup/local"},
+		}, form)
+	}, resp, nil)
+
+	err := mgr.UpdateAuditSettings(AuditSettings{
+		Enabled:        true,
+		RotateInterval: time.Hour,
+		DisabledEvents: []uint32{8243, 8255},
+		IgnoredUsers:   []AuditIgnoredUser{{Username: "backup", Domain: LocalDomain}},
+	}, nil)
+	suite.Require().Nil(err, err)
+
+	err = mgr.UpdateAuditSettings(AuditSettings{
+		IgnoredUsers: []AuditIgnoredUser{{Username: "backup"}},
+	}, nil)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
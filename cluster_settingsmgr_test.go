@@ -0,0 +1,215 @@
+package gocb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) clusterSettingsManager(runFn func(args mock.Arguments), args ...interface{}) *ClusterSettingsManager {
+	mockProvider := new(mockMgmtProvider)
+	call := mockProvider.
+		On("executeMgmtRequest", nil, mock.AnythingOfType("mgmtRequest")).
+		Return(args...)
+
+	if runFn != nil {
+		call.Run(runFn)
+	}
+
+	provider := &clusterSettingsProviderCore{
+		provider: mockProvider,
+		tracer:   newTracerWrapper(&NoopTracer{}),
+	}
+
+	return &ClusterSettingsManager{
+		controller: &providerController[clusterSettingsProvider]{
+			get: func() (clusterSettingsProvider, error) {
+				return provider, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+}
+
+func (suite *UnitTestSuite) TestClusterSettingsManagerGetAutoFailoverSettings() {
+	body := `{"enabled":true,"timeout":120,"count":1,"maxCount":2,` +
+		`"failoverOnDataDiskIssues":{"enabled":true,"timePeriod":60},"canAbortRebalance":true}`
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+	}
+
+	mgr := suite.clusterSettingsManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/settings/autoFailover", req.Path)
+		suite.Assert().Equal("GET", req.Method)
+		suite.Assert().True(req.IsIdempotent)
+	}, resp, nil)
+
+	settings, err := mgr.GetAutoFailoverSettings(nil)
+	suite.Require().Nil(err, err)
+
+	enabled := true
+	suite.Assert().Equal(&AutoFailoverSettings{
+		Enabled:                  &enabled,
+		Timeout:                  120 * time.Second,
+		MaxCount:                 2,
+		Count:                    1,
+		FailoverOnDataDiskIssues: &enabled,
+		DataDiskIssuesTimePeriod: 60 * time.Second,
+		CanAbortRebalance:        &enabled,
+	}, settings)
+}
+
+// clusterSettingsUpdateManager returns a manager whose GET requests return current and whose POST requests are
+// checked by postFn.
+func (suite *UnitTestSuite) clusterSettingsUpdateManager(path, current string, postFn func(form url.Values)) *ClusterSettingsManager {
+	return suite.clusterSettingsManager(nil, func(ctx context.Context, req mgmtRequest) *mgmtResponse {
+		suite.Assert().Equal(path, req.Path)
+		suite.Assert().False(req.Deadline.IsZero())
+
+		if req.Method == "GET" {
+			return &mgmtResponse{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewReader([]byte(current))),
+			}
+		}
+
+		suite.Assert().Equal("POST", req.Method)
+		suite.Assert().False(req.IsIdempotent)
+		suite.Assert().Equal("application/x-www-form-urlencoded", req.ContentType)
+
+		form, err := url.ParseQuery(string(req.Body))
+		suite.Require().Nil(err, err)
+		postFn(form)
+
+		return &mgmtResponse{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewReader([]byte{})),
+		}
+	}, nil)
+}
+
+func (suite *UnitTestSuite) TestClusterSettingsManagerUpdateAutoFailoverSettings() {
+	current := `{"enabled":false,"timeout":120,"count":0,"maxCount":1,` +
+		`"failoverOnDataDiskIssues":{"enabled":false,"timePeriod":60},"canAbortRebalance":true}`
+
+	var posted url.Values
+	mgr := suite.clusterSettingsUpdateManager("/settings/autoFailover", current, func(form url.Values) {
+		posted = form
+	})
+
+	enabled := true
+	err := mgr.UpdateAutoFailoverSettings(AutoFailoverSettings{
+		Enabled:                  &enabled,
+		Timeout:                  30 * time.Second,
+		FailoverOnDataDiskIssues: &enabled,
+		DataDiskIssuesTimePeriod: 90 * time.Second,
+	}, &UpdateAutoFailoverSettingsOptions{Timeout: time.Second})
+	suite.Require().Nil(err, err)
+
+	// Settings which were not set keep their current values.
+	suite.Assert().Equal(url.Values{
+		"enabled":                              []string{"true"},
+		"timeout":                              []string{"30"},
+		"maxCount":                             []string{"1"},
+		"failoverOnDataDiskIssues[enabled]":    []string{"true"},
+		"failoverOnDataDiskIssues[timePeriod]": []string{"90"},
+		"canAbortRebalance":                    []string{"true"},
+	}, posted)
+
+	err = mgr.UpdateAutoFailoverSettings(AutoFailoverSettings{MaxCount: -1}, nil)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
+
+func (suite *UnitTestSuite) TestClusterSettingsManagerUpdateEmailAlertSettings() {
+	current := `{"enabled":true,"recipients":["ops@example.com"],"sender":"couchbase@example.com",` +
+		`"emailServer":{"user":"alerts","host":"smtp.example.com","port":25,"encrypt":true},` +
+		`"alerts":["auto_failover_node"]}`
+
+	var posted url.Values
+	mgr := suite.clusterSettingsUpdateManager("/settings/alerts", current, func(form url.Values) {
+		posted = form
+	})
+
+	encrypt := false
+	err := mgr.UpdateEmailAlertSettings(EmailAlertSettings{
+		Recipients:   []string{"ops@example.com", "dba@example.com"},
+		EmailPort:    587,
+		EmailEncrypt: &encrypt,
+	}, &UpdateEmailAlertSettingsOptions{Timeout: time.Second})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(url.Values{
+		"enabled":      []string{"true"},
+		"recipients":   []string{"ops@example.com,dba@example.com"},
+		"sender":       []string{"couchbase@example.com"},
+		"emailHost":    []string{"smtp.example.com"},
+		"emailPort":    []string{"587"},
+		"emailEncrypt": []string{"false"},
+		"emailUser":    []string{"alerts"},
+		"alerts":       []string{"auto_failover_node"},
+	}, posted)
+
+	// Clearing the recipients of enabled alerts is rejected once merged with the current settings.
+	err = mgr.UpdateEmailAlertSettings(EmailAlertSettings{Recipients: []string{}}, &UpdateEmailAlertSettingsOptions{
+		Timeout: time.Second,
+	})
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
+
+func (suite *UnitTestSuite) TestClusterSettingsManagerGetCompactionSettings() {
+	body := `{"autoCompactionSettings":{"parallelDBAndViewCompaction":false,` +
+		`"databaseFragmentationThreshold":{"percentage":30,"size":1073741824},` +
+		`"viewFragmentationThreshold":{"percentage":"undefined","size":"undefined"}},"purgeInterval":3}`
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+	}
+
+	mgr := suite.clusterSettingsManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/settings/autoCompaction", req.Path)
+		suite.Assert().Equal("GET", req.Method)
+	}, resp, nil)
+
+	settings, err := mgr.GetCompactionSettings(nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(&CompactionSettings{
+		DatabaseFragmentationPercentage: 30,
+		DatabaseFragmentationSizeMB:     1024,
+		PurgeInterval:                   72 * time.Hour,
+	}, settings)
+}
+
+func (suite *UnitTestSuite) TestClusterSettingsManagerUpdateMemoryQuotas() {
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte{})),
+	}
+
+	mgr := suite.clusterSettingsManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/pools/default", req.Path)
+		suite.Assert().Equal("POST", req.Method)
+		suite.Assert().Equal("indexMemoryQuota=512&memoryQuota=2048", string(req.Body))
+	}, resp, nil)
+
+	err := mgr.UpdateMemoryQuotas(MemoryQuotas{
+		DataMB:  2048,
+		IndexMB: 512,
+	}, nil)
+	suite.Require().Nil(err, err)
+
+	err = mgr.UpdateMemoryQuotas(MemoryQuotas{}, nil)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
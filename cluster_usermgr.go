@@ -50,6 +50,8 @@ type jsonUserMetadata struct {
 	Domain          AuthDomain        `json:"domain"`
 	ExternalGroups  []string          `json:"external_groups"`
 	PasswordChanged time.Time         `json:"password_change_date"`
+	Locked          bool              `json:"locked"`
+	Limits          *jsonUserLimits   `json:"limits"`
 }
 
 type jsonGroup struct {
@@ -111,6 +113,16 @@ type Origin struct {
 	Name string
 }
 
+const (
+	// OriginTypeUser indicates that a role is assigned directly to the user.
+	// UNCOMMITTED: This API may change in the future.
+	OriginTypeUser = "user"
+
+	// OriginTypeGroup indicates that a role is inherited from a group.
+	// UNCOMMITTED: This API may change in the future.
+	OriginTypeGroup = "group"
+)
+
 func (o *Origin) fromData(data jsonOrigin) error {
 	o.Type = data.Type
 	o.Name = data.Name
@@ -146,6 +158,36 @@ func (ro *RoleAndOrigins) fromData(data jsonRoleOrigins) error {
 	return nil
 }
 
+// AssignedDirectly returns whether the role is assigned directly to the user, rather than only being inherited from
+// groups. Roles without origins, as returned by older servers, are treated as directly assigned.
+// UNCOMMITTED: This API may change in the future.
+func (ro RoleAndOrigins) AssignedDirectly() bool {
+	if len(ro.Origins) == 0 {
+		return true
+	}
+
+	for _, origin := range ro.Origins {
+		if origin.Type == OriginTypeUser {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InheritedFrom returns the names of the groups from which the user inherits the role.
+// UNCOMMITTED: This API may change in the future.
+func (ro RoleAndOrigins) InheritedFrom() []string {
+	var groups []string
+	for _, origin := range ro.Origins {
+		if origin.Type == OriginTypeGroup {
+			groups = append(groups, origin.Name)
+		}
+	}
+
+	return groups
+}
+
 // User represents a user which was retrieved from the server.
 type User struct {
 	Username    string
@@ -154,6 +196,10 @@ type User struct {
 	Roles    []Role
 	Groups   []string
 	Password string
+	// Limits are the rate limits applied to the user, when nil upserting the user leaves any existing limits
+	// unchanged.
+	// UNCOMMITTED: This API may change in the future.
+	Limits *UserLimits
 }
 
 // UserAndMetadata represents a user and user meta-data from the server.
@@ -165,6 +211,10 @@ type UserAndMetadata struct {
 	EffectiveRoles  []RoleAndOrigins
 	ExternalGroups  []string
 	PasswordChanged time.Time
+	// Locked indicates whether the user has been locked, preventing them from authenticating. This is only reported
+	// by servers which support locking users.
+	// UNCOMMITTED: This API may change in the future.
+	Locked bool
 }
 
 func (um *UserAndMetadata) fromData(data jsonUserMetadata) error {
@@ -175,6 +225,12 @@ func (um *UserAndMetadata) fromData(data jsonUserMetadata) error {
 	um.ExternalGroups = data.ExternalGroups
 	um.Domain = data.Domain
 	um.PasswordChanged = data.PasswordChanged
+	um.Locked = data.Locked
+
+	if data.Limits != nil {
+		um.User.Limits = &UserLimits{}
+		um.User.Limits.fromData(*data.Limits)
+	}
 
 	var roles []Role
 	var effectiveRoles []RoleAndOrigins
@@ -187,16 +243,8 @@ func (um *UserAndMetadata) fromData(data jsonUserMetadata) error {
 
 		effectiveRoles = append(effectiveRoles, effectiveRole)
 
-		role := effectiveRole.Role
-		if roleData.Origins == nil {
-			roles = append(roles, role)
-		} else {
-			for _, origin := range effectiveRole.Origins {
-				if origin.Type == "user" {
-					roles = append(roles, role)
-					break
-				}
-			}
+		if effectiveRole.AssignedDirectly() {
+			roles = append(roles, effectiveRole.Role)
 		}
 	}
 	um.EffectiveRoles = effectiveRoles
@@ -488,3 +536,33 @@ func (um *UserManager) ChangePassword(newPassword string, opts *ChangePasswordOp
 		return provider.ChangePassword(newPassword, opts)
 	})
 }
+
+// SetUserLockedOptions is the set of options available to the user manager SetUserLocked operation.
+// UNCOMMITTED: This API may change in the future.
+type SetUserLockedOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// SetUserLocked locks or unlocks a local user. A locked user cannot authenticate until they are unlocked, but their
+// roles and settings are retained.
+// UNCOMMITTED: This API may change in the future.
+func (um *UserManager) SetUserLocked(name string, locked bool, opts *SetUserLockedOptions) error {
+	return autoOpControlErrorOnly(um.controller, "manager_users_set_user_locked", func(provider userManagerProvider) error {
+		if name == "" {
+			return makeInvalidArgumentsError("name cannot be empty")
+		}
+
+		if opts == nil {
+			opts = &SetUserLockedOptions{}
+		}
+
+		return provider.SetUserLocked(name, locked, opts)
+	})
+}
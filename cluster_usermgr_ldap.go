@@ -0,0 +1,175 @@
+package gocb
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// LDAPEncryption specifies how connections to the LDAP servers are secured.
+// UNCOMMITTED: This API may change in the future.
+type LDAPEncryption string
+
+const (
+	// LDAPEncryptionNone specifies that connections to the LDAP servers are not encrypted.
+	LDAPEncryptionNone LDAPEncryption = "None"
+
+	// LDAPEncryptionTLS specifies that connections to the LDAP servers use TLS.
+	LDAPEncryptionTLS LDAPEncryption = "TLS"
+
+	// LDAPEncryptionStartTLS specifies that connections to the LDAP servers are upgraded using StartTLS.
+	LDAPEncryptionStartTLS LDAPEncryption = "StartTLSExtension"
+)
+
+// LDAPSettings represents the configuration used by the server to authenticate and authorize users against LDAP.
+// When upserting, only settings which are set are sent to the server: nil booleans and zero valued strings, numbers
+// and durations leave the existing server setting unchanged. Booleans are always set when fetched.
+// UNCOMMITTED: This API may change in the future.
+type LDAPSettings struct {
+	AuthenticationEnabled *bool
+	AuthorizationEnabled  *bool
+	Hosts                 []string
+	Port                  int
+	Encryption            LDAPEncryption
+	ServerCertValidation  *bool
+	// CACert is the PEM encoded certificate used to validate the LDAP servers.
+	CACert string
+	// UserDNMapping is the JSON encoded mapping from usernames to LDAP distinguished names, such as
+	// {"template":"uid=%u,ou=users,dc=example,dc=com"}.
+	UserDNMapping string
+	BindDN        string
+	// BindPassword is the password for BindDN. It is never returned by the server, so is empty when fetched.
+	BindPassword           string
+	GroupsQuery            string
+	NestedGroupsEnabled    *bool
+	NestedGroupsMaxDepth   int
+	CacheValueLifetime     time.Duration
+	RequestTimeout         time.Duration
+	MaxParallelConnections int
+	MaxCacheSize           int
+}
+
+type jsonLDAPSettings struct {
+	AuthenticationEnabled  bool            `json:"authenticationEnabled"`
+	AuthorizationEnabled   bool            `json:"authorizationEnabled"`
+	Hosts                  []string        `json:"hosts"`
+	Port                   int             `json:"port"`
+	Encryption             string          `json:"encryption"`
+	ServerCertValidation   bool            `json:"serverCertValidation"`
+	CACert                 string          `json:"cacert"`
+	UserDNMapping          json.RawMessage `json:"userDNMapping"`
+	BindDN                 string          `json:"bindDN"`
+	GroupsQuery            string          `json:"groupsQuery"`
+	NestedGroupsEnabled    bool            `json:"nestedGroupsEnabled"`
+	NestedGroupsMaxDepth   int             `json:"nestedGroupsMaxDepth"`
+	CacheValueLifetime     uint64          `json:"cacheValueLifetime"`
+	RequestTimeout         uint64          `json:"requestTimeout"`
+	MaxParallelConnections int             `json:"maxParallelConnections"`
+	MaxCacheSize           int             `json:"maxCacheSize"`
+}
+
+func (s *LDAPSettings) fromData(data jsonLDAPSettings) error {
+	s.AuthenticationEnabled = &data.AuthenticationEnabled
+	s.AuthorizationEnabled = &data.AuthorizationEnabled
+	s.Hosts = data.Hosts
+	s.Port = data.Port
+	s.Encryption = LDAPEncryption(data.Encryption)
+	s.ServerCertValidation = &data.ServerCertValidation
+	s.CACert = data.CACert
+	if len(data.UserDNMapping) > 0 && string(data.UserDNMapping) != "null" {
+		s.UserDNMapping = string(data.UserDNMapping)
+	}
+	s.BindDN = data.BindDN
+	s.GroupsQuery = data.GroupsQuery
+	s.NestedGroupsEnabled = &data.NestedGroupsEnabled
+	s.NestedGroupsMaxDepth = data.NestedGroupsMaxDepth
+	s.CacheValueLifetime = time.Duration(data.CacheValueLifetime) * time.Millisecond
+	s.RequestTimeout = time.Duration(data.RequestTimeout) * time.Millisecond
+	s.MaxParallelConnections = data.MaxParallelConnections
+	s.MaxCacheSize = data.MaxCacheSize
+
+	return nil
+}
+
+// GetLDAPSettingsOptions is the set of options available to the user manager GetLDAPSettings operation.
+// UNCOMMITTED: This API may change in the future.
+type GetLDAPSettingsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// GetLDAPSettings returns the LDAP configuration of the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (um *UserManager) GetLDAPSettings(opts *GetLDAPSettingsOptions) (*LDAPSettings, error) {
+	return autoOpControl(um.controller, "manager_users_get_ldap_settings", func(provider userManagerProvider) (*LDAPSettings, error) {
+		if opts == nil {
+			opts = &GetLDAPSettingsOptions{}
+		}
+
+		return provider.GetLDAPSettings(opts)
+	})
+}
+
+// UpsertLDAPSettingsOptions is the set of options available to the user manager UpsertLDAPSettings operation.
+// UNCOMMITTED: This API may change in the future.
+type UpsertLDAPSettingsOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// UpsertLDAPSettings updates the LDAP configuration of the cluster.
+// UNCOMMITTED: This API may change in the future.
+func (um *UserManager) UpsertLDAPSettings(settings LDAPSettings, opts *UpsertLDAPSettingsOptions) error {
+	return autoOpControlErrorOnly(um.controller, "manager_users_upsert_ldap_settings", func(provider userManagerProvider) error {
+		if settings.Port < 0 {
+			return makeInvalidArgumentsError("port cannot be negative")
+		}
+		if settings.UserDNMapping != "" && !json.Valid([]byte(settings.UserDNMapping)) {
+			return makeInvalidArgumentsError("user dn mapping must be valid JSON")
+		}
+
+		if opts == nil {
+			opts = &UpsertLDAPSettingsOptions{}
+		}
+
+		return provider.UpsertLDAPSettings(settings, opts)
+	})
+}
+
+// InvalidateLDAPCacheOptions is the set of options available to the user manager InvalidateLDAPCache operation.
+// UNCOMMITTED: This API may change in the future.
+type InvalidateLDAPCacheOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// InvalidateLDAPCache clears the cache of LDAP users and group memberships held by the cluster, so that group
+// memberships are synced from the LDAP servers on the next authentication of each user.
+// UNCOMMITTED: This API may change in the future.
+func (um *UserManager) InvalidateLDAPCache(opts *InvalidateLDAPCacheOptions) error {
+	return autoOpControlErrorOnly(um.controller, "manager_users_invalidate_ldap_cache", func(provider userManagerProvider) error {
+		if opts == nil {
+			opts = &InvalidateLDAPCacheOptions{}
+		}
+
+		return provider.InvalidateLDAPCache(opts)
+	})
+}
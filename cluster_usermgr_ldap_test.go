@@ -0,0 +1,111 @@
+package gocb
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) TestUserManagerGetLDAPSettings() {
+	settingsJSON := `{"authenticationEnabled":true,"authorizationEnabled":false,"hosts":["ldap1","ldap2"],"port":636,
+		"encryption":"TLS","serverCertValidation":true,"userDNMapping":{"template":"uid=%u,dc=example"},
+		"bindDN":"cn=admin","bindPass":"**********","nestedGroupsEnabled":true,"nestedGroupsMaxDepth":5,
+		"cacheValueLifetime":300000,"requestTimeout":5000,"maxParallelConnections":100,"maxCacheSize":10000}`
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte(settingsJSON))),
+	}
+
+	usrMgr := suite.userManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/settings/ldap", req.Path)
+		suite.Assert().True(req.IsIdempotent)
+		suite.Assert().Equal("GET", req.Method)
+	}, resp, nil)
+
+	settings, err := usrMgr.GetLDAPSettings(nil)
+	suite.Require().Nil(err, err)
+
+	enabled, disabled := true, false
+	suite.Assert().Equal(&LDAPSettings{
+		AuthenticationEnabled:  &enabled,
+		AuthorizationEnabled:   &disabled,
+		Hosts:                  []string{"ldap1", "ldap2"},
+		Port:                   636,
+		Encryption:             LDAPEncryptionTLS,
+		ServerCertValidation:   &enabled,
+		UserDNMapping:          `{"template":"uid=%u,dc=example"}`,
+		BindDN:                 "cn=admin",
+		NestedGroupsEnabled:    &enabled,
+		NestedGroupsMaxDepth:   5,
+		CacheValueLifetime:     5 * time.Minute,
+		RequestTimeout:         5 * time.Second,
+		MaxParallelConnections: 100,
+		MaxCacheSize:           10000,
+	}, settings)
+}
+
+func (suite *UnitTestSuite) TestUserManagerUpsertLDAPSettings() {
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte{})),
+	}
+
+	usrMgr := suite.userManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/settings/ldap", req.Path)
+		suite.Assert().False(req.IsIdempotent)
+		suite.Assert().Equal("POST", req.Method)
+
+		form, err := url.ParseQuery(string(req.Body))
+		suite.Require().Nil(err, err)
+		suite.Assert().Equal(url.Values{
+			"authenticationEnabled": []string{"true"},
+			"authorizationEnabled":  []string{"false"},
+			"hosts":                 []string{"ldap1,ldap2"},
+			"port":                  []string{"389"},
+			"encryption":            []string{"StartTLSExtension"},
+			"bindPass":              []string{"secret"},
+			"requestTimeout":        []string{"2500"},
+		}, form)
+	}, resp, nil)
+
+	enabled, disabled := true, false
+	err := usrMgr.UpsertLDAPSettings(LDAPSettings{
+		AuthenticationEnabled: &enabled,
+		AuthorizationEnabled:  &disabled,
+		Hosts:                 []string{"ldap1", "ldap2"},
+		Port:                  389,
+		Encryption:            LDAPEncryptionStartTLS,
+		BindPassword:          "secret",
+		RequestTimeout:        2500 * time.Millisecond,
+	}, nil)
+	suite.Require().Nil(err, err)
+
+	err = usrMgr.UpsertLDAPSettings(LDAPSettings{
+		UserDNMapping: "uid=%u",
+	}, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestUserManagerInvalidateLDAPCache() {
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte{})),
+	}
+
+	usrMgr := suite.userManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/settings/invalidateLDAPCache", req.Path)
+		suite.Assert().Equal("POST", req.Method)
+	}, resp, nil)
+
+	err := usrMgr.InvalidateLDAPCache(nil)
+	suite.Require().Nil(err, err)
+}
@@ -0,0 +1,134 @@
+package gocb
+
+// UserLimits are the rate limits applied to a user, a nil service limit means that the user is not limited on
+// that service. Limits are only enforced once rate limiting has been enabled on the cluster.
+// UNCOMMITTED: This API may change in the future.
+type UserLimits struct {
+	KeyValue       *KeyValueUserLimits
+	Query          *QueryUserLimits
+	Search         *SearchUserLimits
+	Index          *IndexUserLimits
+	ClusterManager *ClusterManagerUserLimits
+}
+
+// KeyValueUserLimits are the rate limits applied to a user on the data service.
+// UNCOMMITTED: This API may change in the future.
+type KeyValueUserLimits struct {
+	NumConnections   uint32
+	NumOpsPerMin     uint32
+	IngressMiBPerMin uint32
+	EgressMiBPerMin  uint32
+}
+
+// QueryUserLimits are the rate limits applied to a user on the query service.
+// UNCOMMITTED: This API may change in the future.
+type QueryUserLimits struct {
+	NumQueriesPerMin      uint32
+	NumConcurrentRequests uint32
+	IngressMiBPerMin      uint32
+	EgressMiBPerMin       uint32
+}
+
+// SearchUserLimits are the rate limits applied to a user on the search service.
+// UNCOMMITTED: This API may change in the future.
+type SearchUserLimits struct {
+	NumQueriesPerMin      uint32
+	NumConcurrentRequests uint32
+	IngressMiBPerMin      uint32
+	EgressMiBPerMin       uint32
+}
+
+// IndexUserLimits are the rate limits applied to a user on the index service.
+// UNCOMMITTED: This API may change in the future.
+type IndexUserLimits struct {
+	NumIndexes uint32
+}
+
+// ClusterManagerUserLimits are the rate limits applied to a user on the cluster manager.
+// UNCOMMITTED: This API may change in the future.
+type ClusterManagerUserLimits struct {
+	NumConcurrentRequests uint32
+	IngressMiBPerMin      uint32
+	EgressMiBPerMin       uint32
+}
+
+type jsonKeyValueUserLimits struct {
+	NumConnections   uint32 `json:"num_connections"`
+	NumOpsPerMin     uint32 `json:"num_ops_per_min"`
+	IngressMiBPerMin uint32 `json:"ingress_mib_per_min"`
+	EgressMiBPerMin  uint32 `json:"egress_mib_per_min"`
+}
+
+type jsonQueryUserLimits struct {
+	NumQueriesPerMin      uint32 `json:"num_queries_per_min"`
+	NumConcurrentRequests uint32 `json:"num_concurrent_requests"`
+	IngressMiBPerMin      uint32 `json:"ingress_mib_per_min"`
+	EgressMiBPerMin       uint32 `json:"egress_mib_per_min"`
+}
+
+type jsonIndexUserLimits struct {
+	NumIndexes uint32 `json:"num_indexes"`
+}
+
+type jsonClusterManagerUserLimits struct {
+	NumConcurrentRequests uint32 `json:"num_concurrent_requests"`
+	IngressMiBPerMin      uint32 `json:"ingress_mib_per_min"`
+	EgressMiBPerMin       uint32 `json:"egress_mib_per_min"`
+}
+
+type jsonUserLimits struct {
+	KeyValue       *jsonKeyValueUserLimits       `json:"kv,omitempty"`
+	Query          *jsonQueryUserLimits          `json:"query,omitempty"`
+	Search         *jsonQueryUserLimits          `json:"fts,omitempty"`
+	Index          *jsonIndexUserLimits          `json:"index,omitempty"`
+	ClusterManager *jsonClusterManagerUserLimits `json:"clusterManager,omitempty"`
+}
+
+func (ul *UserLimits) fromData(data jsonUserLimits) {
+	if data.KeyValue != nil {
+		limits := KeyValueUserLimits(*data.KeyValue)
+		ul.KeyValue = &limits
+	}
+	if data.Query != nil {
+		limits := QueryUserLimits(*data.Query)
+		ul.Query = &limits
+	}
+	if data.Search != nil {
+		limits := SearchUserLimits(*data.Search)
+		ul.Search = &limits
+	}
+	if data.Index != nil {
+		limits := IndexUserLimits(*data.Index)
+		ul.Index = &limits
+	}
+	if data.ClusterManager != nil {
+		limits := ClusterManagerUserLimits(*data.ClusterManager)
+		ul.ClusterManager = &limits
+	}
+}
+
+func (ul *UserLimits) toData() jsonUserLimits {
+	var data jsonUserLimits
+	if ul.KeyValue != nil {
+		limits := jsonKeyValueUserLimits(*ul.KeyValue)
+		data.KeyValue = &limits
+	}
+	if ul.Query != nil {
+		limits := jsonQueryUserLimits(*ul.Query)
+		data.Query = &limits
+	}
+	if ul.Search != nil {
+		limits := jsonQueryUserLimits(*ul.Search)
+		data.Search = &limits
+	}
+	if ul.Index != nil {
+		limits := jsonIndexUserLimits(*ul.Index)
+		data.Index = &limits
+	}
+	if ul.ClusterManager != nil {
+		limits := jsonClusterManagerUserLimits(*ul.ClusterManager)
+		data.ClusterManager = &limits
+	}
+
+	return data
+}
@@ -2,8 +2,10 @@ package gocb
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"io"
+	"net/url"
 	"testing"
 	"time"
 
@@ -657,3 +659,132 @@ func (suite *UnitTestSuite) TestUserManagerDropGroupDoesntExist() {
 		suite.T().Fatalf("Expected user not found error, %s", err)
 	}
 }
+
+func (suite *UnitTestSuite) TestUserManagerSetUserLocked() {
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte{})),
+	}
+
+	username := "larry"
+	usrMgr := suite.userManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/settings/rbac/users/local/"+username, req.Path)
+		suite.Assert().False(req.IsIdempotent)
+		suite.Assert().Equal(1*time.Second, req.Timeout)
+		suite.Assert().Equal("PATCH", req.Method)
+		suite.Assert().Equal("locked=true", string(req.Body))
+	}, resp, nil)
+
+	err := usrMgr.SetUserLocked(username, true, &SetUserLockedOptions{
+		Timeout: 1 * time.Second,
+	})
+	suite.Require().Nil(err, err)
+
+	err = usrMgr.SetUserLocked("", true, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestUserManagerGetUserRoleOrigins() {
+	userJSON := `{"id":"larry","domain":"local","locked":true,"groups":["admins"],"roles":[
+		{"role":"admin","origins":[{"type":"group","name":"admins"}]},
+		{"role":"data_reader","bucket_name":"default","origins":[{"type":"user"},{"type":"group","name":"readers"}]},
+		{"role":"ro_admin"}
+	]}`
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte(userJSON))),
+	}
+
+	usrMgr := suite.userManager(nil, resp, nil)
+
+	user, err := usrMgr.GetUser("larry", nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().True(user.Locked)
+	suite.Require().Len(user.EffectiveRoles, 3)
+
+	suite.Assert().False(user.EffectiveRoles[0].AssignedDirectly())
+	suite.Assert().Equal([]string{"admins"}, user.EffectiveRoles[0].InheritedFrom())
+
+	suite.Assert().True(user.EffectiveRoles[1].AssignedDirectly())
+	suite.Assert().Equal([]string{"readers"}, user.EffectiveRoles[1].InheritedFrom())
+
+	suite.Assert().True(user.EffectiveRoles[2].AssignedDirectly())
+	suite.Assert().Empty(user.EffectiveRoles[2].InheritedFrom())
+
+	suite.Require().Len(user.Roles, 2)
+	suite.Assert().Equal("data_reader", user.Roles[0].Name)
+	suite.Assert().Equal("ro_admin", user.Roles[1].Name)
+}
+
+func (suite *UnitTestSuite) TestUserManagerUpsertUserLimits() {
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte{})),
+	}
+
+	usrMgr := suite.userManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("PUT", req.Method)
+
+		form, err := url.ParseQuery(string(req.Body))
+		suite.Require().Nil(err, err)
+
+		var limits map[string]map[string]uint32
+		suite.Require().Nil(json.Unmarshal([]byte(form.Get("limits")), &limits))
+		suite.Assert().Equal(map[string]map[string]uint32{
+			"kv": {
+				"num_connections":     10,
+				"num_ops_per_min":     1000,
+				"ingress_mib_per_min": 5,
+				"egress_mib_per_min":  6,
+			},
+			"index": {
+				"num_indexes": 3,
+			},
+		}, limits)
+	}, resp, nil)
+
+	err := usrMgr.UpsertUser(User{
+		Username: "larry",
+		Roles:    []Role{{Name: "admin"}},
+		Limits: &UserLimits{
+			KeyValue: &KeyValueUserLimits{
+				NumConnections:   10,
+				NumOpsPerMin:     1000,
+				IngressMiBPerMin: 5,
+				EgressMiBPerMin:  6,
+			},
+			Index: &IndexUserLimits{
+				NumIndexes: 3,
+			},
+		},
+	}, nil)
+	suite.Require().Nil(err, err)
+}
+
+func (suite *UnitTestSuite) TestUserManagerGetUserLimits() {
+	userJSON := `{"id":"larry","domain":"local","roles":[{"role":"admin","origins":[{"type":"user"}]}],
+		"limits":{"query":{"num_queries_per_min":10,"num_concurrent_requests":2,"ingress_mib_per_min":1,"egress_mib_per_min":3}}}`
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte(userJSON))),
+	}
+
+	usrMgr := suite.userManager(nil, resp, nil)
+
+	user, err := usrMgr.GetUser("larry", nil)
+	suite.Require().Nil(err, err)
+
+	suite.Require().NotNil(user.Limits)
+	suite.Assert().Nil(user.Limits.KeyValue)
+	suite.Assert().Equal(&QueryUserLimits{
+		NumQueriesPerMin:      10,
+		NumConcurrentRequests: 2,
+		IngressMiBPerMin:      1,
+		EgressMiBPerMin:       3,
+	}, user.Limits.Query)
+}
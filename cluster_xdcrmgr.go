@@ -0,0 +1,356 @@
+package gocb
+
+import (
+	"context"
+	"time"
+)
+
+// XDCRManager provides methods for managing cross data center replication (XDCR), which replicates the documents of
+// a bucket to a bucket on another cluster.
+// UNCOMMITTED: This API may change in the future.
+type XDCRManager struct {
+	controller *providerController[xdcrManagementProvider]
+}
+
+// XDCREncryptionType specifies how the connection to a remote cluster is secured.
+// UNCOMMITTED: This API may change in the future.
+type XDCREncryptionType string
+
+const (
+	// XDCREncryptionTypeNone specifies that the connection to the remote cluster is not encrypted.
+	XDCREncryptionTypeNone XDCREncryptionType = "none"
+
+	// XDCREncryptionTypeHalf specifies that only credentials are encrypted when connecting to the remote cluster.
+	XDCREncryptionTypeHalf XDCREncryptionType = "half"
+
+	// XDCREncryptionTypeFull specifies that all data sent to the remote cluster is encrypted.
+	XDCREncryptionTypeFull XDCREncryptionType = "full"
+)
+
+// XDCRRemoteCluster is a reference to a cluster which buckets can be replicated to.
+// UNCOMMITTED: This API may change in the future.
+type XDCRRemoteCluster struct {
+	Name     string
+	Hostname string
+	Username string
+	// Password is the password for Username. It is never returned by the server, so is empty when fetched.
+	Password       string
+	EncryptionType XDCREncryptionType
+	// Certificate is the PEM encoded certificate used to validate the remote cluster when using full encryption.
+	Certificate string
+	// UUID is the UUID of the remote cluster, which is populated by the server.
+	UUID string
+}
+
+// XDCRReplicationPriority specifies the share of resources that a replication is given relative to others.
+// UNCOMMITTED: This API may change in the future.
+type XDCRReplicationPriority string
+
+const (
+	// XDCRReplicationPriorityHigh gives the replication a high share of resources.
+	XDCRReplicationPriorityHigh XDCRReplicationPriority = "High"
+
+	// XDCRReplicationPriorityMedium gives the replication a medium share of resources.
+	XDCRReplicationPriorityMedium XDCRReplicationPriority = "Medium"
+
+	// XDCRReplicationPriorityLow gives the replication a low share of resources.
+	XDCRReplicationPriorityLow XDCRReplicationPriority = "Low"
+)
+
+// XDCRReplication is a replication of a bucket to a bucket on a remote cluster.
+// UNCOMMITTED: This API may change in the future.
+type XDCRReplication struct {
+	SourceBucket string
+	// RemoteCluster is the name of the remote cluster reference to replicate to.
+	RemoteCluster string
+	TargetBucket  string
+	// FilterExpression limits the replication to documents which match the expression, such as
+	// REGEXP_CONTAINS(META().id, "^airline").
+	FilterExpression string
+	Priority         XDCRReplicationPriority
+	// CollectionMappings maps source scopes or collections, in the form scope or scope.collection, to target scopes
+	// or collections. When set, only the mapped scopes and collections are replicated.
+	CollectionMappings map[string]string
+}
+
+// XDCRReplicationState is the state of a replication.
+// UNCOMMITTED: This API may change in the future.
+type XDCRReplicationState string
+
+const (
+	// XDCRReplicationStateRunning indicates that the replication is running.
+	XDCRReplicationStateRunning XDCRReplicationState = "running"
+
+	// XDCRReplicationStatePaused indicates that the replication is paused.
+	XDCRReplicationStatePaused XDCRReplicationState = "paused"
+
+	// XDCRReplicationStateNotRunning indicates that the replication is not running, such as when it is starting.
+	XDCRReplicationStateNotRunning XDCRReplicationState = "notRunning"
+)
+
+// XDCRReplicationStatus is the status of a replication.
+// UNCOMMITTED: This API may change in the future.
+type XDCRReplicationStatus struct {
+	ID                string
+	State             XDCRReplicationState
+	SourceBucket      string
+	RemoteClusterUUID string
+	TargetBucket      string
+	FilterExpression  string
+	// ChangesLeft is the number of mutations which are yet to be replicated.
+	ChangesLeft uint64
+	DocsChecked uint64
+	DocsWritten uint64
+	// Errors are the most recent errors encountered by the replication.
+	Errors []string
+}
+
+// CreateXDCRRemoteClusterOptions is the set of options available to the XDCR manager CreateRemoteCluster operation.
+// UNCOMMITTED: This API may change in the future.
+type CreateXDCRRemoteClusterOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// CreateRemoteCluster creates a reference to a remote cluster, which replications can then be created against.
+// UNCOMMITTED: This API may change in the future.
+func (xm *XDCRManager) CreateRemoteCluster(cluster XDCRRemoteCluster, opts *CreateXDCRRemoteClusterOptions) error {
+	return autoOpControlErrorOnly(xm.controller, "manager_xdcr_create_remote_cluster", func(provider xdcrManagementProvider) error {
+		if cluster.Name == "" {
+			return makeInvalidArgumentsError("remote cluster name cannot be empty")
+		}
+		if cluster.Hostname == "" {
+			return makeInvalidArgumentsError("remote cluster hostname cannot be empty")
+		}
+		switch cluster.EncryptionType {
+		case "", XDCREncryptionTypeNone, XDCREncryptionTypeHalf, XDCREncryptionTypeFull:
+		default:
+			return makeInvalidArgumentsError("unknown encryption type")
+		}
+
+		if opts == nil {
+			opts = &CreateXDCRRemoteClusterOptions{}
+		}
+
+		return provider.CreateRemoteCluster(cluster, opts)
+	})
+}
+
+// GetAllXDCRRemoteClustersOptions is the set of options available to the XDCR manager GetAllRemoteClusters operation.
+// UNCOMMITTED: This API may change in the future.
+type GetAllXDCRRemoteClustersOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// GetAllRemoteClusters returns all of the remote cluster references.
+// UNCOMMITTED: This API may change in the future.
+func (xm *XDCRManager) GetAllRemoteClusters(opts *GetAllXDCRRemoteClustersOptions) ([]XDCRRemoteCluster, error) {
+	return autoOpControl(xm.controller, "manager_xdcr_get_all_remote_clusters", func(provider xdcrManagementProvider) ([]XDCRRemoteCluster, error) {
+		if opts == nil {
+			opts = &GetAllXDCRRemoteClustersOptions{}
+		}
+
+		return provider.GetAllRemoteClusters(opts)
+	})
+}
+
+// DeleteXDCRRemoteClusterOptions is the set of options available to the XDCR manager DeleteRemoteCluster operation.
+// UNCOMMITTED: This API may change in the future.
+type DeleteXDCRRemoteClusterOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// DeleteRemoteCluster deletes a remote cluster reference, any replications to the remote cluster must be deleted
+// first.
+// UNCOMMITTED: This API may change in the future.
+func (xm *XDCRManager) DeleteRemoteCluster(name string, opts *DeleteXDCRRemoteClusterOptions) error {
+	return autoOpControlErrorOnly(xm.controller, "manager_xdcr_delete_remote_cluster", func(provider xdcrManagementProvider) error {
+		if name == "" {
+			return makeInvalidArgumentsError("remote cluster name cannot be empty")
+		}
+
+		if opts == nil {
+			opts = &DeleteXDCRRemoteClusterOptions{}
+		}
+
+		return provider.DeleteRemoteCluster(name, opts)
+	})
+}
+
+// CreateXDCRReplicationOptions is the set of options available to the XDCR manager CreateReplication operation.
+// UNCOMMITTED: This API may change in the future.
+type CreateXDCRReplicationOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// CreateReplication creates and starts a continuous replication, returning the ID of the replication.
+// UNCOMMITTED: This API may change in the future.
+func (xm *XDCRManager) CreateReplication(replication XDCRReplication, opts *CreateXDCRReplicationOptions) (string, error) {
+	return autoOpControl(xm.controller, "manager_xdcr_create_replication", func(provider xdcrManagementProvider) (string, error) {
+		if replication.SourceBucket == "" {
+			return "", makeInvalidArgumentsError("source bucket cannot be empty")
+		}
+		if replication.RemoteCluster == "" {
+			return "", makeInvalidArgumentsError("remote cluster cannot be empty")
+		}
+		if replication.TargetBucket == "" {
+			return "", makeInvalidArgumentsError("target bucket cannot be empty")
+		}
+		switch replication.Priority {
+		case "", XDCRReplicationPriorityHigh, XDCRReplicationPriorityMedium, XDCRReplicationPriorityLow:
+		default:
+			return "", makeInvalidArgumentsError("unknown replication priority")
+		}
+
+		if opts == nil {
+			opts = &CreateXDCRReplicationOptions{}
+		}
+
+		return provider.CreateReplication(replication, opts)
+	})
+}
+
+// PauseXDCRReplicationOptions is the set of options available to the XDCR manager PauseReplication operation.
+// UNCOMMITTED: This API may change in the future.
+type PauseXDCRReplicationOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// PauseReplication pauses a replication, which can later be resumed from where it stopped.
+// UNCOMMITTED: This API may change in the future.
+func (xm *XDCRManager) PauseReplication(replicationID string, opts *PauseXDCRReplicationOptions) error {
+	return autoOpControlErrorOnly(xm.controller, "manager_xdcr_pause_replication", func(provider xdcrManagementProvider) error {
+		if replicationID == "" {
+			return makeInvalidArgumentsError("replication id cannot be empty")
+		}
+
+		if opts == nil {
+			opts = &PauseXDCRReplicationOptions{}
+		}
+
+		return provider.PauseReplication(replicationID, opts)
+	})
+}
+
+// ResumeXDCRReplicationOptions is the set of options available to the XDCR manager ResumeReplication operation.
+// UNCOMMITTED: This API may change in the future.
+type ResumeXDCRReplicationOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// ResumeReplication resumes a paused replication.
+// UNCOMMITTED: This API may change in the future.
+func (xm *XDCRManager) ResumeReplication(replicationID string, opts *ResumeXDCRReplicationOptions) error {
+	return autoOpControlErrorOnly(xm.controller, "manager_xdcr_resume_replication", func(provider xdcrManagementProvider) error {
+		if replicationID == "" {
+			return makeInvalidArgumentsError("replication id cannot be empty")
+		}
+
+		if opts == nil {
+			opts = &ResumeXDCRReplicationOptions{}
+		}
+
+		return provider.ResumeReplication(replicationID, opts)
+	})
+}
+
+// DeleteXDCRReplicationOptions is the set of options available to the XDCR manager DeleteReplication operation.
+// UNCOMMITTED: This API may change in the future.
+type DeleteXDCRReplicationOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// DeleteReplication stops and deletes a replication. Documents which have already been replicated are not removed
+// from the target bucket.
+// UNCOMMITTED: This API may change in the future.
+func (xm *XDCRManager) DeleteReplication(replicationID string, opts *DeleteXDCRReplicationOptions) error {
+	return autoOpControlErrorOnly(xm.controller, "manager_xdcr_delete_replication", func(provider xdcrManagementProvider) error {
+		if replicationID == "" {
+			return makeInvalidArgumentsError("replication id cannot be empty")
+		}
+
+		if opts == nil {
+			opts = &DeleteXDCRReplicationOptions{}
+		}
+
+		return provider.DeleteReplication(replicationID, opts)
+	})
+}
+
+// GetXDCRReplicationStatusOptions is the set of options available to the XDCR manager GetReplicationStatus operation.
+// UNCOMMITTED: This API may change in the future.
+type GetXDCRReplicationStatusOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// GetReplicationStatus returns the status of a replication, returning ErrXDCRReplicationNotFound if the replication
+// does not exist.
+// UNCOMMITTED: This API may change in the future.
+func (xm *XDCRManager) GetReplicationStatus(replicationID string, opts *GetXDCRReplicationStatusOptions) (*XDCRReplicationStatus, error) {
+	return autoOpControl(xm.controller, "manager_xdcr_get_replication_status", func(provider xdcrManagementProvider) (*XDCRReplicationStatus, error) {
+		if replicationID == "" {
+			return nil, makeInvalidArgumentsError("replication id cannot be empty")
+		}
+
+		if opts == nil {
+			opts = &GetXDCRReplicationStatusOptions{}
+		}
+
+		return provider.GetReplicationStatus(replicationID, opts)
+	})
+}
@@ -0,0 +1,197 @@
+package gocb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) xdcrManager(runFn func(args mock.Arguments), args ...interface{}) *XDCRManager {
+	mockProvider := new(mockMgmtProvider)
+	call := mockProvider.
+		On("executeMgmtRequest", nil, mock.AnythingOfType("mgmtRequest")).
+		Return(args...)
+
+	if runFn != nil {
+		call.Run(runFn)
+	}
+
+	provider := &xdcrManagementProviderCore{
+		provider: mockProvider,
+		tracer:   newTracerWrapper(&NoopTracer{}),
+	}
+
+	return &XDCRManager{
+		controller: &providerController[xdcrManagementProvider]{
+			get: func() (xdcrManagementProvider, error) {
+				return provider, nil
+			},
+			opController: mockOpController{},
+		},
+	}
+}
+
+func (suite *UnitTestSuite) TestXDCRManagerCreateRemoteCluster() {
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte(`{"name":"remote"}`))),
+	}
+
+	mgr := suite.xdcrManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/pools/default/remoteClusters", req.Path)
+		suite.Assert().Equal("POST", req.Method)
+		suite.Assert().False(req.IsIdempotent)
+
+		form, err := url.ParseQuery(string(req.Body))
+		suite.Require().Nil(err, err)
+		suite.Assert().Equal("remote", form.Get("name"))
+		suite.Assert().Equal("10.0.0.5:8091", form.Get("hostname"))
+		suite.Assert().Equal("Administrator", form.Get("username"))
+		suite.Assert().Equal("password", form.Get("password"))
+		suite.Assert().Equal("1", form.Get("demandEncryption"))
+		suite.Assert().Equal("full", form.Get("encryptionType"))
+		suite.Assert().Equal("-----BEGIN CERTIFICATE-----", form.Get("certificate"))
+	}, resp, nil)
+
+	err := mgr.CreateRemoteCluster(XDCRRemoteCluster{
+		Name:           "remote",
+		Hostname:       "10.0.0.5:8091",
+		Username:       "Administrator",
+		Password:       "password",
+		EncryptionType: XDCREncryptionTypeFull,
+		Certificate:    "-----BEGIN CERTIFICATE-----",
+	}, nil)
+	suite.Require().Nil(err, err)
+
+	err = mgr.CreateRemoteCluster(XDCRRemoteCluster{Hostname: "10.0.0.5"}, nil)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
+
+func (suite *UnitTestSuite) TestXDCRManagerCreateReplication() {
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte(`{"id":"a1b2c3/travel-sample/travel"}`))),
+	}
+
+	mgr := suite.xdcrManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/controller/createReplication", req.Path)
+		suite.Assert().Equal("POST", req.Method)
+
+		form, err := url.ParseQuery(string(req.Body))
+		suite.Require().Nil(err, err)
+		suite.Assert().Equal("travel-sample", form.Get("fromBucket"))
+		suite.Assert().Equal("remote", form.Get("toCluster"))
+		suite.Assert().Equal("travel", form.Get("toBucket"))
+		suite.Assert().Equal("continuous", form.Get("replicationType"))
+		suite.Assert().Equal(`REGEXP_CONTAINS(META().id, "^airline")`, form.Get("filterExpression"))
+		suite.Assert().Equal("High", form.Get("priority"))
+		suite.Assert().Equal("true", form.Get("collectionsExplicitMapping"))
+
+		var rules map[string]string
+		suite.Require().Nil(json.Unmarshal([]byte(form.Get("colMappingRules")), &rules))
+		suite.Assert().Equal(map[string]string{"inventory.airline": "flights.airline"}, rules)
+	}, resp, nil)
+
+	id, err := mgr.CreateReplication(XDCRReplication{
+		SourceBucket:       "travel-sample",
+		RemoteCluster:      "remote",
+		TargetBucket:       "travel",
+		FilterExpression:   `REGEXP_CONTAINS(META().id, "^airline")`,
+		Priority:           XDCRReplicationPriorityHigh,
+		CollectionMappings: map[string]string{"inventory.airline": "flights.airline"},
+	}, nil)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("a1b2c3/travel-sample/travel", id)
+
+	_, err = mgr.CreateReplication(XDCRReplication{
+		SourceBucket:  "travel-sample",
+		RemoteCluster: "remote",
+		TargetBucket:  "travel",
+		Priority:      "Urgent",
+	}, nil)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
+
+func (suite *UnitTestSuite) TestXDCRManagerPauseReplication() {
+	resp := &mgmtResponse{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader([]byte(`{}`))),
+	}
+
+	mgr := suite.xdcrManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/settings/replications/a1b2c3%2Ftravel-sample%2Ftravel", req.Path)
+		suite.Assert().Equal("POST", req.Method)
+		suite.Assert().Equal("pauseRequested=true", string(req.Body))
+	}, resp, nil)
+
+	err := mgr.PauseReplication("a1b2c3/travel-sample/travel", nil)
+	suite.Require().Nil(err, err)
+}
+
+func (suite *UnitTestSuite) TestXDCRManagerDeleteReplicationNotFound() {
+	resp := &mgmtResponse{
+		StatusCode: 404,
+		Body:       io.NopCloser(bytes.NewReader([]byte(`{"error":"replication not found"}`))),
+	}
+
+	mgr := suite.xdcrManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/controller/cancelXDCR/a1b2c3%2Ftravel-sample%2Ftravel", req.Path)
+		suite.Assert().Equal("DELETE", req.Method)
+	}, resp, nil)
+
+	err := mgr.DeleteReplication("a1b2c3/travel-sample/travel", nil)
+	suite.Assert().True(errors.Is(err, ErrXDCRReplicationNotFound))
+}
+
+func (suite *UnitTestSuite) TestXDCRManagerGetReplicationStatus() {
+	body := `[{"type":"rebalance","status":"notRunning"},` +
+		`{"type":"xdcr","id":"a1b2c3/travel-sample/travel","status":"running","source":"travel-sample",` +
+		`"target":"/remoteClusters/a1b2c3/buckets/travel","filterExpression":"","changesLeft":12,` +
+		`"docsChecked":31591,"docsWritten":31579,"errors":["2024-01-01T00:00:00 timeout"]}]`
+
+	newResp := func() *mgmtResponse {
+		return &mgmtResponse{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+		}
+	}
+
+	mgr := suite.xdcrManager(func(args mock.Arguments) {
+		req := args.Get(1).(mgmtRequest)
+
+		suite.Assert().Equal("/pools/default/tasks", req.Path)
+		suite.Assert().Equal("GET", req.Method)
+		suite.Assert().True(req.IsIdempotent)
+	}, newResp(), nil)
+
+	status, err := mgr.GetReplicationStatus("a1b2c3/travel-sample/travel", nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(&XDCRReplicationStatus{
+		ID:                "a1b2c3/travel-sample/travel",
+		State:             XDCRReplicationStateRunning,
+		SourceBucket:      "travel-sample",
+		RemoteClusterUUID: "a1b2c3",
+		TargetBucket:      "travel",
+		ChangesLeft:       12,
+		DocsChecked:       31591,
+		DocsWritten:       31579,
+		Errors:            []string{"2024-01-01T00:00:00 timeout"},
+	}, status)
+
+	mgr = suite.xdcrManager(nil, newResp(), nil)
+	_, err = mgr.GetReplicationStatus("other/travel-sample/travel", nil)
+	suite.Assert().True(errors.Is(err, ErrXDCRReplicationNotFound))
+}
@@ -0,0 +1,15 @@
+package gocb
+
+type clusterSettingsProvider interface {
+	GetAutoFailoverSettings(opts *GetAutoFailoverSettingsOptions) (*AutoFailoverSettings, error)
+	UpdateAutoFailoverSettings(settings AutoFailoverSettings, opts *UpdateAutoFailoverSettingsOptions) error
+	GetEmailAlertSettings(opts *GetEmailAlertSettingsOptions) (*EmailAlertSettings, error)
+	UpdateEmailAlertSettings(settings EmailAlertSettings, opts *UpdateEmailAlertSettingsOptions) error
+	GetCompactionSettings(opts *GetCompactionSettingsOptions) (*CompactionSettings, error)
+	UpdateCompactionSettings(settings CompactionSettings, opts *UpdateCompactionSettingsOptions) error
+	GetMemoryQuotas(opts *GetMemoryQuotasOptions) (*MemoryQuotas, error)
+	UpdateMemoryQuotas(quotas MemoryQuotas, opts *UpdateMemoryQuotasOptions) error
+	GetAuditSettings(opts *GetAuditSettingsOptions) (*AuditSettings, error)
+	UpdateAuditSettings(settings AuditSettings, opts *UpdateAuditSettingsOptions) error
+	GetAuditEventDescriptors(opts *GetAuditEventDescriptorsOptions) ([]AuditEventDescriptor, error)
+}
@@ -0,0 +1,494 @@
+package gocb
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type clusterSettingsProviderCore struct {
+	provider mgmtProvider
+
+	tracer *tracerWrapper
+}
+
+type clusterSettingsRequestOptions struct {
+	Timeout time.Duration
+	// Deadline is shared by the requests made by a read-modify-write update, it takes precedence over Timeout.
+	Deadline      time.Time
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+	Context       context.Context
+}
+
+type jsonAutoFailoverSettings struct {
+	Enabled                  bool `json:"enabled"`
+	Timeout                  int  `json:"timeout"`
+	MaxCount                 int  `json:"maxCount"`
+	Count                    int  `json:"count"`
+	FailoverOnDataDiskIssues struct {
+		Enabled    bool `json:"enabled"`
+		TimePeriod int  `json:"timePeriod"`
+	} `json:"failoverOnDataDiskIssues"`
+	CanAbortRebalance bool `json:"canAbortRebalance"`
+}
+
+type jsonEmailAlertSettings struct {
+	Enabled     bool     `json:"enabled"`
+	Recipients  []string `json:"recipients"`
+	Sender      string   `json:"sender"`
+	EmailServer struct {
+		User    string `json:"user"`
+		Host    string `json:"host"`
+		Port    int    `json:"port"`
+		Encrypt bool   `json:"encrypt"`
+	} `json:"emailServer"`
+	Alerts []string `json:"alerts"`
+}
+
+// jsonFragmentationThreshold holds a threshold whose fields are either a number or the string "undefined" when the
+// threshold is disabled.
+type jsonFragmentationThreshold struct {
+	Percentage interface{} `json:"percentage"`
+	Size       interface{} `json:"size"`
+}
+
+type jsonCompactionSettings struct {
+	AutoCompactionSettings struct {
+		ParallelDBAndViewCompaction    bool                       `json:"parallelDBAndViewCompaction"`
+		DatabaseFragmentationThreshold jsonFragmentationThreshold `json:"databaseFragmentationThreshold"`
+		ViewFragmentationThreshold     jsonFragmentationThreshold `json:"viewFragmentationThreshold"`
+	} `json:"autoCompactionSettings"`
+	PurgeInterval float64 `json:"purgeInterval"`
+}
+
+type jsonMemoryQuotas struct {
+	MemoryQuota         uint64 `json:"memoryQuota"`
+	IndexMemoryQuota    uint64 `json:"indexMemoryQuota"`
+	FTSMemoryQuota      uint64 `json:"ftsMemoryQuota"`
+	CBASMemoryQuota     uint64 `json:"cbasMemoryQuota"`
+	EventingMemoryQuota uint64 `json:"eventingMemoryQuota"`
+}
+
+type jsonAuditIgnoredUser struct {
+	Name   string `json:"name"`
+	Domain string `json:"domain"`
+}
+
+type jsonAuditSettings struct {
+	AuditdEnabled  bool                   `json:"auditdEnabled"`
+	LogPath        string                 `json:"logPath"`
+	RotateInterval uint64                 `json:"rotateInterval"`
+	RotateSize     uint64                 `json:"rotateSize"`
+	Disabled       []uint32               `json:"disabled"`
+	DisabledUsers  []jsonAuditIgnoredUser `json:"disabledUsers"`
+}
+
+type jsonAuditEventDescriptor struct {
+	ID          uint32 `json:"id"`
+	Name        string `json:"name"`
+	Module      string `json:"module"`
+	Description string `json:"description"`
+}
+
+func fragmentationThresholdValue(value interface{}) uint64 {
+	if number, ok := value.(float64); ok && number > 0 {
+		return uint64(number)
+	}
+
+	return 0
+}
+
+func (sp *clusterSettingsProviderCore) GetAutoFailoverSettings(opts *GetAutoFailoverSettingsOptions) (*AutoFailoverSettings, error) {
+	var data jsonAutoFailoverSettings
+	err := sp.doRequest("get_auto_failover", "GET", "/settings/autoFailover", nil, &data, clusterSettingsRequestOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AutoFailoverSettings{
+		Enabled:                  &data.Enabled,
+		Timeout:                  time.Duration(data.Timeout) * time.Second,
+		MaxCount:                 data.MaxCount,
+		Count:                    data.Count,
+		FailoverOnDataDiskIssues: &data.FailoverOnDataDiskIssues.Enabled,
+		DataDiskIssuesTimePeriod: time.Duration(data.FailoverOnDataDiskIssues.TimePeriod) * time.Second,
+		CanAbortRebalance:        &data.CanAbortRebalance,
+	}, nil
+}
+
+// UpdateAutoFailoverSettings reads the current settings and changes only those which are set, as the server resets
+// any setting which is not posted.
+func (sp *clusterSettingsProviderCore) UpdateAutoFailoverSettings(settings AutoFailoverSettings, opts *UpdateAutoFailoverSettingsOptions) error {
+	reqOpts := clusterSettingsRequestOptions{
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	}
+	if opts.Timeout > 0 {
+		reqOpts.Deadline = time.Now().Add(opts.Timeout)
+	}
+
+	var current jsonAutoFailoverSettings
+	err := sp.doRequest("get_auto_failover", "GET", "/settings/autoFailover", nil, &current, reqOpts)
+	if err != nil {
+		return err
+	}
+
+	if settings.Enabled != nil {
+		current.Enabled = *settings.Enabled
+	}
+	if settings.Timeout > 0 {
+		current.Timeout = int(settings.Timeout / time.Second)
+	}
+	if settings.MaxCount > 0 {
+		current.MaxCount = settings.MaxCount
+	}
+	if settings.FailoverOnDataDiskIssues != nil {
+		current.FailoverOnDataDiskIssues.Enabled = *settings.FailoverOnDataDiskIssues
+	}
+	if settings.DataDiskIssuesTimePeriod > 0 {
+		current.FailoverOnDataDiskIssues.TimePeriod = int(settings.DataDiskIssuesTimePeriod / time.Second)
+	}
+	if settings.CanAbortRebalance != nil {
+		current.CanAbortRebalance = *settings.CanAbortRebalance
+	}
+
+	reqForm := make(url.Values)
+	reqForm.Add("enabled", strconv.FormatBool(current.Enabled))
+	if current.Timeout > 0 {
+		reqForm.Add("timeout", strconv.Itoa(current.Timeout))
+	}
+	if current.MaxCount > 0 {
+		reqForm.Add("maxCount", strconv.Itoa(current.MaxCount))
+	}
+	reqForm.Add("failoverOnDataDiskIssues[enabled]", strconv.FormatBool(current.FailoverOnDataDiskIssues.Enabled))
+	if current.FailoverOnDataDiskIssues.Enabled && current.FailoverOnDataDiskIssues.TimePeriod > 0 {
+		reqForm.Add("failoverOnDataDiskIssues[timePeriod]", strconv.Itoa(current.FailoverOnDataDiskIssues.TimePeriod))
+	}
+	reqForm.Add("canAbortRebalance", strconv.FormatBool(current.CanAbortRebalance))
+
+	return sp.doRequest("update_auto_failover", "POST", "/settings/autoFailover", reqForm, nil, reqOpts)
+}
+
+func (sp *clusterSettingsProviderCore) GetEmailAlertSettings(opts *GetEmailAlertSettingsOptions) (*EmailAlertSettings, error) {
+	var data jsonEmailAlertSettings
+	err := sp.doRequest("get_email_alerts", "GET", "/settings/alerts", nil, &data, clusterSettingsRequestOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmailAlertSettings{
+		Enabled:       &data.Enabled,
+		Recipients:    data.Recipients,
+		Sender:        data.Sender,
+		EmailHost:     data.EmailServer.Host,
+		EmailPort:     data.EmailServer.Port,
+		EmailEncrypt:  &data.EmailServer.Encrypt,
+		EmailUsername: data.EmailServer.User,
+		Alerts:        data.Alerts,
+	}, nil
+}
+
+// UpdateEmailAlertSettings reads the current settings and changes only those which are set, as the server resets
+// any setting which is not posted.
+func (sp *clusterSettingsProviderCore) UpdateEmailAlertSettings(settings EmailAlertSettings, opts *UpdateEmailAlertSettingsOptions) error {
+	reqOpts := clusterSettingsRequestOptions{
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	}
+	if opts.Timeout > 0 {
+		reqOpts.Deadline = time.Now().Add(opts.Timeout)
+	}
+
+	var current jsonEmailAlertSettings
+	err := sp.doRequest("get_email_alerts", "GET", "/settings/alerts", nil, &current, reqOpts)
+	if err != nil {
+		return err
+	}
+
+	if settings.Enabled != nil {
+		current.Enabled = *settings.Enabled
+	}
+	if settings.Recipients != nil {
+		current.Recipients = settings.Recipients
+	}
+	if settings.Sender != "" {
+		current.Sender = settings.Sender
+	}
+	if settings.EmailHost != "" {
+		current.EmailServer.Host = settings.EmailHost
+	}
+	if settings.EmailPort > 0 {
+		current.EmailServer.Port = settings.EmailPort
+	}
+	if settings.EmailEncrypt != nil {
+		current.EmailServer.Encrypt = *settings.EmailEncrypt
+	}
+	if settings.EmailUsername != "" {
+		current.EmailServer.User = settings.EmailUsername
+	}
+	if settings.Alerts != nil {
+		current.Alerts = settings.Alerts
+	}
+
+	if current.Enabled && len(current.Recipients) == 0 {
+		return makeInvalidArgumentsError("recipients cannot be empty when email alerts are enabled")
+	}
+
+	reqForm := make(url.Values)
+	reqForm.Add("enabled", strconv.FormatBool(current.Enabled))
+	reqForm.Add("recipients", strings.Join(current.Recipients, ","))
+	reqForm.Add("sender", current.Sender)
+	reqForm.Add("emailHost", current.EmailServer.Host)
+	if current.EmailServer.Port > 0 {
+		reqForm.Add("emailPort", strconv.Itoa(current.EmailServer.Port))
+	}
+	reqForm.Add("emailEncrypt", strconv.FormatBool(current.EmailServer.Encrypt))
+	reqForm.Add("emailUser", current.EmailServer.User)
+	if settings.EmailPassword != "" {
+		reqForm.Add("emailPass", settings.EmailPassword)
+	}
+	reqForm.Add("alerts", strings.Join(current.Alerts, ","))
+
+	return sp.doRequest("update_email_alerts", "POST", "/settings/alerts", reqForm, nil, reqOpts)
+}
+
+func (sp *clusterSettingsProviderCore) GetCompactionSettings(opts *GetCompactionSettingsOptions) (*CompactionSettings, error) {
+	var data jsonCompactionSettings
+	err := sp.doRequest("get_compaction", "GET", "/settings/autoCompaction", nil, &data, clusterSettingsRequestOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	autoCompaction := data.AutoCompactionSettings
+	return &CompactionSettings{
+		ParallelDBAndViewCompaction:     autoCompaction.ParallelDBAndViewCompaction,
+		DatabaseFragmentationPercentage: int(fragmentationThresholdValue(autoCompaction.DatabaseFragmentationThreshold.Percentage)),
+		// Sizes are set in megabytes but are returned in bytes.
+		DatabaseFragmentationSizeMB: fragmentationThresholdValue(autoCompaction.DatabaseFragmentationThreshold.Size) / (1024 * 1024),
+		ViewFragmentationPercentage: int(fragmentationThresholdValue(autoCompaction.ViewFragmentationThreshold.Percentage)),
+		ViewFragmentationSizeMB:     fragmentationThresholdValue(autoCompaction.ViewFragmentationThreshold.Size) / (1024 * 1024),
+		PurgeInterval:               time.Duration(data.PurgeInterval * float64(24*time.Hour)),
+	}, nil
+}
+
+func (sp *clusterSettingsProviderCore) UpdateCompactionSettings(settings CompactionSettings, opts *UpdateCompactionSettingsOptions) error {
+	reqForm := make(url.Values)
+	reqForm.Add("parallelDBAndViewCompaction", strconv.FormatBool(settings.ParallelDBAndViewCompaction))
+	if settings.DatabaseFragmentationPercentage > 0 {
+		reqForm.Add("databaseFragmentationThreshold[percentage]", strconv.Itoa(settings.DatabaseFragmentationPercentage))
+	}
+	if settings.DatabaseFragmentationSizeMB > 0 {
+		reqForm.Add("databaseFragmentationThreshold[size]", strconv.FormatUint(settings.DatabaseFragmentationSizeMB, 10))
+	}
+	if settings.ViewFragmentationPercentage > 0 {
+		reqForm.Add("viewFragmentationThreshold[percentage]", strconv.Itoa(settings.ViewFragmentationPercentage))
+	}
+	if settings.ViewFragmentationSizeMB > 0 {
+		reqForm.Add("viewFragmentationThreshold[size]", strconv.FormatUint(settings.ViewFragmentationSizeMB, 10))
+	}
+	if settings.PurgeInterval > 0 {
+		reqForm.Add("purgeInterval", strconv.FormatFloat(settings.PurgeInterval.Hours()/24, 'f', -1, 64))
+	}
+
+	return sp.doRequest("update_compaction", "POST", "/controller/setAutoCompaction", reqForm, nil, clusterSettingsRequestOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	})
+}
+
+func (sp *clusterSettingsProviderCore) GetMemoryQuotas(opts *GetMemoryQuotasOptions) (*MemoryQuotas, error) {
+	var data jsonMemoryQuotas
+	err := sp.doRequest("get_memory_quotas", "GET", "/pools/default", nil, &data, clusterSettingsRequestOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &MemoryQuotas{
+		DataMB:      data.MemoryQuota,
+		IndexMB:     data.IndexMemoryQuota,
+		SearchMB:    data.FTSMemoryQuota,
+		AnalyticsMB: data.CBASMemoryQuota,
+		EventingMB:  data.EventingMemoryQuota,
+	}, nil
+}
+
+func (sp *clusterSettingsProviderCore) UpdateMemoryQuotas(quotas MemoryQuotas, opts *UpdateMemoryQuotasOptions) error {
+	reqForm := make(url.Values)
+	addQuota := func(key string, quota uint64) {
+		if quota > 0 {
+			reqForm.Add(key, strconv.FormatUint(quota, 10))
+		}
+	}
+	addQuota("memoryQuota", quotas.DataMB)
+	addQuota("indexMemoryQuota", quotas.IndexMB)
+	addQuota("ftsMemoryQuota", quotas.SearchMB)
+	addQuota("cbasMemoryQuota", quotas.AnalyticsMB)
+	addQuota("eventingMemoryQuota", quotas.EventingMB)
+
+	return sp.doRequest("update_memory_quotas", "POST", "/pools/default", reqForm, nil, clusterSettingsRequestOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	})
+}
+
+func (sp *clusterSettingsProviderCore) GetAuditSettings(opts *GetAuditSettingsOptions) (*AuditSettings, error) {
+	var data jsonAuditSettings
+	err := sp.doRequest("get_audit", "GET", "/settings/audit", nil, &data, clusterSettingsRequestOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ignoredUsers := make([]AuditIgnoredUser, len(data.DisabledUsers))
+	for i, user := range data.DisabledUsers {
+		ignoredUsers[i] = AuditIgnoredUser{
+			Username: user.Name,
+			Domain:   AuthDomain(user.Domain),
+		}
+	}
+
+	return &AuditSettings{
+		Enabled:         data.AuditdEnabled,
+		LogPath:         data.LogPath,
+		RotateInterval:  time.Duration(data.RotateInterval) * time.Second,
+		RotateSizeBytes: data.RotateSize,
+		DisabledEvents:  data.Disabled,
+		IgnoredUsers:    ignoredUsers,
+	}, nil
+}
+
+func (sp *clusterSettingsProviderCore) UpdateAuditSettings(settings AuditSettings, opts *UpdateAuditSettingsOptions) error {
+	reqForm := make(url.Values)
+	reqForm.Add("auditdEnabled", strconv.FormatBool(settings.Enabled))
+	if settings.LogPath != "" {
+		reqForm.Add("logPath", settings.LogPath)
+	}
+	if settings.RotateInterval > 0 {
+		reqForm.Add("rotateInterval", strconv.FormatInt(int64(settings.RotateInterval/time.Second), 10))
+	}
+	if settings.RotateSizeBytes > 0 {
+		reqForm.Add("rotateSize", strconv.FormatUint(settings.RotateSizeBytes, 10))
+	}
+
+	disabledEvents := make([]string, len(settings.DisabledEvents))
+	for i, id := range settings.DisabledEvents {
+		disabledEvents[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	reqForm.Add("disabled", strings.Join(disabledEvents, ","))
+
+	// Ignored users are sent in the form username/domain.
+	ignoredUsers := make([]string, len(settings.IgnoredUsers))
+	for i, user := range settings.IgnoredUsers {
+		ignoredUsers[i] = user.Username + "/" + string(user.Domain)
+	}
+	reqForm.Add("disabledUsers", strings.Join(ignoredUsers, ","))
+
+	return sp.doRequest("update_audit", "POST", "/settings/audit", reqForm, nil, clusterSettingsRequestOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	})
+}
+
+func (sp *clusterSettingsProviderCore) GetAuditEventDescriptors(opts *GetAuditEventDescriptorsOptions) ([]AuditEventDescriptor, error) {
+	var data []jsonAuditEventDescriptor
+	err := sp.doRequest("get_audit_descriptors", "GET", "/settings/audit/descriptors", nil, &data,
+		clusterSettingsRequestOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	descriptors := make([]AuditEventDescriptor, len(data))
+	for i, descriptor := range data {
+		descriptors[i] = AuditEventDescriptor{
+			ID:          descriptor.ID,
+			Name:        descriptor.Name,
+			Module:      descriptor.Module,
+			Description: descriptor.Description,
+		}
+	}
+
+	return descriptors, nil
+}
+
+func (sp *clusterSettingsProviderCore) doRequest(opName, method, path string, reqForm url.Values, target interface{},
+	opts clusterSettingsRequestOptions) error {
+	span := sp.tracer.createSpan(opts.ParentSpan, "manager_settings_"+opName, "management")
+	span.SetAttribute("db.operation", method+" "+path)
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        method,
+		Path:          path,
+		IsIdempotent:  method == "GET",
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		Deadline:      opts.Deadline,
+		parentSpanCtx: span.Context(),
+	}
+	if reqForm != nil {
+		req.Body = []byte(reqForm.Encode())
+		req.ContentType = "application/x-www-form-urlencoded"
+	}
+
+	resp, err := sp.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return makeMgmtBadStatusError("failed to "+strings.ReplaceAll(opName, "_", " "), &req, resp)
+	}
+
+	if target == nil {
+		return nil
+	}
+
+	jsonDec := json.NewDecoder(resp.Body)
+	return jsonDec.Decode(target)
+}
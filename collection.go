@@ -1,5 +1,7 @@
 package gocb
 
+import "time"
+
 // Collection represents a single collection.
 type Collection struct {
 	collectionName string
@@ -11,8 +13,13 @@ type Collection struct {
 	transcoder           Transcoder
 	retryStrategyWrapper *coreRetryStrategyWrapper
 	compressor           *compressor
+	warningHandler       WarningHandler
 
-	useMutationTokens bool
+	useMutationTokens         bool
+	allowSystemScopeMutations bool
+	readOnlyMode              bool
+	slidingExpiry             time.Duration
+	timeSource                func() time.Time
 
 	keyspace keyspace
 
@@ -21,6 +28,9 @@ type Collection struct {
 	getKvProvider         func() (kvProvider, error)
 	getKvBulkProvider     func() (kvBulkProvider, error)
 	getQueryIndexProvider func() (queryIndexProvider, error)
+
+	// uid is shared between copies of the collection, such as those created by WithSlidingExpiry.
+	uid *collectionUID
 }
 
 func newCollection(scope *Scope, collectionName string) *Collection {
@@ -34,8 +44,12 @@ func newCollection(scope *Scope, collectionName string) *Collection {
 		transcoder:           scope.transcoder,
 		retryStrategyWrapper: scope.retryStrategyWrapper,
 		compressor:           scope.compressor,
+		warningHandler:       scope.warningHandler,
 
-		useMutationTokens: scope.useMutationTokens,
+		useMutationTokens:         scope.useMutationTokens,
+		allowSystemScopeMutations: scope.allowSystemScopeMutations,
+		readOnlyMode:              scope.readOnlyMode,
+		timeSource:                scope.timeSource,
 
 		keyspace: keyspace{
 			bucketName:     scope.BucketName(),
@@ -48,6 +62,8 @@ func newCollection(scope *Scope, collectionName string) *Collection {
 		getKvProvider:         scope.getKvProvider,
 		getKvBulkProvider:     scope.getKvBulkProvider,
 		getQueryIndexProvider: scope.getQueryIndexProvider,
+
+		uid: &collectionUID{},
 	}
 }
 
@@ -71,6 +87,17 @@ func (c *Collection) Name() string {
 	return c.collectionName
 }
 
+// WithSlidingExpiry returns a copy of this Collection which refreshes the expiry of documents to the given duration
+// whenever they are fetched using Get, as if GetOptions.RefreshExpiry were set. This is useful for cache style
+// usage, such as session stores. The sliding expiry is not applied to Get operations using Project, WithExpiry or
+// ReadConsistency.
+// UNCOMMITTED: This API may change in the future.
+func (c *Collection) WithSlidingExpiry(expiry time.Duration) *Collection {
+	col := *c
+	col.slidingExpiry = expiry
+	return &col
+}
+
 // QueryIndexes returns a CollectionQueryIndexManager for managing query indexes.
 // UNCOMMITTED: This API may change in the future.
 func (c *Collection) QueryIndexes() *CollectionQueryIndexManager {
@@ -107,7 +134,37 @@ func (c *Collection) bucketName() string {
 }
 
 func (c *Collection) isDefault() bool {
-	return (c.scope == "" || c.scope == "_default") && (c.collectionName == "" || c.collectionName == "_default")
+	return (c.scope == "" || c.scope == DefaultScopeName) &&
+		(c.collectionName == "" || c.collectionName == DefaultCollectionName)
+}
+
+// IsDefault returns whether this is the default collection within the default scope.
+// UNCOMMITTED: This API may change in the future.
+func (c *Collection) IsDefault() bool {
+	return c.isDefault()
+}
+
+// checkMutationAllowed verifies that mutations can be performed against this collection.
+func (c *Collection) checkMutationAllowed() error {
+	if err := c.checkReadOnlyMode(); err != nil {
+		return err
+	}
+
+	if c.scope == SystemScopeName && !c.allowSystemScopeMutations {
+		return makeInvalidArgumentsError("mutations to collections within the _system scope are not allowed " +
+			"unless AllowSystemScopeMutations is enabled")
+	}
+
+	return nil
+}
+
+// checkReadOnlyMode verifies that the cluster has not been configured with ReadOnlyMode.
+func (c *Collection) checkReadOnlyMode() error {
+	if c.readOnlyMode {
+		return makeGenericError(ErrClusterReadOnly, nil)
+	}
+
+	return nil
 }
 
 func (c *Collection) kvController() *providerController[kvProvider] {
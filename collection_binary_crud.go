@@ -2,6 +2,10 @@ package gocb
 
 import (
 	"context"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -38,6 +42,10 @@ func (c *BinaryCollection) Append(id string, val []byte, opts *AppendOptions) (m
 			opts = &AppendOptions{}
 		}
 
+		if err := c.collection.checkMutationAllowed(); err != nil {
+			return nil, err
+		}
+
 		return agent.Append(c.collection, id, val, opts)
 	})
 }
@@ -70,10 +78,32 @@ func (c *BinaryCollection) Prepend(id string, val []byte, opts *PrependOptions)
 			opts = &PrependOptions{}
 		}
 
+		if err := c.collection.checkMutationAllowed(); err != nil {
+			return nil, err
+		}
+
 		return agent.Prepend(c.collection, id, val, opts)
 	})
 }
 
+// CounterOverflowBehavior specifies how a counter behaves when an Increment or Decrement would move it beyond the
+// range of a uint64.
+// UNCOMMITTED: This API may change in the future.
+type CounterOverflowBehavior uint
+
+const (
+	// CounterOverflowDefault uses the behaviour of the server, Increment wraps around to zero and Decrement saturates
+	// at zero.
+	CounterOverflowDefault CounterOverflowBehavior = iota
+
+	// CounterOverflowSaturate indicates that the counter should stop at the minimum or maximum value of a uint64.
+	CounterOverflowSaturate
+
+	// CounterOverflowWrap indicates that the counter should wrap around when it passes the minimum or maximum value of
+	// a uint64.
+	CounterOverflowWrap
+)
+
 // IncrementOptions are the options available to the Increment operation.
 type IncrementOptions struct {
 	Timeout time.Duration
@@ -86,10 +116,14 @@ type IncrementOptions struct {
 	// Delta is the value to use for incrementing/decrementing if Initial is not present.
 	Delta           uint64
 	DurabilityLevel DurabilityLevel
-	PersistTo       uint
-	ReplicateTo     uint
-	RetryStrategy   RetryStrategy
-	ParentSpan      RequestSpan
+	// Overflow controls what happens when the counter would move beyond the range of a uint64. Behaviours which are
+	// not natively supported by the server are performed using a CAS loop of Get and Replace.
+	// UNCOMMITTED: This API may change in the future.
+	Overflow      CounterOverflowBehavior
+	PersistTo     uint
+	ReplicateTo   uint
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
 
 	// Deprecated: Cas is not supported by the server for Increment, and is no longer used.
 	Cas Cas
@@ -113,10 +147,33 @@ func (c *BinaryCollection) Increment(id string, opts *IncrementOptions) (countOu
 		if opts == nil {
 			opts = &IncrementOptions{}
 		}
+
+		if err := c.collection.checkMutationAllowed(); err != nil {
+			return nil, err
+		}
+
 		if opts.Cas > 0 {
+			warnDeprecated(c.collection.warningHandler, "IncrementOptions.Cas", "Cas is not supported by the server for Increment")
 			return nil, makeInvalidArgumentsError("cas is not supported for the Increment operation")
 		}
 
+		if opts.Overflow == CounterOverflowSaturate {
+			return c.counterWithOverflow(agent, "increment", id, counterOverflowOptions{
+				delta:           opts.Delta,
+				increment:       true,
+				initial:         opts.Initial,
+				expiry:          opts.Expiry,
+				durabilityLevel: opts.DurabilityLevel,
+				persistTo:       opts.PersistTo,
+				replicateTo:     opts.ReplicateTo,
+				timeout:         opts.Timeout,
+				retryStrategy:   opts.RetryStrategy,
+				parentSpan:      opts.ParentSpan,
+				ctx:             opts.Context,
+				user:            opts.Internal.User,
+			})
+		}
+
 		return agent.Increment(c.collection, id, opts)
 	})
 }
@@ -133,10 +190,14 @@ type DecrementOptions struct {
 	// Delta is the value to use for incrementing/decrementing if Initial is not present.
 	Delta           uint64
 	DurabilityLevel DurabilityLevel
-	PersistTo       uint
-	ReplicateTo     uint
-	RetryStrategy   RetryStrategy
-	ParentSpan      RequestSpan
+	// Overflow controls what happens when the counter would move beyond the range of a uint64. Behaviours which are
+	// not natively supported by the server are performed using a CAS loop of Get and Replace.
+	// UNCOMMITTED: This API may change in the future.
+	Overflow      CounterOverflowBehavior
+	PersistTo     uint
+	ReplicateTo   uint
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
 
 	// Deprecated: Cas is not supported by the server for Decrement, and is no longer used.
 	Cas Cas
@@ -160,10 +221,174 @@ func (c *BinaryCollection) Decrement(id string, opts *DecrementOptions) (countOu
 		if opts == nil {
 			opts = &DecrementOptions{}
 		}
+
+		if err := c.collection.checkMutationAllowed(); err != nil {
+			return nil, err
+		}
+
 		if opts.Cas > 0 {
+			warnDeprecated(c.collection.warningHandler, "DecrementOptions.Cas", "Cas is not supported by the server for Decrement")
 			return nil, makeInvalidArgumentsError("cas is not supported for the Decrement operation")
 		}
 
+		if opts.Overflow == CounterOverflowWrap {
+			return c.counterWithOverflow(agent, "decrement", id, counterOverflowOptions{
+				delta:           opts.Delta,
+				increment:       false,
+				initial:         opts.Initial,
+				expiry:          opts.Expiry,
+				durabilityLevel: opts.DurabilityLevel,
+				persistTo:       opts.PersistTo,
+				replicateTo:     opts.ReplicateTo,
+				timeout:         opts.Timeout,
+				retryStrategy:   opts.RetryStrategy,
+				parentSpan:      opts.ParentSpan,
+				ctx:             opts.Context,
+				user:            opts.Internal.User,
+			})
+		}
+
 		return agent.Decrement(c.collection, id, opts)
 	})
 }
+
+// counterTranscoder passes counter values through as is, regardless of the flags on the document. Values are
+// written with the same flags that the server uses when it creates a counter document.
+type counterTranscoder struct{}
+
+func (t counterTranscoder) Decode(bytes []byte, flags uint32, out interface{}) error {
+	typedOut, ok := out.(*[]byte)
+	if !ok {
+		return errors.New("counter values can only be decoded into a byte array")
+	}
+	*typedOut = bytes
+	return nil
+}
+
+func (t counterTranscoder) Encode(value interface{}) ([]byte, uint32, error) {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil, 0, errors.New("counter values can only be encoded from a byte array")
+	}
+	return bytes, 0, nil
+}
+
+type counterOverflowOptions struct {
+	delta           uint64
+	increment       bool
+	initial         int64
+	expiry          time.Duration
+	durabilityLevel DurabilityLevel
+	persistTo       uint
+	replicateTo     uint
+	timeout         time.Duration
+	retryStrategy   RetryStrategy
+	parentSpan      RequestSpan
+	ctx             context.Context
+	user            string
+}
+
+// counterWithOverflow performs a counter operation using a CAS loop so that overflow behaviours which the server
+// does not natively support can be applied.
+func (c *BinaryCollection) counterWithOverflow(agent kvProvider, opName, id string,
+	opts counterOverflowOptions) (*CounterResult, error) {
+	span := agent.StartKvOpTrace(c.collection, opName, opts.parentSpan, false)
+	defer span.End()
+
+	timeout := opts.timeout
+	if timeout == 0 {
+		timeout = c.collection.timeoutsConfig.KVTimeout
+		if opts.durabilityLevel > DurabilityLevelNone || opts.persistTo > 0 || opts.replicateTo > 0 {
+			timeout = c.collection.timeoutsConfig.KVDurableTimeout
+		}
+	}
+
+	ctx := opts.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	transcoder := counterTranscoder{}
+	for {
+		getOpts := &GetOptions{
+			Transcoder:    transcoder,
+			RetryStrategy: opts.retryStrategy,
+			ParentSpan:    span,
+			Context:       ctx,
+		}
+		getOpts.Internal.User = opts.user
+
+		doc, err := agent.Get(c.collection, id, getOpts)
+		if errors.Is(err, ErrDocumentNotFound) && opts.initial >= 0 {
+			value := uint64(opts.initial)
+			insertOpts := &InsertOptions{
+				Expiry:          opts.expiry,
+				PersistTo:       opts.persistTo,
+				ReplicateTo:     opts.replicateTo,
+				DurabilityLevel: opts.durabilityLevel,
+				Transcoder:      transcoder,
+				RetryStrategy:   opts.retryStrategy,
+				ParentSpan:      span,
+				Context:         ctx,
+			}
+			insertOpts.Internal.User = opts.user
+
+			res, err := agent.Insert(c.collection, id, []byte(strconv.FormatUint(value, 10)), insertOpts)
+			if errors.Is(err, ErrDocumentExists) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			return &CounterResult{MutationResult: *res, content: value}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var raw []byte
+		if err := doc.Content(&raw); err != nil {
+			return nil, err
+		}
+		current, err := strconv.ParseUint(strings.TrimSpace(string(raw)), 10, 64)
+		if err != nil {
+			return nil, makeInvalidArgumentsError("document does not contain a valid counter value")
+		}
+
+		var value uint64
+		if opts.increment {
+			value = current + opts.delta
+			if value < current {
+				value = math.MaxUint64
+			}
+		} else {
+			value = current - opts.delta
+		}
+
+		replaceOpts := &ReplaceOptions{
+			Cas:             doc.Cas(),
+			PersistTo:       opts.persistTo,
+			ReplicateTo:     opts.replicateTo,
+			DurabilityLevel: opts.durabilityLevel,
+			Transcoder:      transcoder,
+			RetryStrategy:   opts.retryStrategy,
+			ParentSpan:      span,
+			PreserveExpiry:  true,
+			Context:         ctx,
+		}
+		replaceOpts.Internal.User = opts.user
+
+		res, err := agent.Replace(c.collection, id, []byte(strconv.FormatUint(value, 10)), replaceOpts)
+		if errors.Is(err, ErrCasMismatch) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return &CounterResult{MutationResult: *res, content: value}, nil
+	}
+}
@@ -1,9 +1,13 @@
 package gocb
 
 import (
+	"math"
+	"strconv"
 	"time"
 
+	"github.com/couchbase/gocbcore/v10"
 	"github.com/couchbase/gocbcore/v10/memd"
+	"github.com/stretchr/testify/mock"
 )
 
 func (suite *IntegrationTestSuite) TestBinaryAppend() {
@@ -280,3 +284,140 @@ func (suite *IntegrationTestSuite) TestBinaryDecrement() {
 	suite.AssertKVMetrics(meterNameCBOperations, "decrement", 3, false)
 	suite.AssertKVMetrics(meterNameCBOperations, "get", 1, false)
 }
+
+func (suite *UnitTestSuite) TestIncrementOverflowSaturate() {
+	pendingOp := new(mockPendingOp)
+	pendingOp.AssertNotCalled(suite.T(), "Cancel", mock.AnythingOfType("error"))
+
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("Get", mock.AnythingOfType("gocbcore.GetOptions"), mock.AnythingOfType("gocbcore.GetCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(1).(gocbcore.GetCallback)
+			cb(&gocbcore.GetResult{
+				Value: []byte("18446744073709551610"),
+				Cas:   gocbcore.Cas(123),
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	var replaces int
+	provider.
+		On("Replace", mock.AnythingOfType("gocbcore.ReplaceOptions"), mock.AnythingOfType("gocbcore.StoreCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.ReplaceOptions)
+			cb := args.Get(1).(gocbcore.StoreCallback)
+
+			suite.Assert().Equal(gocbcore.Cas(123), opts.Cas)
+			suite.Assert().True(opts.PreserveExpiry)
+			suite.Assert().Equal(uint32(0), opts.Flags)
+			suite.Assert().Equal([]byte("18446744073709551615"), opts.Value)
+
+			replaces++
+			if replaces == 1 {
+				cb(nil, gocbcore.ErrCasMismatch)
+				return
+			}
+			cb(&gocbcore.StoreResult{Cas: gocbcore.Cas(456)}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	agent := suite.kvProviderCore(provider, nil)
+	col := suite.collection("mock", "", "", agent)
+
+	res, err := col.Binary().Increment("someid", &IncrementOptions{
+		Delta:    10,
+		Overflow: CounterOverflowSaturate,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(uint64(math.MaxUint64), res.Content())
+	suite.Assert().Equal(Cas(456), res.Cas())
+	suite.Assert().Equal(2, replaces)
+}
+
+func (suite *UnitTestSuite) TestDecrementOverflowWrap() {
+	pendingOp := new(mockPendingOp)
+	pendingOp.AssertNotCalled(suite.T(), "Cancel", mock.AnythingOfType("error"))
+
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("Get", mock.AnythingOfType("gocbcore.GetOptions"), mock.AnythingOfType("gocbcore.GetCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(1).(gocbcore.GetCallback)
+			cb(&gocbcore.GetResult{
+				Value: []byte("1"),
+				Cas:   gocbcore.Cas(123),
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+	provider.
+		On("Replace", mock.AnythingOfType("gocbcore.ReplaceOptions"), mock.AnythingOfType("gocbcore.StoreCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.ReplaceOptions)
+			cb := args.Get(1).(gocbcore.StoreCallback)
+
+			suite.Assert().Equal([]byte("18446744073709551615"), opts.Value)
+			cb(&gocbcore.StoreResult{Cas: gocbcore.Cas(456)}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	agent := suite.kvProviderCore(provider, nil)
+	col := suite.collection("mock", "", "", agent)
+
+	res, err := col.Binary().Decrement("someid", &DecrementOptions{
+		Delta:    2,
+		Overflow: CounterOverflowWrap,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(uint64(math.MaxUint64), res.Content())
+	provider.AssertNotCalled(suite.T(), "Decrement", mock.Anything, mock.Anything)
+}
+
+func (suite *UnitTestSuite) TestSignedCounterAdd() {
+	pendingOp := new(mockPendingOp)
+	pendingOp.AssertNotCalled(suite.T(), "Cancel", mock.AnythingOfType("error"))
+
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("Decrement", mock.AnythingOfType("gocbcore.CounterOptions"), mock.AnythingOfType("gocbcore.CounterCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.CounterOptions)
+			cb := args.Get(1).(gocbcore.CounterCallback)
+
+			suite.Assert().Equal(uint64(5), opts.Delta)
+			suite.Assert().Equal(uint64(signedCounterOffset-5), opts.Initial)
+
+			cb(&gocbcore.CounterResult{
+				Value: uint64(signedCounterOffset - 5),
+				Cas:   gocbcore.Cas(123),
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+	provider.
+		On("Get", mock.AnythingOfType("gocbcore.GetOptions"), mock.AnythingOfType("gocbcore.GetCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(1).(gocbcore.GetCallback)
+			cb(&gocbcore.GetResult{
+				Value: []byte(strconv.FormatInt(signedCounterOffset-5, 10)),
+				Cas:   gocbcore.Cas(123),
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	agent := suite.kvProviderCore(provider, nil)
+	col := suite.collection("mock", "", "", agent)
+
+	counter := col.SignedCounter("someid")
+	value, err := counter.Add(-5)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(int64(-5), value)
+
+	value, err = counter.Value()
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(int64(-5), value)
+
+	_, err = counter.Add(signedCounterOffset)
+	suite.Require().ErrorIs(err, ErrInvalidArgument)
+}
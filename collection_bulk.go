@@ -3,15 +3,24 @@ package gocb
 
 import (
 	"context"
+	"sync"
 	"time"
 )
 
 type bulkOp struct {
 	finishFn func()
+	doneFn   func()
 }
 
 func (op *bulkOp) finish() {
 	op.finishFn()
+	if op.doneFn != nil {
+		op.doneFn()
+	}
+}
+
+func (op *bulkOp) bulk() *bulkOp {
+	return op
 }
 
 // BulkOp represents a single operation that can be submitted (within a list of more operations) to .Do()
@@ -21,6 +30,7 @@ func (op *bulkOp) finish() {
 type BulkOp interface {
 	isBulkOp()
 	finish()
+	bulk() *bulkOp
 }
 
 // BulkOpOptions are the set of options available when performing BulkOps using Do.
@@ -30,6 +40,28 @@ type BulkOpOptions struct {
 	RetryStrategy RetryStrategy
 	ParentSpan    RequestSpan
 
+	// PersistTo and ReplicateTo apply observe based durability to every mutation within the batch. Each successful
+	// mutation is polled until it has been persisted and replicated to the requested number of nodes, any failure
+	// to do so is reported via the Err field of the relevant op.
+	PersistTo   uint
+	ReplicateTo uint
+
+	// Checkpoint is invoked every CheckpointInterval successful ops with a cursor, all ops before the cursor have
+	// completed successfully. The cursor is an index into the full list of ops passed to Do and can be passed as
+	// ResumeFrom to resume processing after a restart. Checkpoint is also invoked once all ops have completed if the
+	// cursor has moved since it was last invoked.
+	// UNCOMMITTED: This API may change in the future.
+	Checkpoint func(cursor int)
+
+	// CheckpointInterval is the number of successful ops between invocations of Checkpoint, must be set if
+	// Checkpoint is set.
+	// UNCOMMITTED: This API may change in the future.
+	CheckpointInterval uint
+
+	// ResumeFrom skips all ops before this index, this is typically a cursor previously provided to Checkpoint.
+	// UNCOMMITTED: This API may change in the future.
+	ResumeFrom int
+
 	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
 	// also applies to global level timeouts.
 	// UNCOMMITTED: This API may change in the future.
@@ -44,8 +76,232 @@ func (c *Collection) Do(ops []BulkOp, opts *BulkOpOptions) error {
 			opts = &BulkOpOptions{}
 		}
 
-		return agent.Do(c, ops, opts)
+		for _, op := range ops {
+			if _, isGet := op.(*GetOp); isGet {
+				continue
+			}
+
+			if err := c.checkMutationAllowed(); err != nil {
+				return err
+			}
+			break
+		}
+
+		if opts.ResumeFrom < 0 || opts.ResumeFrom > len(ops) {
+			return makeInvalidArgumentsError("ResumeFrom must be within the range of ops")
+		}
+		if opts.Checkpoint == nil {
+			return agent.Do(c, ops[opts.ResumeFrom:], opts)
+		}
+		if opts.CheckpointInterval == 0 {
+			return makeInvalidArgumentsError("CheckpointInterval must be set when using Checkpoint")
+		}
+
+		tracker := newBulkCheckpointTracker(ops, opts)
+		defer tracker.detach()
+
+		err := agent.Do(c, ops[opts.ResumeFrom:], opts)
+		tracker.flush()
+
+		return err
+	})
+}
+
+// bulkCheckpointTracker tracks the completion of ops within a Do call and reports the cursor to the user,
+// the cursor is the index of the first op which has not yet completed successfully.
+type bulkCheckpointTracker struct {
+	lock       sync.Mutex
+	ops        []BulkOp
+	succeeded  []bool
+	cursor     int
+	reported   int
+	successes  uint
+	interval   uint
+	checkpoint func(cursor int)
+}
+
+func newBulkCheckpointTracker(ops []BulkOp, opts *BulkOpOptions) *bulkCheckpointTracker {
+	t := &bulkCheckpointTracker{
+		ops:        ops,
+		succeeded:  make([]bool, len(ops)),
+		cursor:     opts.ResumeFrom,
+		reported:   opts.ResumeFrom,
+		interval:   opts.CheckpointInterval,
+		checkpoint: opts.Checkpoint,
+	}
+
+	for idx := opts.ResumeFrom; idx < len(ops); idx++ {
+		idx := idx
+		ops[idx].bulk().doneFn = func() {
+			t.markDone(idx)
+		}
+	}
+
+	return t
+}
+
+func (t *bulkCheckpointTracker) markDone(idx int) {
+	if bulkOpError(t.ops[idx]) != nil {
+		return
+	}
+
+	// The lock is held whilst invoking the callback so that the cursor is always reported in order.
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.succeeded[idx] = true
+	t.successes++
+	for t.cursor < len(t.ops) && t.succeeded[t.cursor] {
+		t.cursor++
+	}
+
+	if t.successes%t.interval == 0 {
+		t.report()
+	}
+}
+
+func (t *bulkCheckpointTracker) flush() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.report()
+}
+
+func (t *bulkCheckpointTracker) report() {
+	if t.cursor == t.reported {
+		return
+	}
+
+	t.reported = t.cursor
+	t.checkpoint(t.cursor)
+}
+
+func (t *bulkCheckpointTracker) detach() {
+	for _, op := range t.ops {
+		op.bulk().doneFn = nil
+	}
+}
+
+// GetAndTouchMultiOptions are the options available to the GetAndTouchMulti operation.
+// UNCOMMITTED: This API may change in the future.
+type GetAndTouchMultiOptions struct {
+	Timeout       time.Duration
+	Transcoder    Transcoder
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// GetAndTouchMultiResult is the outcome of a single document within a GetAndTouchMulti operation.
+// UNCOMMITTED: This API may change in the future.
+type GetAndTouchMultiResult struct {
+	ID     string
+	Result *GetResult
+	Err    error
+}
+
+// GetAndTouchMulti retrieves multiple documents in parallel and simultaneously updates their expiry times. The
+// results are returned in the same order as the ids, any per document failure is reported via the Err field of
+// the relevant result.
+// UNCOMMITTED: This API may change in the future.
+func (c *Collection) GetAndTouchMulti(ids []string, expiry time.Duration, opts *GetAndTouchMultiOptions) ([]GetAndTouchMultiResult, error) {
+	if opts == nil {
+		opts = &GetAndTouchMultiOptions{}
+	}
+
+	ops := make([]BulkOp, len(ids))
+	for i, id := range ids {
+		ops[i] = &GetAndTouchOp{
+			ID:     id,
+			Expiry: expiry,
+		}
+	}
+
+	err := c.Do(ops, &BulkOpOptions{
+		Timeout:       opts.Timeout,
+		Transcoder:    opts.Transcoder,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]GetAndTouchMultiResult, len(ops))
+	for i, op := range ops {
+		item := op.(*GetAndTouchOp)
+		results[i] = GetAndTouchMultiResult{
+			ID:     item.ID,
+			Result: item.Result,
+			Err:    item.Err,
+		}
+	}
+
+	return results, nil
+}
+
+// TouchMultiOptions are the options available to the TouchMulti operation.
+// UNCOMMITTED: This API may change in the future.
+type TouchMultiOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// TouchMultiResult is the outcome of a single document within a TouchMulti operation.
+// UNCOMMITTED: This API may change in the future.
+type TouchMultiResult struct {
+	ID     string
+	Result *MutationResult
+	Err    error
+}
+
+// TouchMulti updates the expiry times of multiple documents in parallel, such as when refreshing the TTLs of many
+// sessions at once. The results are returned in the same order as the ids, any per document failure is reported via
+// the Err field of the relevant result.
+// UNCOMMITTED: This API may change in the future.
+func (c *Collection) TouchMulti(ids []string, expiry time.Duration, opts *TouchMultiOptions) ([]TouchMultiResult, error) {
+	if opts == nil {
+		opts = &TouchMultiOptions{}
+	}
+
+	ops := make([]BulkOp, len(ids))
+	for i, id := range ids {
+		ops[i] = &TouchOp{
+			ID:     id,
+			Expiry: expiry,
+		}
+	}
+
+	err := c.Do(ops, &BulkOpOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]TouchMultiResult, len(ops))
+	for i, op := range ops {
+		item := op.(*TouchOp)
+		results[i] = TouchMultiResult{
+			ID:     item.ID,
+			Result: item.Result,
+			Err:    item.Err,
+		}
+	}
+
+	return results, nil
 }
 
 // GetOp represents a type of `BulkOp` used for Get operations. See BulkOp.
@@ -200,3 +456,51 @@ type DecrementOp struct {
 }
 
 func (item *DecrementOp) isBulkOp() {}
+
+// bulkOpError returns the error, if any, that a BulkOp completed with.
+func bulkOpError(op BulkOp) error {
+	switch i := op.(type) {
+	case *GetOp:
+		return i.Err
+	case *GetAndTouchOp:
+		return i.Err
+	case *TouchOp:
+		return i.Err
+	}
+
+	_, _, errOut, ok := bulkOpMutation(op)
+	if !ok {
+		return nil
+	}
+	return *errOut
+}
+
+// bulkOpMutation returns the details of a BulkOp if it is a mutation, ok is false for any other type of op.
+func bulkOpMutation(op BulkOp) (id string, res *MutationResult, errOut *error, ok bool) {
+	switch i := op.(type) {
+	case *RemoveOp:
+		return i.ID, i.Result, &i.Err, true
+	case *UpsertOp:
+		return i.ID, i.Result, &i.Err, true
+	case *InsertOp:
+		return i.ID, i.Result, &i.Err, true
+	case *ReplaceOp:
+		return i.ID, i.Result, &i.Err, true
+	case *AppendOp:
+		return i.ID, i.Result, &i.Err, true
+	case *PrependOp:
+		return i.ID, i.Result, &i.Err, true
+	case *IncrementOp:
+		if i.Result == nil {
+			return i.ID, nil, &i.Err, true
+		}
+		return i.ID, &i.Result.MutationResult, &i.Err, true
+	case *DecrementOp:
+		if i.Result == nil {
+			return i.ID, nil, &i.Err, true
+		}
+		return i.ID, &i.Result.MutationResult, &i.Err, true
+	}
+
+	return "", nil, nil, false
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/couchbase/gocbcore/v10"
 	"github.com/couchbase/gocbcore/v10/memd"
+	"github.com/stretchr/testify/mock"
 )
 
 func (suite *IntegrationTestSuite) TestUpsertGetBulk() {
@@ -282,3 +284,285 @@ func (suite *IntegrationTestSuite) TestRemoveOperationBulk() {
 	suite.AssertKVMetrics(meterNameCBOperations, "upsert", 20, false)
 	suite.AssertKVMetrics(meterNameCBOperations, "remove", 20, false)
 }
+
+func (suite *UnitTestSuite) TestBulkUpsertPersistTo() {
+	pendingOp := new(mockPendingOp)
+	pendingOp.AssertNotCalled(suite.T(), "Cancel", mock.AnythingOfType("error"))
+
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("Set", mock.AnythingOfType("gocbcore.SetOptions"), mock.AnythingOfType("gocbcore.StoreCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(1).(gocbcore.StoreCallback)
+			cb(&gocbcore.StoreResult{
+				Cas:           gocbcore.Cas(123),
+				MutationToken: gocbcore.MutationToken{VbID: 12, VbUUID: 1234, SeqNo: 10},
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+	provider.
+		On("ObserveVb", mock.AnythingOfType("gocbcore.ObserveVbOptions"), mock.AnythingOfType("gocbcore.ObserveVbCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.ObserveVbOptions)
+			cb := args.Get(1).(gocbcore.ObserveVbCallback)
+
+			suite.Assert().Equal(uint16(12), opts.VbID)
+			cb(&gocbcore.ObserveVbResult{
+				VbID:         opts.VbID,
+				VbUUID:       opts.VbUUID,
+				CurrentSeqNo: 10,
+				PersistSeqNo: 10,
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	col := suite.collection("mock", "", "", nil)
+	col.useMutationTokens = true
+	col.getKvBulkProvider = func() (kvBulkProvider, error) {
+		return &kvBulkProviderCore{
+			agent: provider,
+			snapshotProvider: &mockConfigSnapshotProvider{
+				snapshot: &mockConfigSnapshot{},
+			},
+			tracer: newTracerWrapper(&NoopTracer{}),
+			meter:  newMeterWrapper(&NoopMeter{}),
+		}, nil
+	}
+
+	ops := []BulkOp{
+		&UpsertOp{ID: "key1", Value: "value1"},
+		&UpsertOp{ID: "key2", Value: "value2"},
+	}
+	err := col.Do(ops, &BulkOpOptions{
+		PersistTo: 1,
+		Timeout:   time.Second,
+	})
+	suite.Require().Nil(err, err)
+
+	for _, op := range ops {
+		upsertOp := op.(*UpsertOp)
+		suite.Require().Nil(upsertOp.Err, upsertOp.Err)
+		suite.Assert().Equal(Cas(123), upsertOp.Result.Cas())
+	}
+	provider.AssertNumberOfCalls(suite.T(), "ObserveVb", 2)
+}
+
+func (suite *UnitTestSuite) TestBulkUpsertPersistToDurabilityImpossible() {
+	pendingOp := new(mockPendingOp)
+	pendingOp.AssertNotCalled(suite.T(), "Cancel", mock.AnythingOfType("error"))
+
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("Set", mock.AnythingOfType("gocbcore.SetOptions"), mock.AnythingOfType("gocbcore.StoreCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(1).(gocbcore.StoreCallback)
+			cb(&gocbcore.StoreResult{
+				Cas:           gocbcore.Cas(123),
+				MutationToken: gocbcore.MutationToken{VbID: 12, VbUUID: 1234, SeqNo: 10},
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	col := suite.collection("mock", "", "", nil)
+	col.useMutationTokens = true
+	col.getKvBulkProvider = func() (kvBulkProvider, error) {
+		return &kvBulkProviderCore{
+			agent: provider,
+			snapshotProvider: &mockConfigSnapshotProvider{
+				snapshot: &mockConfigSnapshot{},
+			},
+			tracer: newTracerWrapper(&NoopTracer{}),
+			meter:  newMeterWrapper(&NoopMeter{}),
+		}, nil
+	}
+
+	op := &UpsertOp{ID: "key1", Value: "value1"}
+	err := col.Do([]BulkOp{op}, &BulkOpOptions{
+		ReplicateTo: 1,
+		Timeout:     time.Second,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().ErrorIs(op.Err, ErrDurabilityImpossible)
+}
+
+func (suite *UnitTestSuite) TestBulkCheckpointAndResume() {
+	pendingOp := new(mockPendingOp)
+	pendingOp.AssertNotCalled(suite.T(), "Cancel", mock.AnythingOfType("error"))
+
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("Set", mock.AnythingOfType("gocbcore.SetOptions"), mock.AnythingOfType("gocbcore.StoreCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.SetOptions)
+			cb := args.Get(1).(gocbcore.StoreCallback)
+
+			if string(opts.Key) == "2" {
+				cb(nil, gocbcore.ErrTemporaryFailure)
+				return
+			}
+			cb(&gocbcore.StoreResult{Cas: gocbcore.Cas(123)}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	col := suite.collection("mock", "", "", nil)
+	col.getKvBulkProvider = func() (kvBulkProvider, error) {
+		return &kvBulkProviderCore{
+			agent:  provider,
+			tracer: newTracerWrapper(&NoopTracer{}),
+			meter:  newMeterWrapper(&NoopMeter{}),
+		}, nil
+	}
+
+	makeOps := func() []BulkOp {
+		var ops []BulkOp
+		for i := 0; i < 5; i++ {
+			ops = append(ops, &UpsertOp{ID: fmt.Sprintf("%d", i), Value: "value"})
+		}
+		return ops
+	}
+
+	var cursors []int
+	err := col.Do(makeOps(), &BulkOpOptions{
+		Checkpoint: func(cursor int) {
+			cursors = append(cursors, cursor)
+		},
+		CheckpointInterval: 1,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Require().NotEmpty(cursors)
+	suite.Assert().IsIncreasing(cursors)
+	suite.Assert().Equal(2, cursors[len(cursors)-1])
+	provider.AssertNumberOfCalls(suite.T(), "Set", 5)
+
+	cursors = nil
+	ops := makeOps()
+	err = col.Do(ops, &BulkOpOptions{
+		Checkpoint: func(cursor int) {
+			cursors = append(cursors, cursor)
+		},
+		CheckpointInterval: 10,
+		ResumeFrom:         3,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal([]int{5}, cursors)
+	suite.Assert().Nil(ops[0].(*UpsertOp).Result)
+	suite.Assert().NotNil(ops[4].(*UpsertOp).Result)
+	provider.AssertNumberOfCalls(suite.T(), "Set", 7)
+}
+
+func (suite *UnitTestSuite) TestBulkCheckpointRequiresInterval() {
+	col := suite.collection("mock", "", "", nil)
+	col.getKvBulkProvider = func() (kvBulkProvider, error) {
+		return &kvBulkProviderCore{}, nil
+	}
+
+	err := col.Do([]BulkOp{&UpsertOp{ID: "key"}}, &BulkOpOptions{
+		Checkpoint: func(cursor int) {},
+	})
+	suite.Require().ErrorIs(err, ErrInvalidArgument)
+
+	err = col.Do([]BulkOp{&UpsertOp{ID: "key"}}, &BulkOpOptions{
+		ResumeFrom: 2,
+	})
+	suite.Require().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestGetAndTouchMulti() {
+	pendingOp := new(mockPendingOp)
+	pendingOp.AssertNotCalled(suite.T(), "Cancel", mock.AnythingOfType("error"))
+
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("GetAndTouch", mock.AnythingOfType("gocbcore.GetAndTouchOptions"), mock.AnythingOfType("gocbcore.GetAndTouchCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.GetAndTouchOptions)
+			cb := args.Get(1).(gocbcore.GetAndTouchCallback)
+
+			suite.Assert().Equal(uint32(10), opts.Expiry)
+			if string(opts.Key) == "missing" {
+				cb(nil, gocbcore.ErrDocumentNotFound)
+				return
+			}
+			cb(&gocbcore.GetAndTouchResult{
+				Value: []byte(`"value"`),
+				Cas:   gocbcore.Cas(123),
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	col := suite.collection("mock", "", "", nil)
+	col.getKvBulkProvider = func() (kvBulkProvider, error) {
+		return &kvBulkProviderCore{
+			agent:  provider,
+			tracer: newTracerWrapper(&NoopTracer{}),
+			meter:  newMeterWrapper(&NoopMeter{}),
+		}, nil
+	}
+
+	results, err := col.GetAndTouchMulti([]string{"key1", "missing", "key2"}, 10*time.Second, nil)
+	suite.Require().Nil(err, err)
+	suite.Require().Len(results, 3)
+
+	suite.Assert().Equal("key1", results[0].ID)
+	suite.Require().Nil(results[0].Err, results[0].Err)
+	var content string
+	suite.Require().Nil(results[0].Result.Content(&content))
+	suite.Assert().Equal("value", content)
+
+	suite.Assert().Equal("missing", results[1].ID)
+	suite.Assert().ErrorIs(results[1].Err, ErrDocumentNotFound)
+	suite.Assert().Nil(results[1].Result)
+
+	suite.Assert().Equal("key2", results[2].ID)
+	suite.Assert().Nil(results[2].Err, results[2].Err)
+}
+
+func (suite *UnitTestSuite) TestTouchMulti() {
+	pendingOp := new(mockPendingOp)
+	pendingOp.AssertNotCalled(suite.T(), "Cancel", mock.AnythingOfType("error"))
+
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("Touch", mock.AnythingOfType("gocbcore.TouchOptions"), mock.AnythingOfType("gocbcore.TouchCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.TouchOptions)
+			cb := args.Get(1).(gocbcore.TouchCallback)
+
+			suite.Assert().Equal(uint32(1800), opts.Expiry)
+			if string(opts.Key) == "missing" {
+				cb(nil, gocbcore.ErrDocumentNotFound)
+				return
+			}
+			cb(&gocbcore.TouchResult{
+				Cas: gocbcore.Cas(123),
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	col := suite.collection("mock", "", "", nil)
+	col.getKvBulkProvider = func() (kvBulkProvider, error) {
+		return &kvBulkProviderCore{
+			agent:  provider,
+			tracer: newTracerWrapper(&NoopTracer{}),
+			meter:  newMeterWrapper(&NoopMeter{}),
+		}, nil
+	}
+
+	results, err := col.TouchMulti([]string{"session1", "missing", "session2"}, 30*time.Minute, nil)
+	suite.Require().Nil(err, err)
+	suite.Require().Len(results, 3)
+
+	suite.Assert().Equal("session1", results[0].ID)
+	suite.Require().Nil(results[0].Err, results[0].Err)
+	suite.Assert().Equal(Cas(123), results[0].Result.Cas())
+
+	suite.Assert().Equal("missing", results[1].ID)
+	suite.Assert().ErrorIs(results[1].Err, ErrDocumentNotFound)
+	suite.Assert().Nil(results[1].Result)
+
+	suite.Assert().Equal("session2", results[2].ID)
+	suite.Assert().Nil(results[2].Err, results[2].Err)
+}
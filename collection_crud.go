@@ -27,6 +27,14 @@ type InsertOptions struct {
 	// UNCOMMITTED: This API may change in the future.
 	Context context.Context
 
+	// Idempotent attaches a client generated operation ID to the document as a system extended attribute. If the operation
+	// fails with an ambiguous error (ErrAmbiguousTimeout or ErrDurabilityAmbiguous) then the document is checked for
+	// the operation ID to determine whether the write actually succeeded, if it did not then the write is attempted
+	// once more. Each attempt is subject to the operation timeout. The value must be encoded as JSON and this cannot
+	// be used alongside PersistTo or ReplicateTo.
+	// UNCOMMITTED: This API may change in the future.
+	Idempotent bool
+
 	// Internal: This should never be used and is not supported.
 	Internal struct {
 		User string
@@ -40,6 +48,10 @@ func (c *Collection) Insert(id string, val interface{}, opts *InsertOptions) (mu
 			opts = &InsertOptions{}
 		}
 
+		if err := c.checkMutationAllowed(); err != nil {
+			return nil, err
+		}
+
 		return agent.Insert(c, id, val, opts)
 	})
 }
@@ -61,6 +73,14 @@ type UpsertOptions struct {
 	// UNCOMMITTED: This API may change in the future.
 	Context context.Context
 
+	// Idempotent attaches a client generated operation ID to the document as a system extended attribute. If the operation
+	// fails with an ambiguous error (ErrAmbiguousTimeout or ErrDurabilityAmbiguous) then the document is checked for
+	// the operation ID to determine whether the write actually succeeded, if it did not then the write is attempted
+	// once more. Each attempt is subject to the operation timeout. The value must be encoded as JSON and this cannot
+	// be used alongside PersistTo or ReplicateTo.
+	// UNCOMMITTED: This API may change in the future.
+	Idempotent bool
+
 	// Internal: This should never be used and is not supported.
 	Internal struct {
 		User string
@@ -74,6 +94,10 @@ func (c *Collection) Upsert(id string, val interface{}, opts *UpsertOptions) (mu
 			opts = &UpsertOptions{}
 		}
 
+		if err := c.checkMutationAllowed(); err != nil {
+			return nil, err
+		}
+
 		return agent.Upsert(c, id, val, opts)
 	})
 }
@@ -109,6 +133,10 @@ func (c *Collection) Replace(id string, val interface{}, opts *ReplaceOptions) (
 			opts = &ReplaceOptions{}
 		}
 
+		if err := c.checkMutationAllowed(); err != nil {
+			return nil, err
+		}
+
 		if opts.Expiry > 0 && opts.PreserveExpiry {
 			return nil, makeInvalidArgumentsError("cannot use expiry and preserve ttl together for replace")
 		}
@@ -117,6 +145,19 @@ func (c *Collection) Replace(id string, val interface{}, opts *ReplaceOptions) (
 	})
 }
 
+// ReadConsistency specifies a set of mutations which a read operation must wait upon before it is performed. The
+// mutations are observed on the cluster until they have been replicated and persisted to the requested number of
+// nodes, which allows for read-your-own-writes semantics across application instances without the use of query.
+// Only tokens which belong to the same bucket and vbucket as the document being read are waited upon.
+// If neither PersistTo nor ReplicateTo are set then the read will wait until the mutations are visible on at least
+// one node.
+// UNCOMMITTED: This API may change in the future.
+type ReadConsistency struct {
+	ConsistentWith *MutationState
+	PersistTo      uint
+	ReplicateTo    uint
+}
+
 // GetOptions are the options available to a Get operation.
 type GetOptions struct {
 	WithExpiry bool
@@ -129,6 +170,18 @@ type GetOptions struct {
 	RetryStrategy RetryStrategy
 	ParentSpan    RequestSpan
 
+	// ReadConsistency causes the operation to wait for the specified mutations to be observed before the document
+	// is fetched.
+	// UNCOMMITTED: This API may change in the future.
+	ReadConsistency *ReadConsistency
+
+	// RefreshExpiry causes the expiry of the document to be updated to the given duration as it is fetched, using a
+	// GetAndTouch. This cannot be used alongside Project, WithExpiry or ReadConsistency. If not set then any sliding
+	// expiry configured using Collection.WithSlidingExpiry is used. Refreshing the expiry is a mutation, so fails with
+	// ErrClusterReadOnly when the cluster is in ReadOnlyMode.
+	// UNCOMMITTED: This API may change in the future.
+	RefreshExpiry time.Duration
+
 	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
 	// also applies to global level timeouts.
 	// UNCOMMITTED: This API may change in the future.
@@ -149,10 +202,47 @@ func (c *Collection) Get(id string, opts *GetOptions) (docOut *GetResult, errOut
 			opts = &GetOptions{}
 		}
 
-		return agent.Get(c, id, opts)
+		refreshExpiry := opts.RefreshExpiry
+		usesSubdoc := len(opts.Project) > 0 || opts.WithExpiry || opts.ReadConsistency != nil
+		if refreshExpiry > 0 && usesSubdoc {
+			return nil, makeInvalidArgumentsError("RefreshExpiry cannot be used with Project, WithExpiry or ReadConsistency")
+		}
+		if refreshExpiry == 0 && !usesSubdoc {
+			refreshExpiry = c.slidingExpiry
+		}
+
+		if refreshExpiry > 0 {
+			if err := c.checkMutationAllowed(); err != nil {
+				return nil, err
+			}
+
+			touchOpts := &GetAndTouchOptions{
+				Transcoder:    opts.Transcoder,
+				Timeout:       opts.Timeout,
+				RetryStrategy: opts.RetryStrategy,
+				ParentSpan:    opts.ParentSpan,
+				Context:       opts.Context,
+			}
+			touchOpts.Internal.User = opts.Internal.User
+
+			return c.withWarningHandler(agent.GetAndTouch(c, id, refreshExpiry, touchOpts))
+		}
+
+		return c.withWarningHandler(agent.Get(c, id, opts))
 	})
 }
 
+// withWarningHandler passes the warning handler of the collection to the result, so that its deprecated accessors
+// can raise warnings.
+func (c *Collection) withWarningHandler(res *GetResult, err error) (*GetResult, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	res.warningHandler = c.warningHandler
+	return res, nil
+}
+
 // ExistsOptions are the options available to the Exists command.
 type ExistsOptions struct {
 	Timeout       time.Duration
@@ -191,6 +281,11 @@ type GetAllReplicaOptions struct {
 	// UNCOMMITTED: This API may change in the future.
 	ReadPreference ReadPreference
 
+	// ReadConsistency causes the operation to wait for the specified mutations to be observed before any replicas
+	// are fetched.
+	// UNCOMMITTED: This API may change in the future.
+	ReadConsistency *ReadConsistency
+
 	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
 	// also applies to global level timeouts.
 	// UNCOMMITTED: This API may change in the future.
@@ -205,15 +300,28 @@ type GetAllReplicaOptions struct {
 // GetAllReplicasResult represents the results of a GetAllReplicas operation.
 type GetAllReplicasResult struct {
 	res replicasResult
+
+	activeCas      Cas
+	activeCasKnown bool
 }
 
-// Next fetches the next replica result.
+// Next fetches the next replica result. Once the result from the active has been returned, any subsequent results
+// from replicas are compared against it, see GetReplicaResult.IsStale.
 func (r *GetAllReplicasResult) Next() *GetReplicaResult {
 	res := r.res.Next()
 	if res == nil {
 		return nil
 	}
-	return res.(*GetReplicaResult) // nolint: errcheck
+
+	repRes := res.(*GetReplicaResult) // nolint: errcheck
+	if !repRes.isReplica {
+		r.activeCas = repRes.cas
+		r.activeCasKnown = true
+	}
+	repRes.activeCas = r.activeCas
+	repRes.activeCasKnown = r.activeCasKnown
+
+	return repRes
 }
 
 // Close cancels all remaining get replica requests.
@@ -338,6 +446,11 @@ type GetAnyReplicaOptions struct {
 	// UNCOMMITTED: This API may change in the future.
 	ReadPreference ReadPreference
 
+	// ReadConsistency causes the operation to wait for the specified mutations to be observed before any replicas
+	// are fetched.
+	// UNCOMMITTED: This API may change in the future.
+	ReadConsistency *ReadConsistency
+
 	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
 	// also applies to global level timeouts.
 	// UNCOMMITTED: This API may change in the future.
@@ -388,6 +501,10 @@ func (c *Collection) Remove(id string, opts *RemoveOptions) (mutOut *MutationRes
 			opts = &RemoveOptions{}
 		}
 
+		if err := c.checkMutationAllowed(); err != nil {
+			return nil, err
+		}
+
 		return agent.Remove(c, id, opts)
 	})
 }
@@ -417,7 +534,71 @@ func (c *Collection) GetAndTouch(id string, expiry time.Duration, opts *GetAndTo
 			opts = &GetAndTouchOptions{}
 		}
 
-		return agent.GetAndTouch(c, id, expiry, opts)
+		if err := c.checkMutationAllowed(); err != nil {
+			return nil, err
+		}
+
+		return c.withWarningHandler(agent.GetAndTouch(c, id, expiry, opts))
+	})
+}
+
+// GetExpiryOptions are the options available to the GetExpiry operation.
+// UNCOMMITTED: This API may change in the future.
+type GetExpiryOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+
+	// Internal: This should never be used and is not supported.
+	Internal struct {
+		User string
+	}
+}
+
+// GetExpiry retrieves the expiry time of a document without fetching its content, using the $document virtual
+// extended attribute.
+// UNCOMMITTED: This API may change in the future.
+func (c *Collection) GetExpiry(id string, opts *GetExpiryOptions) (*GetExpiryResult, error) {
+	return autoOpControl(c.kvController(), "get_expiry", func(agent kvProvider) (*GetExpiryResult, error) {
+		if opts == nil {
+			opts = &GetExpiryOptions{}
+		}
+
+		span := agent.StartKvOpTrace(c, "get_expiry", opts.ParentSpan, false)
+		defer span.End()
+
+		lookupOpts := &LookupInOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    span,
+			Context:       opts.Context,
+		}
+		lookupOpts.Internal.User = opts.Internal.User
+
+		result, err := agent.LookupIn(c, id, []LookupInSpec{
+			GetSpec("$document.exptime", &GetSpecOptions{IsXattr: true}),
+		}, lookupOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		var expires int64
+		err = result.ContentAt(0, &expires)
+		if err != nil {
+			return nil, err
+		}
+
+		res := &GetExpiryResult{}
+		res.cas = result.Cas()
+		if expires > 0 {
+			res.expiryTime = time.Unix(expires, 0)
+		}
+
+		return res, nil
 	})
 }
 
@@ -448,7 +629,7 @@ func (c *Collection) GetAndLock(id string, lockTime time.Duration, opts *GetAndL
 			opts = &GetAndLockOptions{}
 		}
 
-		return agent.GetAndLock(c, id, lockTime, opts)
+		return c.withWarningHandler(agent.GetAndLock(c, id, lockTime, opts))
 	})
 }
 
@@ -504,6 +685,10 @@ func (c *Collection) Touch(id string, expiry time.Duration, opts *TouchOptions)
 			opts = &TouchOptions{}
 		}
 
+		if err := c.checkMutationAllowed(); err != nil {
+			return nil, err
+		}
+
 		return agent.Touch(c, id, expiry, opts)
 	})
 }
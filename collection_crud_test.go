@@ -2523,6 +2523,45 @@ func (suite *UnitTestSuite) TestExpiryConversion30Days() {
 	suite.Assert().Equal(Cas(123), res.Cas())
 }
 
+func (suite *UnitTestSuite) TestExpiryConversionTimeSource() {
+	now := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	var expiries []uint32
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("Set", mock.AnythingOfType("gocbcore.SetOptions"), mock.AnythingOfType("gocbcore.StoreCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.SetOptions)
+			cb := args.Get(1).(gocbcore.StoreCallback)
+
+			expiries = append(expiries, opts.Expiry)
+
+			cb(&gocbcore.StoreResult{
+				Cas: gocbcore.Cas(123),
+			}, nil)
+		}).
+		Return(new(mockPendingOp), nil)
+
+	agent := suite.kvProviderCore(provider, nil)
+	agent.timeSource = func() time.Time {
+		return now
+	}
+
+	col := suite.collection("mock", "", "", agent)
+
+	for _, expiry := range []time.Duration{30*24*time.Hour - time.Second, 30 * 24 * time.Hour} {
+		_, err := col.Upsert("someid", "someval", &UpsertOptions{
+			Expiry: expiry,
+		})
+		suite.Require().Nil(err, err)
+	}
+
+	suite.Assert().Equal([]uint32{
+		uint32((30*24*time.Hour - time.Second) / time.Second),
+		uint32(now.Add(30 * 24 * time.Hour).Unix()),
+	}, expiries)
+}
+
 func (suite *UnitTestSuite) TestExpiryConversion31Days() {
 	pendingOp := new(mockPendingOp)
 	pendingOp.AssertNotCalled(suite.T(), "Cancel", mock.AnythingOfType("error"))
@@ -2560,3 +2599,414 @@ func (suite *UnitTestSuite) TestExpiryConversion31Days() {
 
 	suite.Assert().Equal(Cas(123), res.Cas())
 }
+
+func (suite *UnitTestSuite) TestGetReadConsistencyObservesMatchingTokens() {
+	pendingOp := new(mockPendingOp)
+	pendingOp.AssertNotCalled(suite.T(), "Cancel", mock.AnythingOfType("error"))
+
+	var observed bool
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("ObserveVb", mock.AnythingOfType("gocbcore.ObserveVbOptions"), mock.AnythingOfType("gocbcore.ObserveVbCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.ObserveVbOptions)
+			cb := args.Get(1).(gocbcore.ObserveVbCallback)
+
+			suite.Assert().Equal(uint16(12), opts.VbID)
+			suite.Assert().Equal(gocbcore.VbUUID(1234), opts.VbUUID)
+
+			observed = true
+			cb(&gocbcore.ObserveVbResult{
+				VbID:         opts.VbID,
+				VbUUID:       opts.VbUUID,
+				CurrentSeqNo: 10,
+				PersistSeqNo: 10,
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+	provider.
+		On("Get", mock.AnythingOfType("gocbcore.GetOptions"), mock.AnythingOfType("gocbcore.GetCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(1).(gocbcore.GetCallback)
+
+			suite.Assert().True(observed, "Get should not be dispatched before the token has been observed")
+			cb(&gocbcore.GetResult{
+				Value: []byte(`"someval"`),
+				Cas:   gocbcore.Cas(123),
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	snapshotProvider := &mockConfigSnapshotProvider{
+		snapshot: &mockConfigSnapshot{
+			keyVbucket: 12,
+		},
+	}
+
+	agent := suite.kvProviderCore(provider, snapshotProvider)
+
+	col := suite.collection("mock", "", "", agent)
+
+	state := NewMutationState()
+	state.Internal().Add("mock", gocbcore.MutationToken{VbID: 12, VbUUID: 1234, SeqNo: 10})
+	state.Internal().Add("mock", gocbcore.MutationToken{VbID: 13, VbUUID: 5678, SeqNo: 10})
+	state.Internal().Add("other", gocbcore.MutationToken{VbID: 12, VbUUID: 5678, SeqNo: 10})
+
+	res, err := col.Get("someid", &GetOptions{
+		ReadConsistency: &ReadConsistency{
+			ConsistentWith: state,
+			PersistTo:      1,
+		},
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(Cas(123), res.Cas())
+	provider.AssertNumberOfCalls(suite.T(), "ObserveVb", 1)
+}
+
+func (suite *UnitTestSuite) TestGetReadConsistencyDurabilityImpossible() {
+	provider := new(mockKvProviderCoreProvider)
+
+	snapshotProvider := &mockConfigSnapshotProvider{
+		snapshot: &mockConfigSnapshot{
+			keyVbucket: 12,
+		},
+	}
+
+	agent := suite.kvProviderCore(provider, snapshotProvider)
+
+	col := suite.collection("mock", "", "", agent)
+
+	state := NewMutationState()
+	state.Internal().Add("mock", gocbcore.MutationToken{VbID: 12, VbUUID: 1234, SeqNo: 10})
+
+	_, err := col.Get("someid", &GetOptions{
+		ReadConsistency: &ReadConsistency{
+			ConsistentWith: state,
+			ReplicateTo:    1,
+		},
+	})
+	suite.Require().ErrorIs(err, ErrDurabilityImpossible)
+
+	provider.AssertNotCalled(suite.T(), "Get", mock.Anything, mock.Anything)
+}
+
+func (suite *UnitTestSuite) TestGetReadConsistencyTimeoutIsUnambiguous() {
+	pendingOp := new(mockPendingOp)
+	pendingOp.On("Cancel").Return().Maybe()
+
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("ObserveVb", mock.AnythingOfType("gocbcore.ObserveVbOptions"), mock.AnythingOfType("gocbcore.ObserveVbCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.ObserveVbOptions)
+			cb := args.Get(1).(gocbcore.ObserveVbCallback)
+
+			cb(&gocbcore.ObserveVbResult{
+				VbID:         opts.VbID,
+				VbUUID:       opts.VbUUID,
+				CurrentSeqNo: 5,
+				PersistSeqNo: 5,
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	snapshotProvider := &mockConfigSnapshotProvider{
+		snapshot: &mockConfigSnapshot{
+			keyVbucket: 12,
+		},
+	}
+
+	agent := suite.kvProviderCore(provider, snapshotProvider)
+
+	col := suite.collection("mock", "", "", agent)
+
+	state := NewMutationState()
+	state.Internal().Add("mock", gocbcore.MutationToken{VbID: 12, VbUUID: 1234, SeqNo: 10})
+
+	_, err := col.Get("someid", &GetOptions{
+		Timeout: 50 * time.Millisecond,
+		ReadConsistency: &ReadConsistency{
+			ConsistentWith: state,
+			PersistTo:      1,
+		},
+	})
+	suite.Require().ErrorIs(err, ErrUnambiguousTimeout)
+	suite.Assert().NotErrorIs(err, ErrAmbiguousTimeout)
+
+	provider.AssertNotCalled(suite.T(), "Get", mock.Anything, mock.Anything)
+}
+
+func (suite *UnitTestSuite) TestInsertIdempotentAmbiguousWriteSucceeded() {
+	pendingOp := new(mockPendingOp)
+	pendingOp.AssertNotCalled(suite.T(), "Cancel", mock.AnythingOfType("error"))
+
+	var opID []byte
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("MutateIn", mock.AnythingOfType("gocbcore.MutateInOptions"), mock.AnythingOfType("gocbcore.MutateInCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.MutateInOptions)
+			cb := args.Get(1).(gocbcore.MutateInCallback)
+
+			suite.Require().Len(opts.Ops, 2)
+			suite.Assert().Equal(memd.SubdocDocFlagAddDoc, opts.Flags&memd.SubdocDocFlagAddDoc)
+			suite.Assert().Equal("_gocb_opid", opts.Ops[0].Path)
+			suite.Assert().Equal(memd.SubdocFlagXattrPath, opts.Ops[0].Flags&memd.SubdocFlagXattrPath)
+			suite.Assert().Equal(memd.SubDocOpSetDoc, opts.Ops[1].Op)
+			suite.Assert().Equal([]byte(`{"key":"value"}`), opts.Ops[1].Value)
+
+			opID = opts.Ops[0].Value
+			cb(nil, gocbcore.ErrAmbiguousTimeout)
+		}).
+		Return(pendingOp, nil)
+	provider.
+		On("LookupIn", mock.AnythingOfType("gocbcore.LookupInOptions"), mock.AnythingOfType("gocbcore.LookupInCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.LookupInOptions)
+			cb := args.Get(1).(gocbcore.LookupInCallback)
+
+			suite.Require().Len(opts.Ops, 1)
+			suite.Assert().Equal(idempotentOperationIDXattr, opts.Ops[0].Path)
+
+			cb(&gocbcore.LookupInResult{
+				Cas: gocbcore.Cas(123),
+				Ops: []gocbcore.SubDocResult{{Value: opID}},
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	agent := suite.kvProviderCore(provider, nil)
+	col := suite.collection("mock", "", "", agent)
+
+	res, err := col.Insert("someid", map[string]string{"key": "value"}, &InsertOptions{
+		Idempotent: true,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(Cas(123), res.Cas())
+	provider.AssertNumberOfCalls(suite.T(), "MutateIn", 1)
+}
+
+func (suite *UnitTestSuite) TestInsertIdempotentAmbiguousWriteRetried() {
+	pendingOp := new(mockPendingOp)
+	pendingOp.AssertNotCalled(suite.T(), "Cancel", mock.AnythingOfType("error"))
+
+	var opIDs [][]byte
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("MutateIn", mock.AnythingOfType("gocbcore.MutateInOptions"), mock.AnythingOfType("gocbcore.MutateInCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.MutateInOptions)
+			cb := args.Get(1).(gocbcore.MutateInCallback)
+
+			opIDs = append(opIDs, opts.Ops[0].Value)
+			if len(opIDs) == 1 {
+				cb(nil, gocbcore.ErrAmbiguousTimeout)
+				return
+			}
+
+			cb(&gocbcore.MutateInResult{
+				Cas: gocbcore.Cas(456),
+				Ops: make([]gocbcore.SubDocResult, 2),
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+	provider.
+		On("LookupIn", mock.AnythingOfType("gocbcore.LookupInOptions"), mock.AnythingOfType("gocbcore.LookupInCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(1).(gocbcore.LookupInCallback)
+
+			cb(nil, gocbcore.ErrDocumentNotFound)
+		}).
+		Return(pendingOp, nil)
+
+	agent := suite.kvProviderCore(provider, nil)
+	col := suite.collection("mock", "", "", agent)
+
+	res, err := col.Insert("someid", map[string]string{"key": "value"}, &InsertOptions{
+		Idempotent: true,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(Cas(456), res.Cas())
+	suite.Require().Len(opIDs, 2)
+	suite.Assert().Equal(opIDs[0], opIDs[1])
+}
+
+func (suite *UnitTestSuite) TestInsertIdempotentNonJSONValue() {
+	provider := new(mockKvProviderCoreProvider)
+
+	agent := suite.kvProviderCore(provider, nil)
+	col := suite.collection("mock", "", "", agent)
+
+	_, err := col.Insert("someid", []byte("raw"), &InsertOptions{
+		Idempotent: true,
+		Transcoder: NewRawBinaryTranscoder(),
+	})
+	suite.Require().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestGetExpiry() {
+	pendingOp := new(mockPendingOp)
+	pendingOp.AssertNotCalled(suite.T(), "Cancel", mock.AnythingOfType("error"))
+
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("LookupIn", mock.AnythingOfType("gocbcore.LookupInOptions"), mock.AnythingOfType("gocbcore.LookupInCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.LookupInOptions)
+			cb := args.Get(1).(gocbcore.LookupInCallback)
+
+			suite.Require().Len(opts.Ops, 1)
+			suite.Assert().Equal("$document.exptime", opts.Ops[0].Path)
+			suite.Assert().Equal(memd.SubdocFlagXattrPath, opts.Ops[0].Flags&memd.SubdocFlagXattrPath)
+
+			cb(&gocbcore.LookupInResult{
+				Cas: gocbcore.Cas(123),
+				Ops: []gocbcore.SubDocResult{{Value: []byte("1700000000")}},
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	agent := suite.kvProviderCore(provider, nil)
+	col := suite.collection("mock", "", "", agent)
+
+	res, err := col.GetExpiry("someid", nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(Cas(123), res.Cas())
+	suite.Assert().Equal(time.Unix(1700000000, 0), res.ExpiryTime())
+}
+
+func (suite *UnitTestSuite) TestGetExpiryNoExpiry() {
+	pendingOp := new(mockPendingOp)
+	pendingOp.AssertNotCalled(suite.T(), "Cancel", mock.AnythingOfType("error"))
+
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("LookupIn", mock.AnythingOfType("gocbcore.LookupInOptions"), mock.AnythingOfType("gocbcore.LookupInCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(1).(gocbcore.LookupInCallback)
+
+			cb(&gocbcore.LookupInResult{
+				Cas: gocbcore.Cas(123),
+				Ops: []gocbcore.SubDocResult{{Value: []byte("0")}},
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	agent := suite.kvProviderCore(provider, nil)
+	col := suite.collection("mock", "", "", agent)
+
+	res, err := col.GetExpiry("someid", nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().True(res.ExpiryTime().IsZero())
+}
+
+func (suite *UnitTestSuite) TestGetRefreshExpiry() {
+	pendingOp := new(mockPendingOp)
+
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("GetAndTouch", mock.AnythingOfType("gocbcore.GetAndTouchOptions"), mock.AnythingOfType("gocbcore.GetAndTouchCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.GetAndTouchOptions)
+			cb := args.Get(1).(gocbcore.GetAndTouchCallback)
+
+			if string(opts.Key) == "explicit" {
+				suite.Assert().Equal(uint32(60), opts.Expiry)
+			} else {
+				suite.Assert().Equal(uint32(30), opts.Expiry)
+			}
+			cb(&gocbcore.GetAndTouchResult{
+				Value: []byte(`"value"`),
+				Cas:   gocbcore.Cas(123),
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	agent := suite.kvProviderCore(provider, nil)
+	col := suite.collection("mock", "", "", agent)
+
+	res, err := col.Get("explicit", &GetOptions{RefreshExpiry: 60 * time.Second})
+	suite.Require().Nil(err, err)
+
+	var val string
+	suite.Require().Nil(res.Content(&val))
+	suite.Assert().Equal("value", val)
+	suite.Assert().Equal(Cas(123), res.Cas())
+
+	sliding := col.WithSlidingExpiry(30 * time.Second)
+	_, err = sliding.Get("sliding", nil)
+	suite.Require().Nil(err, err)
+
+	_, err = sliding.Get("explicit", &GetOptions{RefreshExpiry: 60 * time.Second})
+	suite.Require().Nil(err, err)
+
+	provider.AssertNumberOfCalls(suite.T(), "GetAndTouch", 3)
+	provider.AssertNotCalled(suite.T(), "Get", mock.Anything, mock.Anything)
+}
+
+func (suite *UnitTestSuite) TestGetRefreshExpiryInvalidWithProject() {
+	provider := new(mockKvProviderCoreProvider)
+	agent := suite.kvProviderCore(provider, nil)
+	col := suite.collection("mock", "", "", agent)
+
+	_, err := col.Get("someid", &GetOptions{
+		RefreshExpiry: 60 * time.Second,
+		Project:       []string{"name"},
+	})
+	suite.Require().ErrorIs(err, ErrInvalidArgument)
+
+	provider.AssertNotCalled(suite.T(), "GetAndTouch", mock.Anything, mock.Anything)
+	provider.AssertNotCalled(suite.T(), "LookupIn", mock.Anything, mock.Anything)
+}
+
+func (suite *UnitTestSuite) TestGetAllReplicasStaleness() {
+	coreRes := &coreReplicasResult{
+		totalRequests:       3,
+		resCh:               make(chan interface{}, 3),
+		cancelCh:            make(chan struct{}),
+		span:                &noopSpan{},
+		childReqsCompleteCh: make(chan struct{}),
+	}
+	repRes := &GetAllReplicasResult{res: coreRes}
+
+	newResult := func(cas Cas, replicaIdx int) *GetReplicaResult {
+		return &GetReplicaResult{
+			GetResult:    GetResult{Result: Result{cas: cas}},
+			isReplica:    replicaIdx > 0,
+			replicaIndex: replicaIdx,
+		}
+	}
+
+	coreRes.addResult(newResult(10, 2))
+	coreRes.addResult(newResult(12, 0))
+	coreRes.addResult(newResult(11, 1))
+
+	// The active has not yet been seen so staleness cannot be determined.
+	res := repRes.Next()
+	suite.Require().NotNil(res)
+	suite.Assert().Equal(2, res.ReplicaIndex())
+	_, known := res.ActiveCas()
+	suite.Assert().False(known)
+	suite.Assert().False(res.IsStale())
+
+	res = repRes.Next()
+	suite.Require().NotNil(res)
+	suite.Assert().Equal(0, res.ReplicaIndex())
+	suite.Assert().False(res.IsReplica())
+	suite.Assert().False(res.IsStale())
+
+	res = repRes.Next()
+	suite.Require().NotNil(res)
+	suite.Assert().Equal(1, res.ReplicaIndex())
+	activeCas, known := res.ActiveCas()
+	suite.Assert().True(known)
+	suite.Assert().Equal(Cas(12), activeCas)
+	suite.Assert().True(res.IsStale())
+
+	suite.Assert().Nil(repRes.Next())
+}
@@ -3,6 +3,8 @@ package gocb
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 // CouchbaseList represents a list document.
@@ -71,7 +73,7 @@ func (cl *CouchbaseList) RemoveAt(index int) error {
 		defer span.End()
 		ops := make([]MutateInSpec, 1)
 		ops[0] = RemoveSpec(fmt.Sprintf("[%d]", index), nil)
-		_, err := agent.MutateIn(cl.collection, cl.id, ops, &MutateInOptions{
+		_, err := dsMutateIn(agent, cl.collection, cl.id, ops, &MutateInOptions{
 			ParentSpan: span,
 		})
 		if err != nil {
@@ -89,7 +91,7 @@ func (cl *CouchbaseList) Append(val interface{}) error {
 		defer span.End()
 		ops := make([]MutateInSpec, 1)
 		ops[0] = ArrayAppendSpec("", val, nil)
-		_, err := agent.MutateIn(cl.collection, cl.id, ops, &MutateInOptions{
+		_, err := dsMutateIn(agent, cl.collection, cl.id, ops, &MutateInOptions{
 			StoreSemantic: StoreSemanticsUpsert,
 			ParentSpan:    span,
 		})
@@ -114,7 +116,7 @@ func (cl *CouchbaseList) Prepend(val interface{}) error {
 func dsListPrepend(agent kvProvider, span RequestSpan, collection *Collection, id string, val interface{}) error {
 	ops := make([]MutateInSpec, 1)
 	ops[0] = ArrayPrependSpec("", val, nil)
-	_, err := agent.MutateIn(collection, id, ops, &MutateInOptions{
+	_, err := dsMutateIn(agent, collection, id, ops, &MutateInOptions{
 		StoreSemantic: StoreSemanticsUpsert,
 		ParentSpan:    span,
 	})
@@ -193,7 +195,7 @@ func (cl *CouchbaseList) Clear() error {
 }
 
 func dsListClear(agent kvProvider, span RequestSpan, collection *Collection, id string) error {
-	_, err := agent.Remove(collection, id, &RemoveOptions{
+	_, err := dsRemove(agent, collection, id, &RemoveOptions{
 		ParentSpan: span,
 	})
 	if err != nil {
@@ -203,6 +205,25 @@ func dsListClear(agent kvProvider, span RequestSpan, collection *Collection, id
 	return nil
 }
 
+// dsMutateIn mutates a data structure document, applying the same checks as Collection.MutateIn.
+func dsMutateIn(agent kvProvider, collection *Collection, id string, ops []MutateInSpec,
+	opts *MutateInOptions) (*MutateInResult, error) {
+	if err := collection.checkMutationAllowed(); err != nil {
+		return nil, err
+	}
+
+	return agent.MutateIn(collection, id, ops, opts)
+}
+
+// dsRemove removes a data structure document, applying the same checks as Collection.Remove.
+func dsRemove(agent kvProvider, collection *Collection, id string, opts *RemoveOptions) (*MutationResult, error) {
+	if err := collection.checkMutationAllowed(); err != nil {
+		return nil, err
+	}
+
+	return agent.Remove(collection, id, opts)
+}
+
 // CouchbaseMap represents a map document.
 type CouchbaseMap struct {
 	collection *Collection
@@ -264,7 +285,7 @@ func (cl *CouchbaseMap) Add(id string, val interface{}) error {
 		defer span.End()
 		ops := make([]MutateInSpec, 1)
 		ops[0] = UpsertSpec(id, val, nil)
-		_, err := agent.MutateIn(cl.collection, cl.id, ops, &MutateInOptions{
+		_, err := dsMutateIn(agent, cl.collection, cl.id, ops, &MutateInOptions{
 			StoreSemantic: StoreSemanticsUpsert,
 			ParentSpan:    span,
 		})
@@ -283,7 +304,7 @@ func (cl *CouchbaseMap) Remove(id string) error {
 		defer span.End()
 		ops := make([]MutateInSpec, 1)
 		ops[0] = RemoveSpec(id, nil)
-		_, err := agent.MutateIn(cl.collection, cl.id, ops, &MutateInOptions{
+		_, err := dsMutateIn(agent, cl.collection, cl.id, ops, &MutateInOptions{
 			ParentSpan: span,
 		})
 		if err != nil {
@@ -395,7 +416,7 @@ func (cl *CouchbaseMap) Clear() error {
 	return autoOpControlErrorOnly(cl.collection.kvController(), "map_clear", func(agent kvProvider) error {
 		span := agent.StartKvOpTrace(cl.collection, "map_clear", nil, false)
 		defer span.End()
-		_, err := agent.Remove(cl.collection, cl.id, &RemoveOptions{
+		_, err := dsRemove(agent, cl.collection, cl.id, &RemoveOptions{
 			ParentSpan: span,
 		})
 		if err != nil {
@@ -436,7 +457,7 @@ func (cs *CouchbaseSet) Add(val interface{}) error {
 		defer span.End()
 		ops := make([]MutateInSpec, 1)
 		ops[0] = ArrayAddUniqueSpec("", val, nil)
-		_, err := agent.MutateIn(cs.collection, cs.id, ops, &MutateInOptions{
+		_, err := dsMutateIn(agent, cs.collection, cs.id, ops, &MutateInOptions{
 			StoreSemantic: StoreSemanticsUpsert,
 			ParentSpan:    span,
 		})
@@ -479,7 +500,7 @@ func (cs *CouchbaseSet) Remove(val string) error {
 			if indexToRemove > -1 {
 				ops := make([]MutateInSpec, 1)
 				ops[0] = RemoveSpec(fmt.Sprintf("[%d]", indexToRemove), nil)
-				_, err = cs.collection.MutateIn(cs.id, ops, &MutateInOptions{
+				_, err = dsMutateIn(agent, cs.collection, cs.id, ops, &MutateInOptions{
 					Cas:        cas,
 					ParentSpan: span,
 				})
@@ -620,7 +641,7 @@ func (cs *CouchbaseQueue) Pop(valuePtr interface{}) error {
 
 			mutateOps := make([]MutateInSpec, 1)
 			mutateOps[0] = RemoveSpec("[-1]", nil)
-			_, err = agent.MutateIn(cs.collection, cs.id, mutateOps, &MutateInOptions{
+			_, err = dsMutateIn(agent, cs.collection, cs.id, mutateOps, &MutateInOptions{
 				Cas:        cas,
 				ParentSpan: span,
 			})
@@ -654,3 +675,100 @@ func (cs *CouchbaseQueue) Clear() error {
 		return dsListClear(agent, span, cs.collection, cs.id)
 	})
 }
+
+// signedCounterOffset is added to the value of a CouchbaseSignedCounter before it is stored, this allows the
+// counter to go negative whilst still being stored as an unsigned counter on the server.
+const signedCounterOffset = int64(1) << 62
+
+// CouchbaseSignedCounter represents a counter document which can hold negative values. The value is stored offset by
+// 2^62 so the counter supports values in the range -2^62 to 2^62. The document is not compatible with Increment and
+// Decrement from other clients unless they apply the same offset.
+// UNCOMMITTED: This API may change in the future.
+type CouchbaseSignedCounter struct {
+	collection *Collection
+	id         string
+}
+
+// SignedCounter returns a new CouchbaseSignedCounter for the document specified by id.
+// UNCOMMITTED: This API may change in the future.
+func (c *Collection) SignedCounter(id string) *CouchbaseSignedCounter {
+	return &CouchbaseSignedCounter{
+		collection: c,
+		id:         id,
+	}
+}
+
+// Add atomically adds delta to the counter, which may be negative, and returns the new value. If the counter does not
+// exist then it is created with an initial value of zero before delta is applied.
+func (cs *CouchbaseSignedCounter) Add(delta int64) (int64, error) {
+	return autoOpControl(cs.collection.kvController(), "signed_counter_add", func(agent kvProvider) (int64, error) {
+		if delta <= -signedCounterOffset || delta >= signedCounterOffset {
+			return 0, makeInvalidArgumentsError("delta is outside of the range supported by a signed counter")
+		}
+		if err := cs.collection.checkMutationAllowed(); err != nil {
+			return 0, err
+		}
+
+		span := agent.StartKvOpTrace(cs.collection, "signed_counter_add", nil, false)
+		defer span.End()
+
+		var res *CounterResult
+		var err error
+		if delta >= 0 {
+			res, err = agent.Increment(cs.collection, cs.id, &IncrementOptions{
+				Delta:      uint64(delta),
+				Initial:    signedCounterOffset + delta,
+				ParentSpan: span,
+			})
+		} else {
+			res, err = agent.Decrement(cs.collection, cs.id, &DecrementOptions{
+				Delta:      uint64(-delta),
+				Initial:    signedCounterOffset + delta,
+				ParentSpan: span,
+			})
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		return int64(res.Content() - uint64(signedCounterOffset)), nil
+	})
+}
+
+// Value returns the current value of the counter.
+func (cs *CouchbaseSignedCounter) Value() (int64, error) {
+	return autoOpControl(cs.collection.kvController(), "signed_counter_value", func(agent kvProvider) (int64, error) {
+		span := agent.StartKvOpTrace(cs.collection, "signed_counter_value", nil, false)
+		defer span.End()
+
+		doc, err := agent.Get(cs.collection, cs.id, &GetOptions{
+			Transcoder: counterTranscoder{},
+			ParentSpan: span,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		var raw []byte
+		err = doc.Content(&raw)
+		if err != nil {
+			return 0, err
+		}
+
+		stored, err := strconv.ParseUint(strings.TrimSpace(string(raw)), 10, 64)
+		if err != nil {
+			return 0, makeInvalidArgumentsError("document does not contain a valid counter value")
+		}
+
+		return int64(stored - uint64(signedCounterOffset)), nil
+	})
+}
+
+// Clear clears a counter, also removing it.
+func (cs *CouchbaseSignedCounter) Clear() error {
+	return autoOpControlErrorOnly(cs.collection.kvController(), "signed_counter_clear", func(agent kvProvider) error {
+		span := agent.StartKvOpTrace(cs.collection, "signed_counter_clear", nil, false)
+		defer span.End()
+		return dsListClear(agent, span, cs.collection, cs.id)
+	})
+}
@@ -122,6 +122,25 @@ func (p *kvProviderCore) waitForDurability(
 	deadline time.Time,
 	cancelCh chan struct{},
 	user string,
+) error {
+	return p.waitForObserved(ctx, c, trace, docID, mt, replicateTo, persistTo, deadline, cancelCh, user,
+		ErrAmbiguousTimeout)
+}
+
+// waitForObserved waits until the mutation has been replicated and persisted to the requested number of nodes,
+// failing with timeoutErr if the deadline is reached first.
+func (p *kvProviderCore) waitForObserved(
+	ctx context.Context,
+	c *Collection,
+	trace RequestSpan,
+	docID string,
+	mt gocbcore.MutationToken,
+	replicateTo uint,
+	persistTo uint,
+	deadline time.Time,
+	cancelCh chan struct{},
+	user string,
+	timeoutErr error,
 ) error {
 	observeOpm := newKvOpManagerCore(c, "observe", trace, p)
 	defer observeOpm.Finish()
@@ -172,7 +191,7 @@ func (p *kvProviderCore) waitForDurability(
 			// deadline exceeded
 			close(subOpCancelCh)
 			wg.Wait()
-			return observeOpm.EnhanceErr(ErrAmbiguousTimeout)
+			return observeOpm.EnhanceErr(timeoutErr)
 		case <-cancelCh:
 			// parent asked for cancellation
 			close(subOpCancelCh)
@@ -187,3 +206,54 @@ func (p *kvProviderCore) waitForDurability(
 		}
 	}
 }
+
+func (p *kvProviderCore) waitForReadConsistency(
+	ctx context.Context,
+	c *Collection,
+	trace RequestSpan,
+	docID string,
+	consistency *ReadConsistency,
+	deadline time.Time,
+	user string,
+) error {
+	if consistency == nil || consistency.ConsistentWith == nil {
+		return nil
+	}
+
+	snapshot, err := p.snapshotProvider.WaitForConfigSnapshot(ctx, deadline)
+	if err != nil {
+		return err
+	}
+
+	vbID, err := snapshot.KeyToVbucket([]byte(docID))
+	if err != nil {
+		return err
+	}
+
+	for _, token := range consistency.ConsistentWith.tokens {
+		// Tokens for other buckets or vbuckets cannot affect the visibility of this document.
+		if token.bucketName != c.bucketName() || token.token.VbID != vbID {
+			continue
+		}
+
+		// Nothing has been written by a read, so timing out whilst waiting is never ambiguous.
+		err := p.waitForObserved(
+			ctx,
+			c,
+			trace,
+			docID,
+			token.token,
+			consistency.ReplicateTo,
+			consistency.PersistTo,
+			deadline,
+			nil,
+			user,
+			ErrUnambiguousTimeout,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package gocb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	gocbcore "github.com/couchbase/gocbcore/v10"
+	"github.com/google/uuid"
+)
+
+// idempotentOperationIDXattr is the extended attribute used to store the client generated operation ID when
+// an Insert or Upsert is performed in idempotent mode. It is a system xattr so that it is not surfaced alongside
+// the application's own extended attributes.
+const idempotentOperationIDXattr = "_gocb_opid"
+
+type idempotentStoreOptions struct {
+	action          StoreSemantics
+	expiry          time.Duration
+	preserveExpiry  bool
+	persistTo       uint
+	replicateTo     uint
+	durabilityLevel DurabilityLevel
+	transcoder      Transcoder
+	timeout         time.Duration
+	retryStrategy   RetryStrategy
+	parentSpan      RequestSpan
+	ctx             context.Context
+	user            string
+}
+
+func isAmbiguousMutationError(err error) bool {
+	return errors.Is(err, ErrAmbiguousTimeout) || errors.Is(err, ErrDurabilityAmbiguous)
+}
+
+// idempotentStore performs an Insert or Upsert which attaches a client generated operation ID to the document. If
+// the operation fails with an ambiguous error then the document is checked for the operation ID, if present then
+// the write is known to have succeeded. Otherwise the write is attempted once more.
+func (p *kvProviderCore) idempotentStore(c *Collection, opName, id string, val interface{},
+	opts idempotentStoreOptions) (*MutationResult, error) {
+	if opts.persistTo > 0 || opts.replicateTo > 0 {
+		return nil, makeInvalidArgumentsError("cannot use observe based durability (PersistTo and ReplicateTo) with idempotent mode")
+	}
+
+	opID := uuid.NewString()
+
+	res, err := p.idempotentStoreAttempt(c, opName, id, val, opID, opts)
+	if err == nil || !isAmbiguousMutationError(err) {
+		return res, err
+	}
+
+	matched, cas, exists, lookupErr := p.lookupOperationID(c, id, opID, opts)
+	if lookupErr != nil {
+		// We couldn't determine the outcome so the original error is the most useful thing to surface.
+		return nil, err
+	}
+	if matched {
+		return &MutationResult{Result: Result{cas: cas}}, nil
+	}
+	if exists && opts.action == StoreSemanticsInsert {
+		// The document was created by someone else.
+		return nil, err
+	}
+
+	res, retryErr := p.idempotentStoreAttempt(c, opName, id, val, opID, opts)
+	if retryErr == nil {
+		return res, nil
+	}
+
+	if errors.Is(retryErr, ErrDocumentExists) {
+		// The first attempt may have landed after our lookup.
+		matched, cas, _, lookupErr = p.lookupOperationID(c, id, opID, opts)
+		if lookupErr == nil && matched {
+			return &MutationResult{Result: Result{cas: cas}}, nil
+		}
+	}
+
+	return nil, retryErr
+}
+
+func (p *kvProviderCore) idempotentStoreAttempt(c *Collection, opName, id string, val interface{}, opID string,
+	opts idempotentStoreOptions) (*MutationResult, error) {
+	opm := newKvOpManagerCore(c, opName, opts.parentSpan, p)
+	defer opm.Finish()
+
+	opm.SetDocumentID(id)
+	opm.SetTranscoder(opts.transcoder)
+	opm.SetValue(val)
+	opm.SetDuraOptions(opts.persistTo, opts.replicateTo, opts.durabilityLevel)
+	opm.SetRetryStrategy(opts.retryStrategy)
+	opm.SetTimeout(opts.timeout)
+	opm.SetImpersonate(opts.user)
+	opm.SetContext(opts.ctx)
+	opm.SetPreserveExpiry(opts.preserveExpiry)
+
+	if err := opm.CheckReadyForOp(); err != nil {
+		return nil, err
+	}
+
+	if opm.ValueFlags() != gocbcore.EncodeCommonFlags(gocbcore.JSONType, gocbcore.NoCompression) {
+		return nil, makeInvalidArgumentsError("idempotent mode can only be used with values that are encoded as JSON")
+	}
+
+	ops := []MutateInSpec{
+		UpsertSpec(idempotentOperationIDXattr, opID, &UpsertSpecOptions{IsXattr: true}),
+		ReplaceSpec("", json.RawMessage(opm.ValueBytes()), nil),
+	}
+
+	res, err := p.internalMutateIn(opm, opts.action, opts.expiry, 0, ops, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.MutationResult, nil
+}
+
+func (p *kvProviderCore) lookupOperationID(c *Collection, id, opID string,
+	opts idempotentStoreOptions) (matched bool, cas Cas, exists bool, errOut error) {
+	opm := newKvOpManagerCore(c, "lookup_in", opts.parentSpan, p)
+	defer opm.Finish()
+
+	opm.SetDocumentID(id)
+	opm.SetRetryStrategy(opts.retryStrategy)
+	opm.SetTimeout(opts.timeout)
+	opm.SetImpersonate(opts.user)
+	opm.SetContext(opts.ctx)
+
+	if err := opm.CheckReadyForOp(); err != nil {
+		return false, 0, false, err
+	}
+
+	res, err := p.internalLookupIn(opm, []LookupInSpec{
+		GetSpec(idempotentOperationIDXattr, &GetSpecOptions{IsXattr: true}),
+	}, 0, 0)
+	if err != nil {
+		if errors.Is(err, ErrDocumentNotFound) {
+			return false, 0, false, nil
+		}
+		return false, 0, false, err
+	}
+
+	var storedID string
+	if err := res.ContentAt(0, &storedID); err != nil {
+		if errors.Is(err, ErrPathNotFound) {
+			return false, res.Cas(), true, nil
+		}
+		return false, 0, true, err
+	}
+
+	return storedID == opID, res.Cas(), true, nil
+}
@@ -0,0 +1,96 @@
+package gocb
+
+import "sync/atomic"
+
+// CollectionIdentity identifies a collection by its names and, once it has been resolved by a key value operation,
+// the unique ID assigned to it by the server.
+// UNCOMMITTED: This API may change in the future.
+type CollectionIdentity struct {
+	Bucket     string
+	Scope      string
+	Collection string
+
+	// UID is the unique ID of the collection, this is only valid if UIDKnown is true. A collection which is dropped
+	// and recreated with the same name is assigned a new UID.
+	UID      uint32
+	UIDKnown bool
+}
+
+// Keyspace returns the keyspace of the collection, which is suitable for use as a map key.
+func (ci CollectionIdentity) Keyspace() Keyspace {
+	return Keyspace{
+		Bucket:     ci.Bucket,
+		Scope:      ci.Scope,
+		Collection: ci.Collection,
+	}
+}
+
+// collectionUID holds the most recently resolved UID of a collection.
+type collectionUID struct {
+	known atomic.Bool
+	uid   atomic.Uint32
+}
+
+func (u *collectionUID) set(uid uint32) {
+	if u == nil {
+		return
+	}
+
+	u.uid.Store(uid)
+	u.known.Store(true)
+}
+
+func (u *collectionUID) get() (uint32, bool) {
+	if u == nil || !u.known.Load() {
+		return 0, false
+	}
+
+	return u.uid.Load(), true
+}
+
+// Identity returns the identity of the collection. The default scope and collection are always reported using their
+// names, and the default collection is always known to have UID 0.
+// UNCOMMITTED: This API may change in the future.
+func (c *Collection) Identity() CollectionIdentity {
+	keyspace := Keyspace{
+		Bucket:     c.bucketName(),
+		Scope:      c.ScopeName(),
+		Collection: c.Name(),
+	}
+
+	identity := CollectionIdentity{
+		Bucket:     keyspace.Bucket,
+		Scope:      keyspace.scopeName(),
+		Collection: keyspace.collectionName(),
+	}
+	if c.isDefault() {
+		identity.UIDKnown = true
+	} else {
+		identity.UID, identity.UIDKnown = c.uid.get()
+	}
+
+	return identity
+}
+
+// Equal returns whether both collections refer to the same collection. Options applied to a copy of a collection,
+// such as WithSlidingExpiry, are not considered. If the UIDs of both collections are known then they must also match.
+// UNCOMMITTED: This API may change in the future.
+func (c *Collection) Equal(other *Collection) bool {
+	if c == other {
+		return true
+	}
+	if c == nil || other == nil {
+		return false
+	}
+
+	identity := c.Identity()
+	otherIdentity := other.Identity()
+	if identity.Keyspace() != otherIdentity.Keyspace() {
+		return false
+	}
+	if identity.UIDKnown && otherIdentity.UIDKnown {
+		return identity.UID == otherIdentity.UID
+	}
+
+	return true
+}
@@ -0,0 +1,550 @@
+package gocb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSubdocSpecs is the maximum number of specs which the server accepts in a single LookupIn or MutateIn.
+const maxSubdocSpecs = 16
+
+// JSONPatchOperation is a single operation within an RFC 6902 JSON Patch.
+// UNCOMMITTED: This API may change in the future.
+type JSONPatchOperation struct {
+	// Op is one of add, remove, replace, move, copy or test.
+	Op string `json:"op"`
+
+	// Path is the JSON Pointer, as specified by RFC 6901, of the location within the document to operate on.
+	Path string `json:"path"`
+
+	// From is the JSON Pointer of the source location for move and copy operations.
+	From string `json:"from,omitempty"`
+
+	// Value is the value to add or replace with, or to compare against for test operations.
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ParseJSONPatch parses an RFC 6902 JSON Patch document.
+// UNCOMMITTED: This API may change in the future.
+func ParseJSONPatch(data []byte) ([]JSONPatchOperation, error) {
+	var patch []JSONPatchOperation
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, makeInvalidArgumentsError(fmt.Sprintf("failed to parse json patch: %s", err))
+	}
+
+	return patch, nil
+}
+
+// ApplyJSONPatchOptions are the options available to the ApplyJSONPatch operation.
+// UNCOMMITTED: This API may change in the future.
+type ApplyJSONPatchOptions struct {
+	Cas             Cas
+	DurabilityLevel DurabilityLevel
+	PreserveExpiry  bool
+	Timeout         time.Duration
+	RetryStrategy   RetryStrategy
+	ParentSpan      RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// jsonPointerToSubdocPath converts an RFC 6901 JSON Pointer into a sub-document path. Tokens consisting only of
+// digits reference an element when the container they index into is an array and a member otherwise, isArray is
+// called with the pointer of the container to decide which. The "-" token is treated as the end of an array, and
+// isAppend is returned as true if the final token is "-", in which case the returned path is that of the array itself.
+func jsonPointerToSubdocPath(pointer string, isArray func(container string) (bool, error)) (path string, isAppend bool,
+	isIndex bool, err error) {
+	if pointer == "" {
+		return "", false, false, nil
+	}
+	if pointer[0] != '/' {
+		return "", false, false, makeInvalidArgumentsError(fmt.Sprintf("invalid json pointer %q", pointer))
+	}
+
+	rawTokens := strings.Split(pointer[1:], "/")
+	var sb strings.Builder
+	for i, rawToken := range rawTokens {
+		token := strings.ReplaceAll(strings.ReplaceAll(rawToken, "~1", "/"), "~0", "~")
+
+		if token == "-" {
+			if i != len(rawTokens)-1 {
+				return "", false, false, makeInvalidArgumentsError(fmt.Sprintf("invalid json pointer %q", pointer))
+			}
+			return sb.String(), true, false, nil
+		}
+
+		if isJSONPointerIndex(token) {
+			arr, err := isArray(jsonPointerPrefix(rawTokens, i))
+			if err != nil {
+				return "", false, false, err
+			}
+			if arr {
+				sb.WriteString("[" + token + "]")
+				isIndex = i == len(rawTokens)-1
+				continue
+			}
+		}
+
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+		if strings.ContainsAny(token, ".[]`") || token == "" || isJSONPointerIndex(token) {
+			sb.WriteString("`" + strings.ReplaceAll(token, "`", "``") + "`")
+		} else {
+			sb.WriteString(token)
+		}
+	}
+
+	return sb.String(), false, isIndex, nil
+}
+
+func isJSONPointerIndex(token string) bool {
+	_, err := strconv.ParseUint(token, 10, 32)
+	return err == nil
+}
+
+// jsonPointerPrefix returns the pointer made up of the first n of the raw, still escaped, tokens.
+func jsonPointerPrefix(rawTokens []string, n int) string {
+	if n == 0 {
+		return ""
+	}
+	return "/" + strings.Join(rawTokens[:n], "/")
+}
+
+// jsonPatchIndexedContainers returns the pointers of the containers which the patch indexes into using digit only
+// tokens, ordered so that a container is always listed after those that it is within.
+func jsonPatchIndexedContainers(patch []JSONPatchOperation) []string {
+	seen := make(map[string]struct{})
+	var containers []string
+	for _, op := range patch {
+		for _, pointer := range []string{op.Path, op.From} {
+			if pointer == "" || pointer[0] != '/' {
+				continue
+			}
+
+			rawTokens := strings.Split(pointer[1:], "/")
+			for i, rawToken := range rawTokens {
+				if !isJSONPointerIndex(rawToken) {
+					continue
+				}
+
+				container := jsonPointerPrefix(rawTokens, i)
+				if _, ok := seen[container]; !ok {
+					seen[container] = struct{}{}
+					containers = append(containers, container)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(containers, func(i, j int) bool {
+		return strings.Count(containers[i], "/") < strings.Count(containers[j], "/")
+	})
+
+	return containers
+}
+
+// documentContainerKinds returns a function which reports whether a container is an array using the kinds read
+// from the document, for pointers which are not modified by the patch.
+func documentContainerKinds(arrays map[string]bool) func(container string) (bool, error) {
+	return func(container string) (bool, error) {
+		return arrays[container], nil
+	}
+}
+
+// jsonPointerContains returns whether the location referenced by b is within, or is, the location referenced by a.
+func jsonPointerContains(a, b string) bool {
+	return a == b || strings.HasPrefix(b, a+"/")
+}
+
+// jsonPatchPlan is the result of planning a JSON Patch, specs are grouped so that the specs for a single patch
+// operation are never split across MutateIn calls.
+type jsonPatchPlan struct {
+	// reads are the pointers which must be read from the document before mutating it, for test, move and copy.
+	reads []string
+	// groups are the specs for each mutating patch operation, values for move and copy are filled once read.
+	groups [][]jsonPatchSpec
+	tests  []JSONPatchOperation
+}
+
+type jsonPatchSpec struct {
+	kind     string
+	path     string
+	value    json.RawMessage
+	readFrom string
+}
+
+func (s jsonPatchSpec) toMutateInSpec(values map[string]json.RawMessage) MutateInSpec {
+	value := s.value
+	if value == nil {
+		value = values[s.readFrom]
+	}
+
+	switch s.kind {
+	case "append":
+		return ArrayAppendSpec(s.path, value, nil)
+	case "insert":
+		return ArrayInsertSpec(s.path, value, nil)
+	case "upsert":
+		return UpsertSpec(s.path, value, nil)
+	case "replace":
+		return ReplaceSpec(s.path, value, nil)
+	default:
+		return RemoveSpec(s.path, nil)
+	}
+}
+
+// planJSONPatch compiles a JSON Patch into sub-document specs, producing a single spec for each add, remove, replace
+// and copy operation and two for each move. Values for test, move and copy are read from the document before any
+// mutations are applied, and so these may not reference a location modified by an earlier operation in the patch.
+func planJSONPatch(patch []JSONPatchOperation, arrays map[string]bool) (*jsonPatchPlan, error) {
+	plan := &jsonPatchPlan{}
+	var modified []string
+
+	// patched holds whether the containers written by earlier operations in the patch are arrays, these are known
+	// from the value written. Indexing into any other container modified earlier in the patch is rejected, as the
+	// kinds in arrays were read from the document before the patch is applied.
+	patched := make(map[string]bool)
+	isArray := func(container string) (bool, error) {
+		if arr, ok := patched[container]; ok {
+			return arr, nil
+		}
+		for _, mod := range modified {
+			if jsonPointerContains(mod, container) {
+				return false, makeInvalidArgumentsError(fmt.Sprintf(
+					"cannot index into %q as it is modified earlier in the patch", container))
+			}
+		}
+		return arrays[container], nil
+	}
+	setPatched := func(pointer string, value json.RawMessage) {
+		for container := range patched {
+			if jsonPointerContains(pointer, container) {
+				delete(patched, container)
+			}
+		}
+		if value != nil {
+			trimmed := bytes.TrimLeft(value, " \t\r\n")
+			patched[pointer] = len(trimmed) > 0 && trimmed[0] == '['
+		}
+	}
+
+	checkUnmodified := func(pointer string) error {
+		for _, mod := range modified {
+			if jsonPointerContains(mod, pointer) || jsonPointerContains(pointer, mod) {
+				return makeInvalidArgumentsError(fmt.Sprintf("cannot read %q as it is modified earlier in the patch",
+					pointer))
+			}
+		}
+		return nil
+	}
+
+	addRead := func(pointer string) {
+		for _, read := range plan.reads {
+			if read == pointer {
+				return
+			}
+		}
+		plan.reads = append(plan.reads, pointer)
+	}
+
+	addSpec := func(path string, isAppend, isIndex bool, value json.RawMessage, readFrom string) jsonPatchSpec {
+		switch {
+		case path == "":
+			// Adding to the root replaces the entire document.
+			return jsonPatchSpec{kind: "replace", value: value, readFrom: readFrom}
+		case isAppend:
+			return jsonPatchSpec{kind: "append", path: path, value: value, readFrom: readFrom}
+		case isIndex:
+			return jsonPatchSpec{kind: "insert", path: path, value: value, readFrom: readFrom}
+		default:
+			return jsonPatchSpec{kind: "upsert", path: path, value: value, readFrom: readFrom}
+		}
+	}
+
+	for _, op := range patch {
+		path, isAppend, isIndex, err := jsonPointerToSubdocPath(op.Path, isArray)
+		if err != nil {
+			return nil, err
+		}
+
+		if isAppend && op.Op != "add" && op.Op != "move" && op.Op != "copy" {
+			return nil, makeInvalidArgumentsError(fmt.Sprintf("%s cannot be used with path %q", op.Op, op.Path))
+		}
+
+		switch op.Op {
+		case "add":
+			if op.Value == nil {
+				return nil, makeInvalidArgumentsError("add operation requires a value")
+			}
+			plan.groups = append(plan.groups, []jsonPatchSpec{addSpec(path, isAppend, isIndex, op.Value, "")})
+		case "replace":
+			if op.Value == nil {
+				return nil, makeInvalidArgumentsError("replace operation requires a value")
+			}
+			plan.groups = append(plan.groups, []jsonPatchSpec{{kind: "replace", path: path, value: op.Value}})
+		case "remove":
+			if op.Path == "" {
+				return nil, makeInvalidArgumentsError("cannot remove the root of the document")
+			}
+			plan.groups = append(plan.groups, []jsonPatchSpec{{kind: "remove", path: path}})
+		case "copy", "move":
+			fromPath, fromAppend, _, err := jsonPointerToSubdocPath(op.From, isArray)
+			if err != nil {
+				return nil, err
+			}
+			if fromAppend || (op.Op == "move" && op.From == "") {
+				return nil, makeInvalidArgumentsError(fmt.Sprintf("%s cannot be used with from %q", op.Op, op.From))
+			}
+			if op.Op == "move" && jsonPointerContains(op.From, op.Path) && op.From != op.Path {
+				return nil, makeInvalidArgumentsError("cannot move a location into one of its children")
+			}
+			if err := checkUnmodified(op.From); err != nil {
+				return nil, err
+			}
+			addRead(op.From)
+
+			var group []jsonPatchSpec
+			if op.Op == "move" {
+				if op.From == op.Path {
+					// Moving a value onto itself is a no-op.
+					break
+				}
+				group = append(group, jsonPatchSpec{kind: "remove", path: fromPath})
+				modified = append(modified, op.From)
+				setPatched(op.From, nil)
+			}
+			group = append(group, addSpec(path, isAppend, isIndex, nil, op.From))
+			plan.groups = append(plan.groups, group)
+		case "test":
+			if err := checkUnmodified(op.Path); err != nil {
+				return nil, err
+			}
+			addRead(op.Path)
+			plan.tests = append(plan.tests, op)
+			continue
+		default:
+			return nil, makeInvalidArgumentsError(fmt.Sprintf("unsupported json patch operation %q", op.Op))
+		}
+
+		if op.Op == "add" || op.Op == "replace" {
+			setPatched(op.Path, op.Value)
+		} else {
+			setPatched(op.Path, nil)
+		}
+		modified = append(modified, op.Path)
+	}
+
+	return plan, nil
+}
+
+// chunks splits the planned specs into batches of at most maxSubdocSpecs specs.
+func (p *jsonPatchPlan) chunks(values map[string]json.RawMessage) [][]MutateInSpec {
+	var chunks [][]MutateInSpec
+	var current []MutateInSpec
+	for _, group := range p.groups {
+		if len(current)+len(group) > maxSubdocSpecs {
+			chunks = append(chunks, current)
+			current = nil
+		}
+		for _, spec := range group {
+			current = append(current, spec.toMutateInSpec(values))
+		}
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+
+	return chunks
+}
+
+// jsonPatchLookupIn performs the lookups in batches of at most maxSubdocSpecs specs, returning the result of each.
+// All of the batches must observe the same CAS, which must match cas if it is not 0.
+func (c *Collection) jsonPatchLookupIn(id string, specs []LookupInSpec, cas Cas,
+	opts *ApplyJSONPatchOptions) ([]lookupInPartial, Cas, error) {
+	contents := make([]lookupInPartial, 0, len(specs))
+	for start := 0; start < len(specs); start += maxSubdocSpecs {
+		end := start + maxSubdocSpecs
+		if end > len(specs) {
+			end = len(specs)
+		}
+
+		res, err := c.LookupIn(id, specs[start:end], &LookupInOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		if cas != 0 && res.Cas() != cas {
+			return nil, 0, ErrCasMismatch
+		}
+		cas = res.Cas()
+
+		contents = append(contents, res.contents...)
+	}
+
+	return contents, cas, nil
+}
+
+// readJSONPatchContainers reads whether each of the containers is an array, returning the result keyed by pointer.
+// Containers are read a level at a time, as the path of a container depends on the kinds of those that it is within.
+// Checking for the last element of a container fails with a path mismatch if the container is not an array, so the
+// contents of the containers are never read.
+func (c *Collection) readJSONPatchContainers(id string, containers []string, cas Cas,
+	opts *ApplyJSONPatchOptions) (map[string]bool, Cas, error) {
+	arrays := make(map[string]bool, len(containers))
+	for start := 0; start < len(containers); {
+		depth := strings.Count(containers[start], "/")
+		end := start
+		for end < len(containers) && strings.Count(containers[end], "/") == depth {
+			end++
+		}
+
+		specs := make([]LookupInSpec, 0, end-start)
+		for _, container := range containers[start:end] {
+			path, isAppend, _, err := jsonPointerToSubdocPath(container, documentContainerKinds(arrays))
+			if err != nil {
+				return nil, 0, err
+			}
+			if isAppend {
+				return nil, 0, makeInvalidArgumentsError(fmt.Sprintf("invalid json pointer %q", container))
+			}
+			specs = append(specs, ExistsSpec(path+"[-1]", nil))
+		}
+
+		contents, readCas, err := c.jsonPatchLookupIn(id, specs, cas, opts)
+		if err != nil {
+			return nil, 0, err
+		}
+		cas = readCas
+
+		for i, container := range containers[start:end] {
+			err := contents[i].as(nil)
+			if err != nil && !errors.Is(err, ErrPathMismatch) {
+				return nil, 0, err
+			}
+			arrays[container] = err == nil
+		}
+
+		start = end
+	}
+
+	return arrays, cas, nil
+}
+
+// readJSONPatchValues reads the values at pointers from the document, returning them keyed by pointer.
+func (c *Collection) readJSONPatchValues(id string, pointers []string, arrays map[string]bool, cas Cas,
+	opts *ApplyJSONPatchOptions) (map[string]json.RawMessage, Cas, error) {
+	specs := make([]LookupInSpec, 0, len(pointers))
+	for _, pointer := range pointers {
+		path, _, _, err := jsonPointerToSubdocPath(pointer, documentContainerKinds(arrays))
+		if err != nil {
+			return nil, 0, err
+		}
+		specs = append(specs, GetSpec(path, nil))
+	}
+
+	contents, cas, err := c.jsonPatchLookupIn(id, specs, cas, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	values := make(map[string]json.RawMessage, len(pointers))
+	for i, pointer := range pointers {
+		var value json.RawMessage
+		if err := contents[i].as(&value); err != nil {
+			return nil, 0, err
+		}
+		values[pointer] = value
+	}
+
+	return values, cas, nil
+}
+
+// ApplyJSONPatch applies an RFC 6902 JSON Patch to a document using sub-document operations, so that only the
+// modified parts of the document are sent to the server. Digit only tokens within paths are treated as array
+// indexes when the container they index into is an array, and as member names otherwise.
+//
+// Patches containing digit only tokens first read whether the containers they index into are arrays, and patches
+// containing test, move or copy operations first read the values needed from the document. The mutations are then
+// applied using the CAS of these reads. A test operation which does not match fails with
+// ErrJSONPatchTestFailed. Patches needing more than 16 sub-document operations are applied across multiple MutateIn
+// calls, each using the CAS of the previous so that no other mutation can be interleaved, however if a later call
+// fails then the earlier calls will already have been applied.
+// UNCOMMITTED: This API may change in the future.
+func (c *Collection) ApplyJSONPatch(id string, patch []JSONPatchOperation, opts *ApplyJSONPatchOptions) (*MutationResult, error) {
+	if opts == nil {
+		opts = &ApplyJSONPatchOptions{}
+	}
+
+	cas := opts.Cas
+	arrays := make(map[string]bool)
+	if containers := jsonPatchIndexedContainers(patch); len(containers) > 0 {
+		var err error
+		arrays, cas, err = c.readJSONPatchContainers(id, containers, cas, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	plan, err := planJSONPatch(patch, arrays)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]json.RawMessage
+	if len(plan.reads) > 0 {
+		values, cas, err = c.readJSONPatchValues(id, plan.reads, arrays, cas, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, test := range plan.tests {
+			var expected, actual interface{}
+			if err := json.Unmarshal(test.Value, &expected); err != nil {
+				return nil, makeInvalidArgumentsError(fmt.Sprintf("invalid value for test of %q", test.Path))
+			}
+			if err := json.Unmarshal(values[test.Path], &actual); err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(expected, actual) {
+				return nil, makeGenericError(ErrJSONPatchTestFailed, map[string]interface{}{"path": test.Path})
+			}
+		}
+	}
+
+	result := &MutationResult{Result: Result{cas: cas}}
+	for _, specs := range plan.chunks(values) {
+		res, err := c.MutateIn(id, specs, &MutateInOptions{
+			Cas:             cas,
+			DurabilityLevel: opts.DurabilityLevel,
+			PreserveExpiry:  opts.PreserveExpiry,
+			StoreSemantic:   StoreSemanticsReplace,
+			Timeout:         opts.Timeout,
+			RetryStrategy:   opts.RetryStrategy,
+			ParentSpan:      opts.ParentSpan,
+			Context:         opts.Context,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		cas = res.Cas()
+		result = &res.MutationResult
+	}
+
+	return result, nil
+}
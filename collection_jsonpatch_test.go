@@ -0,0 +1,268 @@
+package gocb
+
+import (
+	"encoding/json"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/couchbase/gocbcore/v10/memd"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) TestJSONPointerToSubdocPath() {
+	isArray := documentContainerKinds(map[string]bool{"/tags": true, "/matrix": true, "/matrix/1": true})
+
+	type tCase struct {
+		pointer  string
+		path     string
+		isAppend bool
+		isIndex  bool
+	}
+	for _, tc := range []tCase{
+		{pointer: "", path: ""},
+		{pointer: "/name", path: "name"},
+		{pointer: "/address/city", path: "address.city"},
+		{pointer: "/tags/2", path: "tags[2]", isIndex: true},
+		{pointer: "/tags/2/name", path: "tags[2].name"},
+		{pointer: "/tags/-", path: "tags", isAppend: true},
+		{pointer: "/a~1b/c~0d", path: "a/b.c~d"},
+		{pointer: "/dotted.key/x", path: "`dotted.key`.x"},
+		{pointer: "/matrix/1/0", path: "matrix[1][0]", isIndex: true},
+		{pointer: "/ratings/5", path: "ratings.`5`"},
+		{pointer: "/ratings/5/count", path: "ratings.`5`.count"},
+		{pointer: "/0", path: "`0`"},
+	} {
+		path, isAppend, isIndex, err := jsonPointerToSubdocPath(tc.pointer, isArray)
+		suite.Require().Nil(err, err)
+		suite.Assert().Equal(tc.path, path, tc.pointer)
+		suite.Assert().Equal(tc.isAppend, isAppend, tc.pointer)
+		suite.Assert().Equal(tc.isIndex, isIndex, tc.pointer)
+	}
+
+	_, _, _, err := jsonPointerToSubdocPath("name", isArray)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	_, _, _, err = jsonPointerToSubdocPath("/tags/-/name", isArray)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestJSONPatchIndexedContainers() {
+	suite.Assert().Equal([]string{"", "/matrix", "/tags", "/matrix/1"}, jsonPatchIndexedContainers([]JSONPatchOperation{
+		{Op: "add", Path: "/matrix/1/0"},
+		{Op: "remove", Path: "/tags/2"},
+		{Op: "move", From: "/0", Path: "/name"},
+		{Op: "add", Path: "/tags/-"},
+	}))
+	suite.Assert().Empty(jsonPatchIndexedContainers([]JSONPatchOperation{{Op: "add", Path: "/tags/-"}}))
+}
+
+func (suite *UnitTestSuite) TestPlanJSONPatchRejectsReadAfterModify() {
+	patch, err := ParseJSONPatch([]byte(`[
+		{"op": "replace", "path": "/address", "value": {}},
+		{"op": "test", "path": "/address/city", "value": "London"}
+	]`))
+	suite.Require().Nil(err, err)
+
+	_, err = planJSONPatch(patch, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	_, err = planJSONPatch([]JSONPatchOperation{{Op: "increment", Path: "/count"}}, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestPlanJSONPatchPatchedContainers() {
+	// The kind of a container written earlier in the patch comes from the value written, rather than the document.
+	plan, err := planJSONPatch([]JSONPatchOperation{
+		{Op: "add", Path: "/scores", Value: json.RawMessage(` ["a"]`)},
+		{Op: "add", Path: "/scores/0", Value: json.RawMessage(`"b"`)},
+		{Op: "replace", Path: "/ratings", Value: json.RawMessage(`{}`)},
+		{Op: "add", Path: "/ratings/5", Value: json.RawMessage(`1`)},
+	}, map[string]bool{"/ratings": true})
+	suite.Require().Nil(err, err)
+
+	var specs []jsonPatchSpec
+	for _, group := range plan.groups {
+		specs = append(specs, group...)
+	}
+	suite.Require().Len(specs, 4)
+	suite.Assert().Equal(jsonPatchSpec{kind: "insert", path: "scores[0]", value: json.RawMessage(`"b"`)}, specs[1])
+	suite.Assert().Equal(jsonPatchSpec{kind: "upsert", path: "ratings.`5`", value: json.RawMessage(`1`)}, specs[3])
+
+	// The kind of a container which was moved or removed earlier in the patch is not known.
+	_, err = planJSONPatch([]JSONPatchOperation{
+		{Op: "move", From: "/old", Path: "/scores"},
+		{Op: "add", Path: "/scores/0", Value: json.RawMessage(`1`)},
+	}, map[string]bool{"/scores": true})
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestApplyJSONPatch() {
+	patch, err := ParseJSONPatch([]byte(`[
+		{"op": "test", "path": "/version", "value": 1},
+		{"op": "add", "path": "/tags/-", "value": "new"},
+		{"op": "add", "path": "/tags/0", "value": "first"},
+		{"op": "add", "path": "/ratings/5", "value": 10},
+		{"op": "add", "path": "/address/city", "value": "London"},
+		{"op": "replace", "path": "/version", "value": 2},
+		{"op": "remove", "path": "/legacy"},
+		{"op": "move", "from": "/oldName", "path": "/name"},
+		{"op": "copy", "from": "/owner", "path": "/createdBy"}
+	]`))
+	suite.Require().Nil(err, err)
+
+	pendingOp := new(mockPendingOp)
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("LookupIn", mock.AnythingOfType("gocbcore.LookupInOptions"), mock.AnythingOfType("gocbcore.LookupInCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.LookupInOptions)
+			cb := args.Get(1).(gocbcore.LookupInCallback)
+
+			suite.Require().Len(opts.Ops, 2)
+			suite.Assert().Equal(memd.SubDocOpExists, opts.Ops[0].Op)
+			suite.Assert().Equal("tags[-1]", opts.Ops[0].Path)
+			suite.Assert().Equal("ratings[-1]", opts.Ops[1].Path)
+
+			cb(&gocbcore.LookupInResult{
+				Cas: gocbcore.Cas(100),
+				Ops: []gocbcore.SubDocResult{
+					{},
+					{Err: gocbcore.ErrPathMismatch},
+				},
+			}, nil)
+		}).
+		Return(pendingOp, nil).
+		Once()
+	provider.
+		On("LookupIn", mock.AnythingOfType("gocbcore.LookupInOptions"), mock.AnythingOfType("gocbcore.LookupInCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.LookupInOptions)
+			cb := args.Get(1).(gocbcore.LookupInCallback)
+
+			suite.Require().Len(opts.Ops, 3)
+			suite.Assert().Equal("version", opts.Ops[0].Path)
+			suite.Assert().Equal("oldName", opts.Ops[1].Path)
+			suite.Assert().Equal("owner", opts.Ops[2].Path)
+
+			cb(&gocbcore.LookupInResult{
+				Cas: gocbcore.Cas(100),
+				Ops: []gocbcore.SubDocResult{
+					{Value: []byte(`1`)},
+					{Value: []byte(`"Brewery"`)},
+					{Value: []byte(`{"id":"u1"}`)},
+				},
+			}, nil)
+		}).
+		Return(pendingOp, nil).
+		Once()
+	provider.
+		On("MutateIn", mock.AnythingOfType("gocbcore.MutateInOptions"), mock.AnythingOfType("gocbcore.MutateInCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.MutateInOptions)
+			cb := args.Get(1).(gocbcore.MutateInCallback)
+
+			suite.Assert().Equal(gocbcore.Cas(100), opts.Cas)
+
+			type op struct {
+				Op    memd.SubDocOpType
+				Path  string
+				Value string
+			}
+			var ops []op
+			for _, o := range opts.Ops {
+				ops = append(ops, op{Op: o.Op, Path: o.Path, Value: string(o.Value)})
+			}
+			suite.Assert().Equal([]op{
+				{Op: memd.SubDocOpArrayPushLast, Path: "tags", Value: `"new"`},
+				{Op: memd.SubDocOpArrayInsert, Path: "tags[0]", Value: `"first"`},
+				{Op: memd.SubDocOpDictSet, Path: "ratings.`5`", Value: `10`},
+				{Op: memd.SubDocOpDictSet, Path: "address.city", Value: `"London"`},
+				{Op: memd.SubDocOpReplace, Path: "version", Value: `2`},
+				{Op: memd.SubDocOpDelete, Path: "legacy"},
+				{Op: memd.SubDocOpDelete, Path: "oldName"},
+				{Op: memd.SubDocOpDictSet, Path: "name", Value: `"Brewery"`},
+				{Op: memd.SubDocOpDictSet, Path: "createdBy", Value: `{"id":"u1"}`},
+			}, ops)
+
+			cb(&gocbcore.MutateInResult{
+				Cas: gocbcore.Cas(101),
+				Ops: make([]gocbcore.SubDocResult, len(opts.Ops)),
+			}, nil)
+		}).
+		Return(pendingOp, nil).
+		Once()
+
+	col := suite.collection("mock", "", "", suite.kvProviderCore(provider, nil))
+
+	res, err := col.ApplyJSONPatch("someid", patch, nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(Cas(101), res.Cas())
+	provider.AssertExpectations(suite.T())
+}
+
+func (suite *UnitTestSuite) TestApplyJSONPatchTestFailed() {
+	pendingOp := new(mockPendingOp)
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("LookupIn", mock.AnythingOfType("gocbcore.LookupInOptions"), mock.AnythingOfType("gocbcore.LookupInCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(1).(gocbcore.LookupInCallback)
+			cb(&gocbcore.LookupInResult{
+				Cas: gocbcore.Cas(100),
+				Ops: []gocbcore.SubDocResult{{Value: []byte(`{"b":2,"a":1}`)}},
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	col := suite.collection("mock", "", "", suite.kvProviderCore(provider, nil))
+
+	// Objects are compared by value rather than by their encoding.
+	_, err := col.ApplyJSONPatch("someid", []JSONPatchOperation{
+		{Op: "test", Path: "/obj", Value: json.RawMessage(`{"a":1,"b":2}`)},
+	}, nil)
+	suite.Require().Nil(err, err)
+
+	_, err = col.ApplyJSONPatch("someid", []JSONPatchOperation{
+		{Op: "test", Path: "/obj", Value: json.RawMessage(`{"a":1}`)},
+		{Op: "remove", Path: "/obj"},
+	}, nil)
+	suite.Assert().ErrorIs(err, ErrJSONPatchTestFailed)
+
+	provider.AssertNotCalled(suite.T(), "MutateIn", mock.Anything, mock.Anything)
+}
+
+func (suite *UnitTestSuite) TestApplyJSONPatchChunked() {
+	var patch []JSONPatchOperation
+	for i := 0; i < 20; i++ {
+		patch = append(patch, JSONPatchOperation{Op: "add", Path: "/items/-", Value: json.RawMessage(`1`)})
+	}
+
+	var chunkSizes []int
+	var chunkCas []gocbcore.Cas
+	pendingOp := new(mockPendingOp)
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("MutateIn", mock.AnythingOfType("gocbcore.MutateInOptions"), mock.AnythingOfType("gocbcore.MutateInCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.MutateInOptions)
+			cb := args.Get(1).(gocbcore.MutateInCallback)
+
+			chunkSizes = append(chunkSizes, len(opts.Ops))
+			chunkCas = append(chunkCas, opts.Cas)
+
+			cb(&gocbcore.MutateInResult{
+				Cas: opts.Cas + 1,
+				Ops: make([]gocbcore.SubDocResult, len(opts.Ops)),
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	col := suite.collection("mock", "", "", suite.kvProviderCore(provider, nil))
+
+	res, err := col.ApplyJSONPatch("someid", patch, &ApplyJSONPatchOptions{Cas: 50})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal([]int{16, 4}, chunkSizes)
+	suite.Assert().Equal([]gocbcore.Cas{50, 51}, chunkCas)
+	suite.Assert().Equal(Cas(52), res.Cas())
+}
@@ -0,0 +1,59 @@
+package gocb
+
+import (
+	"context"
+	"time"
+)
+
+// GroupKeysByNodeOptions are the options available to the GroupKeysByNode operation.
+type GroupKeysByNodeOptions struct {
+	Timeout time.Duration
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// NodeKeyGroup is a set of keys which are all owned by the same node.
+type NodeKeyGroup struct {
+	// NodeIndex is the index of the node, within the cluster configuration, which is currently active for
+	// all of the keys in this group. A NodeIndex of -1 indicates that the keys do not currently have an active node,
+	// this can occur during a rebalance or failover.
+	NodeIndex int
+
+	// Keys are the keys which belong to this node, in the order in which they were provided.
+	Keys []string
+}
+
+// GroupKeysResult is the result of a GroupKeysByNode operation.
+type GroupKeysResult struct {
+	revID  int64
+	groups []NodeKeyGroup
+}
+
+// Groups returns the key groups, ordered by node index.
+func (r *GroupKeysResult) Groups() []NodeKeyGroup {
+	return r.groups
+}
+
+// ConfigRevision returns the revision of the cluster configuration used to group the keys. Node indexes are only
+// meaningful within a single configuration revision.
+func (r *GroupKeysResult) ConfigRevision() int64 {
+	return r.revID
+}
+
+// GroupKeysByNode groups keys by the node which is currently active for each key, using the same key hashing
+// as the SDK uses to route operations. This allows batch oriented applications to build node local batches and
+// reduce cross-node fan-out. Note that the cluster topology can change at any time and so the grouping is
+// only a hint, operations will always be routed correctly regardless.
+// UNCOMMITTED: This API may change in the future.
+func (c *Collection) GroupKeysByNode(keys []string, opts *GroupKeysByNodeOptions) (*GroupKeysResult, error) {
+	return autoOpControl(c.kvController(), "group_keys_by_node", func(agent kvProvider) (*GroupKeysResult, error) {
+		if opts == nil {
+			opts = &GroupKeysByNodeOptions{}
+		}
+
+		return agent.GroupKeysByNode(c, keys, opts)
+	})
+}
@@ -0,0 +1,40 @@
+package gocb
+
+func (suite *UnitTestSuite) TestGroupKeysByNode() {
+	snapshot := newMockConfigSnapshot(8, 2)
+	snapshot.revID = 7
+	snapshot.keyVbuckets = map[string]uint16{
+		"key1": 5,
+		"key2": 1,
+		"key3": 6,
+		"key4": 2,
+	}
+	// vbucket 7 has no active node.
+	snapshot.serverToVbuckets[1] = []uint16{4, 5, 6}
+	snapshot.keyVbuckets["key5"] = 7
+
+	agent := suite.kvProviderCore(new(mockKvProviderCoreProvider), &mockConfigSnapshotProvider{snapshot: snapshot})
+	col := suite.collection("mock", "", "", agent)
+
+	opts := &GroupKeysByNodeOptions{}
+	res, err := col.GroupKeysByNode([]string{"key1", "key2", "key3", "key4", "key5"}, opts)
+	suite.Require().Nil(err, err)
+	suite.Assert().Zero(opts.Timeout)
+
+	suite.Assert().Equal(int64(7), res.ConfigRevision())
+	suite.Assert().Equal([]NodeKeyGroup{
+		{NodeIndex: -1, Keys: []string{"key5"}},
+		{NodeIndex: 0, Keys: []string{"key2", "key4"}},
+		{NodeIndex: 1, Keys: []string{"key1", "key3"}},
+	}, res.Groups())
+}
+
+func (suite *UnitTestSuite) TestGroupKeysByNodeMemcachedBucket() {
+	snapshot := newMockConfigSnapshot(0, 1)
+
+	agent := suite.kvProviderCore(new(mockKvProviderCoreProvider), &mockConfigSnapshotProvider{snapshot: snapshot})
+	col := suite.collection("mock", "", "", agent)
+
+	_, err := col.GroupKeysByNode([]string{"key1"}, nil)
+	suite.Require().ErrorIs(err, ErrInvalidArgument)
+}
@@ -20,8 +20,7 @@ func (qm *CollectionQueryIndexManager) validateScopeCollection(scope, collection
 }
 
 // CreateIndex creates an index over the specified fields.
-// The SDK will automatically escape the provided index keys. For more advanced use cases like index keys using keywords
-// scope.Query should be used with the query directly.
+// The SDK will automatically escape the provided index keys, unless RawKeys is set in the options.
 func (qm *CollectionQueryIndexManager) CreateIndex(indexName string, keys []string, opts *CreateQueryIndexOptions) error {
 	return autoOpControlErrorOnly(qm.controller, "manager_query_create_index", func(provider queryIndexProvider) error {
 		if opts == nil {
@@ -137,3 +136,25 @@ func (qm *CollectionQueryIndexManager) WatchIndexes(watchList []string, timeout
 		return provider.WatchIndexes(qm.c, "", watchList, timeout, opts)
 	})
 }
+
+// GetIndexStats returns the statistics reported by the index service for an index on the collection. The statistics
+// are gathered from every index node and keyed by the index instance they refer to, as each replica and partition
+// of an index is reported separately.
+// UNCOMMITTED: This API may change in the future.
+func (qm *CollectionQueryIndexManager) GetIndexStats(indexName string, opts *GetQueryIndexStatsOptions) (map[string]interface{}, error) {
+	return autoOpControl(qm.controller, "manager_query_get_index_stats", func(provider queryIndexProvider) (map[string]interface{}, error) {
+		if indexName == "" {
+			return nil, makeInvalidArgumentsError("index name cannot be empty")
+		}
+
+		if opts == nil {
+			opts = &GetQueryIndexStatsOptions{}
+		}
+
+		return provider.GetIndexStats(Keyspace{
+			Bucket:     qm.c.bucketName(),
+			Scope:      qm.c.ScopeName(),
+			Collection: qm.c.Name(),
+		}, indexName, opts)
+	})
+}
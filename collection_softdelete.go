@@ -0,0 +1,174 @@
+package gocb
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultSoftDeletePath is the path used to store the soft delete tombstone when no path is specified.
+const DefaultSoftDeletePath = "softDeletedAt"
+
+// SoftDeleteMarker specifies where within a document the soft delete tombstone is stored. The tombstone is the time at
+// which the document was soft deleted, formatted as RFC3339, and its presence marks the document as soft deleted.
+// UNCOMMITTED: This API may change in the future.
+type SoftDeleteMarker struct {
+	// Path is the path of the tombstone, defaults to DefaultSoftDeletePath.
+	Path string
+
+	// IsXattr specifies that the tombstone is stored within an extended attribute rather than the document body.
+	IsXattr bool
+}
+
+func (m SoftDeleteMarker) path() string {
+	if m.Path == "" {
+		return DefaultSoftDeletePath
+	}
+
+	return m.Path
+}
+
+// SoftRemoveOptions are the options available to the SoftRemove operation.
+// UNCOMMITTED: This API may change in the future.
+type SoftRemoveOptions struct {
+	Marker SoftDeleteMarker
+
+	// PurgeAfter sets the expiry of the document so that it is removed by the server once this duration has elapsed.
+	// If zero then the document is retained, and any existing expiry is removed.
+	PurgeAfter time.Duration
+
+	Cas             Cas
+	DurabilityLevel DurabilityLevel
+	Timeout         time.Duration
+	RetryStrategy   RetryStrategy
+	ParentSpan      RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// SoftRemove marks a document as deleted by writing a tombstone into it, rather than removing it from the collection.
+// Soft deleted documents can be retrieved using GetIncludingSoftDeleted.
+// UNCOMMITTED: This API may change in the future.
+func (c *Collection) SoftRemove(id string, opts *SoftRemoveOptions) (*MutationResult, error) {
+	if opts == nil {
+		opts = &SoftRemoveOptions{}
+	}
+
+	now := time.Now
+	if c.timeSource != nil {
+		now = c.timeSource
+	}
+
+	tombstone := now().UTC().Format(time.RFC3339Nano)
+	res, err := c.MutateIn(id, []MutateInSpec{
+		UpsertSpec(opts.Marker.path(), tombstone, &UpsertSpecOptions{
+			CreatePath: true,
+			IsXattr:    opts.Marker.IsXattr,
+		}),
+	}, &MutateInOptions{
+		Expiry:          opts.PurgeAfter,
+		Cas:             opts.Cas,
+		DurabilityLevel: opts.DurabilityLevel,
+		StoreSemantic:   StoreSemanticsReplace,
+		Timeout:         opts.Timeout,
+		RetryStrategy:   opts.RetryStrategy,
+		ParentSpan:      opts.ParentSpan,
+		Context:         opts.Context,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &res.MutationResult, nil
+}
+
+// GetIncludingSoftDeletedOptions are the options available to the GetIncludingSoftDeleted operation.
+// UNCOMMITTED: This API may change in the future.
+type GetIncludingSoftDeletedOptions struct {
+	Marker SoftDeleteMarker
+
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+}
+
+// GetSoftDeletedResult is the return type of GetIncludingSoftDeleted operations.
+// UNCOMMITTED: This API may change in the future.
+type GetSoftDeletedResult struct {
+	GetResult
+
+	softDeleted   bool
+	softDeletedAt time.Time
+}
+
+// IsSoftDeleted returns whether the document has been soft deleted.
+func (r *GetSoftDeletedResult) IsSoftDeleted() bool {
+	return r.softDeleted
+}
+
+// SoftDeletedAt returns the time at which the document was soft deleted, or a zero time if it has not been.
+func (r *GetSoftDeletedResult) SoftDeletedAt() time.Time {
+	return r.softDeletedAt
+}
+
+// GetIncludingSoftDeleted fetches a JSON document from the collection whether or not it has been soft deleted, along
+// with its soft delete state.
+// UNCOMMITTED: This API may change in the future.
+func (c *Collection) GetIncludingSoftDeleted(id string, opts *GetIncludingSoftDeletedOptions) (*GetSoftDeletedResult, error) {
+	if opts == nil {
+		opts = &GetIncludingSoftDeletedOptions{}
+	}
+
+	res, err := c.LookupIn(id, []LookupInSpec{
+		GetSpec(opts.Marker.path(), &GetSpecOptions{IsXattr: opts.Marker.IsXattr}),
+		GetSpec("", nil),
+	}, &LookupInOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var contents []byte
+	if err := res.ContentAt(1, &contents); err != nil {
+		return nil, err
+	}
+
+	result := &GetSoftDeletedResult{
+		GetResult: GetResult{
+			Result:     res.Result,
+			transcoder: NewJSONTranscoder(),
+			flags:      jsonCommonFlags,
+			contents:   contents,
+		},
+	}
+
+	var tombstone interface{}
+	if err := res.ContentAt(0, &tombstone); err != nil {
+		if errors.Is(err, ErrPathNotFound) {
+			return result, nil
+		}
+
+		return nil, err
+	}
+
+	result.softDeleted = true
+	// The tombstone may have been written by another implementation so a value which is not a timestamp does not
+	// prevent the document from being treated as soft deleted.
+	if tombstoneStr, ok := tombstone.(string); ok {
+		if deletedAt, err := time.Parse(time.RFC3339Nano, tombstoneStr); err == nil {
+			result.softDeletedAt = deletedAt
+		}
+	}
+
+	return result, nil
+}
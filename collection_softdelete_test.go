@@ -0,0 +1,112 @@
+package gocb
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/couchbase/gocbcore/v10/memd"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) TestCollectionSoftRemove() {
+	pendingOp := new(mockPendingOp)
+
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("MutateIn", mock.AnythingOfType("gocbcore.MutateInOptions"), mock.AnythingOfType("gocbcore.MutateInCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.MutateInOptions)
+			cb := args.Get(1).(gocbcore.MutateInCallback)
+
+			suite.Require().Len(opts.Ops, 1)
+			suite.Assert().Equal(memd.SubDocOpDictSet, opts.Ops[0].Op)
+			suite.Assert().Equal("deleted", opts.Ops[0].Path)
+			suite.Assert().Equal(memd.SubdocFlagXattrPath, opts.Ops[0].Flags&memd.SubdocFlagXattrPath)
+			suite.Assert().Equal(memd.SubdocFlagMkDirP, opts.Ops[0].Flags&memd.SubdocFlagMkDirP)
+			suite.Assert().Equal(uint32(24*time.Hour/time.Second), opts.Expiry)
+			suite.Assert().Equal(gocbcore.Cas(123), opts.Cas)
+
+			var tombstone string
+			suite.Require().Nil(json.Unmarshal(opts.Ops[0].Value, &tombstone))
+			suite.Assert().Equal("2024-01-02T03:04:05.5Z", tombstone)
+
+			cb(&gocbcore.MutateInResult{
+				Cas: gocbcore.Cas(456),
+				Ops: []gocbcore.SubDocResult{{}},
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	agent := suite.kvProviderCore(provider, nil)
+	col := suite.collection("mock", "", "", agent)
+	col.timeSource = func() time.Time {
+		return time.Date(2024, 1, 2, 4, 4, 5, 500000000, time.FixedZone("BST", 3600))
+	}
+
+	res, err := col.SoftRemove("someid", &SoftRemoveOptions{
+		Marker:     SoftDeleteMarker{Path: "deleted", IsXattr: true},
+		PurgeAfter: 24 * time.Hour,
+		Cas:        123,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(Cas(456), res.Cas())
+}
+
+func (suite *UnitTestSuite) TestCollectionGetIncludingSoftDeleted() {
+	pendingOp := new(mockPendingOp)
+
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("LookupIn", mock.AnythingOfType("gocbcore.LookupInOptions"), mock.AnythingOfType("gocbcore.LookupInCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.LookupInOptions)
+			cb := args.Get(1).(gocbcore.LookupInCallback)
+
+			suite.Require().Len(opts.Ops, 2)
+			suite.Assert().Equal(DefaultSoftDeletePath, opts.Ops[0].Path)
+			suite.Assert().Equal(memd.SubDocOpGetDoc, opts.Ops[1].Op)
+
+			if string(opts.Key) == "deleted" {
+				cb(&gocbcore.LookupInResult{
+					Cas: gocbcore.Cas(123),
+					Ops: []gocbcore.SubDocResult{
+						{Value: []byte(`"2024-05-01T10:00:00Z"`)},
+						{Value: []byte(`{"name":"test","softDeletedAt":"2024-05-01T10:00:00Z"}`)},
+					},
+				}, nil)
+				return
+			}
+
+			cb(&gocbcore.LookupInResult{
+				Cas: gocbcore.Cas(124),
+				Ops: []gocbcore.SubDocResult{
+					{Err: gocbcore.ErrPathNotFound},
+					{Value: []byte(`{"name":"test"}`)},
+				},
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	agent := suite.kvProviderCore(provider, nil)
+	col := suite.collection("mock", "", "", agent)
+
+	res, err := col.GetIncludingSoftDeleted("deleted", nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().True(res.IsSoftDeleted())
+	suite.Assert().Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), res.SoftDeletedAt())
+	suite.Assert().Equal(Cas(123), res.Cas())
+
+	var doc map[string]interface{}
+	suite.Require().Nil(res.Content(&doc))
+	suite.Assert().Equal("test", doc["name"])
+
+	res, err = col.GetIncludingSoftDeleted("live", nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().False(res.IsSoftDeleted())
+	suite.Assert().True(res.SoftDeletedAt().IsZero())
+	suite.Assert().Equal(Cas(124), res.Cas())
+}
@@ -146,6 +146,10 @@ func (c *Collection) MutateIn(id string, ops []MutateInSpec, opts *MutateInOptio
 			opts = &MutateInOptions{}
 		}
 
+		if err := c.checkMutationAllowed(); err != nil {
+			return nil, err
+		}
+
 		return agent.MutateIn(c, id, ops, opts)
 	})
 }
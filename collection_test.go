@@ -1,5 +1,13 @@
 package gocb
 
+import (
+	"context"
+	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/stretchr/testify/mock"
+)
+
 func (suite *UnitTestSuite) TestCollectionName() {
 	bName := "bucket"
 	sName := "scope"
@@ -36,3 +44,183 @@ func (suite *UnitTestSuite) TestDefaultScopeDefaultCollectionName() {
 	suite.Assert().Equal("_default", c.ScopeName())
 	suite.Assert().Equal("_default", c.Name())
 }
+
+func (suite *UnitTestSuite) TestCollectionIsDefault() {
+	b := suite.bucket("bucket", suite.defaultTimeoutConfig(), nil)
+
+	suite.Assert().True(b.DefaultCollection().IsDefault())
+	suite.Assert().False(b.Collection("collection").IsDefault())
+	suite.Assert().False(b.Scope("scope").Collection(DefaultCollectionName).IsDefault())
+	suite.Assert().Equal(SystemScopeName, b.SystemScope().Name())
+}
+
+func (suite *UnitTestSuite) TestSystemScopeMutationRefused() {
+	provider := new(mockKvProviderCoreProvider)
+	agent := suite.kvProviderCore(provider, &mockConfigSnapshotProvider{snapshot: &mockConfigSnapshot{}})
+
+	col := suite.collection("mock", SystemScopeName, "_mobile", agent)
+	col.getKvBulkProvider = func() (kvBulkProvider, error) {
+		return &kvBulkProviderCore{
+			agent:  provider,
+			tracer: newTracerWrapper(&NoopTracer{}),
+			meter:  newMeterWrapper(&NoopMeter{}),
+		}, nil
+	}
+
+	_, err := col.Upsert("someid", "someval", nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	_, err = col.Remove("someid", nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	_, err = col.MutateIn("someid", []MutateInSpec{UpsertSpec("field", "value", nil)}, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	_, err = col.Binary().Increment("someid", nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	err = col.Do([]BulkOp{&UpsertOp{ID: "someid", Value: "someval"}}, nil)
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	err = col.List("someid").Append("someval")
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	err = col.Queue("someid").Push("someval")
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	err = col.List("someid").Clear()
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+
+	provider.AssertNotCalled(suite.T(), "Set", mock.Anything, mock.Anything)
+	provider.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+	provider.AssertNotCalled(suite.T(), "MutateIn", mock.Anything, mock.Anything)
+	provider.AssertNotCalled(suite.T(), "Increment", mock.Anything, mock.Anything)
+	provider.AssertNotCalled(suite.T(), "GetAndTouch", mock.Anything, mock.Anything)
+}
+
+func (suite *UnitTestSuite) TestReadOnlyModeMutationRefused() {
+	provider := new(mockKvProviderCoreProvider)
+	agent := suite.kvProviderCore(provider, &mockConfigSnapshotProvider{snapshot: &mockConfigSnapshot{}})
+
+	col := suite.collection("mock", "scope", "collection", agent)
+	col.readOnlyMode = true
+
+	_, err := col.Upsert("someid", "someval", nil)
+	suite.Assert().ErrorIs(err, ErrClusterReadOnly)
+
+	_, err = col.MutateIn("someid", []MutateInSpec{UpsertSpec("field", "value", nil)}, nil)
+	suite.Assert().ErrorIs(err, ErrClusterReadOnly)
+
+	_, err = col.Binary().Append("someid", []byte("val"), nil)
+	suite.Assert().ErrorIs(err, ErrClusterReadOnly)
+
+	_, err = col.Get("someid", &GetOptions{RefreshExpiry: time.Minute})
+	suite.Assert().ErrorIs(err, ErrClusterReadOnly)
+
+	_, err = col.WithSlidingExpiry(time.Minute).Get("someid", nil)
+	suite.Assert().ErrorIs(err, ErrClusterReadOnly)
+
+	err = col.Map("someid").Add("key", "val")
+	suite.Assert().ErrorIs(err, ErrClusterReadOnly)
+
+	err = col.Map("someid").Remove("key")
+	suite.Assert().ErrorIs(err, ErrClusterReadOnly)
+
+	err = col.Set("someid").Add("val")
+	suite.Assert().ErrorIs(err, ErrClusterReadOnly)
+
+	err = col.Map("someid").Clear()
+	suite.Assert().ErrorIs(err, ErrClusterReadOnly)
+
+	_, err = col.SignedCounter("someid").Add(1)
+	suite.Assert().ErrorIs(err, ErrClusterReadOnly)
+
+	provider.AssertNotCalled(suite.T(), "Set", mock.Anything, mock.Anything)
+	provider.AssertNotCalled(suite.T(), "MutateIn", mock.Anything, mock.Anything)
+	provider.AssertNotCalled(suite.T(), "Append", mock.Anything, mock.Anything)
+	provider.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+	provider.AssertNotCalled(suite.T(), "Increment", mock.Anything, mock.Anything)
+}
+
+func (suite *UnitTestSuite) TestSystemScopeMutationAllowed() {
+	pendingOp := new(mockPendingOp)
+
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("Set", mock.AnythingOfType("gocbcore.SetOptions"), mock.AnythingOfType("gocbcore.StoreCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(1).(gocbcore.StoreCallback)
+			cb(&gocbcore.StoreResult{
+				Cas: gocbcore.Cas(123),
+			}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	agent := suite.kvProviderCore(provider, &mockConfigSnapshotProvider{snapshot: &mockConfigSnapshot{}})
+
+	col := suite.collection("mock", SystemScopeName, "_mobile", agent)
+	col.allowSystemScopeMutations = true
+
+	res, err := col.Upsert("someid", "someval", nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(Cas(123), res.Cas())
+}
+
+func (suite *UnitTestSuite) TestCollectionHandlesCached() {
+	b := suite.bucket("bucket", suite.defaultTimeoutConfig(), nil)
+
+	suite.Assert().Same(b.Scope("scope"), b.Scope("scope"))
+	suite.Assert().Same(b.DefaultScope(), b.Scope(DefaultScopeName))
+	suite.Assert().Same(b.Scope("scope").Collection("collection"), b.Scope("scope").Collection("collection"))
+	suite.Assert().Same(b.DefaultCollection(), b.DefaultScope().Collection(DefaultCollectionName))
+	suite.Assert().NotSame(b.Collection("collection"), b.Scope("scope").Collection("collection"))
+
+	suite.Assert().True(b.Scope("scope").Equal(suite.newScope(b, "scope")))
+	suite.Assert().False(b.Scope("scope").Equal(b.DefaultScope()))
+
+	col := b.Scope("scope").Collection("collection")
+	suite.Assert().True(col.Equal(col.WithSlidingExpiry(time.Minute)))
+	suite.Assert().False(col.Equal(b.Collection("collection")))
+	suite.Assert().False(col.Equal(nil))
+}
+
+func (suite *UnitTestSuite) TestCollectionIdentity() {
+	pendingOp := new(mockPendingOp)
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("GetCollectionID", "inventory", "airline", mock.AnythingOfType("gocbcore.GetCollectionIDOptions"),
+			mock.AnythingOfType("gocbcore.GetCollectionIDCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(3).(gocbcore.GetCollectionIDCallback)
+			cb(&gocbcore.GetCollectionIDResult{CollectionID: 9}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	b := suite.bucket("travel-sample", suite.defaultTimeoutConfig(), nil)
+	col := b.Scope("inventory").Collection("airline")
+
+	suite.Assert().Equal(CollectionIdentity{
+		Bucket:     "travel-sample",
+		Scope:      "inventory",
+		Collection: "airline",
+	}, col.Identity())
+
+	_, err := suite.kvProviderCore(provider, nil).getCollectionID(context.Background(), col, nil, time.Second, "")
+	suite.Require().Nil(err, err)
+
+	identity := col.Identity()
+	suite.Assert().True(identity.UIDKnown)
+	suite.Assert().Equal(uint32(9), identity.UID)
+	suite.Assert().Equal(Keyspace{Bucket: "travel-sample", Scope: "inventory", Collection: "airline"}, identity.Keyspace())
+
+	// A handle for a recreated collection, once resolved, no longer matches.
+	recreated := suite.newScope(b, "inventory").Collection("airline")
+	suite.Assert().True(col.Equal(recreated))
+	recreated.uid.set(10)
+	suite.Assert().False(col.Equal(recreated))
+
+	defaultIdentity := b.DefaultCollection().Identity()
+	suite.Assert().True(defaultIdentity.UIDKnown)
+	suite.Assert().Equal("_default", defaultIdentity.Collection)
+}
@@ -6,5 +6,6 @@ type collectionsManagementProvider interface {
 	UpdateCollection(scopeName string, collectionName string, settings UpdateCollectionSettings, opts *UpdateCollectionOptions) error
 	DropCollection(scopeName string, collectionName string, opts *DropCollectionOptions) error
 	CreateScope(scopeName string, opts *CreateScopeOptions) error
+	UpdateScopeLimits(scopeName string, limits ScopeLimits, opts *UpdateScopeLimitsOptions) error
 	DropScope(scopeName string, opts *DropScopeOptions) error
 }
@@ -52,11 +52,29 @@ func (cm *collectionsManagementProviderCore) GetAllScopes(opts *GetAllScopesOpti
 		return nil, makeMgmtBadStatusError("failed to get all scopes", &req, resp)
 	}
 
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
 	var scopes []ScopeSpec
 	var mfest gocbcore.Manifest
-	jsonDec := json.NewDecoder(resp.Body)
-	err = jsonDec.Decode(&mfest)
+	err = json.Unmarshal(body, &mfest)
 	if err == nil {
+		var scopesLimits jsonScopesLimits
+		err = json.Unmarshal(body, &scopesLimits)
+		if err != nil {
+			return nil, err
+		}
+		limits := make(map[string]*ScopeLimits, len(scopesLimits.Scopes))
+		for _, scope := range scopesLimits.Scopes {
+			if scope.Limits != nil {
+				scopeLimits := &ScopeLimits{}
+				scopeLimits.fromData(*scope.Limits)
+				limits[scope.Name] = scopeLimits
+			}
+		}
+
 		for _, scope := range mfest.Scopes {
 			var collections []CollectionSpec
 			for _, col := range scope.Collections {
@@ -75,13 +93,13 @@ func (cm *collectionsManagementProviderCore) GetAllScopes(opts *GetAllScopesOpti
 			scopes = append(scopes, ScopeSpec{
 				Name:        scope.Name,
 				Collections: collections,
+				Limits:      limits[scope.Name],
 			})
 		}
 	} else {
 		// Temporary support for older server version
 		var oldMfest jsonManifest
-		jsonDec := json.NewDecoder(resp.Body)
-		err = jsonDec.Decode(&oldMfest)
+		err = json.Unmarshal(body, &oldMfest)
 		if err != nil {
 			return nil, err
 		}
@@ -181,7 +199,7 @@ func (cm *collectionsManagementProviderCore) CreateCollection(scopeName string,
 	return nil
 }
 
-// UpdateCollection creates a new collection on the bucket.
+// UpdateCollection updates the settings of an existing collection.
 func (cm *collectionsManagementProviderCore) UpdateCollection(scopeName string, collectionName string, settings UpdateCollectionSettings, opts *UpdateCollectionOptions) error {
 	if collectionName == "" {
 		return makeInvalidArgumentsError("collection name cannot be empty")
@@ -195,7 +213,8 @@ func (cm *collectionsManagementProviderCore) UpdateCollection(scopeName string,
 		opts = &UpdateCollectionOptions{}
 	}
 
-	path := fmt.Sprintf("/pools/default/buckets/%s/scopes/%s/collections/%s", cm.bucketName, scopeName, collectionName)
+	path := fmt.Sprintf("/pools/default/buckets/%s/scopes/%s/collections/%s", url.PathEscape(cm.bucketName),
+		url.PathEscape(scopeName), url.PathEscape(collectionName))
 	span := cm.tracer.createSpan(opts.ParentSpan, "manager_collections_update_collection", "management")
 	span.SetAttribute("db.name", cm.bucketName)
 	span.SetAttribute("db.couchbase.scope", scopeName)
@@ -242,7 +261,7 @@ func (cm *collectionsManagementProviderCore) UpdateCollection(scopeName string,
 		if colErr != nil {
 			return colErr
 		}
-		return makeMgmtBadStatusError("failed to create collection", &req, resp)
+		return makeMgmtBadStatusError("failed to update collection", &req, resp)
 	}
 
 	err = resp.Body.Close()
@@ -326,6 +345,13 @@ func (cm *collectionsManagementProviderCore) CreateScope(scopeName string, opts
 
 	posts := url.Values{}
 	posts.Add("name", scopeName)
+	if opts.Limits != nil {
+		limits, err := json.Marshal(opts.Limits.toData())
+		if err != nil {
+			return err
+		}
+		posts.Add("limits", string(limits))
+	}
 
 	eSpan := cm.tracer.createSpan(span, "request_encoding", "")
 	encoded := posts.Encode()
@@ -365,6 +391,56 @@ func (cm *collectionsManagementProviderCore) CreateScope(scopeName string, opts
 	return nil
 }
 
+// UpdateScopeLimits replaces the quotas applied to a scope.
+func (cm *collectionsManagementProviderCore) UpdateScopeLimits(scopeName string, limits ScopeLimits, opts *UpdateScopeLimitsOptions) error {
+	path := fmt.Sprintf("/pools/default/buckets/%s/scopes/%s", url.PathEscape(cm.bucketName), url.PathEscape(scopeName))
+	span := cm.tracer.createSpan(opts.ParentSpan, "manager_collections_update_scope_limits", "management")
+	span.SetAttribute("db.name", cm.bucketName)
+	span.SetAttribute("db.couchbase.scope", scopeName)
+	span.SetAttribute("db.operation", "PATCH "+path)
+	defer span.End()
+
+	encodedLimits, err := json.Marshal(limits.toData())
+	if err != nil {
+		return err
+	}
+
+	posts := url.Values{}
+	posts.Add("limits", string(encodedLimits))
+
+	eSpan := cm.tracer.createSpan(span, "request_encoding", "")
+	encoded := posts.Encode()
+	eSpan.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Path:          path,
+		Method:        "PATCH",
+		Body:          []byte(encoded),
+		ContentType:   "application/x-www-form-urlencoded",
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := cm.mgmtProvider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		colErr := cm.tryParseErrorMessage(&req, resp)
+		if colErr != nil {
+			return colErr
+		}
+		return makeMgmtBadStatusError("failed to update scope limits", &req, resp)
+	}
+
+	return nil
+}
+
 // DropScope removes a scope.
 func (cm *collectionsManagementProviderCore) DropScope(scopeName string, opts *DropScopeOptions) error {
 	if opts == nil {
@@ -1,3 +1,5 @@
+//go:build !gocb_nocouchbase2
+
 package gocb
 
 import (
@@ -194,6 +196,10 @@ func (cm *collectionsManagementProviderPs) DropCollection(scopeName string, coll
 
 // CreateScope creates a new scope on the bucket.
 func (cm *collectionsManagementProviderPs) CreateScope(scopeName string, opts *CreateScopeOptions) error {
+	if opts.Limits != nil {
+		return wrapError(ErrFeatureNotAvailable, "scope limits are not supported by the couchbase2 protocol")
+	}
+
 	manager := cm.newOpManager(opts.ParentSpan, "manager_collections_create_scope", map[string]interface{}{
 		"db.name":            cm.bucketName,
 		"db.couchbase.scope": scopeName,
@@ -223,6 +229,11 @@ func (cm *collectionsManagementProviderPs) CreateScope(scopeName string, opts *C
 	return nil
 }
 
+// UpdateScopeLimits replaces the quotas applied to a scope.
+func (cm *collectionsManagementProviderPs) UpdateScopeLimits(string, ScopeLimits, *UpdateScopeLimitsOptions) error {
+	return wrapError(ErrFeatureNotAvailable, "scope limits are not supported by the couchbase2 protocol")
+}
+
 // DropScope removes a scope.
 func (cm *collectionsManagementProviderPs) DropScope(scopeName string, opts *DropScopeOptions) error {
 	manager := cm.newOpManager(opts.ParentSpan, "manager_collections_drop_scope", map[string]interface{}{
@@ -2,12 +2,38 @@ package gocb
 
 import "github.com/golang/snappy"
 
+// The defaults used by gocbcore when the compression settings are not set, the compressor must make the same
+// decision as gocbcore so that the size of the value which will actually be sent is known.
+const (
+	defaultCompressionMinSize  = 32
+	defaultCompressionMinRatio = 0.83
+)
+
 type compressor struct {
 	CompressionEnabled  bool
 	CompressionMinSize  uint32
 	CompressionMinRatio float64
 }
 
+func (c *compressor) minSize() int {
+	if c.CompressionMinSize > 0 {
+		return int(c.CompressionMinSize)
+	}
+
+	return defaultCompressionMinSize
+}
+
+func (c *compressor) minRatio() float64 {
+	if c.CompressionMinRatio <= 0 {
+		return defaultCompressionMinRatio
+	}
+	if c.CompressionMinRatio >= 1.0 {
+		return 1.0
+	}
+
+	return c.CompressionMinRatio
+}
+
 type possiblyCompressedResponse interface {
 	GetContentUncompressed() []byte
 	GetContentCompressed() []byte
@@ -19,9 +45,9 @@ func (c *compressor) Compress(val []byte) ([]byte, bool) {
 	}
 
 	valueLen := len(val)
-	if valueLen > int(c.CompressionMinSize) {
+	if valueLen > c.minSize() {
 		compressedValue := snappy.Encode(nil, val)
-		if float64(len(compressedValue))/float64(valueLen) <= c.CompressionMinRatio {
+		if float64(len(compressedValue))/float64(valueLen) <= c.minRatio() {
 			return compressedValue, true
 		}
 	}
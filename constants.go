@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/couchbase/goprotostellar/genproto/kv_v1"
-
 	gocbcore "github.com/couchbase/gocbcore/v10"
 	"github.com/couchbase/gocbcore/v10/memd"
 )
@@ -16,6 +14,18 @@ const (
 	durabilityTimeoutFloor = 1500 * time.Millisecond
 )
 
+const (
+	// DefaultScopeName is the name of the default scope.
+	DefaultScopeName = "_default"
+
+	// DefaultCollectionName is the name of the default collection.
+	DefaultCollectionName = "_default"
+
+	// SystemScopeName is the name of the scope reserved by the server for system collections.
+	// KV mutations to collections within this scope are refused unless ClusterOptions.AllowSystemScopeMutations is set.
+	SystemScopeName = "_system"
+)
+
 // QueryIndexType provides information on the type of indexer used for an index.
 type QueryIndexType string
 
@@ -88,6 +98,9 @@ const (
 	ServiceTypeEventing ServiceType = ServiceType(gocbcore.EventingService)
 )
 
+// serviceTypeIndex represents the index service, which is only used internally for index management requests.
+const serviceTypeIndex = ServiceType(gocbcore.GSIService)
+
 // QueryProfileMode specifies the profiling mode to use during a query.
 type QueryProfileMode string
 
@@ -198,26 +211,6 @@ func (dl DurabilityLevel) toMemd() (memd.DurabilityLevel, error) {
 	}
 }
 
-func (dl DurabilityLevel) toProtostellar() (*kv_v1.DurabilityLevel, error) {
-	var level kv_v1.DurabilityLevel
-	switch dl {
-	case DurabilityLevelNone:
-		return nil, nil
-	case DurabilityLevelMajority:
-		level = kv_v1.DurabilityLevel_DURABILITY_LEVEL_MAJORITY
-	case DurabilityLevelMajorityAndPersistOnMaster:
-		level = kv_v1.DurabilityLevel_DURABILITY_LEVEL_MAJORITY_AND_PERSIST_TO_ACTIVE
-	case DurabilityLevelPersistToMajority:
-		level = kv_v1.DurabilityLevel_DURABILITY_LEVEL_PERSIST_TO_MAJORITY
-	case DurabilityLevelUnknown:
-		return nil, makeInvalidArgumentsError("unexpected unset durability level")
-	default:
-		return nil, makeInvalidArgumentsError("unexpected durability level")
-	}
-
-	return &level, nil
-}
-
 func durabilityLevelFromManagementAPI(level string) DurabilityLevel {
 	switch level {
 	case "majority":
@@ -330,6 +323,11 @@ const (
 const (
 	spanNameDispatchToServer      = "dispatch_to_server"
 	spanNameRequestEncoding       = "request_encoding"
+	spanNameClusterConnect        = "cluster_connect"
+	spanNameDNSResolution         = "dns_resolution"
+	spanNameBootstrap             = "bootstrap"
+	spanNameBucketOpen            = "bucket_open"
+	spanNameWaitUntilReady        = "wait_until_ready"
 	spanAttribDBSystemKey         = "db.system"
 	spanAttribDBSystemValue       = "couchbase"
 	spanAttribOperationIDKey      = "db.couchbase.operation_id"
@@ -350,16 +348,30 @@ const (
 	spanAttribNumRetries          = "db.couchbase.retries"
 	spanAttribClusterUUIDKey      = "db.couchbase.cluster_uuid"
 	spanAttribClusterNameKey      = "db.couchbase.cluster_name"
-
-	meterNameCBOperations        = "db.couchbase.operations"
-	meterAttribServiceKey        = "db.couchbase.service"
-	meterAttribOperationKey      = "db.operation"
-	meterAttribBucketNameKey     = "db.name"
-	meterAttribScopeNameKey      = "db.couchbase.scope"
-	meterAttribCollectionNameKey = "db.couchbase.collection"
-	meterAttribOutcomeKey        = "outcome"
-	meterAttribClusterUUIDKey    = "db.couchbase.cluster_uuid"
-	meterAttribClusterNameKey    = "db.couchbase.cluster_name"
+	spanAttribCancelledKey        = "db.couchbase.cancelled"
+	spanAttribOutcomeKey          = "db.couchbase.outcome"
+
+	spanOutcomeCancelled = "cancelled"
+	spanOutcomeError     = "error"
+
+	meterNameCBOperations          = "db.couchbase.operations"
+	meterNameCBOperationsCancelled = "db.couchbase.operations.cancelled"
+	meterNameCBOperationsFailed    = "db.couchbase.operations.failed"
+	meterNameCBOperationsDispatch  = "db.couchbase.operations.dispatch"
+	meterAttribServiceKey          = "db.couchbase.service"
+	meterAttribOperationKey        = "db.operation"
+	meterAttribBucketNameKey       = "db.name"
+	meterAttribScopeNameKey        = "db.couchbase.scope"
+	meterAttribCollectionNameKey   = "db.couchbase.collection"
+	meterAttribOutcomeKey          = "outcome"
+	meterAttribClusterUUIDKey      = "db.couchbase.cluster_uuid"
+	meterAttribClusterNameKey      = "db.couchbase.cluster_name"
+
+	meterNameCBTransactionLimitsExceeded = "db.couchbase.transactions.limits_exceeded"
+	meterAttribTransactionLimitKey       = "db.couchbase.transactions.limit"
+
+	transactionLimitCollections = "collections"
+	transactionLimitMutations   = "mutations"
 
 	serviceValueKV         = "kv"
 	serviceValueQuery      = "query"
@@ -76,8 +76,42 @@ func (d *diagnosticsProviderCore) Ping(opts *PingOptions) (*PingResult, error) {
 		TraceContext: span.Context(),
 	}
 
+	samples := opts.Samples
+	if samples <= 0 {
+		samples = 1
+	}
+
+	id := opts.ReportID
+	if id == "" {
+		id = uuid.New().String()
+	}
+
+	aggregator := newPingAggregator()
+	var configRev int64
+	for i := 0; i < samples; i++ {
+		d.setPingDeadlines(&coreopts, opts.Timeout)
+
+		result, err := d.provider.Ping(opts.Context, coreopts)
+		if err != nil {
+			return nil, err
+		}
+
+		configRev = result.ConfigRev
+		for svcType, svc := range result.Services {
+			aggregator.addService(ServiceType(svcType), svc)
+		}
+	}
+
+	return &PingResult{
+		ID:        id,
+		sdk:       Identifier() + " " + "gocbcore/" + gocbcore.Version(),
+		Services:  aggregator.reports(),
+		configRev: configRev,
+	}, nil
+}
+
+func (d *diagnosticsProviderCore) setPingDeadlines(coreopts *gocbcore.PingOptions, timeout time.Duration) {
 	now := time.Now()
-	timeout := opts.Timeout
 	if timeout == 0 {
 		coreopts.KVDeadline = now.Add(d.timeouts.KVTimeout)
 		coreopts.CapiDeadline = now.Add(d.timeouts.ViewTimeout)
@@ -93,43 +127,97 @@ func (d *diagnosticsProviderCore) Ping(opts *PingOptions) (*PingResult, error) {
 		coreopts.FtsDeadline = now.Add(timeout)
 		coreopts.MgmtDeadline = now.Add(timeout)
 	}
+}
 
-	id := opts.ReportID
-	if id == "" {
-		id = uuid.New().String()
+type pingEndpointKey struct {
+	service   ServiceType
+	remote    string
+	namespace string
+}
+
+// pingAggregator combines the results of repeatedly pinging the same endpoints, an endpoint keeps the state of the
+// most recent failed ping so that intermittent failures are not hidden by later successes. Only the latencies of
+// successful pings are summarised, failed pings report the time taken to fail rather than to respond.
+type pingAggregator struct {
+	order   map[ServiceType][]pingEndpointKey
+	entries map[pingEndpointKey]*EndpointPingReport
+	totals  map[pingEndpointKey]time.Duration
+	counts  map[pingEndpointKey]int
+}
+
+func newPingAggregator() *pingAggregator {
+	return &pingAggregator{
+		order:   make(map[ServiceType][]pingEndpointKey),
+		entries: make(map[pingEndpointKey]*EndpointPingReport),
+		totals:  make(map[pingEndpointKey]time.Duration),
+		counts:  make(map[pingEndpointKey]int),
 	}
+}
 
-	result, err := d.provider.Ping(opts.Context, coreopts)
-	if err != nil {
-		return nil, err
+func (a *pingAggregator) addService(svcType ServiceType, reps []gocbcore.EndpointPingResult) {
+	if _, ok := a.order[svcType]; !ok {
+		a.order[svcType] = make([]pingEndpointKey, 0, len(reps))
+	}
+
+	for _, rep := range reps {
+		a.add(svcType, rep)
+	}
+}
+
+func (a *pingAggregator) add(svcType ServiceType, rep gocbcore.EndpointPingResult) {
+	var errStr string
+	if rep.Error != nil {
+		errStr = rep.Error.Error()
 	}
 
+	key := pingEndpointKey{
+		service:   svcType,
+		remote:    rep.Endpoint,
+		namespace: rep.Scope,
+	}
+
+	entry, ok := a.entries[key]
+	if !ok {
+		entry = &EndpointPingReport{
+			Remote:    rep.Endpoint,
+			State:     PingState(rep.State),
+			Error:     errStr,
+			Namespace: rep.Scope,
+		}
+		a.entries[key] = entry
+		a.order[svcType] = append(a.order[svcType], key)
+	}
+
+	entry.ID = rep.ID
+	entry.Latency = rep.Latency
+	if PingState(rep.State) != PingStateOk {
+		entry.State = PingState(rep.State)
+		entry.Error = errStr
+		return
+	}
+
+	if a.counts[key] == 0 || rep.Latency < entry.MinLatency {
+		entry.MinLatency = rep.Latency
+	}
+	if rep.Latency > entry.MaxLatency {
+		entry.MaxLatency = rep.Latency
+	}
+
+	a.totals[key] += rep.Latency
+	a.counts[key]++
+	entry.MeanLatency = a.totals[key] / time.Duration(a.counts[key])
+}
+
+func (a *pingAggregator) reports() map[ServiceType][]EndpointPingReport {
 	reportSvcs := make(map[ServiceType][]EndpointPingReport)
-	for svcType, svc := range result.Services {
-		st := ServiceType(svcType)
-
-		svcs := make([]EndpointPingReport, len(svc))
-		for i, rep := range svc {
-			var errStr string
-			if rep.Error != nil {
-				errStr = rep.Error.Error()
-			}
-			svcs[i] = EndpointPingReport{
-				ID:        rep.ID,
-				Remote:    rep.Endpoint,
-				State:     PingState(rep.State),
-				Error:     errStr,
-				Namespace: rep.Scope,
-				Latency:   rep.Latency,
-			}
+	for svcType, keys := range a.order {
+		svcs := make([]EndpointPingReport, len(keys))
+		for i, key := range keys {
+			svcs[i] = *a.entries[key]
 		}
 
-		reportSvcs[st] = svcs
+		reportSvcs[svcType] = svcs
 	}
 
-	return &PingResult{
-		ID:       id,
-		sdk:      Identifier() + " " + "gocbcore/" + gocbcore.Version(),
-		Services: reportSvcs,
-	}, nil
+	return reportSvcs
 }
@@ -0,0 +1,208 @@
+package gocb
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+)
+
+// EndpointSelectionPolicy specifies how the endpoint used for a query is chosen. Analytics and search requests are not
+// yet supported, as gocbcore does not allow the endpoint of those requests to be chosen, and are always distributed
+// using round robin.
+// UNCOMMITTED: This API may change in the future.
+type EndpointSelectionPolicy uint
+
+const (
+	// EndpointSelectionPolicyRoundRobin indicates that requests should be distributed evenly across all endpoints.
+	// This is the default.
+	EndpointSelectionPolicyRoundRobin EndpointSelectionPolicy = iota + 1
+
+	// EndpointSelectionPolicyLatencyAware indicates that requests should be spread across endpoints at random,
+	// weighted by the inverse of their recent latency, so that faster endpoints receive proportionally more requests.
+	// Endpoints which have recently failed are avoided, and a share of requests are left to round robin so that new
+	// endpoints are discovered and the latencies of slower endpoints are kept up to date.
+	EndpointSelectionPolicyLatencyAware
+)
+
+const (
+	// endpointLatencyWeight is the weight given to each new sample in the moving average of an endpoint's latency.
+	endpointLatencyWeight = 0.2
+	// endpointPenaltyBase is the initial duration for which an endpoint is avoided following a failure, which doubles
+	// with each consecutive failure up to endpointPenaltyMax.
+	endpointPenaltyBase = 1 * time.Second
+	endpointPenaltyMax  = 30 * time.Second
+	// endpointExploreInterval controls how often the choice of endpoint is left to round robin, so that endpoints which
+	// have been added to the cluster are discovered and the latencies of slower endpoints are kept up to date.
+	endpointExploreInterval = 10
+	// endpointStaleAfter is how long an endpoint can go without being seen before it is no longer selected, so that
+	// endpoints which have been removed from the cluster are forgotten.
+	endpointStaleAfter = 5 * time.Minute
+)
+
+type endpointLatencyStats struct {
+	latency        float64
+	failures       uint32
+	penalisedUntil time.Time
+	lastSeen       time.Time
+}
+
+// latencyAwareEndpointSelector tracks an exponentially weighted moving average of the latency of each endpoint that it
+// observes, alongside a penalty for endpoints which have recently failed. Endpoints are learned from the responses
+// recorded against it rather than from the cluster config, as only the endpoint of a response is visible to us.
+type latencyAwareEndpointSelector struct {
+	lock      sync.Mutex
+	endpoints map[string]*endpointLatencyStats
+	requests  uint64
+
+	now    func() time.Time
+	random func() float64
+}
+
+func newLatencyAwareEndpointSelector() *latencyAwareEndpointSelector {
+	return &latencyAwareEndpointSelector{
+		endpoints: make(map[string]*endpointLatencyStats),
+		now:       time.Now,
+		random:    rand.Float64,
+	}
+}
+
+// Select returns the endpoint which a request should be sent to, or an empty string if the choice should be left to
+// the default round robin behaviour. Endpoints are chosen at random, weighted by the inverse of their latency, so that
+// faster endpoints receive proportionally more requests without all requests being sent to the fastest endpoint. As
+// an endpoint receives more load its latency rises, and so its share of the requests falls.
+func (s *latencyAwareEndpointSelector) Select() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.requests++
+	if s.requests%endpointExploreInterval == 0 {
+		return ""
+	}
+
+	now := s.now()
+	var candidates []string
+	for endpoint, stats := range s.endpoints {
+		if now.Sub(stats.lastSeen) > endpointStaleAfter {
+			delete(s.endpoints, endpoint)
+			continue
+		}
+		if now.Before(stats.penalisedUntil) {
+			continue
+		}
+		candidates = append(candidates, endpoint)
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	// Candidates are sorted so that selection is deterministic for a given random value.
+	sort.Strings(candidates)
+
+	weights := make([]float64, len(candidates))
+	var total float64
+	for i, endpoint := range candidates {
+		weights[i] = 1 / math.Max(s.endpoints[endpoint].latency, 1)
+		total += weights[i]
+	}
+
+	target := s.random() * total
+	for i, weight := range weights {
+		target -= weight
+		if target < 0 {
+			return candidates[i]
+		}
+	}
+
+	return candidates[len(candidates)-1]
+}
+
+// Record updates the statistics for endpoint with the outcome of a request sent to it.
+func (s *latencyAwareEndpointSelector) Record(endpoint string, latency time.Duration, err error) {
+	if endpoint == "" {
+		return
+	}
+	if err != nil && !isEndpointHealthError(err) {
+		// The request failed for a reason unrelated to the health of the endpoint, so there is nothing to learn.
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	stats, ok := s.endpoints[endpoint]
+	if !ok {
+		stats = &endpointLatencyStats{}
+		s.endpoints[endpoint] = stats
+	}
+	stats.lastSeen = now
+
+	if err != nil {
+		stats.failures++
+		penalty := endpointPenaltyBase << (stats.failures - 1)
+		if penalty > endpointPenaltyMax || penalty <= 0 {
+			penalty = endpointPenaltyMax
+		}
+		stats.penalisedUntil = now.Add(penalty)
+		return
+	}
+
+	stats.failures = 0
+	stats.penalisedUntil = time.Time{}
+	if !ok {
+		stats.latency = float64(latency)
+		return
+	}
+	stats.latency = endpointLatencyWeight*float64(latency) + (1-endpointLatencyWeight)*stats.latency
+}
+
+// isEndpointHealthError returns whether err indicates that the endpoint which served a request is unhealthy.
+func isEndpointHealthError(err error) bool {
+	if isEndpointConnectError(err) {
+		return true
+	}
+
+	if errors.Is(err, ErrTimeout) || errors.Is(err, ErrServiceNotAvailable) ||
+		errors.Is(err, ErrInternalServerFailure) || errors.Is(err, ErrTemporaryFailure) {
+		return true
+	}
+
+	var svcErr ServiceError
+	if errors.As(err, &svcErr) {
+		return svcErr.ServiceErrorDetails().HTTPStatusCode >= 500
+	}
+
+	return false
+}
+
+// isEndpointConnectError returns whether err indicates that a request could not be sent to the endpoint at all, either
+// because it is no longer part of the cluster or because a connection could not be established to it. Requests which
+// fail in this way are never executed, so can always be sent to another endpoint.
+func isEndpointConnectError(err error) bool {
+	if errors.Is(err, gocbcore.ErrInvalidServer) {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// shouldFailoverEndpoint returns whether a request which was pinned to an endpoint by the selector and failed with err
+// should be sent again with the endpoint left to round robin. Requests which might have been executed are only sent
+// again if they are idempotent.
+func shouldFailoverEndpoint(err error, idempotent bool, deadline time.Time) bool {
+	if !time.Now().Before(deadline) {
+		return false
+	}
+
+	if isEndpointConnectError(err) {
+		return true
+	}
+
+	return idempotent && isEndpointHealthError(err)
+}
@@ -0,0 +1,294 @@
+package gocb
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	gocbcore "github.com/couchbase/gocbcore/v10"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) newTestEndpointSelector() (*latencyAwareEndpointSelector, *time.Time, *float64) {
+	now := time.Now()
+	random := 0.0
+	selector := newLatencyAwareEndpointSelector()
+	selector.now = func() time.Time {
+		return now
+	}
+	selector.random = func() float64 {
+		return random
+	}
+
+	return selector, &now, &random
+}
+
+// countSelections selects an endpoint once for each of count evenly spaced random values.
+func (suite *UnitTestSuite) countSelections(selector *latencyAwareEndpointSelector, random *float64, count int) map[string]int {
+	selections := make(map[string]int)
+	for i := 0; i < count; i++ {
+		*random = float64(i) / float64(count)
+		selections[selector.Select()]++
+	}
+
+	return selections
+}
+
+func (suite *UnitTestSuite) TestLatencyAwareEndpointSelectorPrefersFastest() {
+	selector, _, random := suite.newTestEndpointSelector()
+
+	suite.Assert().Equal("", selector.Select())
+
+	selector.Record("10.0.0.1:8093", 50*time.Millisecond, nil)
+	selector.Record("10.0.0.2:8093", 5*time.Millisecond, nil)
+	selector.Record("10.0.0.3:8093", 20*time.Millisecond, nil)
+
+	// Requests are shared in inverse proportion to latency, every endpoint still receives some requests.
+	selections := suite.countSelections(selector, random, 1000)
+	suite.Assert().Greater(selections["10.0.0.2:8093"], selections["10.0.0.3:8093"])
+	suite.Assert().Greater(selections["10.0.0.3:8093"], selections["10.0.0.1:8093"])
+	suite.Assert().Greater(selections["10.0.0.1:8093"], 0)
+	suite.Assert().Less(selections["10.0.0.2:8093"], 700)
+
+	// Sustained slow responses move the average until another endpoint becomes the most preferred.
+	for i := 0; i < 10; i++ {
+		selector.Record("10.0.0.2:8093", 100*time.Millisecond, nil)
+	}
+	selections = suite.countSelections(selector, random, 1000)
+	suite.Assert().Greater(selections["10.0.0.3:8093"], selections["10.0.0.2:8093"])
+}
+
+func (suite *UnitTestSuite) TestLatencyAwareEndpointSelectorPenalisesFailures() {
+	selector, now, random := suite.newTestEndpointSelector()
+	// Favour the fastest endpoint whenever it is a candidate.
+	*random = 0.99
+
+	selector.Record("10.0.0.1:8093", 50*time.Millisecond, nil)
+	selector.Record("10.0.0.2:8093", 5*time.Millisecond, nil)
+	selector.Record("10.0.0.2:8093", 0, &QueryError{InnerError: ErrInternalServerFailure})
+
+	suite.Assert().Equal("10.0.0.1:8093", selector.Select())
+
+	// Errors unrelated to the health of the endpoint are ignored.
+	selector.Record("10.0.0.1:8093", 0, &QueryError{InnerError: ErrParsingFailure, HTTPStatusCode: 400})
+	suite.Assert().Equal("10.0.0.1:8093", selector.Select())
+
+	*now = now.Add(endpointPenaltyBase + time.Millisecond)
+	suite.Assert().Equal("10.0.0.2:8093", selector.Select())
+
+	// Consecutive failures increase the penalty.
+	selector.Record("10.0.0.2:8093", 0, ErrTimeout)
+	selector.Record("10.0.0.2:8093", 0, ErrTimeout)
+	*now = now.Add(endpointPenaltyBase + time.Millisecond)
+	suite.Assert().Equal("10.0.0.1:8093", selector.Select())
+}
+
+func (suite *UnitTestSuite) TestLatencyAwareEndpointSelectorExploresAndForgets() {
+	selector, now, _ := suite.newTestEndpointSelector()
+
+	selector.Record("10.0.0.1:8093", 5*time.Millisecond, nil)
+
+	var explored int
+	for i := 0; i < endpointExploreInterval*2; i++ {
+		if selector.Select() == "" {
+			explored++
+		}
+	}
+	suite.Assert().Equal(2, explored)
+
+	*now = now.Add(endpointStaleAfter + time.Second)
+	suite.Assert().Equal("", selector.Select())
+	suite.Assert().Empty(selector.endpoints)
+}
+
+func (suite *UnitTestSuite) TestQueryLatencyAwareEndpointSelection() {
+	reader := &mockQueryRowReader{
+		Dataset: []testBreweryDocument{},
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Suite: suite,
+		},
+	}
+
+	var endpoints []string
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("N1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(1).(gocbcore.N1QLQueryOptions)
+			endpoints = append(endpoints, opts.Endpoint)
+		}).
+		Return(reader, nil)
+
+	selector, _, random := suite.newTestEndpointSelector()
+	*random = 0.99
+	selector.Record("10.0.0.1:8093", 50*time.Millisecond, nil)
+	selector.Record("10.0.0.2:8093", 5*time.Millisecond, nil)
+
+	queryProvider := &queryProviderCore{
+		provider:         provider,
+		endpointSelector: selector,
+	}
+
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	queryProvider.tracer = newTracerWrapper(&NoopTracer{})
+	queryProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	queryProvider.timeouts = cluster.timeoutsConfig
+
+	_, err := cluster.Query("SELECT 1=1", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+
+	_, err = cluster.Query("SELECT 1=1", &QueryOptions{
+		Adhoc:                   true,
+		EndpointSelectionPolicy: EndpointSelectionPolicyLatencyAware,
+	})
+	suite.Require().Nil(err, err)
+
+	queryProvider.endpointSelectionPolicy = EndpointSelectionPolicyLatencyAware
+	_, err = cluster.Query("SELECT 1=1", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+
+	_, err = cluster.Query("SELECT 1=1", &QueryOptions{
+		Adhoc:                   true,
+		EndpointSelectionPolicy: EndpointSelectionPolicyRoundRobin,
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal([]string{"", "10.0.0.2:8093", "10.0.0.2:8093", ""}, endpoints)
+}
+
+func (suite *UnitTestSuite) TestQueryEndpointPinning() {
+	reader := &mockQueryRowReader{
+		Dataset: []testBreweryDocument{},
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Suite: suite,
+		},
+	}
+
+	var endpoints []string
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("N1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(1).(gocbcore.N1QLQueryOptions)
+			endpoints = append(endpoints, opts.Endpoint)
+		}).
+		Return(reader, nil).
+		Twice()
+	provider.
+		On("N1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Return(nil, gocbcore.ErrInvalidServer)
+
+	selector, _, _ := suite.newTestEndpointSelector()
+	selector.Record("http://10.0.0.2:8093", 5*time.Millisecond, nil)
+
+	queryProvider := &queryProviderCore{
+		provider:                provider,
+		endpointSelector:        selector,
+		endpointSelectionPolicy: EndpointSelectionPolicyLatencyAware,
+	}
+
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	queryProvider.tracer = newTracerWrapper(&NoopTracer{})
+	queryProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	queryProvider.timeouts = cluster.timeoutsConfig
+
+	// The pinned endpoint takes precedence over the endpoint selection policy.
+	_, err := cluster.Query("SELECT 1=1", &QueryOptions{Adhoc: true, Endpoint: "http://10.0.0.1:8093"})
+	suite.Require().Nil(err, err)
+
+	_, err = cluster.Query("SELECT 1=1", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal([]string{"http://10.0.0.1:8093", "http://10.0.0.2:8093"}, endpoints)
+
+	_, err = cluster.Query("SELECT 1=1", &QueryOptions{Adhoc: true, Endpoint: "http://10.0.0.9:8093"})
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestEndpointHealthErrors() {
+	suite.Assert().True(isEndpointHealthError(maybeEnhanceCoreQueryError(&gocbcore.N1QLError{
+		InnerError: gocbcore.ErrInvalidServer,
+	})))
+	suite.Assert().True(isEndpointHealthError(wrapError(&net.OpError{
+		Op:  "dial",
+		Err: errors.New("connection refused"),
+	}, "failed to connect")))
+	suite.Assert().False(isEndpointHealthError(ErrParsingFailure))
+
+	deadline := time.Now().Add(time.Minute)
+	suite.Assert().True(shouldFailoverEndpoint(gocbcore.ErrInvalidServer, false, deadline))
+	suite.Assert().True(shouldFailoverEndpoint(ErrServiceNotAvailable, true, deadline))
+	suite.Assert().False(shouldFailoverEndpoint(ErrServiceNotAvailable, false, deadline))
+	suite.Assert().False(shouldFailoverEndpoint(ErrParsingFailure, true, deadline))
+	suite.Assert().False(shouldFailoverEndpoint(gocbcore.ErrInvalidServer, false, time.Now().Add(-time.Second)))
+}
+
+func (suite *UnitTestSuite) TestQueryLatencyAwareEndpointFailover() {
+	reader := &mockQueryRowReader{
+		Dataset: []testBreweryDocument{},
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Suite: suite,
+		},
+	}
+
+	var calls []gocbcore.N1QLQueryOptions
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("N1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Run(func(args mock.Arguments) {
+			calls = append(calls, args.Get(1).(gocbcore.N1QLQueryOptions))
+		}).
+		Return(nil, &gocbcore.N1QLError{InnerError: gocbcore.ErrInvalidServer, Endpoint: "http://10.0.0.2:8093"}).
+		Once()
+	provider.
+		On("N1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Run(func(args mock.Arguments) {
+			calls = append(calls, args.Get(1).(gocbcore.N1QLQueryOptions))
+		}).
+		Return(reader, nil).
+		Once()
+
+	selector, _, _ := suite.newTestEndpointSelector()
+	selector.Record("http://10.0.0.2:8093", 5*time.Millisecond, nil)
+
+	queryProvider := &queryProviderCore{
+		provider:                provider,
+		endpointSelector:        selector,
+		endpointSelectionPolicy: EndpointSelectionPolicyLatencyAware,
+	}
+
+	cli := new(mockConnectionManager)
+	cli.On("getQueryProvider").Return(queryProvider, nil)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	queryProvider.tracer = newTracerWrapper(&NoopTracer{})
+	queryProvider.retryStrategyWrapper = cluster.retryStrategyWrapper
+	queryProvider.timeouts = cluster.timeoutsConfig
+
+	_, err := cluster.Query("SELECT 1=1", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+
+	suite.Require().Len(calls, 2)
+	suite.Assert().Equal("http://10.0.0.2:8093", calls[0].Endpoint)
+	suite.Assert().Equal(cluster.retryStrategyWrapper, calls[0].RetryStrategy)
+	suite.Assert().Equal("", calls[1].Endpoint)
+	suite.Assert().Equal(cluster.retryStrategyWrapper, calls[1].RetryStrategy)
+
+	// The failed endpoint is no longer preferred.
+	suite.Assert().Equal("", selector.Select())
+}
@@ -0,0 +1,172 @@
+package gocb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// defaultEnsureTimeout bounds the Ensure helpers when no Timeout is provided, it matches the default management
+// timeout as the helpers are composed of management operations.
+const defaultEnsureTimeout = 75 * time.Second
+
+// ensureContext returns a context which bounds every step of an Ensure helper, including waiting for the resource to
+// become usable.
+func ensureContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if timeout == 0 {
+		timeout = defaultEnsureTimeout
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// searchIndexEnsurer is implemented by both SearchIndexManager and ScopeSearchIndexManager.
+type searchIndexEnsurer interface {
+	GetIndex(indexName string, opts *GetSearchIndexOptions) (*SearchIndex, error)
+	UpsertIndex(indexDefinition SearchIndex, opts *UpsertSearchIndexOptions) error
+	GetIndexedDocumentsCount(indexName string, opts *GetIndexedDocumentsCountOptions) (uint64, error)
+}
+
+func ensureSearchIndex(mgr searchIndexEnsurer, index SearchIndex, opts *EnsureSearchIndexOptions) error {
+	if index.Name == "" {
+		return makeInvalidArgumentsError("index name cannot be empty")
+	}
+
+	ctx, cancel := ensureContext(opts.Context, opts.Timeout)
+	defer cancel()
+
+	existing, err := mgr.GetIndex(index.Name, &GetSearchIndexOptions{
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       ctx,
+	})
+	if err != nil && !errors.Is(err, ErrIndexNotFound) {
+		return err
+	}
+
+	upsert := err != nil
+	if !upsert && opts.UpdateIfDifferent {
+		upsert = searchIndexDiffers(*existing, index)
+		index.UUID = existing.UUID
+	}
+
+	if upsert {
+		err = mgr.UpsertIndex(index, &UpsertSearchIndexOptions{
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       ctx,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.NoWait {
+		return nil
+	}
+
+	// The document count cannot be retrieved until the partitions of the index have been created, at which point
+	// the index can be queried.
+	return PollUntil(ctx, 0, func(ctx context.Context) (bool, error) {
+		_, err := mgr.GetIndexedDocumentsCount(index.Name, &GetIndexedDocumentsCountOptions{
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       ctx,
+		})
+		if err != nil {
+			if errors.Is(err, ErrTimeout) || errors.Is(err, ErrRequestCanceled) {
+				return false, err
+			}
+
+			logDebugf("Search index %s is not yet usable: %v", index.Name, err)
+			return false, nil
+		}
+
+		return true, nil
+	}, &PollUntilOptions{
+		ParentSpan: opts.ParentSpan,
+	})
+}
+
+// searchIndexDiffers returns whether any of the properties set in the desired definition differ from the existing
+// index. Properties left unset in the desired definition, including keys missing from its params, are populated by
+// the server and so are not compared.
+func searchIndexDiffers(existing, desired SearchIndex) bool {
+	if desired.SourceName != "" && desired.SourceName != existing.SourceName {
+		return true
+	}
+	if desired.Type != "" && desired.Type != existing.Type {
+		return true
+	}
+	if desired.SourceType != "" && desired.SourceType != existing.SourceType {
+		return true
+	}
+
+	for _, params := range [][2]map[string]interface{}{
+		{existing.Params, desired.Params},
+		{existing.PlanParams, desired.PlanParams},
+		{existing.SourceParams, desired.SourceParams},
+	} {
+		if len(params[1]) > 0 && !jsonContains(params[0], params[1]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jsonContains compares two values by their JSON representations, so that e.g. an int and a float64 holding the
+// same number are treated as equal. Only the keys present in the objects of desired are compared, any others in
+// existing, such as those populated with defaults by the server, are ignored.
+func jsonContains(existing, desired interface{}) bool {
+	normalize := func(v interface{}) (interface{}, bool) {
+		bytes, err := json.Marshal(v)
+		if err != nil {
+			return nil, false
+		}
+
+		var out interface{}
+		if err := json.Unmarshal(bytes, &out); err != nil {
+			return nil, false
+		}
+
+		return out, true
+	}
+
+	normalizedExisting, ok := normalize(existing)
+	if !ok {
+		return false
+	}
+	normalizedDesired, ok := normalize(desired)
+	if !ok {
+		return false
+	}
+
+	return jsonValueContains(normalizedExisting, normalizedDesired)
+}
+
+func jsonValueContains(existing, desired interface{}) bool {
+	desiredObj, ok := desired.(map[string]interface{})
+	if !ok {
+		return reflect.DeepEqual(existing, desired)
+	}
+
+	existingObj, ok := existing.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	for key, desiredValue := range desiredObj {
+		existingValue, ok := existingObj[key]
+		if !ok || !jsonValueContains(existingValue, desiredValue) {
+			return false
+		}
+	}
+
+	return true
+}
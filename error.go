@@ -343,4 +343,34 @@ var (
 	ErrDocumentTooDeep = errors.New("document too deep")
 
 	ErrShutdown = errors.New("cluster closed")
+
+	// ErrClusterReadOnly occurs when a mutating operation is attempted whilst ClusterOptions.ReadOnlyMode is enabled.
+	// UNCOMMITTED: This API may change in the future.
+	ErrClusterReadOnly = errors.New("cluster is in read only mode")
+
+	// ErrJSONPatchTestFailed occurs when a test operation within a JSON Patch applied with ApplyJSONPatch does not
+	// match the document.
+	// UNCOMMITTED: This API may change in the future.
+	ErrJSONPatchTestFailed = errors.New("json patch test operation failed")
+
+	// ErrAnalyticsHandleNotReady occurs when the results of an analytics query handle are requested before the query
+	// has completed successfully.
+	// UNCOMMITTED: This API may change in the future.
+	ErrAnalyticsHandleNotReady = errors.New("analytics handle results are not ready")
+
+	// ErrXDCRRemoteClusterNotFound occurs when an XDCR remote cluster reference could not be found.
+	// UNCOMMITTED: This API may change in the future.
+	ErrXDCRRemoteClusterNotFound = errors.New("xdcr remote cluster not found")
+
+	// ErrXDCRReplicationNotFound occurs when an XDCR replication could not be found.
+	// UNCOMMITTED: This API may change in the future.
+	ErrXDCRReplicationNotFound = errors.New("xdcr replication not found")
+
+	// ErrNodeNotFound occurs when a node could not be found in the cluster.
+	// UNCOMMITTED: This API may change in the future.
+	ErrNodeNotFound = errors.New("node not found")
+
+	// ErrRebalanceFailed occurs when a rebalance being waited on fails.
+	// UNCOMMITTED: This API may change in the future.
+	ErrRebalanceFailed = errors.New("rebalance failed")
 )
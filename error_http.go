@@ -85,6 +85,16 @@ func makeGenericHTTPError(baseErr error, req *gocbcore.HTTPRequest, resp *gocbco
 		InnerError: baseErr,
 	}
 
+	// Errors from gocbcore carry the endpoint and retry details, which are lifted so that they are available without
+	// unwrapping.
+	var coreErr *gocbcore.HTTPError
+	if errors.As(baseErr, &coreErr) {
+		err.InnerError = coreErr.InnerError
+		err.Endpoint = coreErr.Endpoint
+		err.RetryReasons = translateCoreRetryReasons(coreErr.RetryReasons)
+		err.RetryAttempts = coreErr.RetryAttempts
+	}
+
 	if req != nil {
 		err.UniqueID = req.UniqueID
 	}
@@ -2,6 +2,8 @@ package gocb
 
 import (
 	"encoding/json"
+	"errors"
+
 	"github.com/couchbase/gocbcore/v10/memd"
 )
 
@@ -122,3 +124,83 @@ func (e KeyValueError) Error() string {
 func (e KeyValueError) Unwrap() error {
 	return e.InnerError
 }
+
+// maxDocumentValueSize is the largest value which the server will accept for a document.
+const maxDocumentValueSize = 20 * 1024 * 1024
+
+// ValueTooLargeError occurs when a document value is larger than the maximum size allowed by the server, either
+// because the SDK rejected the value before sending it or because the server responded with ErrValueTooLarge.
+// UNCOMMITTED: This API may change in the future.
+type ValueTooLargeError struct {
+	// InnerError is the KeyValueError returned by the server, or ErrValueTooLarge if the value was never sent.
+	InnerError error  `json:"-"`
+	DocumentID string `json:"document_id,omitempty"`
+	// ValueSize is the size of the encoded value, before any compression. This is 0 for sub-document operations.
+	ValueSize int `json:"value_size,omitempty"`
+	// CompressedSize is the size of the value after compression, this is 0 when the value was not compressed.
+	CompressedSize int `json:"compressed_size,omitempty"`
+	// MaxSize is the maximum size of a value allowed by the server.
+	MaxSize int `json:"max_size"`
+}
+
+// Error returns the string representation of a value too large error.
+func (e ValueTooLargeError) Error() string {
+	errBytes, serErr := json.Marshal(e)
+	if serErr != nil {
+		logErrorf("failed to serialize error to json: %s", serErr.Error())
+	}
+
+	return e.InnerError.Error() + " | " + string(errBytes)
+}
+
+// Unwrap returns the underlying reason for the error
+func (e ValueTooLargeError) Unwrap() error {
+	return e.InnerError
+}
+
+// validateValueSize rejects values which the server would refuse, accounting for the compression that will be
+// applied before the value is sent. Values over the limit which compress to within it are returned compressed, so that
+// they are not compressed a second time when sent, and isCompressed is set.
+func validateValueSize(c *compressor, documentID string, value []byte) (sendValue []byte, isCompressed bool, err error) {
+	if len(value) <= maxDocumentValueSize {
+		return value, false, nil
+	}
+
+	var compressedSize int
+	if c != nil {
+		compressed, ok := c.Compress(value)
+		if ok {
+			if len(compressed) <= maxDocumentValueSize {
+				return compressed, true, nil
+			}
+			compressedSize = len(compressed)
+		}
+	}
+
+	return nil, false, &ValueTooLargeError{
+		InnerError:     ErrValueTooLarge,
+		DocumentID:     documentID,
+		ValueSize:      len(value),
+		CompressedSize: compressedSize,
+		MaxSize:        maxDocumentValueSize,
+	}
+}
+
+// maybeEnhanceValueTooLargeErr adds the size of the value to errors where the server rejected the value as too large.
+func maybeEnhanceValueTooLargeErr(err error, documentID string, value []byte) error {
+	if !errors.Is(err, ErrValueTooLarge) {
+		return err
+	}
+
+	var tooLargeErr *ValueTooLargeError
+	if errors.As(err, &tooLargeErr) {
+		return err
+	}
+
+	return &ValueTooLargeError{
+		InnerError: err,
+		DocumentID: documentID,
+		ValueSize:  len(value),
+		MaxSize:    maxDocumentValueSize,
+	}
+}
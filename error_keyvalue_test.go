@@ -1,8 +1,14 @@
 package gocb
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"math/rand"
+
+	"github.com/couchbase/gocbcore/v10"
 	"github.com/couchbase/gocbcore/v10/memd"
+	"github.com/stretchr/testify/mock"
 )
 
 func (suite *UnitTestSuite) TestKeyValueError() {
@@ -36,3 +42,92 @@ func (suite *UnitTestSuite) TestKeyValueError() {
 		aErr.Error(),
 	)
 }
+
+func (suite *UnitTestSuite) TestValueTooLargePreflight() {
+	provider := new(mockKvProviderCoreProvider)
+	col := suite.collection("mock", "", "", suite.kvProviderCore(provider, nil))
+
+	// Random bytes cannot be compressed, whereas a repeated byte compresses to well below the limit.
+	incompressible := make([]byte, maxDocumentValueSize+1)
+	rand.New(rand.NewSource(1)).Read(incompressible)
+	compressible := bytes.Repeat([]byte("a"), maxDocumentValueSize+1)
+
+	_, err := col.Upsert("big", incompressible, &UpsertOptions{Transcoder: NewRawBinaryTranscoder()})
+	suite.Require().ErrorIs(err, ErrValueTooLarge)
+
+	var tooLargeErr *ValueTooLargeError
+	suite.Require().True(errors.As(err, &tooLargeErr))
+	suite.Assert().Equal("big", tooLargeErr.DocumentID)
+	suite.Assert().Equal(maxDocumentValueSize+1, tooLargeErr.ValueSize)
+	suite.Assert().Zero(tooLargeErr.CompressedSize)
+	suite.Assert().Equal(maxDocumentValueSize, tooLargeErr.MaxSize)
+
+	_, err = col.Upsert("big", compressible, &UpsertOptions{Transcoder: NewRawBinaryTranscoder()})
+	suite.Require().ErrorIs(err, ErrValueTooLarge)
+
+	// Unset compression settings must behave as gocbcore's defaults, rather than never compressing.
+	col.compressor = &compressor{CompressionEnabled: true}
+	sendValue, isCompressed, err := validateValueSize(col.compressor, "big", compressible)
+	suite.Require().Nil(err, err)
+	suite.Assert().True(isCompressed)
+	suite.Assert().Less(len(sendValue), maxDocumentValueSize)
+
+	_, _, err = validateValueSize(col.compressor, "big", incompressible)
+	suite.Require().True(errors.As(err, &tooLargeErr))
+	suite.Assert().Zero(tooLargeErr.CompressedSize)
+
+	provider.AssertNotCalled(suite.T(), "Set", mock.Anything, mock.Anything)
+}
+
+func (suite *UnitTestSuite) TestValueTooLargeFromServer() {
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("Set", mock.AnythingOfType("gocbcore.SetOptions"), mock.AnythingOfType("gocbcore.StoreCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(1).(gocbcore.StoreCallback)
+			cb(nil, &gocbcore.KeyValueError{
+				InnerError:  gocbcore.ErrValueTooLarge,
+				StatusCode:  memd.StatusTooBig,
+				DocumentKey: "key",
+			})
+		}).
+		Return(new(mockPendingOp), nil)
+
+	col := suite.collection("mock", "", "", suite.kvProviderCore(provider, nil))
+
+	_, err := col.Upsert("key", "value", nil)
+	suite.Require().ErrorIs(err, ErrValueTooLarge)
+
+	var tooLargeErr *ValueTooLargeError
+	suite.Require().True(errors.As(err, &tooLargeErr))
+	suite.Assert().Equal(len(`"value"`), tooLargeErr.ValueSize)
+	suite.Assert().Equal(maxDocumentValueSize, tooLargeErr.MaxSize)
+
+	var kvErr *KeyValueError
+	suite.Require().True(errors.As(err, &kvErr))
+	suite.Assert().Equal(memd.StatusTooBig, kvErr.StatusCode)
+}
+
+func (suite *UnitTestSuite) TestValueTooLargeSendsCompressedValue() {
+	compressible := bytes.Repeat([]byte("a"), maxDocumentValueSize+1)
+
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("Set", mock.AnythingOfType("gocbcore.SetOptions"), mock.AnythingOfType("gocbcore.StoreCallback")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(0).(gocbcore.SetOptions)
+			suite.Assert().Equal(uint8(memd.DatatypeFlagCompressed), opts.Datatype)
+			suite.Assert().Less(len(opts.Value), maxDocumentValueSize)
+
+			cb := args.Get(1).(gocbcore.StoreCallback)
+			cb(&gocbcore.StoreResult{Cas: 1}, nil)
+		}).
+		Return(new(mockPendingOp), nil)
+
+	col := suite.collection("mock", "", "", suite.kvProviderCore(provider, nil))
+	col.compressor = &compressor{CompressionEnabled: true}
+
+	_, err := col.Upsert("big", compressible, &UpsertOptions{Transcoder: NewRawBinaryTranscoder()})
+	suite.Require().Nil(err, err)
+	provider.AssertNumberOfCalls(suite.T(), "Set", 1)
+}
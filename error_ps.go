@@ -1,3 +1,5 @@
+//go:build !gocb_nocouchbase2
+
 package gocb
 
 import (
@@ -114,6 +116,23 @@ func mapPsErrorStatusToGocbError(st *status.Status, readOnly bool) *GenericError
 					baseErr = ErrPathExists
 				}
 			}
+		case *errdetails.QuotaFailure:
+			if code == codes.ResourceExhausted && len(d.Violations) > 0 {
+				violation := d.Violations[0]
+				context["quota_violation"] = violation.Subject
+
+				limitType := rateLimitTypeFromText(violation.Subject + " " + violation.Description)
+				if limitType != "" {
+					context["limit"] = string(limitType)
+				}
+
+				// The limits which apply to usage, rather than to what is stored, are rate limits.
+				if limitType == "" || isQuotaLimit(limitType) {
+					baseErr = ErrQuotaLimitedFailure
+				} else {
+					baseErr = ErrRateLimitedFailure
+				}
+			}
 		case *errdetails.ErrorInfo:
 			context["reason"] = d.Reason
 			switch d.Reason {
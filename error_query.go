@@ -2,6 +2,9 @@ package gocb
 
 import (
 	"encoding/json"
+	"regexp"
+	"strings"
+
 	gocbcore "github.com/couchbase/gocbcore/v10"
 )
 
@@ -28,6 +31,49 @@ func (e QueryErrorDesc) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// IsRetriable returns whether the query service indicated that the request which caused this error can be retried.
+// UNCOMMITTED: This API may change in the future.
+func (e QueryErrorDesc) IsRetriable() bool {
+	return e.Retry
+}
+
+// QueryErrorReason is the typed form of the reason which can accompany an error returned from the query service,
+// such as the underlying key-value error which caused a DML statement to fail.
+// UNCOMMITTED: This API may change in the future.
+type QueryErrorReason struct {
+	Code    uint32
+	Key     string
+	Message string
+	Caller  string
+}
+
+// ReasonDetails returns the reason for this error in typed form. Any fields not present in Reason are left empty.
+// UNCOMMITTED: This API may change in the future.
+func (e QueryErrorDesc) ReasonDetails() QueryErrorReason {
+	var reason QueryErrorReason
+	if e.Reason == nil {
+		return reason
+	}
+
+	switch code := e.Reason["code"].(type) {
+	case float64:
+		reason.Code = uint32(code)
+	case int:
+		reason.Code = uint32(code)
+	case uint32:
+		reason.Code = code
+	case json.Number:
+		if c, err := code.Int64(); err == nil {
+			reason.Code = uint32(c)
+		}
+	}
+	reason.Key, _ = e.Reason["key"].(string)
+	reason.Message, _ = e.Reason["message"].(string)
+	reason.Caller, _ = e.Reason["caller"].(string)
+
+	return reason
+}
+
 func translateCoreQueryErrorDesc(descs []gocbcore.N1QLErrorDesc) []QueryErrorDesc {
 	descsOut := make([]QueryErrorDesc, len(descs))
 	for descIdx, desc := range descs {
@@ -41,6 +87,118 @@ func translateCoreQueryErrorDesc(descs []gocbcore.N1QLErrorDesc) []QueryErrorDes
 	return descsOut
 }
 
+// queryErrorFromDescs maps the errors returned by the query service onto the corresponding SDK error, for requests
+// which are not sent through gocbcore. The mapping mirrors the one which gocbcore applies to query responses and is
+// based on the first error returned, except that errors which do not map onto a more specific error are reported as
+// ErrInternalServerFailure so that they can be matched with errors.Is.
+func queryErrorFromDescs(descs []QueryErrorDesc) error {
+	if len(descs) == 0 {
+		return nil
+	}
+
+	firstErr := descs[0]
+	msgLower := strings.ToLower(firstErr.Message)
+
+	switch firstErr.Code / 1000 {
+	case 1:
+		switch firstErr.Code {
+		case 1065:
+			if strings.Contains(msgLower, "query_context") {
+				return wrapError(ErrFeatureNotAvailable, "this server requires that a query context be used for queries")
+			} else if strings.Contains(msgLower, "preserve_expiry") {
+				return wrapError(ErrFeatureNotAvailable, "this server does not support preserve expiry")
+			} else if strings.Contains(msgLower, "use_replica") {
+				return wrapError(ErrFeatureNotAvailable, "this server does not support use replica")
+			}
+		case 1080:
+			return ErrUnambiguousTimeout
+		case 1191, 1192, 1193, 1194:
+			return ErrRateLimitedFailure
+		case 1197:
+			return wrapError(ErrFeatureNotAvailable, "this server requires that a query context be used for queries")
+		}
+	case 3:
+		switch firstErr.Code {
+		case 3000:
+			return ErrParsingFailure
+		case 3230:
+			if strings.Contains(msgLower, "advisor") || strings.Contains(msgLower, "advise") {
+				return wrapError(ErrFeatureNotAvailable, "query index advisor is not supported on community edition")
+			} else if strings.Contains(msgLower, "query window functions") {
+				return wrapError(ErrFeatureNotAvailable, "query window functions are not supported on community edition")
+			}
+		}
+	case 4:
+		switch firstErr.Code {
+		case 4040, 4050, 4060, 4070, 4080, 4090:
+			return ErrPreparedStatementFailure
+		case 4300:
+			return ErrIndexExists
+		default:
+			return ErrPlanningFailure
+		}
+	case 5:
+		if firstErr.Code == 5000 {
+			if queryIndexNotFoundRegexp.MatchString(msgLower) || queryIndexNotExistRegexp.MatchString(msgLower) {
+				return ErrIndexNotFound
+			} else if queryIndexExistsRegexp.MatchString(msgLower) {
+				return ErrIndexExists
+			} else if strings.Contains(msgLower,
+				"limit for number of indexes that can be created per scope has been reached") {
+				return ErrQuotaLimitedFailure
+			}
+		}
+		return ErrInternalServerFailure
+	case 10:
+		return ErrAuthenticationFailure
+	case 12:
+		switch firstErr.Code {
+		case 12004, 12016:
+			return ErrIndexNotFound
+		case 12009:
+			return queryDMLErrorFromDesc(firstErr)
+		default:
+			return ErrIndexFailure
+		}
+	case 13:
+		if firstErr.Code == 13014 {
+			return ErrAuthenticationFailure
+		}
+	case 14:
+		return ErrIndexFailure
+	}
+
+	return ErrInternalServerFailure
+}
+
+var (
+	queryIndexNotFoundRegexp = regexp.MustCompile(".*?ndex .*? not found.*")
+	queryIndexNotExistRegexp = regexp.MustCompile(".*?ndex does not exist.*")
+	queryIndexExistsRegexp   = regexp.MustCompile(".*?ndex .*? already exist.*")
+)
+
+// queryDMLErrorFromDesc maps a failure of the data service during a DML statement using the underlying key-value
+// error, when the query service provided it.
+func queryDMLErrorFromDesc(desc QueryErrorDesc) error {
+	if desc.Reason != nil {
+		switch desc.ReasonDetails().Code {
+		case 12033:
+			return ErrCasMismatch
+		case 17014:
+			return ErrDocumentNotFound
+		case 17012:
+			return ErrDocumentExists
+		}
+
+		return gocbcore.ErrDMLFailure
+	}
+
+	if strings.Contains(strings.ToLower(desc.Message), "cas mismatch") {
+		return ErrCasMismatch
+	}
+	return gocbcore.ErrDMLFailure
+}
+
 // QueryError is the error type of all query errors.
 // UNCOMMITTED: This API may change in the future.
 type QueryError struct {
@@ -117,3 +275,70 @@ func (e QueryError) Error() string {
 func (e QueryError) Unwrap() error {
 	return e.InnerError
 }
+
+// IsRetriable returns whether the query service indicated that the request can be retried, which is the case when
+// every error it returned was flagged as retriable. Errors which did not come from the query service, and so have
+// no Errors, are never considered retriable.
+// UNCOMMITTED: This API may change in the future.
+func (e QueryError) IsRetriable() bool {
+	if len(e.Errors) == 0 {
+		return false
+	}
+
+	for _, desc := range e.Errors {
+		if !desc.IsRetriable() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// QueryParameterError occurs when the query service rejects one of the parameters sent as part of a query request,
+// for example because the server does not support the parameter or the value provided for it is invalid.
+// The InnerError will be ErrFeatureNotAvailable if the parameter is not supported, or ErrInvalidArgument if the value
+// was rejected.
+// UNCOMMITTED: This API may change in the future.
+type QueryParameterError struct {
+	InnerError error
+	Parameter  string
+}
+
+// Error returns the string representation of this error.
+func (e QueryParameterError) Error() string {
+	return e.InnerError.Error() + " | query parameter: " + e.Parameter
+}
+
+// Unwrap returns the underlying cause for this error.
+func (e QueryParameterError) Unwrap() error {
+	return e.InnerError
+}
+
+// queryParameterNames are the query request parameters which are surfaced as a QueryParameterError when the
+// query service rejects them.
+var queryParameterNames = []string{"preserve_expiry", "query_context", "use_replica"}
+
+func queryParameterErrorFromDesc(desc gocbcore.N1QLErrorDesc) *QueryParameterError {
+	var inner error
+	switch desc.Code {
+	case 1065:
+		// Unrecognized parameter.
+		inner = ErrFeatureNotAvailable
+	case 1040:
+		// Invalid value for parameter.
+		inner = ErrInvalidArgument
+	default:
+		return nil
+	}
+
+	for _, name := range queryParameterNames {
+		if strings.Contains(desc.Message, name) {
+			return &QueryParameterError{
+				InnerError: wrapError(inner, desc.Message),
+				Parameter:  name,
+			}
+		}
+	}
+
+	return nil
+}
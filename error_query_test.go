@@ -59,3 +59,63 @@ func (suite *UnitTestSuite) TestQueryErrorImproved() {
 		aErr.Error(),
 	)
 }
+
+func (suite *UnitTestSuite) TestQueryErrorIsRetriable() {
+	suite.Assert().False(QueryError{}.IsRetriable())
+
+	suite.Assert().True(QueryError{Errors: []QueryErrorDesc{
+		{Code: 12009, Retry: true},
+		{Code: 5000, Retry: true},
+	}}.IsRetriable())
+
+	suite.Assert().False(QueryError{Errors: []QueryErrorDesc{
+		{Code: 12009, Retry: true},
+		{Code: 3000},
+	}}.IsRetriable())
+}
+
+func (suite *UnitTestSuite) TestQueryErrorDescReasonDetails() {
+	var desc QueryErrorDesc
+	err := json.Unmarshal([]byte(`{"Code":12009,"Message":"DML Error","Reason":{"caller":"couchbase:2150",`+
+		`"code":17014,"key":"datastore.couchbase.DML_error","message":"Duplicate Key: k1","extra":true}}`), &desc)
+	suite.Require().Nil(err)
+
+	suite.Assert().Equal(QueryErrorReason{
+		Code:    17014,
+		Key:     "datastore.couchbase.DML_error",
+		Message: "Duplicate Key: k1",
+		Caller:  "couchbase:2150",
+	}, desc.ReasonDetails())
+	suite.Assert().Equal(true, desc.Reason["extra"])
+
+	suite.Assert().Equal(QueryErrorReason{}, QueryErrorDesc{Code: 1000}.ReasonDetails())
+}
+
+func (suite *UnitTestSuite) TestQueryErrorFromDescs() {
+	suite.Assert().Nil(queryErrorFromDescs(nil))
+
+	type tCase struct {
+		desc     QueryErrorDesc
+		expected error
+	}
+	testCases := []tCase{
+		{QueryErrorDesc{Code: 1080}, ErrUnambiguousTimeout},
+		{QueryErrorDesc{Code: 1193}, ErrRateLimitedFailure},
+		{QueryErrorDesc{Code: 3000}, ErrParsingFailure},
+		{QueryErrorDesc{Code: 4050}, ErrPreparedStatementFailure},
+		{QueryErrorDesc{Code: 4300}, ErrIndexExists},
+		{QueryErrorDesc{Code: 4000}, ErrPlanningFailure},
+		{QueryErrorDesc{Code: 5000, Message: "Index idx not found."}, ErrIndexNotFound},
+		{QueryErrorDesc{Code: 5000, Message: "Index idx already exists"}, ErrIndexExists},
+		{QueryErrorDesc{Code: 5000, Message: "something broke"}, ErrInternalServerFailure},
+		{QueryErrorDesc{Code: 10000}, ErrAuthenticationFailure},
+		{QueryErrorDesc{Code: 12009, Reason: map[string]interface{}{"code": float64(17012)}}, ErrDocumentExists},
+		{QueryErrorDesc{Code: 12009, Message: "CAS mismatch"}, ErrCasMismatch},
+		{QueryErrorDesc{Code: 12016}, ErrIndexNotFound},
+		{QueryErrorDesc{Code: 14000}, ErrIndexFailure},
+		{QueryErrorDesc{Code: 2000}, ErrInternalServerFailure},
+	}
+	for _, tc := range testCases {
+		suite.Assert().ErrorIs(queryErrorFromDescs([]QueryErrorDesc{tc.desc}), tc.expected, tc.desc.Code)
+	}
+}
@@ -0,0 +1,171 @@
+package gocb
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/couchbase/gocbcore/v10/memd"
+)
+
+// RateLimitType identifies the limit which caused an operation to fail with ErrRateLimitedFailure or
+// ErrQuotaLimitedFailure.
+// UNCOMMITTED: This API may change in the future.
+type RateLimitType string
+
+const (
+	// RateLimitTypeNetworkIngress is the limit on the data sent to a service per minute.
+	RateLimitTypeNetworkIngress RateLimitType = "ingress_mib_per_min"
+
+	// RateLimitTypeNetworkEgress is the limit on the data returned by a service per minute.
+	RateLimitTypeNetworkEgress RateLimitType = "egress_mib_per_min"
+
+	// RateLimitTypeConnections is the limit on the number of connections to the data service.
+	RateLimitTypeConnections RateLimitType = "num_connections"
+
+	// RateLimitTypeOperations is the limit on the number of data service operations per minute.
+	RateLimitTypeOperations RateLimitType = "num_ops_per_min"
+
+	// RateLimitTypeConcurrentRequests is the limit on the number of requests being processed by a service at once.
+	RateLimitTypeConcurrentRequests RateLimitType = "num_concurrent_requests"
+
+	// RateLimitTypeRequests is the limit on the number of requests to a service per minute.
+	RateLimitTypeRequests RateLimitType = "num_queries_per_min"
+
+	// RateLimitTypeDataSize is the quota on the amount of data stored in a scope.
+	RateLimitTypeDataSize RateLimitType = "data_size"
+
+	// RateLimitTypeIndexes is the quota on the number of query or search indexes in a scope.
+	RateLimitTypeIndexes RateLimitType = "num_indexes"
+
+	// RateLimitTypeCollections is the quota on the number of collections in a scope.
+	RateLimitTypeCollections RateLimitType = "num_collections"
+)
+
+// RateLimitDetails describes the limit which caused an operation to fail with ErrRateLimitedFailure or
+// ErrQuotaLimitedFailure.
+// UNCOMMITTED: This API may change in the future.
+type RateLimitDetails struct {
+	Type RateLimitType
+	// WithinPeriod indicates that the limit applies to usage within a period of a minute, so the operation can succeed
+	// once the period has elapsed. Otherwise the limit applies to concurrent usage or is a quota, and the operation
+	// can only succeed once usage has been reduced.
+	WithinPeriod bool
+}
+
+// RateLimitDetailsFromError returns the details of the limit which caused err, if err is ErrRateLimitedFailure or
+// ErrQuotaLimitedFailure and the limit could be identified from the server response.
+// UNCOMMITTED: This API may change in the future.
+func RateLimitDetailsFromError(err error) (*RateLimitDetails, bool) {
+	if !errors.Is(err, ErrRateLimitedFailure) && !errors.Is(err, ErrQuotaLimitedFailure) {
+		return nil, false
+	}
+
+	var limitType RateLimitType
+
+	var kvErr *KeyValueError
+	var queryErr *QueryError
+	var searchErr *SearchError
+	var httpErr *HTTPError
+	var genericErr *GenericError
+	switch {
+	case errors.As(err, &kvErr):
+		limitType = rateLimitTypeFromKVStatus(kvErr.StatusCode)
+	case errors.As(err, &queryErr):
+		for _, desc := range queryErr.Errors {
+			limitType = rateLimitTypeFromQueryError(desc)
+			if limitType != "" {
+				break
+			}
+		}
+	case errors.As(err, &searchErr):
+		limitType = rateLimitTypeFromText(searchErr.ErrorText)
+	case errors.As(err, &httpErr):
+		limitType = rateLimitTypeFromText(httpErr.ErrorText)
+	case errors.As(err, &genericErr):
+		// Couchbase2 errors record the limit identified from the details of the status.
+		if limit, ok := genericErr.Context["limit"].(string); ok {
+			limitType = RateLimitType(limit)
+		}
+	}
+
+	if limitType == "" {
+		return nil, false
+	}
+
+	return &RateLimitDetails{
+		Type: limitType,
+		WithinPeriod: limitType == RateLimitTypeNetworkIngress || limitType == RateLimitTypeNetworkEgress ||
+			limitType == RateLimitTypeOperations || limitType == RateLimitTypeRequests,
+	}, true
+}
+
+// isQuotaLimit returns whether a limit applies to what is stored, rather than to usage.
+func isQuotaLimit(limitType RateLimitType) bool {
+	return limitType == RateLimitTypeDataSize || limitType == RateLimitTypeIndexes ||
+		limitType == RateLimitTypeCollections
+}
+
+func rateLimitTypeFromKVStatus(status memd.StatusCode) RateLimitType {
+	switch status {
+	case memd.StatusRateLimitedNetworkIngress:
+		return RateLimitTypeNetworkIngress
+	case memd.StatusRateLimitedNetworkEgress:
+		return RateLimitTypeNetworkEgress
+	case memd.StatusRateLimitedMaxConnections:
+		return RateLimitTypeConnections
+	case memd.StatusRateLimitedMaxCommands:
+		return RateLimitTypeOperations
+	case memd.StatusRateLimitedScopeSizeLimitExceeded:
+		return RateLimitTypeDataSize
+	}
+
+	return ""
+}
+
+func rateLimitTypeFromQueryError(desc QueryErrorDesc) RateLimitType {
+	switch desc.Code {
+	case 1191:
+		return RateLimitTypeConcurrentRequests
+	case 1192:
+		return RateLimitTypeRequests
+	case 1193:
+		return RateLimitTypeNetworkIngress
+	case 1194:
+		return RateLimitTypeNetworkEgress
+	case 5000:
+		if strings.Contains(strings.ToLower(desc.Message), "limit for number of indexes") {
+			return RateLimitTypeIndexes
+		}
+	}
+
+	return ""
+}
+
+// rateLimitTypeFromText identifies the limit from the error text of the HTTP services, which name the limit that was
+// exceeded.
+func rateLimitTypeFromText(text string) RateLimitType {
+	text = strings.ToLower(text)
+
+	switch {
+	case strings.Contains(text, "num_concurrent_requests"):
+		return RateLimitTypeConcurrentRequests
+	case strings.Contains(text, "num_queries_per_min"):
+		return RateLimitTypeRequests
+	case strings.Contains(text, "ingress_mib_per_min"):
+		return RateLimitTypeNetworkIngress
+	case strings.Contains(text, "egress_mib_per_min"):
+		return RateLimitTypeNetworkEgress
+	case strings.Contains(text, "num_ops_per_min"):
+		return RateLimitTypeOperations
+	case strings.Contains(text, "num_connections"):
+		return RateLimitTypeConnections
+	case strings.Contains(text, "data_size"):
+		return RateLimitTypeDataSize
+	case strings.Contains(text, "num_fts_indexes"), strings.Contains(text, "num_indexes"):
+		return RateLimitTypeIndexes
+	case strings.Contains(text, "num_collections"), strings.Contains(text, "maximum number of collections"):
+		return RateLimitTypeCollections
+	}
+
+	return ""
+}
@@ -0,0 +1,120 @@
+package gocb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/couchbase/gocbcore/v10/memd"
+)
+
+func (suite *UnitTestSuite) TestRateLimitDetailsFromError() {
+	type tCase struct {
+		name         string
+		err          error
+		expectedType RateLimitType
+		withinPeriod bool
+	}
+
+	testCases := []tCase{
+		{
+			name: "kv ops per min",
+			err: &KeyValueError{
+				InnerError: ErrRateLimitedFailure,
+				StatusCode: memd.StatusRateLimitedMaxCommands,
+			},
+			expectedType: RateLimitTypeOperations,
+			withinPeriod: true,
+		},
+		{
+			name: "kv scope size",
+			err: &KeyValueError{
+				InnerError: ErrQuotaLimitedFailure,
+				StatusCode: memd.StatusRateLimitedScopeSizeLimitExceeded,
+			},
+			expectedType: RateLimitTypeDataSize,
+		},
+		{
+			name: "query concurrent requests",
+			err: &QueryError{
+				InnerError: ErrRateLimitedFailure,
+				Errors:     []QueryErrorDesc{{Code: 1191, Message: "User has more requests running than allowed"}},
+			},
+			expectedType: RateLimitTypeConcurrentRequests,
+		},
+		{
+			name: "query requests per min",
+			err: &QueryError{
+				InnerError: ErrRateLimitedFailure,
+				Errors:     []QueryErrorDesc{{Code: 1192, Message: "User has exceeded request rate limit"}},
+			},
+			expectedType: RateLimitTypeRequests,
+			withinPeriod: true,
+		},
+		{
+			name: "query network ingress",
+			err: &QueryError{
+				InnerError: ErrRateLimitedFailure,
+				Errors:     []QueryErrorDesc{{Code: 1193, Message: "User has exceeded input network traffic limit"}},
+			},
+			expectedType: RateLimitTypeNetworkIngress,
+			withinPeriod: true,
+		},
+		{
+			name: "query network egress",
+			err: &QueryError{
+				InnerError: ErrRateLimitedFailure,
+				Errors:     []QueryErrorDesc{{Code: 1194, Message: "User has exceeded results size limit"}},
+			},
+			expectedType: RateLimitTypeNetworkEgress,
+			withinPeriod: true,
+		},
+		{
+			name: "query index quota",
+			err: &QueryError{
+				InnerError: ErrQuotaLimitedFailure,
+				Errors:     []QueryErrorDesc{{Code: 5000, Message: "GSI CreateIndex() - cause: Limit for number of indexes that can be created per scope has been reached. Limit : 1"}},
+			},
+			expectedType: RateLimitTypeIndexes,
+		},
+		{
+			name: "search queries per min",
+			err: &SearchError{
+				InnerError: ErrRateLimitedFailure,
+				ErrorText:  "rest_auth: preparePerm, err: num_queries_per_min limit exceeded",
+			},
+			expectedType: RateLimitTypeRequests,
+			withinPeriod: true,
+		},
+		{
+			name: "http collections quota",
+			err: &HTTPError{
+				InnerError: ErrQuotaLimitedFailure,
+				ErrorText:  "Maximum number of collections has been reached for scope \"limitedScope\"",
+			},
+			expectedType: RateLimitTypeCollections,
+		},
+		{
+			name: "couchbase2 data size quota",
+			err: &GenericError{
+				InnerError: ErrQuotaLimitedFailure,
+				Context:    map[string]interface{}{"quota_violation": "data_size", "limit": "data_size"},
+			},
+			expectedType: RateLimitTypeDataSize,
+		},
+	}
+
+	for _, tCase := range testCases {
+		suite.T().Run(tCase.name, func(te *testing.T) {
+			details, ok := RateLimitDetailsFromError(tCase.err)
+			suite.Require().True(ok)
+			suite.Assert().Equal(tCase.expectedType, details.Type)
+			suite.Assert().Equal(tCase.withinPeriod, details.WithinPeriod)
+		})
+	}
+
+	_, ok := RateLimitDetailsFromError(&KeyValueError{InnerError: ErrDocumentNotFound, StatusCode: memd.StatusKeyNotFound})
+	suite.Assert().False(ok)
+
+	_, ok = RateLimitDetailsFromError(errors.New("rate limited"))
+	suite.Assert().False(ok)
+}
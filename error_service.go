@@ -0,0 +1,80 @@
+package gocb
+
+// ServiceErrorDetails contains the HTTP level details of an error returned by one of the HTTP based services.
+// UNCOMMITTED: This API may change in the future.
+type ServiceErrorDetails struct {
+	Service        ServiceType
+	Endpoint       string
+	HTTPStatusCode int
+	RetryReasons   []RetryReason
+	RetryAttempts  uint32
+}
+
+// ServiceError is implemented by the errors returned by the HTTP based services (QueryError, AnalyticsError,
+// SearchError, ViewError and HTTPError) so that they can be handled uniformly, for example by HTTP level alerting.
+// It can be retrieved from an error chain using errors.As.
+// UNCOMMITTED: This API may change in the future.
+type ServiceError interface {
+	error
+	ServiceErrorDetails() ServiceErrorDetails
+}
+
+// ServiceErrorDetails returns the HTTP level details of this error.
+// UNCOMMITTED: This API may change in the future.
+func (e QueryError) ServiceErrorDetails() ServiceErrorDetails {
+	return ServiceErrorDetails{
+		Service:        ServiceTypeQuery,
+		Endpoint:       e.Endpoint,
+		HTTPStatusCode: e.HTTPStatusCode,
+		RetryReasons:   e.RetryReasons,
+		RetryAttempts:  e.RetryAttempts,
+	}
+}
+
+// ServiceErrorDetails returns the HTTP level details of this error.
+// UNCOMMITTED: This API may change in the future.
+func (e AnalyticsError) ServiceErrorDetails() ServiceErrorDetails {
+	return ServiceErrorDetails{
+		Service:        ServiceTypeAnalytics,
+		Endpoint:       e.Endpoint,
+		HTTPStatusCode: e.HTTPStatusCode,
+		RetryReasons:   e.RetryReasons,
+		RetryAttempts:  e.RetryAttempts,
+	}
+}
+
+// ServiceErrorDetails returns the HTTP level details of this error.
+// UNCOMMITTED: This API may change in the future.
+func (e SearchError) ServiceErrorDetails() ServiceErrorDetails {
+	return ServiceErrorDetails{
+		Service:        ServiceTypeSearch,
+		Endpoint:       e.Endpoint,
+		HTTPStatusCode: e.HTTPStatusCode,
+		RetryReasons:   e.RetryReasons,
+		RetryAttempts:  e.RetryAttempts,
+	}
+}
+
+// ServiceErrorDetails returns the HTTP level details of this error.
+// UNCOMMITTED: This API may change in the future.
+func (e ViewError) ServiceErrorDetails() ServiceErrorDetails {
+	return ServiceErrorDetails{
+		Service:        ServiceTypeViews,
+		Endpoint:       e.Endpoint,
+		HTTPStatusCode: e.HTTPStatusCode,
+		RetryReasons:   e.RetryReasons,
+		RetryAttempts:  e.RetryAttempts,
+	}
+}
+
+// ServiceErrorDetails returns the HTTP level details of this error.
+// UNCOMMITTED: This API may change in the future.
+func (e HTTPError) ServiceErrorDetails() ServiceErrorDetails {
+	return ServiceErrorDetails{
+		Service:        ServiceTypeManagement,
+		Endpoint:       e.Endpoint,
+		HTTPStatusCode: int(e.StatusCode),
+		RetryReasons:   e.RetryReasons,
+		RetryAttempts:  e.RetryAttempts,
+	}
+}
@@ -0,0 +1,125 @@
+package gocb
+
+import (
+	"errors"
+
+	gocbcore "github.com/couchbase/gocbcore/v10"
+)
+
+func (suite *UnitTestSuite) TestServiceErrorDetails() {
+	type tCase struct {
+		name    string
+		err     error
+		service ServiceType
+	}
+
+	reasons := []RetryReason{ServiceNotAvailableRetryReason}
+	tCases := []tCase{
+		{
+			name:    "query",
+			err:     &QueryError{Endpoint: "10.0.0.1:8093", HTTPStatusCode: 500, RetryAttempts: 2, RetryReasons: reasons},
+			service: ServiceTypeQuery,
+		},
+		{
+			name:    "analytics",
+			err:     &AnalyticsError{Endpoint: "10.0.0.1:8093", HTTPStatusCode: 500, RetryAttempts: 2, RetryReasons: reasons},
+			service: ServiceTypeAnalytics,
+		},
+		{
+			name:    "search",
+			err:     &SearchError{Endpoint: "10.0.0.1:8093", HTTPStatusCode: 500, RetryAttempts: 2, RetryReasons: reasons},
+			service: ServiceTypeSearch,
+		},
+		{
+			name:    "views",
+			err:     &ViewError{Endpoint: "10.0.0.1:8093", HTTPStatusCode: 500, RetryAttempts: 2, RetryReasons: reasons},
+			service: ServiceTypeViews,
+		},
+		{
+			name:    "http",
+			err:     &HTTPError{Endpoint: "10.0.0.1:8093", StatusCode: 500, RetryAttempts: 2, RetryReasons: reasons},
+			service: ServiceTypeManagement,
+		},
+	}
+
+	for _, tCase := range tCases {
+		suite.Run(tCase.name, func() {
+			var svcErr ServiceError
+			suite.Require().True(errors.As(wrapError(tCase.err, "wrapped"), &svcErr))
+
+			suite.Assert().Equal(ServiceErrorDetails{
+				Service:        tCase.service,
+				Endpoint:       "10.0.0.1:8093",
+				HTTPStatusCode: 500,
+				RetryReasons:   reasons,
+				RetryAttempts:  2,
+			}, svcErr.ServiceErrorDetails())
+		})
+	}
+}
+
+type endpointQueryRowReader struct {
+	mockQueryRowReader
+	endpoint string
+}
+
+func (r *endpointQueryRowReader) Endpoint() string {
+	return r.endpoint
+}
+
+func (suite *UnitTestSuite) TestQueryStreamErrorEndpoint() {
+	reader := &endpointQueryRowReader{
+		mockQueryRowReader: mockQueryRowReader{
+			mockQueryRowReaderBase: mockQueryRowReaderBase{
+				RowsErr: &gocbcore.N1QLError{
+					InnerError:       errors.New("query error"),
+					HTTPResponseCode: 500,
+				},
+				Suite: suite,
+			},
+		},
+		endpoint: "10.0.0.1:8093",
+	}
+
+	result := newQueryResult(newQueryProviderCoreRowReader(nil, reader, defaultNoopSpan))
+
+	var svcErr ServiceError
+	suite.Require().True(errors.As(result.Err(), &svcErr))
+	suite.Assert().Equal("10.0.0.1:8093", svcErr.ServiceErrorDetails().Endpoint)
+	suite.Assert().Equal(500, svcErr.ServiceErrorDetails().HTTPStatusCode)
+}
+
+func (suite *UnitTestSuite) TestQueryStreamReadErrorEndpoint() {
+	reader := &endpointQueryRowReader{
+		mockQueryRowReader: mockQueryRowReader{
+			mockQueryRowReaderBase: mockQueryRowReaderBase{
+				RowsErr: errors.New("unexpected EOF"),
+				Suite:   suite,
+			},
+		},
+		endpoint: "10.0.0.1:8093",
+	}
+
+	result := newQueryResult(newQueryProviderCoreRowReader(nil, reader, defaultNoopSpan))
+
+	var svcErr ServiceError
+	suite.Require().True(errors.As(result.Err(), &svcErr))
+	suite.Assert().Equal("10.0.0.1:8093", svcErr.ServiceErrorDetails().Endpoint)
+}
+
+func (suite *UnitTestSuite) TestHTTPErrorFromCoreError() {
+	err := makeGenericHTTPError(&gocbcore.HTTPError{
+		InnerError:    gocbcore.ErrServiceNotAvailable,
+		Endpoint:      "http://10.0.0.1:8091",
+		RetryReasons:  []gocbcore.RetryReason{gocbcore.ServiceNotAvailableRetryReason},
+		RetryAttempts: 3,
+	}, &gocbcore.HTTPRequest{UniqueID: "1234"}, nil)
+
+	var httpErr *HTTPError
+	suite.Require().True(errors.As(err, &httpErr))
+	suite.Assert().ErrorIs(err, ErrServiceNotAvailable)
+	suite.Assert().Equal("1234", httpErr.UniqueID)
+	suite.Assert().Equal("http://10.0.0.1:8091", httpErr.Endpoint)
+	suite.Assert().Equal(uint32(3), httpErr.RetryAttempts)
+	suite.Assert().Equal([]RetryReason{ServiceNotAvailableRetryReason}, httpErr.RetryReasons)
+}
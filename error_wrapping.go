@@ -2,6 +2,7 @@ package gocb
 
 import (
 	"errors"
+	"strings"
 
 	gocbcore "github.com/couchbase/gocbcore/v10"
 )
@@ -50,12 +51,32 @@ func maybeEnhanceCoreErr(err error) error {
 				// We replace the gocbcore wrapped inner feature not available error with our own to provide gocb
 				// specific context for the user.
 				if desc.Code == 1197 {
-					inner = wrapError(ErrFeatureNotAvailable, "this server requires that scope.Query() is used rather than "+
-						"cluster.Query(), if this is a transaction then pass a Scope within TransactionQueryOptions")
+					inner = &QueryParameterError{
+						InnerError: wrapError(ErrFeatureNotAvailable, "this server requires that scope.Query() is used rather than "+
+							"cluster.Query(), if this is a transaction then pass a Scope within TransactionQueryOptions"),
+						Parameter: "query_context",
+					}
 				}
 			}
 		}
 
+		for _, desc := range queryErr.Errors {
+			if paramErr := queryParameterErrorFromDesc(desc); paramErr != nil {
+				inner = paramErr
+				break
+			}
+		}
+
+		// gocbcore rejects use_replica before sending the request when the cluster does not advertise support for it,
+		// in which case there are no error descriptors to inspect.
+		if len(queryErr.Errors) == 0 && errors.Is(inner, ErrFeatureNotAvailable) &&
+			strings.Contains(strings.ToLower(inner.Error()), "use replica") {
+			inner = &QueryParameterError{
+				InnerError: inner,
+				Parameter:  "use_replica",
+			}
+		}
+
 		return &QueryError{
 			InnerError:      inner,
 			Statement:       queryErr.Statement,
@@ -50,6 +50,16 @@ var (
 	ErrIllegalState = gocbcore.ErrIllegalState
 
 	ErrAttemptNotFoundOnQuery = errors.New("transactionAttempt not found on query")
+
+	// ErrTransactionCollectionLimitExceeded indicates that a transaction attempted to mutate documents in more
+	// collections than permitted by TransactionLimits.MaxCollections.
+	// UNCOMMITTED: This API may change in the future.
+	ErrTransactionCollectionLimitExceeded = errors.New("transaction collection limit exceeded")
+
+	// ErrTransactionMutationLimitExceeded indicates that a transaction attempted to mutate more documents than
+	// permitted by TransactionLimits.MaxMutations.
+	// UNCOMMITTED: This API may change in the future.
+	ErrTransactionMutationLimitExceeded = errors.New("transaction mutation limit exceeded")
 )
 
 type TransactionFailedError struct {
@@ -1,5 +1,7 @@
 package gocb
 
+import "encoding/json"
+
 type eventingManagementProvider interface {
 	UpsertFunction(scope *Scope, function EventingFunction, opts *UpsertEventingFunctionOptions) error
 	DropFunction(scope *Scope, name string, opts *DropEventingFunctionOptions) error
@@ -10,4 +12,8 @@ type eventingManagementProvider interface {
 	PauseFunction(scope *Scope, name string, opts *PauseEventingFunctionOptions) error
 	ResumeFunction(scope *Scope, name string, opts *ResumeEventingFunctionOptions) error
 	FunctionsStatus(scope *Scope, opts *EventingFunctionsStatusOptions) (*EventingStatus, error)
+	ExportFunctions(scope *Scope, opts *ExportEventingFunctionsOptions) ([]json.RawMessage, error)
+	ImportFunctions(scope *Scope, functions []json.RawMessage, opts *ImportEventingFunctionsOptions) error
+	ValidateFunction(scope *Scope, function EventingFunction, opts *ValidateEventingFunctionOptions) error
+	UpdateFunctionSettings(scope *Scope, name string, settings EventingFunctionSettings, opts *UpdateEventingFunctionSettingsOptions) error
 }
@@ -1,3 +1,5 @@
+//go:build !gocb_noeventing
+
 package gocb
 
 import (
@@ -6,7 +8,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -18,128 +22,15 @@ type eventingManagementProviderCore struct {
 }
 
 type eventingRequestOptions struct {
-	Timeout       time.Duration
+	Timeout time.Duration
+	// Deadline takes precedence over Timeout when set, so that an operation made up of several requests can share
+	// its timeout between them.
+	Deadline      time.Time
 	RetryStrategy RetryStrategy
 	ParentSpan    RequestSpan
 	Context       context.Context
 }
 
-type jsonEventingFunction struct {
-	Name               string                               `json:"appname"`
-	Code               string                               `json:"appcode"`
-	Version            string                               `json:"version"`
-	EnforceSchema      bool                                 `json:"enforce_schema,omitempty"`
-	HandlerUUID        int                                  `json:"handleruuid,omitempty"`
-	FunctionInstanceID string                               `json:"function_instance_id,omitempty"`
-	Settings           jsonEventingFunctionSettings         `json:"settings"`
-	DeploymentConfig   jsonEventingFunctionDeploymentConfig `json:"depcfg"`
-	FunctionScope      *jsonEventingFunctionScope           `json:"function_scope,omitempty"`
-}
-
-type jsonEventingFunctionScope struct {
-	BucketName string `json:"bucket"`
-	ScopeName  string `json:"scope"`
-}
-
-type jsonEventingFunctionSettings struct {
-	CPPWorkerThreadCount   int                                   `json:"cpp_worker_thread_count,omitempty"`
-	DCPStreamBoundary      EventingFunctionDCPBoundary           `json:"dcp_stream_boundary,omitempty"`
-	Description            string                                `json:"description,omitempty"`
-	DeploymentStatus       EventingFunctionDeploymentStatus      `json:"deployment_status"`
-	ProcessingStatus       EventingFunctionProcessingStatus      `json:"processing_status"`
-	LanguageCompatibility  EventingFunctionLanguageCompatibility `json:"language_compatibility,omitempty"`
-	LogLevel               EventingFunctionLogLevel              `json:"log_level,omitempty"`
-	ExecutionTimeout       int                                   `json:"execution_timeout,omitempty"`
-	LCBInstCapacity        int                                   `json:"lcb_inst_capacity,omitempty"`
-	LCBRetryCount          int                                   `json:"lcb_retry_count,omitempty"`
-	LCBTimeout             int                                   `json:"lcb_timeout,omitempty"`
-	QueryConsistency       QueryScanConsistency                  `json:"n1ql_consistency,omitempty"`
-	NumTimerPartitions     int                                   `json:"num_timer_partitions,omitempty"`
-	SockBatchSize          int                                   `json:"sock_batch_size,omitempty"`
-	TickDuration           int                                   `json:"tick_duration,omitempty"`
-	TimerContextSize       int                                   `json:"timer_context_size,omitempty"`
-	UserPrefix             string                                `json:"user_prefix,omitempty"`
-	BucketCacheSize        int                                   `json:"bucket_cache_size,omitempty"`
-	BucketCacheAge         int                                   `json:"bucket_cache_age,omitempty"`
-	CurlMaxAllowedRespSize int                                   `json:"curl_max_allowed_resp_size,omitempty"`
-	QueryPrepareAll        bool                                  `json:"n1ql_prepare_all,omitempty"`
-	WorkerCount            int                                   `json:"worker_count,omitempty"`
-	HandlerHeaders         []string                              `json:"handler_headers,omitempty"`
-	HandlerFooters         []string                              `json:"handler_footers,omitempty"`
-	EnableAppLogRotation   bool                                  `json:"enable_applog_rotation,omitempty"`
-	AppLogDir              string                                `json:"app_log_dir,omitempty"`
-	AppLogMaxSize          int                                   `json:"app_log_max_size,omitempty"`
-	AppLogMaxFiles         int                                   `json:"app_log_max_files,omitempty"`
-	CheckpointInterval     int                                   `json:"checkpoint_interval,omitempty"`
-}
-
-type jsonEventingFunctionDeploymentConfig struct {
-	MetadataBucket     string                                `json:"metadata_bucket"`
-	MetadataScope      string                                `json:"metadata_scope,omitempty"`
-	MetadataCollection string                                `json:"metadata_collection,omitempty"`
-	SourceBucket       string                                `json:"source_bucket"`
-	SourceScope        string                                `json:"source_scope,omitempty"`
-	SourceCollection   string                                `json:"source_collection,omitempty"`
-	BucketBindings     []jsonEventingFunctionBucketBinding   `json:"buckets,omitempty"`
-	UrlBindings        []jsonEventingFunctionUrlBinding      `json:"curl,omitempty"`
-	ConstantBindings   []jsonEventingFunctionConstantBinding `json:"constants,omitempty"`
-}
-
-type jsonEventingFunctionBucketBinding struct {
-	Alias      string                       `json:"alias"`
-	Bucket     string                       `json:"bucket_name"`
-	Scope      string                       `json:"scope_name,omitempty"`
-	Collection string                       `json:"collection_name,omitempty"`
-	Access     EventingFunctionBucketAccess `json:"access"`
-}
-
-type jsonEventingFunctionUrlBinding struct {
-	Hostname               string `json:"hostname"`
-	Alias                  string `json:"value"`
-	AuthType               string `json:"auth_type"`
-	AllowCookies           bool   `json:"allow_cookies"`
-	ValidateSSLCertificate bool   `json:"validate_ssl_certificate"`
-	Username               string `json:"username,omitempty"`
-	Password               string `json:"password,omitempty"`
-	BearerKey              string `json:"bearer_key,omitempty"`
-}
-
-type jsonEventingFunctionConstantBinding struct {
-	Alias   string `json:"value"`
-	Literal string `json:"literal"`
-}
-
-type jsonEventingFunctionsStatusApp struct {
-	CompositeStatus       EventingFunctionStatus           `json:"composite_status"`
-	Name                  string                           `json:"name"`
-	NumBootstrappingNodes int                              `json:"num_bootstrapping_nodes"`
-	NumDeployedNodes      int                              `json:"num_deployed_nodes"`
-	DeploymentStatus      EventingFunctionDeploymentStatus `json:"deployment_status"`
-	ProcessingStatus      EventingFunctionProcessingStatus `json:"processing_status"`
-	FunctionScope         *jsonEventingFunctionScope       `json:"function_scope,omitempty"`
-}
-
-type jsonEventingFunctionsStatus struct {
-	Apps             []jsonEventingFunctionsStatusApp `json:"apps"`
-	NumEventingNodes int                              `json:"num_eventing_nodes"`
-}
-
-func (jf *jsonEventingFunction) MatchesScope(scope *Scope) bool {
-	if scope == nil {
-		return jf.FunctionScope == nil || (jf.FunctionScope.ScopeName == "*" && jf.FunctionScope.BucketName == "*")
-	}
-
-	return jf.FunctionScope != nil && jf.FunctionScope.ScopeName == scope.Name() && jf.FunctionScope.BucketName == scope.BucketName()
-}
-
-func (jfs *jsonEventingFunctionsStatusApp) MatchesScope(scope *Scope) bool {
-	if scope == nil {
-		return jfs.FunctionScope == nil || (jfs.FunctionScope.ScopeName == "*" && jfs.FunctionScope.BucketName == "*")
-	}
-
-	return jfs.FunctionScope != nil && jfs.FunctionScope.ScopeName == scope.Name() && jfs.FunctionScope.BucketName == scope.BucketName()
-}
-
 type eventingResult interface {
 	decodeAndFilter(decoder *json.Decoder, scope *Scope) error
 }
@@ -165,6 +56,33 @@ func (efs *eventingFunctions) decodeAndFilter(decoder *json.Decoder, scope *Scop
 	return nil
 }
 
+type eventingExportedFunctions struct {
+	functions []json.RawMessage
+}
+
+func (efs *eventingExportedFunctions) decodeAndFilter(decoder *json.Decoder, scope *Scope) error {
+	var rawFunctions []json.RawMessage
+	err := decoder.Decode(&rawFunctions)
+	if err != nil {
+		return err
+	}
+
+	for _, rawFunc := range rawFunctions {
+		// Only the function scope is decoded, the function itself is passed through untouched so that fields which
+		// are not known to this SDK survive an export and import.
+		var jsonFunc jsonEventingFunction
+		err = json.Unmarshal(rawFunc, &jsonFunc)
+		if err != nil {
+			return err
+		}
+
+		if jsonFunc.MatchesScope(scope) {
+			efs.functions = append(efs.functions, rawFunc)
+		}
+	}
+	return nil
+}
+
 func (ef *EventingFunction) decodeAndFilter(decoder *json.Decoder, scope *Scope) error {
 	err := decoder.Decode(&ef)
 	return err
@@ -196,183 +114,6 @@ func (es *EventingStatus) decodeAndFilter(decoder *json.Decoder, scope *Scope) e
 	return nil
 }
 
-func (ef *EventingFunction) toJSONEventingFunction() jsonEventingFunction {
-	var bucketBindings []jsonEventingFunctionBucketBinding
-	for _, b := range ef.BucketBindings {
-		bucketBindings = append(bucketBindings, jsonEventingFunctionBucketBinding{
-			Alias:      b.Alias,
-			Bucket:     b.Name.Bucket,
-			Scope:      b.Name.Scope,
-			Collection: b.Name.Collection,
-			Access:     b.Access,
-		})
-	}
-	var urlBindings []jsonEventingFunctionUrlBinding
-	for _, b := range ef.UrlBindings {
-		urlBindings = append(urlBindings, jsonEventingFunctionUrlBinding{
-			Hostname:               b.Hostname,
-			Alias:                  b.Alias,
-			AuthType:               b.Auth.Method(),
-			AllowCookies:           b.AllowCookies,
-			ValidateSSLCertificate: b.ValidateSSLCertificate,
-			Username:               b.Auth.Username(),
-			Password:               b.Auth.Password(),
-			BearerKey:              b.Auth.Key(),
-		})
-	}
-	var constantBindings []jsonEventingFunctionConstantBinding
-	for _, b := range ef.ConstantBindings {
-		constantBindings = append(constantBindings, jsonEventingFunctionConstantBinding(b))
-	}
-
-	return jsonEventingFunction{
-		Name:               ef.Name,
-		Code:               ef.Code,
-		Version:            ef.Version,
-		EnforceSchema:      ef.EnforceSchema,
-		HandlerUUID:        ef.HandlerUUID,
-		FunctionInstanceID: ef.FunctionInstanceID,
-		Settings: jsonEventingFunctionSettings{
-			CPPWorkerThreadCount:   ef.Settings.CPPWorkerThreadCount,
-			DCPStreamBoundary:      ef.Settings.DCPStreamBoundary,
-			Description:            ef.Settings.Description,
-			DeploymentStatus:       ef.Settings.DeploymentStatus,
-			ProcessingStatus:       ef.Settings.ProcessingStatus,
-			LanguageCompatibility:  ef.Settings.LanguageCompatibility,
-			LogLevel:               ef.Settings.LogLevel,
-			ExecutionTimeout:       int(ef.Settings.ExecutionTimeout.Seconds()),
-			LCBInstCapacity:        ef.Settings.LCBInstCapacity,
-			LCBRetryCount:          ef.Settings.LCBRetryCount,
-			LCBTimeout:             int(ef.Settings.LCBTimeout.Seconds()),
-			QueryConsistency:       ef.Settings.QueryConsistency,
-			NumTimerPartitions:     ef.Settings.NumTimerPartitions,
-			SockBatchSize:          ef.Settings.SockBatchSize,
-			TickDuration:           int(ef.Settings.TickDuration.Milliseconds()),
-			TimerContextSize:       ef.Settings.TimerContextSize,
-			UserPrefix:             ef.Settings.UserPrefix,
-			BucketCacheSize:        ef.Settings.BucketCacheSize,
-			BucketCacheAge:         ef.Settings.BucketCacheAge,
-			CurlMaxAllowedRespSize: ef.Settings.CurlMaxAllowedRespSize,
-			QueryPrepareAll:        ef.Settings.QueryPrepareAll,
-			WorkerCount:            ef.Settings.WorkerCount,
-			HandlerHeaders:         ef.Settings.HandlerHeaders,
-			HandlerFooters:         ef.Settings.HandlerFooters,
-			EnableAppLogRotation:   ef.Settings.EnableAppLogRotation,
-			AppLogDir:              ef.Settings.AppLogDir,
-			AppLogMaxSize:          ef.Settings.AppLogMaxSize,
-			AppLogMaxFiles:         ef.Settings.AppLogMaxFiles,
-			CheckpointInterval:     int(ef.Settings.CheckpointInterval.Seconds()),
-		},
-		DeploymentConfig: jsonEventingFunctionDeploymentConfig{
-			MetadataBucket:     ef.MetadataKeyspace.Bucket,
-			MetadataScope:      ef.MetadataKeyspace.Scope,
-			MetadataCollection: ef.MetadataKeyspace.Collection,
-			SourceBucket:       ef.SourceKeyspace.Bucket,
-			SourceScope:        ef.SourceKeyspace.Scope,
-			SourceCollection:   ef.SourceKeyspace.Collection,
-			BucketBindings:     bucketBindings,
-			UrlBindings:        urlBindings,
-			ConstantBindings:   constantBindings,
-		},
-	}
-}
-
-func (ef *EventingFunction) fromJSONEventingFunction(jf jsonEventingFunction) {
-	var bucketBindings []EventingFunctionBucketBinding
-	for _, b := range jf.DeploymentConfig.BucketBindings {
-		bucketBindings = append(bucketBindings, EventingFunctionBucketBinding{
-			Alias: b.Alias,
-			Name: EventingFunctionKeyspace{
-				Bucket:     b.Bucket,
-				Scope:      b.Scope,
-				Collection: b.Collection,
-			},
-			Access: b.Access,
-		})
-	}
-	var urlBindings []EventingFunctionUrlBinding
-	for _, b := range jf.DeploymentConfig.UrlBindings {
-		var auth EventingFunctionUrlAuth
-		switch b.AuthType {
-		case "no-auth":
-			auth = EventingFunctionUrlNoAuth{}
-		case "basic":
-			auth = EventingFunctionUrlAuthBasic{
-				User: b.Username,
-			}
-		case "digest":
-			auth = EventingFunctionUrlAuthDigest{
-				User: b.Username,
-			}
-		case "bearer":
-			auth = EventingFunctionUrlAuthBearer{}
-		}
-
-		urlBindings = append(urlBindings, EventingFunctionUrlBinding{
-			Hostname:               b.Hostname,
-			Alias:                  b.Alias,
-			Auth:                   auth,
-			AllowCookies:           b.AllowCookies,
-			ValidateSSLCertificate: b.ValidateSSLCertificate,
-		})
-	}
-	var constantBindings []EventingFunctionConstantBinding
-	for _, b := range jf.DeploymentConfig.ConstantBindings {
-		constantBindings = append(constantBindings, EventingFunctionConstantBinding(b))
-	}
-
-	ef.Name = jf.Name
-	ef.Code = jf.Code
-	ef.Version = jf.Version
-	ef.EnforceSchema = jf.EnforceSchema
-	ef.HandlerUUID = jf.HandlerUUID
-	ef.FunctionInstanceID = jf.FunctionInstanceID
-	ef.Settings = EventingFunctionSettings{
-		CPPWorkerThreadCount:   jf.Settings.CPPWorkerThreadCount,
-		DCPStreamBoundary:      jf.Settings.DCPStreamBoundary,
-		Description:            jf.Settings.Description,
-		DeploymentStatus:       jf.Settings.DeploymentStatus,
-		ProcessingStatus:       jf.Settings.ProcessingStatus,
-		LanguageCompatibility:  jf.Settings.LanguageCompatibility,
-		LogLevel:               jf.Settings.LogLevel,
-		ExecutionTimeout:       time.Duration(jf.Settings.ExecutionTimeout) * time.Second,
-		LCBInstCapacity:        jf.Settings.LCBInstCapacity,
-		LCBRetryCount:          jf.Settings.LCBRetryCount,
-		LCBTimeout:             time.Duration(jf.Settings.LCBTimeout) * time.Second,
-		QueryConsistency:       jf.Settings.QueryConsistency,
-		NumTimerPartitions:     jf.Settings.NumTimerPartitions,
-		SockBatchSize:          jf.Settings.SockBatchSize,
-		TickDuration:           time.Duration(jf.Settings.TickDuration) * time.Millisecond,
-		TimerContextSize:       jf.Settings.TimerContextSize,
-		UserPrefix:             jf.Settings.UserPrefix,
-		BucketCacheSize:        jf.Settings.BucketCacheSize,
-		BucketCacheAge:         jf.Settings.BucketCacheAge,
-		CurlMaxAllowedRespSize: jf.Settings.CurlMaxAllowedRespSize,
-		QueryPrepareAll:        jf.Settings.QueryPrepareAll,
-		WorkerCount:            jf.Settings.WorkerCount,
-		HandlerHeaders:         jf.Settings.HandlerHeaders,
-		HandlerFooters:         jf.Settings.HandlerFooters,
-		EnableAppLogRotation:   jf.Settings.EnableAppLogRotation,
-		AppLogDir:              jf.Settings.AppLogDir,
-		AppLogMaxSize:          jf.Settings.AppLogMaxSize,
-		AppLogMaxFiles:         jf.Settings.AppLogMaxFiles,
-		CheckpointInterval:     time.Duration(jf.Settings.CheckpointInterval) * time.Second,
-	}
-	ef.MetadataKeyspace = EventingFunctionKeyspace{
-		Bucket:     jf.DeploymentConfig.MetadataBucket,
-		Scope:      jf.DeploymentConfig.MetadataScope,
-		Collection: jf.DeploymentConfig.MetadataCollection,
-	}
-	ef.SourceKeyspace = EventingFunctionKeyspace{
-		Bucket:     jf.DeploymentConfig.SourceBucket,
-		Scope:      jf.DeploymentConfig.SourceScope,
-		Collection: jf.DeploymentConfig.SourceCollection,
-	}
-	ef.BucketBindings = bucketBindings
-	ef.UrlBindings = urlBindings
-	ef.ConstantBindings = constantBindings
-}
-
 func (emp *eventingManagementProviderCore) doMgmtRequest(ctx context.Context, req mgmtRequest) (*mgmtResponse, error) {
 	resp, err := emp.mgmtProvider.executeMgmtRequest(ctx, req)
 	if err != nil {
@@ -407,6 +148,9 @@ func (emp *eventingManagementProviderCore) tryParseErrorMessage(req *mgmtRequest
 		baseErr = ErrCollectionNotFound
 	} else if strings.Contains(strBody, "ERR_BUCKET_MISSING") {
 		baseErr = ErrBucketNotFound
+	} else if resp.StatusCode == 404 && strings.Contains(req.Path, "/validate") {
+		// Eventing services which predate function validation do not have the endpoint.
+		baseErr = ErrFeatureNotAvailable
 	} else {
 		baseErr = errors.New(string(b))
 	}
@@ -421,10 +165,48 @@ func (emp *eventingManagementProviderCore) scopedPath(path string, scope *Scope)
 	return fmt.Sprintf("%s?bucket=%s&scope=%s", path, url.PathEscape(scope.BucketName()), url.PathEscape(scope.Name()))
 }
 
-func (emp *eventingManagementProviderCore) doRequest(scope *Scope, path string, method string, opName string, function *EventingFunction,
+func (emp *eventingManagementProviderCore) scopedJSONFunction(scope *Scope, function EventingFunction) jsonEventingFunction {
+	jsonFunction := function.toJSONEventingFunction()
+
+	// Injecting the function scope for scope-level operations
+	if scope != nil {
+		jsonFunction.FunctionScope = &jsonEventingFunctionScope{
+			ScopeName:  scope.Name(),
+			BucketName: scope.BucketName(),
+		}
+	}
+
+	return jsonFunction
+}
+
+func (emp *eventingManagementProviderCore) scopedRawFunction(scope *Scope, function json.RawMessage) (json.RawMessage, error) {
+	if scope == nil {
+		return function, nil
+	}
+
+	// Injecting the function scope for scope-level operations, leaving every other field as it was given.
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(function, &fields)
+	if err != nil {
+		return nil, makeInvalidArgumentsError("function must be a JSON object: " + err.Error())
+	}
+
+	fields["function_scope"], err = json.Marshal(jsonEventingFunctionScope{
+		ScopeName:  scope.Name(),
+		BucketName: scope.BucketName(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(fields)
+}
+
+func (emp *eventingManagementProviderCore) doRequest(scope *Scope, path string, method string, opName string, body interface{},
 	target eventingResult, opts eventingRequestOptions) error {
 
-	if opName != "get_all_functions" && opName != "functions_status" {
+	if opName != "get_all_functions" && opName != "functions_status" && opName != "export_functions" &&
+		opName != "import_functions" {
 		path = emp.scopedPath(path, scope)
 	}
 
@@ -441,19 +223,9 @@ func (emp *eventingManagementProviderCore) doRequest(scope *Scope, path string,
 	defer span.End()
 
 	var b []byte
-	if function != nil {
-		jsonFunction := function.toJSONEventingFunction()
-
-		// Injecting the function scope for the scope-level UpsertFunction
-		if scope != nil {
-			jsonFunction.FunctionScope = &jsonEventingFunctionScope{
-				ScopeName:  scope.Name(),
-				BucketName: scope.BucketName(),
-			}
-		}
-
+	if body != nil {
 		var err error
-		b, err = json.Marshal(jsonFunction)
+		b, err = json.Marshal(body)
 		if err != nil {
 			return err
 		}
@@ -465,6 +237,7 @@ func (emp *eventingManagementProviderCore) doRequest(scope *Scope, path string,
 		Path:          path,
 		RetryStrategy: opts.RetryStrategy,
 		Timeout:       opts.Timeout,
+		Deadline:      opts.Deadline,
 		parentSpanCtx: span.Context(),
 		Body:          b,
 	}
@@ -499,13 +272,183 @@ func (emp *eventingManagementProviderCore) UpsertFunction(scope *Scope, function
 		opts = &UpsertEventingFunctionOptions{}
 	}
 
+	reqOpts := eventingRequestOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	}
+
+	if function.Version == "" || function.Settings.LanguageCompatibility == "" {
+		// The timeout applies to the operation as a whole, rather than to each of the requests it is made up of.
+		if opts.Timeout > 0 {
+			reqOpts.Deadline = time.Now().Add(opts.Timeout)
+		}
+
+		err := emp.populateCompatibility(&function, reqOpts)
+		if err != nil {
+			// The server will choose defaults for anything that we could not populate.
+			logDebugf("Failed to determine eventing compatibility for %s: %s", function.Name, err)
+		}
+	}
+
 	return emp.doRequest(scope, fmt.Sprintf("/api/v1/functions/%s", url.PathEscape(function.Name)), "POST",
-		"upsert_function", &function, nil, eventingRequestOptions{
-			Timeout:       opts.Timeout,
-			RetryStrategy: opts.RetryStrategy,
-			ParentSpan:    opts.ParentSpan,
-			Context:       opts.Context,
-		})
+		"upsert_function", emp.scopedJSONFunction(scope, function), nil, reqOpts)
+}
+
+// eventingLanguageCompatibilities are the language compatibility versions supported by the eventing service, ordered
+// from oldest to newest.
+var eventingLanguageCompatibilities = []EventingFunctionLanguageCompatibility{
+	EventingFunctionLanguageCompatibilityVersion600,
+	EventingFunctionLanguageCompatibilityVersion650,
+	EventingFunctionLanguageCompatibilityVersion662,
+	EventingFunctionLanguageCompatibilityVersion720,
+}
+
+type jsonEventingClusterNode struct {
+	Version  string   `json:"version"`
+	Services []string `json:"services"`
+}
+
+type jsonEventingCluster struct {
+	Nodes []jsonEventingClusterNode `json:"nodes"`
+}
+
+// parseServerVersion parses the major, minor and patch versions from a node version such as "7.2.0-5325-enterprise".
+func parseServerVersion(version string) ([3]int, error) {
+	var parsed [3]int
+
+	release, build, _ := strings.Cut(version, "-")
+	parts := strings.Split(release, ".")
+	if len(parts) != 3 {
+		return parsed, fmt.Errorf("unexpected version format %s", version)
+	}
+
+	for i, part := range parts {
+		num, err := strconv.Atoi(part)
+		if err != nil {
+			return parsed, fmt.Errorf("unexpected version format %s", version)
+		}
+		parsed[i] = num
+	}
+
+	// 0.0.0 is used by development builds, which support everything.
+	if parsed == [3]int{} && build != "" {
+		parsed = [3]int{math.MaxInt, 0, 0}
+	}
+
+	return parsed, nil
+}
+
+func compareServerVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// populateCompatibility sets the handler version and language compatibility of a function, where they are not
+// already set, to those supported by the oldest eventing node in the cluster. This prevents the function from
+// being rejected by older nodes in a mixed version cluster.
+func (emp *eventingManagementProviderCore) populateCompatibility(function *EventingFunction,
+	opts eventingRequestOptions) error {
+	span := emp.tracer.createSpan(opts.ParentSpan, "manager_eventing_get_compatibility", "management")
+	span.SetAttribute("db.operation", "GET /pools/default")
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "GET",
+		Path:          "/pools/default",
+		IsIdempotent:  true,
+		RetryStrategy: opts.RetryStrategy,
+		Timeout:       opts.Timeout,
+		Deadline:      opts.Deadline,
+		parentSpanCtx: span.Context(),
+	}
+	resp, err := emp.doMgmtRequest(opts.Context, req)
+	if err != nil {
+		return err
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return makeMgmtBadStatusError("failed to get cluster nodes", &req, resp)
+	}
+
+	var cluster jsonEventingCluster
+	jsonDec := json.NewDecoder(resp.Body)
+	err = jsonDec.Decode(&cluster)
+	if err != nil {
+		return err
+	}
+
+	var oldestNode string
+	var oldestVersion [3]int
+	for _, node := range cluster.Nodes {
+		isEventing := false
+		for _, service := range node.Services {
+			if service == "eventing" {
+				isEventing = true
+				break
+			}
+		}
+		if !isEventing {
+			continue
+		}
+
+		version, err := parseServerVersion(node.Version)
+		if err != nil {
+			return err
+		}
+
+		if oldestNode == "" || compareServerVersions(version, oldestVersion) < 0 {
+			oldestNode = node.Version
+			oldestVersion = version
+		}
+	}
+	if oldestNode == "" {
+		return errors.New("no eventing nodes found")
+	}
+
+	if function.Settings.LanguageCompatibility == "" {
+		for _, compat := range eventingLanguageCompatibilities {
+			compatVersion, err := parseServerVersion(string(compat))
+			if err != nil {
+				return err
+			}
+
+			if compareServerVersions(compatVersion, oldestVersion) <= 0 {
+				function.Settings.LanguageCompatibility = compat
+			}
+		}
+	}
+
+	if function.Version == "" {
+		release, build, _ := strings.Cut(oldestNode, "-")
+		build, edition, _ := strings.Cut(build, "-")
+		switch edition {
+		case "enterprise":
+			edition = "ee"
+		case "community":
+			edition = "ce"
+		}
+
+		function.Version = "evt-" + release
+		if build != "" {
+			function.Version += "-" + build
+		}
+		if edition != "" {
+			function.Version += "-" + edition
+		}
+	}
+
+	return nil
 }
 
 func (emp *eventingManagementProviderCore) DropFunction(scope *Scope, name string, opts *DropEventingFunctionOptions) error {
@@ -637,3 +580,98 @@ func (emp *eventingManagementProviderCore) FunctionsStatus(scope *Scope, opts *E
 
 	return &functions, nil
 }
+
+func (emp *eventingManagementProviderCore) ExportFunctions(scope *Scope, opts *ExportEventingFunctionsOptions) ([]json.RawMessage, error) {
+	if opts == nil {
+		opts = &ExportEventingFunctionsOptions{}
+	}
+
+	// The export endpoint is not scoped and returns every function in the cluster, so the functions are filtered by
+	// their function scope as they are decoded, leaving only those in the scope or, without a scope, the admin
+	// functions.
+	var functions eventingExportedFunctions
+	err := emp.doRequest(scope, "/api/v1/export", "GET",
+		"export_functions", nil, &functions, eventingRequestOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return functions.functions, nil
+}
+
+func (emp *eventingManagementProviderCore) ImportFunctions(scope *Scope, functions []json.RawMessage, opts *ImportEventingFunctionsOptions) error {
+	if opts == nil {
+		opts = &ImportEventingFunctionsOptions{}
+	}
+
+	jsonFunctions := make([]json.RawMessage, len(functions))
+	for i, function := range functions {
+		var err error
+		jsonFunctions[i], err = emp.scopedRawFunction(scope, function)
+		if err != nil {
+			return err
+		}
+	}
+
+	return emp.doRequest(scope, "/api/v1/import", "POST",
+		"import_functions", jsonFunctions, nil, eventingRequestOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+}
+
+func (emp *eventingManagementProviderCore) ValidateFunction(scope *Scope, function EventingFunction, opts *ValidateEventingFunctionOptions) error {
+	if opts == nil {
+		opts = &ValidateEventingFunctionOptions{}
+	}
+
+	reqOpts := eventingRequestOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	}
+
+	if function.Version == "" || function.Settings.LanguageCompatibility == "" {
+		// The timeout applies to the operation as a whole, rather than to each of the requests it is made up of.
+		if opts.Timeout > 0 {
+			reqOpts.Deadline = time.Now().Add(opts.Timeout)
+		}
+
+		err := emp.populateCompatibility(&function, reqOpts)
+		if err != nil {
+			logDebugf("Failed to determine eventing compatibility for %s: %s", function.Name, err)
+		}
+	}
+
+	return emp.doRequest(scope, fmt.Sprintf("/api/v1/functions/%s/validate", url.PathEscape(function.Name)), "POST",
+		"validate_function", emp.scopedJSONFunction(scope, function), nil, reqOpts)
+}
+
+func (emp *eventingManagementProviderCore) UpdateFunctionSettings(scope *Scope, name string, settings EventingFunctionSettings,
+	opts *UpdateEventingFunctionSettingsOptions) error {
+	if opts == nil {
+		opts = &UpdateEventingFunctionSettingsOptions{}
+	}
+
+	jsonSettings := jsonEventingFunctionSettingsUpdate{
+		jsonEventingFunctionSettings: settings.toJSONEventingFunctionSettings(),
+		QueryPrepareAll:              settings.QueryPrepareAll,
+		EnableAppLogRotation:         settings.EnableAppLogRotation,
+	}
+
+	return emp.doRequest(scope, fmt.Sprintf("/api/v1/functions/%s/settings", url.PathEscape(name)), "POST",
+		"update_function_settings", jsonSettings, nil, eventingRequestOptions{
+			Timeout:       opts.Timeout,
+			RetryStrategy: opts.RetryStrategy,
+			ParentSpan:    opts.ParentSpan,
+			Context:       opts.Context,
+		})
+}
@@ -0,0 +1,342 @@
+// Package gocbtest provides helpers for setting up and tearing down Couchbase resources from tests.
+// UNCOMMITTED: This API may change in the future.
+package gocbtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbase/gocbcore/v10"
+)
+
+const (
+	defaultScopeName          = "_default"
+	defaultPropagationTimeout = 30 * time.Second
+	propagationPollInterval   = 100 * time.Millisecond
+)
+
+// KeyspacesOptions are the options available when creating or tearing down Keyspaces.
+type KeyspacesOptions struct {
+	// Timeout is the maximum amount of time to wait for all changes to be applied and propagated to every node.
+	// Defaults to 30 seconds.
+	Timeout time.Duration
+
+	// Context can be used to cancel the operation.
+	Context context.Context
+}
+
+// Keyspaces is a set of scopes and collections created by CreateKeyspaces.
+type Keyspaces struct {
+	bucket *gocb.Bucket
+
+	// createdScopes are the scopes which did not exist before CreateKeyspaces was called.
+	createdScopes []string
+	// createdCollections are the collections, keyed by scope, which did not exist before CreateKeyspaces was called and
+	// which do not belong to a created scope.
+	createdCollections map[string][]string
+}
+
+// CreateKeyspaces creates all of the scopes and collections in spec on the bucket, and then waits until they are
+// visible on every node. Scopes and collections which already exist are left untouched, and are not removed by
+// Teardown. The ScopeName field of each CollectionSpec is ignored in favour of the name of the enclosing ScopeSpec.
+// If an error occurs then anything which was created is torn down before the error is returned.
+func CreateKeyspaces(bucket *gocb.Bucket, spec []gocb.ScopeSpec, opts *KeyspacesOptions) (*Keyspaces, error) {
+	if opts == nil {
+		opts = &KeyspacesOptions{}
+	}
+
+	for _, scope := range spec {
+		if scope.Name == "" {
+			return nil, fmt.Errorf("scope name cannot be empty: %w", gocb.ErrInvalidArgument)
+		}
+		for _, collection := range scope.Collections {
+			if collection.Name == "" {
+				return nil, fmt.Errorf("collection name cannot be empty: %w", gocb.ErrInvalidArgument)
+			}
+		}
+	}
+
+	ctx, cancel := propagationContext(opts)
+	defer cancel()
+
+	k := &Keyspaces{
+		bucket:             bucket,
+		createdCollections: make(map[string][]string),
+	}
+
+	err := k.create(ctx, spec)
+	if err != nil {
+		if tErr := k.Teardown(nil); tErr != nil {
+			return nil, fmt.Errorf("%w (teardown also failed: %s)", err, tErr.Error())
+		}
+		return nil, err
+	}
+
+	return k, nil
+}
+
+func (k *Keyspaces) create(ctx context.Context, spec []gocb.ScopeSpec) error {
+	mgr := k.bucket.CollectionsV2()
+
+	for _, scope := range spec {
+		if scope.Name == defaultScopeName {
+			continue
+		}
+
+		err := mgr.CreateScope(scope.Name, &gocb.CreateScopeOptions{Context: ctx})
+		if errors.Is(err, gocb.ErrScopeExists) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		k.createdScopes = append(k.createdScopes, scope.Name)
+	}
+
+	err := waitForManifest(ctx, k.bucket, func(manifest map[string]map[string]struct{}) bool {
+		for _, scope := range spec {
+			if _, ok := manifest[scope.Name]; !ok {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, scope := range spec {
+		createdScope := k.isCreatedScope(scope.Name)
+		for _, collection := range scope.Collections {
+			err := mgr.CreateCollection(scope.Name, collection.Name, &gocb.CreateCollectionSettings{
+				MaxExpiry: collection.MaxExpiry,
+				History:   collection.History,
+			}, &gocb.CreateCollectionOptions{Context: ctx})
+			if errors.Is(err, gocb.ErrCollectionExists) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			if !createdScope {
+				k.createdCollections[scope.Name] = append(k.createdCollections[scope.Name], collection.Name)
+			}
+		}
+	}
+
+	return waitForManifest(ctx, k.bucket, func(manifest map[string]map[string]struct{}) bool {
+		for _, scope := range spec {
+			collections, ok := manifest[scope.Name]
+			if !ok {
+				return false
+			}
+			for _, collection := range scope.Collections {
+				if _, ok := collections[collection.Name]; !ok {
+					return false
+				}
+			}
+		}
+		return true
+	})
+}
+
+// Teardown drops all of the scopes and collections which were created by CreateKeyspaces, and then waits until they
+// have been removed from every node. Scopes and collections which have already been dropped are ignored.
+func (k *Keyspaces) Teardown(opts *KeyspacesOptions) error {
+	if opts == nil {
+		opts = &KeyspacesOptions{}
+	}
+
+	ctx, cancel := propagationContext(opts)
+	defer cancel()
+
+	mgr := k.bucket.CollectionsV2()
+
+	for scopeName, collections := range k.createdCollections {
+		for _, collectionName := range collections {
+			err := mgr.DropCollection(scopeName, collectionName, &gocb.DropCollectionOptions{Context: ctx})
+			if err != nil && !errors.Is(err, gocb.ErrCollectionNotFound) && !errors.Is(err, gocb.ErrScopeNotFound) {
+				return err
+			}
+		}
+	}
+
+	for _, scopeName := range k.createdScopes {
+		err := mgr.DropScope(scopeName, &gocb.DropScopeOptions{Context: ctx})
+		if err != nil && !errors.Is(err, gocb.ErrScopeNotFound) {
+			return err
+		}
+	}
+
+	return waitForManifest(ctx, k.bucket, func(manifest map[string]map[string]struct{}) bool {
+		for _, scopeName := range k.createdScopes {
+			if _, ok := manifest[scopeName]; ok {
+				return false
+			}
+		}
+		for scopeName, collectionNames := range k.createdCollections {
+			collections, ok := manifest[scopeName]
+			if !ok {
+				continue
+			}
+			for _, collectionName := range collectionNames {
+				if _, ok := collections[collectionName]; ok {
+					return false
+				}
+			}
+		}
+		return true
+	})
+}
+
+func (k *Keyspaces) isCreatedScope(name string) bool {
+	for _, scopeName := range k.createdScopes {
+		if scopeName == name {
+			return true
+		}
+	}
+	return false
+}
+
+func propagationContext(opts *KeyspacesOptions) (context.Context, context.CancelFunc) {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = defaultPropagationTimeout
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// waitForManifest waits until predicate is satisfied by the collections manifest of every node in the cluster. If
+// the nodes cannot be queried individually, such as when using the couchbase2 protocol, then the manifest returned
+// by the collections manager is used instead.
+func waitForManifest(ctx context.Context, bucket *gocb.Bucket, predicate func(map[string]map[string]struct{}) bool) error {
+	return gocb.PollUntil(ctx, propagationPollInterval, func(ctx context.Context) (bool, error) {
+		manifests, err := fetchNodeManifests(ctx, bucket)
+		if err != nil {
+			// Manifests can be temporarily unavailable whilst nodes catch up, so we just try again.
+			return false, nil
+		}
+
+		for _, manifest := range manifests {
+			if !predicate(manifest) {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}, nil)
+}
+
+func fetchNodeManifests(ctx context.Context, bucket *gocb.Bucket) ([]map[string]map[string]struct{}, error) {
+	agent, err := bucket.Internal().IORouter()
+	if err != nil || agent == nil {
+		scopes, err := bucket.CollectionsV2().GetAllScopes(&gocb.GetAllScopesOptions{Context: ctx})
+		if err != nil {
+			return nil, err
+		}
+
+		manifest := make(map[string]map[string]struct{})
+		for _, scope := range scopes {
+			collections := make(map[string]struct{})
+			for _, collection := range scope.Collections {
+				collections[collection.Name] = struct{}{}
+			}
+			manifest[scope.Name] = collections
+		}
+
+		return []map[string]map[string]struct{}{manifest}, nil
+	}
+
+	endpoints := agent.MgmtEps()
+	if len(endpoints) == 0 {
+		return nil, errors.New("no management endpoints available")
+	}
+
+	var manifests []map[string]map[string]struct{}
+	for _, endpoint := range endpoints {
+		manifest, err := fetchNodeManifest(ctx, agent, bucket.Name(), endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		manifests = append(manifests, manifest)
+	}
+
+	return manifests, nil
+}
+
+func fetchNodeManifest(ctx context.Context, agent *gocbcore.Agent, bucketName, endpoint string) (map[string]map[string]struct{}, error) {
+	type result struct {
+		resp *gocbcore.HTTPResponse
+		err  error
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultPropagationTimeout)
+	}
+
+	resCh := make(chan result, 1)
+	op, err := agent.DoHTTPRequest(&gocbcore.HTTPRequest{
+		Service:      gocbcore.MgmtService,
+		Method:       "GET",
+		Path:         fmt.Sprintf("/pools/default/buckets/%s/scopes", url.PathEscape(bucketName)),
+		Endpoint:     endpoint,
+		IsIdempotent: true,
+		Deadline:     deadline,
+	}, func(resp *gocbcore.HTTPResponse, err error) {
+		resCh <- result{resp: resp, err: err}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var res result
+	select {
+	case res = <-resCh:
+	case <-ctx.Done():
+		op.Cancel()
+		return nil, ctx.Err()
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+	defer func() {
+		_ = res.resp.Body.Close()
+	}()
+
+	if res.resp.StatusCode != 200 {
+		body, _ := io.ReadAll(res.resp.Body)
+		return nil, fmt.Errorf("unexpected status code %d from %s: %s", res.resp.StatusCode, endpoint, body)
+	}
+
+	var mfest gocbcore.Manifest
+	if err := json.NewDecoder(res.resp.Body).Decode(&mfest); err != nil {
+		return nil, err
+	}
+
+	manifest := make(map[string]map[string]struct{})
+	for _, scope := range mfest.Scopes {
+		collections := make(map[string]struct{})
+		for _, collection := range scope.Collections {
+			collections[collection.Name] = struct{}{}
+		}
+		manifest[scope.Name] = collections
+	}
+
+	return manifest, nil
+}
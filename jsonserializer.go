@@ -0,0 +1,12 @@
+package gocb
+
+// JSONSerializer provides an interface for transforming Go values to and from the JSON bytes used by the query
+// service for parameters and rows. JSONSubdocSerializer implements the default behaviour, applying encoding/json to
+// all values.
+// UNCOMMITTED: This API may change in the future.
+type JSONSerializer interface {
+	SubdocSerializer
+
+	// Deserialize decodes JSON bytes into a Go type.
+	Deserialize(bytes []byte, out interface{}) error
+}
@@ -0,0 +1,114 @@
+package gocb
+
+import (
+	"strings"
+)
+
+// Keyspace identifies a collection by its bucket, scope and collection names. Cluster.Collection can be used to
+// access the collection it identifies, such as to manage its query indexes.
+// UNCOMMITTED: This API may change in the future.
+type Keyspace struct {
+	Bucket     string
+	Scope      string
+	Collection string
+}
+
+// ParseKeyspace parses a keyspace in the form bucket.scope.collection, or bucket to refer to the default collection
+// of a bucket. Names containing dots must be escaped using backticks, e.g. `travel.sample`.inventory.airline, and
+// backticks within an escaped name are doubled.
+// UNCOMMITTED: This API may change in the future.
+func ParseKeyspace(keyspace string) (Keyspace, error) {
+	keyspace = strings.TrimPrefix(keyspace, "default:")
+
+	var parts []string
+	var part strings.Builder
+	var escaped, wasEscaped bool
+	runes := []rune(keyspace)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '`' && escaped && i+1 < len(runes) && runes[i+1] == '`':
+			part.WriteRune(r)
+			i++
+		case r == '`':
+			if !escaped && part.Len() > 0 {
+				return Keyspace{}, makeInvalidArgumentsError("keyspace contains an unexpected backtick")
+			}
+			escaped = !escaped
+			wasEscaped = true
+		case r == '.' && !escaped:
+			parts = append(parts, part.String())
+			part.Reset()
+			wasEscaped = false
+		case wasEscaped && !escaped:
+			return Keyspace{}, makeInvalidArgumentsError("keyspace contains characters after a closing backtick")
+		default:
+			part.WriteRune(r)
+		}
+	}
+	if escaped {
+		return Keyspace{}, makeInvalidArgumentsError("keyspace contains an unterminated backtick")
+	}
+	parts = append(parts, part.String())
+
+	for _, p := range parts {
+		if p == "" {
+			return Keyspace{}, makeInvalidArgumentsError("keyspace cannot contain empty names")
+		}
+	}
+
+	switch len(parts) {
+	case 1:
+		return Keyspace{
+			Bucket:     parts[0],
+			Scope:      "_default",
+			Collection: "_default",
+		}, nil
+	case 3:
+		return Keyspace{
+			Bucket:     parts[0],
+			Scope:      parts[1],
+			Collection: parts[2],
+		}, nil
+	default:
+		return Keyspace{}, makeInvalidArgumentsError("keyspace must be in the form bucket or bucket.scope.collection")
+	}
+}
+
+// String returns the keyspace in the form accepted by ParseKeyspace. An empty scope or collection name refers to the
+// default scope or collection.
+func (k Keyspace) String() string {
+	escape := func(name string) string {
+		if strings.ContainsAny(name, ".`") {
+			return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+		}
+		return name
+	}
+
+	return escape(k.Bucket) + "." + escape(k.scopeName()) + "." + escape(k.collectionName())
+}
+
+func (k Keyspace) scopeName() string {
+	if k.Scope == "" {
+		return "_default"
+	}
+	return k.Scope
+}
+
+func (k Keyspace) collectionName() string {
+	if k.Collection == "" {
+		return "_default"
+	}
+	return k.Collection
+}
+
+func (k Keyspace) validate() error {
+	if k.Bucket == "" {
+		return makeInvalidArgumentsError("keyspace bucket name cannot be empty")
+	}
+	if (k.Scope == "") != (k.Collection == "") {
+		return makeInvalidArgumentsError("keyspace scope and collection names must be set together")
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package gocb
+
+func (suite *UnitTestSuite) TestParseKeyspace() {
+	type tCase struct {
+		keyspace string
+		expected Keyspace
+	}
+
+	cases := []tCase{
+		{"travel-sample", Keyspace{Bucket: "travel-sample", Scope: "_default", Collection: "_default"}},
+		{"travel-sample.inventory.airline", Keyspace{Bucket: "travel-sample", Scope: "inventory", Collection: "airline"}},
+		{"`travel.sample`.inventory.airline", Keyspace{Bucket: "travel.sample", Scope: "inventory", Collection: "airline"}},
+		{"default:`travel-sample`.`inventory`.`airline`", Keyspace{Bucket: "travel-sample", Scope: "inventory", Collection: "airline"}},
+		{"`travel``sample`.inventory.airline", Keyspace{Bucket: "travel`sample", Scope: "inventory", Collection: "airline"}},
+	}
+	for _, tc := range cases {
+		suite.Run(tc.keyspace, func() {
+			keyspace, err := ParseKeyspace(tc.keyspace)
+			suite.Require().Nil(err, err)
+			suite.Assert().Equal(tc.expected, keyspace)
+
+			reparsed, err := ParseKeyspace(keyspace.String())
+			suite.Require().Nil(err, err)
+			suite.Assert().Equal(keyspace, reparsed)
+		})
+	}
+
+	invalid := []string{"", "travel-sample.inventory", "a.b.c.d", "a..c", "`a.b", "a`b`.c.d", "`a`b.c.d"}
+	for _, keyspace := range invalid {
+		suite.Run(keyspace, func() {
+			_, err := ParseKeyspace(keyspace)
+			suite.Assert().ErrorIs(err, ErrInvalidArgument)
+		})
+	}
+
+	suite.Assert().Equal("`travel.sample`._default._default", Keyspace{Bucket: "travel.sample"}.String())
+	suite.Assert().Equal("`travel``sample`._default._default", Keyspace{Bucket: "travel`sample"}.String())
+}
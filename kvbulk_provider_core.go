@@ -1,26 +1,40 @@
 package gocb
 
 import (
+	"errors"
+	"sync"
 	"time"
 
 	gocbcore "github.com/couchbase/gocbcore/v10"
+	"github.com/couchbase/gocbcore/v10/memd"
 )
 
 type kvBulkProviderCore struct {
-	agent kvProviderCoreProvider
+	agent            kvProviderCoreProvider
+	snapshotProvider kvProviderConfigSnapshotProvider
 
-	tracer *tracerWrapper
-	meter  *meterWrapper
+	tracer     *tracerWrapper
+	meter      *meterWrapper
+	timeSource func() time.Time
 }
 
 func (p *kvBulkProviderCore) Do(c *Collection, ops []BulkOp, opts *BulkOpOptions) error {
 	span := p.StartKvOpTrace(c, "bulk", opts.ParentSpan, false)
 	defer span.End()
 
+	observeDurability := opts.PersistTo > 0 || opts.ReplicateTo > 0
+	if observeDurability && !c.useMutationTokens {
+		return makeInvalidArgumentsError("cannot use observe based durability without mutation tokens")
+	}
+
 	timeout := opts.Timeout
 	if opts.Timeout == 0 {
 		timeout = c.timeoutsConfig.KVTimeout * time.Duration(len(ops))
+		if opts.PersistTo > 0 {
+			timeout = c.timeoutsConfig.KVDurableTimeout * time.Duration(len(ops))
+		}
 	}
+	deadline := time.Now().Add(timeout)
 
 	retryWrapper := c.retryStrategyWrapper
 	if opts.RetryStrategy != nil {
@@ -63,11 +77,53 @@ func (p *kvBulkProviderCore) Do(c *Collection, ops []BulkOp, opts *BulkOpOptions
 		}
 	}
 
-	// Wait for all of the ops to complete.
+	if !observeDurability {
+		// Wait for all of the ops to complete.
+		for range ops {
+			item := <-signal
+			item.finish()
+		}
+
+		return nil
+	}
+
+	kv := &kvProviderCore{
+		agent:            p.agent,
+		snapshotProvider: p.snapshotProvider,
+		tracer:           p.tracer,
+	}
+
+	// Wait for all of the ops to complete, any successful mutations then need to be observed before they are
+	// considered to be complete.
+	var wg sync.WaitGroup
 	for range ops {
 		item := <-signal
-		item.finish()
+
+		id, mutRes, errOut, isMutation := bulkOpMutation(item)
+		if !isMutation || *errOut != nil {
+			item.finish()
+			continue
+		}
+
+		wg.Add(1)
+		go func(item BulkOp) {
+			defer wg.Done()
+
+			if mutRes.mt == nil {
+				*errOut = errors.New("expected a mutation token")
+				item.finish()
+				return
+			}
+
+			err := kv.waitForDurability(opts.Context, c, span, id, mutRes.mt.token, opts.ReplicateTo, opts.PersistTo,
+				deadline, nil, "")
+			if err != nil {
+				*errOut = err
+			}
+			item.finish()
+		}(item)
 	}
+	wg.Wait()
 
 	return nil
 }
@@ -121,7 +177,7 @@ func (p *kvBulkProviderCore) GetAndTouch(item *GetAndTouchOp, parentSpan Request
 
 	_, err := p.agent.GetAndTouch(gocbcore.GetAndTouchOptions{
 		Key:            []byte(item.ID),
-		Expiry:         durationToExpiry(item.Expiry),
+		Expiry:         durationToExpiry(item.Expiry, p.timeSource),
 		CollectionName: c.name(),
 		ScopeName:      c.ScopeName(),
 		RetryStrategy:  retryWrapper,
@@ -157,7 +213,7 @@ func (p *kvBulkProviderCore) Touch(item *TouchOp, parentSpan RequestSpan, c *Col
 
 	_, err := p.agent.Touch(gocbcore.TouchOptions{
 		Key:            []byte(item.ID),
-		Expiry:         durationToExpiry(item.Expiry),
+		Expiry:         durationToExpiry(item.Expiry, p.timeSource),
 		CollectionName: c.name(),
 		ScopeName:      c.ScopeName(),
 		RetryStrategy:  retryWrapper,
@@ -245,12 +301,23 @@ func (p *kvBulkProviderCore) Set(item *UpsertOp, parentSpan RequestSpan, c *Coll
 		signal <- item
 		return
 	}
+	bytes, isCompressed, err := validateValueSize(c.compressor, item.ID, bytes)
+	if err != nil {
+		item.Err = err
+		signal <- item
+		return
+	}
+	var datatype uint8
+	if isCompressed {
+		datatype = uint8(memd.DatatypeFlagCompressed)
+	}
 
 	_, err = p.agent.Set(gocbcore.SetOptions{
 		Key:            []byte(item.ID),
 		Value:          bytes,
+		Datatype:       datatype,
 		Flags:          flags,
-		Expiry:         durationToExpiry(item.Expiry),
+		Expiry:         durationToExpiry(item.Expiry, p.timeSource),
 		CollectionName: c.name(),
 		ScopeName:      c.ScopeName(),
 		RetryStrategy:  retryWrapper,
@@ -300,12 +367,23 @@ func (p *kvBulkProviderCore) Add(item *InsertOp, parentSpan RequestSpan, c *Coll
 		return
 	}
 	etrace.End()
+	bytes, isCompressed, err := validateValueSize(c.compressor, item.ID, bytes)
+	if err != nil {
+		item.Err = err
+		signal <- item
+		return
+	}
+	var datatype uint8
+	if isCompressed {
+		datatype = uint8(memd.DatatypeFlagCompressed)
+	}
 
 	_, err = p.agent.Add(gocbcore.AddOptions{
 		Key:            []byte(item.ID),
 		Value:          bytes,
+		Datatype:       datatype,
 		Flags:          flags,
-		Expiry:         durationToExpiry(item.Expiry),
+		Expiry:         durationToExpiry(item.Expiry, p.timeSource),
 		CollectionName: c.name(),
 		ScopeName:      c.ScopeName(),
 		RetryStrategy:  retryWrapper,
@@ -353,13 +431,24 @@ func (p *kvBulkProviderCore) Replace(item *ReplaceOp, parentSpan RequestSpan, c
 		return
 	}
 	etrace.End()
+	bytes, isCompressed, err := validateValueSize(c.compressor, item.ID, bytes)
+	if err != nil {
+		item.Err = err
+		signal <- item
+		return
+	}
+	var datatype uint8
+	if isCompressed {
+		datatype = uint8(memd.DatatypeFlagCompressed)
+	}
 
 	_, err = p.agent.Replace(gocbcore.ReplaceOptions{
 		Key:            []byte(item.ID),
 		Value:          bytes,
+		Datatype:       datatype,
 		Flags:          flags,
 		Cas:            gocbcore.Cas(item.Cas),
-		Expiry:         durationToExpiry(item.Expiry),
+		Expiry:         durationToExpiry(item.Expiry, p.timeSource),
 		CollectionName: c.name(),
 		ScopeName:      c.ScopeName(),
 		RetryStrategy:  retryWrapper,
@@ -489,7 +578,7 @@ func (p *kvBulkProviderCore) Increment(item *IncrementOp, parentSpan RequestSpan
 		Key:            []byte(item.ID),
 		Delta:          uint64(item.Delta),
 		Initial:        realInitial,
-		Expiry:         durationToExpiry(item.Expiry),
+		Expiry:         durationToExpiry(item.Expiry, p.timeSource),
 		CollectionName: c.name(),
 		ScopeName:      c.ScopeName(),
 		RetryStrategy:  retryWrapper,
@@ -540,7 +629,7 @@ func (p *kvBulkProviderCore) Decrement(item *DecrementOp, parentSpan RequestSpan
 		Key:            []byte(item.ID),
 		Delta:          uint64(item.Delta),
 		Initial:        realInitial,
-		Expiry:         durationToExpiry(item.Expiry),
+		Expiry:         durationToExpiry(item.Expiry, p.timeSource),
 		CollectionName: c.name(),
 		ScopeName:      c.ScopeName(),
 		RetryStrategy:  retryWrapper,
@@ -1,3 +1,5 @@
+//go:build !gocb_nocouchbase2
+
 package gocb
 
 import (
@@ -15,6 +17,10 @@ type kvBulkProviderPs struct {
 }
 
 func (p *kvBulkProviderPs) Do(c *Collection, ops []BulkOp, opts *BulkOpOptions) error {
+	if opts.PersistTo > 0 || opts.ReplicateTo > 0 {
+		return wrapError(ErrFeatureNotAvailable, "observe based durability (PersistTo and ReplicateTo) is not supported by the couchbase2 protocol")
+	}
+
 	span := p.StartKvOpTrace(c, "bulk", opts.ParentSpan, false)
 	defer span.End()
 
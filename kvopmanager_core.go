@@ -3,6 +3,7 @@ package gocb
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	gocbcore "github.com/couchbase/gocbcore/v10"
@@ -25,6 +26,8 @@ type kvOpManagerCore struct {
 	timeout         time.Duration
 	deadline        time.Time
 	bytes           []byte
+	sendBytes       []byte
+	datatype        uint8
 	flags           uint32
 	persistTo       uint
 	replicateTo     uint
@@ -39,11 +42,80 @@ type kvOpManagerCore struct {
 	ctx context.Context
 }
 
+func (m *kvOpManagerCore) warnDurableTimeoutCoerced(timeout time.Duration) {
+	emitWarning(m.parent.warningHandler, Warning{
+		Type:    WarningTypeFallback,
+		Feature: "Timeout",
+		Message: "durable operation in use so timeout value coerced up to " + timeout.String(),
+		Details: map[string]interface{}{
+			"operation": m.operationName,
+			"timeout":   timeout,
+		},
+	})
+}
+
+// bucketReplicaCheck records the config revision for which a bucket was last checked for replicas, so that durable
+// writes are only checked, and warned about, once per revision rather than on every write.
+type bucketReplicaCheck struct {
+	lock    sync.Mutex
+	checked bool
+	revID   int64
+}
+
+// shouldCheck returns whether the revision has not been checked yet, marking it as checked.
+func (c *bucketReplicaCheck) shouldCheck(revID int64) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.checked && c.revID == revID {
+		return false
+	}
+	c.checked = true
+	c.revID = revID
+
+	return true
+}
+
+// warnIfDurabilityUnreplicated warns when a durable write was made to a bucket without replicas. The server accepts
+// such writes once they are on the active node alone, so the write has not been replicated as the durability level
+// requires. The bucket is checked once per config revision.
+func (m *kvOpManagerCore) warnIfDurabilityUnreplicated() {
+	if m.kv.snapshotProvider == nil {
+		return
+	}
+
+	snapshot, err := m.kv.snapshotProvider.WaitForConfigSnapshot(m.ctx, m.Deadline())
+	if err != nil {
+		logDebugf("Failed to get config snapshot to check durability: %v", err)
+		return
+	}
+
+	if bucket := m.parent.bucket; bucket != nil && !bucket.replicaCheck.shouldCheck(snapshot.RevID()) {
+		return
+	}
+
+	numReplicas, err := snapshot.NumReplicas()
+	if err != nil || numReplicas > 0 {
+		return
+	}
+
+	emitWarning(m.parent.warningHandler, Warning{
+		Type:    WarningTypeCapabilityDowngrade,
+		Feature: "DurabilityLevel",
+		Message: "the bucket has no replicas so the durable write was only applied to the active node",
+		Details: map[string]interface{}{
+			"operation":   m.operationName,
+			"bucket":      m.parent.bucketName(),
+			"document_id": m.documentID,
+		},
+	})
+}
+
 func (m *kvOpManagerCore) getTimeout() time.Duration {
 	if m.timeout > 0 {
 		if m.durabilityLevel > 0 && m.timeout < durabilityTimeoutFloor {
 			m.timeout = durabilityTimeoutFloor
-			logWarnf("Durable operation in use so timeout value coerced up to %s", m.timeout.String())
+			m.warnDurableTimeoutCoerced(m.timeout)
 		}
 		return m.timeout
 	}
@@ -55,7 +127,7 @@ func (m *kvOpManagerCore) getTimeout() time.Duration {
 
 	if m.durabilityLevel > 0 && defaultTimeout < durabilityTimeoutFloor {
 		defaultTimeout = durabilityTimeoutFloor
-		logWarnf("Durable operation in user so timeout value coerced up to %s", defaultTimeout.String())
+		m.warnDurableTimeoutCoerced(defaultTimeout)
 	}
 
 	return defaultTimeout
@@ -98,7 +170,17 @@ func (m *kvOpManagerCore) SetValue(val interface{}) {
 		return
 	}
 
+	sendBytes, isCompressed, err := validateValueSize(m.parent.compressor, m.documentID, bytes)
+	if err != nil {
+		m.err = err
+		return
+	}
+
 	m.bytes = bytes
+	m.sendBytes = sendBytes
+	if isCompressed {
+		m.datatype = uint8(memd.DatatypeFlagCompressed)
+	}
 	m.flags = flags
 }
 
@@ -110,7 +192,7 @@ func (m *kvOpManagerCore) SetDuraOptions(persistTo, replicateTo uint, level Dura
 		}
 
 		if level > 0 {
-			m.err = makeInvalidArgumentsError("cannot mix observe based durability and synchronous durability")
+			m.err = makeInvalidArgumentsError("cannot mix observe based durability (PersistTo and ReplicateTo) and synchronous durability (DurabilityLevel)")
 			return
 		}
 	}
@@ -194,6 +276,16 @@ func (m *kvOpManagerCore) ValueBytes() []byte {
 	return m.bytes
 }
 
+// SendValueBytes returns the value as it should be sent, which may already be compressed, see ValueDatatype.
+func (m *kvOpManagerCore) SendValueBytes() []byte {
+	return m.sendBytes
+}
+
+// ValueDatatype returns the datatype of SendValueBytes.
+func (m *kvOpManagerCore) ValueDatatype() uint8 {
+	return m.datatype
+}
+
 func (m *kvOpManagerCore) ValueFlags() uint32 {
 	return m.flags
 }
@@ -260,7 +352,7 @@ func (m *kvOpManagerCore) NeedsObserve() bool {
 }
 
 func (m *kvOpManagerCore) EnhanceErr(err error) error {
-	return maybeEnhanceCollKVErr(err, m.parent, m.documentID)
+	return maybeEnhanceValueTooLargeErr(maybeEnhanceCollKVErr(err, m.parent, m.documentID), m.documentID, m.bytes)
 }
 
 func (m *kvOpManagerCore) EnhanceMt(token gocbcore.MutationToken) *MutationToken {
@@ -303,6 +395,10 @@ func (m *kvOpManagerCore) Wait(op gocbcore.PendingOp, err error) error {
 		<-m.signal
 	}
 
+	if m.wasResolved && m.durabilityLevel > 0 {
+		m.warnIfDurabilityUnreplicated()
+	}
+
 	if m.wasResolved && (m.persistTo > 0 || m.replicateTo > 0) {
 		if m.mutationToken == nil {
 			return errors.New("expected a mutation token")
@@ -337,7 +433,9 @@ func newKvOpManagerCore(c *Collection, opName string, parentSpan RequestSpan, kv
 	}
 }
 
-func durationToExpiry(dura time.Duration) uint32 {
+// durationToExpiry converts an expiry duration into the form sent to the server, using now to compute absolute
+// expiry times. If now is nil then time.Now is used.
+func durationToExpiry(dura time.Duration, now func() time.Time) uint32 {
 	// If the duration is 0, that indicates never-expires
 	if dura == 0 {
 		return 0
@@ -354,6 +452,10 @@ func durationToExpiry(dura time.Duration) uint32 {
 		return uint32(dura / time.Second)
 	}
 
+	if now == nil {
+		now = time.Now
+	}
+
 	// Send the duration as a unix timestamp of now plus duration.
-	return uint32(time.Now().Add(dura).Unix())
+	return uint32(now().Add(dura).Unix())
 }
@@ -1,3 +1,5 @@
+//go:build !gocb_nocouchbase2
+
 package gocb
 
 import (
@@ -24,6 +26,7 @@ type kvOpManagerPs struct {
 	transcoder      Transcoder
 	timeout         time.Duration
 	bytes           []byte
+	compressedBytes []byte
 	flags           uint32
 	durabilityLevel *kv_v1.DurabilityLevel
 	retryStrategy   RetryStrategy
@@ -38,11 +41,23 @@ type kvOpManagerPs struct {
 	req *retriableRequestPs
 }
 
+func (m *kvOpManagerPs) warnDurableTimeoutCoerced(timeout time.Duration) {
+	emitWarning(m.parent.warningHandler, Warning{
+		Type:    WarningTypeFallback,
+		Feature: "Timeout",
+		Message: "durable operation in use so timeout value coerced up to " + timeout.String(),
+		Details: map[string]interface{}{
+			"operation": m.operationName,
+			"timeout":   timeout,
+		},
+	})
+}
+
 func (m *kvOpManagerPs) getTimeout() time.Duration {
 	if m.timeout > 0 {
 		if m.durabilityLevel != nil && m.timeout < durabilityTimeoutFloor {
 			m.timeout = durabilityTimeoutFloor
-			logWarnf("Durable operation in use so timeout value coerced up to %s", m.timeout.String())
+			m.warnDurableTimeoutCoerced(m.timeout)
 		}
 		return m.timeout
 	}
@@ -54,7 +69,7 @@ func (m *kvOpManagerPs) getTimeout() time.Duration {
 
 	if m.durabilityLevel != nil && *m.durabilityLevel > 0 && defaultTimeout < durabilityTimeoutFloor {
 		defaultTimeout = durabilityTimeoutFloor
-		logWarnf("Durable operation in user so timeout value coerced up to %s", defaultTimeout.String())
+		m.warnDurableTimeoutCoerced(defaultTimeout)
 	}
 
 	return defaultTimeout
@@ -93,11 +108,25 @@ func (m *kvOpManagerPs) SetValue(val interface{}) {
 		return
 	}
 
+	sendBytes, isCompressed, err := validateValueSize(m.compressor, m.documentID, bytes)
+	if err != nil {
+		m.err = err
+		return
+	}
+
 	m.bytes = bytes
+	if isCompressed {
+		m.compressedBytes = sendBytes
+	}
 	m.flags = flags
 }
 
-func (m *kvOpManagerPs) SetDuraOptions(level DurabilityLevel) {
+func (m *kvOpManagerPs) SetDuraOptions(persistTo, replicateTo uint, level DurabilityLevel) {
+	if persistTo != 0 || replicateTo != 0 {
+		m.err = wrapError(ErrFeatureNotAvailable, "observe based durability (PersistTo and ReplicateTo) is not supported by the couchbase2 protocol, use DurabilityLevel instead")
+		return
+	}
+
 	if level == DurabilityLevelUnknown {
 		level = DurabilityLevelNone
 	}
@@ -165,6 +194,11 @@ func (m *kvOpManagerPs) BucketName() string {
 }
 
 func (m *kvOpManagerPs) Value() ([]byte, bool) {
+	if m.compressedBytes != nil {
+		// The value was already compressed when its size was validated.
+		return m.compressedBytes, true
+	}
+
 	return m.compressor.Compress(m.bytes)
 }
 
@@ -257,11 +291,11 @@ func (m *kvOpManagerPs) RetryReasonFor(err error) RetryReason {
 }
 
 func (m *kvOpManagerPs) EnhanceErrorStatus(st *status.Status, readOnly bool) error {
-	return mapPsErrorStatusToGocbError(st, readOnly)
+	return maybeEnhanceValueTooLargeErr(mapPsErrorStatusToGocbError(st, readOnly), m.documentID, m.bytes)
 }
 
 func (m *kvOpManagerPs) EnhanceErr(err error, readOnly bool) error {
-	return mapPsErrorToGocbError(err, readOnly)
+	return maybeEnhanceValueTooLargeErr(mapPsErrorToGocbError(err, readOnly), m.documentID, m.bytes)
 }
 
 func newKvOpManagerPs(c *Collection, opName string, parentSpan RequestSpan, p *kvProviderPs) *kvOpManagerPs {
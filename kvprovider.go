@@ -32,6 +32,8 @@ type kvProvider interface {
 
 	Scan(*Collection, ScanType, *ScanOptions) (*ScanResult, error)
 
+	GroupKeysByNode(*Collection, []string, *GroupKeysByNodeOptions) (*GroupKeysResult, error)
+
 	StartKvOpTrace(*Collection, string, RequestSpan, bool) RequestSpan
 }
 
@@ -3,6 +3,7 @@ package gocb
 import (
 	"context"
 	"errors"
+	"sort"
 	"time"
 
 	"github.com/couchbase/gocbcore/v10"
@@ -14,6 +15,7 @@ type kvProviderCore struct {
 
 	tracer               *tracerWrapper
 	preferredServerGroup string
+	timeSource           func() time.Time
 }
 
 var _ kvProvider = &kvProviderCore{}
@@ -83,6 +85,74 @@ func (p *kvProviderCore) Scan(c *Collection, scanType ScanType, opts *ScanOption
 	return opm.Scan(opts.Context)
 }
 
+func (p *kvProviderCore) GroupKeysByNode(c *Collection, keys []string, opts *GroupKeysByNodeOptions) (*GroupKeysResult, error) {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = c.timeoutsConfig.KVTimeout
+	}
+
+	config, err := p.snapshotProvider.WaitForConfigSnapshot(opts.Context, time.Now().Add(timeout))
+	if err != nil {
+		return nil, maybeEnhanceKVErr(err, c.bucketName(), c.ScopeName(), c.Name(), "group_keys_by_node")
+	}
+
+	numVbuckets, err := config.NumVbuckets()
+	if err != nil {
+		return nil, err
+	}
+
+	if numVbuckets == 0 {
+		return nil, makeInvalidArgumentsError("can only use GroupKeysByNode with couchbase buckets")
+	}
+
+	numServers, err := config.NumServers()
+	if err != nil {
+		return nil, err
+	}
+
+	vbucketToServer := make(map[uint16]int, numVbuckets)
+	for serverIndex := 0; serverIndex < numServers; serverIndex++ {
+		vbuckets, err := config.VbucketsOnServer(serverIndex)
+		if err != nil {
+			return nil, err
+		}
+		for _, vbID := range vbuckets {
+			vbucketToServer[vbID] = serverIndex
+		}
+	}
+
+	groupIdx := make(map[int]int)
+	var groups []NodeKeyGroup
+	for _, key := range keys {
+		vbID, err := config.KeyToVbucket([]byte(key))
+		if err != nil {
+			return nil, err
+		}
+
+		serverIndex, ok := vbucketToServer[vbID]
+		if !ok {
+			serverIndex = -1
+		}
+
+		idx, ok := groupIdx[serverIndex]
+		if !ok {
+			idx = len(groups)
+			groupIdx[serverIndex] = idx
+			groups = append(groups, NodeKeyGroup{NodeIndex: serverIndex})
+		}
+		groups[idx].Keys = append(groups[idx].Keys, key)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].NodeIndex < groups[j].NodeIndex
+	})
+
+	return &GroupKeysResult{
+		revID:  config.RevID(),
+		groups: groups,
+	}, nil
+}
+
 func (p *kvProviderCore) getCollectionID(ctx context.Context, c *Collection, parentSpan RequestSpan, timeout time.Duration,
 	impersonate string) (uint32, error) {
 	if c.isDefault() {
@@ -114,6 +184,7 @@ func (p *kvProviderCore) getCollectionID(ctx context.Context, c *Collection, par
 		}
 
 		cidOut = res.CollectionID
+		c.uid.set(cidOut)
 
 		opm.Resolve(nil)
 	}))
@@ -125,6 +196,22 @@ func (p *kvProviderCore) getCollectionID(ctx context.Context, c *Collection, par
 }
 
 func (p *kvProviderCore) Insert(c *Collection, id string, val interface{}, opts *InsertOptions) (*MutationResult, error) {
+	if opts.Idempotent {
+		return p.idempotentStore(c, "insert", id, val, idempotentStoreOptions{
+			action:          StoreSemanticsInsert,
+			expiry:          opts.Expiry,
+			persistTo:       opts.PersistTo,
+			replicateTo:     opts.ReplicateTo,
+			durabilityLevel: opts.DurabilityLevel,
+			transcoder:      opts.Transcoder,
+			timeout:         opts.Timeout,
+			retryStrategy:   opts.RetryStrategy,
+			parentSpan:      opts.ParentSpan,
+			ctx:             opts.Context,
+			user:            opts.Internal.User,
+		})
+	}
+
 	opm := newKvOpManagerCore(c, "insert", opts.ParentSpan, p)
 	defer opm.Finish()
 
@@ -145,9 +232,10 @@ func (p *kvProviderCore) Insert(c *Collection, id string, val interface{}, opts
 	var mutOut *MutationResult
 	err := opm.Wait(p.agent.Add(gocbcore.AddOptions{
 		Key:                    opm.DocumentID(),
-		Value:                  opm.ValueBytes(),
+		Value:                  opm.SendValueBytes(),
+		Datatype:               opm.ValueDatatype(),
 		Flags:                  opm.ValueFlags(),
-		Expiry:                 durationToExpiry(opts.Expiry),
+		Expiry:                 durationToExpiry(opts.Expiry, p.timeSource),
 		CollectionName:         opm.CollectionName(),
 		ScopeName:              opm.ScopeName(),
 		DurabilityLevel:        opm.DurabilityLevel(),
@@ -177,6 +265,23 @@ func (p *kvProviderCore) Insert(c *Collection, id string, val interface{}, opts
 }
 
 func (p *kvProviderCore) Upsert(c *Collection, id string, val interface{}, opts *UpsertOptions) (*MutationResult, error) {
+	if opts.Idempotent {
+		return p.idempotentStore(c, "upsert", id, val, idempotentStoreOptions{
+			action:          StoreSemanticsUpsert,
+			expiry:          opts.Expiry,
+			preserveExpiry:  opts.PreserveExpiry,
+			persistTo:       opts.PersistTo,
+			replicateTo:     opts.ReplicateTo,
+			durabilityLevel: opts.DurabilityLevel,
+			transcoder:      opts.Transcoder,
+			timeout:         opts.Timeout,
+			retryStrategy:   opts.RetryStrategy,
+			parentSpan:      opts.ParentSpan,
+			ctx:             opts.Context,
+			user:            opts.Internal.User,
+		})
+	}
+
 	opm := newKvOpManagerCore(c, "upsert", opts.ParentSpan, p)
 	defer opm.Finish()
 
@@ -198,9 +303,10 @@ func (p *kvProviderCore) Upsert(c *Collection, id string, val interface{}, opts
 	var mutOut *MutationResult
 	err := opm.Wait(p.agent.Set(gocbcore.SetOptions{
 		Key:                    opm.DocumentID(),
-		Value:                  opm.ValueBytes(),
+		Value:                  opm.SendValueBytes(),
+		Datatype:               opm.ValueDatatype(),
 		Flags:                  opm.ValueFlags(),
-		Expiry:                 durationToExpiry(opts.Expiry),
+		Expiry:                 durationToExpiry(opts.Expiry, p.timeSource),
 		CollectionName:         opm.CollectionName(),
 		ScopeName:              opm.ScopeName(),
 		DurabilityLevel:        opm.DurabilityLevel(),
@@ -251,9 +357,10 @@ func (p *kvProviderCore) Replace(c *Collection, id string, val interface{}, opts
 	var mutOut *MutationResult
 	err := opm.Wait(p.agent.Replace(gocbcore.ReplaceOptions{
 		Key:                    opm.DocumentID(),
-		Value:                  opm.ValueBytes(),
+		Value:                  opm.SendValueBytes(),
+		Datatype:               opm.ValueDatatype(),
 		Flags:                  opm.ValueFlags(),
-		Expiry:                 durationToExpiry(opts.Expiry),
+		Expiry:                 durationToExpiry(opts.Expiry, p.timeSource),
 		Cas:                    gocbcore.Cas(opts.Cas),
 		CollectionName:         opm.CollectionName(),
 		ScopeName:              opm.ScopeName(),
@@ -316,9 +423,15 @@ func (p *kvProviderCore) getDirect(c *Collection, id string, opts *GetOptions) (
 		return nil, err
 	}
 
+	err := p.waitForReadConsistency(opts.Context, c, opm.TraceSpan(), id, opts.ReadConsistency, opm.Deadline(),
+		opm.Impersonate())
+	if err != nil {
+		return nil, err
+	}
+
 	var docOut *GetResult
 	var errOut error
-	err := opm.Wait(p.agent.Get(gocbcore.GetOptions{
+	err = opm.Wait(p.agent.Get(gocbcore.GetOptions{
 		Key:            opm.DocumentID(),
 		CollectionName: opm.CollectionName(),
 		ScopeName:      opm.ScopeName(),
@@ -372,6 +485,12 @@ func (p *kvProviderCore) getProjected(c *Collection, id string, opts *GetOptions
 		return nil, err
 	}
 
+	err := p.waitForReadConsistency(opts.Context, c, opm.TraceSpan(), id, opts.ReadConsistency, opm.Deadline(),
+		opm.Impersonate())
+	if err != nil {
+		return nil, err
+	}
+
 	var withFlags bool
 	numProjects := len(opts.Project)
 	if opts.WithExpiry {
@@ -385,6 +504,15 @@ func (p *kvProviderCore) getProjected(c *Collection, id string, opts *GetOptions
 	projections := opts.Project
 	if numProjects > 16 {
 		projections = nil
+		emitWarning(c.warningHandler, Warning{
+			Type:    WarningTypeFallback,
+			Feature: "GetOptions.Project",
+			Message: "more than 16 paths were requested so the full document is being fetched and projected locally",
+			Details: map[string]interface{}{
+				"document_id": id,
+				"paths":       len(opts.Project),
+			},
+		})
 	}
 
 	var ops []LookupInSpec
@@ -483,7 +611,7 @@ func (p *kvProviderCore) GetAndTouch(c *Collection, id string, expiry time.Durat
 	var errOut error
 	err := opm.Wait(p.agent.GetAndTouch(gocbcore.GetAndTouchOptions{
 		Key:            opm.DocumentID(),
-		Expiry:         durationToExpiry(expiry),
+		Expiry:         durationToExpiry(expiry, p.timeSource),
 		CollectionName: opm.CollectionName(),
 		ScopeName:      opm.ScopeName(),
 		RetryStrategy:  opm.RetryStrategy(),
@@ -739,7 +867,7 @@ func (p *kvProviderCore) Touch(c *Collection, id string, expiry time.Duration, o
 	var mutOut *MutationResult
 	err := opm.Wait(p.agent.Touch(gocbcore.TouchOptions{
 		Key:            opm.DocumentID(),
-		Expiry:         durationToExpiry(expiry),
+		Expiry:         durationToExpiry(expiry, p.timeSource),
 		CollectionName: opm.CollectionName(),
 		ScopeName:      opm.ScopeName(),
 		RetryStrategy:  opm.RetryStrategy(),
@@ -796,6 +924,12 @@ func (p *kvProviderCore) GetAllReplicas(c *Collection, id string, opts *GetAllRe
 		return nil, err
 	}
 
+	err = p.waitForReadConsistency(ctx, c, span, id, opts.ReadConsistency, deadline, opts.Internal.User)
+	if err != nil {
+		span.End()
+		return nil, err
+	}
+
 	var servers []int
 	if opts.ReadPreference == ReadPreferenceSelectedServerGroup {
 		serverGroups, err := snapshot.KeyToServersByServerGroup([]byte(id))
@@ -893,13 +1027,14 @@ func (p *kvProviderCore) GetAnyReplica(c *Collection, id string, opts *GetAnyRep
 	defer span.End()
 
 	repRes, err := p.GetAllReplicas(c, id, &GetAllReplicaOptions{
-		Timeout:        opts.Timeout,
-		Transcoder:     opts.Transcoder,
-		RetryStrategy:  opts.RetryStrategy,
-		Internal:       opts.Internal,
-		ParentSpan:     span,
-		Context:        opts.Context,
-		ReadPreference: opts.ReadPreference,
+		Timeout:         opts.Timeout,
+		Transcoder:      opts.Transcoder,
+		RetryStrategy:   opts.RetryStrategy,
+		Internal:        opts.Internal,
+		ParentSpan:      span,
+		Context:         opts.Context,
+		ReadPreference:  opts.ReadPreference,
+		ReadConsistency: opts.ReadConsistency,
 	})
 	if err != nil {
 		return nil, err
@@ -973,6 +1108,7 @@ func (p *kvProviderCore) getOneReplica(
 			docOut.contents = res.Value
 			docOut.flags = res.Flags
 			docOut.isReplica = false
+			docOut.replicaIndex = 0
 
 			opm.Resolve(nil)
 		}))
@@ -1006,6 +1142,7 @@ func (p *kvProviderCore) getOneReplica(
 		docOut.contents = res.Value
 		docOut.flags = res.Flags
 		docOut.isReplica = true
+		docOut.replicaIndex = replicaIdx
 
 		opm.Resolve(nil)
 	}))
@@ -1158,7 +1295,7 @@ func (p *kvProviderCore) Increment(c *Collection, id string, opts *IncrementOpti
 		Key:                    opm.DocumentID(),
 		Delta:                  opts.Delta,
 		Initial:                realInitial,
-		Expiry:                 durationToExpiry(opts.Expiry),
+		Expiry:                 durationToExpiry(opts.Expiry, p.timeSource),
 		CollectionName:         opm.CollectionName(),
 		ScopeName:              opm.ScopeName(),
 		DurabilityLevel:        opm.DurabilityLevel(),
@@ -1219,7 +1356,7 @@ func (p *kvProviderCore) Decrement(c *Collection, id string, opts *DecrementOpti
 		Key:                    opm.DocumentID(),
 		Delta:                  opts.Delta,
 		Initial:                realInitial,
-		Expiry:                 durationToExpiry(opts.Expiry),
+		Expiry:                 durationToExpiry(opts.Expiry, p.timeSource),
 		CollectionName:         opm.CollectionName(),
 		ScopeName:              opm.ScopeName(),
 		DurabilityLevel:        opm.DurabilityLevel(),
@@ -42,6 +42,7 @@ type coreConfigSnapshot interface {
 	NumVbuckets() (int, error)
 	NumReplicas() (int, error)
 	NumServers() (int, error)
+	KeyToVbucket(key []byte) (uint16, error)
 	VbucketsOnServer(index int) ([]uint16, error)
 	KeyToServersByServerGroup(key []byte) (map[string][]int, error)
 }
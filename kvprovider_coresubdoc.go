@@ -1,8 +1,8 @@
 package gocb
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"time"
 
@@ -423,7 +423,7 @@ func (p *kvProviderCore) internalMutateIn(
 		Flags:                  docFlags,
 		Cas:                    gocbcore.Cas(cas),
 		Ops:                    subdocs,
-		Expiry:                 durationToExpiry(expiry),
+		Expiry:                 durationToExpiry(expiry, p.timeSource),
 		CollectionName:         opm.CollectionName(),
 		ScopeName:              opm.ScopeName(),
 		DurabilityLevel:        opm.DurabilityLevel(),
@@ -465,11 +465,12 @@ func (p *kvProviderCore) internalMutateIn(
 	return mutOut, errOut
 }
 
-func jsonMarshalMultiArray(in interface{}) ([]byte, error) {
-	out, err := json.Marshal(in)
+func jsonMarshalMultiArray(in interface{}, serializer SubdocSerializer) ([]byte, error) {
+	out, err := serializer.Serialize(in)
 	if err != nil {
 		return nil, err
 	}
+	out = bytes.TrimSpace(out)
 
 	// Assert first character is a '['
 	if len(out) < 2 || out[0] != '[' {
@@ -503,11 +504,16 @@ func jsonMarshalMutateSpec(op MutateInSpec) ([]byte, memd.SubdocFlag, error) {
 		return []byte(macro), memd.SubdocFlagExpandMacros | memd.SubdocFlagXattrPath, nil
 	}
 
+	serializer := op.serializer
+	if serializer == nil {
+		serializer = NewJSONSubdocSerializer()
+	}
+
 	if op.multiValue {
-		bytes, err := jsonMarshalMultiArray(op.value)
+		bytes, err := jsonMarshalMultiArray(op.value, serializer)
 		return bytes, memd.SubdocFlagNone, err
 	}
 
-	bytes, err := json.Marshal(op.value)
+	bytes, err := serializer.Serialize(op.value)
 	return bytes, memd.SubdocFlagNone, err
 }
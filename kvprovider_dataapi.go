@@ -0,0 +1,379 @@
+package gocb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+)
+
+// kvProviderDataAPI performs key value CRUD operations using the document endpoints of the Data API. Operations which
+// have no REST equivalent, such as subdocument, counter and replica operations, return ErrFeatureNotAvailable.
+type kvProviderDataAPI struct {
+	mgr    *dataAPIConnectionMgr
+	tracer *tracerWrapper
+}
+
+var _ kvProvider = &kvProviderDataAPI{}
+
+// dataAPIFlagsHeader carries the flags of a document, which the transcoder uses to determine how to decode it.
+const dataAPIFlagsHeader = "X-Cb-Flags"
+
+func dataAPIUnsupported(feature string) error {
+	return wrapError(ErrFeatureNotAvailable, feature+" is not supported by the Data API")
+}
+
+func dataAPIDocumentPath(c *Collection, id string) string {
+	return fmt.Sprintf("/v1/buckets/%s/scopes/%s/collections/%s/documents/%s", url.PathEscape(c.bucketName()),
+		url.PathEscape(c.ScopeName()), url.PathEscape(c.name()), url.PathEscape(id))
+}
+
+// formatDataAPICas formats a cas as an entity tag, for use with If-Match.
+func formatDataAPICas(cas Cas) string {
+	return `"` + strconv.FormatUint(uint64(cas), 10) + `"`
+}
+
+// parseDataAPICas parses the cas of a document from the entity tag of a response.
+func parseDataAPICas(header http.Header) Cas {
+	etag := strings.TrimPrefix(header.Get("ETag"), "W/")
+	etag = strings.Trim(etag, `"`)
+	if etag == "" {
+		return 0
+	}
+
+	var cas uint64
+	var err error
+	if strings.HasPrefix(etag, "0x") {
+		cas, err = strconv.ParseUint(etag[2:], 16, 64)
+	} else {
+		cas, err = strconv.ParseUint(etag, 10, 64)
+	}
+	if err != nil {
+		logDebugf("Failed to parse Data API entity tag %s: %s", etag, err)
+		return 0
+	}
+
+	return Cas(cas)
+}
+
+func dataAPICheckDurability(persistTo, replicateTo uint, level DurabilityLevel) error {
+	if persistTo > 0 || replicateTo > 0 || level > DurabilityLevelNone {
+		return dataAPIUnsupported("durability")
+	}
+	return nil
+}
+
+func (p *kvProviderDataAPI) timeout(c *Collection, timeout time.Duration) time.Duration {
+	if timeout > 0 {
+		return timeout
+	}
+	return c.timeoutsConfig.KVTimeout
+}
+
+type dataAPIMutation struct {
+	method     string
+	value      interface{}
+	transcoder Transcoder
+	cas        Cas
+	// mustExist causes the mutation to fail if the document does not exist, even when no cas is provided.
+	mustExist bool
+	expiry    time.Duration
+
+	timeout    time.Duration
+	parentSpan RequestSpan
+	ctx        context.Context
+}
+
+func (p *kvProviderDataAPI) mutate(c *Collection, opName, id string, m dataAPIMutation) (*MutationResult, error) {
+	span := p.StartKvOpTrace(c, opName, m.parentSpan, false)
+	defer span.End()
+
+	if id == "" {
+		return nil, makeInvalidArgumentsError("document id cannot be empty")
+	}
+
+	header := make(http.Header)
+	var body []byte
+	if m.value != nil {
+		transcoder := m.transcoder
+		if transcoder == nil {
+			transcoder = c.transcoder
+		}
+
+		espan := p.StartKvOpTrace(c, "request_encoding", span, true)
+		value, flags, err := transcoder.Encode(m.value)
+		espan.End()
+		if err != nil {
+			return nil, err
+		}
+		if _, _, err := validateValueSize(nil, id, value); err != nil {
+			return nil, err
+		}
+
+		body = value
+		if dataType, _ := gocbcore.DecodeCommonFlags(flags); dataType == gocbcore.JSONType {
+			header.Set("Content-Type", "application/json")
+		} else {
+			header.Set("Content-Type", "application/octet-stream")
+		}
+		header.Set(dataAPIFlagsHeader, strconv.FormatUint(uint64(flags), 10))
+	}
+	if m.cas > 0 {
+		header.Set("If-Match", formatDataAPICas(m.cas))
+	} else if m.mustExist {
+		header.Set("If-Match", "*")
+	}
+	if m.expiry > 0 {
+		now := time.Now
+		if p.mgr.timeSource != nil {
+			now = p.mgr.timeSource
+		}
+		header.Set("Expires", now().Add(m.expiry).UTC().Format(http.TimeFormat))
+	}
+
+	resp, err := p.mgr.execute(m.ctx, p.timeout(c, m.timeout), false, m.method, dataAPIDocumentPath(c, id), header,
+		body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// A failed precondition without a cas means that the document did not exist.
+		if resp.StatusCode == http.StatusPreconditionFailed && m.cas == 0 {
+			resp.StatusCode = http.StatusNotFound
+		}
+		return nil, maybeEnhanceValueTooLargeErr(dataAPIStatusError(resp), id, body)
+	}
+
+	return &MutationResult{
+		Result: Result{
+			cas: parseDataAPICas(resp.Header),
+		},
+	}, nil
+}
+
+func (p *kvProviderDataAPI) Insert(c *Collection, id string, val interface{}, opts *InsertOptions) (*MutationResult, error) {
+	if err := dataAPICheckDurability(opts.PersistTo, opts.ReplicateTo, opts.DurabilityLevel); err != nil {
+		return nil, err
+	}
+	if opts.Idempotent {
+		return nil, dataAPIUnsupported("the Idempotent option")
+	}
+
+	return p.mutate(c, "insert", id, dataAPIMutation{
+		method:     http.MethodPost,
+		value:      val,
+		transcoder: opts.Transcoder,
+		expiry:     opts.Expiry,
+		timeout:    opts.Timeout,
+		parentSpan: opts.ParentSpan,
+		ctx:        opts.Context,
+	})
+}
+
+func (p *kvProviderDataAPI) Upsert(c *Collection, id string, val interface{}, opts *UpsertOptions) (*MutationResult, error) {
+	if err := dataAPICheckDurability(opts.PersistTo, opts.ReplicateTo, opts.DurabilityLevel); err != nil {
+		return nil, err
+	}
+	if opts.Idempotent {
+		return nil, dataAPIUnsupported("the Idempotent option")
+	}
+	if opts.PreserveExpiry {
+		return nil, dataAPIUnsupported("the PreserveExpiry option")
+	}
+
+	return p.mutate(c, "upsert", id, dataAPIMutation{
+		method:     http.MethodPut,
+		value:      val,
+		transcoder: opts.Transcoder,
+		expiry:     opts.Expiry,
+		timeout:    opts.Timeout,
+		parentSpan: opts.ParentSpan,
+		ctx:        opts.Context,
+	})
+}
+
+func (p *kvProviderDataAPI) Replace(c *Collection, id string, val interface{}, opts *ReplaceOptions) (*MutationResult, error) {
+	if err := dataAPICheckDurability(opts.PersistTo, opts.ReplicateTo, opts.DurabilityLevel); err != nil {
+		return nil, err
+	}
+	if opts.PreserveExpiry {
+		return nil, dataAPIUnsupported("the PreserveExpiry option")
+	}
+
+	return p.mutate(c, "replace", id, dataAPIMutation{
+		method:     http.MethodPut,
+		value:      val,
+		transcoder: opts.Transcoder,
+		cas:        opts.Cas,
+		mustExist:  true,
+		expiry:     opts.Expiry,
+		timeout:    opts.Timeout,
+		parentSpan: opts.ParentSpan,
+		ctx:        opts.Context,
+	})
+}
+
+func (p *kvProviderDataAPI) Remove(c *Collection, id string, opts *RemoveOptions) (*MutationResult, error) {
+	if err := dataAPICheckDurability(opts.PersistTo, opts.ReplicateTo, opts.DurabilityLevel); err != nil {
+		return nil, err
+	}
+
+	return p.mutate(c, "remove", id, dataAPIMutation{
+		method:     http.MethodDelete,
+		cas:        opts.Cas,
+		timeout:    opts.Timeout,
+		parentSpan: opts.ParentSpan,
+		ctx:        opts.Context,
+	})
+}
+
+func (p *kvProviderDataAPI) Get(c *Collection, id string, opts *GetOptions) (*GetResult, error) {
+	if opts.WithExpiry || len(opts.Project) > 0 || opts.ReadConsistency != nil {
+		return nil, dataAPIUnsupported("the WithExpiry, Project and ReadConsistency options")
+	}
+
+	span := p.StartKvOpTrace(c, "get", opts.ParentSpan, false)
+	defer span.End()
+
+	if id == "" {
+		return nil, makeInvalidArgumentsError("document id cannot be empty")
+	}
+
+	resp, err := p.mgr.execute(opts.Context, p.timeout(c, opts.Timeout), true, http.MethodGet,
+		dataAPIDocumentPath(c, id), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, dataAPIStatusError(resp)
+	}
+
+	transcoder := opts.Transcoder
+	if transcoder == nil {
+		transcoder = c.transcoder
+	}
+
+	// Documents written without flags, such as those written by the Data API itself, are JSON.
+	flags := gocbcore.EncodeCommonFlags(gocbcore.JSONType, gocbcore.NoCompression)
+	if flagsHeader := resp.Header.Get(dataAPIFlagsHeader); flagsHeader != "" {
+		parsed, err := strconv.ParseUint(flagsHeader, 10, 32)
+		if err != nil {
+			logDebugf("Failed to parse Data API document flags %s: %s", flagsHeader, err)
+		} else {
+			flags = uint32(parsed)
+		}
+	}
+
+	return &GetResult{
+		Result: Result{
+			cas: parseDataAPICas(resp.Header),
+		},
+		transcoder: transcoder,
+		contents:   resp.Body,
+		flags:      flags,
+	}, nil
+}
+
+func (p *kvProviderDataAPI) Exists(c *Collection, id string, opts *ExistsOptions) (*ExistsResult, error) {
+	span := p.StartKvOpTrace(c, "exists", opts.ParentSpan, false)
+	defer span.End()
+
+	if id == "" {
+		return nil, makeInvalidArgumentsError("document id cannot be empty")
+	}
+
+	resp, err := p.mgr.execute(opts.Context, p.timeout(c, opts.Timeout), true, http.MethodHead,
+		dataAPIDocumentPath(c, id), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return &ExistsResult{
+			Result: Result{
+				cas: parseDataAPICas(resp.Header),
+			},
+			docExists: true,
+		}, nil
+	case http.StatusNotFound:
+		return &ExistsResult{}, nil
+	default:
+		return nil, dataAPIStatusError(resp)
+	}
+}
+
+func (p *kvProviderDataAPI) GetAndTouch(*Collection, string, time.Duration, *GetAndTouchOptions) (*GetResult, error) {
+	return nil, dataAPIUnsupported("GetAndTouch")
+}
+
+func (p *kvProviderDataAPI) GetAndLock(*Collection, string, time.Duration, *GetAndLockOptions) (*GetResult, error) {
+	return nil, dataAPIUnsupported("GetAndLock")
+}
+
+func (p *kvProviderDataAPI) Unlock(*Collection, string, Cas, *UnlockOptions) error {
+	return dataAPIUnsupported("Unlock")
+}
+
+func (p *kvProviderDataAPI) Touch(*Collection, string, time.Duration, *TouchOptions) (*MutationResult, error) {
+	return nil, dataAPIUnsupported("Touch")
+}
+
+func (p *kvProviderDataAPI) GetAnyReplica(*Collection, string, *GetAnyReplicaOptions) (*GetReplicaResult, error) {
+	return nil, dataAPIUnsupported("GetAnyReplica")
+}
+
+func (p *kvProviderDataAPI) GetAllReplicas(*Collection, string, *GetAllReplicaOptions) (*GetAllReplicasResult, error) {
+	return nil, dataAPIUnsupported("GetAllReplicas")
+}
+
+func (p *kvProviderDataAPI) LookupIn(*Collection, string, []LookupInSpec, *LookupInOptions) (*LookupInResult, error) {
+	return nil, dataAPIUnsupported("LookupIn")
+}
+
+func (p *kvProviderDataAPI) LookupInAnyReplica(*Collection, string, []LookupInSpec, *LookupInAnyReplicaOptions) (*LookupInReplicaResult, error) {
+	return nil, dataAPIUnsupported("LookupInAnyReplica")
+}
+
+func (p *kvProviderDataAPI) LookupInAllReplicas(*Collection, string, []LookupInSpec, *LookupInAllReplicaOptions) (*LookupInAllReplicasResult, error) {
+	return nil, dataAPIUnsupported("LookupInAllReplicas")
+}
+
+func (p *kvProviderDataAPI) MutateIn(*Collection, string, []MutateInSpec, *MutateInOptions) (*MutateInResult, error) {
+	return nil, dataAPIUnsupported("MutateIn")
+}
+
+func (p *kvProviderDataAPI) Increment(*Collection, string, *IncrementOptions) (*CounterResult, error) {
+	return nil, dataAPIUnsupported("Increment")
+}
+
+func (p *kvProviderDataAPI) Decrement(*Collection, string, *DecrementOptions) (*CounterResult, error) {
+	return nil, dataAPIUnsupported("Decrement")
+}
+
+func (p *kvProviderDataAPI) Append(*Collection, string, []byte, *AppendOptions) (*MutationResult, error) {
+	return nil, dataAPIUnsupported("Append")
+}
+
+func (p *kvProviderDataAPI) Prepend(*Collection, string, []byte, *PrependOptions) (*MutationResult, error) {
+	return nil, dataAPIUnsupported("Prepend")
+}
+
+func (p *kvProviderDataAPI) Scan(*Collection, ScanType, *ScanOptions) (*ScanResult, error) {
+	return nil, dataAPIUnsupported("Scan")
+}
+
+func (p *kvProviderDataAPI) GroupKeysByNode(*Collection, []string, *GroupKeysByNodeOptions) (*GroupKeysResult, error) {
+	return nil, dataAPIUnsupported("GroupKeysByNode")
+}
+
+func (p *kvProviderDataAPI) StartKvOpTrace(c *Collection, operationName string, parentSpan RequestSpan, noAttributes bool) RequestSpan {
+	return c.startKvOpTrace(operationName, parentSpan, p.tracer, noAttributes)
+}
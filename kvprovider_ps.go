@@ -1,3 +1,5 @@
+//go:build !gocb_nocouchbase2
+
 //nolint:unused
 package gocb
 
@@ -139,7 +141,7 @@ func (p *kvProviderPs) MutateIn(c *Collection, id string, ops []MutateInSpec, op
 	opm.SetDocumentID(id)
 	opm.SetRetryStrategy(opts.RetryStrategy)
 	opm.SetTimeout(opts.Timeout)
-	opm.SetDuraOptions(opts.DurabilityLevel)
+	opm.SetDuraOptions(opts.PersistTo, opts.ReplicateTo, opts.DurabilityLevel)
 	opm.SetContext(opts.Context)
 
 	if err := opm.CheckReadyForOp(); err != nil {
@@ -265,7 +267,15 @@ func (p *kvProviderPs) Scan(c *Collection, scanType ScanType, opts *ScanOptions)
 	return nil, ErrFeatureNotAvailable
 }
 
+func (p *kvProviderPs) GroupKeysByNode(c *Collection, keys []string, opts *GroupKeysByNodeOptions) (*GroupKeysResult, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
 func (p *kvProviderPs) Insert(c *Collection, id string, val interface{}, opts *InsertOptions) (*MutationResult, error) {
+	if opts.Idempotent {
+		return nil, wrapError(ErrFeatureNotAvailable, "the Idempotent option is not supported by the couchbase2 protocol")
+	}
+
 	opm := newKvOpManagerPs(c, "insert", opts.ParentSpan, p)
 	defer opm.Finish()
 
@@ -273,7 +283,7 @@ func (p *kvProviderPs) Insert(c *Collection, id string, val interface{}, opts *I
 	opm.SetTranscoder(opts.Transcoder)
 	opm.SetRetryStrategy(opts.RetryStrategy)
 	opm.SetValue(val)
-	opm.SetDuraOptions(opts.DurabilityLevel)
+	opm.SetDuraOptions(opts.PersistTo, opts.ReplicateTo, opts.DurabilityLevel)
 	opm.SetTimeout(opts.Timeout)
 	opm.SetContext(opts.Context)
 
@@ -323,6 +333,10 @@ func (p *kvProviderPs) Insert(c *Collection, id string, val interface{}, opts *I
 }
 
 func (p *kvProviderPs) Upsert(c *Collection, id string, val interface{}, opts *UpsertOptions) (*MutationResult, error) {
+	if opts.Idempotent {
+		return nil, wrapError(ErrFeatureNotAvailable, "the Idempotent option is not supported by the couchbase2 protocol")
+	}
+
 	opm := newKvOpManagerPs(c, "upsert", opts.ParentSpan, p)
 	defer opm.Finish()
 
@@ -330,7 +344,7 @@ func (p *kvProviderPs) Upsert(c *Collection, id string, val interface{}, opts *U
 	opm.SetTranscoder(opts.Transcoder)
 	opm.SetRetryStrategy(opts.RetryStrategy)
 	opm.SetValue(val)
-	opm.SetDuraOptions(opts.DurabilityLevel)
+	opm.SetDuraOptions(opts.PersistTo, opts.ReplicateTo, opts.DurabilityLevel)
 	opm.SetTimeout(opts.Timeout)
 	opm.SetContext(opts.Context)
 
@@ -396,7 +410,7 @@ func (p *kvProviderPs) Replace(c *Collection, id string, val interface{}, opts *
 	opm.SetTranscoder(opts.Transcoder)
 	opm.SetRetryStrategy(opts.RetryStrategy)
 	opm.SetValue(val)
-	opm.SetDuraOptions(opts.DurabilityLevel)
+	opm.SetDuraOptions(opts.PersistTo, opts.ReplicateTo, opts.DurabilityLevel)
 	opm.SetTimeout(opts.Timeout)
 	opm.SetContext(opts.Context)
 
@@ -457,6 +471,10 @@ func (p *kvProviderPs) Replace(c *Collection, id string, val interface{}, opts *
 }
 
 func (p *kvProviderPs) Get(c *Collection, id string, opts *GetOptions) (*GetResult, error) {
+	if opts.ReadConsistency != nil {
+		return nil, wrapError(ErrFeatureNotAvailable, "the ReadConsistency option is not supported by the couchbase2 protocol")
+	}
+
 	opm := newKvOpManagerPs(c, "get", opts.ParentSpan, p)
 	defer opm.Finish()
 
@@ -653,7 +671,7 @@ func (p *kvProviderPs) Remove(c *Collection, id string, opts *RemoveOptions) (*M
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
-	opm.SetDuraOptions(opts.DurabilityLevel)
+	opm.SetDuraOptions(opts.PersistTo, opts.ReplicateTo, opts.DurabilityLevel)
 	opm.SetRetryStrategy(opts.RetryStrategy)
 	opm.SetTimeout(opts.Timeout)
 	opm.SetContext(opts.Context)
@@ -780,6 +798,12 @@ func (r *psReplicasResult) Next() interface{} {
 		return nil
 	}
 
+	// The couchbase2 protocol does not tell us which replica served the result.
+	replicaIndex := 0
+	if res.IsReplica {
+		replicaIndex = -1
+	}
+
 	return &GetReplicaResult{
 		GetResult: GetResult{
 			Result: Result{
@@ -789,7 +813,8 @@ func (r *psReplicasResult) Next() interface{} {
 			flags:      res.ContentFlags,
 			contents:   res.Content,
 		},
-		isReplica: res.IsReplica,
+		isReplica:    res.IsReplica,
+		replicaIndex: replicaIndex,
 	}
 }
 
@@ -836,6 +861,10 @@ func (r *psReplicasResult) finishWithError(err error) {
 }
 
 func (p *kvProviderPs) GetAllReplicas(c *Collection, id string, opts *GetAllReplicaOptions) (*GetAllReplicasResult, error) {
+	if opts.ReadConsistency != nil {
+		return nil, wrapError(ErrFeatureNotAvailable, "the ReadConsistency option is not supported by the couchbase2 protocol")
+	}
+
 	opm := newKvOpManagerPs(c, "get_all_replicas", opts.ParentSpan, p)
 	defer opm.Finish()
 
@@ -878,12 +907,13 @@ func (p *kvProviderPs) GetAnyReplica(c *Collection, id string, opts *GetAnyRepli
 	defer opm.Finish()
 
 	res, err := p.GetAllReplicas(c, id, &GetAllReplicaOptions{
-		Transcoder:    opts.Transcoder,
-		Timeout:       opts.Timeout,
-		RetryStrategy: opts.RetryStrategy,
-		ParentSpan:    opm.TraceSpan(),
-		Context:       opts.Context,
-		Internal:      opts.Internal,
+		Transcoder:      opts.Transcoder,
+		Timeout:         opts.Timeout,
+		RetryStrategy:   opts.RetryStrategy,
+		ParentSpan:      opm.TraceSpan(),
+		Context:         opts.Context,
+		Internal:        opts.Internal,
+		ReadConsistency: opts.ReadConsistency,
 	})
 	if err != nil {
 		return nil, opm.EnhanceErr(err, false)
@@ -902,7 +932,7 @@ func (p *kvProviderPs) Prepend(c *Collection, id string, val []byte, opts *Prepe
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
-	opm.SetDuraOptions(opts.DurabilityLevel)
+	opm.SetDuraOptions(opts.PersistTo, opts.ReplicateTo, opts.DurabilityLevel)
 	opm.SetRetryStrategy(opts.RetryStrategy)
 	opm.SetTimeout(opts.Timeout)
 	opm.SetContext(opts.Context)
@@ -949,7 +979,7 @@ func (p *kvProviderPs) Append(c *Collection, id string, val []byte, opts *Append
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
-	opm.SetDuraOptions(opts.DurabilityLevel)
+	opm.SetDuraOptions(opts.PersistTo, opts.ReplicateTo, opts.DurabilityLevel)
 	opm.SetRetryStrategy(opts.RetryStrategy)
 	opm.SetTimeout(opts.Timeout)
 	opm.SetContext(opts.Context)
@@ -1000,7 +1030,7 @@ func (p *kvProviderPs) Increment(c *Collection, id string, opts *IncrementOption
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
-	opm.SetDuraOptions(opts.DurabilityLevel)
+	opm.SetDuraOptions(opts.PersistTo, opts.ReplicateTo, opts.DurabilityLevel)
 	opm.SetTimeout(opts.Timeout)
 	opm.SetRetryStrategy(opts.RetryStrategy)
 	opm.SetContext(opts.Context)
@@ -1043,7 +1073,7 @@ func (p *kvProviderPs) Decrement(c *Collection, id string, opts *DecrementOption
 	defer opm.Finish()
 
 	opm.SetDocumentID(id)
-	opm.SetDuraOptions(opts.DurabilityLevel)
+	opm.SetDuraOptions(opts.PersistTo, opts.ReplicateTo, opts.DurabilityLevel)
 	opm.SetTimeout(opts.Timeout)
 	opm.SetRetryStrategy(opts.RetryStrategy)
 	opm.SetContext(opts.Context)
@@ -1104,3 +1134,23 @@ func (p *kvProviderPs) newOpCtx(ctx context.Context, timeout time.Duration) (con
 
 	return context.WithTimeout(ctx, timeout)
 }
+
+func (dl DurabilityLevel) toProtostellar() (*kv_v1.DurabilityLevel, error) {
+	var level kv_v1.DurabilityLevel
+	switch dl {
+	case DurabilityLevelNone:
+		return nil, nil
+	case DurabilityLevelMajority:
+		level = kv_v1.DurabilityLevel_DURABILITY_LEVEL_MAJORITY
+	case DurabilityLevelMajorityAndPersistOnMaster:
+		level = kv_v1.DurabilityLevel_DURABILITY_LEVEL_MAJORITY_AND_PERSIST_TO_ACTIVE
+	case DurabilityLevelPersistToMajority:
+		level = kv_v1.DurabilityLevel_DURABILITY_LEVEL_PERSIST_TO_MAJORITY
+	case DurabilityLevelUnknown:
+		return nil, makeInvalidArgumentsError("unexpected unset durability level")
+	default:
+		return nil, makeInvalidArgumentsError("unexpected durability level")
+	}
+
+	return &level, nil
+}
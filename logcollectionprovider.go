@@ -0,0 +1,6 @@
+package gocb
+
+type logCollectionProvider interface {
+	StartLogCollection(opts *StartLogCollectionOptions) error
+	GetLogCollectionStatus(opts *GetLogCollectionStatusOptions) (*LogCollectionStatus, error)
+}
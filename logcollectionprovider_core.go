@@ -0,0 +1,127 @@
+package gocb
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type logCollectionProviderCore struct {
+	provider mgmtProvider
+
+	tracer *tracerWrapper
+}
+
+func (lp *logCollectionProviderCore) StartLogCollection(opts *StartLogCollectionOptions) error {
+	span := lp.tracer.createSpan(opts.ParentSpan, "manager_cluster_start_log_collection", "management")
+	span.SetAttribute("db.operation", "POST /controller/startLogsCollection")
+	defer span.End()
+
+	reqForm := make(url.Values)
+	if len(opts.Nodes) > 0 {
+		reqForm.Add("nodes", strings.Join(opts.Nodes, ","))
+	} else {
+		reqForm.Add("nodes", "*")
+	}
+	if opts.RedactionLevel == RedactPartial {
+		reqForm.Add("logRedactionLevel", "partial")
+	} else {
+		reqForm.Add("logRedactionLevel", "none")
+	}
+	if opts.UploadHost != "" {
+		reqForm.Add("uploadHost", opts.UploadHost)
+		reqForm.Add("customer", opts.Customer)
+	}
+	if opts.UploadProxy != "" {
+		reqForm.Add("uploadProxy", opts.UploadProxy)
+	}
+	if opts.Ticket != "" {
+		reqForm.Add("ticket", opts.Ticket)
+	}
+	if opts.LogDir != "" {
+		reqForm.Add("logDir", opts.LogDir)
+	}
+	if opts.TmpDir != "" {
+		reqForm.Add("tmpDir", opts.TmpDir)
+	}
+	if opts.BypassReachabilityChecks {
+		reqForm.Add("bypassReachabilityChecks", "true")
+	}
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "POST",
+		Path:          "/controller/startLogsCollection",
+		Body:          []byte(reqForm.Encode()),
+		ContentType:   "application/x-www-form-urlencoded",
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := lp.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return makeMgmtBadStatusError("failed to start log collection", &req, resp)
+	}
+
+	return nil
+}
+
+func (lp *logCollectionProviderCore) GetLogCollectionStatus(opts *GetLogCollectionStatusOptions) (*LogCollectionStatus, error) {
+	span := lp.tracer.createSpan(opts.ParentSpan, "manager_cluster_get_log_collection_status", "management")
+	span.SetAttribute("db.operation", "GET /pools/default/tasks")
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "GET",
+		Path:          "/pools/default/tasks",
+		RetryStrategy: opts.RetryStrategy,
+		IsIdempotent:  true,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := lp.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return nil, makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, makeMgmtBadStatusError("failed to get log collection status", &req, resp)
+	}
+
+	var tasksData []jsonLogCollectionTask
+	jsonDec := json.NewDecoder(resp.Body)
+	err = jsonDec.Decode(&tasksData)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &LogCollectionStatus{
+		State: LogCollectionStateIdle,
+		Nodes: make(map[string]LogCollectionNodeStatus),
+	}
+	for _, taskData := range tasksData {
+		if taskData.Type != "clusterLogsCollection" {
+			continue
+		}
+
+		err := status.fromData(taskData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return status, nil
+}
@@ -64,6 +64,8 @@ type AggregatingMeterOptions struct {
 // Note that this function will soon be deprecated.
 // Deprecated: See NewLoggingMeter.
 func NewAggregatingMeter(opts *AggregatingMeterOptions) *LoggingMeter {
+	warnDeprecated(nil, "NewAggregatingMeter", "NewAggregatingMeter is deprecated, use NewLoggingMeter instead")
+
 	am := newAggregatingMeter(&LoggingMeterOptions{
 		EmitInterval: opts.EmitInterval,
 	})
@@ -10,6 +10,10 @@ import (
 )
 
 // Meter handles metrics information for SDK operations.
+//
+// The meter also receives the metrics recorded by the underlying gocbcore library. The durations of the individual
+// requests that gocbcore dispatches, which it names db.couchbase.operations, are recorded under
+// db.couchbase.operations.dispatch instead so that they are kept apart from the durations of SDK operations.
 type Meter interface {
 	Counter(name string, tags map[string]string) (Counter, error)
 	ValueRecorder(name string, tags map[string]string) (ValueRecorder, error)
@@ -59,12 +63,10 @@ type noopValueRecorder struct{}
 func (bc *noopValueRecorder) RecordValue(val uint64) {
 }
 
-//nolint:unused
 type coreMeterWrapper struct {
 	meter Meter
 }
 
-//nolint:unused
 func (meter *coreMeterWrapper) Counter(name string, tags map[string]string) (gocbcore.Counter, error) {
 	counter, err := meter.meter.Counter(name, tags)
 	if err != nil {
@@ -75,12 +77,16 @@ func (meter *coreMeterWrapper) Counter(name string, tags map[string]string) (goc
 	}, nil
 }
 
-//nolint:unused
 func (meter *coreMeterWrapper) ValueRecorder(name string, tags map[string]string) (gocbcore.ValueRecorder, error) {
 	if name == "db.couchbase.requests" {
 		// gocbcore has its own requests metrics, we don't want to record those.
 		return &noopValueRecorder{}, nil
 	}
+	if name == meterNameCBOperations {
+		// gocbcore records the durations of the requests that it dispatches under the same name as our operations
+		// metric, keep them apart so that operations aren't counted twice. This renaming is documented on Meter.
+		name = meterNameCBOperationsDispatch
+	}
 
 	recorder, err := meter.meter.ValueRecorder(name, tags)
 	if err != nil {
@@ -91,22 +97,18 @@ func (meter *coreMeterWrapper) ValueRecorder(name string, tags map[string]string
 	}, nil
 }
 
-//nolint:unused
 type coreCounterWrapper struct {
 	counter Counter
 }
 
-//nolint:unused
 func (nm *coreCounterWrapper) IncrementBy(num uint64) {
 	nm.counter.IncrementBy(num)
 }
 
-//nolint:unused
 type coreValueRecorderWrapper struct {
 	valueRecorder ValueRecorder
 }
 
-//nolint:unused
 func (nm *coreValueRecorderWrapper) RecordValue(val uint64) {
 	nm.valueRecorder.RecordValue(val)
 }
@@ -138,6 +140,15 @@ func (mw *meterWrapper) ValueRecorder(service, operation string, keyspace *keysp
 		return defaultNoopValueRecorder, nil
 	}
 
+	recorder, err := mw.meter.ValueRecorder(meterNameCBOperations, mw.attributes(service, operation, keyspace, operationErr))
+	if err != nil {
+		return nil, err
+	}
+
+	return recorder, nil
+}
+
+func (mw *meterWrapper) attributes(service, operation string, keyspace *keyspace, operationErr error) map[string]string {
 	var labels gocbcore.ClusterLabels
 	if mw.clusterLabelsProvider != nil {
 		labels = mw.clusterLabelsProvider.ClusterLabels()
@@ -172,28 +183,25 @@ func (mw *meterWrapper) ValueRecorder(service, operation string, keyspace *keysp
 		if labels.ClusterUUID != "" {
 			attribsMap[meterAttribClusterUUIDKey] = labels.ClusterUUID
 		}
-		if keyspace.bucketName != "" {
-			attribsMap[meterAttribBucketNameKey] = keyspace.bucketName
-		}
-		if keyspace.scopeName != "" {
-			attribsMap[meterAttribScopeNameKey] = keyspace.scopeName
-		}
-		if keyspace.collectionName != "" {
-			attribsMap[meterAttribCollectionNameKey] = keyspace.collectionName
+		if keyspace != nil {
+			if keyspace.bucketName != "" {
+				attribsMap[meterAttribBucketNameKey] = keyspace.bucketName
+			}
+			if keyspace.scopeName != "" {
+				attribsMap[meterAttribScopeNameKey] = keyspace.scopeName
+			}
+			if keyspace.collectionName != "" {
+				attribsMap[meterAttribCollectionNameKey] = keyspace.collectionName
+			}
 		}
 		mw.attribsCache.Store(key, attribsMap)
 	}
 
-	recorder, err := mw.meter.ValueRecorder(meterNameCBOperations, attribsMap)
-	if err != nil {
-		return nil, err
-	}
-
-	return recorder, nil
+	return attribsMap
 }
 
-func (mw *meterWrapper) ValueRecord(service, operation string, start time.Time, keyspace *keyspace, err error) {
-	recorder, err := mw.ValueRecorder(service, operation, keyspace, err)
+func (mw *meterWrapper) ValueRecord(service, operation string, start time.Time, keyspace *keyspace, operationErr error) {
+	recorder, err := mw.ValueRecorder(service, operation, keyspace, operationErr)
 	if err != nil {
 		logDebugf("Failed to create value recorder: %v", err)
 		return
@@ -205,6 +213,53 @@ func (mw *meterWrapper) ValueRecord(service, operation string, start time.Time,
 	}
 
 	recorder.RecordValue(duration)
+
+	if operationErr != nil {
+		mw.countUnsuccessful(service, operation, keyspace, operationErr)
+	}
+}
+
+// countUnsuccessful counts an operation which did not succeed, cancellations by the application are counted
+// separately to failures so that the two can be told apart.
+func (mw *meterWrapper) countUnsuccessful(service, operation string, keyspace *keyspace, operationErr error) {
+	if mw.isNoopMeter {
+		return
+	}
+
+	name := meterNameCBOperationsFailed
+	if isCancellation(nil, operationErr) {
+		name = meterNameCBOperationsCancelled
+	}
+
+	counter, err := mw.meter.Counter(name, mw.attributes(service, operation, keyspace, operationErr))
+	if err != nil {
+		logDebugf("Failed to create counter: %v", err)
+		return
+	}
+
+	counter.IncrementBy(1)
+}
+
+// streamOutcome records the outcome of a result stream, which can fail or be cancelled after the operation that
+// opened it has returned and been recorded as successful. Only the first error seen on the stream is recorded.
+type streamOutcome struct {
+	once   sync.Once
+	record func(err error)
+}
+
+func (o *streamOutcome) observe(err error) {
+	if o == nil || err == nil {
+		return
+	}
+
+	o.once.Do(func() {
+		o.record(err)
+	})
+}
+
+// streamingResult is implemented by results which stream rows after the operation has returned.
+type streamingResult interface {
+	setStreamOutcome(outcome *streamOutcome)
 }
 
 // getStandardizedOutcome returns the name for each error as listed in RFC#58 (Error Handling)
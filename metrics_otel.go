@@ -0,0 +1,106 @@
+package gocb
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const otelMeterInstrumentationName = "com.couchbase.client/go"
+
+// OtelMeter is a Meter implementation which publishes metrics to an OpenTelemetry MeterProvider, operation
+// durations are recorded in microseconds to the db.couchbase.operations histogram.
+// UNCOMMITTED: This API may change in the future.
+type OtelMeter struct {
+	provider metric.MeterProvider
+	wrapped  metric.Meter
+
+	histograms sync.Map
+	counters   sync.Map
+}
+
+// NewOtelMeter creates a new OtelMeter publishing to provider.
+// UNCOMMITTED: This API may change in the future.
+func NewOtelMeter(provider metric.MeterProvider) *OtelMeter {
+	return &OtelMeter{
+		provider: provider,
+		wrapped:  provider.Meter(otelMeterInstrumentationName, metric.WithInstrumentationVersion(Version())),
+	}
+}
+
+// Wrapped returns the OpenTelemetry meter that metrics are published to.
+func (om *OtelMeter) Wrapped() metric.Meter {
+	return om.wrapped
+}
+
+// Provider returns the OpenTelemetry meter provider that the meter was created from.
+func (om *OtelMeter) Provider() metric.MeterProvider {
+	return om.provider
+}
+
+// Counter returns a counter which increments the OpenTelemetry counter with the given name.
+func (om *OtelMeter) Counter(name string, tags map[string]string) (Counter, error) {
+	counter, ok := om.counters.Load(name)
+	if !ok {
+		newCounter, err := om.wrapped.Int64Counter(name)
+		if err != nil {
+			return nil, err
+		}
+
+		// It doesn't matter if the instrument is created multiple times, the OpenTelemetry meter returns the same
+		// underlying instrument for the same name.
+		counter, _ = om.counters.LoadOrStore(name, newCounter)
+	}
+
+	return &otelCounter{
+		counter: counter.(metric.Int64Counter),
+		opts:    otelMeterAttributes(tags),
+	}, nil
+}
+
+// ValueRecorder returns a recorder which records values to the OpenTelemetry histogram with the given name.
+func (om *OtelMeter) ValueRecorder(name string, tags map[string]string) (ValueRecorder, error) {
+	histogram, ok := om.histograms.Load(name)
+	if !ok {
+		newHistogram, err := om.wrapped.Int64Histogram(name, metric.WithUnit("us"))
+		if err != nil {
+			return nil, err
+		}
+
+		histogram, _ = om.histograms.LoadOrStore(name, newHistogram)
+	}
+
+	return &otelValueRecorder{
+		histogram: histogram.(metric.Int64Histogram),
+		opts:      otelMeterAttributes(tags),
+	}, nil
+}
+
+func otelMeterAttributes(tags map[string]string) metric.MeasurementOption {
+	attribs := make([]attribute.KeyValue, 0, len(tags))
+	for k, v := range tags {
+		attribs = append(attribs, attribute.String(k, v))
+	}
+
+	return metric.WithAttributes(attribs...)
+}
+
+type otelCounter struct {
+	counter metric.Int64Counter
+	opts    metric.MeasurementOption
+}
+
+func (oc *otelCounter) IncrementBy(num uint64) {
+	oc.counter.Add(context.Background(), int64(num), oc.opts)
+}
+
+type otelValueRecorder struct {
+	histogram metric.Int64Histogram
+	opts      metric.MeasurementOption
+}
+
+func (ovr *otelValueRecorder) RecordValue(val uint64) {
+	ovr.histogram.Record(context.Background(), int64(val), ovr.opts)
+}
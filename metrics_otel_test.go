@@ -0,0 +1,210 @@
+package gocb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+type testOtelMeasurement struct {
+	name  string
+	value int64
+	attrs attribute.Set
+}
+
+type testOtelMeterProvider struct {
+	noop.MeterProvider
+	meter *testOtelMeter
+}
+
+func (p *testOtelMeterProvider) Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	p.meter.name = name
+	p.meter.version = metric.NewMeterConfig(opts...).InstrumentationVersion()
+	return p.meter
+}
+
+type testOtelMeter struct {
+	noop.Meter
+	name    string
+	version string
+
+	lock        sync.Mutex
+	units       map[string]string
+	records     []testOtelMeasurement
+	counterAdds []testOtelMeasurement
+	instruments int
+}
+
+func (m *testOtelMeter) Int64Histogram(name string, opts ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	m.lock.Lock()
+	m.instruments++
+	m.units[name] = metric.NewInt64HistogramConfig(opts...).Unit()
+	m.lock.Unlock()
+	return &testOtelHistogram{meter: m, name: name}, nil
+}
+
+func (m *testOtelMeter) Int64Counter(name string, opts ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	m.lock.Lock()
+	m.instruments++
+	m.lock.Unlock()
+	return &testOtelCounter{meter: m, name: name}, nil
+}
+
+type testOtelHistogram struct {
+	noop.Int64Histogram
+	meter *testOtelMeter
+	name  string
+}
+
+func (h *testOtelHistogram) Record(_ context.Context, value int64, opts ...metric.RecordOption) {
+	h.meter.lock.Lock()
+	h.meter.records = append(h.meter.records, testOtelMeasurement{
+		name:  h.name,
+		value: value,
+		attrs: metric.NewRecordConfig(opts).Attributes(),
+	})
+	h.meter.lock.Unlock()
+}
+
+type testOtelCounter struct {
+	noop.Int64Counter
+	meter *testOtelMeter
+	name  string
+}
+
+func (c *testOtelCounter) Add(_ context.Context, value int64, opts ...metric.AddOption) {
+	c.meter.lock.Lock()
+	c.meter.counterAdds = append(c.meter.counterAdds, testOtelMeasurement{
+		name:  c.name,
+		value: value,
+		attrs: metric.NewAddConfig(opts).Attributes(),
+	})
+	c.meter.lock.Unlock()
+}
+
+func newTestOtelMeterProvider() *testOtelMeterProvider {
+	return &testOtelMeterProvider{
+		meter: &testOtelMeter{
+			units: make(map[string]string),
+		},
+	}
+}
+
+func (suite *UnitTestSuite) TestOtelMeterOperations() {
+	provider := newTestOtelMeterProvider()
+	meter := NewOtelMeter(provider)
+
+	suite.Assert().Equal(provider, meter.Provider())
+	suite.Assert().Equal(otelMeterInstrumentationName, provider.meter.name)
+	suite.Assert().Equal(Version(), provider.meter.version)
+
+	mw := newMeterWrapper(meter)
+	mw.ValueRecord(serviceValueKV, "get", time.Now().Add(-2*time.Millisecond), &keyspace{
+		bucketName:     "default",
+		scopeName:      "_default",
+		collectionName: "_default",
+	}, nil)
+	mw.ValueRecord(serviceValueQuery, "query", time.Now(), nil, ErrTimeout)
+
+	otelMeter := provider.meter
+	suite.Require().Len(otelMeter.records, 2)
+	suite.Assert().Equal("us", otelMeter.units[meterNameCBOperations])
+	// The histogram is only created once.
+	suite.Assert().Equal(2, otelMeter.instruments)
+
+	kvRecord := otelMeter.records[0]
+	suite.Assert().Equal(meterNameCBOperations, kvRecord.name)
+	suite.Assert().GreaterOrEqual(kvRecord.value, int64(2000))
+	suite.Assert().Equal(attribute.NewSet(
+		attribute.String(meterAttribServiceKey, serviceValueKV),
+		attribute.String(meterAttribOperationKey, "get"),
+		attribute.String(meterAttribOutcomeKey, "Success"),
+		attribute.String(meterAttribBucketNameKey, "default"),
+		attribute.String(meterAttribScopeNameKey, "_default"),
+		attribute.String(meterAttribCollectionNameKey, "_default"),
+	), kvRecord.attrs)
+
+	queryRecord := otelMeter.records[1]
+	suite.Assert().Equal(meterNameCBOperations, queryRecord.name)
+	suite.Assert().Equal(attribute.NewSet(
+		attribute.String(meterAttribServiceKey, serviceValueQuery),
+		attribute.String(meterAttribOperationKey, "query"),
+		attribute.String(meterAttribOutcomeKey, "Timeout"),
+	), queryRecord.attrs)
+
+	suite.Require().Len(otelMeter.counterAdds, 1)
+	suite.Assert().Equal(meterNameCBOperationsFailed, otelMeter.counterAdds[0].name)
+	suite.Assert().Equal(int64(1), otelMeter.counterAdds[0].value)
+}
+
+func (suite *UnitTestSuite) TestOtelMeterCoreMetrics() {
+	provider := newTestOtelMeterProvider()
+
+	mgr := &stdConnectionMgr{meter: newMeterWrapper(NewOtelMeter(provider))}
+	coreMeter := mgr.coreMeter()
+	suite.Require().NotNil(coreMeter)
+
+	recorder, err := coreMeter.ValueRecorder(meterNameCBOperations, map[string]string{
+		meterAttribServiceKey:   serviceValueKV,
+		meterAttribOperationKey: "Get",
+	})
+	suite.Require().Nil(err, err)
+	recorder.RecordValue(150)
+
+	suite.Require().Len(provider.meter.records, 1)
+	suite.Assert().Equal(meterNameCBOperationsDispatch, provider.meter.records[0].name)
+	suite.Assert().Equal(int64(150), provider.meter.records[0].value)
+
+	mgr = &stdConnectionMgr{meter: newMeterWrapper(&NoopMeter{})}
+	suite.Assert().Nil(mgr.coreMeter())
+
+	loggingMeter := NewLoggingMeter(nil)
+	defer loggingMeter.close()
+	mgr = &stdConnectionMgr{meter: newMeterWrapper(loggingMeter)}
+	suite.Assert().Nil(mgr.coreMeter())
+
+	// Custom meters receive gocbcore's metrics as well.
+	meter := newTestMeter()
+	mgr = &stdConnectionMgr{meter: newMeterWrapper(meter)}
+	coreMeter = mgr.coreMeter()
+	suite.Require().NotNil(coreMeter)
+
+	recorder, err = coreMeter.ValueRecorder(meterNameCBOperations, map[string]string{
+		meterAttribServiceKey:   serviceValueKV,
+		meterAttribOperationKey: "Get",
+	})
+	suite.Require().Nil(err, err)
+	recorder.RecordValue(150)
+	suite.Assert().Contains(meter.recorders, makeMetricsKey(meterNameCBOperationsDispatch, serviceValueKV, "Get"))
+}
+
+func (suite *UnitTestSuite) TestOtelMeterInstrumentError() {
+	provider := &testOtelMeterProvider{meter: &testOtelMeter{units: make(map[string]string)}}
+	meter := &OtelMeter{
+		provider: provider,
+		wrapped:  &testOtelErrMeter{},
+	}
+
+	_, err := meter.ValueRecorder(meterNameCBOperations, nil)
+	suite.Assert().Error(err)
+
+	_, err = meter.Counter(meterNameCBOperationsFailed, nil)
+	suite.Assert().Error(err)
+}
+
+type testOtelErrMeter struct {
+	noop.Meter
+}
+
+func (m *testOtelErrMeter) Int64Histogram(string, ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	return nil, errors.New("histogram unavailable")
+}
+
+func (m *testOtelErrMeter) Int64Counter(string, ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return nil, errors.New("counter unavailable")
+}
@@ -23,6 +23,9 @@ type mgmtRequest struct {
 	Timeout       time.Duration
 	RetryStrategy RetryStrategy
 
+	// Deadline takes precedence over Timeout when set, allowing several requests to share a single deadline.
+	Deadline time.Time
+
 	parentSpanCtx RequestSpanContext
 }
 
@@ -43,9 +46,13 @@ type mgmtProviderCore struct {
 }
 
 func (mpc *mgmtProviderCore) executeMgmtRequest(ctx context.Context, req mgmtRequest) (mgmtRespOut *mgmtResponse, errOut error) {
-	timeout := req.Timeout
-	if timeout == 0 {
-		timeout = mpc.mgmtTimeout
+	deadline := req.Deadline
+	if deadline.IsZero() {
+		timeout := req.Timeout
+		if timeout == 0 {
+			timeout = mpc.mgmtTimeout
+		}
+		deadline = time.Now().Add(timeout)
 	}
 
 	retryStrategy := mpc.retryStrategyWrapper
@@ -62,7 +69,7 @@ func (mpc *mgmtProviderCore) executeMgmtRequest(ctx context.Context, req mgmtReq
 		ContentType:   req.ContentType,
 		IsIdempotent:  req.IsIdempotent,
 		UniqueID:      req.UniqueID,
-		Deadline:      time.Now().Add(timeout),
+		Deadline:      deadline,
 		RetryStrategy: retryStrategy,
 		TraceContext:  req.parentSpanCtx,
 		Endpoint:      req.Endpoint,
@@ -0,0 +1,263 @@
+package gocb
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type jsonNodeAlternateAddress struct {
+	Hostname string         `json:"hostname"`
+	Ports    map[string]int `json:"ports"`
+}
+
+type jsonNodeExt struct {
+	Hostname           string                              `json:"hostname"`
+	Services           map[string]int                      `json:"services"`
+	AlternateAddresses map[string]jsonNodeAlternateAddress `json:"alternateAddresses"`
+}
+
+type jsonNodeServices struct {
+	NodesExt []jsonNodeExt `json:"nodesExt"`
+}
+
+// defaultNetwork is the network of the addresses which nodes know themselves by, rather than an alternate address.
+const defaultNetwork = "default"
+
+// address returns the host and the named port of the node on a network. The hostname is omitted for a single node
+// cluster, in which case defaultHost is used.
+func (node *jsonNodeExt) address(network, portName, defaultHost string) (string, int, bool) {
+	host := node.Hostname
+	if host == "" {
+		host = defaultHost
+	}
+	port, ok := node.Services[portName]
+
+	if network != defaultNetwork {
+		alt, hasAlt := node.AlternateAddresses[network]
+		if !hasAlt {
+			return "", 0, false
+		}
+		if alt.Hostname != "" {
+			host = alt.Hostname
+		}
+		// Alternate addresses only list the ports which are remapped.
+		if altPort, hasPort := alt.Ports[portName]; hasPort {
+			port, ok = altPort, true
+		}
+	}
+
+	return strings.Trim(host, "[]"), port, ok
+}
+
+// network returns the network which the SDK is connected to the cluster with, which is the network of the management
+// endpoint that a request was dispatched to as that was chosen from the SDK's current config.
+func (ns *jsonNodeServices) network(endpoint *url.URL) string {
+	mgmtPort := "mgmt"
+	if endpoint.Scheme == "https" {
+		mgmtPort = "mgmtSSL"
+	}
+
+	networks := []string{defaultNetwork}
+	for _, node := range ns.NodesExt {
+		for network := range node.AlternateAddresses {
+			networks = append(networks, network)
+		}
+	}
+
+	for _, network := range networks {
+		for _, node := range ns.NodesExt {
+			host, port, ok := node.address(network, mgmtPort, endpoint.Hostname())
+			if ok && host == endpoint.Hostname() && strconv.Itoa(port) == endpoint.Port() {
+				return network
+			}
+		}
+	}
+
+	return defaultNetwork
+}
+
+// servicePortNames maps a service to the names that /pools/default/nodeServices uses for its plain and TLS ports.
+var servicePortNames = map[ServiceType][2]string{
+	ServiceTypeSearch: {"fts", "ftsSSL"},
+	serviceTypeIndex:  {"indexHttp", "indexHttps"},
+}
+
+// serviceNodeEndpoints returns the endpoint of every node running a service, using the same scheme and network that
+// the SDK is connected to the cluster with. Some service endpoints, such as those reporting statistics, only report on
+// the node that receives the request and so must be sent to each of these endpoints.
+func serviceNodeEndpoints(ctx context.Context, provider mgmtProvider, serviceReq mgmtRequest) ([]string, error) {
+	ports, ok := servicePortNames[serviceReq.Service]
+	if !ok {
+		return nil, makeInvalidArgumentsError("service does not support per node requests")
+	}
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "GET",
+		Path:          "/pools/default/nodeServices",
+		IsIdempotent:  true,
+		RetryStrategy: serviceReq.RetryStrategy,
+		Timeout:       serviceReq.Timeout,
+		Deadline:      serviceReq.Deadline,
+		UniqueID:      uuid.New().String(),
+		parentSpanCtx: serviceReq.parentSpanCtx,
+	}
+	resp, err := provider.executeMgmtRequest(ctx, req)
+	if err != nil {
+		return nil, makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, makeMgmtBadStatusError("failed to get node services", &req, resp)
+	}
+
+	var nodeServices jsonNodeServices
+	jsonDec := json.NewDecoder(resp.Body)
+	err = jsonDec.Decode(&nodeServices)
+	if err != nil {
+		return nil, err
+	}
+
+	respURL, err := url.Parse(resp.Endpoint)
+	if err != nil {
+		return nil, wrapError(err, "failed to parse management endpoint")
+	}
+
+	scheme, port := "http", ports[0]
+	if respURL.Scheme == "https" {
+		scheme, port = "https", ports[1]
+	}
+	network := nodeServices.network(respURL)
+
+	var endpoints []string
+	for _, node := range nodeServices.NodesExt {
+		host, servicePort, ok := node.address(network, port, respURL.Hostname())
+		if !ok {
+			continue
+		}
+
+		endpoints = append(endpoints, scheme+"://"+net.JoinHostPort(host, strconv.Itoa(servicePort)))
+	}
+
+	if len(endpoints) == 0 {
+		return nil, makeGenericMgmtError(ErrServiceNotAvailable, &req, resp, "no nodes are running the service")
+	}
+
+	return endpoints, nil
+}
+
+// forEachServiceNode sends a request to every node running the request's service concurrently, passing each response
+// to handle. The first error, whether from a request or from handle, is returned. The request's timeout is shared by
+// every request made, so callers should set it rather than relying on the default timeout of each request.
+func forEachServiceNode(ctx context.Context, provider mgmtProvider, req mgmtRequest,
+	handle func(req *mgmtRequest, resp *mgmtResponse) error) error {
+	// The timeout applies to the request as a whole, rather than to each of the requests it is made up of.
+	if req.Deadline.IsZero() && req.Timeout > 0 {
+		req.Deadline = time.Now().Add(req.Timeout)
+	}
+
+	endpoints, err := serviceNodeEndpoints(ctx, provider, req)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		lock     sync.Mutex
+		firstErr error
+	)
+	for _, endpoint := range endpoints {
+		nodeReq := req
+		nodeReq.Endpoint = endpoint
+		nodeReq.UniqueID = uuid.New().String()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := func() error {
+				resp, err := provider.executeMgmtRequest(ctx, nodeReq)
+				if err != nil {
+					return err
+				}
+				defer ensureBodyClosed(resp.Body)
+
+				lock.Lock()
+				defer lock.Unlock()
+				return handle(&nodeReq, resp)
+			}()
+			if err != nil {
+				lock.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// gaugeStatWords are the words which identify a statistic as a gauge, such as an average or a percentage, rather than
+// a counter. Gauges cannot be summed across nodes.
+var gaugeStatWords = map[string]struct{}{
+	"avg":       {},
+	"average":   {},
+	"percent":   {},
+	"pct":       {},
+	"ratio":     {},
+	"rate":      {},
+	"latency":   {},
+	"last":      {},
+	"timestamp": {},
+	"max":       {},
+	"min":       {},
+}
+
+// isCounterStat returns whether a numeric statistic is a counter, which can be summed across nodes.
+func isCounterStat(key string) bool {
+	words := strings.FieldsFunc(strings.ToLower(key), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+	for _, word := range words {
+		if _, ok := gaugeStatWords[word]; ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mergeNodeStats adds the statistics reported by one node into those of the cluster. Counters are summed, nested
+// statistics are merged, and for any other value, including gauges such as averages, the first reported is kept.
+func mergeNodeStats(dst, src map[string]interface{}) {
+	for key, value := range src {
+		existing, ok := dst[key]
+		if !ok {
+			dst[key] = value
+			continue
+		}
+
+		switch existingValue := existing.(type) {
+		case float64:
+			if srcValue, ok := value.(float64); ok && isCounterStat(key) {
+				dst[key] = existingValue + srcValue
+			}
+		case map[string]interface{}:
+			if srcValue, ok := value.(map[string]interface{}); ok {
+				mergeNodeStats(existingValue, srcValue)
+			}
+		}
+	}
+}
@@ -196,6 +196,36 @@ func (_m *mockConnectionManager) getBucketManagementProvider() (bucketManagement
 	return r0, r1
 }
 
+// getClusterSettingsProvider provides a mock function with given fields:
+func (_m *mockConnectionManager) getClusterSettingsProvider() (clusterSettingsProvider, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for getClusterSettingsProvider")
+	}
+
+	var r0 clusterSettingsProvider
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (clusterSettingsProvider, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() clusterSettingsProvider); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(clusterSettingsProvider)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // getCollectionsManagementProvider provides a mock function with given fields: bucketName
 func (_m *mockConnectionManager) getCollectionsManagementProvider(bucketName string) (collectionsManagementProvider, error) {
 	ret := _m.Called(bucketName)
@@ -456,6 +486,36 @@ func (_m *mockConnectionManager) getMeter() *meterWrapper {
 	return r0
 }
 
+// getNodeManagementProvider provides a mock function with given fields:
+func (_m *mockConnectionManager) getNodeManagementProvider() (nodeManagementProvider, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for getNodeManagementProvider")
+	}
+
+	var r0 nodeManagementProvider
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (nodeManagementProvider, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() nodeManagementProvider); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(nodeManagementProvider)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // getQueryIndexProvider provides a mock function with given fields:
 func (_m *mockConnectionManager) getQueryIndexProvider() (queryIndexProvider, error) {
 	ret := _m.Called()
@@ -666,6 +726,66 @@ func (_m *mockConnectionManager) getUserManagerProvider() (userManagerProvider,
 	return r0, r1
 }
 
+// getLogCollectionProvider provides a mock function with given fields:
+func (_m *mockConnectionManager) getLogCollectionProvider() (logCollectionProvider, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for getLogCollectionProvider")
+	}
+
+	var r0 logCollectionProvider
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (logCollectionProvider, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() logCollectionProvider); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(logCollectionProvider)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// getSecurityManagementProvider provides a mock function with given fields:
+func (_m *mockConnectionManager) getSecurityManagementProvider() (securityManagementProvider, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for getSecurityManagementProvider")
+	}
+
+	var r0 securityManagementProvider
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (securityManagementProvider, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() securityManagementProvider); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(securityManagementProvider)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // getViewIndexProvider provides a mock function with given fields: bucketName
 func (_m *mockConnectionManager) getViewIndexProvider(bucketName string) (viewIndexProvider, error) {
 	ret := _m.Called(bucketName)
@@ -756,6 +876,36 @@ func (_m *mockConnectionManager) getWaitUntilReadyProvider(bucketName string) (w
 	return r0, r1
 }
 
+// getXDCRManagementProvider provides a mock function with given fields:
+func (_m *mockConnectionManager) getXDCRManagementProvider() (xdcrManagementProvider, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for getXDCRManagementProvider")
+	}
+
+	var r0 xdcrManagementProvider
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (xdcrManagementProvider, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() xdcrManagementProvider); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(xdcrManagementProvider)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // initTransactions provides a mock function with given fields: config, cluster
 func (_m *mockConnectionManager) initTransactions(config TransactionsConfig, cluster *Cluster) error {
 	ret := _m.Called(config, cluster)
@@ -9,6 +9,36 @@ type mockViewProvider struct {
 	mock.Mock
 }
 
+// SpatialViewQuery provides a mock function with given fields: designDoc, viewName, opts
+func (_m *mockViewProvider) SpatialViewQuery(designDoc string, viewName string, opts *SpatialViewOptions) (*ViewResult, error) {
+	ret := _m.Called(designDoc, viewName, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SpatialViewQuery")
+	}
+
+	var r0 *ViewResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, *SpatialViewOptions) (*ViewResult, error)); ok {
+		return rf(designDoc, viewName, opts)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, *SpatialViewOptions) *ViewResult); ok {
+		r0 = rf(designDoc, viewName, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ViewResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, *SpatialViewOptions) error); ok {
+		r1 = rf(designDoc, viewName, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ViewQuery provides a mock function with given fields: designDoc, viewName, opts
 func (_m *mockViewProvider) ViewQuery(designDoc string, viewName string, opts *ViewOptions) (*ViewResult, error) {
 	ret := _m.Called(designDoc, viewName, opts)
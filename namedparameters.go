@@ -0,0 +1,133 @@
+package gocb
+
+import (
+	"reflect"
+	"strings"
+)
+
+// NamedParametersFromStruct converts a struct, or pointer to a struct, into a map of named parameters suitable for
+// use with QueryOptions.NamedParameters and AnalyticsOptions.NamedParameters. Parameter names are taken from json
+// tags following the same rules as encoding/json: fields tagged "-" and unexported fields are skipped, omitempty is
+// respected and the fields of embedded structs are promoted. Field values are not converted and are serialized as
+// part of the request in the usual way.
+// UNCOMMITTED: This API may change in the future.
+func NamedParametersFromStruct(value interface{}) (map[string]interface{}, error) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, makeInvalidArgumentsError("named parameters value cannot be nil")
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil, makeInvalidArgumentsError("named parameters value must be a struct, got " + v.Kind().String())
+	}
+
+	params := make(map[string]interface{})
+
+	// Structs are walked breadth first so that, as with encoding/json, shallower fields take precedence over fields
+	// promoted from deeper embedded structs. Embedded pointers are tracked so that cyclic embedding cannot loop.
+	visited := make(map[uintptr]struct{})
+	queue := []reflect.Value{v}
+	for len(queue) > 0 {
+		var next []reflect.Value
+		for _, sv := range queue {
+			next = append(next, namedParametersFromStructLevel(sv, params, visited)...)
+		}
+		queue = next
+	}
+
+	return params, nil
+}
+
+// namedParametersFromStructLevel adds the direct fields of a struct to params, returning any embedded structs which
+// need to be walked at the next depth.
+func namedParametersFromStructLevel(v reflect.Value, params map[string]interface{},
+	visited map[uintptr]struct{}) []reflect.Value {
+	var embedded []reflect.Value
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitEmpty, skip := parseNamedParameterTag(field.Tag.Get("json"))
+		if skip {
+			continue
+		}
+
+		fv := v.Field(i)
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if fv.Kind() == reflect.Ptr {
+					if fv.IsNil() {
+						continue
+					}
+					if _, ok := visited[fv.Pointer()]; ok {
+						continue
+					}
+					visited[fv.Pointer()] = struct{}{}
+					fv = fv.Elem()
+				}
+				embedded = append(embedded, fv)
+				continue
+			}
+		}
+
+		if !field.IsExported() || !fv.CanInterface() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		if omitEmpty && isEmptyNamedParameterValue(fv) {
+			continue
+		}
+
+		if _, ok := params[name]; ok {
+			continue
+		}
+		params[name] = fv.Interface()
+	}
+
+	return embedded
+}
+
+func parseNamedParameterTag(tag string) (name string, omitEmpty, skip bool) {
+	if tag == "-" {
+		return "", false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return parts[0], omitEmpty, false
+}
+
+func isEmptyNamedParameterValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+
+	return false
+}
@@ -0,0 +1,67 @@
+package gocb
+
+import "errors"
+
+type testNamedParamsBase struct {
+	Country string `json:"country"`
+	City    string `json:"city"`
+}
+
+type testNamedParamsCyclic struct {
+	*testNamedParamsCyclic
+	Depth int `json:"depth"`
+}
+
+type testNamedParams struct {
+	testNamedParamsBase
+	City     string   `json:"city"`
+	Name     string   `json:"name"`
+	Tags     []string `json:"tags,omitempty"`
+	Ignored  string   `json:"-"`
+	Untagged int
+	private  string
+}
+
+func (suite *UnitTestSuite) TestNamedParametersFromStruct() {
+	params, err := NamedParametersFromStruct(&testNamedParams{
+		testNamedParamsBase: testNamedParamsBase{
+			Country: "uk",
+			City:    "manchester",
+		},
+		City:     "london",
+		Name:     "brewery",
+		Ignored:  "ignored",
+		Untagged: 5,
+		private:  "private",
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(map[string]interface{}{
+		"country":  "uk",
+		"city":     "london",
+		"name":     "brewery",
+		"Untagged": 5,
+	}, params)
+}
+
+func (suite *UnitTestSuite) TestNamedParametersFromStructCyclic() {
+	val := &testNamedParamsCyclic{Depth: 1}
+	val.testNamedParamsCyclic = val
+
+	params, err := NamedParametersFromStruct(val)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(map[string]interface{}{"depth": 1}, params)
+}
+
+func (suite *UnitTestSuite) TestNamedParametersFromStructInvalid() {
+	_, err := NamedParametersFromStruct(nil)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+
+	var val *testNamedParams
+	_, err = NamedParametersFromStruct(val)
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+
+	_, err = NamedParametersFromStruct(map[string]interface{}{"a": 1})
+	suite.Assert().True(errors.Is(err, ErrInvalidArgument))
+}
@@ -0,0 +1,10 @@
+package gocb
+
+type nodeManagementProvider interface {
+	ListNodes(opts *ListNodesOptions) ([]Node, error)
+	Failover(hostname string, opts *FailoverNodeOptions) error
+	GracefulFailover(hostname string, opts *GracefulFailoverNodeOptions) error
+	Recover(hostname string, recoveryType NodeRecoveryType, opts *RecoverNodeOptions) error
+	Rebalance(opts *RebalanceOptions) error
+	GetRebalanceProgress(opts *RebalanceProgressOptions) (*RebalanceProgress, error)
+}
@@ -0,0 +1,368 @@
+package gocb
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type nodeManagementProviderCore struct {
+	provider mgmtProvider
+
+	tracer *tracerWrapper
+}
+
+type nodeRequestOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+	Context       context.Context
+}
+
+type jsonNode struct {
+	Hostname          string   `json:"hostname"`
+	OTPNode           string   `json:"otpNode"`
+	Services          []string `json:"services"`
+	Status            string   `json:"status"`
+	ClusterMembership string   `json:"clusterMembership"`
+	Version           string   `json:"version"`
+	RecoveryType      string   `json:"recoveryType"`
+	Ports             struct {
+		HTTPSMgmt int `json:"httpsMgmt"`
+	} `json:"ports"`
+	AlternateAddresses map[string]jsonNodeAlternateAddress `json:"alternateAddresses"`
+}
+
+// ext returns the node in the form used by /pools/default/nodeServices, so that its management address can be
+// resolved on the network that the SDK is connected to the cluster with.
+func (node *jsonNode) ext() (jsonNodeExt, error) {
+	host, port, err := net.SplitHostPort(node.Hostname)
+	if err != nil {
+		return jsonNodeExt{}, wrapError(err, "failed to parse node hostname")
+	}
+	mgmtPort, err := strconv.Atoi(port)
+	if err != nil {
+		return jsonNodeExt{}, wrapError(err, "failed to parse node hostname")
+	}
+
+	services := map[string]int{"mgmt": mgmtPort}
+	if node.Ports.HTTPSMgmt > 0 {
+		services["mgmtSSL"] = node.Ports.HTTPSMgmt
+	}
+
+	return jsonNodeExt{
+		Hostname:           host,
+		Services:           services,
+		AlternateAddresses: node.AlternateAddresses,
+	}, nil
+}
+
+type jsonNodesPool struct {
+	Nodes []jsonNode `json:"nodes"`
+}
+
+type jsonRebalanceTask struct {
+	Type          string  `json:"type"`
+	Status        string  `json:"status"`
+	StatusIsStale bool    `json:"statusIsStale"`
+	Progress      float64 `json:"progress"`
+	ErrorMessage  string  `json:"errorMessage"`
+	RebalanceID   string  `json:"rebalanceId"`
+}
+
+func (nm *nodeManagementProviderCore) ListNodes(opts *ListNodesOptions) ([]Node, error) {
+	nodesData, err := nm.getNodes(nodeRequestOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]Node, len(nodesData))
+	for i, nodeData := range nodesData {
+		nodes[i] = Node{
+			Hostname:   nodeData.Hostname,
+			Services:   nodeData.Services,
+			Status:     NodeStatus(nodeData.Status),
+			Membership: NodeMembership(nodeData.ClusterMembership),
+			Version:    nodeData.Version,
+		}
+		// The server reports a recovery type of none until one has been set.
+		if nodeData.RecoveryType != "none" {
+			nodes[i].RecoveryType = NodeRecoveryType(nodeData.RecoveryType)
+		}
+	}
+
+	return nodes, nil
+}
+
+func (nm *nodeManagementProviderCore) Failover(hostname string, opts *FailoverNodeOptions) error {
+	reqOpts := nodeRequestOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	}
+
+	otpNode, err := nm.lookupOTPNode(hostname, reqOpts)
+	if err != nil {
+		return err
+	}
+
+	reqForm := make(url.Values)
+	reqForm.Add("otpNode", otpNode)
+	if opts.AllowUnsafe {
+		reqForm.Add("allowUnsafe", "true")
+	}
+
+	return nm.doRequest("failover", "/controller/failOver", reqForm, reqOpts)
+}
+
+func (nm *nodeManagementProviderCore) GracefulFailover(hostname string, opts *GracefulFailoverNodeOptions) error {
+	reqOpts := nodeRequestOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	}
+
+	otpNode, err := nm.lookupOTPNode(hostname, reqOpts)
+	if err != nil {
+		return err
+	}
+
+	reqForm := make(url.Values)
+	reqForm.Add("otpNode", otpNode)
+
+	return nm.doRequest("graceful_failover", "/controller/startGracefulFailover", reqForm, reqOpts)
+}
+
+func (nm *nodeManagementProviderCore) Recover(hostname string, recoveryType NodeRecoveryType, opts *RecoverNodeOptions) error {
+	reqOpts := nodeRequestOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	}
+
+	otpNode, err := nm.lookupOTPNode(hostname, reqOpts)
+	if err != nil {
+		return err
+	}
+
+	reqForm := make(url.Values)
+	reqForm.Add("otpNode", otpNode)
+	reqForm.Add("recoveryType", string(recoveryType))
+
+	return nm.doRequest("recover", "/controller/setRecoveryType", reqForm, reqOpts)
+}
+
+func (nm *nodeManagementProviderCore) Rebalance(opts *RebalanceOptions) error {
+	reqOpts := nodeRequestOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	}
+
+	nodesData, err := nm.getNodes(reqOpts)
+	if err != nil {
+		return err
+	}
+
+	// The server requires every node in the cluster to be listed, to guard against rebalancing with a stale view of
+	// the cluster.
+	knownNodes := make([]string, len(nodesData))
+	for i, nodeData := range nodesData {
+		knownNodes[i] = nodeData.OTPNode
+	}
+
+	ejectedNodes := make([]string, len(opts.EjectedNodes))
+	for i, hostname := range opts.EjectedNodes {
+		nodeData, err := findNode(nodesData, hostname)
+		if err != nil {
+			return err
+		}
+		ejectedNodes[i] = nodeData.OTPNode
+	}
+
+	reqForm := make(url.Values)
+	reqForm.Add("knownNodes", strings.Join(knownNodes, ","))
+	reqForm.Add("ejectedNodes", strings.Join(ejectedNodes, ","))
+
+	return nm.doRequest("rebalance", "/controller/rebalance", reqForm, reqOpts)
+}
+
+func (nm *nodeManagementProviderCore) GetRebalanceProgress(opts *RebalanceProgressOptions) (*RebalanceProgress, error) {
+	span := nm.tracer.createSpan(opts.ParentSpan, "manager_nodes_rebalance_progress", "management")
+	span.SetAttribute("db.operation", "GET /pools/default/tasks")
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "GET",
+		Path:          "/pools/default/tasks",
+		RetryStrategy: opts.RetryStrategy,
+		IsIdempotent:  true,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := nm.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return nil, makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, makeMgmtBadStatusError("failed to get rebalance progress", &req, resp)
+	}
+
+	var tasksData []jsonRebalanceTask
+	jsonDec := json.NewDecoder(resp.Body)
+	err = jsonDec.Decode(&tasksData)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &RebalanceProgress{}
+	for _, taskData := range tasksData {
+		if taskData.Type != "rebalance" {
+			continue
+		}
+
+		// A stale status means the orchestrator could not be reached, so the rebalance cannot be assumed to be done.
+		progress.Running = taskData.Status == "running" || taskData.StatusIsStale
+		progress.Progress = taskData.Progress
+		progress.ErrorMessage = taskData.ErrorMessage
+		progress.ID = taskData.RebalanceID
+		if !progress.Running && progress.ErrorMessage == "" {
+			progress.Progress = 100
+		}
+	}
+
+	return progress, nil
+}
+
+func (nm *nodeManagementProviderCore) getNodes(opts nodeRequestOptions) ([]jsonNode, error) {
+	nodesData, _, err := getPoolNodes(nm.provider, nm.tracer, opts)
+	return nodesData, err
+}
+
+// getPoolNodes returns the nodes of the cluster, along with the management endpoint which the request was sent to.
+func getPoolNodes(provider mgmtProvider, tracer *tracerWrapper, opts nodeRequestOptions) ([]jsonNode, string, error) {
+	span := tracer.createSpan(opts.ParentSpan, "manager_nodes_get_nodes", "management")
+	span.SetAttribute("db.operation", "GET /pools/default")
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "GET",
+		Path:          "/pools/default",
+		RetryStrategy: opts.RetryStrategy,
+		IsIdempotent:  true,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return nil, "", makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, "", makeMgmtBadStatusError("failed to get nodes", &req, resp)
+	}
+
+	var poolData jsonNodesPool
+	jsonDec := json.NewDecoder(resp.Body)
+	err = jsonDec.Decode(&poolData)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return poolData.Nodes, resp.Endpoint, nil
+}
+
+func (nm *nodeManagementProviderCore) lookupOTPNode(hostname string, opts nodeRequestOptions) (string, error) {
+	nodesData, err := nm.getNodes(opts)
+	if err != nil {
+		return "", err
+	}
+
+	nodeData, err := findNode(nodesData, hostname)
+	if err != nil {
+		return "", err
+	}
+
+	return nodeData.OTPNode, nil
+}
+
+// findNode returns the node with the given hostname, which can omit the management port where only one node runs on
+// the host.
+func findNode(nodesData []jsonNode, hostname string) (*jsonNode, error) {
+	var found *jsonNode
+	for i, nodeData := range nodesData {
+		if nodeData.Hostname == hostname {
+			return &nodesData[i], nil
+		}
+
+		host, _, err := net.SplitHostPort(nodeData.Hostname)
+		if err != nil || host != hostname {
+			continue
+		}
+		if found != nil {
+			return nil, makeInvalidArgumentsError("hostname " + hostname +
+				" matches more than one node, the management port of the node must be included")
+		}
+		found = &nodesData[i]
+	}
+
+	if found == nil {
+		return nil, wrapError(ErrNodeNotFound, hostname)
+	}
+
+	return found, nil
+}
+
+func (nm *nodeManagementProviderCore) doRequest(opName, path string, reqForm url.Values, opts nodeRequestOptions) error {
+	span := nm.tracer.createSpan(opts.ParentSpan, "manager_nodes_"+opName, "management")
+	span.SetAttribute("db.operation", "POST "+path)
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "POST",
+		Path:          path,
+		Body:          []byte(reqForm.Encode()),
+		ContentType:   "application/x-www-form-urlencoded",
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := nm.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return makeMgmtBadStatusError("failed to "+strings.ReplaceAll(opName, "_", " "), &req, resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package gocb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PollUntilOptions are the options available to PollUntil.
+type PollUntilOptions struct {
+	// Timeout is the maximum amount of time to poll for. If the Context passed to PollUntil has a deadline then the
+	// shorter of the two is used.
+	Timeout time.Duration
+
+	// RetryStrategy is used to calculate how long to wait between attempts. The strategy is called with
+	// PollConditionNotMetRetryReason. If not set then the interval passed to PollUntil is used, or a controlled
+	// backoff if that is also not set.
+	RetryStrategy RetryStrategy
+
+	// Tracer is used to create a span for the polling operation, and a child span for each attempt.
+	Tracer     RequestTracer
+	ParentSpan RequestSpan
+}
+
+type pollRetryReason struct{}
+
+func (rr pollRetryReason) AllowsNonIdempotentRetry() bool {
+	return true
+}
+
+func (rr pollRetryReason) AlwaysRetry() bool {
+	return false
+}
+
+func (rr pollRetryReason) Description() string {
+	return "POLL_CONDITION_NOT_MET"
+}
+
+func (rr pollRetryReason) String() string {
+	return rr.Description()
+}
+
+type pollRetryRequest struct {
+	attempts   uint32
+	identifier string
+	reasons    []RetryReason
+	strategy   RetryStrategy
+}
+
+func (r *pollRetryRequest) RetryAttempts() uint32 {
+	return r.attempts
+}
+
+func (r *pollRetryRequest) Identifier() string {
+	return r.identifier
+}
+
+func (r *pollRetryRequest) Idempotent() bool {
+	return true
+}
+
+func (r *pollRetryRequest) RetryReasons() []RetryReason {
+	return r.reasons
+}
+
+func (r *pollRetryRequest) retryStrategy() RetryStrategy {
+	return r.strategy
+}
+
+func (r *pollRetryRequest) recordRetryAttempt(reason RetryReason) {
+	r.attempts++
+	for _, existing := range r.reasons {
+		if existing == reason {
+			return
+		}
+	}
+	r.reasons = append(r.reasons, reason)
+}
+
+// PollUntil repeatedly calls fn until it reports that its condition has been met, it returns an error, or the
+// Context is cancelled or times out. This is useful when waiting for eventually consistent changes to propagate
+// across the cluster, such as DDL operations.
+// If fn returns an error then polling stops and the error is returned as is.
+// If the Context or Timeout expire before the condition is met then ErrUnambiguousTimeout is returned, if the
+// Context is cancelled then ErrRequestCanceled is returned.
+// UNCOMMITTED: This API may change in the future.
+func PollUntil(ctx context.Context, interval time.Duration, fn func(ctx context.Context) (bool, error), opts *PollUntilOptions) error {
+	if fn == nil {
+		return makeInvalidArgumentsError("poll function cannot be nil")
+	}
+	if opts == nil {
+		opts = &PollUntilOptions{}
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	strategy := opts.RetryStrategy
+	if strategy == nil {
+		if interval > 0 {
+			strategy = NewBestEffortRetryStrategy(func(retryAttempts uint32) time.Duration {
+				return interval
+			})
+		} else {
+			strategy = NewBestEffortRetryStrategy(nil)
+		}
+	}
+
+	tracer := opts.Tracer
+	if tracer == nil {
+		tracer = &NoopTracer{}
+	}
+	tw := newTracerWrapper(tracer)
+
+	span := tw.createSpan(opts.ParentSpan, "poll_until", "")
+	defer span.End()
+
+	start := time.Now()
+	req := &pollRetryRequest{
+		identifier: uuid.NewString(),
+		strategy:   strategy,
+	}
+
+	for {
+		attemptSpan := tw.createSpan(span, "poll_attempt", "")
+		done, err := fn(ctx)
+		attemptSpan.End()
+
+		span.SetAttribute(spanAttribNumRetries, req.RetryAttempts())
+
+		if err != nil {
+			return err
+		}
+
+		if done {
+			return nil
+		}
+
+		shouldRetry, retryTime := retryOrchMaybeRetry(req, PollConditionNotMetRetryReason)
+		if !shouldRetry {
+			return makeGenericError(ErrRequestCanceled, map[string]interface{}{
+				"reason": "retry strategy indicated that polling should stop",
+			})
+		}
+
+		select {
+		case <-time.After(time.Until(retryTime)):
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return &TimeoutError{
+					InnerError:    ErrUnambiguousTimeout,
+					OperationID:   "poll_until",
+					Opaque:        req.Identifier(),
+					TimeObserved:  time.Since(start),
+					RetryReasons:  req.RetryReasons(),
+					RetryAttempts: req.RetryAttempts(),
+				}
+			}
+
+			return makeGenericError(ErrRequestCanceled, nil)
+		}
+	}
+}
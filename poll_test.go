@@ -0,0 +1,108 @@
+package gocb
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type testPollRetryStrategy struct {
+	reasons  []RetryReason
+	attempts []uint32
+}
+
+func (rs *testPollRetryStrategy) RetryAfter(req RetryRequest, reason RetryReason) RetryAction {
+	rs.reasons = append(rs.reasons, reason)
+	rs.attempts = append(rs.attempts, req.RetryAttempts())
+	return &WithDurationRetryAction{WithDuration: time.Millisecond}
+}
+
+func (suite *UnitTestSuite) TestPollUntilConditionMet() {
+	var calls int
+	err := PollUntil(context.Background(), time.Millisecond, func(ctx context.Context) (bool, error) {
+		calls++
+		return calls == 3, nil
+	}, nil)
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal(3, calls)
+}
+
+func (suite *UnitTestSuite) TestPollUntilFunctionError() {
+	expectedErr := errors.New("something went wrong")
+
+	var calls int
+	err := PollUntil(context.Background(), time.Millisecond, func(ctx context.Context) (bool, error) {
+		calls++
+		if calls == 2 {
+			return false, expectedErr
+		}
+		return false, nil
+	}, nil)
+	suite.Require().ErrorIs(err, expectedErr)
+
+	suite.Assert().Equal(2, calls)
+}
+
+func (suite *UnitTestSuite) TestPollUntilTimeout() {
+	err := PollUntil(context.Background(), 5*time.Millisecond, func(ctx context.Context) (bool, error) {
+		return false, nil
+	}, &PollUntilOptions{
+		Timeout: 50 * time.Millisecond,
+	})
+	suite.Require().ErrorIs(err, ErrUnambiguousTimeout)
+
+	var tErr *TimeoutError
+	suite.Require().ErrorAs(err, &tErr)
+	suite.Assert().Equal([]RetryReason{PollConditionNotMetRetryReason}, tErr.RetryReasons)
+	suite.Assert().NotZero(tErr.RetryAttempts)
+}
+
+func (suite *UnitTestSuite) TestPollUntilContextCanceled() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := PollUntil(ctx, time.Second, func(ctx context.Context) (bool, error) {
+		cancel()
+		return false, nil
+	}, nil)
+	suite.Require().ErrorIs(err, ErrRequestCanceled)
+}
+
+func (suite *UnitTestSuite) TestPollUntilRetryStrategy() {
+	strategy := &testPollRetryStrategy{}
+
+	var calls int
+	err := PollUntil(context.Background(), time.Hour, func(ctx context.Context) (bool, error) {
+		calls++
+		return calls == 3, nil
+	}, &PollUntilOptions{
+		RetryStrategy: strategy,
+	})
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal([]RetryReason{PollConditionNotMetRetryReason, PollConditionNotMetRetryReason}, strategy.reasons)
+	suite.Assert().Equal([]uint32{0, 1}, strategy.attempts)
+}
+
+func (suite *UnitTestSuite) TestPollUntilSpans() {
+	tracer := newTestTracer()
+
+	var calls int
+	err := PollUntil(context.Background(), time.Millisecond, func(ctx context.Context) (bool, error) {
+		calls++
+		return calls == 2, nil
+	}, &PollUntilOptions{
+		Tracer: tracer,
+	})
+	suite.Require().NoError(err)
+
+	spans := tracer.GetSpans()
+	suite.Require().Contains(spans, nil)
+	suite.Require().Len(spans[nil], 1)
+
+	span := spans[nil][0]
+	suite.Assert().Equal("poll_until", span.Name)
+	suite.Assert().Equal(uint32(1), span.Tags[spanAttribNumRetries])
+	suite.Require().Contains(span.Spans, "poll_attempt")
+	suite.Assert().Len(span.Spans["poll_attempt"], 2)
+}
@@ -2,6 +2,7 @@ package gocb
 
 import (
 	"context"
+	"encoding/json"
 	"strconv"
 	"strings"
 	"time"
@@ -58,8 +59,9 @@ type QueryOptions struct {
 	// server. If not provided will be assigned a uuid value.
 	ClientContextID      string
 	PositionalParameters []interface{}
-	NamedParameters      map[string]interface{}
-	Metrics              bool
+	// NamedParameters can be built from a struct using NamedParametersFromStruct.
+	NamedParameters map[string]interface{}
+	Metrics         bool
 
 	// Raw provides a way to provide extra parameters in the request body for the query.
 	Raw map[string]interface{}
@@ -72,12 +74,15 @@ type QueryOptions struct {
 	FlexIndex bool
 
 	// PreserveExpiry tells the query engine to preserve expiration values set on any documents modified by this query.
+	// If the server does not support this option then a QueryParameterError wrapping ErrFeatureNotAvailable is returned.
 	PreserveExpiry bool
 
 	ParentSpan RequestSpan
 
 	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
 	// also applies to global level timeouts.
+	// Cancelling the Context whilst the results are being streamed will close the underlying stream, after which
+	// Err and Close on the QueryResult will return ErrRequestCanceled.
 	// UNCOMMITTED: This API may change in the future.
 	Context context.Context
 
@@ -90,8 +95,28 @@ type QueryOptions struct {
 	// This means that results could come from either active or replica nodes, depending on the state of the active node.
 	// If any of the results came from a replica node then a warning will be populated in the query metadata.
 	// If not set then this field is not sent in the query payload and the default setting on the cluster/node will be used.
+	// Requires server 7.6+, if the server does not support this option then a QueryParameterError wrapping
+	// ErrFeatureNotAvailable is returned.
 	UseReplica QueryUseReplicaLevel
 
+	// Serializer is used to serialize positional and named parameters, and to deserialize rows returned by
+	// QueryResult.Row and QueryResult.One. If not set then encoding/json is used.
+	// UNCOMMITTED: This API may change in the future.
+	Serializer JSONSerializer
+
+	// EndpointSelectionPolicy specifies how the endpoint used for this query is chosen, overriding the policy set in
+	// ClusterOptions.
+	// UNCOMMITTED: This API may change in the future.
+	EndpointSelectionPolicy EndpointSelectionPolicy
+
+	// Endpoint pins the query to a specific query node, taking precedence over EndpointSelectionPolicy. The endpoint
+	// must be given in the same form as returned by QueryResult.Endpoint, such as http://10.112.0.101:8093, and
+	// ErrInvalidArgument is returned if it is not a query endpoint within the cluster. Queries pinned to an endpoint
+	// are never served from the query cache.
+	// Not supported by the couchbase2 protocol.
+	// UNCOMMITTED: This API may change in the future.
+	Endpoint string
+
 	// Internal: This should never be used and is not supported.
 	Internal struct {
 		User     string
@@ -130,7 +155,19 @@ func (opts *QueryOptions) toMap() (map[string]interface{}, error) {
 	}
 
 	if opts.PositionalParameters != nil {
-		execOpts["args"] = opts.PositionalParameters
+		if opts.Serializer != nil {
+			args := make([]json.RawMessage, len(opts.PositionalParameters))
+			for i, value := range opts.PositionalParameters {
+				b, err := opts.Serializer.Serialize(value)
+				if err != nil {
+					return nil, err
+				}
+				args[i] = b
+			}
+			execOpts["args"] = args
+		} else {
+			execOpts["args"] = opts.PositionalParameters
+		}
 	}
 
 	if opts.NamedParameters != nil {
@@ -138,7 +175,15 @@ func (opts *QueryOptions) toMap() (map[string]interface{}, error) {
 			if !strings.HasPrefix(key, "$") {
 				key = "$" + key
 			}
-			execOpts[key] = value
+			if opts.Serializer != nil {
+				b, err := opts.Serializer.Serialize(value)
+				if err != nil {
+					return nil, err
+				}
+				execOpts[key] = json.RawMessage(b)
+			} else {
+				execOpts[key] = value
+			}
 		}
 	}
 
@@ -10,4 +10,5 @@ type queryIndexProvider interface {
 	GetAllIndexes(c *Collection, bucketName string, opts *GetAllQueryIndexesOptions) ([]QueryIndex, error)
 	BuildDeferredIndexes(c *Collection, bucketName string, opts *BuildDeferredQueryIndexOptions) ([]string, error)
 	WatchIndexes(c *Collection, bucketName string, watchList []string, timeout time.Duration, opts *WatchQueryIndexOptions) error
+	GetIndexStats(keyspace Keyspace, indexName string, opts *GetQueryIndexStatsOptions) (map[string]interface{}, error)
 }
@@ -1,14 +1,24 @@
 package gocb
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/google/uuid"
 )
 
+// defaultBuildDeferredPollInterval is the maximum interval between polls of index state when waiting for indexes to
+// come online.
+const defaultBuildDeferredPollInterval = 1 * time.Second
+
 func (qpc *queryProviderCore) CreatePrimaryIndex(c *Collection, bucketName string, opts *CreatePrimaryQueryIndexOptions) error {
 	return qpc.createIndex(c, bucketName, opts.CustomName, nil, &CreateQueryIndexOptions{
 		IgnoreIfExists: opts.IgnoreIfExists,
@@ -47,7 +57,14 @@ func (qpc *queryProviderCore) createIndex(c *Collection, bucketName, indexName s
 			if i > 0 {
 				qs += ", "
 			}
-			qs += "`" + fields[i] + "`"
+			if opts.RawKeys {
+				qs += fields[i]
+			} else {
+				qs += "`" + fields[i] + "`"
+			}
+			if i == 0 && opts.IncludeMissing {
+				qs += " INCLUDE MISSING"
+			}
 		}
 		qs += ")"
 	}
@@ -160,7 +177,9 @@ func buildGetAllIndexesWhereClause(c *Collection, bucketName, scopeName, collect
 			where = bucketCond
 		}
 
-		if collectionName == "_default" || collectionName == "" {
+		// Indexes on the default collection created before collections existed are stored against the bucket, these
+		// only belong in the results if the default collection is within the requested keyspace.
+		if collectionName == "_default" || (collectionName == "" && (scopeName == "" || scopeName == "_default")) {
 			defaultColCond := "(bucket_id IS MISSING AND keyspace_id = $bucketName)"
 			where = "(" + where + " OR " + defaultColCond + ")"
 		}
@@ -185,7 +204,23 @@ func buildGetAllIndexesWhereClause(c *Collection, bucketName, scopeName, collect
 }
 
 func (qpc *queryProviderCore) GetAllIndexes(c *Collection, bucketName string, opts *GetAllQueryIndexesOptions) ([]QueryIndex, error) {
-	return qpc.getAllIndexes(c, bucketName, opts)
+	indexes, err := qpc.getAllIndexes(c, bucketName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.IncludeStatus && qpc.mgmtProvider != nil && len(indexes) > 0 {
+		// The replica and scan details of an index are not available from the query service, they are best effort
+		// so that listing indexes does not fail where the index status cannot be fetched.
+		statuses, err := qpc.getIndexStatuses(opts)
+		if err != nil {
+			logDebugf("Failed to fetch index statuses: %v", err)
+		} else {
+			populateQueryIndexStatuses(indexes, statuses)
+		}
+	}
+
+	return indexes, nil
 }
 
 func (qpc *queryProviderCore) getAllIndexes(c *Collection, bucketName string, opts *GetAllQueryIndexesOptions) ([]QueryIndex, error) {
@@ -234,6 +269,12 @@ func (qpc *queryProviderCore) BuildDeferredIndexes(c *Collection, bucketName str
 	span := qpc.tracer.createSpan(opts.ParentSpan, "manager_query_build_deferred_indexes", "management")
 	defer span.End()
 
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = qpc.timeouts.ManagementTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
 	var whereClause string
 	params := make(map[string]interface{})
 	if c == nil {
@@ -312,24 +353,40 @@ func (qpc *queryProviderCore) BuildDeferredIndexes(c *Collection, bucketName str
 		return nil, err
 	}
 
+	if opts.WaitUntilOnline {
+		err = qpc.waitForIndexesOnline(c, bucketName, deferredList, deadline, opts.PollInterval, &GetAllQueryIndexesOptions{
+			RetryStrategy:  opts.RetryStrategy,
+			ParentSpan:     span,
+			ScopeName:      opts.ScopeName,
+			CollectionName: opts.CollectionName,
+			Context:        opts.Context,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return deferredList, nil
 }
 
+// checkIndexesActiveCore returns whether every index in checkList is online. Index names are only unique within a
+// collection so when watching multiple collections all indexes with a given name must be online.
 func checkIndexesActiveCore(indexes []QueryIndex, checkList []string) (bool, error) {
 	var checkIndexes []QueryIndex
 	for i := 0; i < len(checkList); i++ {
 		indexName := checkList[i]
 
+		found := false
 		for j := 0; j < len(indexes); j++ {
 			if indexes[j].Name == indexName {
 				checkIndexes = append(checkIndexes, indexes[j])
-				break
+				found = true
 			}
 		}
-	}
 
-	if len(checkIndexes) != len(checkList) {
-		return false, ErrIndexNotFound
+		if !found {
+			return false, ErrIndexNotFound
+		}
 	}
 
 	for i := 0; i < len(checkIndexes); i++ {
@@ -350,10 +407,41 @@ func (qpc *queryProviderCore) WatchIndexes(c *Collection, bucketName string, wat
 		watchList = append(watchList, "#primary")
 	}
 
-	deadline := time.Now().Add(timeout)
+	return qpc.waitForIndexesOnline(c, bucketName, watchList, time.Now().Add(timeout), 0, &GetAllQueryIndexesOptions{
+		RetryStrategy:  opts.RetryStrategy,
+		ParentSpan:     span,
+		ScopeName:      opts.ScopeName,
+		CollectionName: opts.CollectionName,
+		Context:        opts.Context,
+	})
+}
 
-	curInterval := 50 * time.Millisecond
-	for {
+// waitForIndexesOnline polls the indexes matching opts until all of those in watchList are online. The interval
+// between polls backs off up to maxInterval, or defaultBuildDeferredPollInterval if maxInterval is zero.
+func (qpc *queryProviderCore) waitForIndexesOnline(c *Collection, bucketName string, watchList []string, deadline time.Time,
+	maxInterval time.Duration, opts *GetAllQueryIndexesOptions) error {
+	if maxInterval <= 0 {
+		maxInterval = defaultBuildDeferredPollInterval
+	}
+	minInterval := 50 * time.Millisecond
+	if minInterval > maxInterval {
+		minInterval = maxInterval
+	}
+	backoff := gocbcore.ExponentialBackoff(minInterval, maxInterval, 2)
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for attempt := uint32(0); ; attempt++ {
 		if deadline.Before(time.Now()) {
 			return ErrUnambiguousTimeout
 		}
@@ -364,7 +452,7 @@ func (qpc *queryProviderCore) WatchIndexes(c *Collection, bucketName string, wat
 			&GetAllQueryIndexesOptions{
 				Timeout:        time.Until(deadline),
 				RetryStrategy:  opts.RetryStrategy,
-				ParentSpan:     span,
+				ParentSpan:     opts.ParentSpan,
 				ScopeName:      opts.ScopeName,
 				CollectionName: opts.CollectionName,
 				Context:        opts.Context,
@@ -379,26 +467,31 @@ func (qpc *queryProviderCore) WatchIndexes(c *Collection, bucketName string, wat
 		}
 
 		if allOnline {
-			break
+			return nil
 		}
 
-		curInterval += 500 * time.Millisecond
-		if curInterval > 1000 {
-			curInterval = 1000
+		// Make sure we don't wait past our overall deadline, if we adjust the
+		// deadline then it will be caught at the top of this loop as a timeout.
+		interval := backoff(attempt)
+		if remaining := time.Until(deadline); interval > remaining {
+			interval = remaining
 		}
 
-		// Make sure we don't sleep past our overall deadline, if we adjust the
-		// deadline then it will be caught at the top of this loop as a timeout.
-		sleepDeadline := time.Now().Add(curInterval)
-		if sleepDeadline.After(deadline) {
-			sleepDeadline = deadline
+		if timer == nil {
+			timer = time.NewTimer(interval)
+		} else {
+			timer.Reset(interval)
 		}
 
-		// wait till our next poll interval
-		time.Sleep(time.Until(sleepDeadline))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return ErrUnambiguousTimeout
+			}
+			return makeGenericError(ErrRequestCanceled, nil)
+		}
 	}
-
-	return nil
 }
 
 func (qpc *queryProviderCore) doQuery(c *Collection, q string, opts *QueryOptions) ([][]byte, error) {
@@ -475,6 +568,23 @@ type QueryIndex struct {
 	CollectionName string
 	ScopeName      string
 	BucketName     string
+
+	// The following are populated from the status of the index held by the index service when
+	// GetAllQueryIndexesOptions.IncludeStatus is set, and are left unset if the status could not be fetched.
+
+	// NumReplicas is the number of replicas of the index.
+	// UNCOMMITTED: This API may change in the future.
+	NumReplicas int
+	// ReplicaIDs are the IDs of the instances of the index, where 0 is the primary instance.
+	// UNCOMMITTED: This API may change in the future.
+	ReplicaIDs []int
+	// NumPartitions is the number of partitions of a partitioned index.
+	// UNCOMMITTED: This API may change in the future.
+	NumPartitions int
+	// LastScanTime is the most recent time that any instance of the index was scanned, or the zero time if it has
+	// not been scanned since the index service started.
+	// UNCOMMITTED: This API may change in the future.
+	LastScanTime time.Time
 }
 
 func (index *QueryIndex) fromData(data jsonQueryIndex) error {
@@ -532,3 +642,148 @@ func (qpc *queryProviderCore) makeKeyspace(c *Collection, bucketName, scopeName,
 	}
 	return "`" + bucketName + "`"
 }
+
+type jsonIndexStatusResp struct {
+	Indexes []jsonIndexStatus `json:"indexes"`
+}
+
+type jsonIndexStatus struct {
+	Name         string `json:"index"`
+	Bucket       string `json:"bucket"`
+	Scope        string `json:"scope"`
+	Collection   string `json:"collection"`
+	ReplicaID    int    `json:"replicaId"`
+	NumReplica   int    `json:"numReplica"`
+	NumPartition int    `json:"numPartition"`
+	Partitioned  bool   `json:"partitioned"`
+	LastScanTime string `json:"lastScanTime"`
+}
+
+func (qpc *queryProviderCore) getIndexStatuses(opts *GetAllQueryIndexesOptions) ([]jsonIndexStatus, error) {
+	span := qpc.tracer.createSpan(opts.ParentSpan, "manager_query_get_index_status", "management")
+	span.SetAttribute("db.operation", "GET /indexStatus")
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "GET",
+		Path:          "/indexStatus",
+		IsIdempotent:  true,
+		RetryStrategy: opts.RetryStrategy,
+		Timeout:       opts.Timeout,
+		UniqueID:      uuid.New().String(),
+		parentSpanCtx: span.Context(),
+	}
+	resp, err := qpc.mgmtProvider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return nil, err
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, makeMgmtBadStatusError("failed to get index status", &req, resp)
+	}
+
+	var statusResp jsonIndexStatusResp
+	jsonDec := json.NewDecoder(resp.Body)
+	err = jsonDec.Decode(&statusResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return statusResp.Indexes, nil
+}
+
+// populateQueryIndexStatuses sets the details of each index which are only available from the index service. Each
+// replica of an index has its own status.
+func populateQueryIndexStatuses(indexes []QueryIndex, statuses []jsonIndexStatus) {
+	key := func(bucket, scope, collection, name string) string {
+		if scope == "" {
+			scope = "_default"
+		}
+		if collection == "" {
+			collection = "_default"
+		}
+		return bucket + "\x00" + scope + "\x00" + collection + "\x00" + name
+	}
+
+	byKey := make(map[string][]jsonIndexStatus)
+	for _, status := range statuses {
+		k := key(status.Bucket, status.Scope, status.Collection, status.Name)
+		byKey[k] = append(byKey[k], status)
+	}
+
+	for i := range indexes {
+		index := &indexes[i]
+		for _, status := range byKey[key(index.BucketName, index.ScopeName, index.CollectionName, index.Name)] {
+			index.NumReplicas = status.NumReplica
+			index.ReplicaIDs = append(index.ReplicaIDs, status.ReplicaID)
+			if status.Partitioned {
+				index.NumPartitions = status.NumPartition
+			}
+
+			// The index service reports NA where an instance has not been scanned.
+			scanTime, err := time.Parse(time.UnixDate, status.LastScanTime)
+			if err == nil && scanTime.After(index.LastScanTime) {
+				index.LastScanTime = scanTime
+			}
+		}
+		sort.Ints(index.ReplicaIDs)
+	}
+}
+
+func (qpc *queryProviderCore) GetIndexStats(keyspace Keyspace, indexName string, opts *GetQueryIndexStatsOptions) (map[string]interface{}, error) {
+	path := fmt.Sprintf("/api/v1/stats/%s.%s.%s/%s", url.PathEscape(keyspace.Bucket), url.PathEscape(keyspace.scopeName()),
+		url.PathEscape(keyspace.collectionName()), url.PathEscape(indexName))
+
+	span := qpc.tracer.createSpan(opts.ParentSpan, "manager_query_get_index_stats", "management")
+	span.SetAttribute("db.operation", "GET "+path)
+	defer span.End()
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = qpc.timeouts.ManagementTimeout
+	}
+
+	req := mgmtRequest{
+		Service:       serviceTypeIndex,
+		Method:        "GET",
+		Path:          path,
+		IsIdempotent:  true,
+		RetryStrategy: opts.RetryStrategy,
+		Timeout:       timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	// Each index node only reports on the index instances that it hosts, so the index is only missing if no node
+	// hosts it.
+	stats := make(map[string]interface{})
+	found := false
+	err := forEachServiceNode(opts.Context, qpc.mgmtProvider, req, func(req *mgmtRequest, resp *mgmtResponse) error {
+		if resp.StatusCode == 404 {
+			return nil
+		}
+		if resp.StatusCode != 200 {
+			return makeMgmtBadStatusError("failed to get index stats", req, resp)
+		}
+
+		var nodeStats map[string]interface{}
+		jsonDec := json.NewDecoder(resp.Body)
+		if err := jsonDec.Decode(&nodeStats); err != nil {
+			return err
+		}
+
+		mergeNodeStats(stats, nodeStats)
+		found = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, makeGenericMgmtError(ErrIndexNotFound, &req, nil, "")
+	}
+
+	return stats, nil
+}
@@ -1,3 +1,5 @@
+//go:build !gocb_nocouchbase2
+
 package gocb
 
 import (
@@ -82,6 +84,10 @@ func (qpc *queryIndexProviderPs) CreateIndex(c *Collection, bucketName, indexNam
 		return err
 	}
 
+	if opts.IncludeMissing || opts.RawKeys {
+		return wrapError(ErrFeatureNotAvailable, "include missing and raw keys are not supported by couchbase2")
+	}
+
 	bucket, scope, collection := qpc.makeKeyspace(c, bucketName, opts.ScopeName, opts.CollectionName)
 	var numReplicas int32
 	if opts.NumReplicas != 0 {
@@ -334,6 +340,18 @@ func (qpc *queryIndexProviderPs) BuildDeferredIndexes(c *Collection, bucketName
 		indexNames[i] = fullName
 	}
 
+	if opts.WaitUntilOnline {
+		for _, index := range resp.Indexes {
+			err := qpc.waitForIndexOnline(c, index.Name, index.BucketName, manager, &waitForIndexOnlineOptions{
+				ScopeName:      index.GetScopeName(),
+				CollectionName: index.GetCollectionName(),
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return indexNames, nil
 }
 
@@ -496,3 +514,7 @@ func (qpc *queryIndexProviderPs) tryParseErrorMessage(err *GenericError) *Generi
 
 	return makeGenericError(innerErr, err.Context)
 }
+
+func (qpc *queryIndexProviderPs) GetIndexStats(Keyspace, string, *GetQueryIndexStatsOptions) (map[string]interface{}, error) {
+	return nil, wrapError(ErrFeatureNotAvailable, "index stats are not supported by couchbase2")
+}
@@ -3,7 +3,9 @@ package gocb
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	gocbcore "github.com/couchbase/gocbcore/v10"
@@ -16,21 +18,32 @@ type queryProviderCoreProvider interface {
 
 type queryProviderCore struct {
 	provider queryProviderCoreProvider
+	// mgmtProvider is only set for query index management, to fetch index details from the index service.
+	mgmtProvider mgmtProvider
 
 	retryStrategyWrapper *coreRetryStrategyWrapper
 	transcoder           Transcoder
 	timeouts             TimeoutsConfig
 	tracer               *tracerWrapper
+
+	endpointSelectionPolicy EndpointSelectionPolicy
+	endpointSelector        *latencyAwareEndpointSelector
 }
 
-func (qpc *queryProviderCore) Query(statement string, s *Scope, opts *QueryOptions) (*QueryResult, error) {
+func (qpc *queryProviderCore) Query(statement string, s *Scope, opts *QueryOptions) (resOut *QueryResult, errOut error) {
 	span := qpc.tracer.createSpan(opts.ParentSpan, "query", "query")
 	span.SetAttribute("db.statement", statement)
 	if s != nil {
 		span.SetAttribute("db.name", s.BucketName())
 		span.SetAttribute("db.couchbase.scope", s.Name())
 	}
-	defer span.End()
+	// The span lives for the lifetime of the response stream so that cancellation of the stream can be recorded
+	// against it.
+	defer func() {
+		if errOut != nil {
+			span.End()
+		}
+	}()
 
 	retryStrategy := qpc.retryStrategyWrapper
 	if opts.RetryStrategy != nil {
@@ -68,72 +81,181 @@ func (qpc *queryProviderCore) Query(statement string, s *Scope, opts *QueryOptio
 		}
 	}
 
-	var res queryRowReader
-	var qErr error
-	if opts.Adhoc {
-		res, qErr = qpc.provider.N1QLQuery(opts.Context, gocbcore.N1QLQueryOptions{
-			Payload:       reqBytes,
-			RetryStrategy: retryStrategy,
-			Deadline:      deadline,
-			TraceContext:  span.Context(),
-			User:          opts.Internal.User,
-			Endpoint:      opts.Internal.Endpoint,
-		})
-	} else {
-		res, qErr = qpc.provider.PreparedN1QLQuery(opts.Context, gocbcore.N1QLQueryOptions{
-			Payload:       reqBytes,
-			RetryStrategy: retryStrategy,
-			Deadline:      deadline,
-			TraceContext:  span.Context(),
-			User:          opts.Internal.User,
-			Endpoint:      opts.Internal.Endpoint,
-		})
+	endpoint := opts.Endpoint
+	if endpoint == "" {
+		endpoint = opts.Internal.Endpoint
+	}
+	var selected bool
+	if endpoint == "" && qpc.endpointSelector != nil {
+		policy := opts.EndpointSelectionPolicy
+		if policy == 0 {
+			policy = qpc.endpointSelectionPolicy
+		}
+		if policy == EndpointSelectionPolicyLatencyAware {
+			endpoint = qpc.endpointSelector.Select()
+			selected = endpoint != ""
+		}
+	}
+
+	coreOpts := gocbcore.N1QLQueryOptions{
+		Payload:       reqBytes,
+		RetryStrategy: retryStrategy,
+		Deadline:      deadline,
+		TraceContext:  span.Context(),
+		User:          opts.Internal.User,
+		Endpoint:      endpoint,
+	}
+
+	res, qErr := qpc.execute(opts.Context, opts.Adhoc, coreOpts)
+	if selected && qErr != nil && shouldFailoverEndpoint(maybeEnhanceCoreQueryError(qErr), opts.Readonly, deadline) {
+		logDebugf("Query failed against selected endpoint %s, retrying against any endpoint: %v", endpoint, qErr)
+		coreOpts.Endpoint = ""
+		res, qErr = qpc.execute(opts.Context, opts.Adhoc, coreOpts)
 	}
 	if qErr != nil {
+		setSpanOutcome(span, opts.Context, qErr)
+		if opts.Endpoint != "" && errors.Is(qErr, gocbcore.ErrInvalidServer) {
+			return nil, makeInvalidArgumentsError(fmt.Sprintf("endpoint %s is not a query endpoint within the cluster", opts.Endpoint))
+		}
 		return nil, maybeEnhanceCoreQueryError(qErr)
 	}
 
-	return newQueryResult(&queryProviderCoreRowReader{reader: res}), nil
+	result := newQueryResult(newQueryProviderCoreRowReader(opts.Context, res, span))
+	result.serializer = opts.Serializer
+	return result, nil
 }
 
-// queryProviderCoreRowReader exists primarily to wrap errors.
+// execute sends the query, recording the time taken for the query service to begin responding against the endpoint
+// which served the request. This is tracked regardless of policy so that it is ready if latency aware selection is used.
+func (qpc *queryProviderCore) execute(ctx context.Context, adhoc bool, opts gocbcore.N1QLQueryOptions) (queryRowReader, error) {
+	start := time.Now()
+	var res queryRowReader
+	var err error
+	if adhoc {
+		res, err = qpc.provider.N1QLQuery(ctx, opts)
+	} else {
+		res, err = qpc.provider.PreparedN1QLQuery(ctx, opts)
+	}
+	if qpc.endpointSelector != nil {
+		qpc.recordEndpointLatency(opts.Endpoint, res, time.Since(start), err)
+	}
+
+	return res, err
+}
+
+func (qpc *queryProviderCore) recordEndpointLatency(endpoint string, res queryRowReader, latency time.Duration, err error) {
+	if err != nil {
+		var coreErr *gocbcore.N1QLError
+		if errors.As(err, &coreErr) && coreErr.Endpoint != "" {
+			endpoint = coreErr.Endpoint
+		}
+		qpc.endpointSelector.Record(endpoint, latency, maybeEnhanceCoreQueryError(err))
+		return
+	}
+
+	qpc.endpointSelector.Record(res.Endpoint(), latency, nil)
+}
+
+// queryProviderCoreRowReader wraps errors and ties the lifetime of the response stream to the context
+// provided by the user, closing the stream if the context is cancelled or its deadline passes.
 type queryProviderCoreRowReader struct {
 	reader queryRowReader
+	span   RequestSpan
+	ctx    *resultsContext
+
+	finishOnce sync.Once
 }
 
-func (q queryProviderCoreRowReader) NextRow() []byte {
-	return q.reader.NextRow()
+func newQueryProviderCoreRowReader(ctx context.Context, reader queryRowReader, span RequestSpan) *queryProviderCoreRowReader {
+	q := &queryProviderCoreRowReader{
+		reader: reader,
+		span:   span,
+	}
+	q.ctx = newResultsContext(ctx, reader, func(err error) {
+		setSpanOutcome(q.span, nil, err)
+		q.finish()
+	})
+
+	return q
+}
+
+func (q *queryProviderCoreRowReader) finish() {
+	q.finishOnce.Do(func() {
+		q.span.End()
+	})
+}
+
+func (q *queryProviderCoreRowReader) NextRow() []byte {
+	row := q.ctx.NextRow()
+	if row == nil {
+		q.finish()
+	}
+
+	return row
 }
 
-func (q queryProviderCoreRowReader) Err() error {
-	if err := q.reader.Err(); err != nil {
-		return maybeEnhanceCoreQueryError(err)
+func (q *queryProviderCoreRowReader) Err() error {
+	if err := q.ctx.Err(); err != nil {
+		return err
+	}
+
+	var err error
+	q.ctx.Do(func() {
+		err = q.reader.Err()
+	})
+	if err != nil {
+		return q.withEndpoint(maybeEnhanceCoreQueryError(err))
 	}
 
 	return nil
 }
 
-func (q queryProviderCoreRowReader) MetaData() ([]byte, error) {
-	return q.reader.MetaData()
+func (q *queryProviderCoreRowReader) MetaData() (meta []byte, err error) {
+	q.ctx.Do(func() {
+		meta, err = q.reader.MetaData()
+	})
+	return
 }
 
-func (q queryProviderCoreRowReader) Close() error {
-	err := q.reader.Close()
+func (q *queryProviderCoreRowReader) Close() error {
+	defer q.finish()
+
+	ctxErr, err := q.ctx.Close()
+	if ctxErr != nil {
+		return ctxErr
+	}
 	if err != nil {
-		return maybeEnhanceCoreQueryError(err)
+		return q.withEndpoint(maybeEnhanceCoreQueryError(err))
 	}
 
 	return nil
 }
 
-func (q queryProviderCoreRowReader) PreparedName() (string, error) {
+func (q *queryProviderCoreRowReader) PreparedName() (string, error) {
 	return q.reader.PreparedName()
 }
 
-func (q queryProviderCoreRowReader) Endpoint() string {
+func (q *queryProviderCoreRowReader) Endpoint() string {
 	return q.reader.Endpoint()
 }
 
+// withEndpoint populates the endpoint of errors which occur whilst streaming, gocbcore does not include it on these.
+// Errors reading the stream itself are not query errors in gocbcore, so these are wrapped in one.
+func (q *queryProviderCoreRowReader) withEndpoint(err error) error {
+	var qErr *QueryError
+	if !errors.As(err, &qErr) {
+		return &QueryError{
+			InnerError: wrapError(err, "failed to read query response"),
+			Endpoint:   q.reader.Endpoint(),
+		}
+	}
+	if qErr.Endpoint == "" {
+		qErr.Endpoint = q.reader.Endpoint()
+	}
+
+	return err
+}
+
 func maybeGetQueryOption(options map[string]interface{}, name string) string {
 	if value, ok := options[name].(string); ok {
 		return value
@@ -0,0 +1,188 @@
+package gocb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// queryProviderDataAPI executes queries using the query service proxy of the Data API. The entire response is read
+// before the result is returned, as the proxy does not stream rows.
+type queryProviderDataAPI struct {
+	mgr    *dataAPIConnectionMgr
+	tracer *tracerWrapper
+}
+
+type jsonDataAPIQueryError struct {
+	Code    uint32                 `json:"code"`
+	Message string                 `json:"msg"`
+	Retry   bool                   `json:"retry"`
+	Reason  map[string]interface{} `json:"reason"`
+}
+
+func (p *queryProviderDataAPI) Query(statement string, s *Scope, opts *QueryOptions) (*QueryResult, error) {
+	span := p.tracer.createSpan(opts.ParentSpan, "query", "query")
+	span.SetAttribute("db.statement", statement)
+	if s != nil {
+		span.SetAttribute("db.name", s.BucketName())
+		span.SetAttribute("db.couchbase.scope", s.Name())
+	}
+	defer span.End()
+
+	if opts.Endpoint != "" {
+		return nil, dataAPIUnsupported("the Endpoint query option")
+	}
+
+	queryOpts, err := opts.toMap()
+	if err != nil {
+		return nil, &QueryError{
+			InnerError:      wrapError(err, "failed to generate query options"),
+			Statement:       statement,
+			ClientContextID: opts.ClientContextID,
+		}
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = p.mgr.timeouts.QueryTimeout
+	}
+
+	queryOpts["statement"] = statement
+	// The query service is told the timeout so that it stops executing the query once the SDK has given up on it.
+	queryOpts["timeout"] = timeout.String()
+	if s != nil {
+		queryOpts["query_context"] = fmt.Sprintf("default:`%s`.`%s`", escapeQueryIdentifier(s.BucketName()),
+			escapeQueryIdentifier(s.Name()))
+	}
+
+	eSpan := p.tracer.createSpan(span, "request_encoding", "")
+	reqBytes, err := json.Marshal(queryOpts)
+	eSpan.End()
+	if err != nil {
+		return nil, &QueryError{
+			InnerError:      wrapError(err, "failed to marshall query body"),
+			Statement:       statement,
+			ClientContextID: maybeGetQueryOption(queryOpts, "client_context_id"),
+		}
+	}
+
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+
+	resp, err := p.mgr.execute(opts.Context, timeout, opts.Readonly, http.MethodPost, "/_p/query/query/service",
+		header, reqBytes)
+	if err != nil {
+		setSpanOutcome(span, opts.Context, err)
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, dataAPIStatusError(resp)
+	}
+
+	var respData map[string]json.RawMessage
+	if err := json.Unmarshal(resp.Body, &respData); err != nil {
+		return nil, &QueryError{
+			InnerError:      wrapError(dataAPIStatusError(resp), "failed to parse query response"),
+			Statement:       statement,
+			ClientContextID: maybeGetQueryOption(queryOpts, "client_context_id"),
+			HTTPStatusCode:  resp.StatusCode,
+		}
+	}
+
+	var rows []json.RawMessage
+	if results, ok := respData["results"]; ok {
+		if err := json.Unmarshal(results, &rows); err != nil {
+			return nil, wrapError(err, "failed to parse query results")
+		}
+		delete(respData, "results")
+	}
+
+	var jsonErrs []jsonDataAPIQueryError
+	if errs, ok := respData["errors"]; ok {
+		if err := json.Unmarshal(errs, &jsonErrs); err != nil {
+			return nil, wrapError(err, "failed to parse query errors")
+		}
+	}
+	if len(jsonErrs) > 0 || resp.StatusCode != http.StatusOK {
+		descs := make([]QueryErrorDesc, len(jsonErrs))
+		for i, jsonErr := range jsonErrs {
+			descs[i] = QueryErrorDesc{
+				Code:    jsonErr.Code,
+				Message: jsonErr.Message,
+				Retry:   jsonErr.Retry,
+				Reason:  jsonErr.Reason,
+			}
+		}
+
+		innerErr := queryErrorFromDescs(descs)
+		if innerErr == nil {
+			innerErr = dataAPIStatusError(resp)
+		}
+
+		return nil, &QueryError{
+			InnerError:      innerErr,
+			Statement:       statement,
+			ClientContextID: maybeGetQueryOption(queryOpts, "client_context_id"),
+			Errors:          descs,
+			ErrorText:       string(resp.Body),
+			HTTPStatusCode:  resp.StatusCode,
+		}
+	}
+
+	metaData, err := json.Marshal(respData)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newQueryResult(&dataAPIQueryRowReader{
+		rows:     rows,
+		metaData: metaData,
+	})
+	result.serializer = opts.Serializer
+	return result, nil
+}
+
+// escapeQueryIdentifier escapes a name so that it can be used within backticks in a query, by doubling any
+// backticks it contains.
+func escapeQueryIdentifier(name string) string {
+	return strings.ReplaceAll(name, "`", "``")
+}
+
+// dataAPIQueryRowReader replays the rows of a query response which has been read in full.
+type dataAPIQueryRowReader struct {
+	rows     []json.RawMessage
+	metaData []byte
+	idx      int
+}
+
+func (r *dataAPIQueryRowReader) NextRow() []byte {
+	if r.idx >= len(r.rows) {
+		return nil
+	}
+
+	row := r.rows[r.idx]
+	r.idx++
+	return row
+}
+
+func (r *dataAPIQueryRowReader) Err() error {
+	return nil
+}
+
+func (r *dataAPIQueryRowReader) MetaData() ([]byte, error) {
+	return r.metaData, nil
+}
+
+func (r *dataAPIQueryRowReader) Close() error {
+	return nil
+}
+
+func (r *dataAPIQueryRowReader) PreparedName() (string, error) {
+	return "", nil
+}
+
+func (r *dataAPIQueryRowReader) Endpoint() string {
+	return ""
+}
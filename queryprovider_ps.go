@@ -1,3 +1,5 @@
+//go:build !gocb_nocouchbase2
+
 package gocb
 
 import (
@@ -101,10 +103,14 @@ func (qpc *queryProviderPs) Query(statement string, s *Scope, opts *QueryOptions
 		req.ScanConsistency = &consistency
 	}
 
+	serializer := opts.Serializer
+	if serializer == nil {
+		serializer = NewJSONSubdocSerializer()
+	}
 	if len(opts.PositionalParameters) > 0 {
 		params := make([][]byte, len(opts.PositionalParameters))
 		for i, param := range opts.PositionalParameters {
-			b, err := json.Marshal(param)
+			b, err := serializer.Serialize(param)
 			if err != nil {
 				return nil, err
 			}
@@ -117,7 +123,7 @@ func (qpc *queryProviderPs) Query(statement string, s *Scope, opts *QueryOptions
 	if len(opts.NamedParameters) > 0 {
 		params := make(map[string][]byte, len(opts.NamedParameters))
 		for k, param := range opts.NamedParameters {
-			b, err := json.Marshal(param)
+			b, err := serializer.Serialize(param)
 			if err != nil {
 				return nil, err
 			}
@@ -127,6 +133,18 @@ func (qpc *queryProviderPs) Query(statement string, s *Scope, opts *QueryOptions
 
 		req.NamedParameters = params
 	}
+	if opts.UseReplica != QueryUseReplicaLevelNotSet {
+		return nil, &QueryParameterError{
+			InnerError: wrapError(ErrFeatureNotAvailable, "use replica is not supported by the couchbase2 protocol"),
+			Parameter:  "use_replica",
+		}
+	}
+	if opts.Endpoint != "" {
+		return nil, &QueryParameterError{
+			InnerError: wrapError(ErrFeatureNotAvailable, "endpoint pinning is not supported by the couchbase2 protocol"),
+			Parameter:  "endpoint",
+		}
+	}
 	if opts.FlexIndex {
 		req.FlexIndex = &opts.FlexIndex
 	}
@@ -176,9 +194,9 @@ func (qpc *queryProviderPs) Query(statement string, s *Scope, opts *QueryOptions
 
 	var cancellationIsTimeout uint32
 	// This second context has no real parent and will be cancelled if the user context is cancelled or the timeout
-	// is reached. However, if the user context does not get cancelled during the initial request portion of the
-	// operation then this context will live for the lifetime of the op and be used for cancelled if the user calls
-	// Close on the result.
+	// is reached. Once the initial request portion of the operation has completed the timeout no longer applies, but
+	// cancelling the user context will still cancel this context, terminating the stream. This context is also
+	// cancelled if the user calls Close on the result.
 	doneCh := make(chan struct{})
 	reqCtx, reqCancel := context.WithCancel(context.Background())
 	go func() {
@@ -208,6 +226,7 @@ func (qpc *queryProviderPs) Query(statement string, s *Scope, opts *QueryOptions
 	close(doneCh)
 	if err != nil {
 		reqCancel()
+		setSpanOutcome(manager.TraceSpan(), userCtx, err)
 		manager.Finish()
 		return nil, qpc.makeError(err, statement, opts.Readonly, atomic.LoadUint32(&cancellationIsTimeout) == 1,
 			manager.ElapsedTime(), manager.RetryInfo())
@@ -225,7 +244,21 @@ func (qpc *queryProviderPs) Query(statement string, s *Scope, opts *QueryOptions
 
 		manager: manager,
 	}
-	return newQueryResult(reader), nil
+
+	if userCtx.Done() != nil {
+		go func() {
+			select {
+			case <-userCtx.Done():
+				setSpanOutcome(manager.TraceSpan(), nil, ErrRequestCanceled)
+				reqCancel()
+			case <-reqCtx.Done():
+			}
+		}()
+	}
+
+	result := newQueryResult(reader)
+	result.serializer = opts.Serializer
+	return result, nil
 }
 
 func (qpc *queryProviderPs) makeError(err error, statement string, readonly, hasTimedOut bool, elapsed time.Duration,
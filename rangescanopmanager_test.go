@@ -25,6 +25,8 @@ type mockConfigSnapshot struct {
 	revID            int64
 	numVbuckets      int
 	numReplicas      int
+	keyVbucket       uint16
+	keyVbuckets      map[string]uint16
 	serverToVbuckets map[int][]uint16
 }
 
@@ -32,6 +34,14 @@ func (p *mockConfigSnapshot) KeyToServersByServerGroup(key []byte) (map[string][
 	return nil, nil
 }
 
+func (p *mockConfigSnapshot) KeyToVbucket(key []byte) (uint16, error) {
+	if vbID, ok := p.keyVbuckets[string(key)]; ok {
+		return vbID, nil
+	}
+
+	return p.keyVbucket, nil
+}
+
 func (p *mockConfigSnapshot) RevID() int64 {
 	return p.revID
 }
@@ -20,6 +20,19 @@ func (d *Result) Cas() Cas {
 	return d.cas
 }
 
+// GetExpiryResult is the return type of GetExpiry operations.
+// UNCOMMITTED: This API may change in the future.
+type GetExpiryResult struct {
+	Result
+	expiryTime time.Time
+}
+
+// ExpiryTime returns the expiry time of the document.
+// This function will return a zero time if the document does not have an expiry time.
+func (r *GetExpiryResult) ExpiryTime() time.Time {
+	return r.expiryTime
+}
+
 // GetResult is the return type of Get operations.
 type GetResult struct {
 	Result
@@ -27,6 +40,8 @@ type GetResult struct {
 	flags      uint32
 	contents   []byte
 	expiryTime *time.Time
+
+	warningHandler WarningHandler
 }
 
 // Content assigns the value of the result into the valuePtr using default decoding.
@@ -39,6 +54,8 @@ func (d *GetResult) Content(valuePtr interface{}) error {
 // Duration indicates that the document will never expire.
 // Deprecated: Use ExpiryTime instead.
 func (d *GetResult) Expiry() *time.Duration {
+	warnDeprecated(d.warningHandler, "GetResult.Expiry", "Expiry is deprecated, use ExpiryTime instead")
+
 	if d.expiryTime == nil {
 		return nil
 	}
@@ -407,7 +424,11 @@ func (mr CounterResult) Content() uint64 {
 // GetReplicaResult is the return type of GetReplica operations.
 type GetReplicaResult struct {
 	GetResult
-	isReplica bool
+	isReplica    bool
+	replicaIndex int
+
+	activeCas      Cas
+	activeCasKnown bool
 }
 
 // IsReplica returns whether or not this result came from a replica server.
@@ -415,6 +436,28 @@ func (r *GetReplicaResult) IsReplica() bool {
 	return r.isReplica
 }
 
+// ReplicaIndex returns the index of the server which served this result, where 0 is the active and 1 onwards are
+// the replicas. Returns -1 if the index is not known, such as when using the couchbase2 protocol.
+// UNCOMMITTED: This API may change in the future.
+func (r *GetReplicaResult) ReplicaIndex() int {
+	return r.replicaIndex
+}
+
+// ActiveCas returns the Cas of the document on the active, if the active had already been read by the same
+// GetAllReplicas operation when this result was returned.
+// UNCOMMITTED: This API may change in the future.
+func (r *GetReplicaResult) ActiveCas() (Cas, bool) {
+	return r.activeCas, r.activeCasKnown
+}
+
+// IsStale returns whether this result is known to differ from the document on the active, such as can happen when
+// reading from replicas during failover. A result is never reported as stale if the Cas of the active is not known,
+// see ActiveCas.
+// UNCOMMITTED: This API may change in the future.
+func (r *GetReplicaResult) IsStale() bool {
+	return r.activeCasKnown && r.cas != r.activeCas
+}
+
 // ScanResult is the return type of Scan operations.
 type ScanResult struct {
 	resultChan chan *ScanResultItem
@@ -0,0 +1,160 @@
+package gocb
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+type contextStreamReader interface {
+	NextRow() []byte
+	Close() error
+}
+
+// resultsContext ties a streaming result to the context provided by the user. The gocbcore row readers cannot be
+// used from more than one goroutine, so rather than closing the stream from a goroutine watching the context, the
+// context is checked whenever the result is used and the stream is closed by the goroutine consuming it. A NextRow
+// which is waiting for the server also watches the context, closing the stream to unblock the reader. All access to
+// the underlying reader is serialised through the lock.
+type resultsContext struct {
+	ctx    context.Context
+	reader contextStreamReader
+	// onCancel is called when the context ends the stream whilst it is still open.
+	onCancel func(err error)
+
+	lock      sync.Mutex
+	ctxErr    error
+	completed bool
+	closed    bool
+}
+
+func newResultsContext(ctx context.Context, reader contextStreamReader, onCancel func(err error)) *resultsContext {
+	return &resultsContext{
+		ctx:      ctx,
+		reader:   reader,
+		onCancel: onCancel,
+	}
+}
+
+// checkContext closes the stream if the context is done and the stream is still open, the lock must be held.
+func (rc *resultsContext) checkContext() {
+	if rc.ctx == nil || rc.completed || rc.closed {
+		return
+	}
+
+	ctxErr := rc.ctx.Err()
+	if ctxErr == nil {
+		return
+	}
+
+	rc.closeForContext(ctxErr)
+}
+
+// closeForContext closes the stream because the context is done, the lock must be held.
+func (rc *resultsContext) closeForContext(ctxErr error) {
+	rc.ctxErr = resultsContextError(ctxErr)
+	rc.closed = true
+	if err := rc.reader.Close(); err != nil {
+		logDebugf("Failed to close stream after context was done: %s", err)
+	}
+
+	if rc.onCancel != nil {
+		rc.onCancel(rc.ctxErr)
+	}
+}
+
+// NextRow returns the next row, or nil once the stream has completed or been ended by the context.
+func (rc *resultsContext) NextRow() []byte {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	rc.checkContext()
+	if rc.completed || rc.closed {
+		return nil
+	}
+
+	row := rc.nextRow()
+	if row == nil && !rc.closed {
+		rc.completed = true
+	}
+
+	return row
+}
+
+// nextRow reads the next row from the underlying reader whilst watching the context, the lock must be held. If the
+// context is done before the row arrives then the stream is closed and nil is returned.
+func (rc *resultsContext) nextRow() []byte {
+	var done <-chan struct{}
+	if rc.ctx != nil {
+		done = rc.ctx.Done()
+	}
+	if done == nil {
+		return rc.reader.NextRow()
+	}
+
+	rowCh := make(chan []byte, 1)
+	go func() {
+		rowCh <- rc.reader.NextRow()
+	}()
+
+	select {
+	case row := <-rowCh:
+		return row
+	case <-done:
+		rc.closeForContext(rc.ctx.Err())
+		// Closing the stream unblocks the reader, which must have returned before anything else uses it.
+		<-rowCh
+		return nil
+	}
+}
+
+// Err returns the error caused by the context ending the stream, if it did.
+func (rc *resultsContext) Err() error {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	rc.checkContext()
+	return rc.ctxErr
+}
+
+// Do runs fn with exclusive access to the underlying reader.
+func (rc *resultsContext) Do(fn func()) {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	fn()
+}
+
+// Close closes the stream. If the context ended the stream then the error caused by it is returned as ctxErr,
+// otherwise err is the result of closing the underlying reader.
+func (rc *resultsContext) Close() (ctxErr error, err error) {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	rc.checkContext()
+	if rc.ctxErr != nil {
+		return rc.ctxErr, nil
+	}
+	if rc.closed {
+		return nil, nil
+	}
+
+	rc.closed = true
+	return nil, rc.reader.Close()
+}
+
+// IsOpen returns whether the stream is still being read, that is it has neither completed nor been closed.
+func (rc *resultsContext) IsOpen() bool {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	return !rc.completed && !rc.closed
+}
+
+func resultsContextError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return makeGenericError(ErrTimeout, nil)
+	}
+
+	return makeGenericError(ErrRequestCanceled, nil)
+}
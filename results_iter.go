@@ -0,0 +1,164 @@
+//go:build go1.23
+
+package gocb
+
+import (
+	"encoding/json"
+	"iter"
+)
+
+// rowsResult is implemented by streaming results whose rows can be decoded into a value, such as QueryResult and
+// AnalyticsResult.
+type rowsResult interface {
+	Next() bool
+	Row(valuePtr interface{}) error
+	Err() error
+	Close() error
+}
+
+// RowsAs returns an iterator which decodes each row of result into a value of type T, for use with a range
+// statement. Rows are decoded in the same way as calling Row on the result.
+// If a row cannot be decoded, or an error occurs on the stream, then the error is yielded as the final item and
+// iteration stops. The result is always closed once iteration stops, including if the caller stops early, after
+// which any meta-data can be accessed from the result as usual.
+// UNCOMMITTED: This API may change in the future.
+func RowsAs[T any](result rowsResult) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for result.Next() {
+			var row T
+			if err := result.Row(&row); err != nil {
+				_ = result.Close()
+				yield(row, err)
+				return
+			}
+
+			if !yield(row, nil) {
+				_ = result.Close()
+				return
+			}
+		}
+
+		err := result.Err()
+		if closeErr := result.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// iterRows returns an iterator over the rows of result, using row to fetch the current row after each call to Next.
+// Any error on the stream is yielded as the final item, and the result is always closed once iteration stops.
+func iterRows[T any](result interface {
+	Next() bool
+	Err() error
+	Close() error
+}, row func() T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for result.Next() {
+			if !yield(row(), nil) {
+				_ = result.Close()
+				return
+			}
+		}
+
+		err := result.Err()
+		if closeErr := result.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// Iter returns an iterator over the raw JSON bytes of each row, for use with a range statement. Use RowsAs to
+// decode each row into a value instead.
+// If an error occurs on the stream then it is yielded as the final item. The result is always closed once iteration
+// stops, including if the caller stops early, after which any meta-data can be accessed from the result as usual.
+// UNCOMMITTED: This API may change in the future.
+func (r *QueryResult) Iter() iter.Seq2[json.RawMessage, error] {
+	return RowsAs[json.RawMessage](r)
+}
+
+// Iter returns an iterator over the raw JSON bytes of each row, for use with a range statement. Use RowsAs to
+// decode each row into a value instead.
+// If an error occurs on the stream then it is yielded as the final item. The result is always closed once iteration
+// stops, including if the caller stops early, after which any meta-data can be accessed from the result as usual.
+// UNCOMMITTED: This API may change in the future.
+func (r *AnalyticsResult) Iter() iter.Seq2[json.RawMessage, error] {
+	return RowsAs[json.RawMessage](r)
+}
+
+// Iter returns an iterator over each row of the result, for use with a range statement.
+// If an error occurs on the stream then it is yielded as the final item. The result is always closed once iteration
+// stops, including if the caller stops early, after which the meta-data and facets can be accessed as usual.
+// UNCOMMITTED: This API may change in the future.
+func (r *SearchResult) Iter() iter.Seq2[SearchRow, error] {
+	return iterRows(r, r.Row)
+}
+
+// Iter returns an iterator over each row of the result, for use with a range statement.
+// If an error occurs on the stream then it is yielded as the final item. The result is always closed once iteration
+// stops, including if the caller stops early, after which any meta-data can be accessed as usual.
+// UNCOMMITTED: This API may change in the future.
+func (r *ViewResult) Iter() iter.Seq2[ViewRow, error] {
+	return iterRows(r, r.Row)
+}
+
+// ViewRowsAs returns an iterator which decodes the key and value of each row of result into the types K and V, for
+// use with a range statement. Rows are decoded in the same way as DecodeViewRow.
+// If a row cannot be decoded, or an error occurs on the stream, then the error is yielded as the final item and
+// iteration stops. The result is always closed once iteration stops, including if the caller stops early.
+// UNCOMMITTED: This API may change in the future.
+func ViewRowsAs[K any, V any](result *ViewResult) iter.Seq2[TypedViewRow[K, V], error] {
+	return func(yield func(TypedViewRow[K, V], error) bool) {
+		for result.Next() {
+			row, err := DecodeViewRow[K, V](result.Row())
+			if err != nil {
+				_ = result.Close()
+				yield(row, err)
+				return
+			}
+
+			if !yield(row, nil) {
+				_ = result.Close()
+				return
+			}
+		}
+
+		err := result.Err()
+		if closeErr := result.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			var zero TypedViewRow[K, V]
+			yield(zero, err)
+		}
+	}
+}
+
+// Iter returns an iterator over each item on the stream, for use with a range statement.
+// If an error occurs on the stream then it is yielded as the final item. The stream is always closed once iteration
+// stops, including if the caller stops early.
+// UNCOMMITTED: This API may change in the future.
+func (sr *ScanResult) Iter() iter.Seq2[*ScanResultItem, error] {
+	return func(yield func(*ScanResultItem, error) bool) {
+		for item := sr.Next(); item != nil; item = sr.Next() {
+			if !yield(item, nil) {
+				_ = sr.Close()
+				return
+			}
+		}
+
+		if err := sr.Close(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
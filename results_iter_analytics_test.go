@@ -0,0 +1,27 @@
+//go:build go1.23 && !gocb_noanalytics
+
+package gocb
+
+func (suite *UnitTestSuite) TestAnalyticsRowsAs() {
+	var dataset testAnalyticsDataset
+	err := loadJSONTestDataset("beer_sample_analytics_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockAnalyticsRowReader{
+		Dataset: dataset.Results,
+		Meta:    suite.mustConvertToBytes(dataset.jsonAnalyticsResponse),
+		Suite:   suite,
+	}
+	result := newAnalyticsResult(reader)
+
+	var breweries []testBreweryDocument
+	for doc, err := range RowsAs[testBreweryDocument](result) {
+		suite.Require().Nil(err, err)
+		breweries = append(breweries, doc)
+	}
+
+	suite.Assert().Equal(dataset.Results, breweries)
+
+	_, err = result.MetaData()
+	suite.Require().Nil(err, err)
+}
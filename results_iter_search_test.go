@@ -0,0 +1,38 @@
+//go:build go1.23 && !gocb_nosearch
+
+package gocb
+
+import (
+	"github.com/couchbase/gocb/v2/search"
+)
+
+func (suite *UnitTestSuite) TestSearchResultIter() {
+	var dataset testSearchDataset
+	err := loadJSONTestDataset("beer_sample_search_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockSearchRowReader{
+		Dataset: dataset.Hits,
+		Meta:    suite.mustConvertToBytes(dataset.jsonSearchResponse),
+		Suite:   suite,
+	}
+
+	cluster := suite.searchCluster(reader, nil)
+
+	result, err := cluster.SearchQuery("testindex", search.NewTermQuery("term"), nil)
+	suite.Require().Nil(err, err)
+
+	var ids []string
+	for row, err := range result.Iter() {
+		suite.Require().Nil(err, err)
+		ids = append(ids, row.ID)
+	}
+
+	suite.Require().Len(ids, len(dataset.Hits))
+	for i, hit := range dataset.Hits {
+		suite.Assert().Equal(hit.ID, ids[i])
+	}
+
+	_, err = result.MetaData()
+	suite.Require().Nil(err, err)
+}
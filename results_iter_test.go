@@ -0,0 +1,246 @@
+//go:build go1.23
+
+package gocb
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) TestQueryRowsAs() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  suite.mustConvertToBytes(dataset.jsonQueryResponse),
+			Suite: suite,
+		},
+	}
+
+	cluster := suite.queryCluster(false, reader, nil)
+
+	result, err := cluster.Query("SELECT * FROM dataset", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+
+	var breweries []testBreweryDocument
+	for doc, err := range RowsAs[testBreweryDocument](result) {
+		suite.Require().Nil(err, err)
+		breweries = append(breweries, doc)
+	}
+
+	suite.Assert().Equal(dataset.Results, breweries)
+
+	_, err = result.MetaData()
+	suite.Require().Nil(err, err)
+}
+
+func (suite *UnitTestSuite) TestQueryRowsAsStopEarly() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Suite: suite,
+		},
+	}
+
+	cluster := suite.queryCluster(false, reader, nil)
+
+	result, err := cluster.Query("SELECT * FROM dataset", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+
+	var count int
+	for _, err := range RowsAs[testBreweryDocument](result) {
+		suite.Require().Nil(err, err)
+		count++
+		if count == 2 {
+			break
+		}
+	}
+
+	suite.Assert().Equal(2, count)
+}
+
+func (suite *UnitTestSuite) TestQueryRowsAsStreamError() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	expectedErr := errors.New("stream failed")
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results[:1],
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			RowsErr: expectedErr,
+			Suite:   suite,
+		},
+	}
+
+	cluster := suite.queryCluster(false, reader, nil)
+
+	result, err := cluster.Query("SELECT * FROM dataset", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+
+	var rows int
+	var errs []error
+	for _, err := range RowsAs[testBreweryDocument](result) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rows++
+	}
+
+	suite.Assert().Equal(1, rows)
+	suite.Require().Len(errs, 1)
+	suite.Assert().ErrorIs(errs[0], expectedErr)
+}
+
+func (suite *UnitTestSuite) TestQueryRowsAsDecodeError() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Suite: suite,
+		},
+	}
+
+	cluster := suite.queryCluster(false, reader, nil)
+
+	result, err := cluster.Query("SELECT * FROM dataset", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+
+	var calls int
+	for _, err := range RowsAs[int](result) {
+		calls++
+		suite.Assert().Error(err)
+	}
+
+	suite.Assert().Equal(1, calls)
+}
+
+func (suite *UnitTestSuite) TestQueryResultIter() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  suite.mustConvertToBytes(dataset.jsonQueryResponse),
+			Suite: suite,
+		},
+	}
+
+	cluster := suite.queryCluster(false, reader, nil)
+
+	result, err := cluster.Query("SELECT * FROM dataset", &QueryOptions{Adhoc: true})
+	suite.Require().Nil(err, err)
+
+	var breweries []testBreweryDocument
+	for row, err := range result.Iter() {
+		suite.Require().Nil(err, err)
+
+		var doc testBreweryDocument
+		suite.Require().Nil(json.Unmarshal(row, &doc))
+		breweries = append(breweries, doc)
+	}
+
+	suite.Assert().Equal(dataset.Results, breweries)
+
+	_, err = result.MetaData()
+	suite.Require().Nil(err, err)
+}
+
+func (suite *UnitTestSuite) TestScanResultIterStopEarly() {
+	resultCh := make(chan *ScanResultItem, 3)
+	for _, id := range []string{"a", "b", "c"} {
+		resultCh <- &ScanResultItem{id: id}
+	}
+	close(resultCh)
+
+	var cancelErr error
+	result := &ScanResult{
+		resultChan: resultCh,
+		cancelFn: func(err error) {
+			cancelErr = err
+		},
+	}
+
+	var ids []string
+	for item, err := range result.Iter() {
+		suite.Require().Nil(err, err)
+		ids = append(ids, item.ID())
+		if len(ids) == 2 {
+			break
+		}
+	}
+
+	suite.Assert().Equal([]string{"a", "b"}, ids)
+	suite.Assert().ErrorIs(cancelErr, ErrRequestCanceled)
+}
+
+func (suite *UnitTestSuite) TestScanResultIterStreamError() {
+	resultCh := make(chan *ScanResultItem, 1)
+	resultCh <- &ScanResultItem{id: "a"}
+	close(resultCh)
+
+	expectedErr := errors.New("stream failed")
+	result := &ScanResult{
+		resultChan: resultCh,
+		cancelFn:   func(error) {},
+	}
+	result.setErr(expectedErr)
+
+	var ids []string
+	var errs []error
+	for item, err := range result.Iter() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, item.ID())
+	}
+
+	suite.Assert().Equal([]string{"a"}, ids)
+	suite.Require().Len(errs, 1)
+	suite.Assert().ErrorIs(errs[0], expectedErr)
+}
+
+func (suite *UnitTestSuite) TestViewRowsAs() {
+	reader := &mockViewRowReader{
+		Dataset: []jsonViewRow{
+			{ID: "a", Key: json.RawMessage(`"uk"`), Value: json.RawMessage(`1`)},
+			{ID: "b", Key: json.RawMessage(`"us"`), Value: json.RawMessage(`null`)},
+			{ID: "c", Key: json.RawMessage(`"fr"`), Value: json.RawMessage(`"three"`)},
+		},
+		Meta:  []byte(`{"total_rows": 3}`),
+		Suite: suite,
+	}
+	bucket := suite.viewsBucket(reader, func(args mock.Arguments) {})
+
+	result, err := bucket.ViewQuery("ddoc", "view", nil)
+	suite.Require().Nil(err, err)
+
+	var rows []TypedViewRow[string, int]
+	var iterErr error
+	for row, err := range ViewRowsAs[string, int](result) {
+		if err != nil {
+			iterErr = err
+			break
+		}
+		rows = append(rows, row)
+	}
+
+	suite.Assert().Equal([]TypedViewRow[string, int]{{ID: "a", Key: "uk", Value: 1}, {ID: "b", Key: "us"}}, rows)
+	suite.Assert().Error(iterErr)
+}
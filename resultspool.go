@@ -0,0 +1,206 @@
+package gocb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+)
+
+const defaultResultSpoolMemoryBudget = 16 * 1024 * 1024
+
+// ResultSpoolOptions are the options available when spooling the rows of a result.
+// UNCOMMITTED: This API may change in the future.
+type ResultSpoolOptions struct {
+	// MemoryBudget is the maximum number of bytes of row data which will be held in memory, any rows beyond this
+	// are written to a temporary file. Defaults to 16MiB.
+	MemoryBudget int
+
+	// Dir is the directory in which the temporary file is created. Defaults to os.TempDir.
+	Dir string
+}
+
+// spoolRowSource is the common set of functionality across the query, analytics and search row readers.
+type spoolRowSource interface {
+	NextRow() []byte
+	Err() error
+	MetaData() ([]byte, error)
+	Close() error
+}
+
+// rowSpool reads a row source to completion, holding rows in memory up to a budget and writing the remainder to a
+// temporary file. Rows are then replayed in their original order.
+type rowSpool struct {
+	memRows  [][]byte
+	memIdx   int
+	file     *os.File
+	fileRdr  *bufio.Reader
+	spoolErr error
+
+	err      error
+	meta     []byte
+	metaErr  error
+	closeErr error
+}
+
+func newRowSpool(src spoolRowSource, opts *ResultSpoolOptions) *rowSpool {
+	if opts == nil {
+		opts = &ResultSpoolOptions{}
+	}
+
+	budget := opts.MemoryBudget
+	if budget <= 0 {
+		budget = defaultResultSpoolMemoryBudget
+	}
+
+	s := &rowSpool{}
+
+	var fileWriter *bufio.Writer
+	var lenBuf [binary.MaxVarintLen64]byte
+	memUsed := 0
+	for row := src.NextRow(); row != nil; row = src.NextRow() {
+		if s.spoolErr != nil {
+			// We still have to drain the stream so that the metadata becomes available.
+			continue
+		}
+
+		// Once the first row has been written to disk all subsequent rows must be too, to maintain ordering.
+		if s.file == nil && memUsed+len(row) <= budget {
+			s.memRows = append(s.memRows, row)
+			memUsed += len(row)
+			continue
+		}
+
+		if s.file == nil {
+			f, err := os.CreateTemp(opts.Dir, "gocb-spool-*")
+			if err != nil {
+				s.spoolErr = wrapError(err, "failed to create result spool file")
+				continue
+			}
+			s.file = f
+			fileWriter = bufio.NewWriter(f)
+		}
+
+		n := binary.PutUvarint(lenBuf[:], uint64(len(row)))
+		if _, err := fileWriter.Write(lenBuf[:n]); err != nil {
+			s.spoolErr = wrapError(err, "failed to write to result spool file")
+			continue
+		}
+		if _, err := fileWriter.Write(row); err != nil {
+			s.spoolErr = wrapError(err, "failed to write to result spool file")
+		}
+	}
+
+	s.err = src.Err()
+	s.meta, s.metaErr = src.MetaData()
+	s.closeErr = src.Close()
+
+	if s.file != nil && s.spoolErr == nil {
+		if err := fileWriter.Flush(); err != nil {
+			s.spoolErr = wrapError(err, "failed to write to result spool file")
+		} else if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+			s.spoolErr = wrapError(err, "failed to seek result spool file")
+		} else {
+			s.fileRdr = bufio.NewReader(s.file)
+		}
+	}
+
+	return s
+}
+
+func (s *rowSpool) NextRow() []byte {
+	if s.spoolErr != nil {
+		return nil
+	}
+
+	if s.memIdx < len(s.memRows) {
+		row := s.memRows[s.memIdx]
+		s.memRows[s.memIdx] = nil
+		s.memIdx++
+		return row
+	}
+
+	if s.fileRdr == nil {
+		return nil
+	}
+
+	rowLen, err := binary.ReadUvarint(s.fileRdr)
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			s.spoolErr = wrapError(err, "failed to read from result spool file")
+		}
+		s.fileRdr = nil
+		return nil
+	}
+
+	row := make([]byte, rowLen)
+	if _, err := io.ReadFull(s.fileRdr, row); err != nil {
+		s.spoolErr = wrapError(err, "failed to read from result spool file")
+		s.fileRdr = nil
+		return nil
+	}
+
+	return row
+}
+
+func (s *rowSpool) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+
+	return s.spoolErr
+}
+
+func (s *rowSpool) MetaData() ([]byte, error) {
+	return s.meta, s.metaErr
+}
+
+func (s *rowSpool) Close() error {
+	s.memRows = nil
+	s.fileRdr = nil
+	if s.file != nil {
+		name := s.file.Name()
+		if err := s.file.Close(); err != nil {
+			logDebugf("failed to close result spool file: %s", err)
+		}
+		if err := os.Remove(name); err != nil {
+			logDebugf("failed to remove result spool file: %s", err)
+		}
+		s.file = nil
+	}
+
+	if s.closeErr != nil {
+		return s.closeErr
+	}
+
+	return s.spoolErr
+}
+
+// spooledQueryRowReader is a rowSpool which also retains the query specific details of the original reader.
+type spooledQueryRowReader struct {
+	*rowSpool
+
+	preparedName    string
+	preparedNameErr error
+	endpoint        string
+}
+
+func newSpooledQueryRowReader(src queryRowReader, opts *ResultSpoolOptions) *spooledQueryRowReader {
+	spool := newRowSpool(src, opts)
+	preparedName, preparedNameErr := src.PreparedName()
+	return &spooledQueryRowReader{
+		rowSpool:        spool,
+		preparedName:    preparedName,
+		preparedNameErr: preparedNameErr,
+		endpoint:        src.Endpoint(),
+	}
+}
+
+func (s *spooledQueryRowReader) PreparedName() (string, error) {
+	return s.preparedName, s.preparedNameErr
+}
+
+func (s *spooledQueryRowReader) Endpoint() string {
+	return s.endpoint
+}
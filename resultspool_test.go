@@ -0,0 +1,102 @@
+package gocb
+
+import (
+	"errors"
+	"os"
+)
+
+func (suite *UnitTestSuite) TestQueryResultSpool() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	dir := suite.T().TempDir()
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  suite.mustConvertToBytes(dataset.jsonQueryResponse),
+			PName: "p1",
+			Suite: suite,
+		},
+	}
+	result := newQueryResult(reader)
+
+	// A small budget forces most of the rows to be written to disk.
+	err = result.Spool(&ResultSpoolOptions{MemoryBudget: 200, Dir: dir})
+	suite.Require().Nil(err, err)
+
+	entries, err := os.ReadDir(dir)
+	suite.Require().Nil(err, err)
+	suite.Require().Len(entries, 1)
+
+	metadata, err := result.MetaData()
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(dataset.jsonQueryResponse.RequestID, metadata.RequestID)
+
+	var docs []testBreweryDocument
+	for result.Next() {
+		var doc testBreweryDocument
+		suite.Require().Nil(result.Row(&doc))
+		docs = append(docs, doc)
+	}
+	suite.Assert().Equal(dataset.Results, docs)
+	suite.Require().Nil(result.Err())
+	suite.Require().Nil(result.Close())
+
+	name, err := result.reader.PreparedName()
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal("p1", name)
+
+	entries, err = os.ReadDir(dir)
+	suite.Require().Nil(err, err)
+	suite.Assert().Empty(entries)
+}
+
+func (suite *UnitTestSuite) TestQueryResultSpoolInMemory() {
+	var dataset testQueryDataset
+	err := loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	dir := suite.T().TempDir()
+	reader := &mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  suite.mustConvertToBytes(dataset.jsonQueryResponse),
+			Suite: suite,
+		},
+	}
+	result := newQueryResult(reader)
+
+	err = result.Spool(&ResultSpoolOptions{Dir: dir})
+	suite.Require().Nil(err, err)
+
+	entries, err := os.ReadDir(dir)
+	suite.Require().Nil(err, err)
+	suite.Assert().Empty(entries)
+
+	var count int
+	for result.Next() {
+		count++
+	}
+	suite.Assert().Equal(len(dataset.Results), count)
+	suite.Require().Nil(result.Close())
+}
+
+func (suite *UnitTestSuite) TestQueryResultSpoolErrors() {
+	closeErr := errors.New("close error")
+	reader := &mockQueryRowReader{
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			RowsErr:  errors.New("rows error"),
+			CloseErr: closeErr,
+			Suite:    suite,
+		},
+	}
+	result := newQueryResult(reader)
+
+	err := result.Spool(nil)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().False(result.Next())
+	suite.Assert().EqualError(result.Err(), "rows error")
+	suite.Assert().Equal(closeErr, result.Close())
+}
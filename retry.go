@@ -1,15 +1,8 @@
 package gocb
 
 import (
-	"context"
-	"errors"
-	"go.opentelemetry.io/otel/trace"
 	"time"
 
-	"google.golang.org/grpc"
-
-	"github.com/google/uuid"
-
 	"github.com/couchbase/gocbcore/v10"
 )
 
@@ -93,6 +86,10 @@ var (
 
 	// NotReadyRetryReason indicates the SDK connections are not setup and ready to be used.
 	NotReadyRetryReason = RetryReason(gocbcore.NotReadyRetryReason)
+
+	// PollConditionNotMetRetryReason indicates that the condition being polled for by PollUntil has not yet been met.
+	// UNCOMMITTED: This API may change in the future.
+	PollConditionNotMetRetryReason = RetryReason(pollRetryReason{})
 )
 
 // RetryAction is used by a RetryStrategy to calculate the duration to wait before retrying an operation.
@@ -204,164 +201,3 @@ type retriedRequestInfo interface {
 	RetryAttempts() uint32
 	RetryReasons() []RetryReason
 }
-
-type retriableRequestPs struct {
-	// reasons is effectively a set, so we can't just use len(reasons) for num attempts.
-	reasons  []RetryReason
-	attempts uint32
-
-	operation        string
-	traceIdentifier  string
-	loggerIdentifier string
-	idempotent       bool
-	strategy         RetryStrategy
-	parentSpan       RequestSpan
-}
-
-func newRetriableRequestPS(operation string, idempotent bool, parentSpan RequestSpan, traceIdentifier string,
-	strategy RetryStrategy) *retriableRequestPs {
-	loggerIdentifier := traceIdentifier
-	if loggerIdentifier == "" {
-		loggerIdentifier = uuid.NewString()[:6]
-	}
-	return &retriableRequestPs{
-		operation:        operation,
-		traceIdentifier:  traceIdentifier,
-		loggerIdentifier: loggerIdentifier,
-		idempotent:       idempotent,
-		parentSpan:       parentSpan,
-		strategy:         strategy,
-	}
-}
-
-func (w *retriableRequestPs) RetryAttempts() uint32 {
-	return w.attempts
-}
-
-func (w *retriableRequestPs) Identifier() string {
-	return w.loggerIdentifier
-}
-
-func (w *retriableRequestPs) Idempotent() bool {
-	return w.idempotent
-}
-
-func (w *retriableRequestPs) RetryReasons() []RetryReason {
-	return w.reasons
-}
-
-func (w *retriableRequestPs) Operation() string {
-	return w.operation
-}
-
-func (w *retriableRequestPs) retryStrategy() RetryStrategy {
-	return w.strategy
-}
-
-func (w *retriableRequestPs) recordRetryAttempt(reason RetryReason) {
-	w.attempts++
-	found := false
-	for i := 0; i < len(w.reasons); i++ {
-		if w.reasons[i] == reason {
-			found = true
-			break
-		}
-	}
-
-	// if idx is out of the range of retryReasons then it wasn't found.
-	if !found {
-		w.reasons = append(w.reasons, reason)
-	}
-}
-
-func handleRetriableRequest[ReqT any, RespT any](
-	ctx context.Context, createdTime time.Time, tracer *tracerWrapper,
-	req ReqT,
-	retryReq *retriableRequestPs,
-	sendFn func(context.Context, ReqT, ...grpc.CallOption) (RespT, error),
-	retryReasonFn func(err error) RetryReason,
-	peekResult func(RespT) error) (RespT, error) {
-	for {
-		logSchedf("Writing request ID=%s, OP=%s", retryReq.loggerIdentifier, retryReq.operation)
-
-		if s, ok := retryReq.parentSpan.(OtelAwareRequestSpan); ok {
-			ctx = trace.ContextWithSpan(ctx, s.Wrapped())
-		}
-
-		res, err := sendFn(ctx, req)
-		logSchedf("Handling response ID=%s, OP=%s", retryReq.loggerIdentifier, retryReq.operation)
-
-		if err != nil {
-			// If handleRetriableRequestError doesn't return an error then it's a signal to retry.
-			if gocbErr := handleRetriableRequestError(ctx, createdTime, err, retryReq, retryReasonFn); gocbErr != nil {
-				var emptyResp RespT
-				return emptyResp, gocbErr
-			}
-
-			continue
-		}
-
-		if peekResult != nil {
-			if err := peekResult(res); err != nil {
-				if gocbErr := handleRetriableRequestError(ctx, createdTime, err, retryReq, retryReasonFn); gocbErr != nil {
-					var emptyResp RespT
-					return emptyResp, gocbErr
-				}
-
-				continue
-			}
-		}
-
-		return res, nil
-	}
-}
-
-func handleRetriableRequestError(
-	ctx context.Context,
-	createdTime time.Time,
-	err error,
-	retryReq *retriableRequestPs,
-	retryReasonFn func(err error) RetryReason,
-) error {
-	gocbErr := mapPsErrorToGocbError(err, retryReq.Idempotent())
-
-	if errors.Is(gocbErr, ErrTimeout) {
-		return &TimeoutError{
-			InnerError:    gocbErr,
-			OperationID:   retryReq.Operation(),
-			Opaque:        retryReq.Identifier(),
-			TimeObserved:  time.Since(createdTime),
-			RetryReasons:  retryReq.RetryReasons(),
-			RetryAttempts: retryReq.RetryAttempts(),
-		}
-	}
-
-	retryReason := retryReasonFn(gocbErr)
-	if retryReason == nil {
-		return gocbErr
-	}
-
-	shouldRetry, retryWait := retryOrchMaybeRetry(retryReq, retryReason)
-	if !shouldRetry {
-		return gocbErr
-	}
-
-	select {
-	case <-time.After(time.Until(retryWait)):
-		return nil
-	case <-ctx.Done():
-		err := ctx.Err()
-		if errors.Is(err, context.DeadlineExceeded) {
-			return &TimeoutError{
-				InnerError:    ErrUnambiguousTimeout,
-				OperationID:   retryReq.Operation(),
-				Opaque:        retryReq.Identifier(),
-				TimeObserved:  time.Since(createdTime),
-				RetryReasons:  retryReq.RetryReasons(),
-				RetryAttempts: retryReq.RetryAttempts(),
-			}
-		} else {
-			return makeGenericError(ErrRequestCanceled, nil)
-		}
-	}
-}
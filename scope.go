@@ -1,5 +1,10 @@
 package gocb
 
+import (
+	"sync"
+	"time"
+)
+
 // Scope represents a single scope within a bucket.
 type Scope struct {
 	scopeName string
@@ -10,8 +15,12 @@ type Scope struct {
 	transcoder           Transcoder
 	retryStrategyWrapper *coreRetryStrategyWrapper
 	compressor           *compressor
+	warningHandler       WarningHandler
 
-	useMutationTokens bool
+	useMutationTokens         bool
+	allowSystemScopeMutations bool
+	readOnlyMode              bool
+	timeSource                func() time.Time
 
 	keyspace keyspace
 
@@ -26,6 +35,9 @@ type Scope struct {
 	getAnalyticsProvider          func() (analyticsProvider, error)
 	getEventingManagementProvider func() (eventingManagementProvider, error)
 	getTransactions               func() *Transactions
+
+	// collections caches the Collection handles returned by Collection, keyed by name.
+	collections sync.Map
 }
 
 func newScope(bucket *Bucket, scopeName string) *Scope {
@@ -38,8 +50,12 @@ func newScope(bucket *Bucket, scopeName string) *Scope {
 		transcoder:           bucket.transcoder,
 		retryStrategyWrapper: bucket.retryStrategyWrapper,
 		compressor:           bucket.compressor,
+		warningHandler:       bucket.warningHandler,
 
-		useMutationTokens: bucket.useMutationTokens,
+		useMutationTokens:         bucket.useMutationTokens,
+		allowSystemScopeMutations: bucket.allowSystemScopeMutations,
+		readOnlyMode:              bucket.readOnlyMode,
+		timeSource:                bucket.timeSource,
 
 		keyspace: keyspace{
 			bucketName: bucket.Name(),
@@ -72,8 +88,30 @@ func (s *Scope) BucketName() string {
 }
 
 // Collection returns an instance of a collection.
+//
+// The same Collection is returned for each call with the same name on this Scope. Cluster.Bucket returns a new
+// Bucket on each call, so Collections obtained through different Bucket instances should be compared using
+// Collection.Equal.
 func (s *Scope) Collection(collectionName string) *Collection {
-	return newCollection(s, collectionName)
+	if collection, ok := s.collections.Load(collectionName); ok {
+		return collection.(*Collection)
+	}
+
+	collection, _ := s.collections.LoadOrStore(collectionName, newCollection(s, collectionName))
+	return collection.(*Collection)
+}
+
+// Equal returns whether both scopes refer to the same scope within the same bucket.
+// UNCOMMITTED: This API may change in the future.
+func (s *Scope) Equal(other *Scope) bool {
+	if s == other {
+		return true
+	}
+	if s == nil || other == nil {
+		return false
+	}
+
+	return s.BucketName() == other.BucketName() && s.Name() == other.Name()
 }
 
 // SearchIndexes returns a ScopeSearchIndexManager for managing scope-level search indexes.
@@ -92,6 +130,23 @@ func (s *Scope) SearchIndexes() *ScopeSearchIndexManager {
 	}
 }
 
+// QueryIndexes returns a ScopeQueryIndexManager for managing query indexes across all collections within the scope.
+// UNCOMMITTED: This API may change in the future.
+func (s *Scope) QueryIndexes() *ScopeQueryIndexManager {
+	return &ScopeQueryIndexManager{
+		controller: &providerController[queryIndexProvider]{
+			get:          s.getQueryIndexProvider,
+			opController: s.opController,
+
+			meter:    s.bucket.connectionManager.getMeter(),
+			keyspace: &s.keyspace,
+			service:  serviceValueManagement,
+		},
+
+		s: s,
+	}
+}
+
 // EventingFunctions returns a ScopeEventingFunctionManager for managing scope-level eventing functions.
 //
 // # UNCOMMITTED
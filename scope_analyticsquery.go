@@ -1,6 +1,8 @@
 package gocb
 
 // AnalyticsQuery executes the analytics query statement on the server, constraining the query to the bucket and scope.
+// The query context is set to the scope, so analytics collections mapped to collections within the scope can be
+// referred to by their collection name alone.
 func (s *Scope) AnalyticsQuery(statement string, opts *AnalyticsOptions) (*AnalyticsResult, error) {
 	return autoOpControl(s.analyticsController(), "analytics", func(provider analyticsProvider) (*AnalyticsResult, error) {
 		if opts == nil {
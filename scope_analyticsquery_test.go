@@ -1,8 +1,14 @@
+//go:build !gocb_noanalytics
+
 package gocb
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/stretchr/testify/mock"
 )
 
 func (suite *IntegrationTestSuite) TestScopeAnalyticsQuery() {
@@ -36,3 +42,44 @@ func (suite *IntegrationTestSuite) setupScopeAnalytics() int {
 
 	return n
 }
+
+func (suite *UnitTestSuite) TestScopeAnalyticsQueryContext() {
+	statement := "SELECT * FROM airline"
+
+	analyticsProviderCoreProvider := new(mockAnalyticsProviderCoreProvider)
+	analyticsProviderCoreProvider.
+		On("AnalyticsQuery", nil, mock.AnythingOfType("gocbcore.AnalyticsQueryOptions")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(1).(gocbcore.AnalyticsQueryOptions)
+
+			var actualOptions map[string]interface{}
+			err := json.Unmarshal(opts.Payload, &actualOptions)
+			suite.Require().Nil(err)
+
+			suite.Assert().Equal(statement, actualOptions["statement"])
+			suite.Assert().Equal("default:`travel-sample`.`inventory`", actualOptions["query_context"])
+		}).
+		Return(new(mockAnalyticsRowReader), nil)
+
+	analyticsProvider := &analyticsProviderCore{
+		provider:             analyticsProviderCoreProvider,
+		tracer:               newTracerWrapper(&NoopTracer{}),
+		retryStrategyWrapper: newCoreRetryStrategyWrapper(NewBestEffortRetryStrategy(nil)),
+		analyticsTimeout:     75 * time.Second,
+	}
+
+	cli := new(mockConnectionManager)
+	cli.On("getAnalyticsProvider").Return(analyticsProvider, nil)
+	cli.On("getMeter").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	b := suite.bucket("travel-sample", TimeoutsConfig{AnalyticsTimeout: 75 * time.Second}, cli)
+	scope := suite.newScope(b, "inventory")
+
+	result, err := scope.AnalyticsQuery(statement, nil)
+	suite.Require().Nil(err, err)
+	suite.Require().NotNil(result)
+
+	analyticsProviderCoreProvider.AssertExpectations(suite.T())
+}
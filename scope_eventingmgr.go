@@ -1,5 +1,7 @@
 package gocb
 
+import "encoding/json"
+
 // ScopeEventingFunctionManager provides methods for performing scoped eventing function management operations.
 // This manager is designed to work only against Couchbase Server 7.1+.
 //
@@ -13,8 +15,14 @@ type ScopeEventingFunctionManager struct {
 }
 
 // UpsertFunction inserts or updates an eventing function.
+// If the Version or Settings.LanguageCompatibility of the function are not set then they are set to those supported
+// by the oldest eventing node in the cluster, where this can be determined.
 func (efm *ScopeEventingFunctionManager) UpsertFunction(function EventingFunction, opts *UpsertEventingFunctionOptions) error {
 	return autoOpControlErrorOnly(efm.controller, "manager_eventing_upsert_function", func(provider eventingManagementProvider) error {
+		if err := validateEventingFunctionSettings(function.Settings); err != nil {
+			return err
+		}
+
 		if opts == nil {
 			opts = &UpsertEventingFunctionOptions{}
 		}
@@ -110,3 +118,75 @@ func (efm *ScopeEventingFunctionManager) FunctionsStatus(opts *EventingFunctions
 		return provider.FunctionsStatus(efm.scope, opts)
 	})
 }
+
+// ExportFunctions fetches the definitions of all eventing functions in the scope, as exported by the eventing
+// service. Each function is returned exactly as exported, including any fields which are not known to this SDK.
+// UNCOMMITTED: This API may change in the future.
+func (efm *ScopeEventingFunctionManager) ExportFunctions(opts *ExportEventingFunctionsOptions) ([]json.RawMessage, error) {
+	return autoOpControl(efm.controller, "manager_eventing_export_functions", func(provider eventingManagementProvider) ([]json.RawMessage, error) {
+		if opts == nil {
+			opts = &ExportEventingFunctionsOptions{}
+		}
+
+		return provider.ExportFunctions(efm.scope, opts)
+	})
+}
+
+// ImportFunctions creates or replaces eventing functions in the scope from their definitions, such as those
+// returned by ExportFunctions. Imported functions are always created in an undeployed state.
+// UNCOMMITTED: This API may change in the future.
+func (efm *ScopeEventingFunctionManager) ImportFunctions(functions []json.RawMessage, opts *ImportEventingFunctionsOptions) error {
+	return autoOpControlErrorOnly(efm.controller, "manager_eventing_import_functions", func(provider eventingManagementProvider) error {
+		if len(functions) == 0 {
+			return makeInvalidArgumentsError("at least one function must be provided")
+		}
+
+		if opts == nil {
+			opts = &ImportEventingFunctionsOptions{}
+		}
+
+		return provider.ImportFunctions(efm.scope, functions, opts)
+	})
+}
+
+// ValidateFunction asks the eventing service to compile an eventing function without storing it, returning
+// ErrEventingFunctionCompilationFailure if the code fails to compile, or ErrFeatureNotAvailable if the eventing
+// service does not support validation.
+// UNCOMMITTED: This API may change in the future.
+func (efm *ScopeEventingFunctionManager) ValidateFunction(function EventingFunction, opts *ValidateEventingFunctionOptions) error {
+	return autoOpControlErrorOnly(efm.controller, "manager_eventing_validate_function", func(provider eventingManagementProvider) error {
+		if function.Name == "" {
+			return makeInvalidArgumentsError("function name cannot be empty")
+		}
+
+		if opts == nil {
+			opts = &ValidateEventingFunctionOptions{}
+		}
+
+		return provider.ValidateFunction(efm.scope, function, opts)
+	})
+}
+
+// UpdateFunctionSettings updates the settings of an existing eventing function, without changing its code or
+// bindings. The settings are typically those returned by GetFunction, modified as required. Zero valued numeric,
+// string and duration settings leave the existing setting unchanged, whilst boolean settings are always applied.
+// DeploymentStatus and ProcessingStatus are ignored, DeployFunction, UndeployFunction, PauseFunction and
+// ResumeFunction should be used to change them instead.
+// UNCOMMITTED: This API may change in the future.
+func (efm *ScopeEventingFunctionManager) UpdateFunctionSettings(name string, settings EventingFunctionSettings,
+	opts *UpdateEventingFunctionSettingsOptions) error {
+	return autoOpControlErrorOnly(efm.controller, "manager_eventing_update_function_settings", func(provider eventingManagementProvider) error {
+		if name == "" {
+			return makeInvalidArgumentsError("function name cannot be empty")
+		}
+		if err := validateEventingFunctionSettings(settings); err != nil {
+			return err
+		}
+
+		if opts == nil {
+			opts = &UpdateEventingFunctionSettingsOptions{}
+		}
+
+		return provider.UpdateFunctionSettings(efm.scope, name, settings, opts)
+	})
+}
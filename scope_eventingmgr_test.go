@@ -1,3 +1,5 @@
+//go:build !gocb_noeventing
+
 package gocb
 
 import (
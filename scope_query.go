@@ -14,3 +14,71 @@ func (s *Scope) Query(statement string, opts *QueryOptions) (*QueryResult, error
 		return provider.Query(statement, s, opts)
 	})
 }
+
+// QuerySingleTransaction executes the query statement on the server as a single query transaction, constraining the
+// query to the bucket and scope. See Cluster.QuerySingleTransaction for details.
+// UNCOMMITTED: This API may change in the future.
+func (s *Scope) QuerySingleTransaction(statement string, opts *QueryOptions) (*QueryResult, error) {
+	return s.Query(statement, singleTransactionQueryOptions(opts))
+}
+
+// QueryCallback executes the query statement on the server, constraining the query to the bucket and scope,
+// invoking rowFn with the raw JSON bytes of each row as it is read from the response stream.
+// See Cluster.QueryCallback for details.
+// UNCOMMITTED: This API may change in the future.
+func (s *Scope) QueryCallback(statement string, opts *QueryOptions, rowFn func(row []byte) error) (*QueryMetaData, error) {
+	result, err := s.Query(statement, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.forEachRow(rowFn)
+}
+
+// QueryInTransactionResult is the result of QueryInTransaction.
+// UNCOMMITTED: This API may change in the future.
+type QueryInTransactionResult[T any] struct {
+	Rows          []T
+	MetaData      *QueryMetaData
+	TransactionID string
+}
+
+// QueryInTransaction executes the query statement on the server as a single query transaction, constraining the
+// query to the bucket and scope, and decodes every row into a value of type T. See Cluster.QuerySingleTransaction for
+// details. As errors from a single query transaction can occur whilst reading rows, all rows are read before
+// returning so that the transaction is known to have succeeded.
+// UNCOMMITTED: This API may change in the future.
+func QueryInTransaction[T any](scope *Scope, statement string, opts *QueryOptions) (*QueryInTransactionResult[T], error) {
+	if scope == nil {
+		return nil, makeInvalidArgumentsError("scope cannot be nil")
+	}
+
+	result, err := scope.QuerySingleTransaction(statement, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectQueryRows[T](result)
+}
+
+func collectQueryRows[T any](result *QueryResult) (*QueryInTransactionResult[T], error) {
+	rows := make([]T, 0)
+	meta, err := result.forEachRow(func(rowBytes []byte) error {
+		var row T
+		if err := result.deserialize(rowBytes, &row); err != nil {
+			return err
+		}
+
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryInTransactionResult[T]{
+		Rows:          rows,
+		MetaData:      meta,
+		TransactionID: result.TransactionID(),
+	}, nil
+}
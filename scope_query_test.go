@@ -271,3 +271,29 @@ func (suite *IntegrationTestSuite) TestScopeQueryTransactionDoubleInsert() {
 		suite.Assert().ErrorAs(err, &tErr)
 	}
 }
+
+func (suite *UnitTestSuite) TestQueryInTransaction() {
+	_, err := QueryInTransaction[testBreweryDocument](nil, "SELECT 1", nil)
+	suite.Require().ErrorIs(err, ErrInvalidArgument)
+
+	var dataset testQueryDataset
+	err = loadJSONTestDataset("beer_sample_query_dataset", &dataset)
+	suite.Require().Nil(err, err)
+
+	result := newQueryResult(&mockQueryRowReader{
+		Dataset: dataset.Results,
+		mockQueryRowReaderBase: mockQueryRowReaderBase{
+			Meta:  suite.mustConvertToBytes(dataset.jsonQueryResponse),
+			Suite: suite,
+		},
+	})
+	result.transactionID = "txid"
+
+	res, err := collectQueryRows[testBreweryDocument](result)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal(dataset.Results, res.Rows)
+	suite.Assert().Equal("txid", res.TransactionID)
+	suite.Require().NotNil(res.MetaData)
+	suite.Assert().Equal(dataset.RequestID, res.MetaData.RequestID)
+}
@@ -0,0 +1,56 @@
+package gocb
+
+import (
+	"time"
+)
+
+// ScopeQueryIndexManager provides methods for performing Couchbase query index management across all of the
+// collections within a scope.
+// UNCOMMITTED: This API may change in the future.
+type ScopeQueryIndexManager struct {
+	controller *providerController[queryIndexProvider]
+
+	s *Scope
+}
+
+func (qm *ScopeQueryIndexManager) validateScopeCollection(scope, collection string) error {
+	if scope != "" || collection != "" {
+		return makeInvalidArgumentsError("cannot use scope or collection with scope query index manager")
+	}
+	return nil
+}
+
+// GetAllIndexes returns a list of all currently registered indexes on all collections within the scope.
+func (qm *ScopeQueryIndexManager) GetAllIndexes(opts *GetAllQueryIndexesOptions) ([]QueryIndex, error) {
+	return autoOpControl(qm.controller, "manager_query_get_all_indexes", func(provider queryIndexProvider) ([]QueryIndex, error) {
+		if opts == nil {
+			opts = &GetAllQueryIndexesOptions{}
+		}
+		if err := qm.validateScopeCollection(opts.ScopeName, opts.CollectionName); err != nil {
+			return nil, err
+		}
+
+		scopeOpts := *opts
+		scopeOpts.ScopeName = qm.s.Name()
+
+		return provider.GetAllIndexes(nil, qm.s.BucketName(), &scopeOpts)
+	})
+}
+
+// WatchIndexes waits for a set of indexes, on any of the collections within the scope, to come online. As index
+// names are only unique within a collection, every index within the scope with a watched name must be online.
+func (qm *ScopeQueryIndexManager) WatchIndexes(watchList []string, timeout time.Duration, opts *WatchQueryIndexOptions) error {
+	return autoOpControlErrorOnly(qm.controller, "manager_query_watch_indexes", func(provider queryIndexProvider) error {
+		if opts == nil {
+			opts = &WatchQueryIndexOptions{}
+		}
+		if err := qm.validateScopeCollection(opts.ScopeName, opts.CollectionName); err != nil {
+			return err
+		}
+
+		scopeOpts := *opts
+		scopeOpts.ScopeName = qm.s.Name()
+
+		return provider.WatchIndexes(nil, qm.s.BucketName(), watchList, timeout, &scopeOpts)
+	})
+}
@@ -0,0 +1,93 @@
+package gocb
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) scopeQueryIndexManager(provider *mockQueryProviderCoreProvider) *ScopeQueryIndexManager {
+	return &ScopeQueryIndexManager{
+		controller: &providerController[queryIndexProvider]{
+			get: func() (queryIndexProvider, error) {
+				return &queryProviderCore{
+					provider: provider,
+					tracer:   newTracerWrapper(&NoopTracer{}),
+				}, nil
+			},
+			opController: mockOpController{},
+		},
+		s: &Scope{scopeName: "inventory", bucket: &Bucket{bucketName: "travel-sample"}},
+	}
+}
+
+func (suite *UnitTestSuite) TestScopeQueryIndexesGetAllIndexes() {
+	reader := &mockQueryIndexRowReader{
+		Dataset: []map[string]interface{}{
+			{"name": "idx_name", "keyspace_id": "airline", "bucket_id": "travel-sample", "scope_id": "inventory", "state": "online", "using": "gsi"},
+			{"name": "idx_name", "keyspace_id": "hotel", "bucket_id": "travel-sample", "scope_id": "inventory", "state": "online", "using": "gsi"},
+		},
+		mockQueryRowReaderBase: mockQueryRowReaderBase{Suite: suite},
+	}
+
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("N1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Run(func(args mock.Arguments) {
+			opts := args.Get(1).(gocbcore.N1QLQueryOptions)
+
+			var payload map[string]interface{}
+			suite.Require().Nil(json.Unmarshal(opts.Payload, &payload))
+
+			suite.Assert().Equal("travel-sample", payload["$bucketName"])
+			suite.Assert().Equal("inventory", payload["$scopeName"])
+			suite.Assert().NotContains(payload, "$collectionName")
+			suite.Assert().NotContains(payload["statement"], "bucket_id IS MISSING")
+			suite.Assert().NotContains(payload, "query_context")
+		}).
+		Return(reader, nil).
+		Once()
+
+	indexes, err := suite.scopeQueryIndexManager(provider).GetAllIndexes(nil)
+	suite.Require().Nil(err, err)
+
+	suite.Require().Len(indexes, 2)
+	suite.Assert().Equal("airline", indexes[0].CollectionName)
+	suite.Assert().Equal("hotel", indexes[1].CollectionName)
+}
+
+func (suite *UnitTestSuite) TestScopeQueryIndexesWatchIndexes() {
+	newReader := func(hotelState string) *mockQueryIndexRowReader {
+		return &mockQueryIndexRowReader{
+			Dataset: []map[string]interface{}{
+				{"name": "idx_name", "keyspace_id": "airline", "bucket_id": "travel-sample", "scope_id": "inventory", "state": "online", "using": "gsi"},
+				{"name": "idx_name", "keyspace_id": "hotel", "bucket_id": "travel-sample", "scope_id": "inventory", "state": hotelState, "using": "gsi"},
+			},
+			mockQueryRowReaderBase: mockQueryRowReaderBase{Suite: suite},
+		}
+	}
+
+	provider := new(mockQueryProviderCoreProvider)
+	provider.
+		On("N1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Return(newReader("building"), nil).
+		Once()
+	provider.
+		On("N1QLQuery", nil, mock.AnythingOfType("gocbcore.N1QLQueryOptions")).
+		Return(newReader("online"), nil).
+		Once()
+
+	// The index on hotel is still building after the first poll, so a second poll is required.
+	err := suite.scopeQueryIndexManager(provider).WatchIndexes([]string{"idx_name"}, 5*time.Second, nil)
+	suite.Require().Nil(err, err)
+
+	provider.AssertExpectations(suite.T())
+}
+
+func (suite *UnitTestSuite) TestScopeQueryIndexesRejectsScopeName() {
+	_, err := suite.scopeQueryIndexManager(new(mockQueryProviderCoreProvider)).
+		GetAllIndexes(&GetAllQueryIndexesOptions{ScopeName: "other"})
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
@@ -81,6 +81,16 @@ func (sm *ScopeSearchIndexManager) AnalyzeDocument(indexName string, doc interfa
 	})
 }
 
+// EnsureIndex creates the index if it does not already exist, and then waits until the index can be queried.
+// UNCOMMITTED: This API may change in the future.
+func (sm *ScopeSearchIndexManager) EnsureIndex(indexDefinition SearchIndex, opts *EnsureSearchIndexOptions) error {
+	if opts == nil {
+		opts = &EnsureSearchIndexOptions{}
+	}
+
+	return ensureSearchIndex(sm, indexDefinition, opts)
+}
+
 // GetIndexedDocumentsCount retrieves the document count for a search index.
 func (sm *ScopeSearchIndexManager) GetIndexedDocumentsCount(indexName string, opts *GetIndexedDocumentsCountOptions) (uint64, error) {
 	return autoOpControl(sm.controller, "manager_search_get_indexed_documents_count", func(provider searchIndexProvider) (uint64, error) {
@@ -96,6 +106,42 @@ func (sm *ScopeSearchIndexManager) GetIndexedDocumentsCount(indexName string, op
 	})
 }
 
+// GetIndexedDocumentsCountByPartition retrieves the document count for each partition of a search index, which can
+// be used to monitor the progress of an index build. The partitions are gathered from every search node.
+// UNCOMMITTED: This API may change in the future.
+func (sm *ScopeSearchIndexManager) GetIndexedDocumentsCountByPartition(indexName string,
+	opts *GetIndexedDocumentsCountByPartitionOptions) (*SearchIndexedDocumentsCount, error) {
+	return autoOpControl(sm.controller, "manager_search_get_indexed_documents_count_by_partition", func(provider searchIndexProvider) (*SearchIndexedDocumentsCount, error) {
+		if opts == nil {
+			opts = &GetIndexedDocumentsCountByPartitionOptions{}
+		}
+
+		if indexName == "" {
+			return nil, invalidArgumentsError{"indexName cannot be empty"}
+		}
+
+		return provider.GetIndexedDocumentsCountByPartition(sm.scope, indexName, opts)
+	})
+}
+
+// GetIndexStats retrieves the statistics of a search index, such as doc_count and num_mutations_to_index, keyed by
+// name. The statistics are gathered from every search node, with numeric statistics summed across the nodes. The set
+// of statistics returned depends upon the server version.
+// UNCOMMITTED: This API may change in the future.
+func (sm *ScopeSearchIndexManager) GetIndexStats(indexName string, opts *GetSearchIndexStatsOptions) (map[string]interface{}, error) {
+	return autoOpControl(sm.controller, "manager_search_get_stats", func(provider searchIndexProvider) (map[string]interface{}, error) {
+		if opts == nil {
+			opts = &GetSearchIndexStatsOptions{}
+		}
+
+		if indexName == "" {
+			return nil, invalidArgumentsError{"indexName cannot be empty"}
+		}
+
+		return provider.GetIndexStats(sm.scope, indexName, opts)
+	})
+}
+
 // PauseIngest pauses updates and maintenance for an index.
 func (sm *ScopeSearchIndexManager) PauseIngest(indexName string, opts *PauseIngestSearchIndexOptions) error {
 	return autoOpControlErrorOnly(sm.controller, "manager_search_pause_ingest", func(provider searchIndexProvider) error {
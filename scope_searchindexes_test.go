@@ -1,3 +1,5 @@
+//go:build !gocb_nosearch
+
 package gocb
 
 import (
@@ -339,6 +341,14 @@ func (suite *UnitTestSuite) TestScopeSearchIndexesFeatureNotAvailable() {
 		_, err := mgr.GetIndexedDocumentsCount(indexName, nil)
 		suite.Require().ErrorIs(err, ErrFeatureNotAvailable)
 	})
+	suite.Run("GetIndexedDocumentsCountByPartition", func() {
+		_, err := mgr.GetIndexedDocumentsCountByPartition(indexName, nil)
+		suite.Require().ErrorIs(err, ErrFeatureNotAvailable)
+	})
+	suite.Run("GetIndexStats", func() {
+		_, err := mgr.GetIndexStats(indexName, nil)
+		suite.Require().ErrorIs(err, ErrFeatureNotAvailable)
+	})
 	suite.Run("PauseIngest", func() {
 		err := mgr.PauseIngest(indexName, nil)
 		suite.Require().ErrorIs(err, ErrFeatureNotAvailable)
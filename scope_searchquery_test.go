@@ -1,3 +1,5 @@
+//go:build !gocb_nosearch
+
 package gocb
 
 import (
@@ -567,13 +567,23 @@ func (q *WildcardQuery) Boost(boost float32) *WildcardQuery {
 
 // DocIDQuery represents a search document id query.
 type DocIDQuery struct {
-	ids   []string
-	field *string
-	boost *float32
+	ids        []string
+	boostedIDs []docIDBoost
+	field      *string
+	boost      *float32
+}
+
+type docIDBoost struct {
+	id    string
+	boost float32
 }
 
 // MarshalJSON marshal's this query to JSON for the search REST API.
 func (q DocIDQuery) MarshalJSON() ([]byte, error) {
+	if len(q.boostedIDs) > 0 {
+		return json.Marshal(q.toDisjunction())
+	}
+
 	outStruct := &struct {
 		IDs   []string `json:"ids"`
 		Field *string  `json:"field,omitempty"`
@@ -602,6 +612,30 @@ func (q *DocIDQuery) AddDocIds(ids ...string) *DocIDQuery {
 	return q
 }
 
+// AddBoostedDocID adds a document id to this query, boosting the score of that document relative to the others
+// matched by the query.
+// UNCOMMITTED: This API may change in the future.
+func (q *DocIDQuery) AddBoostedDocID(id string, boost float32) *DocIDQuery {
+	q.boostedIDs = append(q.boostedIDs, docIDBoost{id: id, boost: boost})
+	return q
+}
+
+// toDisjunction expresses a query with boosted document ids, which the server does not support directly, as a
+// disjunction of document id queries. Any boost applied to the whole query is applied to the disjunction.
+func (q *DocIDQuery) toDisjunction() *DisjunctionQuery {
+	disjunction := NewDisjunctionQuery()
+	if len(q.ids) > 0 {
+		disjunction.Or(&DocIDQuery{ids: q.ids, field: q.field})
+	}
+	for _, boosted := range q.boostedIDs {
+		boost := boosted.boost
+		disjunction.Or(&DocIDQuery{ids: []string{boosted.id}, field: q.field, boost: &boost})
+	}
+	disjunction.boost = q.boost
+
+	return disjunction
+}
+
 // Field specifies the field for this query.
 func (q *DocIDQuery) Field(field string) *DocIDQuery {
 	q.field = &field
@@ -986,6 +1020,12 @@ type Coordinate struct {
 	Lat float64
 }
 
+// LatLon creates a Coordinate from a latitude and a longitude, avoiding any ambiguity about their order.
+// UNCOMMITTED: This API may change in the future.
+func LatLon(lat, lon float64) Coordinate {
+	return Coordinate{Lon: lon, Lat: lat}
+}
+
 // GeoPolygonQuery represents a search query which allows to match inside a geo polygon.
 type GeoPolygonQuery struct {
 	polyPoints [][]float64
@@ -1018,6 +1058,32 @@ func NewGeoPolygonQuery(coords []Coordinate) *GeoPolygonQuery {
 	return q
 }
 
+// NewGeoPolygonQueryFromPoints creates a new GeoPolygonQuery from the vertices of the polygon. The points are sent
+// to the server as given, the polygon is not closed by repeating the first point.
+// UNCOMMITTED: This API may change in the future.
+func NewGeoPolygonQueryFromPoints(points ...Coordinate) *GeoPolygonQuery {
+	return NewGeoPolygonQuery(points)
+}
+
+// AddPoints adds further vertices to the polygon.
+// UNCOMMITTED: This API may change in the future.
+func (q *GeoPolygonQuery) AddPoints(points ...Coordinate) *GeoPolygonQuery {
+	for _, point := range points {
+		q.polyPoints = append(q.polyPoints, []float64{point.Lon, point.Lat})
+	}
+	return q
+}
+
+// Points returns the vertices of the polygon.
+// UNCOMMITTED: This API may change in the future.
+func (q *GeoPolygonQuery) Points() []Coordinate {
+	points := make([]Coordinate, len(q.polyPoints))
+	for i, lonLat := range q.polyPoints {
+		points[i] = Coordinate{Lon: lonLat[0], Lat: lonLat[1]}
+	}
+	return points
+}
+
 // Field specifies the field for this query.
 func (q *GeoPolygonQuery) Field(field string) *GeoPolygonQuery {
 	q.field = &field
@@ -2,6 +2,8 @@ package search
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 )
 
 // SearchSort represents an search sorting for a search query.
@@ -143,6 +145,46 @@ const (
 	SearchSortGeoDistanceUnitsYards         SearchSortGeoDistanceUnits = "yards"
 )
 
+var searchSortGeoDistanceUnitAliases = map[string]SearchSortGeoDistanceUnits{
+	"m":             SearchSortGeoDistanceUnitsMeters,
+	"meter":         SearchSortGeoDistanceUnitsMeters,
+	"meters":        SearchSortGeoDistanceUnitsMeters,
+	"cm":            SearchSortGeoDistanceUnitsCentimeters,
+	"centimeter":    SearchSortGeoDistanceUnitsCentimeters,
+	"centimeters":   SearchSortGeoDistanceUnitsCentimeters,
+	"ft":            SearchSortGeoDistanceUnitsFeet,
+	"foot":          SearchSortGeoDistanceUnitsFeet,
+	"feet":          SearchSortGeoDistanceUnitsFeet,
+	"in":            SearchSortGeoDistanceUnitsInches,
+	"inch":          SearchSortGeoDistanceUnitsInches,
+	"inches":        SearchSortGeoDistanceUnitsInches,
+	"km":            SearchSortGeoDistanceUnitsKilometers,
+	"kilometer":     SearchSortGeoDistanceUnitsKilometers,
+	"kilometers":    SearchSortGeoDistanceUnitsKilometers,
+	"mi":            SearchSortGeoDistanceUnitsMiles,
+	"mile":          SearchSortGeoDistanceUnitsMiles,
+	"miles":         SearchSortGeoDistanceUnitsMiles,
+	"mm":            SearchSortGeoDistanceUnitsMilliMeters,
+	"millimeter":    SearchSortGeoDistanceUnitsMilliMeters,
+	"millimeters":   SearchSortGeoDistanceUnitsMilliMeters,
+	"nm":            SearchSortGeoDistanceUnitsNauticalMiles,
+	"nauticalmile":  SearchSortGeoDistanceUnitsNauticalMiles,
+	"nauticalmiles": SearchSortGeoDistanceUnitsNauticalMiles,
+	"yd":            SearchSortGeoDistanceUnitsYards,
+	"yard":          SearchSortGeoDistanceUnitsYards,
+	"yards":         SearchSortGeoDistanceUnitsYards,
+}
+
+// ParseSearchSortGeoDistanceUnits parses a unit string, such as "km" or "miles", into a SearchSortGeoDistanceUnits.
+// Parsing is case-insensitive and accepts both abbreviations and singular or plural names.
+func ParseSearchSortGeoDistanceUnits(unit string) (SearchSortGeoDistanceUnits, error) {
+	if parsed, ok := searchSortGeoDistanceUnitAliases[strings.ToLower(strings.TrimSpace(unit))]; ok {
+		return parsed, nil
+	}
+
+	return "", fmt.Errorf("invalid geo distance unit specified: %s", unit)
+}
+
 // SearchSortGeoDistance represents a search geo sort.
 type SearchSortGeoDistance struct {
 	by       string
@@ -155,6 +197,10 @@ type SearchSortGeoDistance struct {
 
 // MarshalJSON marshal's this query to JSON for the search REST API.
 func (q SearchSortGeoDistance) MarshalJSON() ([]byte, error) {
+	unit, err := q.parsedUnit()
+	if err != nil {
+		return nil, err
+	}
 
 	return json.Marshal(struct {
 		By       string    `json:"by"`
@@ -166,7 +212,7 @@ func (q SearchSortGeoDistance) MarshalJSON() ([]byte, error) {
 		By:       q.by,
 		Field:    q.field,
 		Location: q.location,
-		Unit:     q.unit,
+		Unit:     string(unit),
 		Desc:     q.desc,
 	})
 }
@@ -177,12 +223,52 @@ func NewSearchSortGeoDistance(field string, lon, lat float64) *SearchSortGeoDist
 	return q
 }
 
-// Unit specifies the unit used for sorting
+// NewSearchSortGeoDistanceFrom creates a new SearchSortGeoDistance which sorts by the distance of the field from the
+// given point.
+// UNCOMMITTED: This API may change in the future.
+func NewSearchSortGeoDistanceFrom(field string, from Coordinate) *SearchSortGeoDistance {
+	return NewSearchSortGeoDistance(field, from.Lon, from.Lat)
+}
+
+// InMeters sorts by the distance in meters.
+// UNCOMMITTED: This API may change in the future.
+func (q *SearchSortGeoDistance) InMeters() *SearchSortGeoDistance {
+	return q.Unit(string(SearchSortGeoDistanceUnitsMeters))
+}
+
+// InKilometers sorts by the distance in kilometers.
+// UNCOMMITTED: This API may change in the future.
+func (q *SearchSortGeoDistance) InKilometers() *SearchSortGeoDistance {
+	return q.Unit(string(SearchSortGeoDistanceUnitsKilometers))
+}
+
+// InMiles sorts by the distance in miles.
+// UNCOMMITTED: This API may change in the future.
+func (q *SearchSortGeoDistance) InMiles() *SearchSortGeoDistance {
+	return q.Unit(string(SearchSortGeoDistanceUnitsMiles))
+}
+
+// Ascending orders the results nearest first, this is the default.
+// UNCOMMITTED: This API may change in the future.
+func (q *SearchSortGeoDistance) Ascending() *SearchSortGeoDistance {
+	return q.Descending(false)
+}
+
+// Unit specifies the unit used for sorting, this can be any value accepted by ParseSearchSortGeoDistanceUnits.
+// The unit is validated when the query is sent.
 func (q *SearchSortGeoDistance) Unit(unit string) *SearchSortGeoDistance {
 	q.unit = unit
 	return q
 }
 
+func (q *SearchSortGeoDistance) parsedUnit() (SearchSortGeoDistanceUnits, error) {
+	if q.unit == "" {
+		return "", nil
+	}
+
+	return ParseSearchSortGeoDistanceUnits(q.unit)
+}
+
 // Descending specifies the ordering of the results.
 func (q *SearchSortGeoDistance) Descending(descending bool) *SearchSortGeoDistance {
 	q.desc = descending
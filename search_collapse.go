@@ -0,0 +1,121 @@
+package gocb
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// searchCollapsingRowReader collapses the hits of a search, keeping only the best scoring hit for each value of a
+// stored field. As the best scoring hit for a value could be anywhere within the stream, all of the hits are read
+// before the first is returned.
+type searchCollapsingRowReader struct {
+	reader searchRowReader
+	field  string
+
+	loaded    bool
+	rows      [][]byte
+	collapsed []uint64
+	idx       int
+}
+
+func newSearchCollapsingRowReader(reader searchRowReader, field string) *searchCollapsingRowReader {
+	return &searchCollapsingRowReader{
+		reader: reader,
+		field:  field,
+	}
+}
+
+type jsonSearchCollapseRow struct {
+	Score  float64                    `json:"score"`
+	Fields map[string]json.RawMessage `json:"fields"`
+}
+
+func (r *searchCollapsingRowReader) load() {
+	if r.loaded {
+		return
+	}
+	r.loaded = true
+
+	type collapseGroup struct {
+		idx   int
+		score float64
+	}
+	groups := make(map[string]*collapseGroup)
+
+	for rowBytes := r.reader.NextRow(); rowBytes != nil; rowBytes = r.reader.NextRow() {
+		rowBytes = append([]byte(nil), rowBytes...)
+
+		// Rows which cannot be parsed are passed through, so that the error is surfaced by SearchResult.
+		var row jsonSearchCollapseRow
+		err := json.Unmarshal(rowBytes, &row)
+
+		value, ok := row.Fields[r.field]
+		if err != nil || !ok {
+			// Hits without a value for the field cannot be collapsed.
+			r.rows = append(r.rows, rowBytes)
+			r.collapsed = append(r.collapsed, 0)
+			continue
+		}
+
+		var key bytes.Buffer
+		if err := json.Compact(&key, value); err != nil {
+			key.Write(value)
+		}
+
+		group, ok := groups[key.String()]
+		if !ok {
+			groups[key.String()] = &collapseGroup{idx: len(r.rows), score: row.Score}
+			r.rows = append(r.rows, rowBytes)
+			r.collapsed = append(r.collapsed, 0)
+			continue
+		}
+
+		r.collapsed[group.idx]++
+		if row.Score > group.score {
+			group.score = row.Score
+			r.rows[group.idx] = rowBytes
+		}
+	}
+}
+
+func (r *searchCollapsingRowReader) NextRow() []byte {
+	r.load()
+
+	if r.idx >= len(r.rows) {
+		return nil
+	}
+
+	row := r.rows[r.idx]
+	r.idx++
+	return row
+}
+
+// currentCollapsed returns the number of hits which were collapsed into the row last returned by NextRow.
+func (r *searchCollapsingRowReader) currentCollapsed() uint64 {
+	if r.idx == 0 || r.idx > len(r.collapsed) {
+		return 0
+	}
+
+	return r.collapsed[r.idx-1]
+}
+
+// totalCollapsed returns the number of hits which were discarded by collapsing.
+func (r *searchCollapsingRowReader) totalCollapsed() uint64 {
+	var total uint64
+	for _, collapsed := range r.collapsed {
+		total += collapsed
+	}
+	return total
+}
+
+func (r *searchCollapsingRowReader) Err() error {
+	return r.reader.Err()
+}
+
+func (r *searchCollapsingRowReader) MetaData() ([]byte, error) {
+	return r.reader.MetaData()
+}
+
+func (r *searchCollapsingRowReader) Close() error {
+	return r.reader.Close()
+}
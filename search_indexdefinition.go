@@ -0,0 +1,319 @@
+package gocb
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SearchFieldType is the type of a field within a search index mapping.
+// UNCOMMITTED: This API may change in the future.
+type SearchFieldType string
+
+const (
+	// SearchFieldTypeText indexes the field as text, which is analyzed.
+	SearchFieldTypeText SearchFieldType = "text"
+
+	// SearchFieldTypeNumber indexes the field as a number.
+	SearchFieldTypeNumber SearchFieldType = "number"
+
+	// SearchFieldTypeDateTime indexes the field as a date time.
+	SearchFieldTypeDateTime SearchFieldType = "datetime"
+
+	// SearchFieldTypeBoolean indexes the field as a boolean.
+	SearchFieldTypeBoolean SearchFieldType = "boolean"
+
+	// SearchFieldTypeGeoPoint indexes the field as a geo point.
+	SearchFieldTypeGeoPoint SearchFieldType = "geopoint"
+
+	// SearchFieldTypeGeoShape indexes the field as a geo shape.
+	SearchFieldTypeGeoShape SearchFieldType = "geoshape"
+
+	// SearchFieldTypeIP indexes the field as an IP address.
+	SearchFieldTypeIP SearchFieldType = "IP"
+
+	// SearchFieldTypeVector indexes the field as an array of floats, for use with vector search.
+	SearchFieldTypeVector SearchFieldType = "vector"
+
+	// SearchFieldTypeVectorBase64 indexes the field as a base64 encoded array of little endian floats, for use with
+	// vector search.
+	SearchFieldTypeVectorBase64 SearchFieldType = "vector_base64"
+)
+
+// SearchVectorSimilarity is the metric used to compare vectors within a vector field.
+// UNCOMMITTED: This API may change in the future.
+type SearchVectorSimilarity string
+
+const (
+	// SearchVectorSimilarityDotProduct compares vectors using their dot product.
+	SearchVectorSimilarityDotProduct SearchVectorSimilarity = "dot_product"
+
+	// SearchVectorSimilarityL2Norm compares vectors using their euclidean distance.
+	SearchVectorSimilarityL2Norm SearchVectorSimilarity = "l2_norm"
+)
+
+// SearchVectorOptimization is what the index of a vector field is optimized for.
+// UNCOMMITTED: This API may change in the future.
+type SearchVectorOptimization string
+
+const (
+	// SearchVectorOptimizationRecall optimizes the index for accuracy.
+	SearchVectorOptimizationRecall SearchVectorOptimization = "recall"
+
+	// SearchVectorOptimizationLatency optimizes the index for speed.
+	SearchVectorOptimizationLatency SearchVectorOptimization = "latency"
+
+	// SearchVectorOptimizationMemoryEfficient optimizes the index for memory usage.
+	SearchVectorOptimizationMemoryEfficient SearchVectorOptimization = "memory-efficient"
+)
+
+// SearchIndexParams is a typed representation of the Params of a SearchIndex.
+// UNCOMMITTED: This API may change in the future.
+type SearchIndexParams struct {
+	// DocConfig determines how the type of each document is identified.
+	DocConfig *SearchIndexDocConfig `json:"doc_config,omitempty"`
+	// Mapping determines how documents are indexed.
+	Mapping *SearchIndexMapping `json:"mapping,omitempty"`
+	// Store contains properties of the index storage, such as the index type and segment version.
+	Store map[string]interface{} `json:"store,omitempty"`
+}
+
+// SearchIndexDocConfig determines how the type of each document is identified, the type is then used to select a
+// type mapping.
+// UNCOMMITTED: This API may change in the future.
+type SearchIndexDocConfig struct {
+	// Mode is the means of identifying the type, e.g. type_field or scope.collection.type_field.
+	Mode             string `json:"mode,omitempty"`
+	TypeField        string `json:"type_field,omitempty"`
+	DocIDPrefixDelim string `json:"docid_prefix_delim,omitempty"`
+	DocIDRegexp      string `json:"docid_regexp,omitempty"`
+}
+
+// SearchIndexMapping determines how the documents of each type are indexed.
+// As the server treats omitted boolean properties as true, the boolean properties of a mapping are always sent.
+// UNCOMMITTED: This API may change in the future.
+type SearchIndexMapping struct {
+	// Types maps a document type, such as scope.collection when using scope.collection.type_field, to its mapping.
+	Types                 map[string]SearchDocumentMapping `json:"types,omitempty"`
+	DefaultMapping        *SearchDocumentMapping           `json:"default_mapping,omitempty"`
+	TypeField             string                           `json:"type_field,omitempty"`
+	DefaultType           string                           `json:"default_type,omitempty"`
+	DefaultAnalyzer       string                           `json:"default_analyzer,omitempty"`
+	DefaultDateTimeParser string                           `json:"default_datetime_parser,omitempty"`
+	DefaultField          string                           `json:"default_field,omitempty"`
+	StoreDynamic          bool                             `json:"store_dynamic"`
+	IndexDynamic          bool                             `json:"index_dynamic"`
+	DocValuesDynamic      bool                             `json:"docvalues_dynamic"`
+	Analysis              *SearchIndexAnalysis             `json:"analysis,omitempty"`
+}
+
+// SearchDocumentMapping determines how a document, or an object within a document, is indexed.
+// UNCOMMITTED: This API may change in the future.
+type SearchDocumentMapping struct {
+	Enabled bool `json:"enabled"`
+	// Dynamic indexes all fields which are not explicitly mapped.
+	Dynamic         bool                             `json:"dynamic"`
+	DefaultAnalyzer string                           `json:"default_analyzer,omitempty"`
+	Properties      map[string]SearchDocumentMapping `json:"properties,omitempty"`
+	Fields          []SearchFieldMapping             `json:"fields,omitempty"`
+}
+
+// SearchFieldMapping determines how a single field is indexed.
+// UNCOMMITTED: This API may change in the future.
+type SearchFieldMapping struct {
+	Name               string          `json:"name"`
+	Type               SearchFieldType `json:"type"`
+	Analyzer           string          `json:"analyzer,omitempty"`
+	DateFormat         string          `json:"date_format,omitempty"`
+	Index              bool            `json:"index"`
+	Store              bool            `json:"store,omitempty"`
+	IncludeTermVectors bool            `json:"include_term_vectors,omitempty"`
+	IncludeInAll       bool            `json:"include_in_all,omitempty"`
+	DocValues          bool            `json:"docvalues,omitempty"`
+
+	// Dims is the number of dimensions of a vector field.
+	Dims int `json:"dims,omitempty"`
+	// Similarity is the metric used to compare the vectors of a vector field.
+	Similarity SearchVectorSimilarity `json:"similarity,omitempty"`
+	// VectorIndexOptimizedFor is what the index of a vector field is optimized for.
+	VectorIndexOptimizedFor SearchVectorOptimization `json:"vector_index_optimized_for,omitempty"`
+}
+
+// NewSearchVectorFieldMapping creates a mapping which indexes the named field as a vector.
+// UNCOMMITTED: This API may change in the future.
+func NewSearchVectorFieldMapping(name string, dims int, similarity SearchVectorSimilarity) SearchFieldMapping {
+	return SearchFieldMapping{
+		Name:       name,
+		Type:       SearchFieldTypeVector,
+		Index:      true,
+		Dims:       dims,
+		Similarity: similarity,
+	}
+}
+
+// SearchIndexAnalysis contains the custom analyzers, and their components, which can be used by a mapping.
+// UNCOMMITTED: This API may change in the future.
+type SearchIndexAnalysis struct {
+	Analyzers    map[string]SearchCustomAnalyzer   `json:"analyzers,omitempty"`
+	CharFilters  map[string]map[string]interface{} `json:"char_filters,omitempty"`
+	Tokenizers   map[string]map[string]interface{} `json:"tokenizers,omitempty"`
+	TokenFilters map[string]map[string]interface{} `json:"token_filters,omitempty"`
+	TokenMaps    map[string]map[string]interface{} `json:"token_maps,omitempty"`
+}
+
+// SearchCustomAnalyzer is an analyzer built from a tokenizer and a chain of filters.
+// UNCOMMITTED: This API may change in the future.
+type SearchCustomAnalyzer struct {
+	// Type defaults to custom.
+	Type         string   `json:"type"`
+	Tokenizer    string   `json:"tokenizer"`
+	CharFilters  []string `json:"char_filters,omitempty"`
+	TokenFilters []string `json:"token_filters,omitempty"`
+}
+
+// UnmarshalJSON mirrors the server, which treats omitted boolean properties as true.
+func (m *SearchIndexMapping) UnmarshalJSON(data []byte) error {
+	type searchIndexMapping SearchIndexMapping
+	mapping := searchIndexMapping{
+		StoreDynamic:     true,
+		IndexDynamic:     true,
+		DocValuesDynamic: true,
+	}
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return err
+	}
+
+	*m = SearchIndexMapping(mapping)
+	return nil
+}
+
+// UnmarshalJSON mirrors the server, which treats omitted boolean properties as true.
+func (m *SearchDocumentMapping) UnmarshalJSON(data []byte) error {
+	type searchDocumentMapping SearchDocumentMapping
+	mapping := searchDocumentMapping{
+		Enabled: true,
+		Dynamic: true,
+	}
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return err
+	}
+
+	*m = SearchDocumentMapping(mapping)
+	return nil
+}
+
+func (a SearchCustomAnalyzer) MarshalJSON() ([]byte, error) {
+	type searchCustomAnalyzer SearchCustomAnalyzer
+	if a.Type == "" {
+		a.Type = "custom"
+	}
+
+	return json.Marshal(searchCustomAnalyzer(a))
+}
+
+func validateSearchDocumentMapping(path string, mapping SearchDocumentMapping) error {
+	for _, field := range mapping.Fields {
+		if field.Type != SearchFieldTypeVector && field.Type != SearchFieldTypeVectorBase64 {
+			continue
+		}
+
+		fieldPath := path + "." + field.Name
+		if field.Dims <= 0 {
+			return makeInvalidArgumentsError(fmt.Sprintf("vector field %s must have a positive number of dims", fieldPath))
+		}
+		if field.Similarity == "" {
+			return makeInvalidArgumentsError(fmt.Sprintf("vector field %s must have a similarity", fieldPath))
+		}
+	}
+
+	for name, property := range mapping.Properties {
+		if err := validateSearchDocumentMapping(path+"."+name, property); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *SearchIndexParams) validate() error {
+	if p.Mapping == nil {
+		return nil
+	}
+
+	if p.Mapping.DefaultMapping != nil {
+		if err := validateSearchDocumentMapping("_default", *p.Mapping.DefaultMapping); err != nil {
+			return err
+		}
+	}
+	for typeName, mapping := range p.Mapping.Types {
+		if err := validateSearchDocumentMapping(typeName, mapping); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetParams replaces the Params of the index with the typed params provided.
+// UNCOMMITTED: This API may change in the future.
+func (si *SearchIndex) SetParams(params SearchIndexParams) error {
+	if err := params.validate(); err != nil {
+		return err
+	}
+
+	paramsBytes, err := json.Marshal(params)
+	if err != nil {
+		return wrapError(err, "failed to marshal search index params")
+	}
+
+	var paramsMap map[string]interface{}
+	if err := json.Unmarshal(paramsBytes, &paramsMap); err != nil {
+		return wrapError(err, "failed to unmarshal search index params")
+	}
+
+	si.Params = paramsMap
+	return nil
+}
+
+// DecodeParams decodes the Params of the index into their typed representation. Any properties which are not
+// represented by SearchIndexParams are discarded.
+// UNCOMMITTED: This API may change in the future.
+func (si *SearchIndex) DecodeParams() (*SearchIndexParams, error) {
+	paramsBytes, err := json.Marshal(si.Params)
+	if err != nil {
+		return nil, wrapError(err, "failed to marshal search index params")
+	}
+
+	var params SearchIndexParams
+	if err := json.Unmarshal(paramsBytes, &params); err != nil {
+		return nil, wrapError(err, "failed to unmarshal search index params")
+	}
+
+	return &params, nil
+}
+
+type jsonSearchIndexResp struct {
+	Status   string           `json:"status"`
+	IndexDef *jsonSearchIndex `json:"indexDef"`
+}
+
+type jsonSearchIndexDefs struct {
+	IndexDefs   map[string]jsonSearchIndex `json:"indexDefs"`
+	ImplVersion string                     `json:"implVersion"`
+}
+
+type jsonSearchIndexesResp struct {
+	Status    string              `json:"status"`
+	IndexDefs jsonSearchIndexDefs `json:"indexDefs"`
+}
+
+type jsonSearchIndex struct {
+	UUID         string                 `json:"uuid"`
+	Name         string                 `json:"name"`
+	SourceName   string                 `json:"sourceName"`
+	Type         string                 `json:"type"`
+	Params       map[string]interface{} `json:"params"`
+	SourceUUID   string                 `json:"sourceUUID"`
+	SourceParams map[string]interface{} `json:"sourceParams"`
+	SourceType   string                 `json:"sourceType"`
+	PlanParams   map[string]interface{} `json:"planParams"`
+}
@@ -0,0 +1,196 @@
+package gocb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ExportSearchIndexOptions is the set of options available to the search index ExportIndex operation.
+// UNCOMMITTED: This API may change in the future.
+type ExportSearchIndexOptions struct {
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	// UNCOMMITTED: This API may change in the future.
+	Context context.Context
+}
+
+// ImportSearchIndexOptions is the set of options available to the search index ImportIndex operation.
+// UNCOMMITTED: This API may change in the future.
+type ImportSearchIndexOptions struct {
+	// SourceName overrides the source of the data for the index, for when the bucket is named differently in the
+	// environment that the index is imported into.
+	SourceName string
+
+	// Timeout bounds the entire operation, including looking up any existing index.
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+	ParentSpan    RequestSpan
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation.
+	Context context.Context
+}
+
+// ExportIndex returns the JSON definition of a search index, with the properties which are specific to the cluster
+// removed so that it can be passed to ImportIndex against another cluster, bucket or scope.
+// UNCOMMITTED: This API may change in the future.
+func (sm *SearchIndexManager) ExportIndex(indexName string, opts *ExportSearchIndexOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &ExportSearchIndexOptions{}
+	}
+
+	return exportSearchIndex(sm, indexName, opts)
+}
+
+// ImportIndex creates or updates a search index from a JSON definition, such as one returned by ExportIndex. Any
+// properties in the definition which are specific to the cluster that it was exported from are ignored.
+// UNCOMMITTED: This API may change in the future.
+func (sm *SearchIndexManager) ImportIndex(definition []byte, opts *ImportSearchIndexOptions) error {
+	if opts == nil {
+		opts = &ImportSearchIndexOptions{}
+	}
+
+	return importSearchIndex(sm, "", definition, opts)
+}
+
+// ExportIndex returns the JSON definition of a search index, with the properties which are specific to the cluster
+// removed so that it can be passed to ImportIndex against another cluster, bucket or scope.
+// UNCOMMITTED: This API may change in the future.
+func (sm *ScopeSearchIndexManager) ExportIndex(indexName string, opts *ExportSearchIndexOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &ExportSearchIndexOptions{}
+	}
+
+	return exportSearchIndex(sm, indexName, opts)
+}
+
+// ImportIndex creates or updates a search index from a JSON definition, such as one returned by ExportIndex. Any
+// properties in the definition which are specific to the cluster that it was exported from are ignored. When the
+// index maps documents by scope.collection, the type mappings are rewritten to refer to the collections of this
+// scope, so an index can be imported into a different scope to the one it was exported from.
+// UNCOMMITTED: This API may change in the future.
+func (sm *ScopeSearchIndexManager) ImportIndex(definition []byte, opts *ImportSearchIndexOptions) error {
+	if opts == nil {
+		opts = &ImportSearchIndexOptions{}
+	}
+
+	return importSearchIndex(sm, sm.scope.Name(), definition, opts)
+}
+
+// portableSearchIndex removes the properties of an index definition which only have meaning on the cluster that it
+// was fetched from. The UUIDs identify the index and source bucket instances, and scoped indexes are named by the
+// server using their fully qualified bucket.scope.name form.
+func portableSearchIndex(index SearchIndex) SearchIndex {
+	index.UUID = ""
+	index.SourceUUID = ""
+	if idx := strings.LastIndexByte(index.Name, '.'); idx >= 0 {
+		index.Name = index.Name[idx+1:]
+	}
+
+	return index
+}
+
+func exportSearchIndex(mgr searchIndexEnsurer, indexName string, opts *ExportSearchIndexOptions) ([]byte, error) {
+	if indexName == "" {
+		return nil, makeInvalidArgumentsError("index name cannot be empty")
+	}
+
+	index, err := mgr.GetIndex(indexName, &GetSearchIndexOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       opts.Context,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	portable := portableSearchIndex(*index)
+	return json.MarshalIndent(&portable, "", "  ")
+}
+
+// rescopeSearchIndex rewrites the type mappings of an index which maps documents by scope.collection, so that they
+// refer to the collections of scopeName rather than those of the scope that the index was exported from.
+func rescopeSearchIndex(index SearchIndex, scopeName string) SearchIndex {
+	docConfig, _ := index.Params["doc_config"].(map[string]interface{})
+	mode, _ := docConfig["mode"].(string)
+	if !strings.HasPrefix(mode, "scope.collection") {
+		return index
+	}
+
+	mapping, _ := index.Params["mapping"].(map[string]interface{})
+	types, _ := mapping["types"].(map[string]interface{})
+	if len(types) == 0 {
+		return index
+	}
+
+	rescopedTypes := make(map[string]interface{}, len(types))
+	for typeName, typeMapping := range types {
+		// Type names are of the form scope.collection, optionally followed by the value of the type field.
+		if idx := strings.IndexByte(typeName, '.'); idx >= 0 {
+			typeName = scopeName + typeName[idx:]
+		}
+		rescopedTypes[typeName] = typeMapping
+	}
+
+	rescopedMapping := make(map[string]interface{}, len(mapping))
+	for k, v := range mapping {
+		rescopedMapping[k] = v
+	}
+	rescopedMapping["types"] = rescopedTypes
+
+	params := make(map[string]interface{}, len(index.Params))
+	for k, v := range index.Params {
+		params[k] = v
+	}
+	params["mapping"] = rescopedMapping
+	index.Params = params
+
+	return index
+}
+
+func importSearchIndex(mgr searchIndexEnsurer, scopeName string, definition []byte, opts *ImportSearchIndexOptions) error {
+	var index SearchIndex
+	if err := json.Unmarshal(definition, &index); err != nil {
+		return makeInvalidArgumentsError("index definition is not valid JSON: " + err.Error())
+	}
+
+	index = portableSearchIndex(index)
+	if scopeName != "" {
+		index = rescopeSearchIndex(index, scopeName)
+	}
+	if opts.SourceName != "" {
+		index.SourceName = opts.SourceName
+	}
+	if index.Name == "" {
+		return makeInvalidArgumentsError("index name cannot be empty")
+	}
+
+	ctx, cancel := ensureContext(opts.Context, opts.Timeout)
+	defer cancel()
+
+	// Updating an index requires the UUID of the index on this cluster, rather than that of the exported index.
+	existing, err := mgr.GetIndex(index.Name, &GetSearchIndexOptions{
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       ctx,
+	})
+	if err != nil && !errors.Is(err, ErrIndexNotFound) {
+		return err
+	}
+	if err == nil {
+		index.UUID = existing.UUID
+	}
+
+	return mgr.UpsertIndex(index, &UpsertSearchIndexOptions{
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    opts.ParentSpan,
+		Context:       ctx,
+	})
+}
@@ -1,12 +1,326 @@
 package gocb
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/couchbase/gocb/v2/search"
 	"github.com/couchbase/gocb/v2/vector"
 )
 
+var defaultVectorQueryNumCandidates = uint32(3)
+
 // SearchRequest is used for describing a search request used with Search.
 type SearchRequest struct {
 	SearchQuery  search.Query
 	VectorSearch *vector.Search
 }
+
+// MarshalJSON marshal's this request to the query and knn fields of a search REST API request, as sent by Search.
+// MarshalFTSRequest should be used to also include the fields built from SearchOptions.
+// UNCOMMITTED: This API may change in the future.
+func (r SearchRequest) MarshalJSON() ([]byte, error) {
+	data := make(map[string]interface{})
+	if err := r.addToMap(data); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(data)
+}
+
+func (r SearchRequest) addToMap(data map[string]interface{}) error {
+	if r.SearchQuery != nil {
+		data["query"] = r.SearchQuery
+	} else if r.VectorSearch != nil {
+		// See MB-60312.
+		data["query"] = search.NewMatchNoneQuery()
+	}
+
+	if r.VectorSearch != nil {
+		if err := addVectorSearchToMap(data, r.VectorSearch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MarshalFTSRequest marshal's a request and the options used to execute it to a search REST API request, such as can
+// be imported into the Couchbase Server UI. It is the inverse of UnmarshalFTSRequest, the Timeout, ScanConsistency and
+// ConsistentWith options are included in the ctl field. The index name is used to build the consistency vectors of
+// ConsistentWith.
+// UNCOMMITTED: This API may change in the future.
+func MarshalFTSRequest(indexName string, request SearchRequest, opts *SearchOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+
+	data, err := opts.toMap(indexName)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Timeout > 0 {
+		// The ctl field may have been built from the options or provided through Raw, so it is copied rather than
+		// modified in place.
+		ctl := make(map[string]json.RawMessage)
+		if existing, ok := data["ctl"]; ok {
+			b, err := json.Marshal(existing)
+			if err != nil {
+				return nil, wrapError(err, "failed to marshal search request ctl")
+			}
+			if err := json.Unmarshal(b, &ctl); err != nil {
+				return nil, wrapError(err, "failed to marshal search request ctl")
+			}
+		}
+
+		timeout, err := json.Marshal(opts.Timeout.Milliseconds())
+		if err != nil {
+			return nil, wrapError(err, "failed to marshal search request timeout")
+		}
+		ctl["timeout"] = timeout
+		data["ctl"] = ctl
+	}
+
+	if err := request.addToMap(data); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(data)
+}
+
+func addVectorSearchToMap(data map[string]interface{}, vSearch *vector.Search) error {
+	internalVSearch := vSearch.Internal()
+
+	if err := internalVSearch.Validate(); err != nil {
+		return makeInvalidArgumentsError(err.Error())
+	}
+
+	queries := make([]vector.InternalQuery, len(internalVSearch.Queries))
+	for i, query := range internalVSearch.Queries {
+		if query.NumCandidates == nil {
+			query.NumCandidates = &defaultVectorQueryNumCandidates
+		}
+		queries[i] = query
+	}
+
+	data["knn"] = queries
+	if internalVSearch.VectorQueryCombination != vector.VectorQueryCombinationNotSet {
+		data["knn_operator"] = string(internalVSearch.VectorQueryCombination)
+	}
+
+	return nil
+}
+
+type jsonFTSVectorQuery struct {
+	Field        string          `json:"field"`
+	Vector       []float32       `json:"vector,omitempty"`
+	Base64Vector string          `json:"vector_base64,omitempty"`
+	K            *uint32         `json:"k,omitempty"`
+	Boost        *float32        `json:"boost,omitempty"`
+	Filter       json.RawMessage `json:"filter,omitempty"`
+}
+
+// jsonFTSVectorQueryKnownFields are the fields of a knn entry which UnmarshalFTSRequest maps onto a vector.Query.
+var jsonFTSVectorQueryKnownFields = map[string]struct{}{
+	"field": {}, "vector": {}, "vector_base64": {}, "k": {}, "boost": {}, "filter": {},
+}
+
+type jsonFTSHighlight struct {
+	Style  string   `json:"style"`
+	Fields []string `json:"fields"`
+}
+
+type jsonFTSRequest struct {
+	Query            json.RawMessage            `json:"query,omitempty"`
+	Knn              []jsonFTSVectorQuery       `json:"knn,omitempty"`
+	KnnOperator      string                     `json:"knn_operator,omitempty"`
+	Size             *uint32                    `json:"size,omitempty"`
+	From             *uint32                    `json:"from,omitempty"`
+	Explain          bool                       `json:"explain,omitempty"`
+	Fields           []string                   `json:"fields,omitempty"`
+	Sort             []json.RawMessage          `json:"sort,omitempty"`
+	Highlight        *jsonFTSHighlight          `json:"highlight,omitempty"`
+	Facets           map[string]json.RawMessage `json:"facets,omitempty"`
+	Score            string                     `json:"score,omitempty"`
+	Collections      []string                   `json:"collections,omitempty"`
+	IncludeLocations bool                       `json:"includeLocations,omitempty"`
+	Ctl              map[string]json.RawMessage `json:"ctl,omitempty"`
+	SearchAfter      []string                   `json:"search_after,omitempty"`
+	SearchBefore     []string                   `json:"search_before,omitempty"`
+}
+
+// jsonFTSRequestKnownFields are the fields of a search REST API request which UnmarshalFTSRequest maps onto
+// SearchRequest and SearchOptions, any other fields are carried in SearchOptions.Raw.
+var jsonFTSRequestKnownFields = []string{
+	"query", "knn", "knn_operator", "size", "from", "explain", "fields", "sort", "highlight", "facets", "score",
+	"collections", "includeLocations", "ctl", "search_after", "search_before",
+}
+
+// UnmarshalFTSRequest parses a search REST API request, such as one exported from the Couchbase Server UI, into a
+// SearchRequest and the SearchOptions needed to execute it with Search. The search query, sorts and facets are passed
+// through to the server as-is rather than being parsed into the types within the search package, as such the
+// resulting request cannot be used with the couchbase2 protocol. If any knn entry contains fields which cannot be
+// represented by a vector.Query then the knn and knn_operator fields are carried in SearchOptions.Raw as-is, rather
+// than being mapped onto VectorSearch, so that those fields are not lost.
+// UNCOMMITTED: This API may change in the future.
+func UnmarshalFTSRequest(data []byte) (*SearchRequest, *SearchOptions, error) {
+	var jsonReq jsonFTSRequest
+	if err := json.Unmarshal(data, &jsonReq); err != nil {
+		return nil, nil, wrapError(err, "failed to parse search request")
+	}
+
+	var allFields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &allFields); err != nil {
+		return nil, nil, wrapError(err, "failed to parse search request")
+	}
+
+	req := &SearchRequest{}
+	if len(jsonReq.Query) > 0 && string(jsonReq.Query) != "null" {
+		req.SearchQuery = jsonReq.Query
+	}
+
+	rawKnn, err := hasUnknownFTSVectorQueryFields(allFields["knn"])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if rawKnn {
+		if req.SearchQuery == nil {
+			// See MB-60312.
+			req.SearchQuery = search.NewMatchNoneQuery()
+		}
+	} else if len(jsonReq.Knn) > 0 {
+		queries := make([]*vector.Query, len(jsonReq.Knn))
+		for i, knn := range jsonReq.Knn {
+			var query *vector.Query
+			if knn.Base64Vector != "" {
+				query = vector.NewBase64Query(knn.Field, knn.Base64Vector)
+			} else {
+				query = vector.NewQuery(knn.Field, knn.Vector)
+			}
+			if knn.K != nil {
+				query.NumCandidates(*knn.K)
+			}
+			if knn.Boost != nil {
+				query.Boost(*knn.Boost)
+			}
+			if len(knn.Filter) > 0 && string(knn.Filter) != "null" {
+				query.Prefilter(knn.Filter)
+			}
+			queries[i] = query
+		}
+
+		req.VectorSearch = vector.NewSearch(queries, &vector.SearchOptions{
+			VectorQueryCombination: vector.VectorQueryCombination(jsonReq.KnnOperator),
+		})
+	}
+
+	opts := &SearchOptions{
+		Explain:          jsonReq.Explain,
+		Fields:           jsonReq.Fields,
+		DisableScoring:   jsonReq.Score == "none",
+		Collections:      jsonReq.Collections,
+		IncludeLocations: jsonReq.IncludeLocations,
+		SearchAfter:      jsonReq.SearchAfter,
+		SearchBefore:     jsonReq.SearchBefore,
+	}
+	if jsonReq.Size != nil {
+		opts.Limit = *jsonReq.Size
+	}
+	if jsonReq.From != nil {
+		opts.Skip = *jsonReq.From
+	}
+	for _, sort := range jsonReq.Sort {
+		opts.Sort = append(opts.Sort, sort)
+	}
+	if jsonReq.Highlight != nil {
+		opts.Highlight = &SearchHighlightOptions{
+			Style:  SearchHighlightStyle(jsonReq.Highlight.Style),
+			Fields: jsonReq.Highlight.Fields,
+		}
+	}
+	if len(jsonReq.Facets) > 0 {
+		opts.Facets = make(map[string]search.Facet, len(jsonReq.Facets))
+		for name, facet := range jsonReq.Facets {
+			opts.Facets[name] = facet
+		}
+	}
+
+	if err := opts.fromFTSCtl(jsonReq.Ctl); err != nil {
+		return nil, nil, err
+	}
+
+	for _, field := range jsonFTSRequestKnownFields {
+		if rawKnn && (field == "knn" || field == "knn_operator") {
+			continue
+		}
+		delete(allFields, field)
+	}
+	for field, value := range allFields {
+		if opts.Raw == nil {
+			opts.Raw = make(map[string]interface{})
+		}
+		opts.Raw[field] = value
+	}
+
+	return req, opts, nil
+}
+
+// hasUnknownFTSVectorQueryFields reports whether any entry of a knn field contains fields which are not in
+// jsonFTSVectorQueryKnownFields.
+func hasUnknownFTSVectorQueryFields(knn json.RawMessage) (bool, error) {
+	if len(knn) == 0 || string(knn) == "null" {
+		return false, nil
+	}
+
+	var entries []map[string]json.RawMessage
+	if err := json.Unmarshal(knn, &entries); err != nil {
+		return false, wrapError(err, "failed to parse search request knn")
+	}
+
+	for _, entry := range entries {
+		for field := range entry {
+			if _, ok := jsonFTSVectorQueryKnownFields[field]; !ok {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// fromFTSCtl applies the ctl section of a search REST API request. Consistency vectors cannot be mapped onto a
+// MutationState, and so at_plus consistency is carried in Raw.
+func (opts *SearchOptions) fromFTSCtl(ctl map[string]json.RawMessage) error {
+	if timeout, ok := ctl["timeout"]; ok {
+		var timeoutMs uint64
+		if err := json.Unmarshal(timeout, &timeoutMs); err != nil {
+			return wrapError(err, "failed to parse search request timeout")
+		}
+		opts.Timeout = time.Duration(timeoutMs) * time.Millisecond
+	}
+
+	consistency, ok := ctl["consistency"]
+	if !ok {
+		return nil
+	}
+
+	var level struct {
+		Level string `json:"level"`
+	}
+	if err := json.Unmarshal(consistency, &level); err != nil {
+		return wrapError(err, "failed to parse search request consistency")
+	}
+
+	switch level.Level {
+	case "", "not_bounded":
+		opts.ScanConsistency = SearchScanConsistencyNotBounded
+	default:
+		opts.Raw = map[string]interface{}{
+			"ctl": map[string]json.RawMessage{"consistency": consistency},
+		}
+	}
+
+	return nil
+}
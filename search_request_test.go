@@ -0,0 +1,185 @@
+package gocb
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/couchbase/gocb/v2/search"
+	"github.com/couchbase/gocb/v2/vector"
+)
+
+func (suite *UnitTestSuite) TestSearchRequestMarshalJSON() {
+	req := SearchRequest{
+		SearchQuery: search.NewMatchQuery("airport").Field("type"),
+		VectorSearch: vector.NewSearch([]*vector.Query{
+			vector.NewQuery("embedding", []float32{0.1, 0.2}).Boost(2),
+		}, &vector.SearchOptions{VectorQueryCombination: vector.VectorQueryCombinationOr}),
+	}
+
+	b, err := json.Marshal(req)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().JSONEq(`{
+		"query": {"match": "airport", "field": "type"},
+		"knn": [{"field": "embedding", "vector": [0.1, 0.2], "k": 3, "boost": 2}],
+		"knn_operator": "or"
+	}`, string(b))
+
+	b, err = json.Marshal(SearchRequest{
+		VectorSearch: vector.NewSearch([]*vector.Query{vector.NewBase64Query("embedding", "AAAA")}, nil),
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().JSONEq(`{
+		"query": {"match_none": null},
+		"knn": [{"field": "embedding", "vector_base64": "AAAA", "k": 3}]
+	}`, string(b))
+
+	_, err = json.Marshal(SearchRequest{VectorSearch: vector.NewSearch(nil, nil)})
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestSearchRequestMultiVectorPrefilter() {
+	encoded := vector.EncodeBase64([]float32{1, -2})
+	suite.Assert().Equal("AACAPwAAAMA=", encoded)
+
+	b, err := json.Marshal(SearchRequest{
+		VectorSearch: vector.NewSearch([]*vector.Query{
+			vector.NewBase64Query("embedding", encoded).Boost(2).
+				Prefilter(search.NewTermQuery("uk").Field("country")),
+			vector.NewQuery("summary", []float32{0.5, 0.5}).NumCandidates(10).Boost(0.5),
+		}, &vector.SearchOptions{VectorQueryCombination: vector.VectorQueryCombinationOr}),
+	})
+	suite.Require().Nil(err, err)
+
+	suite.Assert().JSONEq(`{
+		"query": {"match_none": null},
+		"knn": [
+			{"field": "embedding", "vector_base64": "AACAPwAAAMA=", "k": 3, "boost": 2,
+				"filter": {"term": "uk", "field": "country"}},
+			{"field": "summary", "vector": [0.5, 0.5], "k": 10, "boost": 0.5}
+		],
+		"knn_operator": "or"
+	}`, string(b))
+
+	req, _, err := UnmarshalFTSRequest(b)
+	suite.Require().Nil(err, err)
+	queries := req.VectorSearch.Internal().Queries
+	suite.Require().Len(queries, 2)
+	suite.Assert().JSONEq(`{"term": "uk", "field": "country"}`, string(queries[0].Prefilter.(json.RawMessage)))
+	suite.Assert().Nil(queries[1].Prefilter)
+
+	_, err = json.Marshal(SearchRequest{
+		VectorSearch: vector.NewSearch([]*vector.Query{
+			vector.NewQuery("embedding", []float32{1}),
+			{},
+		}, nil),
+	})
+	suite.Assert().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestUnmarshalFTSRequest() {
+	exported := `{
+		"query": {"conjuncts": [{"match": "airport", "field": "type"}, {"term": "uk", "field": "country"}]},
+		"knn": [{"field": "embedding", "vector": [0.5, 0.25], "k": 5}],
+		"knn_operator": "and",
+		"size": 20,
+		"from": 40,
+		"explain": true,
+		"fields": ["*"],
+		"sort": ["-_score", {"by": "field", "field": "name"}],
+		"highlight": {"style": "html", "fields": ["name"]},
+		"facets": {"types": {"field": "type", "size": 5}},
+		"score": "none",
+		"collections": ["airport"],
+		"includeLocations": true,
+		"ctl": {"timeout": 5000, "consistency": {"level": ""}},
+		"showrequest": false
+	}`
+
+	req, opts, err := UnmarshalFTSRequest([]byte(exported))
+	suite.Require().Nil(err, err)
+
+	suite.Require().NotNil(req.VectorSearch)
+	vSearch := req.VectorSearch.Internal()
+	suite.Require().Len(vSearch.Queries, 1)
+	suite.Assert().Equal("embedding", vSearch.Queries[0].Field)
+	suite.Assert().Equal([]float32{0.5, 0.25}, vSearch.Queries[0].Vector)
+	suite.Assert().Equal(uint32(5), *vSearch.Queries[0].NumCandidates)
+	suite.Assert().Equal(vector.VectorQueryCombinationAnd, vSearch.VectorQueryCombination)
+
+	suite.Assert().Equal(uint32(20), opts.Limit)
+	suite.Assert().Equal(uint32(40), opts.Skip)
+	suite.Assert().True(opts.Explain)
+	suite.Assert().True(opts.DisableScoring)
+	suite.Assert().True(opts.IncludeLocations)
+	suite.Assert().Equal([]string{"*"}, opts.Fields)
+	suite.Assert().Equal([]string{"airport"}, opts.Collections)
+	suite.Assert().Equal(&SearchHighlightOptions{Style: HTMLHighlightStyle, Fields: []string{"name"}}, opts.Highlight)
+	suite.Assert().Len(opts.Sort, 2)
+	suite.Assert().Len(opts.Facets, 1)
+	suite.Assert().Equal(5*time.Second, opts.Timeout)
+	suite.Assert().Equal(SearchScanConsistencyNotBounded, opts.ScanConsistency)
+	suite.Assert().Equal(map[string]interface{}{"showrequest": json.RawMessage("false")}, opts.Raw)
+
+	// Converting back should produce an equivalent request to the one which was exported.
+	bodyBytes, err := MarshalFTSRequest("travel-index", *req, opts)
+	suite.Require().Nil(err, err)
+	suite.Assert().JSONEq(exported, string(bodyBytes))
+}
+
+func (suite *UnitTestSuite) TestUnmarshalFTSRequestUnknownKnnFields() {
+	exported := `{
+		"knn": [{"field": "embedding", "vector": [0.5, 0.25], "k": 5, "params": {"ivf_nprobe_pct": 10}}],
+		"knn_operator": "or"
+	}`
+
+	req, opts, err := UnmarshalFTSRequest([]byte(exported))
+	suite.Require().Nil(err, err)
+
+	// The knn fields cannot be represented by a vector.Query so are passed through as-is.
+	suite.Assert().Nil(req.VectorSearch)
+	suite.Require().Contains(opts.Raw, "knn")
+	suite.Require().Contains(opts.Raw, "knn_operator")
+
+	bodyBytes, err := MarshalFTSRequest("travel-index", *req, opts)
+	suite.Require().Nil(err, err)
+
+	var body map[string]interface{}
+	suite.Require().Nil(json.Unmarshal(bodyBytes, &body))
+	suite.Assert().Contains(body["query"], "match_none")
+	suite.Assert().Equal("or", body["knn_operator"])
+	suite.Assert().Equal([]interface{}{map[string]interface{}{
+		"field":  "embedding",
+		"vector": []interface{}{0.5, 0.25},
+		"k":      float64(5),
+		"params": map[string]interface{}{"ivf_nprobe_pct": float64(10)},
+	}}, body["knn"])
+}
+
+func (suite *UnitTestSuite) TestUnmarshalFTSRequestAtPlus() {
+	req, opts, err := UnmarshalFTSRequest([]byte(`{
+		"query": {"match_all": {}},
+		"ctl": {"consistency": {"level": "at_plus", "vectors": {"travel-index": {"12/1234": 10}}}}
+	}`))
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Nil(req.VectorSearch)
+	suite.Assert().JSONEq(`{"match_all": {}}`, string(req.SearchQuery.(json.RawMessage)))
+	suite.Assert().Equal(searchScanConsistencyNotSet, opts.ScanConsistency)
+
+	ctl, err := json.Marshal(opts.Raw["ctl"])
+	suite.Require().Nil(err, err)
+	suite.Assert().JSONEq(`{"consistency": {"level": "at_plus", "vectors": {"travel-index": {"12/1234": 10}}}}`, string(ctl))
+
+	opts.Timeout = 2 * time.Second
+	b, err := MarshalFTSRequest("travel-index", *req, opts)
+	suite.Require().Nil(err, err)
+	suite.Assert().JSONEq(`{
+		"query": {"match_all": {}},
+		"ctl": {"timeout": 2000, "consistency": {"level": "at_plus", "vectors": {"travel-index": {"12/1234": 10}}}}
+	}`, string(b))
+
+	_, _, err = UnmarshalFTSRequest([]byte(`{"query":`))
+	suite.Assert().NotNil(err)
+}
@@ -0,0 +1,536 @@
+package gocb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	cbsearch "github.com/couchbase/gocb/v2/search"
+)
+
+type jsonRowLocation struct {
+	Field          string   `json:"field"`
+	Term           string   `json:"term"`
+	Position       uint32   `json:"position"`
+	Start          uint32   `json:"start"`
+	End            uint32   `json:"end"`
+	ArrayPositions []uint32 `json:"array_positions"`
+}
+
+type jsonSearchTermFacet struct {
+	Term  string `json:"term,omitempty"`
+	Count int    `json:"count,omitempty"`
+}
+
+type jsonSearchNumericFacet struct {
+	Name  string  `json:"name,omitempty"`
+	Min   float64 `json:"min,omitempty"`
+	Max   float64 `json:"max,omitempty"`
+	Count int     `json:"count,omitempty"`
+}
+
+type jsonSearchDateFacet struct {
+	Name  string `json:"name,omitempty"`
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+	Count int    `json:"count,omitempty"`
+}
+
+type jsonSearchFacet struct {
+	Name          string                   `json:"name"`
+	Field         string                   `json:"field"`
+	Total         uint64                   `json:"total"`
+	Missing       uint64                   `json:"missing"`
+	Other         uint64                   `json:"other"`
+	Terms         []jsonSearchTermFacet    `json:"terms"`
+	NumericRanges []jsonSearchNumericFacet `json:"numeric_ranges"`
+	DateRanges    []jsonSearchDateFacet    `json:"date_ranges"`
+}
+
+type jsonSearchRowLocations map[string]map[string][]jsonRowLocation
+
+type jsonSearchRow struct {
+	Index       string                 `json:"index"`
+	ID          string                 `json:"id"`
+	Score       float64                `json:"score"`
+	Explanation json.RawMessage        `json:"explanation"`
+	Locations   jsonSearchRowLocations `json:"locations"`
+	Fragments   map[string][]string    `json:"fragments"`
+	Fields      json.RawMessage        `json:"fields"`
+	Sort        []string               `json:"sort"`
+}
+
+type jsonSearchResponseStatus struct {
+	Errors     map[string]string `json:"errors"`
+	Failed     uint64            `json:"failed"`
+	Successful uint64            `json:"successful"`
+}
+
+type jsonSearchResponse struct {
+	Status    jsonSearchResponseStatus   `json:"status,omitempty"`
+	TotalHits uint64                     `json:"total_hits"`
+	MaxScore  float64                    `json:"max_score"`
+	Took      uint64                     `json:"took"`
+	Facets    map[string]jsonSearchFacet `json:"facets"`
+}
+
+type searchRowReader interface {
+	NextRow() []byte
+	Err() error
+	MetaData() ([]byte, error)
+	Close() error
+}
+
+// SearchResultRaw provides raw access to search data.
+// VOLATILE: This API is subject to change at any time.
+type SearchResultRaw struct {
+	reader searchRowReader
+}
+
+// NextBytes returns the next row as bytes.
+func (srr *SearchResultRaw) NextBytes() []byte {
+	return srr.reader.NextRow()
+}
+
+// Err returns any errors that have occurred on the stream
+func (srr *SearchResultRaw) Err() error {
+	err := srr.reader.Err()
+	if err != nil {
+		return maybeEnhanceSearchError(err)
+	}
+
+	return nil
+}
+
+// Close marks the results as closed, returning any errors that occurred during reading the results.
+func (srr *SearchResultRaw) Close() error {
+	err := srr.reader.Close()
+	if err != nil {
+		return maybeEnhanceSearchError(err)
+	}
+
+	return nil
+}
+
+// MetaData returns any meta-data that was available from this query as bytes.
+func (srr *SearchResultRaw) MetaData() ([]byte, error) {
+	return srr.reader.MetaData()
+}
+
+// SearchResult allows access to the results of a search query.
+type SearchResult struct {
+	reader searchRowReader
+
+	currentRow SearchRow
+	jsonErr    error
+
+	// geoDistanceSortIdx is the index of the first geo distance sort within the request, or -1 if there is not one.
+	geoDistanceSortIdx int
+
+	// collapser is set when the hits are being collapsed client side.
+	collapser *searchCollapsingRowReader
+
+	// facetRequests are the facets which were requested, used to verify the type of facet results.
+	facetRequests map[string]cbsearch.Facet
+	// finished is set once all of the rows have been read from the stream.
+	finished bool
+
+	lastSortKey []string
+
+	outcome *streamOutcome
+}
+
+// searchFacetsReader is implemented by readers which can provide facets before the stream has been fully read.
+type searchFacetsReader interface {
+	receivedFacets() (map[string]jsonSearchFacet, bool)
+}
+
+func newSearchResult(reader searchRowReader) *SearchResult {
+	return &SearchResult{
+		reader:             reader,
+		geoDistanceSortIdx: -1,
+	}
+}
+
+func searchGeoDistanceSortIndex(sorts []cbsearch.Sort) int {
+	for idx, sort := range sorts {
+		if _, ok := sort.(*cbsearch.SearchSortGeoDistance); ok {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// parseSearchSortDistance parses a geo distance from a hit sort value. Depending on the server version the value is
+// either a plain number or a prefix coded int64 holding the sortable bits of a float64.
+func parseSearchSortDistance(value string) (float64, bool) {
+	if distance, err := strconv.ParseFloat(value, 64); err == nil {
+		return distance, true
+	}
+
+	if len(value) < 2 {
+		return 0, false
+	}
+	shift := int(value[0]) - 0x20
+	if shift < 0 || shift > 63 {
+		return 0, false
+	}
+
+	var sortableBits uint64
+	for i := 1; i < len(value); i++ {
+		sortableBits <<= 7
+		sortableBits |= uint64(value[i] & 0x7f)
+	}
+	bits := (sortableBits << uint(shift)) ^ 0x8000000000000000
+	if int64(bits) < 0 {
+		bits ^= 0x7fffffffffffffff
+	}
+
+	return math.Float64frombits(bits), true
+}
+
+// Raw returns a SearchResultRaw which can be used to access the raw byte data from search queries.
+// Calling this function invalidates the underlying SearchResult which will no longer be able to be used.
+// VOLATILE: This API is subject to change at any time.
+func (r *SearchResult) Raw() *SearchResultRaw {
+	vr := &SearchResultRaw{
+		reader: r.reader,
+	}
+
+	r.reader = nil
+	return vr
+}
+
+// Next assigns the next result from the results into the value pointer, returning whether the read was successful.
+func (r *SearchResult) Next() bool {
+	if r.reader == nil {
+		return false
+	}
+
+	rowBytes := r.reader.NextRow()
+	if rowBytes == nil {
+		r.finished = true
+		return false
+	}
+
+	r.currentRow = SearchRow{}
+
+	var rowData jsonSearchRow
+	if err := json.Unmarshal(rowBytes, &rowData); err != nil {
+		// This should never happen but if it does then lets store it in a best efforts basis and maybe the next
+		// row will be ok. We can then return this from .Err().
+		r.jsonErr = err
+		return true
+	}
+
+	r.currentRow.Index = rowData.Index
+	r.currentRow.ID = rowData.ID
+	r.currentRow.Score = rowData.Score
+	if len(rowData.Explanation) > 0 {
+		if err := json.Unmarshal(rowData.Explanation, &r.currentRow.Explanation); err != nil {
+			r.jsonErr = err
+		}
+	}
+	r.currentRow.Fragments = rowData.Fragments
+	r.currentRow.rowBytes = rowBytes
+	r.currentRow.fieldsBytes = rowData.Fields
+	r.currentRow.explanationBytes = rowData.Explanation
+	r.currentRow.Sort = rowData.Sort
+	if len(rowData.Sort) > 0 {
+		r.lastSortKey = rowData.Sort
+	}
+	if r.collapser != nil {
+		r.currentRow.CollapsedHits = r.collapser.currentCollapsed()
+	}
+
+	if r.geoDistanceSortIdx >= 0 && r.geoDistanceSortIdx < len(rowData.Sort) {
+		if distance, ok := parseSearchSortDistance(rowData.Sort[r.geoDistanceSortIdx]); ok {
+			r.currentRow.GeoDistance = &distance
+		}
+	}
+
+	locations := make(map[string]map[string][]SearchRowLocation)
+	for fieldName, fieldData := range rowData.Locations {
+		terms := make(map[string][]SearchRowLocation)
+		for termName, termData := range fieldData {
+			locations := make([]SearchRowLocation, len(termData))
+			for locIdx, locData := range termData {
+				err := locations[locIdx].fromData(locData)
+				if err != nil {
+					logWarnf("failed to parse search query location data: %s", err)
+				}
+			}
+			terms[termName] = locations
+		}
+		locations[fieldName] = terms
+	}
+	r.currentRow.Locations = locations
+
+	return true
+}
+
+// Row returns the contents of the current row.
+func (r *SearchResult) Row() SearchRow {
+	if r.reader == nil {
+		return SearchRow{}
+	}
+
+	return r.currentRow
+}
+
+// LastSortKey returns the sort key of the most recent hit returned by Next. Once all of the rows have been iterated
+// this can be passed to SearchOptions.SearchAfter to fetch the next page of results. This is nil if no hits have been
+// returned.
+// UNCOMMITTED: This API may change in the future.
+func (r *SearchResult) LastSortKey() []string {
+	return r.lastSortKey
+}
+
+// Spool reads all of the remaining rows from the stream, holding them in memory up to the configured budget and
+// buffering any beyond it to a temporary file. Once spooled, MetaData and Facets can be accessed before the rows are
+// iterated. The response stream is closed by spooling, Close must still be called to remove any temporary file.
+// UNCOMMITTED: This API may change in the future.
+func (r *SearchResult) Spool(opts *ResultSpoolOptions) error {
+	if r.reader == nil {
+		return r.Err()
+	}
+
+	if r.collapser != nil {
+		// Collapsing already reads all of the hits into memory.
+		r.collapser.load()
+		return r.Err()
+	}
+
+	spool := newRowSpool(r.reader, opts)
+	r.reader = spool
+	return spool.spoolErr
+}
+
+// Err returns any errors that have occurred on the stream
+func (r *SearchResult) Err() error {
+	if r.reader == nil {
+		return errors.New("result object is no longer valid")
+	}
+
+	err := r.reader.Err()
+	if err != nil {
+		r.outcome.observe(err)
+		return maybeEnhanceSearchError(err)
+	}
+	// This is an error from json unmarshal so no point in trying to enhance it.
+	return r.jsonErr
+}
+
+// Close marks the results as closed, returning any errors that occurred during reading the results.
+func (r *SearchResult) Close() error {
+	if r.reader == nil {
+		return r.Err()
+	}
+
+	err := r.reader.Close()
+	if err != nil {
+		r.outcome.observe(err)
+		return maybeEnhanceSearchError(err)
+	}
+
+	return nil
+}
+
+func (r *SearchResult) setStreamOutcome(outcome *streamOutcome) {
+	r.outcome = outcome
+}
+
+func (r *SearchResult) getJSONResp() (jsonSearchResponse, error) {
+	metaDataBytes, err := r.reader.MetaData()
+	if err != nil {
+		return jsonSearchResponse{}, err
+	}
+
+	var jsonResp jsonSearchResponse
+	err = json.Unmarshal(metaDataBytes, &jsonResp)
+	if err != nil {
+		return jsonSearchResponse{}, err
+	}
+
+	return jsonResp, nil
+}
+
+// MetaData returns any meta-data that was available from this query.  Note that
+// the meta-data will only be available once the object has been closed (either
+// implicitly or explicitly).
+func (r *SearchResult) MetaData() (*SearchMetaData, error) {
+	if r.reader == nil {
+		return nil, r.Err()
+	}
+
+	jsonResp, err := r.getJSONResp()
+	if err != nil {
+		return nil, err
+	}
+
+	var metaData SearchMetaData
+	err = metaData.fromData(jsonResp)
+	if err != nil {
+		return nil, err
+	}
+	if r.collapser != nil {
+		metaData.CollapsedHits = r.collapser.totalCollapsed()
+	}
+
+	return &metaData, nil
+}
+
+// Facets returns any facets that were returned with this query. If the facets have not yet been received then any
+// remaining rows are spooled, as with Spool, so that the facets are available before the rows have been iterated.
+func (r *SearchResult) Facets() (map[string]SearchFacetResult, error) {
+	if r.reader == nil {
+		return nil, r.Err()
+	}
+
+	jsonFacets, err := r.getJSONFacets()
+	if err != nil {
+		return nil, err
+	}
+
+	facets := make(map[string]SearchFacetResult)
+	for facetName, facetData := range jsonFacets {
+		var facet SearchFacetResult
+		err := facet.fromData(facetData)
+		if err != nil {
+			return nil, err
+		}
+
+		facets[facetName] = facet
+	}
+
+	return facets, nil
+}
+
+func (r *SearchResult) getJSONFacets() (map[string]jsonSearchFacet, error) {
+	if facetsReader, ok := r.reader.(searchFacetsReader); ok {
+		if facets, ok := facetsReader.receivedFacets(); ok {
+			return facets, nil
+		}
+	}
+
+	if _, spooled := r.reader.(*rowSpool); !spooled && !r.finished {
+		if err := r.Spool(nil); err != nil {
+			return nil, err
+		}
+	}
+
+	jsonResp, err := r.getJSONResp()
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonResp.Facets, nil
+}
+
+type searchFacetKind int
+
+const (
+	searchFacetKindTerm searchFacetKind = iota + 1
+	searchFacetKindNumericRange
+	searchFacetKindDateRange
+)
+
+func (r *SearchResult) typedFacet(name string, kind searchFacetKind) (*SearchFacetResult, error) {
+	facets, err := r.Facets()
+	if err != nil {
+		return nil, err
+	}
+
+	facet, ok := facets[name]
+	if !ok {
+		return nil, makeInvalidArgumentsError(fmt.Sprintf("no facet named %s was returned", name))
+	}
+
+	// Where the facet was not built using the search package, such as with UnmarshalFTSRequest, the kind can only
+	// be inferred from the results.
+	var actualKind searchFacetKind
+	switch r.facetRequests[name].(type) {
+	case *cbsearch.TermFacet:
+		actualKind = searchFacetKindTerm
+	case *cbsearch.NumericFacet:
+		actualKind = searchFacetKindNumericRange
+	case *cbsearch.DateFacet:
+		actualKind = searchFacetKindDateRange
+	default:
+		switch {
+		case len(facet.Terms) > 0:
+			actualKind = searchFacetKindTerm
+		case len(facet.NumericRanges) > 0:
+			actualKind = searchFacetKindNumericRange
+		case len(facet.DateRanges) > 0:
+			actualKind = searchFacetKindDateRange
+		default:
+			actualKind = kind
+		}
+	}
+	if actualKind != kind {
+		return nil, makeInvalidArgumentsError(fmt.Sprintf("facet %s is not of the requested type", name))
+	}
+
+	return &facet, nil
+}
+
+// TermFacet returns the result of the named term facet. As with Facets, any remaining rows are spooled if the facets
+// have not yet been received.
+// UNCOMMITTED: This API may change in the future.
+func (r *SearchResult) TermFacet(name string) (*SearchTermFacet, error) {
+	facet, err := r.typedFacet(name, searchFacetKindTerm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchTermFacet{
+		Name:    facet.Name,
+		Field:   facet.Field,
+		Total:   facet.Total,
+		Missing: facet.Missing,
+		Other:   facet.Other,
+		Terms:   facet.Terms,
+	}, nil
+}
+
+// NumericRangeFacet returns the result of the named numeric range facet. As with Facets, any remaining rows are
+// spooled if the facets have not yet been received.
+// UNCOMMITTED: This API may change in the future.
+func (r *SearchResult) NumericRangeFacet(name string) (*SearchNumericRangeFacet, error) {
+	facet, err := r.typedFacet(name, searchFacetKindNumericRange)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchNumericRangeFacet{
+		Name:          facet.Name,
+		Field:         facet.Field,
+		Total:         facet.Total,
+		Missing:       facet.Missing,
+		Other:         facet.Other,
+		NumericRanges: facet.NumericRanges,
+	}, nil
+}
+
+// DateRangeFacet returns the result of the named date range facet. As with Facets, any remaining rows are spooled if
+// the facets have not yet been received.
+// UNCOMMITTED: This API may change in the future.
+func (r *SearchResult) DateRangeFacet(name string) (*SearchDateRangeFacet, error) {
+	facet, err := r.typedFacet(name, searchFacetKindDateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchDateRangeFacet{
+		Name:       facet.Name,
+		Field:      facet.Field,
+		Total:      facet.Total,
+		Missing:    facet.Missing,
+		Other:      facet.Other,
+		DateRanges: facet.DateRanges,
+	}, nil
+}
@@ -7,6 +7,8 @@ type searchIndexProvider interface {
 	DropIndex(scope *Scope, indexName string, opts *DropSearchIndexOptions) error
 	AnalyzeDocument(scope *Scope, indexName string, doc interface{}, opts *AnalyzeDocumentOptions) ([]interface{}, error)
 	GetIndexedDocumentsCount(scope *Scope, indexName string, opts *GetIndexedDocumentsCountOptions) (uint64, error)
+	GetIndexedDocumentsCountByPartition(scope *Scope, indexName string, opts *GetIndexedDocumentsCountByPartitionOptions) (*SearchIndexedDocumentsCount, error)
+	GetIndexStats(scope *Scope, indexName string, opts *GetSearchIndexStatsOptions) (map[string]interface{}, error)
 	PauseIngest(scope *Scope, indexName string, opts *PauseIngestSearchIndexOptions) error
 	ResumeIngest(scope *Scope, indexName string, opts *ResumeIngestSearchIndexOptions) error
 	AllowQuerying(scope *Scope, indexName string, opts *AllowQueryingSearchIndexOptions) error
@@ -1,3 +1,5 @@
+//go:build !gocb_nosearch
+
 package gocb
 
 import (
@@ -16,6 +18,7 @@ import (
 type searchIndexProviderCore struct {
 	mgmtProvider      mgmtProvider
 	searchCapVerifier searchCapabilityVerifier
+	timeouts          TimeoutsConfig
 
 	tracer *tracerWrapper
 }
@@ -415,6 +418,120 @@ func (sm *searchIndexProviderCore) GetIndexedDocumentsCount(scope *Scope, indexN
 	return count.Count, nil
 }
 
+func (sm *searchIndexProviderCore) GetIndexedDocumentsCountByPartition(scope *Scope, indexName string,
+	opts *GetIndexedDocumentsCountByPartitionOptions) (*SearchIndexedDocumentsCount, error) {
+	if scope != nil && sm.scopedIndexesUnsupported() {
+		return nil, wrapError(ErrFeatureNotAvailable, "scoped indexes cannot be used with this server version")
+	}
+
+	path := "/api/stats/index/" + url.PathEscape(sm.statsIndexName(scope, indexName))
+	span := sm.tracer.createSpan(opts.ParentSpan, "manager_search_get_indexed_documents_count_by_partition", "management")
+	span.SetAttribute("db.operation", "GET "+path)
+	defer span.End()
+
+	count := &SearchIndexedDocumentsCount{
+		Partitions: make(map[string]uint64),
+	}
+	err := sm.getStats(opts.Context, span, path, opts.Timeout, opts.RetryStrategy, func(jsonDec *json.Decoder) error {
+		var stats struct {
+			PIndexes map[string]struct {
+				Basic struct {
+					DocCount uint64 `json:"DocCount"`
+				} `json:"basic"`
+			} `json:"pindexes"`
+		}
+		if err := jsonDec.Decode(&stats); err != nil {
+			return err
+		}
+
+		// A replica of a partition shares its name, the count of the most up to date copy is used.
+		for name, pindex := range stats.PIndexes {
+			if pindex.Basic.DocCount >= count.Partitions[name] {
+				count.Partitions[name] = pindex.Basic.DocCount
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, partitionCount := range count.Partitions {
+		count.Total += partitionCount
+	}
+
+	return count, nil
+}
+
+func (sm *searchIndexProviderCore) GetIndexStats(scope *Scope, indexName string, opts *GetSearchIndexStatsOptions) (map[string]interface{}, error) {
+	if scope != nil && sm.scopedIndexesUnsupported() {
+		return nil, wrapError(ErrFeatureNotAvailable, "scoped indexes cannot be used with this server version")
+	}
+
+	path := "/api/nsstats/index/" + url.PathEscape(sm.statsIndexName(scope, indexName))
+	span := sm.tracer.createSpan(opts.ParentSpan, "manager_search_get_stats", "management")
+	span.SetAttribute("db.operation", "GET "+path)
+	defer span.End()
+
+	stats := make(map[string]interface{})
+	err := sm.getStats(opts.Context, span, path, opts.Timeout, opts.RetryStrategy, func(jsonDec *json.Decoder) error {
+		var nodeStats map[string]interface{}
+		if err := jsonDec.Decode(&nodeStats); err != nil {
+			return err
+		}
+
+		mergeNodeStats(stats, nodeStats)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// statsIndexName returns the name which the stats endpoints use for an index, scoped indexes are identified by their
+// fully qualified name.
+func (sm *searchIndexProviderCore) statsIndexName(scope *Scope, indexName string) string {
+	if scope == nil {
+		return indexName
+	}
+
+	return fmt.Sprintf("%s.%s.%s", scope.bucket.bucketName, scope.scopeName, indexName)
+}
+
+// getStats fetches stats from every search node, as each node only reports on the index partitions that it hosts.
+func (sm *searchIndexProviderCore) getStats(ctx context.Context, span RequestSpan, path string, timeout time.Duration,
+	retryStrategy RetryStrategy, handle func(jsonDec *json.Decoder) error) error {
+	if timeout == 0 {
+		timeout = sm.timeouts.ManagementTimeout
+	}
+
+	req := mgmtRequest{
+		Service:       ServiceTypeSearch,
+		Method:        "GET",
+		Path:          path,
+		IsIdempotent:  true,
+		RetryStrategy: retryStrategy,
+		Timeout:       timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	return forEachServiceNode(ctx, sm.mgmtProvider, req, func(req *mgmtRequest, resp *mgmtResponse) error {
+		if resp.StatusCode != 200 {
+			idxErr := sm.tryParseErrorMessage(req, resp)
+			if idxErr != nil {
+				return idxErr
+			}
+
+			return makeMgmtBadStatusError("failed to get the search index stats", req, resp)
+		}
+
+		return handle(json.NewDecoder(resp.Body))
+	})
+}
+
 func (sm *searchIndexProviderCore) PauseIngest(scope *Scope, indexName string, opts *PauseIngestSearchIndexOptions) error {
 	if opts == nil {
 		opts = &PauseIngestSearchIndexOptions{}
@@ -684,30 +801,3 @@ func (sm *searchIndexProviderCore) doMgmtRequest(ctx context.Context, req mgmtRe
 
 	return resp, nil
 }
-
-type jsonSearchIndexResp struct {
-	Status   string           `json:"status"`
-	IndexDef *jsonSearchIndex `json:"indexDef"`
-}
-
-type jsonSearchIndexDefs struct {
-	IndexDefs   map[string]jsonSearchIndex `json:"indexDefs"`
-	ImplVersion string                     `json:"implVersion"`
-}
-
-type jsonSearchIndexesResp struct {
-	Status    string              `json:"status"`
-	IndexDefs jsonSearchIndexDefs `json:"indexDefs"`
-}
-
-type jsonSearchIndex struct {
-	UUID         string                 `json:"uuid"`
-	Name         string                 `json:"name"`
-	SourceName   string                 `json:"sourceName"`
-	Type         string                 `json:"type"`
-	Params       map[string]interface{} `json:"params"`
-	SourceUUID   string                 `json:"sourceUUID"`
-	SourceParams map[string]interface{} `json:"sourceParams"`
-	SourceType   string                 `json:"sourceType"`
-	PlanParams   map[string]interface{} `json:"planParams"`
-}
@@ -1,3 +1,5 @@
+//go:build !gocb_nocouchbase2 && !gocb_nosearch
+
 package gocb
 
 import (
@@ -343,6 +345,15 @@ func (sip *searchIndexProviderPs) GetIndexedDocumentsCount(scope *Scope, indexNa
 	return resp.Count, nil
 }
 
+func (sip *searchIndexProviderPs) GetIndexedDocumentsCountByPartition(scope *Scope, indexName string,
+	opts *GetIndexedDocumentsCountByPartitionOptions) (*SearchIndexedDocumentsCount, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
+func (sip *searchIndexProviderPs) GetIndexStats(scope *Scope, indexName string, opts *GetSearchIndexStatsOptions) (map[string]interface{}, error) {
+	return nil, ErrFeatureNotAvailable
+}
+
 func (sip *searchIndexProviderPs) PauseIngest(scope *Scope, indexName string, opts *PauseIngestSearchIndexOptions) error {
 	manager := sip.newOpManager(opts.ParentSpan, "manager_search_pause_ingest", map[string]interface{}{
 		"db.operation": "PauseIndexIngest",
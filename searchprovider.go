@@ -1,8 +1,15 @@
 package gocb
 
-import cbsearch "github.com/couchbase/gocb/v2/search"
+import (
+	cbsearch "github.com/couchbase/gocb/v2/search"
+	"github.com/couchbase/gocbcore/v10"
+)
 
 type searchProvider interface {
 	SearchQuery(indexName string, query cbsearch.Query, opts *SearchOptions) (*SearchResult, error)
 	Search(scope *Scope, indexName string, request SearchRequest, opts *SearchOptions) (*SearchResult, error)
 }
+
+type searchCapabilityVerifier interface {
+	SearchCapabilityStatus(cap gocbcore.SearchCapability) gocbcore.CapabilityStatus
+}
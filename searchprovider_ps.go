@@ -1,3 +1,5 @@
+//go:build !gocb_nocouchbase2 && !gocb_nosearch
+
 package gocb
 
 import (
@@ -33,6 +35,12 @@ func (search *searchProviderPs) SearchQuery(indexName string, query cbsearch.Que
 	if len(opts.Raw) > 0 {
 		return nil, wrapError(ErrFeatureNotAvailable, "the Raw search option is not supported by the couchbase2 protocol")
 	}
+	if len(opts.SearchAfter) > 0 || len(opts.SearchBefore) > 0 {
+		return nil, wrapError(ErrFeatureNotAvailable, "the SearchAfter and SearchBefore search options are not supported by the couchbase2 protocol")
+	}
+	if opts.CollapseField != "" {
+		return nil, wrapError(ErrFeatureNotAvailable, "the CollapseField search option is not supported by the couchbase2 protocol")
+	}
 
 	manager := search.managerProvider.NewManager(opts.ParentSpan, "search", map[string]interface{}{
 		"db.operation": indexName,
@@ -150,19 +158,36 @@ func (search *searchProviderPs) SearchQuery(indexName string, query cbsearch.Que
 	close(doneCh)
 	if err != nil {
 		reqCancel()
+		setSpanOutcome(manager.TraceSpan(), userCtx, err)
 		return nil, search.makeError(err, query, atomic.LoadUint32(&cancellationIsTimeout) == 1, manager.ElapsedTime(), manager.RetryInfo())
 	}
 
-	return newSearchResult(&psSearchRowReader{
+	// The user context continues to govern the stream once the first rows have been received, so that long running
+	// searches can be cancelled whilst the rows are being read. reqCtx is cancelled once the stream is finished.
+	if userCtx.Done() != nil {
+		go func() {
+			select {
+			case <-userCtx.Done():
+				setSpanOutcome(manager.TraceSpan(), userCtx, resultsContextError(userCtx.Err()))
+				reqCancel()
+			case <-reqCtx.Done():
+			}
+		}()
+	}
+
+	res := newSearchResult(&psSearchRowReader{
 		client:     client,
 		cancelFunc: reqCancel,
 		query:      query,
+		userCtx:    userCtx,
 
 		nextRows:      firstRows.GetHits(),
 		nextRowsIndex: 0,
 		meta:          firstRows.MetaData,
 		facets:        firstRows.Facets,
-	}), nil
+	})
+	res.facetRequests = opts.Facets
+	return res, nil
 }
 
 func (search *searchProviderPs) makeError(err error, query interface{}, hasTimedOut bool, elapsed time.Duration,
@@ -202,6 +227,7 @@ type psSearchRowReader struct {
 	cancelFunc    context.CancelFunc
 	facets        map[string]*search_v1.SearchQueryResponse_FacetResult
 	query         cbsearch.Query
+	userCtx       context.Context
 
 	manager *psOpManagerDefault
 }
@@ -236,7 +262,9 @@ func (reader *psSearchRowReader) NextRow() []byte {
 	reader.nextRows = res.GetHits()
 	reader.nextRowsIndex = 1
 	reader.meta = res.MetaData
-	reader.facets = res.Facets
+	if len(res.Facets) > 0 {
+		reader.facets = res.Facets
+	}
 	if len(res.Hits) > 0 {
 		convertedRow, err := psSearchRowToJSONBytes(res.Hits[0])
 		if errors.Is(err, io.EOF) {
@@ -265,6 +293,20 @@ func (reader *psSearchRowReader) Close() error {
 	return err
 }
 
+// receivedFacets returns the facets as soon as they have been received, which can be before the rows have been read.
+func (reader *psSearchRowReader) receivedFacets() (map[string]jsonSearchFacet, bool) {
+	if len(reader.facets) == 0 {
+		return nil, false
+	}
+
+	facets, err := psSearchFacetToJSONSearchFacet(reader.facets)
+	if err != nil {
+		return nil, false
+	}
+
+	return facets, true
+}
+
 func (reader *psSearchRowReader) MetaData() ([]byte, error) {
 	if reader.err != nil {
 		return nil, reader.Err()
@@ -299,6 +341,12 @@ func (reader *psSearchRowReader) Err() error {
 		return nil
 	}
 
+	// The stream is ended by cancelling the request context when the user context is done, so the stream error is
+	// always a cancellation even if the deadline passed.
+	if ctxErr := reader.userCtx.Err(); ctxErr != nil {
+		return resultsContextError(ctxErr)
+	}
+
 	return mapPsErrorToGocbError(err, true)
 }
 
@@ -368,6 +416,16 @@ func psSearchRowFragmentToMap(fragmentMap map[string]*search_v1.SearchQueryRespo
 	return result
 }
 
+// helper util to convert the JSON encoded explanation of a PS SearchQueryRow, which would otherwise be re-encoded as
+// base64 when the row is converted to JSON.
+func psSearchRowExplanation(explanation []byte) json.RawMessage {
+	if len(explanation) == 0 {
+		return nil
+	}
+
+	return explanation
+}
+
 // helper util to convert PS's SearchQueryRow to jsonSearchRow.
 func psSearchRowToJSONSearchRow(row *search_v1.SearchQueryResponse_SearchQueryRow) (jsonSearchRow, error) {
 	fieldRaw, err := json.Marshal(row.Fields)
@@ -379,7 +437,7 @@ func psSearchRowToJSONSearchRow(row *search_v1.SearchQueryResponse_SearchQueryRo
 		ID:          row.Id,
 		Index:       row.Index,
 		Score:       row.Score,
-		Explanation: row.Explanation,
+		Explanation: psSearchRowExplanation(row.Explanation),
 		Locations:   psSearchRowLocationToJSONSearchRowLocations(row.Locations),
 		Fragments:   psSearchRowFragmentToMap(row.Fragments),
 		Fields:      fieldRaw,
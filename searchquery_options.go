@@ -69,6 +69,27 @@ type SearchOptions struct {
 	// If set to true, will include the SearchRowLocations.
 	IncludeLocations bool
 
+	// CollapseField collapses the hits of the search client side, keeping only the best scoring hit for each value
+	// of the field. The field must be stored within the index, and is added to Fields if not already present. The
+	// number of hits collapsed into each row is available from SearchRow.CollapsedHits. All of the hits are read
+	// from the stream before the first is returned, so Limit should be used to bound the number of hits.
+	// This option is intended for servers which do not support collapsing natively and is not supported by the
+	// couchbase2 protocol.
+	// UNCOMMITTED: This API may change in the future.
+	CollapseField string
+
+	// SearchAfter returns the hits which sort after the provided sort key, allowing deep pagination without the cost
+	// of Skip. The sort key of a hit is available from SearchRow.Sort, and the sort key of the last hit returned from
+	// SearchResult.LastSortKey. The key must have an entry for each Sort, and Sort should end in a unique field such
+	// as the document ID so that the order is total. Cannot be used with Skip or SearchBefore.
+	// UNCOMMITTED: This API may change in the future.
+	SearchAfter []string
+
+	// SearchBefore returns the hits which sort before the provided sort key, allowing pagination backwards from a
+	// page obtained with SearchAfter. Cannot be used with Skip or SearchAfter.
+	// UNCOMMITTED: This API may change in the future.
+	SearchBefore []string
+
 	// Internal: This should never be used and is not supported.
 	Internal struct {
 		User string
@@ -90,14 +111,45 @@ func (opts *SearchOptions) toMap(indexName string) (map[string]interface{}, erro
 		data["explain"] = opts.Explain
 	}
 
-	if len(opts.Fields) > 0 {
-		data["fields"] = opts.Fields
+	fields := opts.Fields
+	if opts.CollapseField != "" && !containsSearchField(fields, opts.CollapseField) {
+		fields = append(append([]string(nil), fields...), opts.CollapseField)
+	}
+	if len(fields) > 0 {
+		data["fields"] = fields
 	}
 
 	if len(opts.Sort) > 0 {
 		data["sort"] = opts.Sort
 	}
 
+	if len(opts.SearchAfter) > 0 || len(opts.SearchBefore) > 0 {
+		if len(opts.SearchAfter) > 0 && len(opts.SearchBefore) > 0 {
+			return nil, makeInvalidArgumentsError("SearchAfter and SearchBefore must be used exclusively")
+		}
+		if opts.Skip > 0 {
+			return nil, makeInvalidArgumentsError("Skip cannot be used with SearchAfter or SearchBefore")
+		}
+
+		// Without a sort the hits are sorted by score alone.
+		sortLen := len(opts.Sort)
+		if sortLen == 0 {
+			sortLen = 1
+		}
+
+		if len(opts.SearchAfter) > 0 {
+			if len(opts.SearchAfter) != sortLen {
+				return nil, makeInvalidArgumentsError("SearchAfter must have a value for each sort")
+			}
+			data["search_after"] = opts.SearchAfter
+		} else {
+			if len(opts.SearchBefore) != sortLen {
+				return nil, makeInvalidArgumentsError("SearchBefore must have a value for each sort")
+			}
+			data["search_before"] = opts.SearchBefore
+		}
+	}
+
 	if opts.Highlight != nil {
 		highlight := make(map[string]interface{})
 		highlight["style"] = string(opts.Highlight.Style)
@@ -167,3 +219,12 @@ func (opts *SearchOptions) toMap(indexName string) (map[string]interface{}, erro
 
 	return data, nil
 }
+
+func containsSearchField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field || f == "*" {
+			return true
+		}
+	}
+	return false
+}
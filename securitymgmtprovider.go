@@ -0,0 +1,11 @@
+package gocb
+
+type securityManagementProvider interface {
+	GetTrustedCAs(opts *GetTrustedCAsOptions) ([]TrustedCA, error)
+	GetNodeCertificates(opts *GetNodeCertificatesOptions) ([]NodeCertificate, error)
+	GetClientCertAuthSettings(opts *GetClientCertAuthSettingsOptions) (*ClientCertAuthSettings, error)
+	UpdateClientCertAuthSettings(settings ClientCertAuthSettings, opts *UpdateClientCertAuthSettingsOptions) error
+	GetTLSSettings(opts *GetTLSSettingsOptions) (*TLSSettings, error)
+	UpdateTLSSettings(settings TLSSettings, opts *UpdateTLSSettingsOptions) error
+	ReloadNodeCertificates(opts *ReloadNodeCertificatesOptions) error
+}
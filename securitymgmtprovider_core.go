@@ -0,0 +1,404 @@
+package gocb
+
+import (
+	"encoding/json"
+	"net"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+type securityManagementProviderCore struct {
+	provider mgmtProvider
+
+	tracer *tracerWrapper
+}
+
+func (sm *securityManagementProviderCore) GetTrustedCAs(opts *GetTrustedCAsOptions) ([]TrustedCA, error) {
+	span := sm.tracer.createSpan(opts.ParentSpan, "manager_security_get_trusted_cas", "management")
+	span.SetAttribute("db.operation", "GET /pools/default/trustedCAs")
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "GET",
+		Path:          "/pools/default/trustedCAs",
+		RetryStrategy: opts.RetryStrategy,
+		IsIdempotent:  true,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := sm.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return nil, makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, makeMgmtBadStatusError("failed to get trusted CAs", &req, resp)
+	}
+
+	var casData []jsonTrustedCA
+	jsonDec := json.NewDecoder(resp.Body)
+	err = jsonDec.Decode(&casData)
+	if err != nil {
+		return nil, err
+	}
+
+	cas := make([]TrustedCA, len(casData))
+	for caIdx, caData := range casData {
+		err := cas[caIdx].fromData(caData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cas, nil
+}
+
+func (sm *securityManagementProviderCore) GetNodeCertificates(opts *GetNodeCertificatesOptions) ([]NodeCertificate, error) {
+	span := sm.tracer.createSpan(opts.ParentSpan, "manager_security_get_node_certificates", "management")
+	span.SetAttribute("db.operation", "GET /pools/default/certificates")
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "GET",
+		Path:          "/pools/default/certificates",
+		RetryStrategy: opts.RetryStrategy,
+		IsIdempotent:  true,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := sm.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return nil, makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, makeMgmtBadStatusError("failed to get node certificates", &req, resp)
+	}
+
+	var certsData []jsonNodeCertificate
+	jsonDec := json.NewDecoder(resp.Body)
+	err = jsonDec.Decode(&certsData)
+	if err != nil {
+		return nil, err
+	}
+
+	certs := make([]NodeCertificate, len(certsData))
+	for certIdx, certData := range certsData {
+		err := certs[certIdx].fromData(certData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return certs, nil
+}
+
+type jsonClientCertAuthPrefix struct {
+	Path      string `json:"path"`
+	Prefix    string `json:"prefix"`
+	Delimiter string `json:"delimiter"`
+}
+
+type jsonClientCertAuthSettings struct {
+	State    string                     `json:"state"`
+	Prefixes []jsonClientCertAuthPrefix `json:"prefixes"`
+}
+
+type jsonTLSSettings struct {
+	TLSMinVersion    string   `json:"tlsMinVersion"`
+	CipherSuites     []string `json:"cipherSuites"`
+	HonorCipherOrder bool     `json:"honorCipherOrder"`
+}
+
+func (sm *securityManagementProviderCore) GetClientCertAuthSettings(opts *GetClientCertAuthSettingsOptions) (*ClientCertAuthSettings, error) {
+	span := sm.tracer.createSpan(opts.ParentSpan, "manager_security_get_client_cert_auth", "management")
+	span.SetAttribute("db.operation", "GET /settings/clientCertAuth")
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "GET",
+		Path:          "/settings/clientCertAuth",
+		RetryStrategy: opts.RetryStrategy,
+		IsIdempotent:  true,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := sm.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return nil, makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, makeMgmtBadStatusError("failed to get client certificate auth settings", &req, resp)
+	}
+
+	var settingsData jsonClientCertAuthSettings
+	jsonDec := json.NewDecoder(resp.Body)
+	err = jsonDec.Decode(&settingsData)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &ClientCertAuthSettings{
+		State:    ClientCertAuthState(settingsData.State),
+		Prefixes: make([]ClientCertAuthPrefix, len(settingsData.Prefixes)),
+	}
+	for i, prefix := range settingsData.Prefixes {
+		settings.Prefixes[i] = ClientCertAuthPrefix{
+			Path:      prefix.Path,
+			Prefix:    prefix.Prefix,
+			Delimiter: prefix.Delimiter,
+		}
+	}
+
+	return settings, nil
+}
+
+func (sm *securityManagementProviderCore) UpdateClientCertAuthSettings(settings ClientCertAuthSettings, opts *UpdateClientCertAuthSettingsOptions) error {
+	span := sm.tracer.createSpan(opts.ParentSpan, "manager_security_update_client_cert_auth", "management")
+	span.SetAttribute("db.operation", "POST /settings/clientCertAuth")
+	defer span.End()
+
+	settingsData := jsonClientCertAuthSettings{
+		State:    string(settings.State),
+		Prefixes: make([]jsonClientCertAuthPrefix, len(settings.Prefixes)),
+	}
+	for i, prefix := range settings.Prefixes {
+		settingsData.Prefixes[i] = jsonClientCertAuthPrefix{
+			Path:      prefix.Path,
+			Prefix:    prefix.Prefix,
+			Delimiter: prefix.Delimiter,
+		}
+	}
+
+	b, err := json.Marshal(settingsData)
+	if err != nil {
+		return err
+	}
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "POST",
+		Path:          "/settings/clientCertAuth",
+		Body:          b,
+		ContentType:   "application/json",
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := sm.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 && resp.StatusCode != 202 {
+		return makeMgmtBadStatusError("failed to update client certificate auth settings", &req, resp)
+	}
+
+	return nil
+}
+
+func (sm *securityManagementProviderCore) GetTLSSettings(opts *GetTLSSettingsOptions) (*TLSSettings, error) {
+	span := sm.tracer.createSpan(opts.ParentSpan, "manager_security_get_tls_settings", "management")
+	span.SetAttribute("db.operation", "GET /settings/security")
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "GET",
+		Path:          "/settings/security",
+		RetryStrategy: opts.RetryStrategy,
+		IsIdempotent:  true,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := sm.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return nil, makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, makeMgmtBadStatusError("failed to get tls settings", &req, resp)
+	}
+
+	var settingsData jsonTLSSettings
+	jsonDec := json.NewDecoder(resp.Body)
+	err = jsonDec.Decode(&settingsData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TLSSettings{
+		MinVersion:       TLSMinVersion(settingsData.TLSMinVersion),
+		CipherSuites:     settingsData.CipherSuites,
+		HonorCipherOrder: &settingsData.HonorCipherOrder,
+	}, nil
+}
+
+func (sm *securityManagementProviderCore) UpdateTLSSettings(settings TLSSettings, opts *UpdateTLSSettingsOptions) error {
+	span := sm.tracer.createSpan(opts.ParentSpan, "manager_security_update_tls_settings", "management")
+	span.SetAttribute("db.operation", "POST /settings/security")
+	defer span.End()
+
+	reqForm := make(url.Values)
+	if settings.MinVersion != "" {
+		reqForm.Add("tlsMinVersion", string(settings.MinVersion))
+	}
+	if settings.CipherSuites != nil {
+		cipherSuitesJSON, err := json.Marshal(settings.CipherSuites)
+		if err != nil {
+			return err
+		}
+		reqForm.Add("cipherSuites", string(cipherSuitesJSON))
+	}
+	if settings.HonorCipherOrder != nil {
+		reqForm.Add("honorCipherOrder", strconv.FormatBool(*settings.HonorCipherOrder))
+	}
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "POST",
+		Path:          "/settings/security",
+		Body:          []byte(reqForm.Encode()),
+		ContentType:   "application/x-www-form-urlencoded",
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := sm.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return makeMgmtBadStatusError("failed to update tls settings", &req, resp)
+	}
+
+	return nil
+}
+
+func (sm *securityManagementProviderCore) ReloadNodeCertificates(opts *ReloadNodeCertificatesOptions) error {
+	span := sm.tracer.createSpan(opts.ParentSpan, "manager_security_reload_node_certificates", "management")
+	defer span.End()
+
+	poolNodes, mgmtEndpoint, err := getPoolNodes(sm.provider, sm.tracer, nodeRequestOptions{
+		Timeout:       opts.Timeout,
+		RetryStrategy: opts.RetryStrategy,
+		ParentSpan:    span,
+		Context:       opts.Context,
+	})
+	if err != nil {
+		return err
+	}
+
+	nodesData := poolNodes
+	if len(opts.Nodes) > 0 {
+		nodesData = make([]jsonNode, len(opts.Nodes))
+		for i, hostname := range opts.Nodes {
+			nodeData, err := findNode(poolNodes, hostname)
+			if err != nil {
+				return err
+			}
+			nodesData[i] = *nodeData
+		}
+	}
+
+	// Each node must reload its own certificate, so the requests are sent to each node directly using the same scheme
+	// and network that the SDK is connected to the cluster with.
+	respURL, err := url.Parse(mgmtEndpoint)
+	if err != nil {
+		return wrapError(err, "failed to parse management endpoint")
+	}
+
+	var nodeServices jsonNodeServices
+	for _, nodeData := range poolNodes {
+		ext, err := nodeData.ext()
+		if err != nil {
+			return err
+		}
+		nodeServices.NodesExt = append(nodeServices.NodesExt, ext)
+	}
+	network := nodeServices.network(respURL)
+
+	scheme, portName := "http", "mgmt"
+	if respURL.Scheme == "https" {
+		scheme, portName = "https", "mgmtSSL"
+	}
+
+	endpoints := make([]string, len(nodesData))
+	for i, nodeData := range nodesData {
+		ext, err := nodeData.ext()
+		if err != nil {
+			return err
+		}
+
+		host, port, ok := ext.address(network, portName, respURL.Hostname())
+		if !ok || port == 0 {
+			return wrapError(ErrServiceNotAvailable, "node "+nodeData.Hostname+" has no "+scheme+
+				" management port on the "+network+" network")
+		}
+		endpoints[i] = scheme + "://" + net.JoinHostPort(host, strconv.Itoa(port))
+	}
+
+	for i, endpoint := range endpoints {
+		err := sm.reloadNodeCertificate(endpoint, span, opts)
+		if err != nil {
+			return wrapError(err, "failed to reload certificate on "+nodesData[i].Hostname)
+		}
+	}
+
+	return nil
+}
+
+func (sm *securityManagementProviderCore) reloadNodeCertificate(endpoint string, parentSpan RequestSpan,
+	opts *ReloadNodeCertificatesOptions) error {
+	span := sm.tracer.createSpan(parentSpan, "manager_security_reload_node_certificate", "management")
+	span.SetAttribute("db.operation", "POST /node/controller/reloadCertificate")
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "POST",
+		Path:          "/node/controller/reloadCertificate",
+		Endpoint:      endpoint,
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := sm.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return makeMgmtBadStatusError("failed to reload node certificate", &req, resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package gocb
+
+import (
+	"encoding/json"
+)
+
+// SubdocSerializer provides an interface for transforming Go values into the raw JSON bytes used as the value of
+// a subdocument mutation spec. Unlike a Transcoder it is applied to individual spec values rather than whole documents.
+// UNCOMMITTED: This API may change in the future.
+type SubdocSerializer interface {
+	// Serialize encodes a Go type into JSON bytes.
+	Serialize(value interface{}) ([]byte, error)
+}
+
+// JSONSubdocSerializer implements the default subdocument serialization behaviour and applies encoding/json to all
+// values. It also implements JSONSerializer, which is used by default for query and analytics parameters and rows.
+// UNCOMMITTED: This API may change in the future.
+type JSONSubdocSerializer struct {
+}
+
+// NewJSONSubdocSerializer returns a new JSONSubdocSerializer.
+// UNCOMMITTED: This API may change in the future.
+func NewJSONSubdocSerializer() *JSONSubdocSerializer {
+	return &JSONSubdocSerializer{}
+}
+
+// Serialize applies JSON encoding to a Go type.
+func (s *JSONSubdocSerializer) Serialize(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Deserialize applies JSON decoding into a Go type.
+func (s *JSONSubdocSerializer) Deserialize(bytes []byte, out interface{}) error {
+	return json.Unmarshal(bytes, out)
+}
+
+// RawJSONSubdocSerializer implements passthrough behaviour of pre-encoded JSON data, the value is written as is
+// without being re-encoded. The caller is responsible for ensuring that the value is valid JSON.
+//
+// This will apply the following behavior to the value:
+// string -> string bytes.
+// *string -> string bytes.
+// []byte -> []byte.
+// *[]byte -> []byte.
+// json.RawMessage -> []byte.
+// *json.RawMessage -> []byte.
+// default -> error.
+// UNCOMMITTED: This API may change in the future.
+type RawJSONSubdocSerializer struct {
+}
+
+// NewRawJSONSubdocSerializer returns a new RawJSONSubdocSerializer.
+// UNCOMMITTED: This API may change in the future.
+func NewRawJSONSubdocSerializer() *RawJSONSubdocSerializer {
+	return &RawJSONSubdocSerializer{}
+}
+
+// Serialize returns the pre-encoded JSON bytes held by value.
+func (s *RawJSONSubdocSerializer) Serialize(value interface{}) ([]byte, error) {
+	switch typeValue := value.(type) {
+	case []byte:
+		return typeValue, nil
+	case *[]byte:
+		return *typeValue, nil
+	case string:
+		return []byte(typeValue), nil
+	case *string:
+		return []byte(*typeValue), nil
+	case json.RawMessage:
+		return typeValue, nil
+	case *json.RawMessage:
+		return *typeValue, nil
+	case *interface{}:
+		return s.Serialize(*typeValue)
+	default:
+		return nil, makeInvalidArgumentsError("only binary and string data is supported by RawJSONSubdocSerializer")
+	}
+}
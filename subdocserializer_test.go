@@ -0,0 +1,61 @@
+package gocb
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+func (suite *UnitTestSuite) TestSubdocSerializerDefault() {
+	spec := UpsertSpec("path", map[string]int{"b": 2, "a": 1}, nil)
+
+	bytes, _, err := jsonMarshalMutateSpec(spec)
+	suite.Require().Nil(err)
+
+	suite.Assert().Equal([]byte(`{"a":1,"b":2}`), bytes)
+}
+
+func (suite *UnitTestSuite) TestSubdocSerializerRawPassthrough() {
+	raw := json.RawMessage(`{"b":2,"a":1}`)
+	serializer := NewRawJSONSubdocSerializer()
+
+	specs := []MutateInSpec{
+		InsertSpec("path", raw, &InsertSpecOptions{Serializer: serializer}),
+		UpsertSpec("path", raw, &UpsertSpecOptions{Serializer: serializer}),
+		ReplaceSpec("path", []byte(raw), &ReplaceSpecOptions{Serializer: serializer}),
+		ArrayAppendSpec("path", string(raw), &ArrayAppendSpecOptions{Serializer: serializer}),
+		ArrayPrependSpec("path", &raw, &ArrayPrependSpecOptions{Serializer: serializer}),
+		ArrayInsertSpec("path[0]", raw, &ArrayInsertSpecOptions{Serializer: serializer}),
+		ArrayAddUniqueSpec("path", []byte("1"), &ArrayAddUniqueSpecOptions{Serializer: serializer}),
+	}
+
+	for i, spec := range specs {
+		bytes, _, err := jsonMarshalMutateSpec(spec)
+		suite.Require().Nil(err)
+
+		if i == len(specs)-1 {
+			suite.Assert().Equal([]byte("1"), bytes)
+			continue
+		}
+
+		suite.Assert().Equal([]byte(raw), bytes)
+	}
+}
+
+func (suite *UnitTestSuite) TestSubdocSerializerRawMultiValue() {
+	spec := ArrayAppendSpec("path", json.RawMessage(` [1,{"b":2,"a":1}] `), &ArrayAppendSpecOptions{
+		HasMultiple: true,
+		Serializer:  NewRawJSONSubdocSerializer(),
+	})
+
+	bytes, _, err := jsonMarshalMutateSpec(spec)
+	suite.Require().Nil(err)
+
+	suite.Assert().Equal([]byte(`1,{"b":2,"a":1}`), bytes)
+}
+
+func (suite *UnitTestSuite) TestSubdocSerializerRawUnsupportedType() {
+	spec := UpsertSpec("path", 22, &UpsertSpecOptions{Serializer: NewRawJSONSubdocSerializer()})
+
+	_, _, err := jsonMarshalMutateSpec(spec)
+	suite.Require().True(errors.Is(err, ErrInvalidArgument))
+}
@@ -17,6 +17,7 @@ type MutateInSpec struct {
 	path       string
 	value      interface{}
 	multiValue bool
+	serializer SubdocSerializer
 }
 
 // GetSpecOptions are the options available to LookupIn subdoc Get operations.
@@ -83,6 +84,10 @@ func CountSpec(path string, opts *CountSpecOptions) LookupInSpec {
 type InsertSpecOptions struct {
 	CreatePath bool
 	IsXattr    bool
+
+	// Serializer is used to serialize the value, if not set then the value is serialized using encoding/json.
+	// UNCOMMITTED: This API may change in the future.
+	Serializer SubdocSerializer
 }
 
 // InsertSpec inserts a value at the specified path within the document.
@@ -98,6 +103,7 @@ func InsertSpec(path string, val interface{}, opts *InsertSpecOptions) MutateInS
 		path:       path,
 		value:      val,
 		multiValue: false,
+		serializer: opts.Serializer,
 	}
 }
 
@@ -105,6 +111,10 @@ func InsertSpec(path string, val interface{}, opts *InsertSpecOptions) MutateInS
 type UpsertSpecOptions struct {
 	CreatePath bool
 	IsXattr    bool
+
+	// Serializer is used to serialize the value, if not set then the value is serialized using encoding/json.
+	// UNCOMMITTED: This API may change in the future.
+	Serializer SubdocSerializer
 }
 
 // UpsertSpec creates a new value at the specified path within the document if it does not exist, if it does exist then it
@@ -121,12 +131,17 @@ func UpsertSpec(path string, val interface{}, opts *UpsertSpecOptions) MutateInS
 		path:       path,
 		value:      val,
 		multiValue: false,
+		serializer: opts.Serializer,
 	}
 }
 
 // ReplaceSpecOptions are the options available to subdocument Replace operations.
 type ReplaceSpecOptions struct {
 	IsXattr bool
+
+	// Serializer is used to serialize the value, if not set then the value is serialized using encoding/json.
+	// UNCOMMITTED: This API may change in the future.
+	Serializer SubdocSerializer
 }
 
 // ReplaceSpec replaces the value of the field at path.
@@ -142,6 +157,7 @@ func ReplaceSpec(path string, val interface{}, opts *ReplaceSpecOptions) MutateI
 		path:       path,
 		value:      val,
 		multiValue: false,
+		serializer: opts.Serializer,
 	}
 }
 
@@ -181,6 +197,10 @@ type ArrayAppendSpecOptions struct {
 	// spec.ArrayAppend("path", 2, nil)
 	// spec.ArrayAppend("path", 3, nil)
 	HasMultiple bool
+
+	// Serializer is used to serialize the value, if not set then the value is serialized using encoding/json.
+	// UNCOMMITTED: This API may change in the future.
+	Serializer SubdocSerializer
 }
 
 // ArrayAppendSpec adds an element(s) to the end (i.e. right) of an array
@@ -196,6 +216,7 @@ func ArrayAppendSpec(path string, val interface{}, opts *ArrayAppendSpecOptions)
 		path:       path,
 		value:      val,
 		multiValue: opts.HasMultiple,
+		serializer: opts.Serializer,
 	}
 }
 
@@ -214,6 +235,10 @@ type ArrayPrependSpecOptions struct {
 	// spec.ArrayPrepend("path", 2, nil)
 	// spec.ArrayPrepend("path", 3, nil)
 	HasMultiple bool
+
+	// Serializer is used to serialize the value, if not set then the value is serialized using encoding/json.
+	// UNCOMMITTED: This API may change in the future.
+	Serializer SubdocSerializer
 }
 
 // ArrayPrependSpec adds an element to the beginning (i.e. left) of an array
@@ -229,6 +254,7 @@ func ArrayPrependSpec(path string, val interface{}, opts *ArrayPrependSpecOption
 		path:       path,
 		value:      val,
 		multiValue: opts.HasMultiple,
+		serializer: opts.Serializer,
 	}
 }
 
@@ -247,6 +273,10 @@ type ArrayInsertSpecOptions struct {
 	// spec.ArrayInsert("path[3]", 2, nil)
 	// spec.ArrayInsert("path[4]", 3, nil)
 	HasMultiple bool
+
+	// Serializer is used to serialize the value, if not set then the value is serialized using encoding/json.
+	// UNCOMMITTED: This API may change in the future.
+	Serializer SubdocSerializer
 }
 
 // ArrayInsertSpec inserts an element at a given position within an array. The position should be
@@ -263,6 +293,7 @@ func ArrayInsertSpec(path string, val interface{}, opts *ArrayInsertSpecOptions)
 		path:       path,
 		value:      val,
 		multiValue: opts.HasMultiple,
+		serializer: opts.Serializer,
 	}
 }
 
@@ -270,6 +301,10 @@ func ArrayInsertSpec(path string, val interface{}, opts *ArrayInsertSpecOptions)
 type ArrayAddUniqueSpecOptions struct {
 	CreatePath bool
 	IsXattr    bool
+
+	// Serializer is used to serialize the value, if not set then the value is serialized using encoding/json.
+	// UNCOMMITTED: This API may change in the future.
+	Serializer SubdocSerializer
 }
 
 // ArrayAddUniqueSpec adds an dictionary add unique operation to this mutation operation set.
@@ -285,6 +320,7 @@ func ArrayAddUniqueSpec(path string, val interface{}, opts *ArrayAddUniqueSpecOp
 		path:       path,
 		value:      val,
 		multiValue: false,
+		serializer: opts.Serializer,
 	}
 }
 
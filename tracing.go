@@ -1,9 +1,13 @@
 package gocb
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/couchbase/gocbcore/v10"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
-	"time"
 )
 
 func tracerAddRef(tracer RequestTracer) {
@@ -129,6 +133,16 @@ func newTracerWrapper(tracer RequestTracer) *tracerWrapper {
 	}
 }
 
+// createBootstrapSpan creates a span for a connection lifecycle phase, these spans are created before any tracer
+// may be available and so a noop span is returned if there is no tracer.
+func createBootstrapSpan(tw *tracerWrapper, parent RequestSpan, name string) RequestSpan {
+	if tw == nil {
+		return defaultNoopSpan
+	}
+
+	return tw.createSpan(parent, name, "")
+}
+
 func (tw *tracerWrapper) createSpan(parent RequestSpan, operationType, service string) RequestSpan {
 	var tracectx RequestSpanContext
 	if parent != nil {
@@ -153,3 +167,32 @@ func (tw *tracerWrapper) createSpan(parent RequestSpan, operationType, service s
 
 	return span
 }
+
+// isCancellation returns whether an operation failed because it was cancelled by the application, either through its
+// context or by closing its result, as opposed to failing due to an error.
+func isCancellation(ctx context.Context, err error) bool {
+	if errors.Is(err, ErrRequestCanceled) || errors.Is(err, context.Canceled) {
+		return true
+	}
+
+	return ctx != nil && errors.Is(ctx.Err(), context.Canceled)
+}
+
+// setSpanOutcome records the outcome of a failed operation on its span, distinguishing cancellations from errors.
+// Cancelled operations are not marked as errors on OpenTelemetry spans.
+func setSpanOutcome(span RequestSpan, ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	if isCancellation(ctx, err) {
+		span.SetAttribute(spanAttribCancelledKey, true)
+		span.SetAttribute(spanAttribOutcomeKey, spanOutcomeCancelled)
+		return
+	}
+
+	span.SetAttribute(spanAttribOutcomeKey, spanOutcomeError)
+	if otelSpan, ok := span.(OtelAwareRequestSpan); ok {
+		otelSpan.Wrapped().SetStatus(codes.Error, err.Error())
+	}
+}
@@ -1,6 +1,7 @@
 package gocb
 
 import (
+	"context"
 	"errors"
 	"sync"
 
@@ -34,6 +35,43 @@ type TransactionAttemptContext struct {
 	attemptID      string
 
 	preferredServerGroup string
+	// replicaReadFallback is whether Get should fall back to the preferred server group if the active is unavailable.
+	replicaReadFallback bool
+
+	// ctx is the context passed to RunWithContext, nil when using Run.
+	ctx context.Context
+
+	// limits tracks the size of the attempt, nil when no limits are configured.
+	limits *transactionLimitsState
+
+	// metadataCollection places the metadata of the attempt, nil when no mapper is configured.
+	metadataCollection *transactionMetadataCollectionState
+
+	// savepoints tracks the savepoints of the attempt, and the mutations which can be rolled back to them.
+	savepoints *transactionSavepointState
+}
+
+// checkContext fails the attempt if the context passed to RunWithContext is done, preventing it from being committed or
+// retried so that any staged mutations are rolled back.
+func (c *TransactionAttemptContext) checkContext() error {
+	if c.ctx == nil {
+		return nil
+	}
+
+	err := c.ctx.Err()
+	if err == nil {
+		return nil
+	}
+
+	c.logger.logInfof(c.attemptID, "Context is done: %s", err)
+	return operationFailed(transactionQueryOperationFailedDef{
+		ShouldNotRetry:    true,
+		ShouldNotRollback: false,
+		Reason:            gocbcore.TransactionErrorReasonTransactionFailed,
+		ErrorCause:        err,
+		ErrorClass:        gocbcore.TransactionErrorClassFailOther,
+		ShouldNotCommit:   true,
+	}, c)
 }
 
 func (c *TransactionAttemptContext) canCommit() bool {
@@ -81,6 +119,21 @@ func (iac *InternalTransactionAttemptContext) IsExpired() bool {
 
 // Get will attempt to fetch a document, and fail the transaction if it does not exist.
 func (c *TransactionAttemptContext) Get(collection *Collection, id string) (*TransactionGetResult, error) {
+	return c.GetWithOptions(collection, id, nil)
+}
+
+// GetWithOptions will attempt to fetch a document, and fail the transaction if it does not exist.
+// If TransactionsConfig.ReplicaReadFallback is enabled and the read from the active fails then the document is read
+// from a replica in the preferred server group instead.
+// UNCOMMITTED: This API may change in the future.
+func (c *TransactionAttemptContext) GetWithOptions(collection *Collection, id string, opts *TransactionGetOptions) (*TransactionGetResult, error) {
+	if opts == nil {
+		opts = &TransactionGetOptions{}
+	}
+	if err := c.checkContext(); err != nil {
+		return nil, err
+	}
+
 	c.queryStateLock.Lock()
 	if c.queryModeLocked() {
 		res, err := c.getQueryMode(collection, id)
@@ -93,17 +146,60 @@ func (c *TransactionAttemptContext) Get(collection *Collection, id string) (*Tra
 			return nil, err
 		}
 		c.queryStateLock.Unlock()
-		return res, nil
+		return res.withTranscoder(opts.Transcoder), nil
 	}
 	c.queryStateLock.Unlock()
 
-	return c.get(collection, id, "")
+	res, err := c.get(collection, id, "")
+	if err != nil && c.shouldFallbackToReplica(err) {
+		c.logger.logInfof(c.attemptID, "Get from active failed, falling back to replica in server group %s: %v",
+			c.preferredServerGroup, err)
+		res, err = c.get(collection, id, c.preferredServerGroup)
+		if err != nil {
+			c.getFailed()
+			return nil, err
+		}
+	}
+	if err != nil {
+		var txnErr *TransactionOperationFailedError
+		if errors.As(err, &txnErr) {
+			c.getFailed()
+		}
+		return nil, err
+	}
+
+	return res.withTranscoder(opts.Transcoder), nil
+}
+
+// getFailed stops the attempt from committing after a failed get. gocbcore can only make every get non-fatal, which
+// is enabled so that a get which fails on the active can fall back to a replica. Any get which does not fall back, or
+// whose fallback also fails, must still fail the attempt as it would if non-fatal gets were disabled.
+func (c *TransactionAttemptContext) getFailed() {
+	if !c.replicaReadFallback {
+		return
+	}
+
+	c.txn.UpdateState(gocbcore.TransactionUpdateStateOptions{
+		ShouldNotCommit: true,
+	})
+}
+
+func (c *TransactionAttemptContext) shouldFallbackToReplica(err error) bool {
+	if !c.replicaReadFallback || c.preferredServerGroup == "" {
+		return false
+	}
+
+	return !errors.Is(err, ErrDocumentNotFound) && !c.txn.HasExpired()
 }
 
 // GetReplicaFromPreferredServerGroup will attempt to fetch a document from the preferred server group, and fail the transaction if it does not exist.
 //
 // UNCOMMITTED: This API may change in the future.
 func (c *TransactionAttemptContext) GetReplicaFromPreferredServerGroup(collection *Collection, id string) (*TransactionGetResult, error) {
+	if err := c.checkContext(); err != nil {
+		return nil, err
+	}
+
 	c.queryStateLock.Lock()
 	if c.queryModeLocked() {
 		c.queryStateLock.Unlock()
@@ -128,7 +224,16 @@ func (c *TransactionAttemptContext) GetReplicaFromPreferredServerGroup(collectio
 	}
 	c.queryStateLock.Unlock()
 
-	return c.get(collection, id, c.preferredServerGroup)
+	res, err := c.get(collection, id, c.preferredServerGroup)
+	if err != nil {
+		var txnErr *TransactionOperationFailedError
+		if errors.As(err, &txnErr) {
+			c.getFailed()
+		}
+		return nil, err
+	}
+
+	return res, nil
 }
 
 func (c *TransactionAttemptContext) get(collection *Collection, id string, serverGroup string) (resOut *TransactionGetResult, errOut error) {
@@ -151,7 +256,7 @@ func (c *TransactionAttemptContext) get(collection *Collection, id string, serve
 				docID:      id,
 
 				transcoder: NewJSONTranscoder(),
-				flags:      2 << 24,
+				flags:      transactionContentFlags(res.Flags),
 
 				coreRes: res,
 			}
@@ -182,14 +287,36 @@ func (c *TransactionAttemptContext) get(collection *Collection, id string, serve
 
 // Replace will replace the contents of a document, failing if the document does not already exist.
 func (c *TransactionAttemptContext) Replace(doc *TransactionGetResult, value interface{}) (*TransactionGetResult, error) {
-	// TODO: Use Transcoder here
-	valueBytes, _, err := c.transcoder.Encode(value)
+	return c.ReplaceWithOptions(doc, value, nil)
+}
+
+// ReplaceWithOptions will replace the contents of a document, failing if the document does not already exist.
+// UNCOMMITTED: This API may change in the future.
+func (c *TransactionAttemptContext) ReplaceWithOptions(doc *TransactionGetResult, value interface{}, opts *TransactionReplaceOptions) (*TransactionGetResult, error) {
+	if opts == nil {
+		opts = &TransactionReplaceOptions{}
+	}
+	if err := doc.collection.checkReadOnlyMode(); err != nil {
+		return nil, err
+	}
+	if err := c.checkContext(); err != nil {
+		return nil, err
+	}
+	if err := c.checkLimits(doc.collection, doc.docID); err != nil {
+		return nil, err
+	}
+
+	valueBytes, flags, err := c.encodeValue(opts.Transcoder, value)
 	if err != nil {
 		return nil, err
 	}
 
 	c.queryStateLock.Lock()
 	if c.queryModeLocked() {
+		if err := checkQueryModeValue(flags); err != nil {
+			c.queryStateLock.Unlock()
+			return nil, err
+		}
 		res, err := c.replaceQueryMode(doc, valueBytes)
 		c.queryStateLock.Unlock()
 		if err != nil {
@@ -197,21 +324,38 @@ func (c *TransactionAttemptContext) Replace(doc *TransactionGetResult, value int
 			return nil, err
 		}
 
-		return res, nil
+		return res.withTranscoder(opts.Transcoder), nil
 	}
 	c.queryStateLock.Unlock()
 
-	return c.replace(doc, valueBytes)
+	res, err := c.replace(doc, valueBytes, flags)
+	if err != nil {
+		return nil, err
+	}
+	c.savepoints.record(transactionUndoEntry{
+		kind:       transactionUndoReplace,
+		collection: doc.collection,
+		id:         doc.docID,
+		before:     doc,
+		after:      res,
+	})
+
+	return res.withTranscoder(opts.Transcoder), nil
 }
 
-func (c *TransactionAttemptContext) replace(doc *TransactionGetResult, valueBytes []byte) (resOut *TransactionGetResult, errOut error) {
+func (c *TransactionAttemptContext) replace(doc *TransactionGetResult, valueBytes []byte, flags uint32) (resOut *TransactionGetResult, errOut error) {
 	collection := doc.collection
 	id := doc.docID
 
+	if err := c.mapMetadataCollection(collection); err != nil {
+		return nil, err
+	}
+
 	waitCh := make(chan struct{}, 1)
 	err := c.txn.Replace(gocbcore.TransactionReplaceOptions{
 		Document: doc.coreRes,
 		Value:    valueBytes,
+		Flags:    flags,
 	}, func(res *gocbcore.TransactionGetResult, err error) {
 		if err == nil {
 			resOut = &TransactionGetResult{
@@ -219,7 +363,7 @@ func (c *TransactionAttemptContext) replace(doc *TransactionGetResult, valueByte
 				docID:      id,
 
 				transcoder: NewJSONTranscoder(),
-				flags:      2 << 24,
+				flags:      transactionContentFlags(res.Flags),
 
 				coreRes: res,
 			}
@@ -239,14 +383,36 @@ func (c *TransactionAttemptContext) replace(doc *TransactionGetResult, valueByte
 
 // Insert will insert a new document, failing if the document already exists.
 func (c *TransactionAttemptContext) Insert(collection *Collection, id string, value interface{}) (*TransactionGetResult, error) {
-	// TODO: Use Transcoder here
-	valueBytes, _, err := c.transcoder.Encode(value)
+	return c.InsertWithOptions(collection, id, value, nil)
+}
+
+// InsertWithOptions will insert a new document, failing if the document already exists.
+// UNCOMMITTED: This API may change in the future.
+func (c *TransactionAttemptContext) InsertWithOptions(collection *Collection, id string, value interface{}, opts *TransactionInsertOptions) (*TransactionGetResult, error) {
+	if opts == nil {
+		opts = &TransactionInsertOptions{}
+	}
+	if err := collection.checkReadOnlyMode(); err != nil {
+		return nil, err
+	}
+	if err := c.checkContext(); err != nil {
+		return nil, err
+	}
+	if err := c.checkLimits(collection, id); err != nil {
+		return nil, err
+	}
+
+	valueBytes, flags, err := c.encodeValue(opts.Transcoder, value)
 	if err != nil {
 		return nil, err
 	}
 
 	c.queryStateLock.Lock()
 	if c.queryModeLocked() {
+		if err := checkQueryModeValue(flags); err != nil {
+			c.queryStateLock.Unlock()
+			return nil, err
+		}
 		res, err := c.insertQueryMode(collection, id, valueBytes)
 		c.queryStateLock.Unlock()
 		if err != nil {
@@ -254,14 +420,59 @@ func (c *TransactionAttemptContext) Insert(collection *Collection, id string, va
 			return nil, err
 		}
 
-		return res, nil
+		return res.withTranscoder(opts.Transcoder), nil
 	}
 	c.queryStateLock.Unlock()
 
-	return c.insert(collection, id, valueBytes)
+	res, err := c.insert(collection, id, valueBytes, flags)
+	if err != nil {
+		return nil, err
+	}
+	c.savepoints.record(transactionUndoEntry{
+		kind:       transactionUndoInsert,
+		collection: collection,
+		id:         id,
+		after:      res,
+	})
+
+	return res.withTranscoder(opts.Transcoder), nil
+}
+
+// encodeValue encodes a value to be staged, returning the user flags which are staged alongside it. Binary documents
+// are staged using the binary metadata extension of the transactions protocol, which gocbcore only supports against
+// servers which support binary xattrs, failing the operation with ErrFeatureNotAvailable otherwise.
+func (c *TransactionAttemptContext) encodeValue(transcoder Transcoder, value interface{}) ([]byte, uint32, error) {
+	if transcoder == nil {
+		transcoder = c.transcoder
+	}
+
+	return transcoder.Encode(value)
 }
 
-func (c *TransactionAttemptContext) insert(collection *Collection, id string, valueBytes []byte) (resOut *TransactionGetResult, errOut error) {
+// checkQueryModeValue rejects non-JSON values in query mode, where documents are staged by query statements which
+// only support JSON.
+func checkQueryModeValue(flags uint32) error {
+	if dataType, _ := gocbcore.DecodeCommonFlags(flags); dataType != gocbcore.JSONType {
+		return wrapError(ErrFeatureNotAvailable, "binary documents are not supported by transactions in query mode, the transcoder produced a non-JSON value")
+	}
+
+	return nil
+}
+
+// transactionContentFlags returns the flags used to decode a document, documents without user flags are JSON.
+func transactionContentFlags(flags uint32) uint32 {
+	if flags == 0 {
+		return 2 << 24
+	}
+
+	return flags
+}
+
+func (c *TransactionAttemptContext) insert(collection *Collection, id string, valueBytes []byte, flags uint32) (resOut *TransactionGetResult, errOut error) {
+	if err := c.mapMetadataCollection(collection); err != nil {
+		return nil, err
+	}
+
 	a, err := collection.Bucket().Internal().IORouter()
 	if err != nil {
 		return nil, err
@@ -274,6 +485,7 @@ func (c *TransactionAttemptContext) insert(collection *Collection, id string, va
 		CollectionName: collection.Name(),
 		Key:            []byte(id),
 		Value:          valueBytes,
+		Flags:          flags,
 	}, func(res *gocbcore.TransactionGetResult, err error) {
 		if err == nil {
 			resOut = &TransactionGetResult{
@@ -281,7 +493,7 @@ func (c *TransactionAttemptContext) insert(collection *Collection, id string, va
 				docID:      id,
 
 				transcoder: NewJSONTranscoder(),
-				flags:      2 << 24,
+				flags:      transactionContentFlags(res.Flags),
 
 				coreRes: res,
 			}
@@ -306,6 +518,16 @@ func (c *TransactionAttemptContext) insert(collection *Collection, id string, va
 
 // Remove will delete a document.
 func (c *TransactionAttemptContext) Remove(doc *TransactionGetResult) error {
+	if err := doc.collection.checkReadOnlyMode(); err != nil {
+		return err
+	}
+	if err := c.checkContext(); err != nil {
+		return err
+	}
+	if err := c.checkLimits(doc.collection, doc.docID); err != nil {
+		return err
+	}
+
 	c.queryStateLock.Lock()
 	if c.queryModeLocked() {
 		err := c.removeQueryMode(doc)
@@ -319,10 +541,24 @@ func (c *TransactionAttemptContext) Remove(doc *TransactionGetResult) error {
 	}
 	c.queryStateLock.Unlock()
 
-	return c.remove(doc)
+	if err := c.remove(doc); err != nil {
+		return err
+	}
+	c.savepoints.record(transactionUndoEntry{
+		kind:       transactionUndoRemove,
+		collection: doc.collection,
+		id:         doc.docID,
+		before:     doc,
+	})
+
+	return nil
 }
 
 func (c *TransactionAttemptContext) remove(doc *TransactionGetResult) (errOut error) {
+	if err := c.mapMetadataCollection(doc.collection); err != nil {
+		return err
+	}
+
 	waitCh := make(chan struct{}, 1)
 	err := c.txn.Remove(gocbcore.TransactionRemoveOptions{
 		Document: doc.coreRes,
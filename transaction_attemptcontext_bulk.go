@@ -0,0 +1,164 @@
+package gocb
+
+import (
+	"errors"
+	"sync"
+)
+
+// defaultTransactionBulkConcurrency is the default number of operations performed concurrently by the bulk operations
+// of a TransactionAttemptContext.
+const defaultTransactionBulkConcurrency = 16
+
+// TransactionBulkOptions specifies the set of options available to the bulk operations of a
+// TransactionAttemptContext.
+// UNCOMMITTED: This API may change in the future.
+type TransactionBulkOptions struct {
+	// Concurrency is the maximum number of operations in flight at once, defaulting to 16. Operations are always
+	// performed serially once the transaction has entered query mode.
+	Concurrency int
+
+	// Transcoder is used to encode values and decode documents, defaulting to the JSONTranscoder.
+	Transcoder Transcoder
+}
+
+// TransactionGetSpec specifies a document to be fetched by GetMulti.
+// UNCOMMITTED: This API may change in the future.
+type TransactionGetSpec struct {
+	Collection *Collection
+	ID         string
+}
+
+// TransactionInsertSpec specifies a document to be inserted by InsertMulti.
+// UNCOMMITTED: This API may change in the future.
+type TransactionInsertSpec struct {
+	Collection *Collection
+	ID         string
+	Value      interface{}
+}
+
+// TransactionReplaceSpec specifies a document to be replaced by ReplaceMulti.
+// UNCOMMITTED: This API may change in the future.
+type TransactionReplaceSpec struct {
+	Doc   *TransactionGetResult
+	Value interface{}
+}
+
+// GetMulti fetches a number of documents concurrently, returning the results in the same order as the specs. The
+// result for a document which does not exist is nil, as this does not fail the transaction. If any other error
+// occurs then the first such error is returned, in which case the transaction will fail as it would for Get.
+// UNCOMMITTED: This API may change in the future.
+func (c *TransactionAttemptContext) GetMulti(specs []TransactionGetSpec, opts *TransactionBulkOptions) ([]*TransactionGetResult, error) {
+	if opts == nil {
+		opts = &TransactionBulkOptions{}
+	}
+
+	return runTransactionBulk(c, specs, opts.Concurrency, func(spec TransactionGetSpec) (*TransactionGetResult, error) {
+		res, err := c.GetWithOptions(spec.Collection, spec.ID, &TransactionGetOptions{
+			Transcoder: opts.Transcoder,
+		})
+		if errors.Is(err, ErrDocumentNotFound) {
+			return nil, nil
+		}
+
+		return res, err
+	})
+}
+
+// InsertMulti stages the insertion of a number of documents concurrently, returning the results in the same order as
+// the specs. If any insert fails then the first error is returned, in which case the transaction will fail as it
+// would for Insert.
+// UNCOMMITTED: This API may change in the future.
+func (c *TransactionAttemptContext) InsertMulti(specs []TransactionInsertSpec, opts *TransactionBulkOptions) ([]*TransactionGetResult, error) {
+	if opts == nil {
+		opts = &TransactionBulkOptions{}
+	}
+
+	return runTransactionBulk(c, specs, opts.Concurrency, func(spec TransactionInsertSpec) (*TransactionGetResult, error) {
+		return c.InsertWithOptions(spec.Collection, spec.ID, spec.Value, &TransactionInsertOptions{
+			Transcoder: opts.Transcoder,
+		})
+	})
+}
+
+// ReplaceMulti stages the replacement of a number of documents concurrently, returning the results in the same order
+// as the specs. If any replace fails then the first error is returned, in which case the transaction will fail as
+// it would for Replace.
+// UNCOMMITTED: This API may change in the future.
+func (c *TransactionAttemptContext) ReplaceMulti(specs []TransactionReplaceSpec, opts *TransactionBulkOptions) ([]*TransactionGetResult, error) {
+	if opts == nil {
+		opts = &TransactionBulkOptions{}
+	}
+
+	return runTransactionBulk(c, specs, opts.Concurrency, func(spec TransactionReplaceSpec) (*TransactionGetResult, error) {
+		return c.ReplaceWithOptions(spec.Doc, spec.Value, &TransactionReplaceOptions{
+			Transcoder: opts.Transcoder,
+		})
+	})
+}
+
+// runTransactionBulk performs opFn for each spec, with up to concurrency operations in flight at once. Once an
+// operation has failed no further operations are started, as the attempt cannot be committed.
+func runTransactionBulk[S any, R any](c *TransactionAttemptContext, specs []S, concurrency int, opFn func(S) (R, error)) ([]R, error) {
+	if concurrency < 0 {
+		return nil, makeInvalidArgumentsError("concurrency cannot be negative")
+	}
+	if concurrency == 0 {
+		concurrency = defaultTransactionBulkConcurrency
+	}
+
+	// Query mode operations are performed by statements against a single query node, which must be sequential.
+	c.queryStateLock.Lock()
+	if c.queryModeLocked() {
+		concurrency = 1
+	}
+	c.queryStateLock.Unlock()
+
+	results := make([]R, len(specs))
+
+	var (
+		wg       sync.WaitGroup
+		errLock  sync.Mutex
+		firstErr error
+	)
+	failed := func() bool {
+		errLock.Lock()
+		defer errLock.Unlock()
+		return firstErr != nil
+	}
+
+	sem := make(chan struct{}, concurrency)
+	for i, spec := range specs {
+		sem <- struct{}{}
+		if failed() {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, spec S) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			res, err := opFn(spec)
+			if err != nil {
+				errLock.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				errLock.Unlock()
+				return
+			}
+
+			results[i] = res
+		}(i, spec)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return results, nil
+}
@@ -11,6 +11,10 @@ import (
 
 // Query executes the query statement on the server.
 func (c *TransactionAttemptContext) Query(statement string, options *TransactionQueryOptions) (*TransactionQueryResult, error) {
+	if err := c.checkContext(); err != nil {
+		return nil, err
+	}
+
 	c.logger.logInfof(c.attemptID, "Performing query: %s", redactUserDataString(statement))
 	var opts TransactionQueryOptions
 	if options != nil {
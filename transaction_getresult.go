@@ -20,6 +20,15 @@ type TransactionGetResult struct {
 	coreRes *gocbcore.TransactionGetResult
 }
 
+// withTranscoder sets the transcoder used to decode the content, if one is provided.
+func (d *TransactionGetResult) withTranscoder(transcoder Transcoder) *TransactionGetResult {
+	if transcoder != nil {
+		d.transcoder = transcoder
+	}
+
+	return d
+}
+
 // Content provides access to the documents contents.
 func (d *TransactionGetResult) Content(valuePtr interface{}) error {
 	return d.transcoder.Decode(d.coreRes.Value, d.flags, valuePtr)
@@ -0,0 +1,146 @@
+package gocb
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/couchbase/gocbcore/v10"
+)
+
+// TransactionLimits specifies client side limits on the size of a transaction, protecting against transactions which
+// grow unbounded and would otherwise only fail once they exceed server limits. A transaction which exceeds a limit
+// fails, with any mutations already staged being rolled back. Mutations performed by statements passed to Query are
+// not accounted for.
+// UNCOMMITTED: This API may change in the future.
+type TransactionLimits struct {
+	// MaxCollections is the maximum number of distinct collections, across all buckets, which a transaction can mutate
+	// documents within. Zero means no limit.
+	MaxCollections int
+
+	// MaxMutations is the maximum number of distinct documents which a transaction can mutate. A document is counted
+	// once the first mutation of it is attempted, regardless of how many times it is mutated. Zero means no limit.
+	MaxMutations int
+}
+
+func (l TransactionLimits) validate() error {
+	if l.MaxCollections < 0 {
+		return makeInvalidArgumentsError("max collections cannot be negative")
+	}
+	if l.MaxMutations < 0 {
+		return makeInvalidArgumentsError("max mutations cannot be negative")
+	}
+
+	return nil
+}
+
+// transactionLimitsState tracks the collections and documents mutated by a single transaction attempt.
+type transactionLimitsState struct {
+	lock   sync.Mutex
+	limits TransactionLimits
+	// collections counts the documents mutated within each collection.
+	collections map[string]int
+	documents   map[string]struct{}
+}
+
+func newTransactionLimitsState(limits TransactionLimits) *transactionLimitsState {
+	return &transactionLimitsState{
+		limits:      limits,
+		collections: make(map[string]int),
+		documents:   make(map[string]struct{}),
+	}
+}
+
+// reserve accounts for a mutation of a document, returning an error if doing so would exceed a limit, along with
+// the name of that limit.
+func (s *transactionLimitsState) reserve(collection *Collection, id string) (string, error) {
+	collectionKey := fmt.Sprintf("%s.%s.%s", collection.bucketName(), collection.ScopeName(), collection.Name())
+	documentKey := collectionKey + "." + id
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.documents[documentKey]; ok {
+		return "", nil
+	}
+
+	_, seenCollection := s.collections[collectionKey]
+	if !seenCollection && s.limits.MaxCollections > 0 && len(s.collections) >= s.limits.MaxCollections {
+		return transactionLimitCollections, wrapError(ErrTransactionCollectionLimitExceeded,
+			fmt.Sprintf("transaction cannot mutate documents in more than %d collections", s.limits.MaxCollections))
+	}
+	if s.limits.MaxMutations > 0 && len(s.documents) >= s.limits.MaxMutations {
+		return transactionLimitMutations, wrapError(ErrTransactionMutationLimitExceeded,
+			fmt.Sprintf("transaction cannot mutate more than %d documents", s.limits.MaxMutations))
+	}
+
+	s.collections[collectionKey]++
+	s.documents[documentKey] = struct{}{}
+
+	return "", nil
+}
+
+// release stops accounting for a document which is no longer part of the attempt, such as when a savepoint is rolled
+// back to before its first mutation.
+func (s *transactionLimitsState) release(collection *Collection, id string) {
+	collectionKey := fmt.Sprintf("%s.%s.%s", collection.bucketName(), collection.ScopeName(), collection.Name())
+	documentKey := collectionKey + "." + id
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.documents[documentKey]; !ok {
+		return
+	}
+	delete(s.documents, documentKey)
+
+	s.collections[collectionKey]--
+	if s.collections[collectionKey] == 0 {
+		delete(s.collections, collectionKey)
+	}
+}
+
+// checkLimits accounts for a mutation of a document against the transaction limits, failing the attempt if a limit
+// would be exceeded. The transaction is not retried, as a subsequent attempt would exceed the same limit.
+func (c *TransactionAttemptContext) checkLimits(collection *Collection, id string) error {
+	if c.limits == nil {
+		return nil
+	}
+
+	limit, err := c.limits.reserve(collection, id)
+	if err == nil {
+		return nil
+	}
+
+	c.logger.logInfof(c.attemptID, "Transaction %s limit exceeded", limit)
+	c.recordLimitExceeded(limit)
+
+	return operationFailed(transactionQueryOperationFailedDef{
+		ShouldNotRetry:    true,
+		ShouldNotRollback: false,
+		Reason:            gocbcore.TransactionErrorReasonTransactionFailed,
+		ErrorCause:        err,
+		ErrorClass:        gocbcore.TransactionErrorClassFailOther,
+		ShouldNotCommit:   true,
+	}, c)
+}
+
+func (c *TransactionAttemptContext) recordLimitExceeded(limit string) {
+	if c.cluster == nil {
+		return
+	}
+
+	meter := c.cluster.connectionManager.getMeter()
+	if meter == nil || meter.isNoopMeter {
+		return
+	}
+
+	counter, err := meter.meter.Counter(meterNameCBTransactionLimitsExceeded, map[string]string{
+		meterAttribTransactionLimitKey: limit,
+	})
+	if err != nil {
+		logDebugf("Failed to create counter: %v", err)
+		return
+	}
+
+	counter.IncrementBy(1)
+}
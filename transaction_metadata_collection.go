@@ -0,0 +1,83 @@
+package gocb
+
+import (
+	"sync"
+
+	"github.com/couchbase/gocbcore/v10"
+)
+
+// TransactionMetadataCollectionMapper returns the collection in which to place the metadata of a transaction attempt,
+// given the keyspace of the first document which the attempt mutates using Insert, Replace or Remove. This allows the
+// metadata to be kept alongside the documents, such as within the bucket of a tenant. Returning nil places the
+// metadata in the default location. Mappers are called on the goroutine running the transaction and so should not
+// block.
+// UNCOMMITTED: This API may change in the future.
+type TransactionMetadataCollectionMapper func(keyspace TransactionKeyspace) *TransactionKeyspace
+
+// transactionMetadataCollectionState applies a TransactionMetadataCollectionMapper to a single transaction attempt.
+type transactionMetadataCollectionState struct {
+	once   sync.Once
+	mapper TransactionMetadataCollectionMapper
+	err    error
+}
+
+func newTransactionMetadataCollectionState(mapper TransactionMetadataCollectionMapper) *transactionMetadataCollectionState {
+	return &transactionMetadataCollectionState{
+		mapper: mapper,
+	}
+}
+
+// mapMetadataCollection places the metadata of the attempt according to the mapper, it must be called before each KV
+// mutation. The metadata location can only be set before the first mutation, so concurrent mutations wait for the
+// location to be set by whichever is first.
+func (c *TransactionAttemptContext) mapMetadataCollection(collection *Collection) error {
+	if c.metadataCollection == nil {
+		return nil
+	}
+
+	c.metadataCollection.once.Do(func() {
+		c.metadataCollection.err = c.setMetadataCollection(collection)
+	})
+	if c.metadataCollection.err == nil {
+		return nil
+	}
+
+	return operationFailed(transactionQueryOperationFailedDef{
+		ShouldNotRetry:    true,
+		ShouldNotRollback: false,
+		Reason:            gocbcore.TransactionErrorReasonTransactionFailed,
+		ErrorCause:        c.metadataCollection.err,
+		ErrorClass:        gocbcore.TransactionErrorClassFailOther,
+		ShouldNotCommit:   true,
+	}, c)
+}
+
+func (c *TransactionAttemptContext) setMetadataCollection(collection *Collection) error {
+	keyspace := c.metadataCollection.mapper(TransactionKeyspace{
+		BucketName:     collection.bucketName(),
+		ScopeName:      collection.ScopeName(),
+		CollectionName: collection.Name(),
+	})
+	if keyspace == nil {
+		return nil
+	}
+
+	agent, err := c.cluster.Bucket(keyspace.BucketName).Internal().IORouter()
+	if err != nil {
+		return err
+	}
+
+	c.logger.logInfof(c.attemptID, "Mapped metadata collection to %s.%s.%s", keyspace.BucketName, keyspace.ScopeName,
+		keyspace.CollectionName)
+
+	err = c.txn.SetATRLocation(gocbcore.TransactionATRLocation{
+		Agent:          agent,
+		ScopeName:      keyspace.ScopeName,
+		CollectionName: keyspace.CollectionName,
+	})
+	if err != nil {
+		return makeInvalidArgumentsError(err.Error())
+	}
+
+	return nil
+}
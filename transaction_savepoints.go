@@ -0,0 +1,222 @@
+package gocb
+
+import (
+	"fmt"
+	"sync"
+)
+
+// TransactionSavepoint marks a point within a transaction attempt which mutations can be rolled back to, using
+// TransactionAttemptContext.RollbackTo.
+// UNCOMMITTED: This API may change in the future.
+type TransactionSavepoint struct {
+	state *transactionSavepointState
+	id    uint64
+}
+
+type transactionUndoKind int
+
+const (
+	transactionUndoInsert transactionUndoKind = iota
+	transactionUndoReplace
+	transactionUndoRemove
+)
+
+// transactionUndoEntry records a mutation made after a savepoint, along with what is needed to reverse it.
+type transactionUndoEntry struct {
+	kind       transactionUndoKind
+	collection *Collection
+	id         string
+	// before is the document passed to a replace or remove, holding the content to restore.
+	before *TransactionGetResult
+	// after is the result of an insert or replace.
+	after *TransactionGetResult
+	// firstMutation is whether the attempt had not mutated the document before this mutation.
+	firstMutation bool
+}
+
+func (e transactionUndoEntry) key() string {
+	return fmt.Sprintf("%s.%s.%s.%s", e.collection.bucketName(), e.collection.ScopeName(), e.collection.Name(), e.id)
+}
+
+type transactionSavepointMark struct {
+	id    uint64
+	index int
+}
+
+// transactionSavepointState tracks the savepoints of a single transaction attempt, and the mutations made since the
+// first of them.
+type transactionSavepointState struct {
+	lock   sync.Mutex
+	nextID uint64
+	marks  []transactionSavepointMark
+	undo   []transactionUndoEntry
+	// mutated is the set of documents which the attempt has mutated.
+	mutated map[string]struct{}
+}
+
+func (s *transactionSavepointState) record(entry transactionUndoEntry) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := entry.key()
+	_, seen := s.mutated[key]
+	if !seen {
+		if s.mutated == nil {
+			s.mutated = make(map[string]struct{})
+		}
+		s.mutated[key] = struct{}{}
+	}
+
+	// Mutations only need to be recorded whilst there is a savepoint to roll back to.
+	if len(s.marks) == 0 {
+		return
+	}
+
+	entry.firstMutation = !seen
+	s.undo = append(s.undo, entry)
+}
+
+func (s *transactionSavepointState) forget(key string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.mutated, key)
+}
+
+// Savepoint marks the current point in the attempt, such that any mutations staged after it can be reversed using
+// RollbackTo without failing the attempt. Savepoints cannot be used once the attempt has executed a query.
+// UNCOMMITTED: This API may change in the future.
+func (c *TransactionAttemptContext) Savepoint() (*TransactionSavepoint, error) {
+	if err := c.checkContext(); err != nil {
+		return nil, err
+	}
+	if err := c.checkSavepointsSupported(); err != nil {
+		return nil, err
+	}
+
+	c.savepoints.lock.Lock()
+	defer c.savepoints.lock.Unlock()
+
+	c.savepoints.nextID++
+	c.savepoints.marks = append(c.savepoints.marks, transactionSavepointMark{
+		id:    c.savepoints.nextID,
+		index: len(c.savepoints.undo),
+	})
+
+	return &TransactionSavepoint{
+		state: c.savepoints,
+		id:    c.savepoints.nextID,
+	}, nil
+}
+
+// RollbackTo returns the documents mutated since the savepoint was created to their state at the savepoint, without
+// failing the attempt. Documents inserted after the savepoint, which were not otherwise part of the attempt, have their
+// staged inserts removed and are no longer part of the attempt, nor do they count towards the limits of the
+// transaction. Any other document mutated after the savepoint has its content at the savepoint staged again, as a
+// staged replace or remove of an existing document cannot be dropped from the attempt. Such a document remains part of
+// the attempt, so its CAS is changed on commit even where its content is not. Any savepoints created after this one are
+// released, whilst this savepoint remains valid and can be rolled back to again. Documents returned by operations
+// performed after the savepoint must not be used once rolled back, and should be fetched again. RollbackTo must not be
+// called concurrently with other operations on the attempt. If returning a document to its state at the savepoint
+// fails then the attempt fails, as it would for any other failed operation.
+// UNCOMMITTED: This API may change in the future.
+func (c *TransactionAttemptContext) RollbackTo(savepoint *TransactionSavepoint) error {
+	if savepoint == nil || savepoint.state != c.savepoints {
+		return makeInvalidArgumentsError("savepoint does not belong to this transaction attempt")
+	}
+	if err := c.checkContext(); err != nil {
+		return err
+	}
+	if err := c.checkSavepointsSupported(); err != nil {
+		return err
+	}
+
+	c.savepoints.lock.Lock()
+	markIdx := -1
+	for i, mark := range c.savepoints.marks {
+		if mark.id == savepoint.id {
+			markIdx = i
+			break
+		}
+	}
+	if markIdx == -1 {
+		c.savepoints.lock.Unlock()
+		return makeInvalidArgumentsError("savepoint has been released by rolling back to an earlier savepoint")
+	}
+
+	mark := c.savepoints.marks[markIdx]
+	entries := c.savepoints.undo[mark.index:]
+	c.savepoints.undo = c.savepoints.undo[:mark.index:mark.index]
+	c.savepoints.marks = c.savepoints.marks[:markIdx+1]
+	c.savepoints.lock.Unlock()
+
+	// Only the first and last mutation of each document since the savepoint matter, the first holding its state at the
+	// savepoint and the last its currently staged state.
+	var keys []string
+	firsts := make(map[string]transactionUndoEntry)
+	lasts := make(map[string]transactionUndoEntry)
+	for _, entry := range entries {
+		key := entry.key()
+		if _, ok := firsts[key]; !ok {
+			keys = append(keys, key)
+			firsts[key] = entry
+		}
+		lasts[key] = entry
+	}
+
+	c.logger.logInfof(c.attemptID, "Rolling back %d documents to savepoint", len(keys))
+
+	for _, key := range keys {
+		first, last := firsts[key], lasts[key]
+
+		current := last.after
+		if last.kind == transactionUndoRemove {
+			current = nil
+		}
+
+		if first.kind == transactionUndoInsert && first.firstMutation {
+			// Removing a document which the attempt inserted unstages the insert, dropping it from the attempt.
+			if current != nil {
+				if err := c.remove(current); err != nil {
+					return err
+				}
+			}
+
+			c.savepoints.forget(key)
+			if c.limits != nil {
+				c.limits.release(first.collection, first.id)
+			}
+			continue
+		}
+
+		// The document existed at the savepoint unless it was first inserted over a remove staged before it.
+		target := first.before
+
+		var err error
+		switch {
+		case current == nil && target == nil:
+		case current == nil:
+			_, err = c.insert(first.collection, first.id, target.coreRes.Value, target.flags)
+		case target == nil:
+			err = c.remove(current)
+		default:
+			_, err = c.replace(current, target.coreRes.Value, target.flags)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *TransactionAttemptContext) checkSavepointsSupported() error {
+	c.queryStateLock.Lock()
+	defer c.queryStateLock.Unlock()
+
+	if c.queryModeLocked() {
+		return wrapError(ErrFeatureNotAvailable, "savepoints cannot be used once a transaction has executed a query")
+	}
+
+	return nil
+}
@@ -1,7 +1,9 @@
 package gocb
 
 import (
+	"context"
 	"errors"
+
 	"github.com/couchbase/gocbcore/v10"
 )
 
@@ -30,7 +32,34 @@ func (c *Cluster) initTransactions(config TransactionsConfig) (*Transactions, er
 // singular transaction.
 func (t *Transactions) Run(logicFn AttemptFunc, perConfig *TransactionOptions) (*TransactionResult, error) {
 	return autoOpControl(t.controller, "", func(provider transactionsProvider) (*TransactionResult, error) {
-		return provider.Run(logicFn, perConfig, false)
+		return provider.Run(nil, logicFn, perConfig, false)
+	})
+}
+
+// RunWithContext runs a lambda to perform a number of operations as part of a singular transaction, in the same way
+// as Run. If ctx is cancelled, or its deadline is reached, then the transaction fails: any mutations staged by the
+// current attempt are rolled back and a TransactionFailedError wrapping the context error is returned. Operations
+// already in progress when ctx is cancelled are allowed to complete, and each subsequent operation on the
+// TransactionAttemptContext fails. A deadline on ctx also bounds the transaction timeout. If ctx is already done then
+// its error is returned without starting the transaction.
+// UNCOMMITTED: This API may change in the future.
+func (t *Transactions) RunWithContext(ctx context.Context, logicFn AttemptFunc, perConfig *TransactionOptions) (*TransactionResult, error) {
+	return autoOpControl(t.controller, "", func(provider transactionsProvider) (*TransactionResult, error) {
+		if ctx == nil {
+			return nil, makeInvalidArgumentsError("context cannot be nil")
+		}
+
+		return provider.Run(ctx, logicFn, perConfig, false)
+	})
+}
+
+// CleanupStats returns a snapshot of the activity of the transactions cleanup processes, which can be used to detect
+// transactions which are not being cleaned up.
+// UNCOMMITTED: This API may change in the future.
+func (t *Transactions) CleanupStats() (*TransactionsCleanupStats, error) {
+	return autoOpControl(t.controller, "", func(provider transactionsProvider) (*TransactionsCleanupStats, error) {
+		stats := provider.CleanupStats()
+		return &stats, nil
 	})
 }
 
@@ -47,7 +76,7 @@ func (t *Transactions) singleQuery(statement string, scope *Scope, opts QueryOpt
 		config.Internal.Hooks = opts.AsTransaction.Internal.Hooks
 
 		var queryRes *QueryResult
-		res, err := provider.Run(func(context *TransactionAttemptContext) error {
+		res, err := provider.Run(nil, func(context *TransactionAttemptContext) error {
 			// We need to tell the core loop that autocommit and autorollback are disabled.
 			// context.txn.UpdateState(gocbcore.TransactionUpdateStateOptions{
 			// 	ShouldNotCommit:   true,
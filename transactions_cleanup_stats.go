@@ -0,0 +1,79 @@
+package gocb
+
+import (
+	"sync/atomic"
+
+	"github.com/couchbase/gocbcore/v10"
+)
+
+// TransactionsCleanupStats is a snapshot of the activity of the transactions cleanup processes. The counters are
+// cumulative from when transactions were initialized.
+// UNCOMMITTED: This API may change in the future.
+type TransactionsCleanupStats struct {
+	// ClientQueueLength is the number of attempts made by this client which are waiting to be cleaned up. A queue
+	// which does not drain indicates that client attempts cleanup is stuck.
+	ClientQueueLength int32
+
+	// EntriesProcessed is the number of attempts, made by this client or found to be lost, for which cleanup has
+	// completed the documents and moved on to removing the entry from the ATR. A count which stops increasing while
+	// ClientQueueLength is non-zero indicates that cleanup is stuck.
+	EntriesProcessed uint64
+
+	// DocumentsProcessed is the number of staged documents which cleanup has read and found still needing to be
+	// committed or rolled back. Documents which no longer hold staged changes when read, such as those already
+	// completed by an earlier cleanup attempt, are not counted.
+	DocumentsProcessed uint64
+}
+
+// transactionsCleanupObserver records the activity of cleanup, as seen through the cleanup hooks.
+type transactionsCleanupObserver struct {
+	entriesProcessed   atomic.Uint64
+	documentsProcessed atomic.Uint64
+}
+
+func (o *transactionsCleanupObserver) stats() TransactionsCleanupStats {
+	return TransactionsCleanupStats{
+		EntriesProcessed:   o.entriesProcessed.Load(),
+		DocumentsProcessed: o.documentsProcessed.Load(),
+	}
+}
+
+// observedCleanupHooksWrapper counts the stages of cleanup before passing them through to the wrapped hooks. A stage
+// is counted once the wrapped hook has allowed it to go ahead. The hooks which come before the mutation of a document
+// are only reached once its staged state has been read, and each is reached at most once per document for an attempt.
+type observedCleanupHooksWrapper struct {
+	transactionCleanupHooksWrapper
+	observer *transactionsCleanupObserver
+}
+
+func (ochw *observedCleanupHooksWrapper) countOnSuccess(counter *atomic.Uint64, cb func(error)) func(error) {
+	return func(err error) {
+		if err == nil {
+			counter.Add(1)
+		}
+		cb(err)
+	}
+}
+
+func (ochw *observedCleanupHooksWrapper) BeforeRemoveLinks(id []byte, cb func(error)) {
+	ochw.transactionCleanupHooksWrapper.BeforeRemoveLinks(id, ochw.countOnSuccess(&ochw.observer.documentsProcessed, cb))
+}
+
+func (ochw *observedCleanupHooksWrapper) BeforeCommitDoc(id []byte, cb func(error)) {
+	ochw.transactionCleanupHooksWrapper.BeforeCommitDoc(id, ochw.countOnSuccess(&ochw.observer.documentsProcessed, cb))
+}
+
+func (ochw *observedCleanupHooksWrapper) BeforeRemoveDocStagedForRemoval(id []byte, cb func(error)) {
+	ochw.transactionCleanupHooksWrapper.BeforeRemoveDocStagedForRemoval(id,
+		ochw.countOnSuccess(&ochw.observer.documentsProcessed, cb))
+}
+
+func (ochw *observedCleanupHooksWrapper) BeforeRemoveDoc(id []byte, cb func(error)) {
+	ochw.transactionCleanupHooksWrapper.BeforeRemoveDoc(id, ochw.countOnSuccess(&ochw.observer.documentsProcessed, cb))
+}
+
+func (ochw *observedCleanupHooksWrapper) BeforeATRRemove(id []byte, cb func(error)) {
+	ochw.transactionCleanupHooksWrapper.BeforeATRRemove(id, ochw.countOnSuccess(&ochw.observer.entriesProcessed, cb))
+}
+
+var _ gocbcore.TransactionCleanUpHooks = (*observedCleanupHooksWrapper)(nil)
@@ -32,6 +32,11 @@ type TransactionsConfig struct {
 	// MetadataCollection specifies a specific location to place meta-data.
 	MetadataCollection *TransactionKeyspace
 
+	// MetadataCollectionMapper chooses the location to place meta-data for each transaction attempt, based on the
+	// documents that it mutates. This cannot be set along with MetadataCollection.
+	// UNCOMMITTED: This API may change in the future.
+	MetadataCollectionMapper TransactionMetadataCollectionMapper
+
 	// ExpirationTimout sets the maximum time that transactions created
 	// by this Transactions object can run for, before expiring.
 	Timeout time.Duration
@@ -46,6 +51,20 @@ type TransactionsConfig struct {
 	// CleanupConfig specifies cleanup configuration to use in transactions.
 	CleanupConfig TransactionsCleanupConfig
 
+	// Limits specifies client side limits on the size of transactions created by this Transactions object.
+	// UNCOMMITTED: This API may change in the future.
+	Limits TransactionLimits
+
+	// ReplicaReadFallback enables transactional Gets to fall back to reading the document from a replica in the
+	// preferred server group, set by ClusterOptions.PreferredServerGroup, if the read from the active fails.
+	// Enabling this also allows an attempt to commit after a failed Get whose error was handled by the lambda.
+	// UNCOMMITTED: This API may change in the future.
+	ReplicaReadFallback bool
+
+	// LifecycleHooks are called as each transaction created by this Transactions object progresses.
+	// UNCOMMITTED: This API may change in the future.
+	LifecycleHooks *TransactionLifecycleHooks
+
 	// Internal specifies a set of options for internal use.
 	// Internal: This should never be used and is not supported.
 	Internal struct {
@@ -68,6 +87,19 @@ type TransactionOptions struct {
 	// MetadataCollection specifies a specific Collection to place meta-data.
 	MetadataCollection *Collection
 
+	// MetadataCollectionMapper overrides TransactionsConfig.MetadataCollectionMapper for this transaction. It is not
+	// used if MetadataCollection is set, and cannot be used if TransactionsConfig.MetadataCollection is set.
+	// UNCOMMITTED: This API may change in the future.
+	MetadataCollectionMapper TransactionMetadataCollectionMapper
+
+	// Limits overrides TransactionsConfig.Limits for this transaction.
+	// UNCOMMITTED: This API may change in the future.
+	Limits *TransactionLimits
+
+	// LifecycleHooks overrides TransactionsConfig.LifecycleHooks for this transaction.
+	// UNCOMMITTED: This API may change in the future.
+	LifecycleHooks *TransactionLifecycleHooks
+
 	// Internal specifies a set of options for internal use.
 	// Internal: This should never be used and is not supported.
 	Internal struct {
@@ -75,6 +107,39 @@ type TransactionOptions struct {
 	}
 }
 
+// TransactionAttemptInfo describes an attempt of a transaction.
+// UNCOMMITTED: This API may change in the future.
+type TransactionAttemptInfo struct {
+	TransactionID string
+	AttemptID     string
+	// Attempt is the number of the attempt within the transaction, starting from 1.
+	Attempt int
+}
+
+// TransactionLifecycleHooks are called as a transaction progresses, for purposes such as instrumentation. Hooks are
+// called synchronously on the goroutine running the transaction and so should not block.
+// UNCOMMITTED: This API may change in the future.
+type TransactionLifecycleHooks struct {
+	// OnAttemptStart is called before the lambda is invoked for each attempt.
+	OnAttemptStart func(attempt TransactionAttemptInfo)
+
+	// OnRollback is called once an attempt has been rolled back, with the error which caused the rollback and any
+	// error which occurred whilst rolling back.
+	OnRollback func(attempt TransactionAttemptInfo, cause error, rollbackErr error)
+}
+
+func (h *TransactionLifecycleHooks) attemptStarted(attempt TransactionAttemptInfo) {
+	if h != nil && h.OnAttemptStart != nil {
+		h.OnAttemptStart(attempt)
+	}
+}
+
+func (h *TransactionLifecycleHooks) rolledBack(attempt TransactionAttemptInfo, cause error, rollbackErr error) {
+	if h != nil && h.OnRollback != nil {
+		h.OnRollback(attempt, cause, rollbackErr)
+	}
+}
+
 // TransactionsQueryConfig specifies various tunable query options related to transactions.
 type TransactionsQueryConfig struct {
 	ScanConsistency QueryScanConsistency
@@ -99,6 +164,33 @@ type TransactionKeyspace struct {
 	CollectionName string
 }
 
+// TransactionGetOptions specifies the set of options available when performing a Get as a part of a transaction.
+// UNCOMMITTED: This API may change in the future.
+type TransactionGetOptions struct {
+	// Transcoder is used to decode the content of the document, defaulting to the JSONTranscoder.
+	Transcoder Transcoder
+}
+
+// TransactionInsertOptions specifies the set of options available when performing an Insert as a part of a
+// transaction.
+// UNCOMMITTED: This API may change in the future.
+type TransactionInsertOptions struct {
+	// Transcoder is used to encode the value, defaulting to the JSONTranscoder. Binary documents can only be staged
+	// against servers which support them, and not in query mode, otherwise the operation fails with
+	// ErrFeatureNotAvailable.
+	Transcoder Transcoder
+}
+
+// TransactionReplaceOptions specifies the set of options available when performing a Replace as a part of a
+// transaction.
+// UNCOMMITTED: This API may change in the future.
+type TransactionReplaceOptions struct {
+	// Transcoder is used to encode the value, defaulting to the JSONTranscoder. Binary documents can only be staged
+	// against servers which support them, and not in query mode, otherwise the operation fails with
+	// ErrFeatureNotAvailable.
+	Transcoder Transcoder
+}
+
 // TransactionQueryOptions specifies the set of options available when running queries as a part of a transaction.
 // This is a subset of QueryOptions.
 type TransactionQueryOptions struct {
@@ -1,3 +1,5 @@
+//go:build !gocb_notransactions
+
 package gocb
 
 import (
@@ -641,7 +643,7 @@ func (suite *UnitTestSuite) TestTransactionsQueryGocbcoreCauseError() {
 	suite.Require().Nil(err, err)
 	defer txns.close()
 
-	txnRes, err := txns.Run(func(ctx *TransactionAttemptContext) error {
+	txnRes, err := txns.Run(nil, func(ctx *TransactionAttemptContext) error {
 		_, err := ctx.Query("SELECT 1=1", nil)
 		if err != nil {
 			return err
@@ -1,9 +1,14 @@
+//go:build !gocb_notransactions
+
 package gocb
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/couchbase/gocbcore/v10"
@@ -1226,3 +1231,555 @@ func (suite *IntegrationTestSuite) runTranasctionLoadTest(grps []transactionTest
 		}
 	}
 }
+
+func (suite *UnitTestSuite) TestTransactionsRunWithContextCancelled() {
+	cli := new(mockConnectionManager)
+	cli.On("getMeter").Return(nil)
+	cli.On("close").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	defer cluster.Close(nil)
+
+	txns := &transactionsProviderCore{}
+	err := txns.Init(TransactionsConfig{
+		CleanupConfig: TransactionsCleanupConfig{
+			DisableLostAttemptCleanup:   true,
+			DisableClientAttemptCleanup: true,
+		},
+	}, cluster)
+	suite.Require().Nil(err, err)
+	defer txns.close()
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls int
+	txnRes, err := txns.Run(cancelledCtx, func(ctx *TransactionAttemptContext) error {
+		calls++
+		return nil
+	}, nil, false)
+	suite.Require().ErrorIs(err, context.Canceled)
+	suite.Assert().Nil(txnRes)
+	suite.Assert().Zero(calls)
+
+	// Cancelling during the lambda fails the next operation, and the transaction is not retried.
+	runCtx, cancel := context.WithCancel(context.Background())
+	txnRes, err = txns.Run(runCtx, func(ctx *TransactionAttemptContext) error {
+		calls++
+		cancel()
+
+		_, err := ctx.Query("SELECT 1=1", nil)
+		return err
+	}, nil, false)
+	suite.Require().ErrorIs(err, context.Canceled)
+	var failedErr *TransactionFailedError
+	suite.Assert().True(errors.As(err, &failedErr))
+	suite.Assert().Nil(txnRes)
+	suite.Assert().Equal(1, calls)
+
+	// Cancelling after the final operation must prevent the commit.
+	runCtx, cancel = context.WithCancel(context.Background())
+	txnRes, err = txns.Run(runCtx, func(ctx *TransactionAttemptContext) error {
+		cancel()
+		return nil
+	}, nil, false)
+	suite.Require().ErrorIs(err, context.Canceled)
+	suite.Assert().True(errors.As(err, &failedErr))
+	suite.Assert().Nil(txnRes)
+}
+
+func (suite *UnitTestSuite) TestTransactionsLimits() {
+	cli := new(mockConnectionManager)
+	cli.On("getMeter").Return(nil)
+	cli.On("close").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	defer cluster.Close(nil)
+
+	txns := &transactionsProviderCore{}
+	err := txns.Init(TransactionsConfig{
+		CleanupConfig: TransactionsCleanupConfig{
+			DisableLostAttemptCleanup:   true,
+			DisableClientAttemptCleanup: true,
+		},
+		Limits: TransactionLimits{
+			MaxMutations: 2,
+		},
+	}, cluster)
+	suite.Require().Nil(err, err)
+	defer txns.close()
+
+	airline := suite.collection("travel-sample", "inventory", "airline", nil)
+	route := suite.collection("travel-sample", "inventory", "route", nil)
+
+	var attempts int
+	txnRes, err := txns.Run(nil, func(ctx *TransactionAttemptContext) error {
+		attempts++
+
+		// Mutating the same document again does not count against the limit.
+		for _, id := range []string{"airline_10", "airline_10", "airline_11"} {
+			if err := ctx.checkLimits(airline, id); err != nil {
+				return err
+			}
+		}
+
+		return ctx.checkLimits(airline, "airline_12")
+	}, nil, false)
+	suite.Require().ErrorIs(err, ErrTransactionMutationLimitExceeded)
+	var failedErr *TransactionFailedError
+	suite.Assert().True(errors.As(err, &failedErr))
+	suite.Assert().Nil(txnRes)
+	suite.Assert().Equal(1, attempts)
+
+	txnRes, err = txns.Run(nil, func(ctx *TransactionAttemptContext) error {
+		if err := ctx.checkLimits(airline, "airline_10"); err != nil {
+			return err
+		}
+
+		return ctx.checkLimits(route, "route_10")
+	}, &TransactionOptions{
+		Limits: &TransactionLimits{
+			MaxCollections: 1,
+		},
+	}, false)
+	suite.Require().ErrorIs(err, ErrTransactionCollectionLimitExceeded)
+	suite.Assert().Nil(txnRes)
+
+	_, err = txns.Run(nil, func(ctx *TransactionAttemptContext) error {
+		return nil
+	}, &TransactionOptions{
+		Limits: &TransactionLimits{
+			MaxMutations: -1,
+		},
+	}, false)
+	suite.Require().ErrorIs(err, ErrInvalidArgument)
+}
+
+func (suite *UnitTestSuite) TestTransactionsLifecycleHooks() {
+	cli := new(mockConnectionManager)
+	cli.On("getMeter").Return(nil)
+	cli.On("close").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	defer cluster.Close(nil)
+
+	var started []TransactionAttemptInfo
+	var rolledBack []error
+	txns := &transactionsProviderCore{}
+	err := txns.Init(TransactionsConfig{
+		CleanupConfig: TransactionsCleanupConfig{
+			DisableLostAttemptCleanup:   true,
+			DisableClientAttemptCleanup: true,
+		},
+		LifecycleHooks: &TransactionLifecycleHooks{
+			OnAttemptStart: func(attempt TransactionAttemptInfo) {
+				started = append(started, attempt)
+			},
+			OnRollback: func(attempt TransactionAttemptInfo, cause error, rollbackErr error) {
+				suite.Assert().Equal(started[len(started)-1], attempt)
+				suite.Assert().Nil(rollbackErr)
+				rolledBack = append(rolledBack, cause)
+			},
+		},
+	}, cluster)
+	suite.Require().Nil(err, err)
+	defer txns.close()
+
+	errBoom := errors.New("boom")
+	_, err = txns.Run(nil, func(ctx *TransactionAttemptContext) error {
+		return errBoom
+	}, nil, false)
+	suite.Require().ErrorIs(err, errBoom)
+
+	suite.Require().Len(started, 1)
+	suite.Assert().Equal(1, started[0].Attempt)
+	suite.Assert().NotEmpty(started[0].TransactionID)
+	suite.Assert().NotEmpty(started[0].AttemptID)
+	suite.Require().Len(rolledBack, 1)
+	suite.Assert().ErrorIs(rolledBack[0], errBoom)
+
+	// Per transaction hooks replace those in the config.
+	var overridden int
+	_, err = txns.Run(nil, func(ctx *TransactionAttemptContext) error {
+		return nil
+	}, &TransactionOptions{
+		LifecycleHooks: &TransactionLifecycleHooks{
+			OnAttemptStart: func(attempt TransactionAttemptInfo) {
+				overridden++
+			},
+		},
+	}, false)
+	suite.Require().Nil(err, err)
+	suite.Assert().Equal(1, overridden)
+	suite.Assert().Len(started, 1)
+}
+
+func (suite *UnitTestSuite) TestTransactionsTranscoders() {
+	cli := new(mockConnectionManager)
+	cli.On("getMeter").Return(nil)
+	cli.On("close").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	defer cluster.Close(nil)
+
+	cleanupConfig := TransactionsCleanupConfig{
+		DisableLostAttemptCleanup:   true,
+		DisableClientAttemptCleanup: true,
+	}
+
+	err := (&transactionsProviderCore{}).Init(TransactionsConfig{
+		CleanupConfig:       cleanupConfig,
+		ReplicaReadFallback: true,
+	}, cluster)
+	suite.Require().ErrorIs(err, ErrInvalidArgument)
+
+	txns := &transactionsProviderCore{}
+	err = txns.Init(TransactionsConfig{
+		CleanupConfig: cleanupConfig,
+	}, cluster)
+	suite.Require().Nil(err, err)
+	defer txns.close()
+
+	_, err = txns.Run(nil, func(ctx *TransactionAttemptContext) error {
+		valueBytes, flags, err := ctx.encodeValue(NewRawBinaryTranscoder(), []byte{0x01, 0x02})
+		suite.Require().Nil(err, err)
+		suite.Assert().Equal([]byte{0x01, 0x02}, valueBytes)
+
+		// Binary values are staged with their user flags, but cannot be staged by query statements.
+		dataType, _ := gocbcore.DecodeCommonFlags(flags)
+		suite.Assert().Equal(gocbcore.BinaryType, dataType)
+		suite.Assert().ErrorIs(checkQueryModeValue(flags), ErrFeatureNotAvailable)
+
+		_, flags, err = ctx.encodeValue(nil, map[string]string{"name": "airline"})
+		suite.Require().Nil(err, err)
+		suite.Assert().Equal(jsonCommonFlags, flags)
+		suite.Assert().Nil(checkQueryModeValue(flags))
+
+		return nil
+	}, nil, false)
+	suite.Require().Nil(err, err)
+
+	res := (&TransactionGetResult{
+		transcoder: NewJSONTranscoder(),
+		flags:      transactionContentFlags(0),
+		coreRes:    &gocbcore.TransactionGetResult{Value: []byte(`{"name":"airline"}`)},
+	}).withTranscoder(NewRawJSONTranscoder())
+
+	var content []byte
+	suite.Require().Nil(res.Content(&content))
+	suite.Assert().Equal(`{"name":"airline"}`, string(content))
+
+	binaryRes := (&TransactionGetResult{
+		transcoder: NewJSONTranscoder(),
+		flags:      transactionContentFlags(gocbcore.EncodeCommonFlags(gocbcore.BinaryType, gocbcore.NoCompression)),
+		coreRes:    &gocbcore.TransactionGetResult{Value: []byte{0x01, 0x02}},
+	}).withTranscoder(NewRawBinaryTranscoder())
+
+	content = nil
+	suite.Require().Nil(binaryRes.Content(&content))
+	suite.Assert().Equal([]byte{0x01, 0x02}, content)
+}
+
+func (suite *UnitTestSuite) TestTransactionsBulkOperations() {
+	cli := new(mockConnectionManager)
+	cli.On("getMeter").Return(nil)
+	cli.On("close").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+
+	cluster := suite.newCluster(cli)
+	defer cluster.Close(nil)
+
+	txns := &transactionsProviderCore{}
+	err := txns.Init(TransactionsConfig{
+		CleanupConfig: TransactionsCleanupConfig{
+			DisableLostAttemptCleanup:   true,
+			DisableClientAttemptCleanup: true,
+		},
+	}, cluster)
+	suite.Require().Nil(err, err)
+	defer txns.close()
+
+	airline := suite.collection("travel-sample", "inventory", "airline", nil)
+
+	var (
+		emptyRes      []*TransactionGetResult
+		emptyErr      error
+		concurrentErr error
+		insertErr     error
+	)
+	_, err = txns.Run(nil, func(ctx *TransactionAttemptContext) error {
+		emptyRes, emptyErr = ctx.GetMulti(nil, nil)
+		if emptyErr != nil {
+			return emptyErr
+		}
+
+		_, concurrentErr = ctx.GetMulti([]TransactionGetSpec{{Collection: airline, ID: "airline_10"}}, &TransactionBulkOptions{
+			Concurrency: -1,
+		})
+
+		// Values which cannot be encoded fail before anything is staged.
+		_, insertErr = ctx.InsertMulti([]TransactionInsertSpec{
+			{Collection: airline, ID: "airline_10", Value: make(chan int)},
+			{Collection: airline, ID: "airline_11", Value: make(chan int)},
+		}, nil)
+		return insertErr
+	}, nil, false)
+	suite.Assert().Nil(emptyErr)
+	suite.Assert().Empty(emptyRes)
+	suite.Assert().ErrorIs(concurrentErr, ErrInvalidArgument)
+
+	var encodeErr *json.UnsupportedTypeError
+	suite.Assert().ErrorAs(insertErr, &encodeErr)
+	suite.Assert().Error(err)
+}
+
+func (suite *UnitTestSuite) TestTransactionsCleanupStats() {
+	cli := new(mockConnectionManager)
+	cli.On("getMeter").Return(nil)
+	cli.On("close").Return(nil)
+
+	cluster := suite.newCluster(cli)
+	defer cluster.Close(nil)
+
+	txns := &transactionsProviderCore{}
+	err := txns.Init(TransactionsConfig{
+		CleanupConfig: TransactionsCleanupConfig{
+			DisableLostAttemptCleanup:   true,
+			DisableClientAttemptCleanup: true,
+		},
+	}, cluster)
+	suite.Require().Nil(err, err)
+	defer txns.close()
+
+	waitHook := func(hook func([]byte, func(error))) {
+		waitCh := make(chan error, 1)
+		hook([]byte("_txn:atr-1"), func(err error) {
+			waitCh <- err
+		})
+		suite.Require().Nil(<-waitCh)
+	}
+	// Reading documents and ATRs is not counted, only the stages which follow a completed read.
+	waitHook(txns.cleanupHooksWrapper.BeforeATRGet)
+	waitHook(txns.cleanupHooksWrapper.BeforeDocGet)
+	waitHook(txns.cleanupHooksWrapper.BeforeDocGet)
+	waitHook(txns.cleanupHooksWrapper.BeforeCommitDoc)
+	waitHook(txns.cleanupHooksWrapper.BeforeATRRemove)
+
+	stats := txns.CleanupStats()
+	suite.Assert().Equal(uint64(1), stats.DocumentsProcessed)
+	suite.Assert().Equal(uint64(1), stats.EntriesProcessed)
+	suite.Assert().Zero(stats.ClientQueueLength)
+}
+
+func (suite *UnitTestSuite) TestTransactionsMetadataCollectionMapper() {
+	cli := new(mockConnectionManager)
+	cli.On("getMeter").Return(nil)
+	cli.On("close").Return(nil)
+	cli.On("MarkOpBeginning").Return()
+	cli.On("MarkOpCompleted").Return()
+	cli.On("openBucket", "default").Return(nil)
+	cli.On("connection", "default").Return(&gocbcore.Agent{}, nil)
+	cli.On("openBucket", "tenant-a").Return(nil)
+	cli.On("connection", "tenant-a").Return(&gocbcore.Agent{}, nil)
+
+	cluster := suite.newCluster(cli)
+	defer cluster.Close(nil)
+
+	cleanupConfig := TransactionsCleanupConfig{
+		DisableLostAttemptCleanup:   true,
+		DisableClientAttemptCleanup: true,
+	}
+
+	var (
+		lock   sync.Mutex
+		mapped []TransactionKeyspace
+	)
+	mapper := func(keyspace TransactionKeyspace) *TransactionKeyspace {
+		lock.Lock()
+		mapped = append(mapped, keyspace)
+		lock.Unlock()
+
+		return &TransactionKeyspace{
+			BucketName:     keyspace.BucketName,
+			ScopeName:      keyspace.ScopeName,
+			CollectionName: "txn-metadata",
+		}
+	}
+
+	err := (&transactionsProviderCore{}).Init(TransactionsConfig{
+		CleanupConfig: cleanupConfig,
+		MetadataCollection: &TransactionKeyspace{
+			BucketName:     "default",
+			ScopeName:      "_default",
+			CollectionName: "_default",
+		},
+		MetadataCollectionMapper: mapper,
+	}, cluster)
+	suite.Require().ErrorIs(err, ErrInvalidArgument)
+
+	globalTxns := &transactionsProviderCore{}
+	err = globalTxns.Init(TransactionsConfig{
+		CleanupConfig: cleanupConfig,
+		MetadataCollection: &TransactionKeyspace{
+			BucketName:     "default",
+			ScopeName:      "_default",
+			CollectionName: "_default",
+		},
+	}, cluster)
+	suite.Require().Nil(err, err)
+	defer globalTxns.close()
+
+	_, err = globalTxns.Run(nil, func(ctx *TransactionAttemptContext) error {
+		return nil
+	}, &TransactionOptions{
+		MetadataCollectionMapper: mapper,
+	}, false)
+	suite.Require().ErrorIs(err, ErrInvalidArgument)
+
+	txns := &transactionsProviderCore{}
+	err = txns.Init(TransactionsConfig{
+		CleanupConfig:            cleanupConfig,
+		MetadataCollectionMapper: mapper,
+	}, cluster)
+	suite.Require().Nil(err, err)
+	defer txns.close()
+
+	customers := suite.collection("tenant-a", "app", "customers", nil)
+
+	var location gocbcore.TransactionATRLocation
+	_, err = txns.Run(nil, func(ctx *TransactionAttemptContext) error {
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				suite.Assert().Nil(ctx.mapMetadataCollection(customers))
+			}()
+		}
+		wg.Wait()
+
+		location = ctx.txn.GetATRLocation()
+		return nil
+	}, nil, false)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().Equal([]TransactionKeyspace{{
+		BucketName:     "tenant-a",
+		ScopeName:      "app",
+		CollectionName: "customers",
+	}}, mapped)
+	suite.Assert().NotNil(location.Agent)
+	suite.Assert().Equal("app", location.ScopeName)
+	suite.Assert().Equal("txn-metadata", location.CollectionName)
+}
+
+func (suite *UnitTestSuite) TestTransactionsSavepoints() {
+	cli := new(mockConnectionManager)
+	cli.On("getMeter").Return(nil)
+	cli.On("close").Return(nil)
+
+	cluster := suite.newCluster(cli)
+	defer cluster.Close(nil)
+
+	txns := &transactionsProviderCore{}
+	err := txns.Init(TransactionsConfig{
+		CleanupConfig: TransactionsCleanupConfig{
+			DisableLostAttemptCleanup:   true,
+			DisableClientAttemptCleanup: true,
+		},
+	}, cluster)
+	suite.Require().Nil(err, err)
+	defer txns.close()
+
+	var (
+		nilErr      error
+		foreignErr  error
+		firstErr    error
+		releasedErr error
+		repeatErr   error
+	)
+	_, err = txns.Run(nil, func(ctx *TransactionAttemptContext) error {
+		first, err := ctx.Savepoint()
+		if err != nil {
+			return err
+		}
+		second, err := ctx.Savepoint()
+		if err != nil {
+			return err
+		}
+
+		nilErr = ctx.RollbackTo(nil)
+		foreignErr = ctx.RollbackTo(&TransactionSavepoint{state: &transactionSavepointState{}, id: first.id})
+
+		firstErr = ctx.RollbackTo(first)
+		releasedErr = ctx.RollbackTo(second)
+		repeatErr = ctx.RollbackTo(first)
+
+		return nil
+	}, nil, false)
+	suite.Require().Nil(err, err)
+
+	suite.Assert().ErrorIs(nilErr, ErrInvalidArgument)
+	suite.Assert().ErrorIs(foreignErr, ErrInvalidArgument)
+	suite.Assert().Nil(firstErr)
+	suite.Assert().ErrorIs(releasedErr, ErrInvalidArgument)
+	suite.Assert().Nil(repeatErr)
+
+	airline := suite.collection("travel-sample", "inventory", "airline", nil)
+
+	state := &transactionSavepointState{}
+	state.record(transactionUndoEntry{kind: transactionUndoInsert, collection: airline, id: "airline_10"})
+	suite.Assert().Empty(state.undo)
+
+	state.marks = append(state.marks, transactionSavepointMark{id: 1})
+	state.record(transactionUndoEntry{kind: transactionUndoReplace, collection: airline, id: "airline_10"})
+	state.record(transactionUndoEntry{kind: transactionUndoInsert, collection: airline, id: "airline_11"})
+	state.record(transactionUndoEntry{kind: transactionUndoReplace, collection: airline, id: "airline_11"})
+	suite.Require().Len(state.undo, 3)
+
+	// Only documents which were not mutated before the savepoint can be dropped from the attempt when rolled back.
+	suite.Assert().False(state.undo[0].firstMutation)
+	suite.Assert().True(state.undo[1].firstMutation)
+	suite.Assert().False(state.undo[2].firstMutation)
+
+	state.forget(state.undo[1].key())
+	state.record(transactionUndoEntry{kind: transactionUndoInsert, collection: airline, id: "airline_11"})
+	suite.Assert().True(state.undo[3].firstMutation)
+}
+
+func (suite *UnitTestSuite) TestTransactionsLimitsRelease() {
+	airline := suite.collection("travel-sample", "inventory", "airline", nil)
+	route := suite.collection("travel-sample", "inventory", "route", nil)
+
+	limits := newTransactionLimitsState(TransactionLimits{MaxCollections: 1, MaxMutations: 2})
+	_, err := limits.reserve(airline, "airline_10")
+	suite.Require().Nil(err, err)
+	_, err = limits.reserve(airline, "airline_11")
+	suite.Require().Nil(err, err)
+
+	_, err = limits.reserve(airline, "airline_12")
+	suite.Assert().ErrorIs(err, ErrTransactionMutationLimitExceeded)
+
+	// A document dropped from the attempt no longer counts towards the limits.
+	limits.release(airline, "airline_11")
+	_, err = limits.reserve(airline, "airline_12")
+	suite.Require().Nil(err, err)
+
+	// A collection only stops counting once none of its documents are part of the attempt.
+	limits.release(airline, "airline_10")
+	_, err = limits.reserve(route, "route_10")
+	suite.Assert().ErrorIs(err, ErrTransactionCollectionLimitExceeded)
+
+	limits.release(airline, "airline_12")
+	_, err = limits.reserve(route, "route_10")
+	suite.Require().Nil(err, err)
+}
@@ -1,9 +1,18 @@
 package gocb
 
-import "github.com/couchbase/gocbcore/v10"
+import (
+	"context"
+
+	"github.com/couchbase/gocbcore/v10"
+)
+
+const (
+	transactionsUnstagingParallelismLimit = 1000
+)
 
 type transactionsProvider interface {
-	Run(logicFn AttemptFunc, perConfig *TransactionOptions, singleQueryMode bool) (*TransactionResult, error)
+	Run(ctx context.Context, logicFn AttemptFunc, perConfig *TransactionOptions, singleQueryMode bool) (*TransactionResult, error)
+	CleanupStats() TransactionsCleanupStats
 
 	Internal() transactionsInternal
 }
@@ -1,6 +1,9 @@
+//go:build !gocb_notransactions
+
 package gocb
 
 import (
+	"context"
 	"errors"
 	"math"
 	"sync"
@@ -31,12 +34,9 @@ type transactionsProviderCore struct {
 	hooksWrapper        transactionHooksWrapper
 	cleanupHooksWrapper transactionCleanupHooksWrapper
 	cleanupCollections  []gocbcore.TransactionLostATRLocation
+	cleanupObserver     *transactionsCleanupObserver
 }
 
-const (
-	transactionsUnstagingParallelismLimit = 1000
-)
-
 func (t *transactionsProviderCore) Init(config TransactionsConfig, c *Cluster) error {
 	// Note that gocbcore will handle a lot of default values for us.
 	if config.QueryConfig.ScanConsistency == 0 {
@@ -46,6 +46,10 @@ func (t *transactionsProviderCore) Init(config TransactionsConfig, c *Cluster) e
 		config.DurabilityLevel = DurabilityLevelMajority
 	}
 
+	if config.MetadataCollection != nil && config.MetadataCollectionMapper != nil {
+		return makeInvalidArgumentsError("metadata collection and metadata collection mapper cannot both be set")
+	}
+
 	var hooksWrapper transactionHooksWrapper
 	if config.Internal.Hooks == nil {
 		hooksWrapper = &noopHooksWrapper{
@@ -69,6 +73,12 @@ func (t *transactionsProviderCore) Init(config TransactionsConfig, c *Cluster) e
 		}
 	}
 
+	cleanupObserver := &transactionsCleanupObserver{}
+	cleanupHooksWrapper = &observedCleanupHooksWrapper{
+		transactionCleanupHooksWrapper: cleanupHooksWrapper,
+		observer:                       cleanupObserver,
+	}
+
 	var clientRecordHooksWrapper clientRecordHooksWrapper
 	if config.Internal.ClientRecordHooks == nil {
 		clientRecordHooksWrapper = &noopClientRecordHooksWrapper{
@@ -126,6 +136,7 @@ func (t *transactionsProviderCore) Init(config TransactionsConfig, c *Cluster) e
 	t.hooksWrapper = hooksWrapper
 	t.cleanupHooksWrapper = cleanupHooksWrapper
 	t.cleanupCollections = cleanupLocs
+	t.cleanupObserver = cleanupObserver
 
 	corecfg := &gocbcore.TransactionsConfig{}
 	corecfg.DurabilityLevel = gocbcore.TransactionDurabilityLevel(config.DurabilityLevel)
@@ -143,6 +154,15 @@ func (t *transactionsProviderCore) Init(config TransactionsConfig, c *Cluster) e
 	corecfg.Internal.NumATRs = config.Internal.NumATRs
 	corecfg.Internal.EnableParallelUnstaging = true
 	corecfg.Internal.UnstagingParallelismLimit = transactionsUnstagingParallelismLimit
+	if config.ReplicaReadFallback {
+		if c.preferredServerGroup == "" {
+			return makeInvalidArgumentsError("replica read fallback requires a preferred server group to be set")
+		}
+		// A failed read from the active must not prevent the attempt from committing once the read has been
+		// satisfied by a replica. gocbcore applies this to every get, so the attempt context marks the attempt as
+		// unable to commit after any get which is not satisfied by falling back to a replica.
+		corecfg.Internal.EnableNonFatalGets = true
+	}
 	corecfg.KeyValueTimeout = c.timeoutsConfig.KVTimeout
 
 	txns, err := gocbcore.InitTransactions(corecfg)
@@ -154,7 +174,7 @@ func (t *transactionsProviderCore) Init(config TransactionsConfig, c *Cluster) e
 	return nil
 }
 
-func (t *transactionsProviderCore) Run(logicFn AttemptFunc, perConfig *TransactionOptions, singleQueryMode bool) (*TransactionResult, error) {
+func (t *transactionsProviderCore) Run(ctx context.Context, logicFn AttemptFunc, perConfig *TransactionOptions, singleQueryMode bool) (*TransactionResult, error) {
 	if perConfig == nil {
 		perConfig = &TransactionOptions{
 			DurabilityLevel: t.config.DurabilityLevel,
@@ -164,9 +184,30 @@ func (t *transactionsProviderCore) Run(logicFn AttemptFunc, perConfig *Transacti
 
 	scanConsistency := t.config.QueryConfig.ScanConsistency
 
+	limits := t.config.Limits
+	if perConfig.Limits != nil {
+		limits = *perConfig.Limits
+	}
+
+	lifecycleHooks := t.config.LifecycleHooks
+	if perConfig.LifecycleHooks != nil {
+		lifecycleHooks = perConfig.LifecycleHooks
+	}
+	if err := limits.validate(); err != nil {
+		return nil, err
+	}
+
+	metadataCollectionMapper := t.config.MetadataCollectionMapper
+	if perConfig.MetadataCollectionMapper != nil {
+		metadataCollectionMapper = perConfig.MetadataCollectionMapper
+	}
+
 	// Gocbcore looks at whether the location agent is nil to verify whether CustomATRLocation has been set.
 	atrLocation := gocbcore.TransactionATRLocation{}
 	if perConfig.MetadataCollection != nil {
+		// An explicit location for this transaction takes precedence over any mapper.
+		metadataCollectionMapper = nil
+
 		customATRAgent, err := perConfig.MetadataCollection.bucket.Internal().IORouter()
 		if err != nil {
 			return nil, err
@@ -177,6 +218,10 @@ func (t *transactionsProviderCore) Run(logicFn AttemptFunc, perConfig *Transacti
 		atrLocation.ScopeName = perConfig.MetadataCollection.ScopeName()
 	}
 
+	if metadataCollectionMapper != nil && t.config.MetadataCollection != nil {
+		return nil, makeInvalidArgumentsError("metadata collection mapper cannot be used when a metadata collection is configured")
+	}
+
 	logger := newTransactionLogger()
 
 	// TODO: fill in the rest of this config
@@ -187,6 +232,23 @@ func (t *transactionsProviderCore) Run(logicFn AttemptFunc, perConfig *Transacti
 		TransactionLogger: logger,
 	}
 
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// The transaction must not outlive the context, so the context deadline also bounds the expiration time.
+		if deadline, ok := ctx.Deadline(); ok {
+			expirationTime := config.ExpirationTime
+			if expirationTime == 0 {
+				expirationTime = t.txns.Config().ExpirationTime
+			}
+			if untilDeadline := time.Until(deadline); untilDeadline < expirationTime {
+				config.ExpirationTime = untilDeadline
+			}
+		}
+	}
+
 	hooksWrapper := t.hooksWrapper
 	if perConfig.Internal.Hooks != nil {
 		hooksWrapper = &coreTxnsHooksWrapper{
@@ -219,7 +281,7 @@ func (t *transactionsProviderCore) Run(logicFn AttemptFunc, perConfig *Transacti
 		return time.Duration(backoff)
 	}
 
-	for {
+	for attemptNum := 1; ; attemptNum++ {
 		err = txn.NewAttempt()
 		if err != nil {
 			return nil, err
@@ -229,6 +291,13 @@ func (t *transactionsProviderCore) Run(logicFn AttemptFunc, perConfig *Transacti
 		logDebugf("New transaction attempt starting for %s, %s", txn.ID(), attemptID)
 		logger.logInfof(attemptID, "New transaction attempt starting")
 
+		attemptInfo := TransactionAttemptInfo{
+			TransactionID: txn.ID(),
+			AttemptID:     attemptID,
+			Attempt:       attemptNum,
+		}
+		lifecycleHooks.attemptStarted(attemptInfo)
+
 		attempt := TransactionAttemptContext{
 			txn:            txn,
 			transcoder:     t.transcoder,
@@ -241,6 +310,15 @@ func (t *transactionsProviderCore) Run(logicFn AttemptFunc, perConfig *Transacti
 			logger:               logger,
 			attemptID:            attemptID,
 			preferredServerGroup: t.cluster.preferredServerGroup,
+			replicaReadFallback:  t.config.ReplicaReadFallback,
+			ctx:                  ctx,
+			savepoints:           &transactionSavepointState{},
+		}
+		if limits.MaxCollections > 0 || limits.MaxMutations > 0 {
+			attempt.limits = newTransactionLimitsState(limits)
+		}
+		if metadataCollectionMapper != nil {
+			attempt.metadataCollection = newTransactionMetadataCollectionState(metadataCollectionMapper)
 		}
 
 		if hooksWrapper != nil {
@@ -248,6 +326,10 @@ func (t *transactionsProviderCore) Run(logicFn AttemptFunc, perConfig *Transacti
 		}
 
 		lambdaErr := logicFn(&attempt)
+		if ctxErr := attempt.checkContext(); ctxErr != nil && lambdaErr == nil {
+			// The context was cancelled after the final operation of the lambda, it must not be committed.
+			lambdaErr = ctxErr
+		}
 
 		if !singleQueryMode && lambdaErr != nil {
 			logger.logInfof(attemptID, "Lambda returned error and not single query mode")
@@ -274,6 +356,7 @@ func (t *transactionsProviderCore) Run(logicFn AttemptFunc, perConfig *Transacti
 				if rollbackErr != nil {
 					logWarnf("rollback after error failed: %s", rollbackErr)
 				}
+				lifecycleHooks.rolledBack(attemptInfo, transactionErrorCause(finalErr), rollbackErr)
 			}
 		}
 		toRaise := attempt.finalErrorToRaise()
@@ -282,21 +365,30 @@ func (t *transactionsProviderCore) Run(logicFn AttemptFunc, perConfig *Transacti
 			logDebugf("retrying lambda after backoff")
 			sleep := backoffCalc()
 			logger.logInfof(attemptID, "Will retry lambda after %s", sleep)
-			time.Sleep(sleep)
-			continue
-		}
+			if ctx == nil {
+				time.Sleep(sleep)
+				continue
+			}
 
-		// We don't want the TOF to be the cause in the final error we return so we unwrap it.
-		var finalErrCause error
-		if finalErr != nil {
-			var txnErr *TransactionOperationFailedError
-			if errors.As(finalErr, &txnErr) {
-				finalErrCause = txnErr.InternalUnwrap()
-			} else {
-				finalErrCause = finalErr
+			select {
+			case <-time.After(sleep):
+				continue
+			case <-ctx.Done():
+			}
+
+			return nil, &TransactionFailedError{
+				cause: ctx.Err(),
+				result: &TransactionResult{
+					TransactionID:     txn.ID(),
+					UnstagingComplete: false,
+					Logs:              logger.Logs(),
+				},
 			}
 		}
 
+		// We don't want the TOF to be the cause in the final error we return so we unwrap it.
+		finalErrCause := transactionErrorCause(finalErr)
+
 		switch toRaise {
 		case gocbcore.TransactionErrorReasonSuccess:
 			if singleQueryMode && finalErr != nil {
@@ -360,6 +452,23 @@ func (t *transactionsProviderCore) Run(logicFn AttemptFunc, perConfig *Transacti
 	}
 }
 
+// transactionErrorCause returns the cause of a TransactionOperationFailedError, or err if it is any other error.
+func transactionErrorCause(err error) error {
+	var txnErr *TransactionOperationFailedError
+	if errors.As(err, &txnErr) {
+		return txnErr.InternalUnwrap()
+	}
+
+	return err
+}
+
+func (t *transactionsProviderCore) CleanupStats() TransactionsCleanupStats {
+	stats := t.cleanupObserver.stats()
+	stats.ClientQueueLength = t.txns.Internal().CleanupQueueLength()
+
+	return stats
+}
+
 func (t *transactionsProviderCore) Internal() transactionsInternal {
 	return &transactionsInternalCore{parent: t}
 }
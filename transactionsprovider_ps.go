@@ -1,15 +1,25 @@
+//go:build !gocb_nocouchbase2
+
 package gocb
 
-import "github.com/couchbase/gocbcore/v10"
+import (
+	"context"
+
+	"github.com/couchbase/gocbcore/v10"
+)
 
 type transactionsProviderPs struct{}
 
 type transactionsInternalPs struct{}
 
-func (t *transactionsProviderPs) Run(logicFn AttemptFunc, perConfig *TransactionOptions, singleQueryMode bool) (*TransactionResult, error) {
+func (t *transactionsProviderPs) Run(ctx context.Context, logicFn AttemptFunc, perConfig *TransactionOptions, singleQueryMode bool) (*TransactionResult, error) {
 	return nil, wrapError(ErrFeatureNotAvailable, "transactions are not currently supported against the couchbase2 protocol")
 }
 
+func (t *transactionsProviderPs) CleanupStats() TransactionsCleanupStats {
+	return TransactionsCleanupStats{}
+}
+
 func (t *transactionsProviderPs) Internal() transactionsInternal {
 	return &transactionsInternalPs{}
 }
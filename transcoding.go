@@ -7,6 +7,10 @@ import (
 	gocbcore "github.com/couchbase/gocbcore/v10"
 )
 
+// jsonCommonFlags are the common flags of a JSON document, the same as
+// gocbcore.EncodeCommonFlags(gocbcore.JSONType, gocbcore.NoCompression).
+const jsonCommonFlags uint32 = 2 << 24
+
 // Transcoder provides an interface for transforming Go values to and
 // from raw bytes for storage and retreival from Couchbase data storage.
 type Transcoder interface {
@@ -17,12 +21,43 @@ type Transcoder interface {
 	Encode(interface{}) ([]byte, uint32, error)
 }
 
+// RawJSON is a pre-encoded JSON document. Values of this type are stored verbatim by JSONTranscoder, with JSON flags,
+// rather than being encoded again, and can be used as the target of Content to retrieve the document without decoding it.
+// UNCOMMITTED: This API may change in the future.
+type RawJSON []byte
+
+// MarshalJSON returns r as the JSON encoding of r.
+func (r RawJSON) MarshalJSON() ([]byte, error) {
+	if r == nil {
+		return []byte("null"), nil
+	}
+	return r, nil
+}
+
+// UnmarshalJSON sets *r to a copy of data.
+func (r *RawJSON) UnmarshalJSON(data []byte) error {
+	if r == nil {
+		return errors.New("gocb.RawJSON: UnmarshalJSON on nil pointer")
+	}
+	*r = append((*r)[0:0], data...)
+	return nil
+}
+
+// JSONTranscoderOptions are the options available when creating a JSONTranscoder.
+// UNCOMMITTED: This API may change in the future.
+type JSONTranscoderOptions struct {
+	// ValidateRawJSON specifies whether RawJSON values should be checked to be well-formed JSON before being stored.
+	ValidateRawJSON bool
+}
+
 // JSONTranscoder implements the default transcoding behavior and applies JSON transcoding to all values.
 //
 // This will apply the following behavior to the value:
 // binary ([]byte) -> error.
+// RawJSON -> JSON bytes, JSON Flags.
 // default -> JSON value, JSON Flags.
 type JSONTranscoder struct {
+	validateRawJSON bool
 }
 
 // NewJSONTranscoder returns a new JSONTranscoder.
@@ -30,6 +65,18 @@ func NewJSONTranscoder() *JSONTranscoder {
 	return &JSONTranscoder{}
 }
 
+// NewJSONTranscoderWithOptions returns a new JSONTranscoder configured with the provided options.
+// UNCOMMITTED: This API may change in the future.
+func NewJSONTranscoderWithOptions(opts *JSONTranscoderOptions) *JSONTranscoder {
+	if opts == nil {
+		opts = &JSONTranscoderOptions{}
+	}
+
+	return &JSONTranscoder{
+		validateRawJSON: opts.ValidateRawJSON,
+	}
+}
+
 // Decode applies JSON transcoding behaviour to decode into a Go type.
 func (t *JSONTranscoder) Decode(bytes []byte, flags uint32, out interface{}) error {
 	valueType, compression := gocbcore.DecodeCommonFlags(flags)
@@ -72,6 +119,10 @@ func (t *JSONTranscoder) Encode(value interface{}) ([]byte, uint32, error) {
 	case *json.RawMessage:
 		bytes = *typeValue
 		flags = gocbcore.EncodeCommonFlags(gocbcore.JSONType, gocbcore.NoCompression)
+	case RawJSON:
+		return t.encodeRawJSON(typeValue)
+	case *RawJSON:
+		return t.encodeRawJSON(*typeValue)
 	case *interface{}:
 		return t.Encode(*typeValue)
 	default:
@@ -87,6 +138,14 @@ func (t *JSONTranscoder) Encode(value interface{}) ([]byte, uint32, error) {
 	return bytes, flags, nil
 }
 
+func (t *JSONTranscoder) encodeRawJSON(value RawJSON) ([]byte, uint32, error) {
+	if t.validateRawJSON && !json.Valid(value) {
+		return nil, 0, makeInvalidArgumentsError("RawJSON value is not valid JSON")
+	}
+
+	return value, gocbcore.EncodeCommonFlags(gocbcore.JSONType, gocbcore.NoCompression), nil
+}
+
 // RawJSONTranscoder implements passthrough behavior of JSON data. This transcoder does not apply any serialization.
 // It will forward data across the network without incurring unnecessary parsing costs.
 //
@@ -156,6 +215,12 @@ func (t *RawJSONTranscoder) Encode(value interface{}) ([]byte, uint32, error) {
 	case *json.RawMessage:
 		bytes = *typeValue
 		flags = gocbcore.EncodeCommonFlags(gocbcore.JSONType, gocbcore.NoCompression)
+	case RawJSON:
+		bytes = typeValue
+		flags = gocbcore.EncodeCommonFlags(gocbcore.JSONType, gocbcore.NoCompression)
+	case *RawJSON:
+		bytes = *typeValue
+		flags = gocbcore.EncodeCommonFlags(gocbcore.JSONType, gocbcore.NoCompression)
 	case *interface{}:
 		return t.Encode(*typeValue)
 	default:
@@ -382,6 +447,12 @@ func (t *LegacyTranscoder) Encode(value interface{}) ([]byte, uint32, error) {
 	case *json.RawMessage:
 		bytes = *typeValue
 		flags = gocbcore.EncodeCommonFlags(gocbcore.JSONType, gocbcore.NoCompression)
+	case RawJSON:
+		bytes = typeValue
+		flags = gocbcore.EncodeCommonFlags(gocbcore.JSONType, gocbcore.NoCompression)
+	case *RawJSON:
+		bytes = *typeValue
+		flags = gocbcore.EncodeCommonFlags(gocbcore.JSONType, gocbcore.NoCompression)
 	case *interface{}:
 		return t.Encode(*typeValue)
 	default:
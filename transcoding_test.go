@@ -859,3 +859,45 @@ func (suite *UnitTestSuite) TestDecodeString() {
 		}
 	}
 }
+
+func (suite *UnitTestSuite) TestJSONTranscoderRawJSON() {
+	raw := RawJSON(`{"b":2, "a":1}`)
+	expectedFlags := gocbcore.EncodeCommonFlags(gocbcore.JSONType, gocbcore.NoCompression)
+
+	transcoder := NewJSONTranscoder()
+
+	bytes, flags, err := transcoder.Encode(raw)
+	suite.Require().Nil(err)
+	suite.Assert().Equal([]byte(raw), bytes)
+	suite.Assert().Equal(expectedFlags, flags)
+
+	bytes, flags, err = transcoder.Encode(&raw)
+	suite.Require().Nil(err)
+	suite.Assert().Equal([]byte(raw), bytes)
+	suite.Assert().Equal(expectedFlags, flags)
+
+	// Invalid JSON is stored as is unless validation is enabled.
+	bytes, _, err = transcoder.Encode(RawJSON(`{"a":`))
+	suite.Require().Nil(err)
+	suite.Assert().Equal([]byte(`{"a":`), bytes)
+
+	var out RawJSON
+	err = transcoder.Decode([]byte(raw), expectedFlags, &out)
+	suite.Require().Nil(err)
+	suite.Assert().Equal(raw, out)
+}
+
+func (suite *UnitTestSuite) TestJSONTranscoderRawJSONValidation() {
+	transcoder := NewJSONTranscoderWithOptions(&JSONTranscoderOptions{ValidateRawJSON: true})
+
+	_, _, err := transcoder.Encode(RawJSON(`{"a":`))
+	suite.Require().ErrorIs(err, ErrInvalidArgument)
+
+	bytes, _, err := transcoder.Encode(RawJSON(`{"a":1}`))
+	suite.Require().Nil(err)
+	suite.Assert().Equal([]byte(`{"a":1}`), bytes)
+}
+
+func (suite *UnitTestSuite) TestJSONCommonFlags() {
+	suite.Assert().Equal(gocbcore.EncodeCommonFlags(gocbcore.JSONType, gocbcore.NoCompression), jsonCommonFlags)
+}
@@ -11,4 +11,8 @@ type userManagerProvider interface {
 	UpsertGroup(group Group, opts *UpsertGroupOptions) error
 	DropGroup(groupName string, opts *DropGroupOptions) error
 	ChangePassword(newPassword string, opts *ChangePasswordOptions) error
+	SetUserLocked(name string, locked bool, opts *SetUserLockedOptions) error
+	GetLDAPSettings(opts *GetLDAPSettingsOptions) (*LDAPSettings, error)
+	UpsertLDAPSettings(settings LDAPSettings, opts *UpsertLDAPSettingsOptions) error
+	InvalidateLDAPCache(opts *InvalidateLDAPCacheOptions) error
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"io"
 	"net/url"
+	"strconv"
 	"strings"
 )
 
@@ -227,6 +228,13 @@ func (um *userManagerProviderCore) UpsertUser(user User, opts *UpsertUserOptions
 		reqForm.Add("groups", strings.Join(user.Groups, ","))
 	}
 	reqForm.Add("roles", strings.Join(reqRoleStrs, ","))
+	if user.Limits != nil {
+		limits, err := json.Marshal(user.Limits.toData())
+		if err != nil {
+			return err
+		}
+		reqForm.Add("limits", string(limits))
+	}
 
 	req := mgmtRequest{
 		Service:       ServiceTypeManagement,
@@ -599,3 +607,225 @@ func (um *userManagerProviderCore) ChangePassword(newPassword string, opts *Chan
 
 	return nil
 }
+
+func (um *userManagerProviderCore) SetUserLocked(name string, locked bool, opts *SetUserLockedOptions) error {
+	if opts == nil {
+		opts = &SetUserLockedOptions{}
+	}
+
+	path := fmt.Sprintf("/settings/rbac/users/%s/%s", url.PathEscape(string(LocalDomain)), url.PathEscape(name))
+	span := um.tracer.createSpan(opts.ParentSpan, "manager_users_set_user_locked", "management")
+	span.SetAttribute("db.operation", "PATCH "+path)
+	defer span.End()
+
+	reqForm := make(url.Values)
+	reqForm.Add("locked", strconv.FormatBool(locked))
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "PATCH",
+		Path:          path,
+		Body:          []byte(reqForm.Encode()),
+		ContentType:   "application/x-www-form-urlencoded",
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := um.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		usrErr := um.tryParseErrorMessage(&req, resp)
+		if usrErr != nil {
+			return usrErr
+		}
+		return makeMgmtBadStatusError("failed to set user locked", &req, resp)
+	}
+
+	return nil
+}
+
+func (um *userManagerProviderCore) GetLDAPSettings(opts *GetLDAPSettingsOptions) (*LDAPSettings, error) {
+	if opts == nil {
+		opts = &GetLDAPSettingsOptions{}
+	}
+
+	path := "/settings/ldap"
+	span := um.tracer.createSpan(opts.ParentSpan, "manager_users_get_ldap_settings", "management")
+	span.SetAttribute("db.operation", "GET "+path)
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "GET",
+		Path:          path,
+		IsIdempotent:  true,
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := um.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return nil, makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		usrErr := um.tryParseErrorMessage(&req, resp)
+		if usrErr != nil {
+			return nil, usrErr
+		}
+		return nil, makeMgmtBadStatusError("failed to get ldap settings", &req, resp)
+	}
+
+	var settingsData jsonLDAPSettings
+	jsonDec := json.NewDecoder(resp.Body)
+	err = jsonDec.Decode(&settingsData)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings LDAPSettings
+	err = settings.fromData(settingsData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (um *userManagerProviderCore) UpsertLDAPSettings(settings LDAPSettings, opts *UpsertLDAPSettingsOptions) error {
+	if opts == nil {
+		opts = &UpsertLDAPSettingsOptions{}
+	}
+
+	path := "/settings/ldap"
+	span := um.tracer.createSpan(opts.ParentSpan, "manager_users_upsert_ldap_settings", "management")
+	span.SetAttribute("db.operation", "POST "+path)
+	defer span.End()
+
+	reqForm := make(url.Values)
+	if settings.AuthenticationEnabled != nil {
+		reqForm.Add("authenticationEnabled", strconv.FormatBool(*settings.AuthenticationEnabled))
+	}
+	if settings.AuthorizationEnabled != nil {
+		reqForm.Add("authorizationEnabled", strconv.FormatBool(*settings.AuthorizationEnabled))
+	}
+	if settings.ServerCertValidation != nil {
+		reqForm.Add("serverCertValidation", strconv.FormatBool(*settings.ServerCertValidation))
+	}
+	if settings.NestedGroupsEnabled != nil {
+		reqForm.Add("nestedGroupsEnabled", strconv.FormatBool(*settings.NestedGroupsEnabled))
+	}
+	if len(settings.Hosts) > 0 {
+		reqForm.Add("hosts", strings.Join(settings.Hosts, ","))
+	}
+	if settings.Port > 0 {
+		reqForm.Add("port", strconv.Itoa(settings.Port))
+	}
+	if settings.Encryption != "" {
+		reqForm.Add("encryption", string(settings.Encryption))
+	}
+	if settings.CACert != "" {
+		reqForm.Add("cacert", settings.CACert)
+	}
+	if settings.UserDNMapping != "" {
+		reqForm.Add("userDNMapping", settings.UserDNMapping)
+	}
+	if settings.BindDN != "" {
+		reqForm.Add("bindDN", settings.BindDN)
+	}
+	if settings.BindPassword != "" {
+		reqForm.Add("bindPass", settings.BindPassword)
+	}
+	if settings.GroupsQuery != "" {
+		reqForm.Add("groupsQuery", settings.GroupsQuery)
+	}
+	if settings.NestedGroupsMaxDepth > 0 {
+		reqForm.Add("nestedGroupsMaxDepth", strconv.Itoa(settings.NestedGroupsMaxDepth))
+	}
+	if settings.CacheValueLifetime > 0 {
+		reqForm.Add("cacheValueLifetime", strconv.FormatInt(settings.CacheValueLifetime.Milliseconds(), 10))
+	}
+	if settings.RequestTimeout > 0 {
+		reqForm.Add("requestTimeout", strconv.FormatInt(settings.RequestTimeout.Milliseconds(), 10))
+	}
+	if settings.MaxParallelConnections > 0 {
+		reqForm.Add("maxParallelConnections", strconv.Itoa(settings.MaxParallelConnections))
+	}
+	if settings.MaxCacheSize > 0 {
+		reqForm.Add("maxCacheSize", strconv.Itoa(settings.MaxCacheSize))
+	}
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "POST",
+		Path:          path,
+		Body:          []byte(reqForm.Encode()),
+		ContentType:   "application/x-www-form-urlencoded",
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := um.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		usrErr := um.tryParseErrorMessage(&req, resp)
+		if usrErr != nil {
+			return usrErr
+		}
+		return makeMgmtBadStatusError("failed to upsert ldap settings", &req, resp)
+	}
+
+	return nil
+}
+
+func (um *userManagerProviderCore) InvalidateLDAPCache(opts *InvalidateLDAPCacheOptions) error {
+	if opts == nil {
+		opts = &InvalidateLDAPCacheOptions{}
+	}
+
+	path := "/settings/invalidateLDAPCache"
+	span := um.tracer.createSpan(opts.ParentSpan, "manager_users_invalidate_ldap_cache", "management")
+	span.SetAttribute("db.operation", "POST "+path)
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "POST",
+		Path:          path,
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := um.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		usrErr := um.tryParseErrorMessage(&req, resp)
+		if usrErr != nil {
+			return usrErr
+		}
+		return makeMgmtBadStatusError("failed to invalidate ldap cache", &req, resp)
+	}
+
+	return nil
+}
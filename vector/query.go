@@ -1,8 +1,13 @@
 package vector
 
 import (
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
+	"math"
+
+	"github.com/couchbase/gocb/v2/search"
 )
 
 // Query specifies a vector Query.
@@ -13,6 +18,7 @@ type Query struct {
 
 	numCandidates *uint32
 	boost         *float32
+	prefilter     search.Query
 }
 
 // NewQuery constructs a new vector Query.
@@ -32,6 +38,18 @@ func NewBase64Query(vectorFieldName string, base64Vector string) *Query {
 	}
 }
 
+// EncodeBase64 encodes a vector as a Base64-encoded sequence of little-endian IEEE 754 floats, the form accepted by
+// NewBase64Query.
+// UNCOMMITTED: This API may change in the future.
+func EncodeBase64(vector []float32) string {
+	buf := make([]byte, 4*len(vector))
+	for i, f := range vector {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(f))
+	}
+
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
 // NumCandidates controls how many results are returned for this query.
 func (q *Query) NumCandidates(num uint32) *Query {
 	q.numCandidates = &num
@@ -44,6 +62,14 @@ func (q *Query) Boost(boost float32) *Query {
 	return q
 }
 
+// Prefilter restricts the documents considered by this query to those matching the search query, the nearest
+// neighbours are then found from amongst those documents.
+// UNCOMMITTED: This API may change in the future.
+func (q *Query) Prefilter(query search.Query) *Query {
+	q.prefilter = query
+	return q
+}
+
 // InternalQuery is used for internal functionality.
 // Internal: This should never be used and is not supported.
 type InternalQuery struct {
@@ -53,6 +79,7 @@ type InternalQuery struct {
 
 	NumCandidates *uint32
 	Boost         *float32
+	Prefilter     search.Query
 }
 
 // Internal is used for internal functionality.
@@ -64,6 +91,7 @@ func (q *Query) Internal() InternalQuery {
 		Base64Vector:  q.base64Vector,
 		NumCandidates: q.numCandidates,
 		Boost:         q.boost,
+		Prefilter:     q.prefilter,
 	}
 }
 
@@ -75,6 +103,9 @@ func (q InternalQuery) Validate() error {
 	if len(q.Vector) == 0 && len(q.Base64Vector) == 0 {
 		return errors.New("one of vector or base64vector must be specified")
 	}
+	if len(q.Vector) > 0 && len(q.Base64Vector) > 0 {
+		return errors.New("only one of vector or base64vector can be specified")
+	}
 	if q.NumCandidates != nil && *q.NumCandidates == 0 {
 		return errors.New("when set numCandidates must have a value >= 1")
 	}
@@ -85,17 +116,19 @@ func (q InternalQuery) Validate() error {
 // MarshalJSON marshal's this query to JSON for the search REST API.
 func (q InternalQuery) MarshalJSON() ([]byte, error) {
 	outStruct := &struct {
-		Field         string    `json:"field"`
-		Vector        []float32 `json:"vector,omitempty"`
-		Base64Vector  string    `json:"vector_base64,omitempty"`
-		NumCandidates *uint32   `json:"k,omitempty"`
-		Boost         *float32  `json:"boost,omitempty"`
+		Field         string       `json:"field"`
+		Vector        []float32    `json:"vector,omitempty"`
+		Base64Vector  string       `json:"vector_base64,omitempty"`
+		NumCandidates *uint32      `json:"k,omitempty"`
+		Boost         *float32     `json:"boost,omitempty"`
+		Prefilter     search.Query `json:"filter,omitempty"`
 	}{
 		Field:         q.Field,
 		Vector:        q.Vector,
 		Base64Vector:  q.Base64Vector,
 		NumCandidates: q.NumCandidates,
 		Boost:         q.Boost,
+		Prefilter:     q.Prefilter,
 	}
 
 	return json.Marshal(outStruct)
@@ -25,7 +25,8 @@ type Search struct {
 	vectorQueryCombination VectorQueryCombination
 }
 
-// NewSearch constructs a new vector Search.
+// NewSearch constructs a new vector Search. Each query can search for a different vector, with its own boost and
+// prefilter, and VectorQueryCombination controls how the results of the queries are combined.
 func NewSearch(queries []*Query, opts *SearchOptions) *Search {
 	if opts == nil {
 		opts = &SearchOptions{}
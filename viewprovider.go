@@ -2,6 +2,7 @@ package gocb
 
 type viewProvider interface {
 	ViewQuery(designDoc string, viewName string, opts *ViewOptions) (*ViewResult, error)
+	SpatialViewQuery(designDoc string, viewName string, opts *SpatialViewOptions) (*ViewResult, error)
 }
 
 type viewRowReader interface {
@@ -16,9 +16,10 @@ type jsonViewResponse struct {
 }
 
 type jsonViewRow struct {
-	ID    string          `json:"id"`
-	Key   json.RawMessage `json:"key"`
-	Value json.RawMessage `json:"value"`
+	ID       string          `json:"id"`
+	Key      json.RawMessage `json:"key"`
+	Value    json.RawMessage `json:"value"`
+	Geometry json.RawMessage `json:"geometry"`
 }
 
 type viewProviderCore struct {
@@ -60,6 +61,35 @@ func (v *viewProviderCore) ViewQuery(designDoc string, viewName string, opts *Vi
 		retryWrapper, opts.Internal.User)
 }
 
+// SpatialViewQuery performs a spatial view query and returns a list of rows or an error.
+func (v *viewProviderCore) SpatialViewQuery(designDoc string, viewName string, opts *SpatialViewOptions) (*ViewResult, error) {
+	designDoc = v.maybePrefixDevDocument(opts.Namespace, designDoc)
+
+	span := v.tracer.createSpan(opts.ParentSpan, "views", "views")
+	span.SetAttribute("db.name", v.bucketName)
+	span.SetAttribute("db.operation", designDoc+"/"+viewName)
+	defer span.End()
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = v.timeouts.ViewTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	retryWrapper := v.retryStrategyWrapper
+	if opts.RetryStrategy != nil {
+		retryWrapper = newCoreRetryStrategyWrapper(opts.RetryStrategy)
+	}
+
+	urlValues, err := opts.toURLValues()
+	if err != nil {
+		return nil, wrapError(err, "could not parse query options")
+	}
+
+	return v.execViewQuery(opts.Context, span.Context(), "_spatial", designDoc, viewName, *urlValues, deadline,
+		retryWrapper, opts.Internal.User)
+}
+
 func (v *viewProviderCore) execViewQuery(
 	ctx context.Context,
 	span RequestSpanContext,
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -218,3 +219,104 @@ func (opts *ViewOptions) marshalJSON(value interface{}) ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
+
+// SpatialViewBoundingBox is a bounding box used to filter the results of a spatial view query, specified as the
+// longitude and latitude of the bottom left and top right corners.
+// UNCOMMITTED: This API may change in the future.
+type SpatialViewBoundingBox struct {
+	MinLon float64
+	MinLat float64
+	MaxLon float64
+	MaxLat float64
+}
+
+// SpatialViewOptions represents the options available when executing a spatial view query.
+// UNCOMMITTED: This API may change in the future.
+type SpatialViewOptions struct {
+	ScanConsistency ViewScanConsistency
+	Skip            uint32
+	Limit           uint32
+
+	// BoundingBox restricts the results to those within the box. This cannot be used alongside StartRange or
+	// EndRange.
+	BoundingBox *SpatialViewBoundingBox
+	// StartRange and EndRange restrict the results to those within the range in each dimension emitted by the view,
+	// a nil element leaves that dimension unbounded.
+	StartRange []interface{}
+	EndRange   []interface{}
+
+	OnError    ViewErrorMode
+	Debug      bool
+	ParentSpan RequestSpan
+
+	// Raw provides a way to provide extra parameters in the request body for the query.
+	Raw map[string]string
+
+	Namespace DesignDocumentNamespace
+
+	Timeout       time.Duration
+	RetryStrategy RetryStrategy
+
+	// Using a deadlined Context alongside a Timeout will cause the shorter of the two to cause cancellation, this
+	// also applies to global level timeouts.
+	Context context.Context
+
+	// Internal: This should never be used and is not supported.
+	Internal struct {
+		User string
+	}
+}
+
+func (opts *SpatialViewOptions) toURLValues() (*url.Values, error) {
+	viewOpts := &ViewOptions{
+		ScanConsistency: opts.ScanConsistency,
+		Skip:            opts.Skip,
+		Limit:           opts.Limit,
+		OnError:         opts.OnError,
+		Debug:           opts.Debug,
+	}
+	options, err := viewOpts.toURLValues()
+	if err != nil {
+		return nil, err
+	}
+	// Spatial views cannot be reduced.
+	options.Del("reduce")
+
+	if opts.BoundingBox != nil {
+		if len(opts.StartRange) > 0 || len(opts.EndRange) > 0 {
+			return nil, makeInvalidArgumentsError("bounding box cannot be used with start range or end range")
+		}
+
+		box := opts.BoundingBox
+		options.Set("bbox", strings.Join([]string{
+			strconv.FormatFloat(box.MinLon, 'f', -1, 64),
+			strconv.FormatFloat(box.MinLat, 'f', -1, 64),
+			strconv.FormatFloat(box.MaxLon, 'f', -1, 64),
+			strconv.FormatFloat(box.MaxLat, 'f', -1, 64),
+		}, ","))
+	}
+
+	if len(opts.StartRange) > 0 {
+		jsonRange, err := viewOpts.marshalJSON(opts.StartRange)
+		if err != nil {
+			return nil, err
+		}
+		options.Set("start_range", string(jsonRange))
+	}
+
+	if len(opts.EndRange) > 0 {
+		jsonRange, err := viewOpts.marshalJSON(opts.EndRange)
+		if err != nil {
+			return nil, err
+		}
+		options.Set("end_range", string(jsonRange))
+	}
+
+	if opts.Raw != nil {
+		for k, v := range opts.Raw {
+			options.Set(k, v)
+		}
+	}
+
+	return options, nil
+}
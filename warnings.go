@@ -0,0 +1,76 @@
+package gocb
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WarningType is the category of a Warning.
+// UNCOMMITTED: This API may change in the future.
+type WarningType string
+
+const (
+	// WarningTypeDeprecated indicates that a deprecated API was used.
+	WarningTypeDeprecated WarningType = "deprecated"
+
+	// WarningTypeCapabilityDowngrade indicates that a feature was unavailable and so a less capable alternative was
+	// used.
+	WarningTypeCapabilityDowngrade WarningType = "capability_downgrade"
+
+	// WarningTypeFallback indicates that the SDK silently adjusted the request, such as coercing a timeout or
+	// fetching a full document in place of a projection.
+	WarningTypeFallback WarningType = "fallback"
+)
+
+// Warning describes a condition which did not cause an operation to fail, but which the application may want to act
+// upon, such as the use of a deprecated API.
+// UNCOMMITTED: This API may change in the future.
+type Warning struct {
+	Type WarningType
+	// Feature is the API or option which the warning relates to, e.g. Collection.Get.
+	Feature string
+	Message string
+	// Details contains any further context, such as the adjusted value of an option.
+	Details map[string]interface{}
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s (%s): %s", w.Feature, w.Type, w.Message)
+}
+
+// WarningHandler is called, synchronously, with each Warning raised by the SDK. Handlers must be safe for concurrent
+// use and should not block.
+// UNCOMMITTED: This API may change in the future.
+type WarningHandler func(warning Warning)
+
+// warnDeprecated raises a WarningTypeDeprecated warning for the use of a deprecated API or option.
+func warnDeprecated(handler WarningHandler, feature, message string) {
+	emitWarning(handler, Warning{
+		Type:    WarningTypeDeprecated,
+		Feature: feature,
+		Message: message,
+	})
+}
+
+// warningLogInterval is the minimum time between logging warnings of the same type and feature, so that a warning
+// raised on every operation, such as for a projection of more than 16 paths, does not flood the log.
+const warningLogInterval = time.Minute
+
+// warningLogTimes holds when each type and feature of warning was last logged.
+var warningLogTimes sync.Map
+
+// emitWarning logs the warning, as was always done, and passes it to the handler if one is registered. Every warning
+// is passed to the handler, but repeats of a warning are only logged once per warningLogInterval.
+func emitWarning(handler WarningHandler, warning Warning) {
+	key := string(warning.Type) + "|" + warning.Feature
+	now := time.Now()
+	if last, ok := warningLogTimes.Load(key); !ok || now.Sub(last.(time.Time)) >= warningLogInterval {
+		warningLogTimes.Store(key, now)
+		logWarnf("%s", warning.String())
+	}
+
+	if handler != nil {
+		handler(warning)
+	}
+}
@@ -0,0 +1,136 @@
+package gocb
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/couchbase/gocbcore/v10"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *UnitTestSuite) TestDurableWriteWithoutReplicasWarning() {
+	pendingOp := new(mockPendingOp)
+	provider := new(mockKvProviderCoreProvider)
+	provider.
+		On("Set", mock.AnythingOfType("gocbcore.SetOptions"), mock.AnythingOfType("gocbcore.StoreCallback")).
+		Run(func(args mock.Arguments) {
+			cb := args.Get(1).(gocbcore.StoreCallback)
+			cb(&gocbcore.StoreResult{Cas: gocbcore.Cas(123)}, nil)
+		}).
+		Return(pendingOp, nil)
+
+	for _, numReplicas := range []int{0, 1} {
+		snapshot := &mockConfigSnapshot{revID: 1, numReplicas: numReplicas}
+		snapshotProvider := &mockConfigSnapshotProvider{snapshot: snapshot}
+
+		var warnings []Warning
+		col := suite.collection("mock", "", "", suite.kvProviderCore(provider, snapshotProvider))
+		col.warningHandler = func(warning Warning) {
+			warnings = append(warnings, warning)
+		}
+
+		_, err := col.Upsert("someid", "someval", &UpsertOptions{DurabilityLevel: DurabilityLevelMajority})
+		suite.Require().Nil(err, err)
+		_, err = col.Upsert("someid", "someval", nil)
+		suite.Require().Nil(err, err)
+
+		if numReplicas > 0 {
+			suite.Assert().Empty(warnings)
+			continue
+		}
+
+		suite.Require().Len(warnings, 1)
+		suite.Assert().Equal(WarningTypeCapabilityDowngrade, warnings[0].Type)
+		suite.Assert().Equal("DurabilityLevel", warnings[0].Feature)
+		suite.Assert().Equal("someid", warnings[0].Details["document_id"])
+
+		// The bucket is only checked again once the config has changed.
+		_, err = col.Upsert("someid", "someval", &UpsertOptions{DurabilityLevel: DurabilityLevelMajority})
+		suite.Require().Nil(err, err)
+		suite.Assert().Len(warnings, 1)
+
+		snapshot.revID = 2
+		_, err = col.Upsert("someid", "someval", &UpsertOptions{DurabilityLevel: DurabilityLevelMajority})
+		suite.Require().Nil(err, err)
+		suite.Assert().Len(warnings, 2)
+	}
+}
+
+type warningCountingLogger struct {
+	lock    sync.Mutex
+	feature string
+	count   int
+}
+
+func (l *warningCountingLogger) Log(level LogLevel, offset int, format string, v ...interface{}) error {
+	if level == LogWarn && strings.Contains(fmt.Sprintf(format, v...), l.feature) {
+		l.lock.Lock()
+		l.count++
+		l.lock.Unlock()
+	}
+	return nil
+}
+
+func (l *warningCountingLogger) logged() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.count
+}
+
+func (suite *UnitTestSuite) TestEmitWarningRateLimitsLogging() {
+	warning := Warning{Type: WarningTypeFallback, Feature: "TestEmitWarningRateLimitsLogging"}
+
+	logger := &warningCountingLogger{feature: warning.Feature}
+	previousLogger := globalLogger
+	SetLogger(logger)
+	defer SetLogger(previousLogger)
+
+	var handled int
+	for i := 0; i < 3; i++ {
+		emitWarning(func(Warning) { handled++ }, warning)
+	}
+
+	suite.Assert().Equal(3, handled)
+	suite.Assert().Equal(1, logger.logged())
+
+	// Once the interval has passed the warning is logged again.
+	warningLogTimes.Store(string(warning.Type)+"|"+warning.Feature, time.Now().Add(-warningLogInterval))
+	emitWarning(nil, warning)
+	suite.Assert().Equal(2, logger.logged())
+}
+
+func (suite *UnitTestSuite) TestDeprecationWarnings() {
+	var features []string
+	handler := func(warning Warning) {
+		suite.Assert().Equal(WarningTypeDeprecated, warning.Type)
+		features = append(features, warning.Feature)
+	}
+
+	cli := new(mockConnectionManager)
+	cli.On("getMeter").Return(nil)
+	bucket := &Bucket{warningHandler: handler, connectionManager: cli}
+	bucket.Collections()
+
+	expiry := time.Time{}
+	res := &GetResult{expiryTime: &expiry, warningHandler: handler}
+	res.Expiry()
+
+	bm := &BucketManager{warningHandler: handler}
+	bm.warnIfMaxTTL(&BucketSettings{MaxTTL: time.Hour})
+	// Settings returned by GetBucket populate both, so are not warned about.
+	bm.warnIfMaxTTL(&BucketSettings{MaxTTL: time.Hour, MaxExpiry: time.Hour})
+	bm.warnIfMaxTTL(&BucketSettings{MaxExpiry: time.Hour})
+
+	qm := &QueryIndexManager{warningHandler: handler}
+	qm.warnIfScopeCollection("CreateQueryIndexOptions", "scope", "collection")
+	qm.warnIfScopeCollection("CreateQueryIndexOptions", "", "")
+
+	suite.Assert().Equal([]string{
+		"Bucket.Collections",
+		"GetResult.Expiry",
+		"BucketSettings.MaxTTL",
+		"CreateQueryIndexOptions.ScopeName",
+	}, features)
+}
@@ -0,0 +1,12 @@
+package gocb
+
+type xdcrManagementProvider interface {
+	CreateRemoteCluster(cluster XDCRRemoteCluster, opts *CreateXDCRRemoteClusterOptions) error
+	GetAllRemoteClusters(opts *GetAllXDCRRemoteClustersOptions) ([]XDCRRemoteCluster, error)
+	DeleteRemoteCluster(name string, opts *DeleteXDCRRemoteClusterOptions) error
+	CreateReplication(replication XDCRReplication, opts *CreateXDCRReplicationOptions) (string, error)
+	PauseReplication(replicationID string, opts *PauseXDCRReplicationOptions) error
+	ResumeReplication(replicationID string, opts *ResumeXDCRReplicationOptions) error
+	DeleteReplication(replicationID string, opts *DeleteXDCRReplicationOptions) error
+	GetReplicationStatus(replicationID string, opts *GetXDCRReplicationStatusOptions) (*XDCRReplicationStatus, error)
+}
@@ -0,0 +1,399 @@
+package gocb
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type xdcrManagementProviderCore struct {
+	provider mgmtProvider
+
+	tracer *tracerWrapper
+}
+
+type jsonXDCRRemoteCluster struct {
+	Name           string `json:"name"`
+	Hostname       string `json:"hostname"`
+	Username       string `json:"username"`
+	UUID           string `json:"uuid"`
+	EncryptionType string `json:"encryptionType"`
+	DemandEncrypt  bool   `json:"demandEncryption"`
+	Certificate    string `json:"certificate"`
+	Deleted        bool   `json:"deleted"`
+}
+
+type jsonXDCRCreateReplicationResponse struct {
+	ID string `json:"id"`
+}
+
+type jsonXDCRTask struct {
+	Type             string            `json:"type"`
+	ID               string            `json:"id"`
+	Status           string            `json:"status"`
+	Source           string            `json:"source"`
+	Target           string            `json:"target"`
+	FilterExpression string            `json:"filterExpression"`
+	ChangesLeft      uint64            `json:"changesLeft"`
+	DocsChecked      uint64            `json:"docsChecked"`
+	DocsWritten      uint64            `json:"docsWritten"`
+	Errors           []json.RawMessage `json:"errors"`
+}
+
+func (s *XDCRReplicationStatus) fromData(data jsonXDCRTask) {
+	s.ID = data.ID
+	s.State = XDCRReplicationState(data.Status)
+	s.SourceBucket = data.Source
+	s.FilterExpression = data.FilterExpression
+	s.ChangesLeft = data.ChangesLeft
+	s.DocsChecked = data.DocsChecked
+	s.DocsWritten = data.DocsWritten
+
+	// The target is of the form /remoteClusters/<uuid>/buckets/<bucket>.
+	targetParts := strings.Split(strings.TrimPrefix(data.Target, "/"), "/")
+	if len(targetParts) == 4 && targetParts[0] == "remoteClusters" && targetParts[2] == "buckets" {
+		s.RemoteClusterUUID = targetParts[1]
+		s.TargetBucket = targetParts[3]
+	}
+
+	s.Errors = make([]string, 0, len(data.Errors))
+	for _, rawErr := range data.Errors {
+		var errText string
+		if err := json.Unmarshal(rawErr, &errText); err != nil {
+			errText = string(rawErr)
+		}
+		s.Errors = append(s.Errors, errText)
+	}
+}
+
+func (xm *xdcrManagementProviderCore) CreateRemoteCluster(cluster XDCRRemoteCluster, opts *CreateXDCRRemoteClusterOptions) error {
+	span := xm.tracer.createSpan(opts.ParentSpan, "manager_xdcr_create_remote_cluster", "management")
+	span.SetAttribute("db.operation", "POST /pools/default/remoteClusters")
+	defer span.End()
+
+	reqForm := make(url.Values)
+	reqForm.Add("name", cluster.Name)
+	reqForm.Add("hostname", cluster.Hostname)
+	if cluster.Username != "" {
+		reqForm.Add("username", cluster.Username)
+	}
+	if cluster.Password != "" {
+		reqForm.Add("password", cluster.Password)
+	}
+	switch cluster.EncryptionType {
+	case XDCREncryptionTypeHalf, XDCREncryptionTypeFull:
+		reqForm.Add("demandEncryption", "1")
+		reqForm.Add("encryptionType", string(cluster.EncryptionType))
+	default:
+		reqForm.Add("demandEncryption", "0")
+	}
+	if cluster.Certificate != "" {
+		reqForm.Add("certificate", cluster.Certificate)
+	}
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "POST",
+		Path:          "/pools/default/remoteClusters",
+		Body:          []byte(reqForm.Encode()),
+		ContentType:   "application/x-www-form-urlencoded",
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := xm.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return makeMgmtBadStatusError("failed to create remote cluster", &req, resp)
+	}
+
+	return nil
+}
+
+func (xm *xdcrManagementProviderCore) GetAllRemoteClusters(opts *GetAllXDCRRemoteClustersOptions) ([]XDCRRemoteCluster, error) {
+	span := xm.tracer.createSpan(opts.ParentSpan, "manager_xdcr_get_all_remote_clusters", "management")
+	span.SetAttribute("db.operation", "GET /pools/default/remoteClusters")
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "GET",
+		Path:          "/pools/default/remoteClusters",
+		RetryStrategy: opts.RetryStrategy,
+		IsIdempotent:  true,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := xm.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return nil, makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, makeMgmtBadStatusError("failed to get remote clusters", &req, resp)
+	}
+
+	var clustersData []jsonXDCRRemoteCluster
+	jsonDec := json.NewDecoder(resp.Body)
+	err = jsonDec.Decode(&clustersData)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make([]XDCRRemoteCluster, 0, len(clustersData))
+	for _, clusterData := range clustersData {
+		// References which have been deleted can still be listed until the server has finished cleaning them up.
+		if clusterData.Deleted {
+			continue
+		}
+
+		encryptionType := XDCREncryptionTypeNone
+		if clusterData.DemandEncrypt {
+			encryptionType = XDCREncryptionType(clusterData.EncryptionType)
+		}
+
+		clusters = append(clusters, XDCRRemoteCluster{
+			Name:           clusterData.Name,
+			Hostname:       clusterData.Hostname,
+			Username:       clusterData.Username,
+			EncryptionType: encryptionType,
+			Certificate:    clusterData.Certificate,
+			UUID:           clusterData.UUID,
+		})
+	}
+
+	return clusters, nil
+}
+
+func (xm *xdcrManagementProviderCore) DeleteRemoteCluster(name string, opts *DeleteXDCRRemoteClusterOptions) error {
+	span := xm.tracer.createSpan(opts.ParentSpan, "manager_xdcr_delete_remote_cluster", "management")
+	span.SetAttribute("db.operation", "DELETE /pools/default/remoteClusters/"+name)
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "DELETE",
+		Path:          "/pools/default/remoteClusters/" + url.PathEscape(name),
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := xm.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode == 404 {
+		return makeGenericMgmtError(ErrXDCRRemoteClusterNotFound, &req, resp, "")
+	}
+	if resp.StatusCode != 200 {
+		return makeMgmtBadStatusError("failed to delete remote cluster", &req, resp)
+	}
+
+	return nil
+}
+
+func (xm *xdcrManagementProviderCore) CreateReplication(replication XDCRReplication, opts *CreateXDCRReplicationOptions) (string, error) {
+	span := xm.tracer.createSpan(opts.ParentSpan, "manager_xdcr_create_replication", "management")
+	span.SetAttribute("db.operation", "POST /controller/createReplication")
+	defer span.End()
+
+	reqForm := make(url.Values)
+	reqForm.Add("fromBucket", replication.SourceBucket)
+	reqForm.Add("toCluster", replication.RemoteCluster)
+	reqForm.Add("toBucket", replication.TargetBucket)
+	reqForm.Add("replicationType", "continuous")
+	if replication.FilterExpression != "" {
+		reqForm.Add("filterExpression", replication.FilterExpression)
+	}
+	if replication.Priority != "" {
+		reqForm.Add("priority", string(replication.Priority))
+	}
+	if len(replication.CollectionMappings) > 0 {
+		rules, err := json.Marshal(replication.CollectionMappings)
+		if err != nil {
+			return "", err
+		}
+
+		reqForm.Add("collectionsExplicitMapping", "true")
+		reqForm.Add("colMappingRules", string(rules))
+	}
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "POST",
+		Path:          "/controller/createReplication",
+		Body:          []byte(reqForm.Encode()),
+		ContentType:   "application/x-www-form-urlencoded",
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := xm.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return "", makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return "", makeMgmtBadStatusError("failed to create replication", &req, resp)
+	}
+
+	var respData jsonXDCRCreateReplicationResponse
+	jsonDec := json.NewDecoder(resp.Body)
+	err = jsonDec.Decode(&respData)
+	if err != nil {
+		return "", err
+	}
+
+	return respData.ID, nil
+}
+
+func (xm *xdcrManagementProviderCore) PauseReplication(replicationID string, opts *PauseXDCRReplicationOptions) error {
+	return xm.setReplicationPaused(replicationID, true, "manager_xdcr_pause_replication", opts.ParentSpan,
+		opts.RetryStrategy, opts.Timeout, opts.Context)
+}
+
+func (xm *xdcrManagementProviderCore) ResumeReplication(replicationID string, opts *ResumeXDCRReplicationOptions) error {
+	return xm.setReplicationPaused(replicationID, false, "manager_xdcr_resume_replication", opts.ParentSpan,
+		opts.RetryStrategy, opts.Timeout, opts.Context)
+}
+
+func (xm *xdcrManagementProviderCore) setReplicationPaused(replicationID string, paused bool, spanName string,
+	parentSpan RequestSpan, retryStrategy RetryStrategy, timeout time.Duration, ctx context.Context) error {
+	span := xm.tracer.createSpan(parentSpan, spanName, "management")
+	span.SetAttribute("db.operation", "POST /settings/replications/"+replicationID)
+	defer span.End()
+
+	reqForm := make(url.Values)
+	if paused {
+		reqForm.Add("pauseRequested", "true")
+	} else {
+		reqForm.Add("pauseRequested", "false")
+	}
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "POST",
+		Path:          "/settings/replications/" + url.PathEscape(replicationID),
+		Body:          []byte(reqForm.Encode()),
+		ContentType:   "application/x-www-form-urlencoded",
+		RetryStrategy: retryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := xm.provider.executeMgmtRequest(ctx, req)
+	if err != nil {
+		return makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode == 404 {
+		return makeGenericMgmtError(ErrXDCRReplicationNotFound, &req, resp, "")
+	}
+	if resp.StatusCode != 200 {
+		if paused {
+			return makeMgmtBadStatusError("failed to pause replication", &req, resp)
+		}
+		return makeMgmtBadStatusError("failed to resume replication", &req, resp)
+	}
+
+	return nil
+}
+
+func (xm *xdcrManagementProviderCore) DeleteReplication(replicationID string, opts *DeleteXDCRReplicationOptions) error {
+	span := xm.tracer.createSpan(opts.ParentSpan, "manager_xdcr_delete_replication", "management")
+	span.SetAttribute("db.operation", "DELETE /controller/cancelXDCR/"+replicationID)
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "DELETE",
+		Path:          "/controller/cancelXDCR/" + url.PathEscape(replicationID),
+		RetryStrategy: opts.RetryStrategy,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := xm.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode == 404 {
+		return makeGenericMgmtError(ErrXDCRReplicationNotFound, &req, resp, "")
+	}
+	if resp.StatusCode != 200 {
+		return makeMgmtBadStatusError("failed to delete replication", &req, resp)
+	}
+
+	return nil
+}
+
+func (xm *xdcrManagementProviderCore) GetReplicationStatus(replicationID string, opts *GetXDCRReplicationStatusOptions) (*XDCRReplicationStatus, error) {
+	span := xm.tracer.createSpan(opts.ParentSpan, "manager_xdcr_get_replication_status", "management")
+	span.SetAttribute("db.operation", "GET /pools/default/tasks")
+	defer span.End()
+
+	req := mgmtRequest{
+		Service:       ServiceTypeManagement,
+		Method:        "GET",
+		Path:          "/pools/default/tasks",
+		RetryStrategy: opts.RetryStrategy,
+		IsIdempotent:  true,
+		UniqueID:      uuid.New().String(),
+		Timeout:       opts.Timeout,
+		parentSpanCtx: span.Context(),
+	}
+
+	resp, err := xm.provider.executeMgmtRequest(opts.Context, req)
+	if err != nil {
+		return nil, makeGenericMgmtError(err, &req, resp, "")
+	}
+	defer ensureBodyClosed(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, makeMgmtBadStatusError("failed to get replication status", &req, resp)
+	}
+
+	var tasksData []jsonXDCRTask
+	jsonDec := json.NewDecoder(resp.Body)
+	err = jsonDec.Decode(&tasksData)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, taskData := range tasksData {
+		if taskData.Type != "xdcr" || taskData.ID != replicationID {
+			continue
+		}
+
+		status := &XDCRReplicationStatus{}
+		status.fromData(taskData)
+		return status, nil
+	}
+
+	return nil, makeGenericMgmtError(ErrXDCRReplicationNotFound, &req, resp, "")
+}